@@ -12,6 +12,7 @@ import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 )
@@ -23,6 +24,104 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type ListBuildsRequest_Outcome int32
+
+const (
+	ListBuildsRequest_OUTCOME_ANY       ListBuildsRequest_Outcome = 0
+	ListBuildsRequest_OUTCOME_SUCCEEDED ListBuildsRequest_Outcome = 1
+	ListBuildsRequest_OUTCOME_FAILED    ListBuildsRequest_Outcome = 2
+)
+
+// Enum value maps for ListBuildsRequest_Outcome.
+var (
+	ListBuildsRequest_Outcome_name = map[int32]string{
+		0: "OUTCOME_ANY",
+		1: "OUTCOME_SUCCEEDED",
+		2: "OUTCOME_FAILED",
+	}
+	ListBuildsRequest_Outcome_value = map[string]int32{
+		"OUTCOME_ANY":       0,
+		"OUTCOME_SUCCEEDED": 1,
+		"OUTCOME_FAILED":    2,
+	}
+)
+
+func (x ListBuildsRequest_Outcome) Enum() *ListBuildsRequest_Outcome {
+	p := new(ListBuildsRequest_Outcome)
+	*p = x
+	return p
+}
+
+func (x ListBuildsRequest_Outcome) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ListBuildsRequest_Outcome) Descriptor() protoreflect.EnumDescriptor {
+	return file_build_service_proto_enumTypes[0].Descriptor()
+}
+
+func (ListBuildsRequest_Outcome) Type() protoreflect.EnumType {
+	return &file_build_service_proto_enumTypes[0]
+}
+
+func (x ListBuildsRequest_Outcome) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ListBuildsRequest_Outcome.Descriptor instead.
+func (ListBuildsRequest_Outcome) EnumDescriptor() ([]byte, []int) {
+	return file_build_service_proto_rawDescGZIP(), []int{7, 0}
+}
+
+type CountBuildsRequest_Outcome int32
+
+const (
+	CountBuildsRequest_OUTCOME_ANY       CountBuildsRequest_Outcome = 0
+	CountBuildsRequest_OUTCOME_SUCCEEDED CountBuildsRequest_Outcome = 1
+	CountBuildsRequest_OUTCOME_FAILED    CountBuildsRequest_Outcome = 2
+)
+
+// Enum value maps for CountBuildsRequest_Outcome.
+var (
+	CountBuildsRequest_Outcome_name = map[int32]string{
+		0: "OUTCOME_ANY",
+		1: "OUTCOME_SUCCEEDED",
+		2: "OUTCOME_FAILED",
+	}
+	CountBuildsRequest_Outcome_value = map[string]int32{
+		"OUTCOME_ANY":       0,
+		"OUTCOME_SUCCEEDED": 1,
+		"OUTCOME_FAILED":    2,
+	}
+)
+
+func (x CountBuildsRequest_Outcome) Enum() *CountBuildsRequest_Outcome {
+	p := new(CountBuildsRequest_Outcome)
+	*p = x
+	return p
+}
+
+func (x CountBuildsRequest_Outcome) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CountBuildsRequest_Outcome) Descriptor() protoreflect.EnumDescriptor {
+	return file_build_service_proto_enumTypes[1].Descriptor()
+}
+
+func (CountBuildsRequest_Outcome) Type() protoreflect.EnumType {
+	return &file_build_service_proto_enumTypes[1]
+}
+
+func (x CountBuildsRequest_Outcome) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CountBuildsRequest_Outcome.Descriptor instead.
+func (CountBuildsRequest_Outcome) EnumDescriptor() ([]byte, []int) {
+	return file_build_service_proto_rawDescGZIP(), []int{23, 0}
+}
+
 type CreateBuildRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Build         *Build                 `protobuf:"bytes,1,opt,name=build,proto3" json:"build,omitempty"`
@@ -67,6 +166,255 @@ func (x *CreateBuildRequest) GetBuild() *Build {
 	return nil
 }
 
+// The outcome of storing one build of a CreateBuilds stream or batch
+type CreateBuildsResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	BuildId string                 `protobuf:"bytes,1,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+	// gRPC status code, zero when the build was stored
+	Code          int32  `protobuf:"varint,2,opt,name=code,proto3" json:"code,omitempty"`
+	Error         string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateBuildsResponse) Reset() {
+	*x = CreateBuildsResponse{}
+	mi := &file_build_service_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateBuildsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateBuildsResponse) ProtoMessage() {}
+
+func (x *CreateBuildsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_build_service_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateBuildsResponse.ProtoReflect.Descriptor instead.
+func (*CreateBuildsResponse) Descriptor() ([]byte, []int) {
+	return file_build_service_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateBuildsResponse) GetBuildId() string {
+	if x != nil {
+		return x.BuildId
+	}
+	return ""
+}
+
+func (x *CreateBuildsResponse) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *CreateBuildsResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type BatchCreateBuildsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// At most 500 builds; larger batches are rejected with INVALID_ARGUMENT.
+	// The request must also fit the server's gRPC message size limit.
+	Builds        []*Build `protobuf:"bytes,1,rep,name=builds,proto3" json:"builds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchCreateBuildsRequest) Reset() {
+	*x = BatchCreateBuildsRequest{}
+	mi := &file_build_service_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchCreateBuildsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCreateBuildsRequest) ProtoMessage() {}
+
+func (x *BatchCreateBuildsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_build_service_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCreateBuildsRequest.ProtoReflect.Descriptor instead.
+func (*BatchCreateBuildsRequest) Descriptor() ([]byte, []int) {
+	return file_build_service_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *BatchCreateBuildsRequest) GetBuilds() []*Build {
+	if x != nil {
+		return x.Builds
+	}
+	return nil
+}
+
+type BatchCreateBuildsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// One result per build, in request order
+	Results       []*CreateBuildsResponse `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchCreateBuildsResponse) Reset() {
+	*x = BatchCreateBuildsResponse{}
+	mi := &file_build_service_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchCreateBuildsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCreateBuildsResponse) ProtoMessage() {}
+
+func (x *BatchCreateBuildsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_build_service_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCreateBuildsResponse.ProtoReflect.Descriptor instead.
+func (*BatchCreateBuildsResponse) Descriptor() ([]byte, []int) {
+	return file_build_service_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *BatchCreateBuildsResponse) GetResults() []*CreateBuildsResponse {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type AppendRemarksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BuildId       string                 `protobuf:"bytes,1,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+	Remarks       []*CompilerRemark      `protobuf:"bytes,2,rep,name=remarks,proto3" json:"remarks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AppendRemarksRequest) Reset() {
+	*x = AppendRemarksRequest{}
+	mi := &file_build_service_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AppendRemarksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AppendRemarksRequest) ProtoMessage() {}
+
+func (x *AppendRemarksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_build_service_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AppendRemarksRequest.ProtoReflect.Descriptor instead.
+func (*AppendRemarksRequest) Descriptor() ([]byte, []int) {
+	return file_build_service_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *AppendRemarksRequest) GetBuildId() string {
+	if x != nil {
+		return x.BuildId
+	}
+	return ""
+}
+
+func (x *AppendRemarksRequest) GetRemarks() []*CompilerRemark {
+	if x != nil {
+		return x.Remarks
+	}
+	return nil
+}
+
+type AppendRemarksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stored        int64                  `protobuf:"varint,1,opt,name=stored,proto3" json:"stored,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AppendRemarksResponse) Reset() {
+	*x = AppendRemarksResponse{}
+	mi := &file_build_service_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AppendRemarksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AppendRemarksResponse) ProtoMessage() {}
+
+func (x *AppendRemarksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_build_service_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AppendRemarksResponse.ProtoReflect.Descriptor instead.
+func (*AppendRemarksResponse) Descriptor() ([]byte, []int) {
+	return file_build_service_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *AppendRemarksResponse) GetStored() int64 {
+	if x != nil {
+		return x.Stored
+	}
+	return 0
+}
+
 type GetBuildRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -76,7 +424,7 @@ type GetBuildRequest struct {
 
 func (x *GetBuildRequest) Reset() {
 	*x = GetBuildRequest{}
-	mi := &file_build_service_proto_msgTypes[1]
+	mi := &file_build_service_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -88,7 +436,7 @@ func (x *GetBuildRequest) String() string {
 func (*GetBuildRequest) ProtoMessage() {}
 
 func (x *GetBuildRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_build_service_proto_msgTypes[1]
+	mi := &file_build_service_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -101,7 +449,7 @@ func (x *GetBuildRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetBuildRequest.ProtoReflect.Descriptor instead.
 func (*GetBuildRequest) Descriptor() ([]byte, []int) {
-	return file_build_service_proto_rawDescGZIP(), []int{1}
+	return file_build_service_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *GetBuildRequest) GetId() string {
@@ -112,17 +460,24 @@ func (x *GetBuildRequest) GetId() string {
 }
 
 type ListBuildsRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	PageSize      int32                  `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
-	PageToken     string                 `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
-	Filter        string                 `protobuf:"bytes,3,opt,name=filter,proto3" json:"filter,omitempty"`
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	PageSize  int32                  `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken string                 `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	Filter    string                 `protobuf:"bytes,3,opt,name=filter,proto3" json:"filter,omitempty"`
+	// Compiler name; empty matches every compiler
+	Compiler string `protobuf:"bytes,4,opt,name=compiler,proto3" json:"compiler,omitempty"`
+	// Outcomes other than any leave out builds still in progress
+	Outcome ListBuildsRequest_Outcome `protobuf:"varint,5,opt,name=outcome,proto3,enum=build.v1.ListBuildsRequest_Outcome" json:"outcome,omitempty"`
+	// Bounds on when the server stored the build; unset bounds are open
+	Since         *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=since,proto3" json:"since,omitempty"`
+	Until         *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=until,proto3" json:"until,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ListBuildsRequest) Reset() {
 	*x = ListBuildsRequest{}
-	mi := &file_build_service_proto_msgTypes[2]
+	mi := &file_build_service_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -134,7 +489,7 @@ func (x *ListBuildsRequest) String() string {
 func (*ListBuildsRequest) ProtoMessage() {}
 
 func (x *ListBuildsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_build_service_proto_msgTypes[2]
+	mi := &file_build_service_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -147,7 +502,7 @@ func (x *ListBuildsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListBuildsRequest.ProtoReflect.Descriptor instead.
 func (*ListBuildsRequest) Descriptor() ([]byte, []int) {
-	return file_build_service_proto_rawDescGZIP(), []int{2}
+	return file_build_service_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *ListBuildsRequest) GetPageSize() int32 {
@@ -171,6 +526,34 @@ func (x *ListBuildsRequest) GetFilter() string {
 	return ""
 }
 
+func (x *ListBuildsRequest) GetCompiler() string {
+	if x != nil {
+		return x.Compiler
+	}
+	return ""
+}
+
+func (x *ListBuildsRequest) GetOutcome() ListBuildsRequest_Outcome {
+	if x != nil {
+		return x.Outcome
+	}
+	return ListBuildsRequest_OUTCOME_ANY
+}
+
+func (x *ListBuildsRequest) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+func (x *ListBuildsRequest) GetUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Until
+	}
+	return nil
+}
+
 type ListBuildsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Builds        []*Build               `protobuf:"bytes,1,rep,name=builds,proto3" json:"builds,omitempty"`
@@ -182,7 +565,7 @@ type ListBuildsResponse struct {
 
 func (x *ListBuildsResponse) Reset() {
 	*x = ListBuildsResponse{}
-	mi := &file_build_service_proto_msgTypes[3]
+	mi := &file_build_service_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -194,7 +577,7 @@ func (x *ListBuildsResponse) String() string {
 func (*ListBuildsResponse) ProtoMessage() {}
 
 func (x *ListBuildsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_build_service_proto_msgTypes[3]
+	mi := &file_build_service_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -207,7 +590,7 @@ func (x *ListBuildsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListBuildsResponse.ProtoReflect.Descriptor instead.
 func (*ListBuildsResponse) Descriptor() ([]byte, []int) {
-	return file_build_service_proto_rawDescGZIP(), []int{3}
+	return file_build_service_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *ListBuildsResponse) GetBuilds() []*Build {
@@ -240,7 +623,7 @@ type DeleteBuildRequest struct {
 
 func (x *DeleteBuildRequest) Reset() {
 	*x = DeleteBuildRequest{}
-	mi := &file_build_service_proto_msgTypes[4]
+	mi := &file_build_service_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -252,7 +635,7 @@ func (x *DeleteBuildRequest) String() string {
 func (*DeleteBuildRequest) ProtoMessage() {}
 
 func (x *DeleteBuildRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_build_service_proto_msgTypes[4]
+	mi := &file_build_service_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -265,7 +648,7 @@ func (x *DeleteBuildRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteBuildRequest.ProtoReflect.Descriptor instead.
 func (*DeleteBuildRequest) Descriptor() ([]byte, []int) {
-	return file_build_service_proto_rawDescGZIP(), []int{4}
+	return file_build_service_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *DeleteBuildRequest) GetId() string {
@@ -275,16 +658,64 @@ func (x *DeleteBuildRequest) GetId() string {
 	return ""
 }
 
-type StreamBuildsRequest struct {
+type UndeleteBuildRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Filter        string                 `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *StreamBuildsRequest) Reset() {
-	*x = StreamBuildsRequest{}
-	mi := &file_build_service_proto_msgTypes[5]
+func (x *UndeleteBuildRequest) Reset() {
+	*x = UndeleteBuildRequest{}
+	mi := &file_build_service_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UndeleteBuildRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UndeleteBuildRequest) ProtoMessage() {}
+
+func (x *UndeleteBuildRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_build_service_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UndeleteBuildRequest.ProtoReflect.Descriptor instead.
+func (*UndeleteBuildRequest) Descriptor() ([]byte, []int) {
+	return file_build_service_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *UndeleteBuildRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type StreamBuildsRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Filter string                 `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	// When set, builds finished at or after this server time
+	// (Build.finished_at) are replayed first. Builds in progress are only
+	// streamed once finalized.
+	Since         *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=since,proto3" json:"since,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamBuildsRequest) Reset() {
+	*x = StreamBuildsRequest{}
+	mi := &file_build_service_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -296,7 +727,7 @@ func (x *StreamBuildsRequest) String() string {
 func (*StreamBuildsRequest) ProtoMessage() {}
 
 func (x *StreamBuildsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_build_service_proto_msgTypes[5]
+	mi := &file_build_service_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -309,7 +740,7 @@ func (x *StreamBuildsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StreamBuildsRequest.ProtoReflect.Descriptor instead.
 func (*StreamBuildsRequest) Descriptor() ([]byte, []int) {
-	return file_build_service_proto_rawDescGZIP(), []int{5}
+	return file_build_service_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *StreamBuildsRequest) GetFilter() string {
@@ -319,6 +750,903 @@ func (x *StreamBuildsRequest) GetFilter() string {
 	return ""
 }
 
+func (x *StreamBuildsRequest) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+type GetSummaryRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Number of compilers to rank; defaults to 5
+	TopCompilers  int32 `protobuf:"varint,1,opt,name=top_compilers,json=topCompilers,proto3" json:"top_compilers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSummaryRequest) Reset() {
+	*x = GetSummaryRequest{}
+	mi := &file_build_service_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSummaryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSummaryRequest) ProtoMessage() {}
+
+func (x *GetSummaryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_build_service_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSummaryRequest.ProtoReflect.Descriptor instead.
+func (*GetSummaryRequest) Descriptor() ([]byte, []int) {
+	return file_build_service_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetSummaryRequest) GetTopCompilers() int32 {
+	if x != nil {
+		return x.TopCompilers
+	}
+	return 0
+}
+
+type CompilerCount struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Builds        int64                  `protobuf:"varint,2,opt,name=builds,proto3" json:"builds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompilerCount) Reset() {
+	*x = CompilerCount{}
+	mi := &file_build_service_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompilerCount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompilerCount) ProtoMessage() {}
+
+func (x *CompilerCount) ProtoReflect() protoreflect.Message {
+	mi := &file_build_service_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompilerCount.ProtoReflect.Descriptor instead.
+func (*CompilerCount) Descriptor() ([]byte, []int) {
+	return file_build_service_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *CompilerCount) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CompilerCount) GetBuilds() int64 {
+	if x != nil {
+		return x.Builds
+	}
+	return 0
+}
+
+type BuildSummary struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	TotalBuilds    int64                  `protobuf:"varint,1,opt,name=total_builds,json=totalBuilds,proto3" json:"total_builds,omitempty"`
+	BuildsLastDay  int64                  `protobuf:"varint,2,opt,name=builds_last_day,json=buildsLastDay,proto3" json:"builds_last_day,omitempty"`
+	BuildsLastWeek int64                  `protobuf:"varint,3,opt,name=builds_last_week,json=buildsLastWeek,proto3" json:"builds_last_week,omitempty"`
+	// Fraction of successful builds over the last week
+	RecentSuccessRate float64          `protobuf:"fixed64,4,opt,name=recent_success_rate,json=recentSuccessRate,proto3" json:"recent_success_rate,omitempty"`
+	TopCompilers      []*CompilerCount `protobuf:"bytes,5,rep,name=top_compilers,json=topCompilers,proto3" json:"top_compilers,omitempty"`
+	// Longest build of the last week, if any
+	SlowestRecentBuild *Build `protobuf:"bytes,6,opt,name=slowest_recent_build,json=slowestRecentBuild,proto3" json:"slowest_recent_build,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *BuildSummary) Reset() {
+	*x = BuildSummary{}
+	mi := &file_build_service_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BuildSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BuildSummary) ProtoMessage() {}
+
+func (x *BuildSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_build_service_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BuildSummary.ProtoReflect.Descriptor instead.
+func (*BuildSummary) Descriptor() ([]byte, []int) {
+	return file_build_service_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *BuildSummary) GetTotalBuilds() int64 {
+	if x != nil {
+		return x.TotalBuilds
+	}
+	return 0
+}
+
+func (x *BuildSummary) GetBuildsLastDay() int64 {
+	if x != nil {
+		return x.BuildsLastDay
+	}
+	return 0
+}
+
+func (x *BuildSummary) GetBuildsLastWeek() int64 {
+	if x != nil {
+		return x.BuildsLastWeek
+	}
+	return 0
+}
+
+func (x *BuildSummary) GetRecentSuccessRate() float64 {
+	if x != nil {
+		return x.RecentSuccessRate
+	}
+	return 0
+}
+
+func (x *BuildSummary) GetTopCompilers() []*CompilerCount {
+	if x != nil {
+		return x.TopCompilers
+	}
+	return nil
+}
+
+func (x *BuildSummary) GetSlowestRecentBuild() *Build {
+	if x != nil {
+		return x.SlowestRecentBuild
+	}
+	return nil
+}
+
+type GetRawRemarksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRawRemarksRequest) Reset() {
+	*x = GetRawRemarksRequest{}
+	mi := &file_build_service_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRawRemarksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRawRemarksRequest) ProtoMessage() {}
+
+func (x *GetRawRemarksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_build_service_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRawRemarksRequest.ProtoReflect.Descriptor instead.
+func (*GetRawRemarksRequest) Descriptor() ([]byte, []int) {
+	return file_build_service_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetRawRemarksRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// A piece of the uncompressed optimization record YAML
+type RawRemarksChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RawRemarksChunk) Reset() {
+	*x = RawRemarksChunk{}
+	mi := &file_build_service_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RawRemarksChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RawRemarksChunk) ProtoMessage() {}
+
+func (x *RawRemarksChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_build_service_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RawRemarksChunk.ProtoReflect.Descriptor instead.
+func (*RawRemarksChunk) Descriptor() ([]byte, []int) {
+	return file_build_service_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *RawRemarksChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+// A piece of a gzip-compressed optimization record. Every chunk of an
+// upload names the same build.
+type UploadRawRemarksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BuildId       string                 `protobuf:"bytes,1,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+	Data          []byte                 `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadRawRemarksRequest) Reset() {
+	*x = UploadRawRemarksRequest{}
+	mi := &file_build_service_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadRawRemarksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadRawRemarksRequest) ProtoMessage() {}
+
+func (x *UploadRawRemarksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_build_service_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadRawRemarksRequest.ProtoReflect.Descriptor instead.
+func (*UploadRawRemarksRequest) Descriptor() ([]byte, []int) {
+	return file_build_service_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *UploadRawRemarksRequest) GetBuildId() string {
+	if x != nil {
+		return x.BuildId
+	}
+	return ""
+}
+
+func (x *UploadRawRemarksRequest) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type UploadRawRemarksResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Compressed bytes received
+	Size int64 `protobuf:"varint,1,opt,name=size,proto3" json:"size,omitempty"`
+	// False when the server discards raw records
+	Stored        bool `protobuf:"varint,2,opt,name=stored,proto3" json:"stored,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadRawRemarksResponse) Reset() {
+	*x = UploadRawRemarksResponse{}
+	mi := &file_build_service_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadRawRemarksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadRawRemarksResponse) ProtoMessage() {}
+
+func (x *UploadRawRemarksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_build_service_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadRawRemarksResponse.ProtoReflect.Descriptor instead.
+func (*UploadRawRemarksResponse) Descriptor() ([]byte, []int) {
+	return file_build_service_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *UploadRawRemarksResponse) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *UploadRawRemarksResponse) GetStored() bool {
+	if x != nil {
+		return x.Stored
+	}
+	return false
+}
+
+type GetRemarkRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	BuildId string                 `protobuf:"bytes,1,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+	// Stable remark ID as returned in CompilerRemark.id
+	RemarkId      string `protobuf:"bytes,2,opt,name=remark_id,json=remarkId,proto3" json:"remark_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRemarkRequest) Reset() {
+	*x = GetRemarkRequest{}
+	mi := &file_build_service_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRemarkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRemarkRequest) ProtoMessage() {}
+
+func (x *GetRemarkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_build_service_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRemarkRequest.ProtoReflect.Descriptor instead.
+func (*GetRemarkRequest) Descriptor() ([]byte, []int) {
+	return file_build_service_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetRemarkRequest) GetBuildId() string {
+	if x != nil {
+		return x.BuildId
+	}
+	return ""
+}
+
+func (x *GetRemarkRequest) GetRemarkId() string {
+	if x != nil {
+		return x.RemarkId
+	}
+	return ""
+}
+
+type StreamRemarksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BuildId       string                 `protobuf:"bytes,1,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamRemarksRequest) Reset() {
+	*x = StreamRemarksRequest{}
+	mi := &file_build_service_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamRemarksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamRemarksRequest) ProtoMessage() {}
+
+func (x *StreamRemarksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_build_service_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamRemarksRequest.ProtoReflect.Descriptor instead.
+func (*StreamRemarksRequest) Descriptor() ([]byte, []int) {
+	return file_build_service_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *StreamRemarksRequest) GetBuildId() string {
+	if x != nil {
+		return x.BuildId
+	}
+	return ""
+}
+
+type PurgeRemarksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BuildId       string                 `protobuf:"bytes,1,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PurgeRemarksRequest) Reset() {
+	*x = PurgeRemarksRequest{}
+	mi := &file_build_service_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PurgeRemarksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PurgeRemarksRequest) ProtoMessage() {}
+
+func (x *PurgeRemarksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_build_service_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PurgeRemarksRequest.ProtoReflect.Descriptor instead.
+func (*PurgeRemarksRequest) Descriptor() ([]byte, []int) {
+	return file_build_service_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *PurgeRemarksRequest) GetBuildId() string {
+	if x != nil {
+		return x.BuildId
+	}
+	return ""
+}
+
+type PurgeRemarksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Purged        int64                  `protobuf:"varint,1,opt,name=purged,proto3" json:"purged,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PurgeRemarksResponse) Reset() {
+	*x = PurgeRemarksResponse{}
+	mi := &file_build_service_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PurgeRemarksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PurgeRemarksResponse) ProtoMessage() {}
+
+func (x *PurgeRemarksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_build_service_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PurgeRemarksResponse.ProtoReflect.Descriptor instead.
+func (*PurgeRemarksResponse) Descriptor() ([]byte, []int) {
+	return file_build_service_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *PurgeRemarksResponse) GetPurged() int64 {
+	if x != nil {
+		return x.Purged
+	}
+	return 0
+}
+
+type CountBuildsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Compiler name; empty matches every compiler
+	Compiler string `protobuf:"bytes,1,opt,name=compiler,proto3" json:"compiler,omitempty"`
+	// Outcomes other than any leave out builds still in progress
+	Outcome CountBuildsRequest_Outcome `protobuf:"varint,2,opt,name=outcome,proto3,enum=build.v1.CountBuildsRequest_Outcome" json:"outcome,omitempty"`
+	// Bounds on when the server stored the build; unset bounds are open
+	Since         *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=since,proto3" json:"since,omitempty"`
+	Until         *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=until,proto3" json:"until,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CountBuildsRequest) Reset() {
+	*x = CountBuildsRequest{}
+	mi := &file_build_service_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CountBuildsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountBuildsRequest) ProtoMessage() {}
+
+func (x *CountBuildsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_build_service_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountBuildsRequest.ProtoReflect.Descriptor instead.
+func (*CountBuildsRequest) Descriptor() ([]byte, []int) {
+	return file_build_service_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *CountBuildsRequest) GetCompiler() string {
+	if x != nil {
+		return x.Compiler
+	}
+	return ""
+}
+
+func (x *CountBuildsRequest) GetOutcome() CountBuildsRequest_Outcome {
+	if x != nil {
+		return x.Outcome
+	}
+	return CountBuildsRequest_OUTCOME_ANY
+}
+
+func (x *CountBuildsRequest) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+func (x *CountBuildsRequest) GetUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Until
+	}
+	return nil
+}
+
+type CountBuildsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Count         int64                  `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CountBuildsResponse) Reset() {
+	*x = CountBuildsResponse{}
+	mi := &file_build_service_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CountBuildsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountBuildsResponse) ProtoMessage() {}
+
+func (x *CountBuildsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_build_service_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountBuildsResponse.ProtoReflect.Descriptor instead.
+func (*CountBuildsResponse) Descriptor() ([]byte, []int) {
+	return file_build_service_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *CountBuildsResponse) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type RemarkHistoryRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Builds considered are those labelled label_key=label_value, such as a
+	// project; an empty key considers every build
+	LabelKey   string `protobuf:"bytes,1,opt,name=label_key,json=labelKey,proto3" json:"label_key,omitempty"`
+	LabelValue string `protobuf:"bytes,2,opt,name=label_value,json=labelValue,proto3" json:"label_value,omitempty"`
+	// The remark identity; empty function or pass match any value
+	Name          string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Function      string `protobuf:"bytes,4,opt,name=function,proto3" json:"function,omitempty"`
+	Pass          string `protobuf:"bytes,5,opt,name=pass,proto3" json:"pass,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemarkHistoryRequest) Reset() {
+	*x = RemarkHistoryRequest{}
+	mi := &file_build_service_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemarkHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemarkHistoryRequest) ProtoMessage() {}
+
+func (x *RemarkHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_build_service_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemarkHistoryRequest.ProtoReflect.Descriptor instead.
+func (*RemarkHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_build_service_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *RemarkHistoryRequest) GetLabelKey() string {
+	if x != nil {
+		return x.LabelKey
+	}
+	return ""
+}
+
+func (x *RemarkHistoryRequest) GetLabelValue() string {
+	if x != nil {
+		return x.LabelValue
+	}
+	return ""
+}
+
+func (x *RemarkHistoryRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RemarkHistoryRequest) GetFunction() string {
+	if x != nil {
+		return x.Function
+	}
+	return ""
+}
+
+func (x *RemarkHistoryRequest) GetPass() string {
+	if x != nil {
+		return x.Pass
+	}
+	return ""
+}
+
+// A build referred to by a history, with the server time it was stored
+type BuildRef struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BuildRef) Reset() {
+	*x = BuildRef{}
+	mi := &file_build_service_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BuildRef) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BuildRef) ProtoMessage() {}
+
+func (x *BuildRef) ProtoReflect() protoreflect.Message {
+	mi := &file_build_service_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BuildRef.ProtoReflect.Descriptor instead.
+func (*BuildRef) Descriptor() ([]byte, []int) {
+	return file_build_service_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *BuildRef) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *BuildRef) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type RemarkHistoryResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Number of builds the remark appeared in
+	Builds int64     `protobuf:"varint,1,opt,name=builds,proto3" json:"builds,omitempty"`
+	First  *BuildRef `protobuf:"bytes,2,opt,name=first,proto3" json:"first,omitempty"`
+	Last   *BuildRef `protobuf:"bytes,3,opt,name=last,proto3" json:"last,omitempty"`
+	// The newest build considered, whether or not the remark appeared in it
+	Latest        *BuildRef `protobuf:"bytes,4,opt,name=latest,proto3" json:"latest,omitempty"`
+	InLatest      bool      `protobuf:"varint,5,opt,name=in_latest,json=inLatest,proto3" json:"in_latest,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemarkHistoryResponse) Reset() {
+	*x = RemarkHistoryResponse{}
+	mi := &file_build_service_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemarkHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemarkHistoryResponse) ProtoMessage() {}
+
+func (x *RemarkHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_build_service_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemarkHistoryResponse.ProtoReflect.Descriptor instead.
+func (*RemarkHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_build_service_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *RemarkHistoryResponse) GetBuilds() int64 {
+	if x != nil {
+		return x.Builds
+	}
+	return 0
+}
+
+func (x *RemarkHistoryResponse) GetFirst() *BuildRef {
+	if x != nil {
+		return x.First
+	}
+	return nil
+}
+
+func (x *RemarkHistoryResponse) GetLast() *BuildRef {
+	if x != nil {
+		return x.Last
+	}
+	return nil
+}
+
+func (x *RemarkHistoryResponse) GetLatest() *BuildRef {
+	if x != nil {
+		return x.Latest
+	}
+	return nil
+}
+
+func (x *RemarkHistoryResponse) GetInLatest() bool {
+	if x != nil {
+		return x.InLatest
+	}
+	return false
+}
+
 var File_build_service_proto protoreflect.FileDescriptor
 
 var file_build_service_proto_rawDesc = []byte{
@@ -326,57 +1654,277 @@ var file_build_service_proto_rawDesc = []byte{
 	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x08, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x76, 0x31, 0x1a,
 	0x11, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x70, 0x72, 0x6f,
 	0x74, 0x6f, 0x1a, 0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x62, 0x75, 0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22,
-	0x3b, 0x0a, 0x12, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x25, 0x0a, 0x05, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x76, 0x31, 0x2e,
-	0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x05, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x22, 0x21, 0x0a, 0x0f,
-	0x47, 0x65, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
-	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22,
-	0x67, 0x0a, 0x11, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x73, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x73, 0x69, 0x7a,
-	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x61, 0x67, 0x65, 0x53, 0x69, 0x7a,
-	0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e,
-	0x12, 0x16, 0x0a, 0x06, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x06, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x22, 0x84, 0x01, 0x0a, 0x12, 0x4c, 0x69, 0x73,
-	0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x27, 0x0a, 0x06, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x62, 0x75, 0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a,
+	0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x22, 0x3b, 0x0a, 0x12, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x25, 0x0a, 0x05, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x76, 0x31,
+	0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x05, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x22, 0x5b, 0x0a,
+	0x14, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x49, 0x64,
+	0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04,
+	0x63, 0x6f, 0x64, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x43, 0x0a, 0x18, 0x42, 0x61,
+	0x74, 0x63, 0x68, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x27, 0x0a, 0x06, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x76,
+	0x31, 0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x06, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x73, 0x22,
+	0x55, 0x0a, 0x19, 0x42, 0x61, 0x74, 0x63, 0x68, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x42, 0x75,
+	0x69, 0x6c, 0x64, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x38, 0x0a, 0x07,
+	0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e,
+	0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x42,
+	0x75, 0x69, 0x6c, 0x64, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x07, 0x72,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x22, 0x65, 0x0a, 0x14, 0x41, 0x70, 0x70, 0x65, 0x6e, 0x64,
+	0x52, 0x65, 0x6d, 0x61, 0x72, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19,
+	0x0a, 0x08, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x07, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x49, 0x64, 0x12, 0x32, 0x0a, 0x07, 0x72, 0x65, 0x6d,
+	0x61, 0x72, 0x6b, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x62, 0x75, 0x69,
+	0x6c, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x69, 0x6c, 0x65, 0x72, 0x52, 0x65,
+	0x6d, 0x61, 0x72, 0x6b, 0x52, 0x07, 0x72, 0x65, 0x6d, 0x61, 0x72, 0x6b, 0x73, 0x22, 0x2f, 0x0a,
+	0x15, 0x41, 0x70, 0x70, 0x65, 0x6e, 0x64, 0x52, 0x65, 0x6d, 0x61, 0x72, 0x6b, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x22, 0x21,
+	0x0a, 0x0f, 0x47, 0x65, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69,
+	0x64, 0x22, 0xed, 0x02, 0x0a, 0x11, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61, 0x67, 0x65, 0x5f,
+	0x73, 0x69, 0x7a, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x61, 0x67, 0x65,
+	0x53, 0x69, 0x7a, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x74, 0x6f, 0x6b,
+	0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x61, 0x67, 0x65, 0x54, 0x6f,
+	0x6b, 0x65, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x63,
+	0x6f, 0x6d, 0x70, 0x69, 0x6c, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63,
+	0x6f, 0x6d, 0x70, 0x69, 0x6c, 0x65, 0x72, 0x12, 0x3d, 0x0a, 0x07, 0x6f, 0x75, 0x74, 0x63, 0x6f,
+	0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x23, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64,
+	0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x4f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x52, 0x07, 0x6f,
+	0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x12, 0x30, 0x0a, 0x05, 0x73, 0x69, 0x6e, 0x63, 0x65, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x52, 0x05, 0x73, 0x69, 0x6e, 0x63, 0x65, 0x12, 0x30, 0x0a, 0x05, 0x75, 0x6e, 0x74, 0x69,
+	0x6c, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x05, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x22, 0x45, 0x0a, 0x07, 0x4f, 0x75,
+	0x74, 0x63, 0x6f, 0x6d, 0x65, 0x12, 0x0f, 0x0a, 0x0b, 0x4f, 0x55, 0x54, 0x43, 0x4f, 0x4d, 0x45,
+	0x5f, 0x41, 0x4e, 0x59, 0x10, 0x00, 0x12, 0x15, 0x0a, 0x11, 0x4f, 0x55, 0x54, 0x43, 0x4f, 0x4d,
+	0x45, 0x5f, 0x53, 0x55, 0x43, 0x43, 0x45, 0x45, 0x44, 0x45, 0x44, 0x10, 0x01, 0x12, 0x12, 0x0a,
+	0x0e, 0x4f, 0x55, 0x54, 0x43, 0x4f, 0x4d, 0x45, 0x5f, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x10,
+	0x02, 0x22, 0x84, 0x01, 0x0a, 0x12, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x27, 0x0a, 0x06, 0x62, 0x75, 0x69, 0x6c,
+	0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64,
+	0x2e, 0x76, 0x31, 0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x06, 0x62, 0x75, 0x69, 0x6c, 0x64,
+	0x73, 0x12, 0x26, 0x0a, 0x0f, 0x6e, 0x65, 0x78, 0x74, 0x5f, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x74,
+	0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x6e, 0x65, 0x78, 0x74,
+	0x50, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x6f, 0x74,
+	0x61, 0x6c, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x74,
+	0x6f, 0x74, 0x61, 0x6c, 0x53, 0x69, 0x7a, 0x65, 0x22, 0x24, 0x0a, 0x12, 0x44, 0x65, 0x6c, 0x65,
+	0x74, 0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e,
+	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x26,
+	0x0a, 0x14, 0x55, 0x6e, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x5f, 0x0a, 0x13, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d,
+	0x42, 0x75, 0x69, 0x6c, 0x64, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a,
+	0x06, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x66,
+	0x69, 0x6c, 0x74, 0x65, 0x72, 0x12, 0x30, 0x0a, 0x05, 0x73, 0x69, 0x6e, 0x63, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x05, 0x73, 0x69, 0x6e, 0x63, 0x65, 0x22, 0x38, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x53, 0x75,
+	0x6d, 0x6d, 0x61, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x23, 0x0a, 0x0d,
+	0x74, 0x6f, 0x70, 0x5f, 0x63, 0x6f, 0x6d, 0x70, 0x69, 0x6c, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x0c, 0x74, 0x6f, 0x70, 0x43, 0x6f, 0x6d, 0x70, 0x69, 0x6c, 0x65, 0x72,
+	0x73, 0x22, 0x3b, 0x0a, 0x0d, 0x43, 0x6f, 0x6d, 0x70, 0x69, 0x6c, 0x65, 0x72, 0x43, 0x6f, 0x75,
+	0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x73,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x73, 0x22, 0xb4,
+	0x02, 0x0a, 0x0c, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x12,
+	0x21, 0x0a, 0x0c, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x73, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x42, 0x75, 0x69, 0x6c,
+	0x64, 0x73, 0x12, 0x26, 0x0a, 0x0f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x73, 0x5f, 0x6c, 0x61, 0x73,
+	0x74, 0x5f, 0x64, 0x61, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x62, 0x75, 0x69,
+	0x6c, 0x64, 0x73, 0x4c, 0x61, 0x73, 0x74, 0x44, 0x61, 0x79, 0x12, 0x28, 0x0a, 0x10, 0x62, 0x75,
+	0x69, 0x6c, 0x64, 0x73, 0x5f, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x77, 0x65, 0x65, 0x6b, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x73, 0x4c, 0x61, 0x73, 0x74,
+	0x57, 0x65, 0x65, 0x6b, 0x12, 0x2e, 0x0a, 0x13, 0x72, 0x65, 0x63, 0x65, 0x6e, 0x74, 0x5f, 0x73,
+	0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x11, 0x72, 0x65, 0x63, 0x65, 0x6e, 0x74, 0x53, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73,
+	0x52, 0x61, 0x74, 0x65, 0x12, 0x3c, 0x0a, 0x0d, 0x74, 0x6f, 0x70, 0x5f, 0x63, 0x6f, 0x6d, 0x70,
+	0x69, 0x6c, 0x65, 0x72, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x62, 0x75,
+	0x69, 0x6c, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x69, 0x6c, 0x65, 0x72, 0x43,
+	0x6f, 0x75, 0x6e, 0x74, 0x52, 0x0c, 0x74, 0x6f, 0x70, 0x43, 0x6f, 0x6d, 0x70, 0x69, 0x6c, 0x65,
+	0x72, 0x73, 0x12, 0x41, 0x0a, 0x14, 0x73, 0x6c, 0x6f, 0x77, 0x65, 0x73, 0x74, 0x5f, 0x72, 0x65,
+	0x63, 0x65, 0x6e, 0x74, 0x5f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x0f, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x75, 0x69, 0x6c,
+	0x64, 0x52, 0x12, 0x73, 0x6c, 0x6f, 0x77, 0x65, 0x73, 0x74, 0x52, 0x65, 0x63, 0x65, 0x6e, 0x74,
+	0x42, 0x75, 0x69, 0x6c, 0x64, 0x22, 0x26, 0x0a, 0x14, 0x47, 0x65, 0x74, 0x52, 0x61, 0x77, 0x52,
+	0x65, 0x6d, 0x61, 0x72, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a,
+	0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x25, 0x0a,
+	0x0f, 0x52, 0x61, 0x77, 0x52, 0x65, 0x6d, 0x61, 0x72, 0x6b, 0x73, 0x43, 0x68, 0x75, 0x6e, 0x6b,
+	0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04,
+	0x64, 0x61, 0x74, 0x61, 0x22, 0x48, 0x0a, 0x17, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x61,
+	0x77, 0x52, 0x65, 0x6d, 0x61, 0x72, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x19, 0x0a, 0x08, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61,
+	0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x46,
+	0x0a, 0x18, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x61, 0x77, 0x52, 0x65, 0x6d, 0x61, 0x72,
+	0x6b, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69,
+	0x7a, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x16,
+	0x0a, 0x06, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06,
+	0x73, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x22, 0x4a, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x52, 0x65, 0x6d,
+	0x61, 0x72, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x75,
+	0x69, 0x6c, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x62, 0x75,
+	0x69, 0x6c, 0x64, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x72, 0x65, 0x6d, 0x61, 0x72, 0x6b, 0x5f,
+	0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x72, 0x65, 0x6d, 0x61, 0x72, 0x6b,
+	0x49, 0x64, 0x22, 0x31, 0x0a, 0x14, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x6d, 0x61,
+	0x72, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x75,
+	0x69, 0x6c, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x62, 0x75,
+	0x69, 0x6c, 0x64, 0x49, 0x64, 0x22, 0x30, 0x0a, 0x13, 0x50, 0x75, 0x72, 0x67, 0x65, 0x52, 0x65,
+	0x6d, 0x61, 0x72, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08,
+	0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x62, 0x75, 0x69, 0x6c, 0x64, 0x49, 0x64, 0x22, 0x2e, 0x0a, 0x14, 0x50, 0x75, 0x72, 0x67, 0x65,
+	0x52, 0x65, 0x6d, 0x61, 0x72, 0x6b, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x16, 0x0a, 0x06, 0x70, 0x75, 0x72, 0x67, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x06, 0x70, 0x75, 0x72, 0x67, 0x65, 0x64, 0x22, 0x9b, 0x02, 0x0a, 0x12, 0x43, 0x6f, 0x75, 0x6e,
+	0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1a,
+	0x0a, 0x08, 0x63, 0x6f, 0x6d, 0x70, 0x69, 0x6c, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x63, 0x6f, 0x6d, 0x70, 0x69, 0x6c, 0x65, 0x72, 0x12, 0x3e, 0x0a, 0x07, 0x6f, 0x75,
+	0x74, 0x63, 0x6f, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x24, 0x2e, 0x62, 0x75,
+	0x69, 0x6c, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x42, 0x75, 0x69, 0x6c,
+	0x64, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x4f, 0x75, 0x74, 0x63, 0x6f, 0x6d,
+	0x65, 0x52, 0x07, 0x6f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x12, 0x30, 0x0a, 0x05, 0x73, 0x69,
+	0x6e, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x05, 0x73, 0x69, 0x6e, 0x63, 0x65, 0x12, 0x30, 0x0a, 0x05,
+	0x75, 0x6e, 0x74, 0x69, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x05, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x22, 0x45,
+	0x0a, 0x07, 0x4f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x12, 0x0f, 0x0a, 0x0b, 0x4f, 0x55, 0x54,
+	0x43, 0x4f, 0x4d, 0x45, 0x5f, 0x41, 0x4e, 0x59, 0x10, 0x00, 0x12, 0x15, 0x0a, 0x11, 0x4f, 0x55,
+	0x54, 0x43, 0x4f, 0x4d, 0x45, 0x5f, 0x53, 0x55, 0x43, 0x43, 0x45, 0x45, 0x44, 0x45, 0x44, 0x10,
+	0x01, 0x12, 0x12, 0x0a, 0x0e, 0x4f, 0x55, 0x54, 0x43, 0x4f, 0x4d, 0x45, 0x5f, 0x46, 0x41, 0x49,
+	0x4c, 0x45, 0x44, 0x10, 0x02, 0x22, 0x2b, 0x0a, 0x13, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x42, 0x75,
+	0x69, 0x6c, 0x64, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x22, 0x98, 0x01, 0x0a, 0x14, 0x52, 0x65, 0x6d, 0x61, 0x72, 0x6b, 0x48, 0x69, 0x73,
+	0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x6c,
+	0x61, 0x62, 0x65, 0x6c, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x6c, 0x61, 0x62, 0x65, 0x6c, 0x4b, 0x65, 0x79, 0x12, 0x1f, 0x0a, 0x0b, 0x6c, 0x61, 0x62, 0x65,
+	0x6c, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6c,
+	0x61, 0x62, 0x65, 0x6c, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a,
+	0x08, 0x66, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x66, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x73,
+	0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x73, 0x73, 0x22, 0x55, 0x0a,
+	0x08, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x65, 0x66, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x39, 0x0a, 0x0a, 0x63, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x64, 0x41, 0x74, 0x22, 0xca, 0x01, 0x0a, 0x15, 0x52, 0x65, 0x6d, 0x61, 0x72, 0x6b, 0x48,
+	0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16,
+	0x0a, 0x06, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06,
+	0x62, 0x75, 0x69, 0x6c, 0x64, 0x73, 0x12, 0x28, 0x0a, 0x05, 0x66, 0x69, 0x72, 0x73, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x76, 0x31,
+	0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x65, 0x66, 0x52, 0x05, 0x66, 0x69, 0x72, 0x73, 0x74,
+	0x12, 0x26, 0x0a, 0x04, 0x6c, 0x61, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12,
+	0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52,
+	0x65, 0x66, 0x52, 0x04, 0x6c, 0x61, 0x73, 0x74, 0x12, 0x2a, 0x0a, 0x06, 0x6c, 0x61, 0x74, 0x65,
+	0x73, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64,
+	0x2e, 0x76, 0x31, 0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x65, 0x66, 0x52, 0x06, 0x6c, 0x61,
+	0x74, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x69, 0x6e, 0x5f, 0x6c, 0x61, 0x74, 0x65, 0x73,
+	0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x69, 0x6e, 0x4c, 0x61, 0x74, 0x65, 0x73,
+	0x74, 0x32, 0x82, 0x0b, 0x0a, 0x0c, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x12, 0x3c, 0x0a, 0x0b, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x42, 0x75, 0x69, 0x6c,
+	0x64, 0x12, 0x1c, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
 	0x0f, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64,
-	0x52, 0x06, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x73, 0x12, 0x26, 0x0a, 0x0f, 0x6e, 0x65, 0x78, 0x74,
-	0x5f, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x0d, 0x6e, 0x65, 0x78, 0x74, 0x50, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e,
-	0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x53, 0x69, 0x7a, 0x65, 0x22,
-	0x24, 0x0a, 0x12, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x2d, 0x0a, 0x13, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x42,
-	0x75, 0x69, 0x6c, 0x64, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06,
-	0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x66, 0x69,
-	0x6c, 0x74, 0x65, 0x72, 0x32, 0xd4, 0x02, 0x0a, 0x0c, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x53, 0x65,
-	0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x3c, 0x0a, 0x0b, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x42,
-	0x75, 0x69, 0x6c, 0x64, 0x12, 0x1c, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x76, 0x31, 0x2e,
-	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x75,
-	0x69, 0x6c, 0x64, 0x12, 0x36, 0x0a, 0x08, 0x47, 0x65, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x12,
-	0x19, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x42, 0x75,
-	0x69, 0x6c, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x62, 0x75, 0x69,
-	0x6c, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x12, 0x47, 0x0a, 0x0a, 0x4c,
-	0x69, 0x73, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x73, 0x12, 0x1b, 0x2e, 0x62, 0x75, 0x69, 0x6c,
-	0x64, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x73, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x76,
-	0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x73, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x43, 0x0a, 0x0b, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x75,
-	0x69, 0x6c, 0x64, 0x12, 0x1c, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x44,
-	0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x40, 0x0a, 0x0c, 0x53, 0x74, 0x72,
+	0x12, 0x50, 0x0a, 0x0c, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x73,
+	0x12, 0x1c, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e,
+	0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x42, 0x75, 0x69, 0x6c, 0x64, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x28, 0x01,
+	0x30, 0x01, 0x12, 0x5c, 0x0a, 0x11, 0x42, 0x61, 0x74, 0x63, 0x68, 0x43, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x73, 0x12, 0x22, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e,
+	0x76, 0x31, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x42, 0x75,
+	0x69, 0x6c, 0x64, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e, 0x62, 0x75,
+	0x69, 0x6c, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x43, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x3b, 0x0a, 0x0a, 0x42, 0x65, 0x67, 0x69, 0x6e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x12, 0x1c,
+	0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x62,
+	0x75, 0x69, 0x6c, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x12, 0x52, 0x0a,
+	0x0d, 0x41, 0x70, 0x70, 0x65, 0x6e, 0x64, 0x52, 0x65, 0x6d, 0x61, 0x72, 0x6b, 0x73, 0x12, 0x1e,
+	0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x70, 0x70, 0x65, 0x6e, 0x64,
+	0x52, 0x65, 0x6d, 0x61, 0x72, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f,
+	0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x70, 0x70, 0x65, 0x6e, 0x64,
+	0x52, 0x65, 0x6d, 0x61, 0x72, 0x6b, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x28,
+	0x01, 0x12, 0x3e, 0x0a, 0x0d, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x42, 0x75, 0x69,
+	0x6c, 0x64, 0x12, 0x1c, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x0f, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x75, 0x69, 0x6c,
+	0x64, 0x12, 0x36, 0x0a, 0x08, 0x47, 0x65, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x12, 0x19, 0x2e,
+	0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x42, 0x75, 0x69, 0x6c,
+	0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64,
+	0x2e, 0x76, 0x31, 0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x12, 0x47, 0x0a, 0x0a, 0x4c, 0x69, 0x73,
+	0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x73, 0x12, 0x1b, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e,
+	0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x76, 0x31, 0x2e,
+	0x4c, 0x69, 0x73, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x43, 0x0a, 0x0b, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x75, 0x69, 0x6c,
+	0x64, 0x12, 0x1c, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x40, 0x0a, 0x0d, 0x55, 0x6e, 0x64, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x12, 0x1e, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64,
+	0x2e, 0x76, 0x31, 0x2e, 0x55, 0x6e, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x75, 0x69, 0x6c,
+	0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64,
+	0x2e, 0x76, 0x31, 0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x12, 0x40, 0x0a, 0x0c, 0x53, 0x74, 0x72,
 	0x65, 0x61, 0x6d, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x73, 0x12, 0x1d, 0x2e, 0x62, 0x75, 0x69, 0x6c,
 	0x64, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x42, 0x75, 0x69, 0x6c, 0x64,
 	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64,
-	0x2e, 0x76, 0x31, 0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x30, 0x01, 0x42, 0x12, 0x5a, 0x10, 0x62,
-	0x75, 0x69, 0x6c, 0x64, 0x73, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62,
-	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x2e, 0x76, 0x31, 0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x30, 0x01, 0x12, 0x41, 0x0a, 0x0a, 0x47,
+	0x65, 0x74, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x12, 0x1b, 0x2e, 0x62, 0x75, 0x69, 0x6c,
+	0x64, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x76,
+	0x31, 0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x12, 0x4c,
+	0x0a, 0x0d, 0x47, 0x65, 0x74, 0x52, 0x61, 0x77, 0x52, 0x65, 0x6d, 0x61, 0x72, 0x6b, 0x73, 0x12,
+	0x1e, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x52, 0x61,
+	0x77, 0x52, 0x65, 0x6d, 0x61, 0x72, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x19, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x61, 0x77, 0x52, 0x65,
+	0x6d, 0x61, 0x72, 0x6b, 0x73, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x30, 0x01, 0x12, 0x5b, 0x0a, 0x10,
+	0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x61, 0x77, 0x52, 0x65, 0x6d, 0x61, 0x72, 0x6b, 0x73,
+	0x12, 0x21, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x70, 0x6c, 0x6f,
+	0x61, 0x64, 0x52, 0x61, 0x77, 0x52, 0x65, 0x6d, 0x61, 0x72, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x55,
+	0x70, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x61, 0x77, 0x52, 0x65, 0x6d, 0x61, 0x72, 0x6b, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x28, 0x01, 0x12, 0x41, 0x0a, 0x09, 0x47, 0x65, 0x74,
+	0x52, 0x65, 0x6d, 0x61, 0x72, 0x6b, 0x12, 0x1a, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x76,
+	0x31, 0x2e, 0x47, 0x65, 0x74, 0x52, 0x65, 0x6d, 0x61, 0x72, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x18, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f,
+	0x6d, 0x70, 0x69, 0x6c, 0x65, 0x72, 0x52, 0x65, 0x6d, 0x61, 0x72, 0x6b, 0x12, 0x4b, 0x0a, 0x0d,
+	0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x6d, 0x61, 0x72, 0x6b, 0x73, 0x12, 0x1e, 0x2e,
+	0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52,
+	0x65, 0x6d, 0x61, 0x72, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e,
+	0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x69, 0x6c, 0x65,
+	0x72, 0x52, 0x65, 0x6d, 0x61, 0x72, 0x6b, 0x30, 0x01, 0x12, 0x4d, 0x0a, 0x0c, 0x50, 0x75, 0x72,
+	0x67, 0x65, 0x52, 0x65, 0x6d, 0x61, 0x72, 0x6b, 0x73, 0x12, 0x1d, 0x2e, 0x62, 0x75, 0x69, 0x6c,
+	0x64, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x75, 0x72, 0x67, 0x65, 0x52, 0x65, 0x6d, 0x61, 0x72, 0x6b,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64,
+	0x2e, 0x76, 0x31, 0x2e, 0x50, 0x75, 0x72, 0x67, 0x65, 0x52, 0x65, 0x6d, 0x61, 0x72, 0x6b, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4a, 0x0a, 0x0b, 0x43, 0x6f, 0x75, 0x6e,
+	0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x73, 0x12, 0x1c, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e,
+	0x76, 0x31, 0x2e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x76, 0x31,
+	0x2e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x50, 0x0a, 0x0d, 0x52, 0x65, 0x6d, 0x61, 0x72, 0x6b, 0x48, 0x69,
+	0x73, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x1e, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x76, 0x31,
+	0x2e, 0x52, 0x65, 0x6d, 0x61, 0x72, 0x6b, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x76, 0x31,
+	0x2e, 0x52, 0x65, 0x6d, 0x61, 0x72, 0x6b, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x12, 0x5a, 0x10, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x73,
+	0x2f, 0x61, 0x70, 0x69, 0x2f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
 }
 
 var (
@@ -391,35 +1939,106 @@ func file_build_service_proto_rawDescGZIP() []byte {
 	return file_build_service_proto_rawDescData
 }
 
-var file_build_service_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_build_service_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_build_service_proto_msgTypes = make([]protoimpl.MessageInfo, 28)
 var file_build_service_proto_goTypes = []any{
-	(*CreateBuildRequest)(nil),  // 0: build.v1.CreateBuildRequest
-	(*GetBuildRequest)(nil),     // 1: build.v1.GetBuildRequest
-	(*ListBuildsRequest)(nil),   // 2: build.v1.ListBuildsRequest
-	(*ListBuildsResponse)(nil),  // 3: build.v1.ListBuildsResponse
-	(*DeleteBuildRequest)(nil),  // 4: build.v1.DeleteBuildRequest
-	(*StreamBuildsRequest)(nil), // 5: build.v1.StreamBuildsRequest
-	(*Build)(nil),               // 6: build.v1.Build
-	(*emptypb.Empty)(nil),       // 7: google.protobuf.Empty
+	(ListBuildsRequest_Outcome)(0),    // 0: build.v1.ListBuildsRequest.Outcome
+	(CountBuildsRequest_Outcome)(0),   // 1: build.v1.CountBuildsRequest.Outcome
+	(*CreateBuildRequest)(nil),        // 2: build.v1.CreateBuildRequest
+	(*CreateBuildsResponse)(nil),      // 3: build.v1.CreateBuildsResponse
+	(*BatchCreateBuildsRequest)(nil),  // 4: build.v1.BatchCreateBuildsRequest
+	(*BatchCreateBuildsResponse)(nil), // 5: build.v1.BatchCreateBuildsResponse
+	(*AppendRemarksRequest)(nil),      // 6: build.v1.AppendRemarksRequest
+	(*AppendRemarksResponse)(nil),     // 7: build.v1.AppendRemarksResponse
+	(*GetBuildRequest)(nil),           // 8: build.v1.GetBuildRequest
+	(*ListBuildsRequest)(nil),         // 9: build.v1.ListBuildsRequest
+	(*ListBuildsResponse)(nil),        // 10: build.v1.ListBuildsResponse
+	(*DeleteBuildRequest)(nil),        // 11: build.v1.DeleteBuildRequest
+	(*UndeleteBuildRequest)(nil),      // 12: build.v1.UndeleteBuildRequest
+	(*StreamBuildsRequest)(nil),       // 13: build.v1.StreamBuildsRequest
+	(*GetSummaryRequest)(nil),         // 14: build.v1.GetSummaryRequest
+	(*CompilerCount)(nil),             // 15: build.v1.CompilerCount
+	(*BuildSummary)(nil),              // 16: build.v1.BuildSummary
+	(*GetRawRemarksRequest)(nil),      // 17: build.v1.GetRawRemarksRequest
+	(*RawRemarksChunk)(nil),           // 18: build.v1.RawRemarksChunk
+	(*UploadRawRemarksRequest)(nil),   // 19: build.v1.UploadRawRemarksRequest
+	(*UploadRawRemarksResponse)(nil),  // 20: build.v1.UploadRawRemarksResponse
+	(*GetRemarkRequest)(nil),          // 21: build.v1.GetRemarkRequest
+	(*StreamRemarksRequest)(nil),      // 22: build.v1.StreamRemarksRequest
+	(*PurgeRemarksRequest)(nil),       // 23: build.v1.PurgeRemarksRequest
+	(*PurgeRemarksResponse)(nil),      // 24: build.v1.PurgeRemarksResponse
+	(*CountBuildsRequest)(nil),        // 25: build.v1.CountBuildsRequest
+	(*CountBuildsResponse)(nil),       // 26: build.v1.CountBuildsResponse
+	(*RemarkHistoryRequest)(nil),      // 27: build.v1.RemarkHistoryRequest
+	(*BuildRef)(nil),                  // 28: build.v1.BuildRef
+	(*RemarkHistoryResponse)(nil),     // 29: build.v1.RemarkHistoryResponse
+	(*Build)(nil),                     // 30: build.v1.Build
+	(*CompilerRemark)(nil),            // 31: build.v1.CompilerRemark
+	(*timestamppb.Timestamp)(nil),     // 32: google.protobuf.Timestamp
+	(*emptypb.Empty)(nil),             // 33: google.protobuf.Empty
 }
 var file_build_service_proto_depIdxs = []int32{
-	6, // 0: build.v1.CreateBuildRequest.build:type_name -> build.v1.Build
-	6, // 1: build.v1.ListBuildsResponse.builds:type_name -> build.v1.Build
-	0, // 2: build.v1.BuildService.CreateBuild:input_type -> build.v1.CreateBuildRequest
-	1, // 3: build.v1.BuildService.GetBuild:input_type -> build.v1.GetBuildRequest
-	2, // 4: build.v1.BuildService.ListBuilds:input_type -> build.v1.ListBuildsRequest
-	4, // 5: build.v1.BuildService.DeleteBuild:input_type -> build.v1.DeleteBuildRequest
-	5, // 6: build.v1.BuildService.StreamBuilds:input_type -> build.v1.StreamBuildsRequest
-	6, // 7: build.v1.BuildService.CreateBuild:output_type -> build.v1.Build
-	6, // 8: build.v1.BuildService.GetBuild:output_type -> build.v1.Build
-	3, // 9: build.v1.BuildService.ListBuilds:output_type -> build.v1.ListBuildsResponse
-	7, // 10: build.v1.BuildService.DeleteBuild:output_type -> google.protobuf.Empty
-	6, // 11: build.v1.BuildService.StreamBuilds:output_type -> build.v1.Build
-	7, // [7:12] is the sub-list for method output_type
-	2, // [2:7] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	30, // 0: build.v1.CreateBuildRequest.build:type_name -> build.v1.Build
+	30, // 1: build.v1.BatchCreateBuildsRequest.builds:type_name -> build.v1.Build
+	3,  // 2: build.v1.BatchCreateBuildsResponse.results:type_name -> build.v1.CreateBuildsResponse
+	31, // 3: build.v1.AppendRemarksRequest.remarks:type_name -> build.v1.CompilerRemark
+	0,  // 4: build.v1.ListBuildsRequest.outcome:type_name -> build.v1.ListBuildsRequest.Outcome
+	32, // 5: build.v1.ListBuildsRequest.since:type_name -> google.protobuf.Timestamp
+	32, // 6: build.v1.ListBuildsRequest.until:type_name -> google.protobuf.Timestamp
+	30, // 7: build.v1.ListBuildsResponse.builds:type_name -> build.v1.Build
+	32, // 8: build.v1.StreamBuildsRequest.since:type_name -> google.protobuf.Timestamp
+	15, // 9: build.v1.BuildSummary.top_compilers:type_name -> build.v1.CompilerCount
+	30, // 10: build.v1.BuildSummary.slowest_recent_build:type_name -> build.v1.Build
+	1,  // 11: build.v1.CountBuildsRequest.outcome:type_name -> build.v1.CountBuildsRequest.Outcome
+	32, // 12: build.v1.CountBuildsRequest.since:type_name -> google.protobuf.Timestamp
+	32, // 13: build.v1.CountBuildsRequest.until:type_name -> google.protobuf.Timestamp
+	32, // 14: build.v1.BuildRef.created_at:type_name -> google.protobuf.Timestamp
+	28, // 15: build.v1.RemarkHistoryResponse.first:type_name -> build.v1.BuildRef
+	28, // 16: build.v1.RemarkHistoryResponse.last:type_name -> build.v1.BuildRef
+	28, // 17: build.v1.RemarkHistoryResponse.latest:type_name -> build.v1.BuildRef
+	2,  // 18: build.v1.BuildService.CreateBuild:input_type -> build.v1.CreateBuildRequest
+	2,  // 19: build.v1.BuildService.CreateBuilds:input_type -> build.v1.CreateBuildRequest
+	4,  // 20: build.v1.BuildService.BatchCreateBuilds:input_type -> build.v1.BatchCreateBuildsRequest
+	2,  // 21: build.v1.BuildService.BeginBuild:input_type -> build.v1.CreateBuildRequest
+	6,  // 22: build.v1.BuildService.AppendRemarks:input_type -> build.v1.AppendRemarksRequest
+	2,  // 23: build.v1.BuildService.FinalizeBuild:input_type -> build.v1.CreateBuildRequest
+	8,  // 24: build.v1.BuildService.GetBuild:input_type -> build.v1.GetBuildRequest
+	9,  // 25: build.v1.BuildService.ListBuilds:input_type -> build.v1.ListBuildsRequest
+	11, // 26: build.v1.BuildService.DeleteBuild:input_type -> build.v1.DeleteBuildRequest
+	12, // 27: build.v1.BuildService.UndeleteBuild:input_type -> build.v1.UndeleteBuildRequest
+	13, // 28: build.v1.BuildService.StreamBuilds:input_type -> build.v1.StreamBuildsRequest
+	14, // 29: build.v1.BuildService.GetSummary:input_type -> build.v1.GetSummaryRequest
+	17, // 30: build.v1.BuildService.GetRawRemarks:input_type -> build.v1.GetRawRemarksRequest
+	19, // 31: build.v1.BuildService.UploadRawRemarks:input_type -> build.v1.UploadRawRemarksRequest
+	21, // 32: build.v1.BuildService.GetRemark:input_type -> build.v1.GetRemarkRequest
+	22, // 33: build.v1.BuildService.StreamRemarks:input_type -> build.v1.StreamRemarksRequest
+	23, // 34: build.v1.BuildService.PurgeRemarks:input_type -> build.v1.PurgeRemarksRequest
+	25, // 35: build.v1.BuildService.CountBuilds:input_type -> build.v1.CountBuildsRequest
+	27, // 36: build.v1.BuildService.RemarkHistory:input_type -> build.v1.RemarkHistoryRequest
+	30, // 37: build.v1.BuildService.CreateBuild:output_type -> build.v1.Build
+	3,  // 38: build.v1.BuildService.CreateBuilds:output_type -> build.v1.CreateBuildsResponse
+	5,  // 39: build.v1.BuildService.BatchCreateBuilds:output_type -> build.v1.BatchCreateBuildsResponse
+	30, // 40: build.v1.BuildService.BeginBuild:output_type -> build.v1.Build
+	7,  // 41: build.v1.BuildService.AppendRemarks:output_type -> build.v1.AppendRemarksResponse
+	30, // 42: build.v1.BuildService.FinalizeBuild:output_type -> build.v1.Build
+	30, // 43: build.v1.BuildService.GetBuild:output_type -> build.v1.Build
+	10, // 44: build.v1.BuildService.ListBuilds:output_type -> build.v1.ListBuildsResponse
+	33, // 45: build.v1.BuildService.DeleteBuild:output_type -> google.protobuf.Empty
+	30, // 46: build.v1.BuildService.UndeleteBuild:output_type -> build.v1.Build
+	30, // 47: build.v1.BuildService.StreamBuilds:output_type -> build.v1.Build
+	16, // 48: build.v1.BuildService.GetSummary:output_type -> build.v1.BuildSummary
+	18, // 49: build.v1.BuildService.GetRawRemarks:output_type -> build.v1.RawRemarksChunk
+	20, // 50: build.v1.BuildService.UploadRawRemarks:output_type -> build.v1.UploadRawRemarksResponse
+	31, // 51: build.v1.BuildService.GetRemark:output_type -> build.v1.CompilerRemark
+	31, // 52: build.v1.BuildService.StreamRemarks:output_type -> build.v1.CompilerRemark
+	24, // 53: build.v1.BuildService.PurgeRemarks:output_type -> build.v1.PurgeRemarksResponse
+	26, // 54: build.v1.BuildService.CountBuilds:output_type -> build.v1.CountBuildsResponse
+	29, // 55: build.v1.BuildService.RemarkHistory:output_type -> build.v1.RemarkHistoryResponse
+	37, // [37:56] is the sub-list for method output_type
+	18, // [18:37] is the sub-list for method input_type
+	18, // [18:18] is the sub-list for extension type_name
+	18, // [18:18] is the sub-list for extension extendee
+	0,  // [0:18] is the sub-list for field type_name
 }
 
 func init() { file_build_service_proto_init() }
@@ -433,13 +2052,14 @@ func file_build_service_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_build_service_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   6,
+			NumEnums:      2,
+			NumMessages:   28,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_build_service_proto_goTypes,
 		DependencyIndexes: file_build_service_proto_depIdxs,
+		EnumInfos:         file_build_service_proto_enumTypes,
 		MessageInfos:      file_build_service_proto_msgTypes,
 	}.Build()
 	File_build_service_proto = out.File
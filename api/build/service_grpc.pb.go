@@ -22,11 +22,25 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	BuildService_CreateBuild_FullMethodName  = "/build.v1.BuildService/CreateBuild"
-	BuildService_GetBuild_FullMethodName     = "/build.v1.BuildService/GetBuild"
-	BuildService_ListBuilds_FullMethodName   = "/build.v1.BuildService/ListBuilds"
-	BuildService_DeleteBuild_FullMethodName  = "/build.v1.BuildService/DeleteBuild"
-	BuildService_StreamBuilds_FullMethodName = "/build.v1.BuildService/StreamBuilds"
+	BuildService_CreateBuild_FullMethodName       = "/build.v1.BuildService/CreateBuild"
+	BuildService_CreateBuilds_FullMethodName      = "/build.v1.BuildService/CreateBuilds"
+	BuildService_BatchCreateBuilds_FullMethodName = "/build.v1.BuildService/BatchCreateBuilds"
+	BuildService_BeginBuild_FullMethodName        = "/build.v1.BuildService/BeginBuild"
+	BuildService_AppendRemarks_FullMethodName     = "/build.v1.BuildService/AppendRemarks"
+	BuildService_FinalizeBuild_FullMethodName     = "/build.v1.BuildService/FinalizeBuild"
+	BuildService_GetBuild_FullMethodName          = "/build.v1.BuildService/GetBuild"
+	BuildService_ListBuilds_FullMethodName        = "/build.v1.BuildService/ListBuilds"
+	BuildService_DeleteBuild_FullMethodName       = "/build.v1.BuildService/DeleteBuild"
+	BuildService_UndeleteBuild_FullMethodName     = "/build.v1.BuildService/UndeleteBuild"
+	BuildService_StreamBuilds_FullMethodName      = "/build.v1.BuildService/StreamBuilds"
+	BuildService_GetSummary_FullMethodName        = "/build.v1.BuildService/GetSummary"
+	BuildService_GetRawRemarks_FullMethodName     = "/build.v1.BuildService/GetRawRemarks"
+	BuildService_UploadRawRemarks_FullMethodName  = "/build.v1.BuildService/UploadRawRemarks"
+	BuildService_GetRemark_FullMethodName         = "/build.v1.BuildService/GetRemark"
+	BuildService_StreamRemarks_FullMethodName     = "/build.v1.BuildService/StreamRemarks"
+	BuildService_PurgeRemarks_FullMethodName      = "/build.v1.BuildService/PurgeRemarks"
+	BuildService_CountBuilds_FullMethodName       = "/build.v1.BuildService/CountBuilds"
+	BuildService_RemarkHistory_FullMethodName     = "/build.v1.BuildService/RemarkHistory"
 )
 
 // BuildServiceClient is the client API for BuildService service.
@@ -34,10 +48,39 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type BuildServiceClient interface {
 	CreateBuild(ctx context.Context, in *CreateBuildRequest, opts ...grpc.CallOption) (*Build, error)
+	// Stores many builds over one stream, answering each as it is stored.
+	// Suited to uploads of unbounded size, such as replaying a spool of
+	// builds, since nothing has to be held in memory at once.
+	CreateBuilds(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[CreateBuildRequest, CreateBuildsResponse], error)
+	// Stores up to 500 builds in one call, reporting the outcome of each.
+	// Simpler for clients and proxies without streaming support; larger
+	// uploads should be split into batches or sent over CreateBuilds.
+	BatchCreateBuilds(ctx context.Context, in *BatchCreateBuildsRequest, opts ...grpc.CallOption) (*BatchCreateBuildsResponse, error)
+	// Incremental ingestion: begin a build, stream remarks as they are
+	// produced, then finalize it with the complete record
+	BeginBuild(ctx context.Context, in *CreateBuildRequest, opts ...grpc.CallOption) (*Build, error)
+	AppendRemarks(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[AppendRemarksRequest, AppendRemarksResponse], error)
+	FinalizeBuild(ctx context.Context, in *CreateBuildRequest, opts ...grpc.CallOption) (*Build, error)
 	GetBuild(ctx context.Context, in *GetBuildRequest, opts ...grpc.CallOption) (*Build, error)
 	ListBuilds(ctx context.Context, in *ListBuildsRequest, opts ...grpc.CallOption) (*ListBuildsResponse, error)
 	DeleteBuild(ctx context.Context, in *DeleteBuildRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	UndeleteBuild(ctx context.Context, in *UndeleteBuildRequest, opts ...grpc.CallOption) (*Build, error)
 	StreamBuilds(ctx context.Context, in *StreamBuildsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Build], error)
+	GetSummary(ctx context.Context, in *GetSummaryRequest, opts ...grpc.CallOption) (*BuildSummary, error)
+	GetRawRemarks(ctx context.Context, in *GetRawRemarksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[RawRemarksChunk], error)
+	// Attaches the raw optimization record to a stored build, sent in chunks
+	// so records larger than a gRPC message can be uploaded
+	UploadRawRemarks(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadRawRemarksRequest, UploadRawRemarksResponse], error)
+	GetRemark(ctx context.Context, in *GetRemarkRequest, opts ...grpc.CallOption) (*CompilerRemark, error)
+	// Sends a build's stored remarks, then those appended while it is still
+	// being ingested, ending once the build is finalized
+	StreamRemarks(ctx context.Context, in *StreamRemarksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CompilerRemark], error)
+	// Deletes a build's remarks while keeping the build
+	PurgeRemarks(ctx context.Context, in *PurgeRemarksRequest, opts ...grpc.CallOption) (*PurgeRemarksResponse, error)
+	// Counts the builds matching a filter without listing them
+	CountBuilds(ctx context.Context, in *CountBuildsRequest, opts ...grpc.CallOption) (*CountBuildsResponse, error)
+	// Reports the first and last builds in which a remark appeared
+	RemarkHistory(ctx context.Context, in *RemarkHistoryRequest, opts ...grpc.CallOption) (*RemarkHistoryResponse, error)
 }
 
 type buildServiceClient struct {
@@ -58,6 +101,62 @@ func (c *buildServiceClient) CreateBuild(ctx context.Context, in *CreateBuildReq
 	return out, nil
 }
 
+func (c *buildServiceClient) CreateBuilds(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[CreateBuildRequest, CreateBuildsResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &BuildService_ServiceDesc.Streams[0], BuildService_CreateBuilds_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[CreateBuildRequest, CreateBuildsResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BuildService_CreateBuildsClient = grpc.BidiStreamingClient[CreateBuildRequest, CreateBuildsResponse]
+
+func (c *buildServiceClient) BatchCreateBuilds(ctx context.Context, in *BatchCreateBuildsRequest, opts ...grpc.CallOption) (*BatchCreateBuildsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchCreateBuildsResponse)
+	err := c.cc.Invoke(ctx, BuildService_BatchCreateBuilds_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *buildServiceClient) BeginBuild(ctx context.Context, in *CreateBuildRequest, opts ...grpc.CallOption) (*Build, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Build)
+	err := c.cc.Invoke(ctx, BuildService_BeginBuild_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *buildServiceClient) AppendRemarks(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[AppendRemarksRequest, AppendRemarksResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &BuildService_ServiceDesc.Streams[1], BuildService_AppendRemarks_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[AppendRemarksRequest, AppendRemarksResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BuildService_AppendRemarksClient = grpc.ClientStreamingClient[AppendRemarksRequest, AppendRemarksResponse]
+
+func (c *buildServiceClient) FinalizeBuild(ctx context.Context, in *CreateBuildRequest, opts ...grpc.CallOption) (*Build, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Build)
+	err := c.cc.Invoke(ctx, BuildService_FinalizeBuild_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *buildServiceClient) GetBuild(ctx context.Context, in *GetBuildRequest, opts ...grpc.CallOption) (*Build, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(Build)
@@ -88,9 +187,19 @@ func (c *buildServiceClient) DeleteBuild(ctx context.Context, in *DeleteBuildReq
 	return out, nil
 }
 
+func (c *buildServiceClient) UndeleteBuild(ctx context.Context, in *UndeleteBuildRequest, opts ...grpc.CallOption) (*Build, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Build)
+	err := c.cc.Invoke(ctx, BuildService_UndeleteBuild_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *buildServiceClient) StreamBuilds(ctx context.Context, in *StreamBuildsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Build], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	stream, err := c.cc.NewStream(ctx, &BuildService_ServiceDesc.Streams[0], BuildService_StreamBuilds_FullMethodName, cOpts...)
+	stream, err := c.cc.NewStream(ctx, &BuildService_ServiceDesc.Streams[2], BuildService_StreamBuilds_FullMethodName, cOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -107,15 +216,145 @@ func (c *buildServiceClient) StreamBuilds(ctx context.Context, in *StreamBuildsR
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type BuildService_StreamBuildsClient = grpc.ServerStreamingClient[Build]
 
+func (c *buildServiceClient) GetSummary(ctx context.Context, in *GetSummaryRequest, opts ...grpc.CallOption) (*BuildSummary, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BuildSummary)
+	err := c.cc.Invoke(ctx, BuildService_GetSummary_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *buildServiceClient) GetRawRemarks(ctx context.Context, in *GetRawRemarksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[RawRemarksChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &BuildService_ServiceDesc.Streams[3], BuildService_GetRawRemarks_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[GetRawRemarksRequest, RawRemarksChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BuildService_GetRawRemarksClient = grpc.ServerStreamingClient[RawRemarksChunk]
+
+func (c *buildServiceClient) UploadRawRemarks(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadRawRemarksRequest, UploadRawRemarksResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &BuildService_ServiceDesc.Streams[4], BuildService_UploadRawRemarks_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[UploadRawRemarksRequest, UploadRawRemarksResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BuildService_UploadRawRemarksClient = grpc.ClientStreamingClient[UploadRawRemarksRequest, UploadRawRemarksResponse]
+
+func (c *buildServiceClient) GetRemark(ctx context.Context, in *GetRemarkRequest, opts ...grpc.CallOption) (*CompilerRemark, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CompilerRemark)
+	err := c.cc.Invoke(ctx, BuildService_GetRemark_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *buildServiceClient) StreamRemarks(ctx context.Context, in *StreamRemarksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CompilerRemark], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &BuildService_ServiceDesc.Streams[5], BuildService_StreamRemarks_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamRemarksRequest, CompilerRemark]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BuildService_StreamRemarksClient = grpc.ServerStreamingClient[CompilerRemark]
+
+func (c *buildServiceClient) PurgeRemarks(ctx context.Context, in *PurgeRemarksRequest, opts ...grpc.CallOption) (*PurgeRemarksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PurgeRemarksResponse)
+	err := c.cc.Invoke(ctx, BuildService_PurgeRemarks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *buildServiceClient) CountBuilds(ctx context.Context, in *CountBuildsRequest, opts ...grpc.CallOption) (*CountBuildsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CountBuildsResponse)
+	err := c.cc.Invoke(ctx, BuildService_CountBuilds_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *buildServiceClient) RemarkHistory(ctx context.Context, in *RemarkHistoryRequest, opts ...grpc.CallOption) (*RemarkHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemarkHistoryResponse)
+	err := c.cc.Invoke(ctx, BuildService_RemarkHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // BuildServiceServer is the server API for BuildService service.
 // All implementations must embed UnimplementedBuildServiceServer
 // for forward compatibility.
 type BuildServiceServer interface {
 	CreateBuild(context.Context, *CreateBuildRequest) (*Build, error)
+	// Stores many builds over one stream, answering each as it is stored.
+	// Suited to uploads of unbounded size, such as replaying a spool of
+	// builds, since nothing has to be held in memory at once.
+	CreateBuilds(grpc.BidiStreamingServer[CreateBuildRequest, CreateBuildsResponse]) error
+	// Stores up to 500 builds in one call, reporting the outcome of each.
+	// Simpler for clients and proxies without streaming support; larger
+	// uploads should be split into batches or sent over CreateBuilds.
+	BatchCreateBuilds(context.Context, *BatchCreateBuildsRequest) (*BatchCreateBuildsResponse, error)
+	// Incremental ingestion: begin a build, stream remarks as they are
+	// produced, then finalize it with the complete record
+	BeginBuild(context.Context, *CreateBuildRequest) (*Build, error)
+	AppendRemarks(grpc.ClientStreamingServer[AppendRemarksRequest, AppendRemarksResponse]) error
+	FinalizeBuild(context.Context, *CreateBuildRequest) (*Build, error)
 	GetBuild(context.Context, *GetBuildRequest) (*Build, error)
 	ListBuilds(context.Context, *ListBuildsRequest) (*ListBuildsResponse, error)
 	DeleteBuild(context.Context, *DeleteBuildRequest) (*emptypb.Empty, error)
+	UndeleteBuild(context.Context, *UndeleteBuildRequest) (*Build, error)
 	StreamBuilds(*StreamBuildsRequest, grpc.ServerStreamingServer[Build]) error
+	GetSummary(context.Context, *GetSummaryRequest) (*BuildSummary, error)
+	GetRawRemarks(*GetRawRemarksRequest, grpc.ServerStreamingServer[RawRemarksChunk]) error
+	// Attaches the raw optimization record to a stored build, sent in chunks
+	// so records larger than a gRPC message can be uploaded
+	UploadRawRemarks(grpc.ClientStreamingServer[UploadRawRemarksRequest, UploadRawRemarksResponse]) error
+	GetRemark(context.Context, *GetRemarkRequest) (*CompilerRemark, error)
+	// Sends a build's stored remarks, then those appended while it is still
+	// being ingested, ending once the build is finalized
+	StreamRemarks(*StreamRemarksRequest, grpc.ServerStreamingServer[CompilerRemark]) error
+	// Deletes a build's remarks while keeping the build
+	PurgeRemarks(context.Context, *PurgeRemarksRequest) (*PurgeRemarksResponse, error)
+	// Counts the builds matching a filter without listing them
+	CountBuilds(context.Context, *CountBuildsRequest) (*CountBuildsResponse, error)
+	// Reports the first and last builds in which a remark appeared
+	RemarkHistory(context.Context, *RemarkHistoryRequest) (*RemarkHistoryResponse, error)
 	mustEmbedUnimplementedBuildServiceServer()
 }
 
@@ -129,6 +368,21 @@ type UnimplementedBuildServiceServer struct{}
 func (UnimplementedBuildServiceServer) CreateBuild(context.Context, *CreateBuildRequest) (*Build, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateBuild not implemented")
 }
+func (UnimplementedBuildServiceServer) CreateBuilds(grpc.BidiStreamingServer[CreateBuildRequest, CreateBuildsResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method CreateBuilds not implemented")
+}
+func (UnimplementedBuildServiceServer) BatchCreateBuilds(context.Context, *BatchCreateBuildsRequest) (*BatchCreateBuildsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchCreateBuilds not implemented")
+}
+func (UnimplementedBuildServiceServer) BeginBuild(context.Context, *CreateBuildRequest) (*Build, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BeginBuild not implemented")
+}
+func (UnimplementedBuildServiceServer) AppendRemarks(grpc.ClientStreamingServer[AppendRemarksRequest, AppendRemarksResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method AppendRemarks not implemented")
+}
+func (UnimplementedBuildServiceServer) FinalizeBuild(context.Context, *CreateBuildRequest) (*Build, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FinalizeBuild not implemented")
+}
 func (UnimplementedBuildServiceServer) GetBuild(context.Context, *GetBuildRequest) (*Build, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetBuild not implemented")
 }
@@ -138,9 +392,36 @@ func (UnimplementedBuildServiceServer) ListBuilds(context.Context, *ListBuildsRe
 func (UnimplementedBuildServiceServer) DeleteBuild(context.Context, *DeleteBuildRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteBuild not implemented")
 }
+func (UnimplementedBuildServiceServer) UndeleteBuild(context.Context, *UndeleteBuildRequest) (*Build, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UndeleteBuild not implemented")
+}
 func (UnimplementedBuildServiceServer) StreamBuilds(*StreamBuildsRequest, grpc.ServerStreamingServer[Build]) error {
 	return status.Errorf(codes.Unimplemented, "method StreamBuilds not implemented")
 }
+func (UnimplementedBuildServiceServer) GetSummary(context.Context, *GetSummaryRequest) (*BuildSummary, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSummary not implemented")
+}
+func (UnimplementedBuildServiceServer) GetRawRemarks(*GetRawRemarksRequest, grpc.ServerStreamingServer[RawRemarksChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method GetRawRemarks not implemented")
+}
+func (UnimplementedBuildServiceServer) UploadRawRemarks(grpc.ClientStreamingServer[UploadRawRemarksRequest, UploadRawRemarksResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method UploadRawRemarks not implemented")
+}
+func (UnimplementedBuildServiceServer) GetRemark(context.Context, *GetRemarkRequest) (*CompilerRemark, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRemark not implemented")
+}
+func (UnimplementedBuildServiceServer) StreamRemarks(*StreamRemarksRequest, grpc.ServerStreamingServer[CompilerRemark]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamRemarks not implemented")
+}
+func (UnimplementedBuildServiceServer) PurgeRemarks(context.Context, *PurgeRemarksRequest) (*PurgeRemarksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PurgeRemarks not implemented")
+}
+func (UnimplementedBuildServiceServer) CountBuilds(context.Context, *CountBuildsRequest) (*CountBuildsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CountBuilds not implemented")
+}
+func (UnimplementedBuildServiceServer) RemarkHistory(context.Context, *RemarkHistoryRequest) (*RemarkHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemarkHistory not implemented")
+}
 func (UnimplementedBuildServiceServer) mustEmbedUnimplementedBuildServiceServer() {}
 func (UnimplementedBuildServiceServer) testEmbeddedByValue()                      {}
 
@@ -180,6 +461,74 @@ func _BuildService_CreateBuild_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _BuildService_CreateBuilds_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(BuildServiceServer).CreateBuilds(&grpc.GenericServerStream[CreateBuildRequest, CreateBuildsResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BuildService_CreateBuildsServer = grpc.BidiStreamingServer[CreateBuildRequest, CreateBuildsResponse]
+
+func _BuildService_BatchCreateBuilds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchCreateBuildsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BuildServiceServer).BatchCreateBuilds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BuildService_BatchCreateBuilds_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BuildServiceServer).BatchCreateBuilds(ctx, req.(*BatchCreateBuildsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BuildService_BeginBuild_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateBuildRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BuildServiceServer).BeginBuild(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BuildService_BeginBuild_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BuildServiceServer).BeginBuild(ctx, req.(*CreateBuildRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BuildService_AppendRemarks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(BuildServiceServer).AppendRemarks(&grpc.GenericServerStream[AppendRemarksRequest, AppendRemarksResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BuildService_AppendRemarksServer = grpc.ClientStreamingServer[AppendRemarksRequest, AppendRemarksResponse]
+
+func _BuildService_FinalizeBuild_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateBuildRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BuildServiceServer).FinalizeBuild(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BuildService_FinalizeBuild_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BuildServiceServer).FinalizeBuild(ctx, req.(*CreateBuildRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _BuildService_GetBuild_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetBuildRequest)
 	if err := dec(in); err != nil {
@@ -234,6 +583,24 @@ func _BuildService_DeleteBuild_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _BuildService_UndeleteBuild_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UndeleteBuildRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BuildServiceServer).UndeleteBuild(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BuildService_UndeleteBuild_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BuildServiceServer).UndeleteBuild(ctx, req.(*UndeleteBuildRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _BuildService_StreamBuilds_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(StreamBuildsRequest)
 	if err := stream.RecvMsg(m); err != nil {
@@ -245,6 +612,125 @@ func _BuildService_StreamBuilds_Handler(srv interface{}, stream grpc.ServerStrea
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type BuildService_StreamBuildsServer = grpc.ServerStreamingServer[Build]
 
+func _BuildService_GetSummary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSummaryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BuildServiceServer).GetSummary(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BuildService_GetSummary_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BuildServiceServer).GetSummary(ctx, req.(*GetSummaryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BuildService_GetRawRemarks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetRawRemarksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BuildServiceServer).GetRawRemarks(m, &grpc.GenericServerStream[GetRawRemarksRequest, RawRemarksChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BuildService_GetRawRemarksServer = grpc.ServerStreamingServer[RawRemarksChunk]
+
+func _BuildService_UploadRawRemarks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(BuildServiceServer).UploadRawRemarks(&grpc.GenericServerStream[UploadRawRemarksRequest, UploadRawRemarksResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BuildService_UploadRawRemarksServer = grpc.ClientStreamingServer[UploadRawRemarksRequest, UploadRawRemarksResponse]
+
+func _BuildService_GetRemark_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRemarkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BuildServiceServer).GetRemark(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BuildService_GetRemark_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BuildServiceServer).GetRemark(ctx, req.(*GetRemarkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BuildService_StreamRemarks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamRemarksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BuildServiceServer).StreamRemarks(m, &grpc.GenericServerStream[StreamRemarksRequest, CompilerRemark]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BuildService_StreamRemarksServer = grpc.ServerStreamingServer[CompilerRemark]
+
+func _BuildService_PurgeRemarks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurgeRemarksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BuildServiceServer).PurgeRemarks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BuildService_PurgeRemarks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BuildServiceServer).PurgeRemarks(ctx, req.(*PurgeRemarksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BuildService_CountBuilds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CountBuildsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BuildServiceServer).CountBuilds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BuildService_CountBuilds_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BuildServiceServer).CountBuilds(ctx, req.(*CountBuildsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BuildService_RemarkHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemarkHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BuildServiceServer).RemarkHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BuildService_RemarkHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BuildServiceServer).RemarkHistory(ctx, req.(*RemarkHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // BuildService_ServiceDesc is the grpc.ServiceDesc for BuildService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -256,6 +742,18 @@ var BuildService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CreateBuild",
 			Handler:    _BuildService_CreateBuild_Handler,
 		},
+		{
+			MethodName: "BatchCreateBuilds",
+			Handler:    _BuildService_BatchCreateBuilds_Handler,
+		},
+		{
+			MethodName: "BeginBuild",
+			Handler:    _BuildService_BeginBuild_Handler,
+		},
+		{
+			MethodName: "FinalizeBuild",
+			Handler:    _BuildService_FinalizeBuild_Handler,
+		},
 		{
 			MethodName: "GetBuild",
 			Handler:    _BuildService_GetBuild_Handler,
@@ -268,13 +766,63 @@ var BuildService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteBuild",
 			Handler:    _BuildService_DeleteBuild_Handler,
 		},
+		{
+			MethodName: "UndeleteBuild",
+			Handler:    _BuildService_UndeleteBuild_Handler,
+		},
+		{
+			MethodName: "GetSummary",
+			Handler:    _BuildService_GetSummary_Handler,
+		},
+		{
+			MethodName: "GetRemark",
+			Handler:    _BuildService_GetRemark_Handler,
+		},
+		{
+			MethodName: "PurgeRemarks",
+			Handler:    _BuildService_PurgeRemarks_Handler,
+		},
+		{
+			MethodName: "CountBuilds",
+			Handler:    _BuildService_CountBuilds_Handler,
+		},
+		{
+			MethodName: "RemarkHistory",
+			Handler:    _BuildService_RemarkHistory_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "CreateBuilds",
+			Handler:       _BuildService_CreateBuilds_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "AppendRemarks",
+			Handler:       _BuildService_AppendRemarks_Handler,
+			ClientStreams: true,
+		},
 		{
 			StreamName:    "StreamBuilds",
 			Handler:       _BuildService_StreamBuilds_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "GetRawRemarks",
+			Handler:       _BuildService_GetRawRemarks_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "UploadRawRemarks",
+			Handler:       _BuildService_UploadRawRemarks_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "StreamRemarks",
+			Handler:       _BuildService_StreamRemarks_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "build/service.proto",
 }
@@ -0,0 +1,151 @@
+// cmd/builds/collect.go
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"builds/internal/models"
+)
+
+// compilerCollectors run the compiler: the compiler collector probes it and
+// the remarks collector runs the compile itself. They run one after another
+// in this order, so the probes never compete with the compile, while the
+// other collectors run alongside them.
+var compilerCollectors = []string{"compiler", "remarks"}
+
+// finalCollectors sample the process or read what the compile wrote once it
+// is over, so they run after every other collector finished
+var finalCollectors = []string{"resource", "linkmap", "binary"}
+
+// onceCollectors are not retried: running the remarks collector again would
+// run the compile again
+var onceCollectors = map[string]bool{"remarks": true}
+
+// compileCollector runs the compile, which is bounded by
+// config.CompileTimeout rather than config.Timeout: a large translation
+// unit can take far longer to compile than any probe should run
+const compileCollector = "remarks"
+
+// retryBackoff is the wait before the second attempt of a failed
+// collector, doubling for each further attempt
+const retryBackoff = 500 * time.Millisecond
+
+// runCollectors runs every collector of factory and returns the error each
+// one failed with, by name. Each attempt is cancelled after config.Timeout
+// seconds, the compile after config.CompileTimeout, and failed collectors
+// are tried up to config.MaxAttempts times.
+func runCollectors(ctx context.Context, factory *models.CollectorFactory, config *models.CollectorConfig) map[string]error {
+	var (
+		mu   sync.Mutex
+		errs = make(map[string]error)
+	)
+	run := func(name string) {
+		collector, ok := factory.GetCollector(name)
+		if !ok {
+			return
+		}
+		err := collect(ctx, name, collector, config)
+		mu.Lock()
+		errs[name] = err
+		mu.Unlock()
+	}
+
+	scheduled := make(map[string]bool)
+	for _, name := range append(compilerCollectors, finalCollectors...) {
+		scheduled[name] = true
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for _, name := range compilerCollectors {
+			run(name)
+		}
+	}()
+	for name := range factory.GetCollectors() {
+		if scheduled[name] {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			run(name)
+		}()
+	}
+	wg.Wait()
+
+	for _, name := range finalCollectors {
+		run(name)
+	}
+	return errs
+}
+
+// collectAll runs the collectors of factory like runCollectors. In strict
+// mode it also returns the error of a failed remark collection, which fails
+// the whole run, once every collector has been cleaned up.
+func collectAll(ctx context.Context, factory *models.CollectorFactory, config *models.CollectorConfig, strict bool) (map[string]error, error) {
+	errs := runCollectors(ctx, factory, config)
+	if err := errs["remarks"]; err != nil && strict {
+		cleanupCollectors(ctx, factory)
+		return errs, err
+	}
+	return errs, nil
+}
+
+// collect runs one collector, retrying failures with backoff. Errors of
+// attempts that ran out of time say so.
+func collect(ctx context.Context, name string, collector models.Collector, config *models.CollectorConfig) error {
+	timeout := time.Duration(config.Timeout) * time.Second
+	if name == compileCollector {
+		timeout = time.Duration(config.CompileTimeout) * time.Second
+	}
+	attempts := max(config.MaxAttempts, 1)
+	if onceCollectors[name] {
+		attempts = 1
+	}
+
+	backoff := retryBackoff
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		attemptCtx, cancel := ctx, context.CancelFunc(func() {})
+		if timeout > 0 {
+			attemptCtx, cancel = context.WithTimeout(ctx, timeout)
+		}
+		err = collector.Collect(attemptCtx)
+		if err != nil && errors.Is(attemptCtx.Err(), context.DeadlineExceeded) {
+			err = fmt.Errorf("timed out after %s: %w", timeout, err)
+		}
+		cancel()
+
+		if err == nil || attempt == attempts {
+			break
+		}
+		log.Printf("Warning: %s collector failed (attempt %d of %d), retrying in %s: %v", name, attempt, attempts, backoff, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+	return err
+}
+
+// collectorNames returns the names of the collectors of factory in a fixed
+// order, so results are assembled the same way on every run
+func collectorNames(factory *models.CollectorFactory) []string {
+	names := make([]string, 0, len(factory.GetCollectors()))
+	for name := range factory.GetCollectors() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"builds/internal/models"
+)
+
+// fakeCollector fails Collect or Cleanup on request and counts its calls
+type fakeCollector struct {
+	mu          sync.Mutex
+	collectErr  error
+	cleanupErr  error
+	failures    int  // Collections failing with collectErr, all when zero
+	hang        bool // Block in Collect until cancelled
+	deadline    bool // Whether the last Collect had a deadline
+	collections int
+	cleanups    int
+}
+
+func (c *fakeCollector) Initialize(ctx context.Context) error { return nil }
+
+func (c *fakeCollector) Collect(ctx context.Context) error {
+	c.mu.Lock()
+	c.collections++
+	n := c.collections
+	_, c.deadline = ctx.Deadline()
+	c.mu.Unlock()
+
+	if c.hang {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	if c.failures > 0 && n > c.failures {
+		return nil
+	}
+	return c.collectErr
+}
+
+func (c *fakeCollector) GetData() interface{} { return nil }
+
+func (c *fakeCollector) Cleanup(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cleanups++
+	return c.cleanupErr
+}
+
+func TestCleanupCollectors(t *testing.T) {
+	tests := []struct {
+		name    string
+		strict  bool
+		wantErr bool
+	}{
+		{"lenient", false, false},
+		{"strict", true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collectors := map[string]*fakeCollector{
+				"compiler":    {},
+				"environment": {cleanupErr: errors.New("busy")},
+				"remarks":     {collectErr: errors.New("record file not created")},
+				"resource":    {},
+			}
+			factory := models.NewCollectorFactory()
+			for name, collector := range collectors {
+				factory.RegisterCollector(name, collector)
+			}
+			ctx := context.Background()
+			config := &models.CollectorConfig{Timeout: 1, MaxAttempts: 1}
+
+			errs, err := collectAll(ctx, factory, config, tt.strict)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got %v, want an error %v", err, tt.wantErr)
+			}
+			if errs["remarks"] == nil {
+				t.Error("remark collection failure not reported")
+			}
+			// A run going on cleans up once it stored the data
+			if err == nil {
+				cleanupCollectors(ctx, factory)
+			}
+
+			// A failed cleanup does not stop the others, and neither does a
+			// failed collection
+			for name, collector := range collectors {
+				if collector.cleanups != 1 {
+					t.Errorf("%s cleaned up %d times, want 1", name, collector.cleanups)
+				}
+			}
+		})
+	}
+}
+
+func TestCollectRetriesAndTimesOut(t *testing.T) {
+	failed := errors.New("nvidia-smi failed")
+	tests := []struct {
+		name            string
+		collector       *fakeCollector
+		maxAttempts     int
+		wantCollections int
+		wantErr         string
+	}{
+		{"recovers on retry", &fakeCollector{collectErr: failed, failures: 1}, 3, 2, ""},
+		{"gives up after max attempts", &fakeCollector{collectErr: failed}, 2, 2, "nvidia-smi failed"},
+		{"hangs", &fakeCollector{hang: true}, 1, 1, "timed out after 1s"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &models.CollectorConfig{Timeout: 1, MaxAttempts: tt.maxAttempts}
+			err := collect(context.Background(), "hardware", tt.collector, config)
+			if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("got %v, want an error containing %q", err, tt.wantErr)
+			}
+			if tt.collector.collections != tt.wantCollections {
+				t.Errorf("collected %d times, want %d", tt.collector.collections, tt.wantCollections)
+			}
+		})
+	}
+}
+
+func TestCollectRunsCompileOnce(t *testing.T) {
+	remarks := &fakeCollector{collectErr: errors.New("compile failed")}
+	config := &models.CollectorConfig{MaxAttempts: 3}
+	if err := collect(context.Background(), "remarks", remarks, config); err == nil {
+		t.Error("the failed compile was not reported")
+	}
+	if remarks.collections != 1 {
+		t.Errorf("ran the compile %d times, want 1", remarks.collections)
+	}
+}
+
+func TestCollectCompileTimeout(t *testing.T) {
+	// The collector timeout does not cut the compile short
+	remarks := &fakeCollector{}
+	config := &models.CollectorConfig{Timeout: 1, MaxAttempts: 1}
+	if err := collect(context.Background(), "remarks", remarks, config); err != nil {
+		t.Fatal(err)
+	}
+	if remarks.deadline {
+		t.Error("the compile had a deadline without -compile-timeout")
+	}
+
+	remarks = &fakeCollector{hang: true}
+	config.CompileTimeout = 1
+	err := collect(context.Background(), "remarks", remarks, config)
+	if err == nil || !strings.Contains(err.Error(), "timed out after 1s") {
+		t.Errorf("got %v, want the compile timed out", err)
+	}
+}
+
+// orderCollector records when it starts and ends collecting in a shared log
+type orderCollector struct {
+	fakeCollector
+	name    string
+	log     *[]string
+	mu      *sync.Mutex
+	started *sync.WaitGroup // Waited for while collecting, if set
+}
+
+func (c *orderCollector) Collect(ctx context.Context) error {
+	c.record("start " + c.name)
+	if c.started != nil {
+		c.started.Done()
+		c.started.Wait()
+	}
+	c.record("end " + c.name)
+	return nil
+}
+
+func (c *orderCollector) record(event string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	*c.log = append(*c.log, event)
+}
+
+func TestRunCollectorsOrder(t *testing.T) {
+	var (
+		log []string
+		mu  sync.Mutex
+	)
+	// environment and hardware only finish once both started, so running
+	// them one after the other would hang
+	var started sync.WaitGroup
+	started.Add(2)
+	factory := models.NewCollectorFactory()
+	for _, name := range []string{"compiler", "remarks", "environment", "hardware", "resource", "linkmap"} {
+		collector := &orderCollector{name: name, log: &log, mu: &mu}
+		if name == "environment" || name == "hardware" {
+			collector.started = &started
+		}
+		factory.RegisterCollector(name, collector)
+	}
+
+	done := make(chan map[string]error)
+	go func() { done <- runCollectors(context.Background(), factory, &models.CollectorConfig{MaxAttempts: 1}) }()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("independent collectors did not run concurrently")
+	}
+
+	index := make(map[string]int)
+	for i, event := range log {
+		index[event] = i
+	}
+	if index["end compiler"] > index["start remarks"] {
+		t.Errorf("the compile started before the compiler probes ended: %v", log)
+	}
+	for _, final := range []string{"resource", "linkmap"} {
+		for _, other := range []string{"compiler", "remarks", "environment", "hardware"} {
+			if index["start "+final] < index["end "+other] {
+				t.Errorf("%s started before %s ended: %v", final, other, log)
+			}
+		}
+	}
+}
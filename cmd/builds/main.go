@@ -3,35 +3,90 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"flag"
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
-	"google.golang.org/protobuf/types/known/structpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	buildv1 "builds/api/build"
+	"builds/internal/client"
+	"builds/internal/collectors/binary"
+	"builds/internal/collectors/cache"
 	"builds/internal/collectors/compiler"
 	"builds/internal/collectors/environment"
+	"builds/internal/collectors/git"
 	"builds/internal/collectors/hardware"
+	"builds/internal/collectors/labels"
+	"builds/internal/collectors/linkmap"
 	"builds/internal/collectors/remarks"
 	"builds/internal/collectors/resource"
 	"builds/internal/models"
-	grpcutil "builds/internal/utils/grpcutil"
+	"builds/internal/parsers/diagnostics"
+	"builds/internal/parsers/timereport"
 )
 
 var (
-	serverAddr = flag.String("server", "localhost:50051", "The server address") // Changed from 8080 to 50051
-	useTLS     = flag.Bool("tls", false, "Use TLS when connecting to server")
-	verbose    = flag.Bool("verbose", false, "Enable verbose output")
-	version    = flag.Bool("version", false, "Show version information")
+	serverAddr  = flag.String("server", "localhost:50051", "The server address") // Changed from 8080 to 50051
+	useTLS      = flag.Bool("tls", false, "Use TLS when connecting to server")
+	token       = flag.String("token", os.Getenv("BUILDS_TOKEN"), "Authentication token sent to the server")
+	verbose     = flag.Bool("verbose", false, "Enable verbose output")
+	version     = flag.Bool("version", false, "Show version information")
+	records     = flag.String("records", "", "Comma-separated optimization record files, directories or globs to merge")
+	rawRemarks  = flag.Bool("raw-remarks", false, "Upload the raw optimization records (storage heavy, must be enabled on the server)")
+	pathRoot    = flag.String("path-root", "", "Store remark locations relative to this directory (\".\" for the working directory)")
+	maxRemarks  = flag.Int("max-remarks", remarks.DefaultMaxRemarks, "Maximum remarks kept per build (0 for no limit)")
+	systemHdrs  = flag.Bool("include-system-headers", false, "Keep remarks located in system headers, which are dropped by default")
+	passFilter  = flag.String("remark-passes", "", "Comma-separated passes or globs to keep remarks from, e.g. loop-vectorize,licm* (default all)")
+	noRedact    = flag.Bool("no-redact", false, "Store sensitive environment variables unredacted (trusted machines only)")
+	phaseMemory = flag.Bool("phase-memory", false, "Record the GC memory each compiler phase allocates using -ftime-report (GCC)")
+	streamMode  = flag.Bool("stream-remarks", false, "Upload remarks while the compiler is still running")
+	strictMode  = flag.Bool("strict-remarks", false, "Exit with an error when a remark cannot be parsed")
+	alwaysOK    = flag.Bool("always-succeed", false, "Exit 0 even when the compiler fails, instead of passing its exit code through")
+	linkMap     = flag.Bool("link-map", false, "Record section and symbol sizes from the linker map of link steps, adding -Wl,-Map when needed")
+	maxSymbols  = flag.Int("max-symbols", linkmap.DefaultMaxSymbols, "Maximum linker map symbols kept per build, the largest first (0 for no limit)")
+	driverTree  = flag.Bool("invocations", false, "Record the programs the compiler driver runs (cc1, as, ld) using -###")
+	summary     = flag.Bool("summary", false, "Print the build summary even when output is piped")
+	memInterval = flag.Duration("memory-sample-interval", resource.DefaultSampleInterval, "How often the memory of the compiler and its subprocesses is sampled for the peak (0 samples only before and after)")
+	sampleRate  = flag.Float64("sample", 1, "Fraction of successful builds submitted, e.g. 0.1, chosen by command and commit; failed builds are always submitted")
+	timeout     = flag.Int("collector-timeout", 300, "Seconds each collector other than the compile may run before it is cancelled (0 for no limit)")
+	compileTime = flag.Int("compile-timeout", 0, "Seconds the compile may run before it is cancelled (0 for no limit)")
+	labelEnv    = flag.String("label-env", strings.Join(labels.DefaultVariables, ","), "Comma-separated environment variables recorded as build labels")
+	buildLabels = labelFlag{}
 )
 
+func init() {
+	flag.Var(buildLabels, "label", "Build label as key=value (repeatable)")
+}
+
+// labelFlag collects repeated -label key=value flags
+type labelFlag map[string]string
+
+func (l labelFlag) String() string {
+	pairs := make([]string, 0, len(l))
+	for k, v := range l {
+		pairs = append(pairs, k+"="+v)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (l labelFlag) Set(value string) error {
+	key, val, ok := strings.Cut(value, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("label must be key=value, got %q", value)
+	}
+	l[key] = val
+	return nil
+}
+
 const buildVersion = "0.1.0"
 
 func main() {
@@ -48,29 +103,89 @@ func main() {
 		os.Exit(1)
 	}
 
+	if *sampleRate < 0 || *sampleRate > 1 {
+		log.Fatalf("-sample must be between 0 and 1, got %g", *sampleRate)
+	}
+	// Streamed builds are stored before it is known whether they succeed
+	if *sampleRate < 1 && *streamMode {
+		log.Fatal("-sample cannot be combined with -stream-remarks")
+	}
+
 	buildID := uuid.New().String()
 	startTime := time.Now()
 
-	// Create build context
+	// Look through compiler caches such as "ccache gcc" to the real compiler
+	wrapper, compilerCmd, compilerArgs, _ := cache.Unwrap(flag.Arg(0), flag.Args()[1:])
+
+	// Create build context. Collectors add flags to its copy of the
+	// arguments; compilerArgs stays the command line as given.
 	buildCtx := &models.BuildContext{
 		Context:  context.Background(),
 		BuildID:  buildID,
-		Compiler: flag.Arg(0),
-		Args:     flag.Args()[1:],
+		Compiler: compilerCmd,
+		Args:     slices.Clone(compilerArgs),
 		Config: &models.CollectorConfig{
-			Enabled:     true,
-			Timeout:     300,
-			MaxAttempts: 3,
+			Enabled:        true,
+			Timeout:        *timeout,
+			CompileTimeout: *compileTime,
+			MaxAttempts:    3,
 		},
 	}
+	if compilerCmd != flag.Arg(0) {
+		buildCtx.Wrapper = flag.Arg(0)
+	}
 
 	// Initialize collectors
 	factory := models.NewCollectorFactory()
-	factory.RegisterCollector("environment", environment.NewCollector())
+	envCollector := environment.NewCollector()
+	if *noRedact {
+		fmt.Fprintln(os.Stderr, "WARNING: -no-redact is set; tokens, passwords and other secrets in the environment will be sent to and stored by the server")
+		envCollector.DisableRedaction()
+	}
+	factory.RegisterCollector("environment", envCollector)
 	factory.RegisterCollector("hardware", hardware.NewCollector())
+	factory.RegisterCollector("git", git.NewCollector())
 	factory.RegisterCollector("compiler", compiler.NewCollector(buildCtx))
-	factory.RegisterCollector("remarks", remarks.NewCollector(buildCtx))
-	factory.RegisterCollector("resource", resource.NewCollector(buildCtx))
+	remarksCollector := remarks.NewCollector(buildCtx, splitList(*records)...)
+	remarksCollector.SetMaxRemarks(*maxRemarks)
+	if err := remarksCollector.SetPasses(splitList(*passFilter)); err != nil {
+		log.Fatalf("Invalid -remark-passes: %v", err)
+	}
+	if !*systemHdrs {
+		dirs, err := compiler.SystemIncludeDirs(context.Background(), compilerCmd, compilerArgs)
+		if err != nil {
+			log.Printf("Warning: could not list system include directories, using the common ones: %v", err)
+		}
+		remarksCollector.SkipSystemHeaders(dirs)
+	}
+	if *phaseMemory {
+		remarksCollector.CaptureTimeReport()
+	}
+	if *rawRemarks {
+		remarksCollector.KeepRawRecords()
+	}
+	if *strictMode {
+		remarksCollector.Strict()
+	}
+	if *pathRoot != "" {
+		root, err := filepath.Abs(*pathRoot)
+		if err != nil {
+			log.Fatalf("Invalid path root: %v", err)
+		}
+		remarksCollector.RelativeTo(root)
+	}
+	factory.RegisterCollector("remarks", remarksCollector)
+	resourceCollector := resource.NewCollector(buildCtx)
+	resourceCollector.SetSampleInterval(*memInterval)
+	resourceCollector.SetCompileState(remarksCollector.ProcessState)
+	factory.RegisterCollector("resource", resourceCollector)
+	factory.RegisterCollector("binary", binary.NewCollector(buildCtx))
+	if *linkMap {
+		linkMapCollector := linkmap.NewCollector(buildCtx)
+		linkMapCollector.SetMaxSymbols(*maxSymbols)
+		factory.RegisterCollector("linkmap", linkMapCollector)
+	}
+	factory.RegisterCollector("labels", labels.NewCollector(splitList(*labelEnv), buildLabels))
 
 	// Initialize and run collectors
 	build := &buildv1.Build{
@@ -78,8 +193,42 @@ func main() {
 		StartTime: timestamppb.New(startTime),
 	}
 
+	// Connect to the server
+	c, err := client.New(client.Options{
+		Address: *serverAddr,
+		TLS:     *useTLS,
+		Token:   *token,
+	})
+	if err != nil {
+		log.Fatalf("Failed to connect: %v", err)
+	}
+	defer c.Close()
+
 	ctx := context.Background()
 
+	// Stream remarks into the build while the compiler runs. A server that
+	// cannot take them gets the build once the compile ends instead; the
+	// compile must not wait on it.
+	var remarkStream *client.RemarkStream
+	begun := false
+	if *streamMode {
+		if _, err := c.Begin(ctx, build); err != nil {
+			log.Printf("Warning: failed to begin build, storing it after the compile: %v", err)
+		} else {
+			begun = true
+			if remarkStream, err = c.AppendRemarks(ctx, buildID); err != nil {
+				log.Printf("Warning: failed to open remark stream, sending remarks after the compile: %v", err)
+			} else {
+				remarksCollector.StreamRemarks(func(batch []models.CompilerRemark) error {
+					return remarkStream.Send(client.RemarksToProto(batch))
+				})
+			}
+		}
+	}
+
+	cacheProbe := cache.Start(wrapper)
+	buildCtx.Env = cacheProbe.Env()
+
 	// Initialize collectors
 	for name, collector := range factory.GetCollectors() {
 		if err := collector.Initialize(ctx); err != nil {
@@ -88,9 +237,21 @@ func main() {
 		}
 	}
 
-	// Run collectors
-	for name, collector := range factory.GetCollectors() {
-		if err := collector.Collect(ctx); err != nil {
+	// Sample memory while the compile runs; collecting the resource usage
+	// afterwards stops it
+	if err := resourceCollector.StartTracking(); err != nil {
+		log.Printf("Warning: failed to track resource usage: %v", err)
+	}
+
+	// Run collectors concurrently, then store their data in a fixed order
+	var rawRecords []byte
+	errs, err := collectAll(ctx, factory, buildCtx.Config, *strictMode)
+	if err != nil {
+		log.Fatalf("Remark collection failed: %v", err)
+	}
+	for _, name := range collectorNames(factory) {
+		collector, _ := factory.GetCollector(name)
+		if err := errs[name]; err != nil {
 			log.Printf("Warning: collection failed for %s: %v", name, err)
 			continue
 		}
@@ -100,253 +261,190 @@ func main() {
 			switch name {
 			case "environment":
 				if env, ok := data.(models.Environment); ok {
-					build.Environment = convertEnvironment(env)
+					build.Environment = client.EnvironmentToProto(env)
+				}
+			case "git":
+				if vc, ok := data.(models.VersionControl); ok {
+					build.VersionControl = client.VersionControlToProto(vc)
 				}
 			case "hardware":
 				if hw, ok := data.(models.Hardware); ok {
-					build.Hardware = convertHardware(hw)
+					build.Hardware = client.HardwareToProto(hw)
 				}
 			case "compiler":
 				if comp, ok := data.(models.Compiler); ok {
-					build.Compiler = convertCompiler(comp)
+					build.Compiler = client.CompilerToProto(comp)
 				}
 			case "resource":
 				if res, ok := data.(models.ResourceUsage); ok {
-					build.ResourceUsage = convertResourceUsage(res)
+					build.ResourceUsage = client.ResourceUsageToProto(res)
+				}
+			case "labels":
+				if l, ok := data.(map[string]string); ok {
+					build.Labels = l
+				}
+			case "binary":
+				if m, ok := data.(models.BuildMetrics); ok {
+					build.Metrics = client.MetricsToProto(m)
+				}
+			case "linkmap":
+				if m, ok := data.(models.LinkMap); ok {
+					build.LinkMap = client.LinkMapToProto(m)
 				}
 			case "remarks":
 				if remarks, ok := data.([]models.CompilerRemark); ok {
-					build.Remarks = convertRemarks(remarks)
+					build.Remarks = client.RemarksToProto(remarks)
+				}
+				// Uploaded separately once the build is stored
+				rawRecords, build.RawRemarksTruncated = remarksCollector.RawRecords()
+				build.RemarksTruncated, build.RemarksSeen = remarksCollector.Truncated()
+				if n := remarksCollector.SystemHeaderRemarks(); n > 0 {
+					log.Printf("Dropped %d remarks in system headers, use -include-system-headers to keep them", n)
 				}
 			}
 		}
 	}
 
-	// Set end time and duration
-	endTime := time.Now()
-	build.EndTime = timestamppb.New(endTime)
-	build.Duration = endTime.Sub(startTime).Seconds()
+	// Release collector resources; a failed cleanup never fails the build
+	cleanupCollectors(ctx, factory)
 
-	// Connect to the server
-	conn, err := grpcutil.CreateGRPCConnection(*serverAddr, *useTLS)
-	if err != nil {
-		log.Fatalf("Failed to connect: %v", err)
+	// Record the command as given, without the flags the collectors added,
+	// so it hashes the same however it was collected. On request, also
+	// record what the driver spawned.
+	command := models.Command{
+		Executable: buildCtx.Compiler,
+		Arguments:  compilerArgs,
 	}
-	defer conn.Close()
-
-	client := buildv1.NewBuildServiceClient(conn)
-
-	// Store build
-	response, err := client.CreateBuild(ctx, &buildv1.CreateBuildRequest{
-		Build: build,
-	})
-	if err != nil {
-		log.Fatalf("Failed to store build: %v", err)
+	if wd, err := os.Getwd(); err == nil {
+		command.WorkingDir = wd
 	}
-
-	if *verbose {
-		fmt.Printf("Build completed. Build ID: %s\n", response.Id)
-		fmt.Printf("Build success: %v\n", build.Success)
-		if build.Error != "" {
-			fmt.Printf("Build error: %s\n", build.Error)
+	if *driverTree {
+		invocations, err := compiler.Invocations(ctx, buildCtx.Compiler, buildCtx.Args)
+		if err != nil {
+			log.Printf("Warning: failed to capture compiler invocations: %v", err)
 		}
-	} else {
-		fmt.Printf("Build ID: %s\n", response.Id)
+		command.Invocations = invocations
 	}
-}
+	build.Command = client.CommandToProto(command)
 
-// Converter functions for collected data
-func convertEnvironment(env models.Environment) *buildv1.Environment {
-	variables := make(map[string]string)
-	for _, v := range env.Variables {
-		if pair := strings.SplitN(v, "=", 2); len(pair) == 2 {
-			variables[pair[0]] = pair[1]
+	// Label cached compiles so they are not mistaken for fast ones
+	build.CacheStatus = cacheProbe.Finish()
+	if wrapper != "" {
+		if build.Compiler == nil {
+			build.Compiler = &buildv1.Compiler{}
 		}
+		build.Compiler.Wrapper = wrapper
 	}
 
-	return &buildv1.Environment{
-		Os:         env.OS,
-		Arch:       env.Arch,
-		WorkingDir: env.WorkingDir,
-		Variables:  variables,
+	// Record how the compiler exited and the warnings and errors it printed
+	exitCode, runErr := remarksCollector.ExitCode()
+	build.Success = runErr == nil
+	if runErr != nil {
+		build.Error = runErr.Error()
 	}
-}
-
-func convertHardware(hw models.Hardware) *buildv1.Hardware {
-	gpus := make([]*buildv1.GPU, len(hw.GPUs))
-	for i, gpu := range hw.GPUs {
-		gpus[i] = &buildv1.GPU{
-			Model:       gpu.Model,
-			Memory:      gpu.Memory,
-			Driver:      gpu.Driver,
-			ComputeCaps: gpu.ComputeCaps,
-		}
+	// Proto strings must be valid UTF-8, which compiler output need not be
+	stdout, stderr := remarksCollector.Streams()
+	build.Output = &buildv1.Output{
+		Stdout:    strings.ToValidUTF8(stdout, "\uFFFD"),
+		Stderr:    strings.ToValidUTF8(stderr, "\uFFFD"),
+		ExitCode:  int32(exitCode),
+		Truncated: remarksCollector.OutputTruncated(),
 	}
 
-	return &buildv1.Hardware{
-		Cpu: &buildv1.CPU{
-			Model:     hw.CPU.Model,
-			Vendor:    hw.CPU.Vendor,
-			Cores:     hw.CPU.Cores,
-			Threads:   hw.CPU.Threads,
-			Frequency: hw.CPU.Frequency,
-			CacheSize: hw.CPU.CacheSize,
-		},
-		Memory: &buildv1.Memory{
-			Total:     hw.Memory.Total,
-			Available: hw.Memory.Available,
-			Used:      hw.Memory.Used,
-			SwapTotal: hw.Memory.SwapTotal,
-			SwapFree:  hw.Memory.SwapFree,
-		},
-		Gpus: gpus,
+	compilerOutput := bytes.ToValidUTF8(remarksCollector.Output(), []byte("\uFFFD"))
+	compilerDiagnostics, err := diagnostics.Parse(bytes.NewReader(compilerOutput))
+	if err != nil {
+		log.Printf("Warning: failed to parse diagnostics: %v", err)
 	}
-}
-
-func convertCompiler(comp models.Compiler) *buildv1.Compiler {
-	return &buildv1.Compiler{
-		Name:    comp.Name,
-		Version: comp.Version,
-		Target:  comp.Target,
-		Language: &buildv1.Language{
-			Name:          comp.Language.Name,
-			Version:       comp.Language.Version,
-			Specification: comp.Language.Specification,
-		},
-		Features: &buildv1.CompilerFeatures{
-			SupportsOpenmp: comp.Features.SupportsOpenMP,
-			SupportsGpu:    comp.Features.SupportsGPU,
-			SupportsLto:    comp.Features.SupportsLTO,
-			SupportsPgo:    comp.Features.SupportsPGO,
-			Extensions:     comp.Features.Extensions,
-		},
-		Options:       comp.Options,
-		Optimizations: comp.Optimizations,
-		Flags:         comp.Flags,
+	build.Output.Diagnostics = client.DiagnosticsToProto(compilerDiagnostics)
+
+	// Attribute memory to compiler phases when the compiler reported it
+	if *phaseMemory && build.ResourceUsage != nil {
+		phases, err := timereport.ParsePhaseMemory(bytes.NewReader(compilerOutput))
+		if err != nil {
+			log.Printf("Warning: failed to parse time report: %v", err)
+		} else if len(phases) > 0 {
+			build.ResourceUsage.PhaseMemory = phases
+		}
 	}
-}
 
-func convertResourceUsage(res models.ResourceUsage) *buildv1.ResourceUsage {
-	return &buildv1.ResourceUsage{
-		MaxMemory: res.MaxMemory,
-		CpuTime:   res.CPUTime,
-		Threads:   res.Threads,
-		Io: &buildv1.IOStats{
-			ReadBytes:  res.IO.ReadBytes,
-			WriteBytes: res.IO.WriteBytes,
-			ReadCount:  res.IO.ReadCount,
-			WriteCount: res.IO.WriteCount,
-		},
-	}
-}
+	// Set end time and duration
+	endTime := time.Now()
+	build.EndTime = timestamppb.New(endTime)
+	build.Duration = endTime.Sub(startTime).Seconds()
 
-func convertRemarks(remarks []models.CompilerRemark) []*buildv1.CompilerRemark {
-	log.Printf("Converting %d remarks to protobuf", len(remarks))
-	pbRemarks := make([]*buildv1.CompilerRemark, len(remarks))
-
-	for i, remark := range remarks {
-		log.Printf("Converting remark %d: %s", i, remark.Message)
-
-		pbRemark := &buildv1.CompilerRemark{
-			Message:   remark.Message,
-			Function:  remark.Function,
-			Timestamp: timestamppb.New(remark.Timestamp),
-			Location: &buildv1.Location{
-				File:     remark.Location.File,
-				Line:     remark.Location.Line,
-				Column:   remark.Location.Column,
-				Function: remark.Location.Function,
-				Region:   remark.Location.Region,
-				Artifact: remark.Location.Artifact,
-			},
+	// Store build, unless sampling drops it
+	sampledOut := build.Success && !keepSample(*sampleRate, build)
+	if sampledOut {
+		fmt.Printf("Build not submitted (sampled out at rate %g)\n", *sampleRate)
+	} else {
+		var response *buildv1.Build
+		if remarkStream != nil {
+			if stored, err := remarkStream.Close(); err != nil {
+				log.Printf("Warning: remark stream failed: %v", err)
+			} else if *verbose {
+				log.Printf("Streamed %d remarks", stored)
+			}
 		}
-
-		// Convert type
-		switch strings.ToLower(string(remark.Type)) {
-		case "optimization":
-			pbRemark.Type = buildv1.CompilerRemark_OPTIMIZATION
-		case "kernel":
-			pbRemark.Type = buildv1.CompilerRemark_KERNEL
-		case "analysis":
-			pbRemark.Type = buildv1.CompilerRemark_ANALYSIS
-		case "metric":
-			pbRemark.Type = buildv1.CompilerRemark_METRIC
-		default:
-			pbRemark.Type = buildv1.CompilerRemark_INFO
+		if begun {
+			// Remarks the stream missed are sent again; the server keeps
+			// one of each
+			response, err = c.Finalize(ctx, build)
+		} else {
+			response, err = c.Store(ctx, build)
 		}
-
-		// Convert pass
-		switch strings.ToLower(string(remark.Pass)) {
-		case "vectorization":
-			pbRemark.Pass = buildv1.CompilerRemark_VECTORIZATION
-		case "inlining":
-			pbRemark.Pass = buildv1.CompilerRemark_INLINING
-		case "kernel-info":
-			pbRemark.Pass = buildv1.CompilerRemark_KERNEL_INFO
-		case "size-info":
-			pbRemark.Pass = buildv1.CompilerRemark_SIZE_INFO
-		default:
-			pbRemark.Pass = buildv1.CompilerRemark_PASS_ANALYSIS
+		if err == nil && len(rawRecords) > 0 {
+			if stored, err := c.UploadRawRemarks(ctx, response.Id, rawRecords); err != nil {
+				log.Printf("Warning: failed to upload raw remarks: %v", err)
+			} else if !stored {
+				log.Printf("Warning: the server discarded the raw remarks, enable -store-raw-remarks there")
+			}
 		}
-
-		// Convert status
-		switch strings.ToLower(string(remark.Status)) {
-		case "passed":
-			pbRemark.Status = buildv1.CompilerRemark_PASSED
-		case "missed":
-			pbRemark.Status = buildv1.CompilerRemark_MISSED
-		case "analysis":
-			pbRemark.Status = buildv1.CompilerRemark_STATUS_ANALYSIS
+		switch {
+		case err != nil:
+			// The compile itself is done; its result matters more to the
+			// build system than the telemetry
+			log.Printf("Warning: failed to store build: %v", err)
+		case *verbose:
+			fmt.Printf("Build completed. Build ID: %s\n", response.Id)
+			fmt.Printf("Build success: %v\n", build.Success)
+			if build.Error != "" {
+				fmt.Printf("Build error: %s\n", build.Error)
+			}
 		default:
-			pbRemark.Status = buildv1.CompilerRemark_PASSED
+			fmt.Printf("Build ID: %s\n", response.Id)
 		}
+	}
 
-		// Convert kernel info if present
-		if remark.KernelInfo != nil {
-			memAccesses := make([]*buildv1.MemoryAccess, len(remark.KernelInfo.MemoryAccesses))
-			for j, acc := range remark.KernelInfo.MemoryAccesses {
-				memAccesses[j] = &buildv1.MemoryAccess{
-					Type:          acc.Type,
-					AddressSpace:  acc.AddressSpace,
-					Instruction:   acc.Instruction,
-					Variable:      acc.Variable,
-					AccessPattern: acc.AccessPattern,
-				}
-			}
+	// Failed compiles end on the compiler's errors, which the summary would
+	// bury. It shows the flags as given, not those the collectors added.
+	if build.Success && !sampledOut && (*summary || isTerminal(os.Stderr)) {
+		fmt.Fprintln(os.Stderr, buildSummary(build, compilerArgs))
+	}
 
-			pbRemark.KernelInfo = &buildv1.KernelInfo{
-				ThreadLimit:              remark.KernelInfo.ThreadLimit,
-				MaxThreadsX:              remark.KernelInfo.MaxThreadsX,
-				MaxThreadsY:              remark.KernelInfo.MaxThreadsY,
-				MaxThreadsZ:              remark.KernelInfo.MaxThreadsZ,
-				SharedMemory:             remark.KernelInfo.SharedMemory,
-				Target:                   remark.KernelInfo.Target,
-				DirectCalls:              remark.KernelInfo.DirectCalls,
-				IndirectCalls:            remark.KernelInfo.IndirectCalls,
-				Callees:                  remark.KernelInfo.Callees,
-				AllocasCount:             remark.KernelInfo.AllocasCount,
-				AllocasStaticSize:        remark.KernelInfo.AllocasStaticSize,
-				AllocasDynamicCount:      remark.KernelInfo.AllocasDynamicCount,
-				FlatAddressSpaceAccesses: remark.KernelInfo.FlatAddressSpaceAccesses,
-				InlineAssemblyCalls:      remark.KernelInfo.InlineAssemblyCalls,
-				MemoryAccesses:           memAccesses,
-				Metrics:                  remark.KernelInfo.Metrics,
-				Attributes:               remark.KernelInfo.Attributes,
-			}
-		}
+	// Exit like the compiler so build systems see failed compiles
+	if exitCode != 0 && !*alwaysOK {
+		os.Exit(exitCode)
+	}
+}
 
-		// Convert metadata
-		if len(remark.Metadata) > 0 {
-			metadata, err := structpb.NewStruct(map[string]interface{}(remark.Metadata))
-			if err == nil {
-				pbRemark.Metadata = metadata
-			} else {
-				log.Printf("Warning: Failed to convert metadata for remark: %v", err)
-			}
+// cleanupCollectors runs the Cleanup of every collector of factory, whether
+// or not it collected anything. Failures are logged and otherwise ignored.
+func cleanupCollectors(ctx context.Context, factory *models.CollectorFactory) {
+	for name, collector := range factory.GetCollectors() {
+		if err := collector.Cleanup(ctx); err != nil {
+			log.Printf("Warning: cleanup failed for %s: %v", name, err)
 		}
-
-		pbRemarks[i] = pbRemark
 	}
+}
 
-	return pbRemarks
+// splitList splits a comma-separated flag value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
 }
@@ -0,0 +1,283 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	buildv1 "builds/api/build"
+)
+
+// runMainEnv makes the test binary run the wrapper instead of the tests
+const runMainEnv = "BUILDS_TEST_RUN_MAIN"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(runMainEnv) == "1" {
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// fakeServer records the builds the wrapper stores
+type fakeServer struct {
+	buildv1.UnimplementedBuildServiceServer
+
+	mu     sync.Mutex
+	builds []*buildv1.Build
+	fail   bool // reject every build
+}
+
+func (s *fakeServer) CreateBuild(ctx context.Context, req *buildv1.CreateBuildRequest) (*buildv1.Build, error) {
+	if s.fail {
+		return nil, status.Error(codes.Internal, "database is down")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.builds = append(s.builds, req.Build)
+	return req.Build, nil
+}
+
+// startServer serves fake on a local port, returning its address
+func startServer(t *testing.T, fake *fakeServer) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer()
+	buildv1.RegisterBuildServiceServer(server, fake)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	return listener.Addr().String()
+}
+
+// fakeCompiler writes a compiler that runs script
+func fakeCompiler(t *testing.T, script string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "cc")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// runWrapper runs the wrapper around compiler, returning its exit code and
+// what it printed
+func runWrapper(t *testing.T, server string, compiler string, flags ...string) (code int, stdout, stderr string) {
+	t.Helper()
+	args := append([]string{"-server", server, "-collector-timeout", "30"}, flags...)
+	args = append(args, compiler, "-c", "foo.c")
+
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Dir = t.TempDir()
+	cmd.Env = append(os.Environ(), runMainEnv+"=1")
+	var out, errOut strings.Builder
+	cmd.Stdout = &out
+	cmd.Stderr = &errOut
+
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+	case errors.As(err, &exitErr):
+		code = exitErr.ExitCode()
+	default:
+		t.Fatalf("failed to run the wrapper: %v", err)
+	}
+	return code, out.String(), errOut.String()
+}
+
+func TestExitCode(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs the wrapper")
+	}
+
+	tests := []struct {
+		name     string
+		script   string
+		flags    []string
+		fail     bool
+		wantCode int
+	}{
+		{"compiler succeeds", "exit 0", nil, false, 0},
+		{"compiler fails", "exit 3", nil, false, 3},
+		{"always succeed", "exit 3", []string{"-always-succeed"}, false, 0},
+		{"store fails", "exit 3", nil, true, 3},
+		{"store fails after a successful compile", "exit 0", nil, true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeServer{fail: tt.fail}
+			server := startServer(t, fake)
+
+			code, _, stderr := runWrapper(t, server, fakeCompiler(t, tt.script), tt.flags...)
+			if code != tt.wantCode {
+				t.Errorf("exit code %d, want %d\n%s", code, tt.wantCode, stderr)
+			}
+			if tt.fail && !strings.Contains(stderr, "failed to store build") {
+				t.Errorf("no warning about the failed store:\n%s", stderr)
+			}
+			if !tt.fail && len(fake.builds) != 1 {
+				t.Errorf("stored %d builds, want 1", len(fake.builds))
+			}
+		})
+	}
+}
+
+func TestOutputPassthrough(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs the wrapper")
+	}
+
+	fake := &fakeServer{}
+	server := startServer(t, fake)
+	compiler := fakeCompiler(t, `echo "compiled foo.c"
+printf 'foo.c:1:1: warning: odd byte \377 in source\n' >&2`)
+
+	_, stdout, stderr := runWrapper(t, server, compiler)
+	if !strings.Contains(stdout, "compiled foo.c") {
+		t.Errorf("compiler stdout not passed through:\n%s", stdout)
+	}
+	if !strings.Contains(stderr, "warning: odd byte \377 in source") {
+		t.Errorf("compiler stderr not passed through:\n%s", stderr)
+	}
+
+	if len(fake.builds) != 1 {
+		t.Fatalf("stored %d builds, want 1\n%s", len(fake.builds), stderr)
+	}
+	output := fake.builds[0].Output
+	if output.Stdout != "compiled foo.c\n" {
+		t.Errorf("stored stdout %q", output.Stdout)
+	}
+	if output.Stderr != "foo.c:1:1: warning: odd byte \uFFFD in source\n" {
+		t.Errorf("stored stderr %q", output.Stderr)
+	}
+	if len(output.Diagnostics) != 1 {
+		t.Errorf("stored %d diagnostics, want 1", len(output.Diagnostics))
+	}
+}
+
+func TestNoRedact(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs the wrapper")
+	}
+	t.Setenv("GITHUB_TOKEN", "ghp_secret")
+
+	tests := []struct {
+		name       string
+		flags      []string
+		wantSecret bool
+	}{
+		{"redacted by default", nil, false},
+		{"no-redact", []string{"-no-redact"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeServer{}
+			server := startServer(t, fake)
+
+			_, _, stderr := runWrapper(t, server, fakeCompiler(t, "exit 0"), tt.flags...)
+			if warned := strings.Contains(stderr, "WARNING: -no-redact"); warned != tt.wantSecret {
+				t.Errorf("warned = %v, want %v\n%s", warned, tt.wantSecret, stderr)
+			}
+			if len(fake.builds) != 1 {
+				t.Fatalf("stored %d builds, want 1\n%s", len(fake.builds), stderr)
+			}
+			if _, kept := fake.builds[0].Environment.GetVariables()["GITHUB_TOKEN"]; kept != tt.wantSecret {
+				t.Errorf("GITHUB_TOKEN stored = %v, want %v", kept, tt.wantSecret)
+			}
+		})
+	}
+}
+
+func TestUnsupportedDriverKeepsFlags(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs the wrapper")
+	}
+
+	fake := &fakeServer{}
+	server := startServer(t, fake)
+	// nvcc fails on the GCC-style flags the remarks collector adds
+	nvcc := filepath.Join(t.TempDir(), "nvcc")
+	script := `#!/bin/sh
+for arg; do
+	case $arg in -f*) echo "nvcc fatal   : Unknown option '$arg'" >&2; exit 1 ;; esac
+done
+exit 0
+`
+	if err := os.WriteFile(nvcc, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	code, _, stderr := runWrapper(t, server, nvcc)
+	if code != 0 {
+		t.Fatalf("exit code %d, want 0\n%s", code, stderr)
+	}
+	if len(fake.builds) != 1 {
+		t.Fatalf("stored %d builds, want 1\n%s", len(fake.builds), stderr)
+	}
+	build := fake.builds[0]
+	if !build.Success {
+		t.Errorf("build failed: %s", build.Error)
+	}
+}
+
+func TestCommandRecordedAsGiven(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs the wrapper")
+	}
+
+	fake := &fakeServer{}
+	server := startServer(t, fake)
+	// Named gcc, so the remarks collector adds its flags and -O2, which
+	// must not reach the stored command or its hash
+	gcc := filepath.Join(t.TempDir(), "gcc")
+	if err := os.WriteFile(gcc, []byte("#!/bin/sh\nexit 0\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, stderr := runWrapper(t, server, gcc)
+	if len(fake.builds) != 1 {
+		t.Fatalf("stored %d builds, want 1\n%s", len(fake.builds), stderr)
+	}
+	if args := fake.builds[0].Command.GetArguments(); !slices.Equal(args, []string{"-c", "foo.c"}) {
+		t.Errorf("recorded arguments %q, want the command line as given", args)
+	}
+}
+
+func TestStreamFallsBackWithoutBeginBuild(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs the wrapper")
+	}
+
+	// The fake server leaves BeginBuild unimplemented, rejecting it
+	fake := &fakeServer{}
+	server := startServer(t, fake)
+
+	code, stdout, stderr := runWrapper(t, server, fakeCompiler(t, "echo compiled"), "-stream-remarks")
+	if code != 0 {
+		t.Fatalf("exit code %d, want 0\n%s", code, stderr)
+	}
+	if !strings.Contains(stdout, "compiled") {
+		t.Errorf("the compile did not run:\n%s", stdout)
+	}
+	if !strings.Contains(stderr, "failed to begin build") {
+		t.Errorf("no warning about the rejected BeginBuild:\n%s", stderr)
+	}
+	if len(fake.builds) != 1 {
+		t.Errorf("stored %d builds, want 1 created at the end\n%s", len(fake.builds), stderr)
+	}
+}
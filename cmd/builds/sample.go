@@ -0,0 +1,34 @@
+// cmd/builds/sample.go
+
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math"
+
+	buildv1 "builds/api/build"
+	"builds/internal/parsers/invocations"
+)
+
+// keepSample reports whether a successful build is submitted at the given
+// sampling rate. The decision is not random: it hashes the normalised
+// command and the commit, so a retried compile of the same commit is kept
+// or dropped just like the first attempt, and about rate of the distinct
+// compiles are kept.
+func keepSample(rate float64, build *buildv1.Build) bool {
+	switch {
+	case rate >= 1:
+		return true
+	case rate <= 0:
+		return false
+	}
+
+	cmd := build.GetCommand()
+	key := invocations.Hash(cmd.GetExecutable(), cmd.GetArguments(), cmd.GetWorkingDir()) +
+		"\x00" + build.GetVersionControl().GetCommit()
+	sum := sha256.Sum256([]byte(key))
+	// The first 64 bits of the hash, spread evenly over [0, 1)
+	position := float64(binary.BigEndian.Uint64(sum[:8])) / math.Exp2(64)
+	return position < rate
+}
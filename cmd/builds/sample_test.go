@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	buildv1 "builds/api/build"
+)
+
+func sampledBuild(source, commit string, extra ...string) *buildv1.Build {
+	return &buildv1.Build{
+		Success: true,
+		Command: &buildv1.Command{
+			Executable: "clang",
+			Arguments:  append([]string{"-O2", "-c", source}, extra...),
+			WorkingDir: "/src",
+		},
+		VersionControl: &buildv1.VersionControl{Commit: commit},
+	}
+}
+
+func TestKeepSampleRate(t *testing.T) {
+	const n = 20000
+	for _, rate := range []float64{0.1, 0.5, 0.9} {
+		kept := 0
+		for i := range n {
+			if keepSample(rate, sampledBuild(fmt.Sprintf("file%d.c", i), "abc123")) {
+				kept++
+			}
+		}
+		// The binomial standard deviation is at most 0.0036 here
+		if got := float64(kept) / n; got < rate-0.015 || got > rate+0.015 {
+			t.Errorf("rate %g kept %.3f of the builds", rate, got)
+		}
+	}
+
+	build := sampledBuild("foo.c", "abc123")
+	if !keepSample(1, build) || keepSample(0, build) {
+		t.Error("rates 1 and 0 do not keep all and none")
+	}
+}
+
+func TestKeepSampleDeterministic(t *testing.T) {
+	for i := range 200 {
+		source := fmt.Sprintf("file%d.c", i)
+		first := keepSample(0.5, sampledBuild(source, "abc123"))
+		// A retry of the same compile, writing elsewhere
+		retry := keepSample(0.5, sampledBuild(source, "abc123", "-o", "/tmp/retry.o"))
+		if first != retry {
+			t.Fatalf("%s kept %v on the first attempt and %v on retry", source, first, retry)
+		}
+	}
+
+	// Another commit is sampled independently
+	same := 0
+	for i := range 200 {
+		source := fmt.Sprintf("file%d.c", i)
+		if keepSample(0.5, sampledBuild(source, "abc123")) == keepSample(0.5, sampledBuild(source, "def456")) {
+			same++
+		}
+	}
+	if same == 200 {
+		t.Error("the commit does not affect sampling")
+	}
+}
+
+func TestSampleKeepsFailures(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs the wrapper")
+	}
+
+	tests := []struct {
+		name       string
+		script     string
+		wantStored int
+	}{
+		{"failed build", "exit 1", 1},
+		{"successful build", "exit 0", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeServer{}
+			server := startServer(t, fake)
+
+			_, stdout, stderr := runWrapper(t, server, fakeCompiler(t, tt.script), "-sample", "0")
+			if len(fake.builds) != tt.wantStored {
+				t.Errorf("stored %d builds, want %d\n%s", len(fake.builds), tt.wantStored, stderr)
+			}
+			if sampled := strings.Contains(stdout, "sampled out"); sampled != (tt.wantStored == 0) {
+				t.Errorf("sampled out = %v:\n%s", sampled, stdout)
+			}
+		})
+	}
+}
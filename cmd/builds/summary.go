@@ -0,0 +1,77 @@
+// cmd/builds/summary.go
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	buildv1 "builds/api/build"
+	"builds/internal/utils/units"
+)
+
+// buildSummary renders a one-line overview of a collected build, e.g.
+// "clang 18.1 -O2 in 4.2s, 1.1GB peak, 37 missed opts"
+func buildSummary(build *buildv1.Build, args []string) string {
+	var head []string
+	if comp := build.Compiler; comp != nil && comp.Name != "" {
+		head = append(head, comp.Name)
+		if v := shortVersion(comp.Version); v != "" {
+			head = append(head, v)
+		}
+	}
+	if level := optimizationLevel(args); level != "" {
+		head = append(head, level)
+	}
+	head = append(head, fmt.Sprintf("in %.1fs", build.Duration))
+	if build.CacheStatus != "" {
+		head = append(head, fmt.Sprintf("(%s %s)", build.Compiler.GetWrapper(), build.CacheStatus))
+	}
+
+	parts := []string{strings.Join(head, " ")}
+
+	if usage := build.ResourceUsage; usage != nil && usage.MaxMemory > 0 {
+		parts = append(parts, units.FormatBytes(usage.MaxMemory)+" peak")
+	}
+
+	missed := 0
+	for _, remark := range build.Remarks {
+		if remark.Status == buildv1.CompilerRemark_MISSED {
+			missed++
+		}
+	}
+	parts = append(parts, fmt.Sprintf("%d missed opts", missed))
+
+	return strings.Join(parts, ", ")
+}
+
+// shortVersion trims a version to major.minor
+func shortVersion(version string) string {
+	fields := strings.SplitN(version, ".", 3)
+	if len(fields) < 2 {
+		return version
+	}
+	return fields[0] + "." + fields[1]
+}
+
+// optimizationLevel returns the last -O flag passed to the compiler
+func optimizationLevel(args []string) string {
+	level := ""
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-O") {
+			level = arg
+		}
+	}
+	return level
+}
+
+// isTerminal reports whether f is attached to a terminal rather than a pipe
+// or file
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	buildv1 "builds/api/build"
+)
+
+func TestBuildSummary(t *testing.T) {
+	missed := &buildv1.CompilerRemark{Status: buildv1.CompilerRemark_MISSED}
+	passed := &buildv1.CompilerRemark{Status: buildv1.CompilerRemark_PASSED}
+
+	tests := []struct {
+		name  string
+		build *buildv1.Build
+		args  []string
+		want  string
+	}{
+		{
+			name: "full",
+			build: &buildv1.Build{
+				Duration:      4.21,
+				Compiler:      &buildv1.Compiler{Name: "clang", Version: "18.1.8"},
+				ResourceUsage: &buildv1.ResourceUsage{MaxMemory: 1181116006},
+				Remarks:       []*buildv1.CompilerRemark{missed, passed, missed},
+			},
+			args: []string{"-O0", "-c", "foo.c", "-O2"},
+			want: "clang 18.1 -O2 in 4.2s, 1.1GiB peak, 2 missed opts",
+		},
+		{
+			name:  "nothing collected",
+			build: &buildv1.Build{Duration: 0.04},
+			args:  []string{"-c", "foo.c"},
+			want:  "in 0.0s, 0 missed opts",
+		},
+		{
+			name: "major version only",
+			build: &buildv1.Build{
+				Duration:      1,
+				Compiler:      &buildv1.Compiler{Name: "gcc", Version: "13"},
+				ResourceUsage: &buildv1.ResourceUsage{MaxMemory: 512},
+			},
+			args: []string{"-Os"},
+			want: "gcc 13 -Os in 1.0s, 512B peak, 0 missed opts",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := buildSummary(tt.build, tt.args); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSummaryPrinted(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs the wrapper")
+	}
+
+	tests := []struct {
+		name   string
+		script string
+		flags  []string
+		want   bool
+	}{
+		{"successful build", "exit 0", nil, true},
+		{"failed build", "exit 1", nil, false},
+		{"sampled out", "exit 0", []string{"-sample", "0"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := startServer(t, &fakeServer{})
+			// Named gcc, so the remarks collector adds -O2 to its arguments
+			gcc := filepath.Join(t.TempDir(), "gcc")
+			if err := os.WriteFile(gcc, []byte("#!/bin/sh\n"+tt.script+"\n"), 0o755); err != nil {
+				t.Fatal(err)
+			}
+
+			_, _, stderr := runWrapper(t, server, gcc, append([]string{"-summary"}, tt.flags...)...)
+			printed := strings.Contains(stderr, "missed opts")
+			if printed != tt.want {
+				t.Errorf("summary printed = %v, want %v\n%s", printed, tt.want, stderr)
+			}
+			if printed && strings.Contains(stderr, "-O2 in") {
+				t.Errorf("summary shows the flags the collectors added:\n%s", stderr)
+			}
+		})
+	}
+}
@@ -0,0 +1,238 @@
+// cmd/buildsctl/compare.go
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"builds/internal/analysis/performance"
+	buildsclient "builds/internal/client"
+	"builds/internal/models"
+	"builds/internal/utils/units"
+)
+
+// Units of compared metrics, which decide how values are printed
+const (
+	unitSeconds = "seconds"
+	unitBytes   = "bytes"
+	unitRatio   = "ratio"
+	unitCount   = "count"
+)
+
+// metricDiff compares one metric across two builds. A and B are nil when
+// that build did not record the metric, and Delta and Percent are then nil
+// too; Percent is also nil when A is zero.
+type metricDiff struct {
+	Name    string   `json:"name"`
+	Unit    string   `json:"unit"`
+	A       *float64 `json:"a"`
+	B       *float64 `json:"b"`
+	Delta   *float64 `json:"delta"`
+	Percent *float64 `json:"percent"`
+}
+
+// buildDiff compares build B against build A
+type buildDiff struct {
+	A       string       `json:"a"`
+	B       string       `json:"b"`
+	Metrics []metricDiff `json:"metrics"`
+	Passes  []metricDiff `json:"remarksByPass"`
+}
+
+// diffBuilds compares two builds metric by metric. Both the display and
+// JSON output of compare are rendered from its result.
+func diffBuilds(a, b *models.Build) buildDiff {
+	diff := buildDiff{A: a.ID, B: b.ID}
+
+	effA, effB := efficiency(a), efficiency(b)
+	diff.Metrics = []metricDiff{
+		newMetricDiff("Duration", unitSeconds, present(a.Duration), present(b.Duration)),
+		newMetricDiff("Compile Time", unitSeconds, compileTime(a), compileTime(b)),
+		newMetricDiff("Link Time", unitSeconds, linkTime(a), linkTime(b)),
+		newMetricDiff("Max Memory", unitBytes, present(float64(a.ResourceUsage.MaxMemory)), present(float64(b.ResourceUsage.MaxMemory))),
+		newMetricDiff("Output Size", unitBytes, present(float64(a.Metrics.OutputSize)), present(float64(b.Metrics.OutputSize))),
+		newMetricDiff("Resource Efficiency", unitRatio, effA, effB),
+		newMetricDiff("Remarks", unitCount, count(len(a.Remarks)), count(len(b.Remarks))),
+	}
+
+	passesA, passesB := remarksByPass(a), remarksByPass(b)
+	names := make([]string, 0, len(passesA)+len(passesB))
+	for pass := range passesA {
+		names = append(names, pass)
+	}
+	for pass := range passesB {
+		if _, ok := passesA[pass]; !ok {
+			names = append(names, pass)
+		}
+	}
+	sort.Strings(names)
+	for _, pass := range names {
+		diff.Passes = append(diff.Passes,
+			newMetricDiff(pass, unitCount, count(passesA[pass]), count(passesB[pass])))
+	}
+
+	return diff
+}
+
+func newMetricDiff(name, unit string, a, b *float64) metricDiff {
+	d := metricDiff{Name: name, Unit: unit, A: a, B: b}
+	if a == nil || b == nil {
+		return d
+	}
+	delta := *b - *a
+	d.Delta = &delta
+	if *a != 0 {
+		percent := delta / *a * 100
+		d.Percent = &percent
+	}
+	return d
+}
+
+// present treats a zero value as not recorded
+func present(v float64) *float64 {
+	if v == 0 {
+		return nil
+	}
+	return &v
+}
+
+func count(n int) *float64 {
+	v := float64(n)
+	return &v
+}
+
+// compileTime and linkTime are missing when the build has no performance
+// data at all; a build with data may still have spent no time linking
+func compileTime(build *models.Build) *float64 {
+	if !hasPerformance(build) {
+		return nil
+	}
+	return &build.Performance.CompileTime
+}
+
+func linkTime(build *models.Build) *float64 {
+	if !hasPerformance(build) {
+		return nil
+	}
+	return &build.Performance.LinkTime
+}
+
+func hasPerformance(build *models.Build) bool {
+	perf := build.Performance
+	return perf.CompileTime != 0 || perf.LinkTime != 0 || perf.OptimizeTime != 0 || len(perf.Phases) > 0
+}
+
+// efficiency is the analyzer's resource efficiency, missing without
+// resource usage to compute it from
+func efficiency(build *models.Build) *float64 {
+	if build.ResourceUsage.CPUTime == 0 {
+		return nil
+	}
+	result, err := performance.Analyze(build)
+	if err != nil || math.IsNaN(result.ResourceEfficiency) || math.IsInf(result.ResourceEfficiency, 0) {
+		return nil
+	}
+	return &result.ResourceEfficiency
+}
+
+func remarksByPass(build *models.Build) map[string]int {
+	passes := make(map[string]int)
+	for _, remark := range build.Remarks {
+		pass := remark.Pass
+		if pass == "" {
+			pass = "unknown"
+		}
+		passes[pass]++
+	}
+	return passes
+}
+
+// compareBuilds fetches two builds and prints how the second differs from
+// the first
+func compareBuilds(ctx context.Context, client *buildsclient.Client, idA, idB string) {
+	a, err := client.Get(ctx, idA)
+	if err != nil {
+		log.Fatalf("Failed to get build %s: %v", idA, err)
+	}
+	b, err := client.Get(ctx, idB)
+	if err != nil {
+		log.Fatalf("Failed to get build %s: %v", idB, err)
+	}
+
+	diff := diffBuilds(buildsclient.BuildToModel(a), buildsclient.BuildToModel(b))
+	if err := writeDiff(os.Stdout, diff, *format); err != nil {
+		log.Fatalf("Failed to encode comparison: %v", err)
+	}
+}
+
+// writeDiff renders the comparison as JSON for the json format and as a
+// table otherwise
+func writeDiff(out io.Writer, diff buildDiff, format string) error {
+	if format == "json" {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(diff)
+	}
+	printDiff(out, diff)
+	return nil
+}
+
+func printDiff(out io.Writer, diff buildDiff) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintf(w, "METRIC\tA\tB\tDELTA\tCHANGE\n")
+	fmt.Fprintf(w, "Build\t%s\t%s\t\t\n", diff.A, diff.B)
+	for _, metric := range diff.Metrics {
+		printMetricDiff(w, metric.Name, metric)
+	}
+	if len(diff.Passes) > 0 {
+		fmt.Fprintf(w, "\nREMARKS BY PASS\t\t\t\t\n")
+		for _, pass := range diff.Passes {
+			printMetricDiff(w, "  "+pass.Name, pass)
+		}
+	}
+}
+
+func printMetricDiff(w io.Writer, label string, d metricDiff) {
+	value := func(v *float64) string {
+		if v == nil {
+			return "n/a"
+		}
+		return formatMetric(d.Unit, *v)
+	}
+
+	delta, change := "", ""
+	if d.Delta != nil {
+		sign := "+"
+		if *d.Delta < 0 {
+			sign = "-"
+		}
+		delta = sign + formatMetric(d.Unit, math.Abs(*d.Delta))
+	}
+	if d.Percent != nil {
+		change = fmt.Sprintf("%+.1f%%", *d.Percent)
+	}
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", label, value(d.A), value(d.B), delta, change)
+}
+
+func formatMetric(unit string, v float64) string {
+	switch unit {
+	case unitSeconds:
+		return fmt.Sprintf("%.*fs", *precision, v)
+	case unitBytes:
+		return units.FormatBytes(int64(v))
+	case unitRatio:
+		return fmt.Sprintf("%.1f%%", v*100)
+	default:
+		return fmt.Sprintf("%.0f", v)
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	buildv1 "builds/api/build"
+	buildsclient "builds/internal/client"
+	"builds/internal/models"
+)
+
+func TestDiffBuildsByPass(t *testing.T) {
+	remark := func(pass string) *buildv1.CompilerRemark {
+		return &buildv1.CompilerRemark{Pass: buildv1.CompilerRemark_PASS_ANALYSIS, PassName: pass}
+	}
+	// Builds as fetched from the server, whose pass enum is only a category
+	a := buildsclient.BuildToModel(&buildv1.Build{
+		Id:      "a",
+		Remarks: []*buildv1.CompilerRemark{remark("loop-vectorize"), remark("inline"), remark("inline")},
+	})
+	b := buildsclient.BuildToModel(&buildv1.Build{
+		Id:      "b",
+		Remarks: []*buildv1.CompilerRemark{remark("inline"), remark("licm")},
+	})
+
+	diff := diffBuilds(a, b)
+
+	want := map[string][2]float64{
+		"inline":         {2, 1},
+		"licm":           {0, 1},
+		"loop-vectorize": {1, 0},
+	}
+	if len(diff.Passes) != len(want) {
+		t.Fatalf("got passes %+v, want %v", diff.Passes, want)
+	}
+	for _, pass := range diff.Passes {
+		counts, ok := want[pass.Name]
+		if !ok {
+			t.Errorf("unexpected pass %q", pass.Name)
+			continue
+		}
+		if *pass.A != counts[0] || *pass.B != counts[1] {
+			t.Errorf("%s: got %v and %v, want %v", pass.Name, *pass.A, *pass.B, counts)
+		}
+	}
+}
+
+// compareFixture is a pair of builds where B got faster, grew and lost its
+// performance data
+func compareFixture() (a, b *models.Build) {
+	a = &models.Build{
+		ID:          "a",
+		Duration:    4,
+		Performance: models.Performance{CompileTime: 3, LinkTime: 1},
+		Metrics:     models.BuildMetrics{OutputSize: 2048},
+	}
+	b = &models.Build{
+		ID:       "b",
+		Duration: 3,
+		Metrics:  models.BuildMetrics{OutputSize: 3072},
+	}
+	return a, b
+}
+
+// rows splits the printed table into the fields of each metric row
+func rows(t *testing.T, out string) map[string][]string {
+	t.Helper()
+	rows := make(map[string][]string)
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		for i := 1; i < len(fields); i++ {
+			if fields[i] == "n/a" || strings.ContainsAny(fields[i][:1], "0123456789+-") {
+				rows[strings.Join(fields[:i], " ")] = fields[i:]
+				break
+			}
+		}
+	}
+	return rows
+}
+
+func TestPrintDiff(t *testing.T) {
+	a, b := compareFixture()
+
+	var out strings.Builder
+	if err := writeDiff(&out, diffBuilds(a, b), "display"); err != nil {
+		t.Fatal(err)
+	}
+	got := rows(t, out.String())
+
+	tests := []struct {
+		metric string
+		want   string
+	}{
+		// Deltas are signed and percentages relative to A
+		{"Duration", "4.00s 3.00s -1.00s -25.0%"},
+		{"Output Size", "2.0KiB 3.0KiB +1.0KiB +50.0%"},
+		// B has no performance data, so there is nothing to compare
+		{"Compile Time", "3.00s n/a"},
+		{"Link Time", "1.00s n/a"},
+		// Neither build recorded these
+		{"Max Memory", "n/a n/a"},
+		{"Resource Efficiency", "n/a n/a"},
+		// No change, and no percentage from zero
+		{"Remarks", "0 0 +0"},
+	}
+	for _, tt := range tests {
+		if fields := strings.Join(got[tt.metric], " "); fields != tt.want {
+			t.Errorf("%s: printed %q, want %q\n%s", tt.metric, fields, tt.want, out.String())
+		}
+	}
+}
+
+func TestWriteDiffJSON(t *testing.T) {
+	a, b := compareFixture()
+
+	var out strings.Builder
+	if err := writeDiff(&out, diffBuilds(a, b), "json"); err != nil {
+		t.Fatal(err)
+	}
+
+	var diff struct {
+		A, B    string
+		Metrics []struct {
+			Name                 string
+			Unit                 string
+			A, B, Delta, Percent *float64
+		}
+	}
+	if err := json.Unmarshal([]byte(out.String()), &diff); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+	if diff.A != "a" || diff.B != "b" {
+		t.Errorf("compared %s with %s", diff.A, diff.B)
+	}
+
+	metrics := make(map[string]int)
+	for i, metric := range diff.Metrics {
+		metrics[metric.Name] = i
+	}
+	duration := diff.Metrics[metrics["Duration"]]
+	if duration.Unit != unitSeconds || *duration.A != 4 || *duration.B != 3 || *duration.Delta != -1 || *duration.Percent != -25 {
+		t.Errorf("duration %+v, want 4s to 3s, -1s and -25%%", duration)
+	}
+	// Missing values are null rather than zero
+	compile := diff.Metrics[metrics["Compile Time"]]
+	if compile.A == nil || compile.B != nil || compile.Delta != nil || compile.Percent != nil {
+		t.Errorf("compile time %+v, want only A", compile)
+	}
+	if !strings.Contains(out.String(), `"b": null`) {
+		t.Errorf("missing values not encoded as null:\n%s", out.String())
+	}
+}
@@ -0,0 +1,148 @@
+// cmd/buildsctl/doctor.go
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"text/tabwriter"
+	"time"
+
+	buildv1 "builds/api/build"
+	buildsclient "builds/internal/client"
+	"builds/internal/collectors/compiler"
+	"builds/pkg/config"
+)
+
+// Outcomes of a doctor check. Skipped checks cover optional tools, such as
+// the GPU utilities, and checks that depend on one that failed.
+const (
+	checkPassed  = "ok"
+	checkFailed  = "FAIL"
+	checkSkipped = "skip"
+)
+
+type checkResult struct {
+	name   string
+	status string
+	detail string
+}
+
+// runDoctor checks the local environment for the problems that most often
+// stop collection and prints a checklist. It returns false if any check
+// failed.
+func runDoctor(args []string) bool {
+	fs := flag.NewFlagSet("doctor", flag.ExitOnError)
+	configPath := fs.String("config", "", "Configuration file to validate")
+	compilerName := fs.String("compiler", "", "Compiler to check (default: the configured default compiler)")
+	fs.Parse(args)
+
+	cfg, result := checkConfig(*configPath)
+	results := []checkResult{result}
+	results = append(results, checkServer())
+
+	name := *compilerName
+	if name == "" {
+		name = cfg.DefaultCompiler
+	}
+	result = checkCompiler(cfg.CompilerPath(name))
+	results = append(results, result)
+	if result.status == checkPassed {
+		results = append(results, checkOptimizationRecord(cfg.CompilerPath(name)))
+	} else {
+		results = append(results, checkResult{"Optimization records", checkSkipped, "no usable compiler"})
+	}
+
+	results = append(results, checkTool("nvidia-smi", "NVIDIA GPU details"))
+	results = append(results, checkTool("rocm-smi", "AMD GPU details"))
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	healthy := true
+	for _, result := range results {
+		fmt.Fprintf(w, "[%s]\t%s\t%s\n", result.status, result.name, result.detail)
+		if result.status == checkFailed {
+			healthy = false
+		}
+	}
+	w.Flush()
+	return healthy
+}
+
+// checkConfig loads and validates the configuration file. Without one the
+// defaults are used.
+func checkConfig(path string) (*config.Config, checkResult) {
+	if path == "" {
+		return config.DefaultConfig(), checkResult{"Config", checkSkipped, "no -config given, using defaults"}
+	}
+	cfg, err := config.LoadConfig(path)
+	if err != nil {
+		return cfg, checkResult{"Config", checkFailed, fmt.Sprintf("cannot load %s: %v", path, err)}
+	}
+	if err := cfg.Validate(); err != nil {
+		return config.DefaultConfig(), checkResult{"Config", checkFailed, fmt.Sprintf("%s: %v", path, err)}
+	}
+	return cfg, checkResult{"Config", checkPassed, path}
+}
+
+// checkServer connects to the server and makes a cheap authenticated call
+func checkServer() checkResult {
+	client, err := buildsclient.New(buildsclient.Options{
+		Address:     *serverAddr,
+		TLS:         *useTLS,
+		Token:       *token,
+		MaxAttempts: 1,
+	})
+	if err != nil {
+		return checkResult{"Server", checkFailed, err.Error()}
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	count, err := client.Count(ctx, &buildv1.CountBuildsRequest{})
+	if err != nil {
+		return checkResult{"Server", checkFailed, fmt.Sprintf("%s: %v", *serverAddr, err)}
+	}
+	return checkResult{"Server", checkPassed, fmt.Sprintf("%s, %d builds stored", *serverAddr, count)}
+}
+
+// checkCompiler verifies the compiler is installed and of a kind the
+// collectors understand
+func checkCompiler(command string) checkResult {
+	path, err := exec.LookPath(command)
+	if err != nil {
+		return checkResult{"Compiler", checkFailed, fmt.Sprintf("%s not found in PATH", command)}
+	}
+	driver := compiler.Driver(path)
+	if driver == "unknown" {
+		return checkResult{"Compiler", checkFailed, fmt.Sprintf("%s is not a supported compiler (clang, gcc or cl.exe)", path)}
+	}
+	return checkResult{"Compiler", checkPassed, fmt.Sprintf("%s (%s)", path, driver)}
+}
+
+// checkOptimizationRecord verifies the compiler can write the optimization
+// records remarks are read from
+func checkOptimizationRecord(command string) checkResult {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := compiler.CheckOptimizationRecord(ctx, command); err != nil {
+		return checkResult{"Optimization records", checkFailed, err.Error()}
+	}
+	if compiler.Driver(command) == "msvc" {
+		return checkResult{"Optimization records", checkPassed, "cl.exe reports through /Qvec-report"}
+	}
+	return checkResult{"Optimization records", checkPassed, "-fsave-optimization-record works"}
+}
+
+// checkTool looks for an optional helper program. Its absence only means
+// the details it provides are not collected.
+func checkTool(name, provides string) checkResult {
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return checkResult{name, checkSkipped, fmt.Sprintf("not installed, %s are not collected", provides)}
+	}
+	return checkResult{name, checkPassed, path}
+}
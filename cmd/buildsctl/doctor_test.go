@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+
+	buildv1 "builds/api/build"
+)
+
+// fakeTools puts programs that run the given scripts at the front of PATH
+func fakeTools(t *testing.T, scripts map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, script := range scripts {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return dir
+}
+
+// writeRecord makes a fake compiler write the record named by
+// -foptimization-record-file
+const writeRecord = `for arg; do
+	case "$arg" in -foptimization-record-file=*) : > "${arg#-foptimization-record-file=}" ;; esac
+done`
+
+func TestCheckCompiler(t *testing.T) {
+	fakeTools(t, map[string]string{
+		"gcc":     "exit 0",
+		"mycc":    `echo "clang version 18.1.0"`,
+		"strange": `echo "Strange C 1.0"`,
+	})
+
+	tests := []struct {
+		command string
+		want    string
+	}{
+		{"gcc", checkPassed},
+		{"mycc", checkPassed},
+		{"strange", checkFailed},
+		{"missing-cc", checkFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			if got := checkCompiler(tt.command); got.status != tt.want {
+				t.Errorf("status %s (%s), want %s", got.status, got.detail, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckOptimizationRecord(t *testing.T) {
+	fakeTools(t, map[string]string{
+		"clang":      writeRecord,
+		"old-clang":  `echo "error: unknown argument: '-fsave-optimization-record'" >&2; exit 1`,
+		"lazy-clang": "exit 0",
+	})
+
+	tests := []struct {
+		command    string
+		want       string
+		wantDetail string
+	}{
+		{"clang", checkPassed, "-fsave-optimization-record works"},
+		{"old-clang", checkFailed, "unknown argument"},
+		{"lazy-clang", checkFailed, "wrote no record"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			got := checkOptimizationRecord(tt.command)
+			if got.status != tt.want || !strings.Contains(got.detail, tt.wantDetail) {
+				t.Errorf("got %s (%s), want %s (%s)", got.status, got.detail, tt.want, tt.wantDetail)
+			}
+		})
+	}
+}
+
+func TestCheckTool(t *testing.T) {
+	fakeTools(t, map[string]string{"nvidia-smi": "exit 0"})
+
+	if got := checkTool("nvidia-smi", "NVIDIA GPU details"); got.status != checkPassed {
+		t.Errorf("installed tool: %s (%s)", got.status, got.detail)
+	}
+	// A missing GPU tool is not a failure
+	if got := checkTool("missing-smi", "GPU details"); got.status != checkSkipped {
+		t.Errorf("missing tool: %s (%s)", got.status, got.detail)
+	}
+}
+
+func TestCheckConfig(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{"no config", "", checkSkipped},
+		{"valid", write("valid.json", `{"defaultCompiler": "clang", "outputFormat": "text"}`), checkPassed},
+		{"invalid", write("invalid.json", `{"defaultCompiler": "", "outputFormat": "text"}`), checkFailed},
+		{"malformed", write("malformed.json", `{`), checkFailed},
+		{"missing", filepath.Join(dir, "missing.json"), checkFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, got := checkConfig(tt.path)
+			if got.status != tt.want {
+				t.Errorf("status %s (%s), want %s", got.status, got.detail, tt.want)
+			}
+			// The other checks always get a usable configuration
+			if cfg == nil || cfg.DefaultCompiler == "" {
+				t.Errorf("config %+v", cfg)
+			}
+		})
+	}
+}
+
+// countServer answers CountBuilds with a fixed count
+type countServer struct {
+	buildv1.UnimplementedBuildServiceServer
+}
+
+func (countServer) CountBuilds(ctx context.Context, req *buildv1.CountBuildsRequest) (*buildv1.CountBuildsResponse, error) {
+	return &buildv1.CountBuildsResponse{Count: 3}, nil
+}
+
+func TestCheckServer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer()
+	buildv1.RegisterBuildServiceServer(server, countServer{})
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	defer func(addr string) { *serverAddr = addr }(*serverAddr)
+
+	*serverAddr = listener.Addr().String()
+	if got := checkServer(); got.status != checkPassed || !strings.Contains(got.detail, "3 builds") {
+		t.Errorf("reachable server: %s (%s)", got.status, got.detail)
+	}
+
+	if testing.Short() {
+		t.Skip("waits for the dial to time out")
+	}
+
+	// Nothing listens on a closed port
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	*serverAddr = closed.Addr().String()
+	closed.Close()
+	if got := checkServer(); got.status != checkFailed {
+		t.Errorf("unreachable server: %s (%s)", got.status, got.detail)
+	}
+}
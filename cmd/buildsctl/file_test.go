@@ -0,0 +1,73 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	buildv1 "builds/api/build"
+	buildsclient "builds/internal/client"
+)
+
+func TestInspectBuildFile(t *testing.T) {
+	exported := &buildv1.Build{
+		Id:       "b1",
+		Success:  true,
+		Duration: 2.5,
+		Remarks: []*buildv1.CompilerRemark{{
+			Id:       "r1",
+			Status:   buildv1.CompilerRemark_MISSED,
+			Message:  "loop not vectorized",
+			Location: &buildv1.Location{File: "foo.c", Line: 3, Column: 5},
+			KernelInfo: &buildv1.KernelInfo{
+				ThreadLimit: 256,
+				Metrics:     map[string]int64{"spill_stores": 3},
+			},
+		}},
+	}
+
+	path := filepath.Join(t.TempDir(), "build.json")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := buildsclient.ExportBuild(file, exported); err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	build, err := buildsclient.ReadBuildFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(build, exported) {
+		t.Errorf("read back %v, want %v", build, exported)
+	}
+
+	var out strings.Builder
+	printInspection(&out, build)
+	for _, want := range []string{"Build b1", "Compiler Remarks (1 remarks)", "r1", "foo.c:3:5"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("inspection lacks %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestReadBuildFileErrors(t *testing.T) {
+	dir := t.TempDir()
+	invalid := filepath.Join(dir, "invalid.json")
+	if err := os.WriteFile(invalid, []byte(`{"id": 3}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{invalid, filepath.Join(dir, "missing.json")} {
+		if build, err := buildsclient.ReadBuildFile(path); err == nil {
+			t.Errorf("ReadBuildFile(%s) = %v, want an error", filepath.Base(path), build)
+		}
+	}
+}
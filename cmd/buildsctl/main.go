@@ -4,29 +4,45 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"os/signal"
+	"sort"
 	"strings"
 	"text/tabwriter"
 	"time"
 
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
 	buildv1 "builds/api/build"
 	"builds/internal/analysis/performance"
-	"builds/internal/models"
+	buildsclient "builds/internal/client"
 	"builds/internal/reporters"
-
-	grpcutil "builds/internal/utils/grpcutil"
+	"builds/internal/reporters/template"
+	"builds/internal/utils/units"
 )
 
 var (
 	serverAddr = flag.String("server", "localhost:50051", "The server address")
-	format     = flag.String("format", "display", "Output format (display, text, json)")
+	format     = flag.String("format", "display", "Output format (display, text, json, yaml, csv, html, markdown, sarif, template)")
+	tmplFile   = flag.String("template-file", "", "text/template file rendered by -format template")
+	outDir     = flag.String("out", "", "Write text, json, yaml and csv reports to this directory instead of stdout")
+	outPrefix  = flag.String("output-prefix", "", "Template for report file names, e.g. \"{{.Compiler}}-{{.Timestamp}}\" (default \"build-{{.ID}}\")")
+	explain    = flag.Bool("explain", false, "Show the figures and thresholds behind each bottleneck")
+	allRemarks = flag.Bool("all-remarks", false, "Include analysis and informational remarks in display, text, html and markdown reports")
+	precision  = flag.Int("precision", 2, "Decimals shown for durations in display and text reports (1-9)")
 	watch      = flag.Bool("watch", false, "Watch for new builds")
 	useTLS     = flag.Bool("tls", false, "Use TLS when connecting to server")
+	token      = flag.String("token", os.Getenv("BUILDS_TOKEN"), "Authentication token sent to the server")
 	version    = flag.Bool("version", false, "Show version information")
 	verbose    = flag.Bool("verbose", false, "Enable verbose output")
+	buildFile  = flag.String("file", "", "Read the build from an exported JSON file instead of the server")
+	parallel   = flag.Int("parallel-upload", 4, "Builds uploaded at once by import")
 )
 
 const buildVersion = "0.1.0"
@@ -39,13 +55,54 @@ func main() {
 		return
 	}
 
-	conn, err := grpcutil.CreateGRPCConnection(*serverAddr, *useTLS)
+	if *precision < 1 || *precision > 9 {
+		log.Fatalf("Invalid -precision %d: must be between 1 and 9", *precision)
+	}
+
+	// Reject a broken template before contacting the server
+	if *format == "template" {
+		if *tmplFile == "" {
+			log.Fatal("-format template requires -template-file")
+		}
+		if _, err := template.Parse(*tmplFile); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Inspect an exported build offline, without contacting a server
+	if *buildFile != "" {
+		build, err := buildsclient.ReadBuildFile(*buildFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		switch command := flag.Arg(0); command {
+		case "", "get":
+			reportBuild(build)
+		case "inspect":
+			printInspection(os.Stdout, build)
+		default:
+			log.Fatalf("Command %s does not support -file", command)
+		}
+		return
+	}
+
+	// The doctor reports an unreachable server rather than exiting on it
+	if flag.Arg(0) == "doctor" {
+		if !runDoctor(flag.Args()[1:]) {
+			os.Exit(1)
+		}
+		return
+	}
+
+	client, err := buildsclient.New(buildsclient.Options{
+		Address: *serverAddr,
+		TLS:     *useTLS,
+		Token:   *token,
+	})
 	if err != nil {
 		log.Fatalf("Failed to connect: %v", err)
 	}
-	defer conn.Close()
-
-	client := buildv1.NewBuildServiceClient(conn)
+	defer client.Close()
 
 	if *watch {
 		watchBuilds(client)
@@ -69,7 +126,16 @@ func main() {
 		getBuild(ctx, client, args[1])
 
 	case "list":
-		listBuilds(ctx, client)
+		listBuilds(ctx, client, args[1:])
+
+	case "compare":
+		if len(args) < 3 {
+			log.Fatal("Two build IDs required")
+		}
+		compareBuilds(ctx, client, args[1], args[2])
+
+	case "size-diff":
+		sizeDiffBuilds(ctx, client, args[1:])
 
 	case "delete":
 		if len(args) < 2 {
@@ -77,12 +143,90 @@ func main() {
 		}
 		deleteBuild(ctx, client, args[1])
 
+	case "undelete":
+		if len(args) < 2 {
+			log.Fatal("Build ID required")
+		}
+		if _, err := client.Undelete(ctx, args[1]); err != nil {
+			log.Fatalf("Failed to undelete build: %v", err)
+		}
+		fmt.Printf("Build %s restored\n", args[1])
+
 	case "inspect":
 		if len(args) < 2 {
 			log.Fatal("Build ID required")
 		}
 		inspectBuild(ctx, client, args[1])
 
+	case "summary":
+		printSummary(ctx, client)
+
+	case "count":
+		countBuilds(ctx, client, args[1:])
+
+	case "remark-history":
+		remarkHistory(ctx, client, args[1:])
+
+	case "import":
+		if len(args) < 2 {
+			log.Fatal("Build files required")
+		}
+		importBuilds(client, args[1:])
+
+	case "export":
+		if len(args) < 2 {
+			log.Fatal("Build ID required")
+		}
+		build, err := client.Get(ctx, args[1])
+		if err != nil {
+			log.Fatalf("Failed to get build: %v", err)
+		}
+		if err := buildsclient.ExportBuild(os.Stdout, build); err != nil {
+			log.Fatalf("Failed to export build: %v", err)
+		}
+
+	case "remark":
+		if len(args) < 3 {
+			log.Fatal("Build ID and remark ID required")
+		}
+		printRemark(ctx, client, args[1], args[2])
+
+	case "tail-remarks":
+		if len(args) < 2 {
+			log.Fatal("Build ID required")
+		}
+		// Tailing a long build outlasts the command timeout; stop on Ctrl-C
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		err := client.TailRemarks(ctx, args[1], func(remark *buildv1.CompilerRemark) error {
+			fmt.Println(formatRemarkLine(remark))
+			return nil
+		})
+		if err != nil && ctx.Err() == nil {
+			log.Fatalf("Failed to stream remarks: %v", err)
+		}
+
+	case "purge-remarks":
+		if len(args) < 2 {
+			log.Fatal("Build ID required")
+		}
+		purged, err := client.PurgeRemarks(ctx, args[1])
+		if err != nil {
+			log.Fatalf("Failed to purge remarks: %v", err)
+		}
+		fmt.Printf("Purged %d remarks from build %s\n", purged, args[1])
+
+	case "get-remarks-raw":
+		if len(args) < 2 {
+			log.Fatal("Build ID required")
+		}
+		// Copying a large record can take longer than other commands
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		if err := client.RawRemarks(ctx, args[1], os.Stdout); err != nil {
+			log.Fatalf("Failed to get raw remarks: %v", err)
+		}
+
 	default:
 		fmt.Printf("Unknown command: %s\n", args[0])
 		printUsage()
@@ -90,28 +234,38 @@ func main() {
 	}
 }
 
-func getBuild(ctx context.Context, client buildv1.BuildServiceClient, id string) {
-	build, err := client.GetBuild(ctx, &buildv1.GetBuildRequest{Id: id})
+func getBuild(ctx context.Context, client *buildsclient.Client, id string) {
+	build, err := client.Get(ctx, id)
 	if err != nil {
 		log.Fatalf("Failed to get build: %v", err)
 	}
+	reportBuild(build)
+}
 
+// reportBuild analyzes a build and renders it in the selected format
+func reportBuild(build *buildv1.Build) {
 	// Convert proto build to internal model
-	modelBuild := convertProtoToModel(build)
+	modelBuild := buildsclient.BuildToModel(build)
 
 	// Run analysis
-	analyzer := performance.NewAnalyzer(modelBuild)
-	analysisResult, err := analyzer.Analyze()
+	analysisResult, err := performance.Analyze(modelBuild)
 	if err != nil {
 		log.Printf("Warning: analysis failed: %v", err)
 	}
 
 	// Create reporter options
 	opts := reporters.Options{
-		Format:   *format,
-		Build:    modelBuild,
-		Analysis: analysisResult,
-		Writer:   os.Stdout,
+		OutputDir: *outDir,
+		Format:    *format,
+		Build:     modelBuild,
+		Analysis:  analysisResult,
+		Writer:    os.Stdout,
+		Explain:   *explain,
+		Precision: *precision,
+
+		AllRemarks:   *allRemarks,
+		FilePrefix:   *outPrefix,
+		TemplateFile: *tmplFile,
 	}
 
 	// Create and use reporter
@@ -125,19 +279,139 @@ func getBuild(ctx context.Context, client buildv1.BuildServiceClient, id string)
 	}
 }
 
-func listBuilds(ctx context.Context, client buildv1.BuildServiceClient) {
-	resp, err := client.ListBuilds(ctx, &buildv1.ListBuildsRequest{
+func listBuilds(ctx context.Context, client *buildsclient.Client, args []string) {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	var labels []string
+	fs.Func("label", "Only list builds with this label, as key:value (repeatable)", func(value string) error {
+		if key, _, ok := strings.Cut(value, ":"); !ok || key == "" {
+			return fmt.Errorf("label must be key:value, got %q", value)
+		}
+		labels = append(labels, "label="+value)
+		return nil
+	})
+	arch := fs.String("arch", "", "Only list builds targeting this architecture, such as aarch64")
+	command := fs.String("command", "", "Only list runs of the compile with this command hash (prefix)")
+	success := fs.Bool("success", false, "Only list successful builds")
+	failed := fs.Bool("failed", false, "Only list failed builds")
+	compilerName := fs.String("compiler", "", "Only list builds using this compiler")
+	var since units.Duration
+	fs.Var(&since, "since", "Only list builds stored within this long, e.g. 24h")
+	all := fs.Bool("all", false, "List every matching build, not only the newest 50")
+	fs.Parse(args)
+
+	if *failed && *success {
+		log.Fatal("-failed and -success are mutually exclusive")
+	}
+
+	terms := labels
+	if *arch != "" {
+		terms = append(terms, "arch="+*arch)
+	}
+	if *command != "" {
+		terms = append(terms, "command="+*command)
+	}
+	req := &buildv1.ListBuildsRequest{
 		PageSize: 50,
+		Filter:   strings.Join(terms, " "),
+		Compiler: *compilerName,
+	}
+	switch {
+	case *failed:
+		req.Outcome = buildv1.ListBuildsRequest_OUTCOME_FAILED
+	case *success:
+		req.Outcome = buildv1.ListBuildsRequest_OUTCOME_SUCCEEDED
+	}
+	if since > 0 {
+		req.Since = timestamppb.New(time.Now().Add(-time.Duration(since)))
+	}
+	if *all {
+		// Walking every page can outlast the timeout of a single command
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(context.Background())
+		defer cancel()
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "BUILD ID\tSTATUS\tSTART TIME\tDURATION\tCOMPILER\tCOMMAND\tLABELS\n")
+
+	listed := 0
+	next, err := client.Search(ctx, req, *all, func(builds []*buildv1.Build) error {
+		for _, build := range builds {
+			status := "Failed"
+			if build.Success {
+				status = "Success"
+			}
+
+			compilerName := "unknown"
+			if build.Compiler != nil {
+				compilerName = build.Compiler.Name
+			}
+
+			startTime := "N/A"
+			if build.StartTime != nil {
+				startTime = build.StartTime.AsTime().Format(time.RFC3339)
+			}
+
+			fmt.Fprintf(w, "%s\t%s\t%s\t%.2fs\t%s\t%s\t%s\n",
+				build.Id,
+				status,
+				startTime,
+				build.Duration,
+				compilerName,
+				shortHash(build.Command.GetHash()),
+				formatLabels(build.Labels),
+			)
+		}
+		listed += len(builds)
+		return nil
 	})
+	w.Flush()
 	if err != nil {
 		log.Fatalf("Failed to list builds: %v", err)
 	}
 
+	if listed == 0 {
+		fmt.Println("No builds found")
+	} else if next != "" {
+		fmt.Fprintf(os.Stderr, "More builds match; use -all to list them all\n")
+	}
+}
+
+// shortHash abbreviates a command hash for listings; list -command accepts
+// the prefix it prints
+func shortHash(hash string) string {
+	if hash == "" {
+		return "-"
+	}
+	return hash[:min(len(hash), 12)]
+}
+
+// formatLabels renders labels as sorted key=value pairs
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return "-"
+	}
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func deleteBuild(ctx context.Context, client *buildsclient.Client, id string) {
+	if err := client.Delete(ctx, id); err != nil {
+		log.Fatalf("Failed to delete build: %v", err)
+	}
+	fmt.Printf("Build %s deleted successfully\n", id)
+}
+
+func watchBuilds(client *buildsclient.Client) {
+	fmt.Println("Watching for new builds...")
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 	defer w.Flush()
 
-	fmt.Fprintf(w, "BUILD ID\tSTATUS\tSTART TIME\tDURATION\tCOMPILER\n")
-	for _, build := range resp.Builds {
+	err := client.Watch(context.Background(), "", func(build *buildv1.Build) error {
 		status := "Failed"
 		if build.Success {
 			status = "Success"
@@ -160,62 +434,169 @@ func listBuilds(ctx context.Context, client buildv1.BuildServiceClient) {
 			build.Duration,
 			compilerName,
 		)
-	}
-
-	if len(resp.Builds) == 0 {
-		fmt.Println("No builds found")
+		return w.Flush()
+	})
+	if err != nil {
+		log.Fatalf("Stream error: %v", err)
 	}
 }
 
-func deleteBuild(ctx context.Context, client buildv1.BuildServiceClient, id string) {
-	_, err := client.DeleteBuild(ctx, &buildv1.DeleteBuildRequest{Id: id})
+func printSummary(ctx context.Context, client *buildsclient.Client) {
+	summary, err := client.Summary(ctx, 5)
 	if err != nil {
-		log.Fatalf("Failed to delete build: %v", err)
+		log.Fatalf("Failed to get summary: %v", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintf(w, "Total builds:\t%d\n", summary.TotalBuilds)
+	fmt.Fprintf(w, "Last 24h:\t%d\n", summary.BuildsLastDay)
+	fmt.Fprintf(w, "Last 7d:\t%d\n", summary.BuildsLastWeek)
+	fmt.Fprintf(w, "Success rate (7d):\t%.1f%%\n", summary.RecentSuccessRate*100)
+
+	if len(summary.TopCompilers) > 0 {
+		fmt.Fprintf(w, "\nTop compilers:\n")
+		for _, c := range summary.TopCompilers {
+			fmt.Fprintf(w, "  %s\t%d\n", c.Name, c.Builds)
+		}
+	}
+
+	if slowest := summary.SlowestRecentBuild; slowest != nil {
+		compilerName := "unknown"
+		if slowest.Compiler != nil && slowest.Compiler.Name != "" {
+			compilerName = slowest.Compiler.Name
+		}
+		fmt.Fprintf(w, "\nSlowest build (7d):\t%s (%.2fs, %s)\n", slowest.Id, slowest.Duration, compilerName)
 	}
-	fmt.Printf("Build %s deleted successfully\n", id)
 }
 
-func watchBuilds(client buildv1.BuildServiceClient) {
-	ctx := context.Background()
-	stream, err := client.StreamBuilds(ctx, &buildv1.StreamBuildsRequest{})
+// remarkHistory prints the first and last builds of a project in which a
+// remark appeared
+func remarkHistory(ctx context.Context, client *buildsclient.Client, args []string) {
+	fs := flag.NewFlagSet("remark-history", flag.ExitOnError)
+	project := fs.String("project", "", "Only consider builds labelled project=<value>")
+	label := fs.String("label", "", "Only consider builds with this label, as key:value")
+	name := fs.String("name", "", "Remark name, e.g. NotVectorized (required)")
+	function := fs.String("function", "", "Function the remark is reported for")
+	pass := fs.String("pass", "", "Pass reporting the remark")
+	fs.Parse(args)
+
+	if *name == "" {
+		log.Fatal("-name is required")
+	}
+
+	req := &buildv1.RemarkHistoryRequest{Name: *name, Function: *function, Pass: *pass}
+	switch {
+	case *label != "":
+		key, value, ok := strings.Cut(*label, ":")
+		if !ok || key == "" {
+			log.Fatalf("label must be key:value, got %q", *label)
+		}
+		req.LabelKey, req.LabelValue = key, value
+	case *project != "":
+		req.LabelKey, req.LabelValue = "project", *project
+	}
+
+	history, err := client.RemarkHistory(ctx, req)
 	if err != nil {
-		log.Fatalf("Failed to watch builds: %v", err)
+		log.Fatalf("Failed to get remark history: %v", err)
+	}
+	if history.Builds == 0 {
+		fmt.Println("Remark not found in any build")
+		return
+	}
+
+	formatRef := func(ref *buildv1.BuildRef) string {
+		if ref == nil {
+			return "N/A"
+		}
+		return fmt.Sprintf("%s (%s)", ref.Id, ref.CreatedAt.AsTime().Format(time.RFC3339))
 	}
 
-	fmt.Println("Watching for new builds...")
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 	defer w.Flush()
+	fmt.Fprintf(w, "Builds with remark:\t%d\n", history.Builds)
+	fmt.Fprintf(w, "First seen:\t%s\n", formatRef(history.First))
+	fmt.Fprintf(w, "Last seen:\t%s\n", formatRef(history.Last))
+	fmt.Fprintf(w, "Latest build:\t%s\n", formatRef(history.Latest))
+	fmt.Fprintf(w, "In latest build:\t%v\n", history.InLatest)
+}
 
-	for {
-		build, err := stream.Recv()
+// importBuilds uploads exported build files over a single stream and
+// reports the outcome of each
+func importBuilds(client *buildsclient.Client, paths []string) {
+	builds := make([]*buildv1.Build, 0, len(paths))
+	for _, path := range paths {
+		build, err := buildsclient.ReadBuildFile(path)
 		if err != nil {
-			log.Fatalf("Stream error: %v", err)
+			log.Fatal(err)
 		}
+		builds = append(builds, build)
+	}
 
-		status := "Failed"
-		if build.Success {
-			status = "Success"
-		}
+	// The per-command timeout is too short for a large batch
+	results, err := client.CreateBatch(context.Background(), builds, *parallel)
+	if err != nil {
+		log.Printf("Upload stream failed: %v", err)
+	}
 
-		compilerName := "unknown"
-		if build.Compiler != nil {
-			compilerName = build.Compiler.Name
+	failed := 0
+	for i, result := range results {
+		if result.Err != nil {
+			failed++
+			fmt.Printf("FAIL\t%s\t%s: %v\n", paths[i], result.ID, result.Err)
+			continue
 		}
+		fmt.Printf("OK\t%s\t%s\n", paths[i], result.ID)
+	}
+	fmt.Printf("Imported %d of %d builds\n", len(results)-failed, len(results))
+	if failed > 0 || err != nil {
+		os.Exit(1)
+	}
+}
 
-		startTime := "N/A"
-		if build.StartTime != nil {
-			startTime = build.StartTime.AsTime().Format(time.RFC3339)
-		}
+// countBuilds prints the number of builds matching the filter flags in args
+func countBuilds(ctx context.Context, client *buildsclient.Client, args []string) {
+	req, err := countRequest(flag.NewFlagSet("count", flag.ExitOnError), args, time.Now())
+	if err != nil {
+		log.Fatal(err)
+	}
 
-		fmt.Fprintf(w, "%s\t%s\t%s\t%.2fs\t%s\n",
-			build.Id,
-			status,
-			startTime,
-			build.Duration,
-			compilerName,
-		)
-		w.Flush()
+	count, err := client.Count(ctx, req)
+	if err != nil {
+		log.Fatalf("Failed to count builds: %v", err)
 	}
+	fmt.Println(count)
+}
+
+// countRequest parses the count command's filter flags in args onto fs,
+// taking -since relative to now
+func countRequest(fs *flag.FlagSet, args []string, now time.Time) (*buildv1.CountBuildsRequest, error) {
+	failed := fs.Bool("failed", false, "Count only failed builds")
+	success := fs.Bool("success", false, "Count only successful builds")
+	compilerName := fs.String("compiler", "", "Count only builds using this compiler")
+	var since units.Duration
+	fs.Var(&since, "since", "Count only builds stored within this long, e.g. 24h")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if *failed && *success {
+		return nil, errors.New("-failed and -success are mutually exclusive")
+	}
+
+	req := &buildv1.CountBuildsRequest{Compiler: *compilerName}
+	switch {
+	case *failed:
+		req.Outcome = buildv1.CountBuildsRequest_OUTCOME_FAILED
+	case *success:
+		req.Outcome = buildv1.CountBuildsRequest_OUTCOME_SUCCEEDED
+	}
+	if since > 0 {
+		req.Since = timestamppb.New(now.Add(-time.Duration(since)))
+	}
+	return req, nil
 }
 
 func printUsage() {
@@ -223,200 +604,66 @@ func printUsage() {
 
 Commands:
   get <build-id>    Get details of a specific build
-  list              List all builds
+  list [-label key:value]... [-arch name] [-command hash] [-success|-failed] [-compiler name] [-since 24h] [-all]
+                    List the newest 50 builds, or with -all every build, optionally only those matching every filter
+  compare <build-id-a> <build-id-b> Show how build B differs from build A (-format json for a structured diff)
+  size-diff [-threshold pct] <build-id-a> <build-id-b>
+                    Compare output and section sizes, failing when the output grew by more than pct
   delete <build-id> Delete a build
+  undelete <build-id> Restore a deleted build before it is pruned
   inspect <build-id> Inspect a build in detail
+  summary           Show an overview of all stored builds
+  count [-success|-failed] [-compiler name] [-since 24h] Count matching builds
+  get-remarks-raw <build-id> Print the stored optimization record YAML
+  remark <build-id> <remark-id> Print a single remark with all its details
+  tail-remarks <build-id> Follow a build's remarks as they are stored, until it is finalized
+  purge-remarks <build-id> Delete a build's remarks but keep the build
+  remark-history -name <name> [-function f] [-pass p] [-project x] First and last builds with a remark
+  export <build-id> Print a build as JSON for offline inspection with -file
+  import <file>...  Upload exported builds, -parallel-upload at a time
+  doctor [-config file] [-compiler name] Check the server, compiler and tools collection relies on
 
 Options:
   -server string    The server address (default "localhost:50051")
-  -format string    Output format (text, json) (default "text")
+  -token string     Authentication token (default $BUILDS_TOKEN)
+  -format string    Output format (display, text, json, yaml, csv, html, markdown, sarif, template) (default "display")
+  -template-file string text/template for -format template, given .Build and .Analysis
+                    (helpers: formatBytes, seconds, percent, duration, upper, lower, join)
+  -out string       Write reports to this directory instead of stdout
+  -output-prefix string Report file name template ({{.ID}}, {{.Compiler}}, {{.Version}}, {{.Timestamp}}, {{.Date}})
+  -explain          Explain the figures behind each bottleneck
+  -all-remarks      Include analysis and informational remarks, hidden by default
+                    from display, text, html and markdown reports
+  -precision int    Decimals shown for durations, percentages use one fewer (default 2)
+  -file string      Read the build from an exported file (get, inspect) without a server
+  -parallel-upload int Builds uploaded at once by import (default 4)
   -watch           Watch for new builds
   -version         Show version information
 
 Examples:
   %[1]s get abc123                    # Get details of build abc123
   %[1]s list                          # List all builds
+  %[1]s summary                       # Fleet overview
+  %[1]s count -failed -since 24h       # Failed builds in the last day
+  %[1]s remark-history -project X -name NotVectorized -function foo
+  %[1]s -file build.json inspect       # Inspect an exported build offline
+  %[1]s -format sarif get abc123 > remarks.sarif # Remarks for code scanning
   %[1]s -watch                        # Watch for new builds
   %[1]s -server remote:50051 list     # List builds from remote server
 `, os.Args[0], os.Args[0])
 }
 
-func convertProtoToModel(pb *buildv1.Build) *models.Build {
-	if pb == nil {
-		return nil
-	}
-
-	build := &models.Build{
-		ID:      pb.Id,
-		Success: pb.Success,
-		Error:   pb.Error,
-	}
-
-	// Handle timestamps safely
-	if pb.StartTime != nil {
-		build.StartTime = pb.StartTime.AsTime()
-	}
-	if pb.EndTime != nil {
-		build.EndTime = pb.EndTime.AsTime()
-	}
-	build.Duration = pb.Duration
-
-	// Convert Environment
-	if pb.Environment != nil {
-		build.Environment = models.Environment{
-			OS:         pb.Environment.Os,
-			Arch:       pb.Environment.Arch,
-			WorkingDir: pb.Environment.WorkingDir,
-			Variables:  pb.Environment.Variables,
-		}
-	}
-
-	// Convert Hardware
-	if pb.Hardware != nil && pb.Hardware.Cpu != nil && pb.Hardware.Memory != nil {
-		build.Hardware = models.Hardware{
-			CPU: models.CPU{
-				Model:     pb.Hardware.Cpu.Model,
-				Frequency: pb.Hardware.Cpu.Frequency,
-				Cores:     pb.Hardware.Cpu.Cores,
-				Threads:   pb.Hardware.Cpu.Threads,
-				Vendor:    pb.Hardware.Cpu.Vendor,
-				CacheSize: pb.Hardware.Cpu.CacheSize,
-			},
-			Memory: models.Memory{
-				Total:     pb.Hardware.Memory.Total,
-				Available: pb.Hardware.Memory.Available,
-				Used:      pb.Hardware.Memory.Used,
-				SwapTotal: pb.Hardware.Memory.SwapTotal,
-				SwapFree:  pb.Hardware.Memory.SwapFree,
-			},
-		}
-
-		// Handle GPUs safely
-		if pb.Hardware.Gpus != nil {
-			build.Hardware.GPUs = make([]models.GPU, len(pb.Hardware.Gpus))
-			for i, gpu := range pb.Hardware.Gpus {
-				if gpu != nil {
-					build.Hardware.GPUs[i] = models.GPU{
-						Model:       gpu.Model,
-						Memory:      gpu.Memory,
-						Driver:      gpu.Driver,
-						ComputeCaps: gpu.ComputeCaps,
-					}
-				}
-			}
-		}
-	}
-
-	// Convert Remarks
-	if pb.Remarks != nil {
-		build.Remarks = make([]models.CompilerRemark, 0, len(pb.Remarks))
-		for _, remark := range pb.Remarks {
-			if remark == nil {
-				continue
-			}
-
-			modelRemark := models.CompilerRemark{
-				Type:     strings.ToLower(remark.Type.String()),
-				Pass:     strings.ToLower(remark.Pass.String()),
-				Status:   strings.ToLower(remark.Status.String()),
-				Message:  remark.Message,
-				Function: remark.Function,
-				Hotness:  remark.Hotness,
-			}
-
-			if remark.Timestamp != nil {
-				modelRemark.Timestamp = remark.Timestamp.AsTime()
-			}
-
-			// Handle Location
-			if remark.Location != nil {
-				modelRemark.Location = models.Location{
-					File:     remark.Location.File,
-					Line:     remark.Location.Line,
-					Column:   remark.Location.Column,
-					Function: remark.Location.Function,
-					Region:   remark.Location.Region,
-				}
-			}
-
-			// Handle KernelInfo
-			if remark.KernelInfo != nil {
-				modelRemark.KernelInfo = &models.KernelInfo{
-					ThreadLimit:              remark.KernelInfo.ThreadLimit,
-					MaxThreadsX:              remark.KernelInfo.MaxThreadsX,
-					MaxThreadsY:              remark.KernelInfo.MaxThreadsY,
-					MaxThreadsZ:              remark.KernelInfo.MaxThreadsZ,
-					SharedMemory:             remark.KernelInfo.SharedMemory,
-					Target:                   remark.KernelInfo.Target,
-					DirectCalls:              remark.KernelInfo.DirectCalls,
-					IndirectCalls:            remark.KernelInfo.IndirectCalls,
-					Callees:                  remark.KernelInfo.Callees,
-					AllocasCount:             remark.KernelInfo.AllocasCount,
-					AllocasStaticSize:        remark.KernelInfo.AllocasStaticSize,
-					AllocasDynamicCount:      remark.KernelInfo.AllocasDynamicCount,
-					FlatAddressSpaceAccesses: remark.KernelInfo.FlatAddressSpaceAccesses,
-					InlineAssemblyCalls:      remark.KernelInfo.InlineAssemblyCalls,
-					Metrics:                  make(map[string]int64),
-					Attributes:               make(map[string]string),
-				}
-
-				// Copy metrics
-				if remark.KernelInfo.Metrics != nil {
-					for k, v := range remark.KernelInfo.Metrics {
-						modelRemark.KernelInfo.Metrics[k] = v
-					}
-				}
-
-				// Copy attributes
-				if remark.KernelInfo.Attributes != nil {
-					for k, v := range remark.KernelInfo.Attributes {
-						modelRemark.KernelInfo.Attributes[k] = v
-					}
-				}
-
-				// Handle memory accesses
-				if remark.KernelInfo.MemoryAccesses != nil {
-					modelRemark.KernelInfo.MemoryAccesses = make([]models.MemoryAccess, len(remark.KernelInfo.MemoryAccesses))
-					for i, acc := range remark.KernelInfo.MemoryAccesses {
-						if acc != nil {
-							modelRemark.KernelInfo.MemoryAccesses[i] = models.MemoryAccess{
-								Type:          acc.Type,
-								AddressSpace:  acc.AddressSpace,
-								Instruction:   acc.Instruction,
-								Variable:      acc.Variable,
-								AccessPattern: acc.AccessPattern,
-							}
-						}
-					}
-				}
-			}
-
-			// Handle metadata
-			if remark.Metadata != nil {
-				modelRemark.Metadata = remark.Metadata.AsMap()
-			}
-
-			// Handle args
-			if remark.Args != nil {
-				modelRemark.Args.Strings = remark.Args.Strings
-				modelRemark.Args.Values = make(map[string]string)
-			}
-
-			build.Remarks = append(build.Remarks, modelRemark)
-		}
-	}
-
-	return build
-}
-
-func inspectBuild(ctx context.Context, client buildv1.BuildServiceClient, id string) {
-	build, err := client.GetBuild(ctx, &buildv1.GetBuildRequest{Id: id})
+func inspectBuild(ctx context.Context, client *buildsclient.Client, id string) {
+	build, err := client.Get(ctx, id)
 	if err != nil {
 		log.Fatalf("Failed to get build: %v", err)
 	}
+	printInspection(os.Stdout, build)
+}
 
+func printInspection(out io.Writer, build *buildv1.Build) {
 	// Create a detailed inspection report
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
 	defer w.Flush()
 
 	fmt.Fprintf(w, "Database Inspection for Build %s\n", build.Id)
@@ -471,8 +718,14 @@ func inspectBuild(ctx context.Context, client buildv1.BuildServiceClient, id str
 			}
 			if remark.Metadata != nil {
 				fmt.Fprintf(w, "  Metadata:\n")
-				for k, v := range remark.Metadata.AsMap() {
-					fmt.Fprintf(w, "    %s:\t%v\n", k, v)
+				metadata := remark.Metadata.AsMap()
+				keys := make([]string, 0, len(metadata))
+				for k := range metadata {
+					keys = append(keys, k)
+				}
+				sort.Strings(keys)
+				for _, k := range keys {
+					fmt.Fprintf(w, "    %s:\t%v\n", k, metadata[k])
 				}
 			}
 			fmt.Fprintf(w, "\n")
@@ -480,6 +733,33 @@ func inspectBuild(ctx context.Context, client buildv1.BuildServiceClient, id str
 	}
 }
 
+func printRemark(ctx context.Context, client *buildsclient.Client, buildID, remarkID string) {
+	remark, err := client.Remark(ctx, buildID, remarkID)
+	if err != nil {
+		log.Fatalf("Failed to get remark: %v", err)
+	}
+
+	data, err := protojson.MarshalOptions{Multiline: true, Indent: "  "}.Marshal(remark)
+	if err != nil {
+		log.Fatalf("Failed to encode remark: %v", err)
+	}
+	fmt.Println(string(data))
+}
+
+// formatRemarkLine renders a remark in compiler diagnostic style,
+// file:line:col: status: pass: message
+func formatRemarkLine(remark *buildv1.CompilerRemark) string {
+	location := "<unknown>"
+	if loc := remark.Location; loc != nil && loc.File != "" {
+		location = fmt.Sprintf("%s:%d:%d", loc.File, loc.Line, loc.Column)
+	}
+	return fmt.Sprintf("%s: %s: %s: %s",
+		location,
+		strings.ToLower(remark.Status.String()),
+		strings.ToLower(remark.Pass.String()),
+		remark.Message)
+}
+
 func truncate(s string, n int) string {
 	if len(s) <= n {
 		return s
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"testing"
+	"time"
+
+	buildv1 "builds/api/build"
+)
+
+func TestCountRequest(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		args    []string
+		want    buildv1.CountBuildsRequest_Outcome
+		since   time.Time
+		wantErr bool
+	}{
+		{"unfiltered", nil, buildv1.CountBuildsRequest_OUTCOME_ANY, time.Time{}, false},
+		{"success", []string{"-success"}, buildv1.CountBuildsRequest_OUTCOME_SUCCEEDED, time.Time{}, false},
+		{"failed since", []string{"-failed", "-since", "24h"}, buildv1.CountBuildsRequest_OUTCOME_FAILED, now.Add(-24 * time.Hour), false},
+		{"both outcomes", []string{"-success", "-failed"}, 0, time.Time{}, true},
+		// The flag is named -success, as for list
+		{"succeeded", []string{"-succeeded"}, 0, time.Time{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("count", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			req, err := countRequest(fs, tt.args, now)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parsed %v, want an error", tt.args)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if req.Outcome != tt.want {
+				t.Errorf("Outcome = %v, want %v", req.Outcome, tt.want)
+			}
+			var since time.Time
+			if req.Since != nil {
+				since = req.Since.AsTime()
+			}
+			if !since.Equal(tt.since) {
+				t.Errorf("Since = %v, want %v", since, tt.since)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+// cmd/buildsctl/size.go
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	buildsclient "builds/internal/client"
+	"builds/internal/models"
+)
+
+// sizeDiff compares the output sizes of build B against build A
+type sizeDiff struct {
+	A        string       `json:"a"`
+	B        string       `json:"b"`
+	Totals   []metricDiff `json:"totals"`
+	Sections []metricDiff `json:"sections"`
+}
+
+// diffSizes compares two builds' output, text, data and bss sizes and each
+// loaded section. Sections only one build has are compared against n/a.
+func diffSizes(a, b *models.Build) sizeDiff {
+	diff := sizeDiff{A: a.ID, B: b.ID}
+	ma, mb := a.Metrics, b.Metrics
+	diff.Totals = []metricDiff{
+		newMetricDiff("Output Size", unitBytes, present(float64(ma.OutputSize)), present(float64(mb.OutputSize))),
+		newMetricDiff("Text", unitBytes, present(float64(ma.TextSize)), present(float64(mb.TextSize))),
+		newMetricDiff("Data", unitBytes, present(float64(ma.DataSize)), present(float64(mb.DataSize))),
+		newMetricDiff("BSS", unitBytes, present(float64(ma.BSSSize)), present(float64(mb.BSSSize))),
+	}
+
+	names := make([]string, 0, len(ma.SectionSizes)+len(mb.SectionSizes))
+	for name := range ma.SectionSizes {
+		names = append(names, name)
+	}
+	for name := range mb.SectionSizes {
+		if _, ok := ma.SectionSizes[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		diff.Sections = append(diff.Sections, newMetricDiff(name, unitBytes,
+			present(float64(ma.SectionSizes[name])), present(float64(mb.SectionSizes[name]))))
+	}
+	return diff
+}
+
+// sizeDiffBuilds fetches two builds and prints how the sizes of their
+// outputs differ. With -threshold it exits with status 1 when the output
+// of B grew by more than that percentage, so scripts can fail on size
+// regressions.
+func sizeDiffBuilds(ctx context.Context, client *buildsclient.Client, args []string) {
+	fs := flag.NewFlagSet("size-diff", flag.ExitOnError)
+	threshold := fs.Float64("threshold", 0, "Exit with status 1 when the output grew by more than this percentage")
+	fs.Parse(args)
+	if fs.NArg() < 2 {
+		log.Fatal("Two build IDs required")
+	}
+	idA, idB := fs.Arg(0), fs.Arg(1)
+
+	a, err := client.Get(ctx, idA)
+	if err != nil {
+		log.Fatalf("Failed to get build %s: %v", idA, err)
+	}
+	b, err := client.Get(ctx, idB)
+	if err != nil {
+		log.Fatalf("Failed to get build %s: %v", idB, err)
+	}
+	if a.Metrics == nil || b.Metrics == nil {
+		log.Fatal("Both builds must have recorded output sizes")
+	}
+
+	diff := diffSizes(buildsclient.BuildToModel(a), buildsclient.BuildToModel(b))
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(diff); err != nil {
+			log.Fatalf("Failed to encode size comparison: %v", err)
+		}
+	} else {
+		printSizeDiff(os.Stdout, diff)
+	}
+
+	if output := diff.Totals[0]; *threshold > 0 && output.Percent != nil && *output.Percent > *threshold {
+		fmt.Fprintf(os.Stderr, "Output size grew by %.1f%%, more than the %.1f%% threshold\n", *output.Percent, *threshold)
+		os.Exit(1)
+	}
+}
+
+func printSizeDiff(out io.Writer, diff sizeDiff) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintf(w, "SIZE\tA\tB\tDELTA\tCHANGE\n")
+	fmt.Fprintf(w, "Build\t%s\t%s\t\t\n", diff.A, diff.B)
+	for _, total := range diff.Totals {
+		printMetricDiff(w, total.Name, total)
+	}
+	if len(diff.Sections) > 0 {
+		fmt.Fprintf(w, "\nSECTIONS\t\t\t\t\n")
+		for _, section := range diff.Sections {
+			printMetricDiff(w, "  "+section.Name, section)
+		}
+	}
+}
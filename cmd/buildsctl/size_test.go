@@ -0,0 +1,56 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"builds/internal/models"
+)
+
+func TestDiffSizes(t *testing.T) {
+	a := &models.Build{ID: "a", Metrics: models.BuildMetrics{
+		OutputSize: 1000, TextSize: 600, DataSize: 100, BSSSize: 50,
+		SectionSizes: map[string]int64{".text": 600, ".data": 100, ".eh_frame": 80},
+	}}
+	b := &models.Build{ID: "b", Metrics: models.BuildMetrics{
+		OutputSize: 1250, TextSize: 900, DataSize: 100,
+		SectionSizes: map[string]int64{".text": 900, ".data": 100, ".init_array": 8},
+	}}
+
+	diff := diffSizes(a, b)
+	if diff.A != "a" || diff.B != "b" {
+		t.Errorf("compared %s with %s", diff.A, diff.B)
+	}
+
+	output := diff.Totals[0]
+	if output.Name != "Output Size" || *output.Delta != 250 || *output.Percent != 25 {
+		t.Errorf("output size %+v, want a 250 byte, 25%% increase", output)
+	}
+	if text := diff.Totals[1]; *text.Delta != 300 || *text.Percent != 50 {
+		t.Errorf("text %+v, want a 300 byte, 50%% increase", text)
+	}
+	// B has no bss, which is not comparable
+	if bss := diff.Totals[3]; bss.B != nil || bss.Delta != nil {
+		t.Errorf("bss %+v, want no delta", bss)
+	}
+
+	var names []string
+	for _, section := range diff.Sections {
+		names = append(names, section.Name)
+	}
+	if got := strings.Join(names, " "); got != ".data .eh_frame .init_array .text" {
+		t.Errorf("sections %s", got)
+	}
+	if data := diff.Sections[0]; *data.Delta != 0 {
+		t.Errorf(".data changed by %v", *data.Delta)
+	}
+	if added := diff.Sections[2]; added.A != nil || *added.B != 8 {
+		t.Errorf(".init_array %+v, want only in B", added)
+	}
+
+	var out strings.Builder
+	printSizeDiff(&out, diff)
+	if !strings.Contains(out.String(), ".eh_frame") || !strings.Contains(out.String(), "Output Size") {
+		t.Errorf("printed\n%s", out.String())
+	}
+}
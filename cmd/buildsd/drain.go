@@ -0,0 +1,107 @@
+// cmd/buildsd/drain.go
+
+package main
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+// requestTracker counts the requests being served so shutdown can wait for
+// them. gRPC is served through ServeHTTP on h2c connections, which neither
+// http.Server.Shutdown nor grpc.Server.GracefulStop can drain: Shutdown
+// does not track hijacked connections and GracefulStop panics on them.
+type requestTracker struct {
+	mu       sync.Mutex
+	active   int
+	draining bool
+	idle     chan struct{} // Closed once draining and no request is active
+
+	// Paths of requests that only end when the client leaves, such as
+	// watches, and the cancel functions of those running
+	unbounded map[string]bool
+	cancels   map[*http.Request]context.CancelFunc
+}
+
+// newRequestTracker tracks requests, cancelling those to the unbounded
+// paths as soon as draining starts rather than waiting for them
+func newRequestTracker(unbounded ...string) *requestTracker {
+	t := &requestTracker{
+		idle:      make(chan struct{}),
+		unbounded: make(map[string]bool, len(unbounded)),
+		cancels:   make(map[*http.Request]context.CancelFunc),
+	}
+	for _, path := range unbounded {
+		t.unbounded[path] = true
+	}
+	return t
+}
+
+// wrap serves requests through next until draining starts, answering
+// later ones with 503 Service Unavailable, which gRPC clients retry
+func (t *requestTracker) wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r, ok := t.start(r)
+		if !ok {
+			http.Error(w, "server is shutting down", http.StatusServiceUnavailable)
+			return
+		}
+		defer t.done(r)
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (t *requestTracker) start(r *http.Request) (*http.Request, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.draining {
+		return r, false
+	}
+	t.active++
+	if t.unbounded[r.URL.Path] {
+		ctx, cancel := context.WithCancel(r.Context())
+		r = r.WithContext(ctx)
+		t.cancels[r] = cancel
+	}
+	return r, true
+}
+
+func (t *requestTracker) done(r *http.Request) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if cancel, ok := t.cancels[r]; ok {
+		cancel()
+		delete(t.cancels, r)
+	}
+	t.active--
+	if t.draining && t.active == 0 {
+		close(t.idle)
+	}
+}
+
+// drain refuses new requests, cancels the unbounded ones and waits until
+// the others finish or ctx expires, returning the number still active in
+// that case
+func (t *requestTracker) drain(ctx context.Context) (int, error) {
+	t.mu.Lock()
+	if !t.draining {
+		t.draining = true
+		for _, cancel := range t.cancels {
+			cancel()
+		}
+		if t.active == 0 {
+			close(t.idle)
+		}
+	}
+	t.mu.Unlock()
+
+	select {
+	case <-t.idle:
+		return 0, nil
+	case <-ctx.Done():
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		return t.active, ctx.Err()
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDrainWaitsForRequests(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	requests := newRequestTracker()
+	server := httptest.NewServer(requests.wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		io.WriteString(w, "done")
+	})))
+	defer server.Close()
+
+	type result struct {
+		status int
+		body   string
+		err    error
+	}
+	inFlight := make(chan result, 1)
+	go func() {
+		resp, err := http.Get(server.URL)
+		if err != nil {
+			inFlight <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		inFlight <- result{resp.StatusCode, string(body), err}
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	drained := make(chan error, 1)
+	go func() {
+		_, err := requests.drain(ctx)
+		drained <- err
+	}()
+
+	// New requests are turned away while the old one finishes
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		resp, err := http.Get(server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusServiceUnavailable {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("new requests still accepted while draining: %s", resp.Status)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	select {
+	case err := <-drained:
+		t.Fatalf("drain returned %v with a request in flight", err)
+	default:
+	}
+
+	close(release)
+	if err := <-drained; err != nil {
+		t.Errorf("drain: %v", err)
+	}
+	got := <-inFlight
+	if got.err != nil || got.status != http.StatusOK || got.body != "done" {
+		t.Errorf("in-flight request got %d %q: %v", got.status, got.body, got.err)
+	}
+}
+
+func TestDrainCancelsUnboundedRequests(t *testing.T) {
+	started := make(chan struct{})
+	requests := newRequestTracker("/watch")
+	server := httptest.NewServer(requests.wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		close(started)
+		<-r.Context().Done()
+	})))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/watch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	start := time.Now()
+	if active, err := requests.drain(ctx); err != nil {
+		t.Fatalf("drain left %d requests: %v", active, err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("drain took %s waiting for a watch", elapsed)
+	}
+}
+
+func TestDrainTimeout(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	requests := newRequestTracker("/watch")
+	server := httptest.NewServer(requests.wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	})))
+	defer server.Close()
+	defer close(release)
+
+	go http.Get(server.URL + "/slow")
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if active, err := requests.drain(ctx); err == nil || active != 1 {
+		t.Errorf("drain returned %d active, %v; want 1 and a timeout", active, err)
+	}
+}
@@ -3,8 +3,12 @@ package main
 import (
 	buildv1 "builds/api/build"
 	"builds/internal/server/api"
+	"builds/internal/server/auth"
 	"builds/internal/server/db"
 	dbmodels "builds/internal/server/db/models"
+	grpcutil "builds/internal/utils/grpcutil"
+	"builds/internal/utils/units"
+	"context"
 	"flag"
 	"fmt"
 	"log"
@@ -13,18 +17,27 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/joho/godotenv"
 	"golang.org/x/net/http2"
 	"golang.org/x/net/http2/h2c"
 	"google.golang.org/grpc"
-	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
 var (
 	host = flag.String("host", "", "The server host (default: all interfaces)")
 	port = flag.Int("port", 50051, "The server port")
+
+	deleteGrace     = units.Duration(7 * 24 * time.Hour)
+	maxWrites       = flag.Int("max-concurrent-writes", 8, "Maximum build writes running at once (0 for no limit)")
+	writeQueue      = flag.Int("write-queue", 64, "Maximum build writes waiting for a slot before rejecting")
+	writeTimeout    = units.Duration(30 * time.Second)
+	drainTimeout    = units.Duration(30 * time.Second)
+	compressOutput  = units.Bytes(dbmodels.CompressThreshold)
+	storeRawRemarks = flag.Bool("store-raw-remarks", os.Getenv("BUILDS_STORE_RAW_REMARKS") == "true", "Store uploaded raw optimization records (storage heavy)")
+	serverTimes     = flag.Bool("server-timestamps", os.Getenv("BUILDS_SERVER_TIMESTAMPS") == "true", "Replace client build timestamps with the server clock")
 )
 
 func getNetworkInterfaces() []string {
@@ -58,31 +71,54 @@ func getNetworkInterfaces() []string {
 	return addresses
 }
 
+func init() {
+	flag.Var(&deleteGrace, "delete-grace", "How long deleted builds remain recoverable before pruning (e.g. 72h)")
+	flag.Var(&writeTimeout, "write-queue-timeout", "How long a queued build write waits for a slot")
+	flag.Var(&drainTimeout, "drain-timeout", "How long shutdown waits for in-flight requests to finish")
+	flag.Var(&compressOutput, "compress-output-above", "Store build stdout and stderr gzip-compressed from this size (0 to disable)")
+}
+
 func main() {
 	if err := godotenv.Load(); err != nil {
 		log.Printf("Warning: Error loading .env file: %v", err)
 	}
 
 	flag.Parse()
+	dbmodels.CompressThreshold = int(compressOutput)
 
-	dbURL := os.Getenv("DATABASE_URL")
-	if dbURL == "" {
-		log.Fatal("DATABASE_URL environment variable is required")
-	}
-
-	gormDB, err := gorm.Open(postgres.Open(dbURL), &gorm.Config{})
+	gormDB, err := connect()
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
 
-	if err := autoMigrate(gormDB); err != nil {
+	database, err := migrate(gormDB)
+	if err != nil {
 		log.Fatalf("Failed to migrate database schema: %v", err)
 	}
+	go pruneDeleted(database, time.Duration(deleteGrace))
+	srv := api.NewServer(database, api.Options{
+		StoreRawRemarks:  *storeRawRemarks,
+		ServerTimestamps: *serverTimes,
+
+		MaxConcurrentWrites: *maxWrites,
+		WriteQueueSize:      *writeQueue,
+		WriteQueueTimeout:   time.Duration(writeTimeout),
+	})
 
-	database := db.New(gormDB)
-	srv := api.NewServer(database)
+	serverOpts := grpcutil.ServerOptions()
+	if mapping := os.Getenv("BUILDS_TENANT_TOKENS"); mapping != "" {
+		tenants, err := auth.ParseTenants(mapping)
+		if err != nil {
+			log.Fatalf("Invalid BUILDS_TENANT_TOKENS: %v", err)
+		}
+		log.Printf("Tenant scoping enabled for %d tokens", len(tenants))
+		serverOpts = append(serverOpts,
+			grpc.UnaryInterceptor(tenants.UnaryInterceptor()),
+			grpc.StreamInterceptor(tenants.StreamInterceptor()),
+		)
+	}
 
-	grpcServer := grpc.NewServer()
+	grpcServer := grpc.NewServer(serverOpts...)
 	buildv1.RegisterBuildServiceServer(grpcServer, srv)
 
 	addr := fmt.Sprintf("%s:%d", *host, *port)
@@ -101,8 +137,13 @@ func main() {
 		}
 	})
 
+	// Watches last until the client leaves, so shutdown ends them at once
+	requests := newRequestTracker(
+		buildv1.BuildService_StreamBuilds_FullMethodName,
+		buildv1.BuildService_StreamRemarks_FullMethodName,
+	)
 	h2sServer := &http.Server{
-		Handler: h2c.NewHandler(httpHandler, &http2.Server{}),
+		Handler: h2c.NewHandler(requests.wrap(httpHandler), &http2.Server{}),
 	}
 
 	// Print server addresses
@@ -116,41 +157,79 @@ func main() {
 		log.Printf("Server listening at %v\n", listener.Addr())
 	}
 
-	// Handle shutdown gracefully
+	// Handle shutdown gracefully: stop accepting connections, let in-flight
+	// requests finish within the drain timeout, then close the database
+	stopped := make(chan struct{})
 	go func() {
+		defer close(stopped)
 		sigChan := make(chan os.Signal, 1)
 		signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 		<-sigChan
 		log.Println("\nShutting down server...")
-		grpcServer.GracefulStop()
-		h2sServer.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(drainTimeout))
+		defer cancel()
+		if err := h2sServer.Shutdown(ctx); err != nil {
+			log.Printf("Warning: HTTP shutdown: %v", err)
+		}
+		if active, err := requests.drain(ctx); err != nil {
+			log.Printf("Warning: cancelling %d requests still running after %s", active, time.Duration(drainTimeout))
+		}
+		grpcServer.Stop()
+
+		if sqlDB, err := gormDB.DB(); err == nil {
+			if err := sqlDB.Close(); err != nil {
+				log.Printf("Warning: closing database: %v", err)
+			}
+		}
 	}()
 
 	if err := h2sServer.Serve(listener); err != nil && err != http.ErrServerClosed {
 		log.Fatalf("Failed to serve: %v", err)
 	}
+	<-stopped
 }
 
-func autoMigrate(gormDB *gorm.DB) error {
-	return gormDB.AutoMigrate(
-		&dbmodels.Build{},
-		&dbmodels.Environment{},
-		&dbmodels.EnvironmentVariable{},
-		&dbmodels.Hardware{},
-		&dbmodels.GPU{},
-		&dbmodels.Compiler{},
-		&dbmodels.CompilerOption{},
-		&dbmodels.CompilerOptimization{},
-		&dbmodels.CompilerExtension{},
-		&dbmodels.Command{},
-		&dbmodels.CommandArgument{},
-		&dbmodels.Output{},
-		&dbmodels.Artifact{},
-		&dbmodels.CompilerRemark{},
-		&dbmodels.KernelInfo{},
-		&dbmodels.MemoryAccess{},
-		&dbmodels.ResourceUsage{},
-		&dbmodels.Performance{},
-		&dbmodels.PerformancePhase{},
-	)
+// connect opens the database named by DATABASE_URL, or failing that by the
+// DB_HOST, DB_PORT, DB_USER, DB_PASSWORD, DB_NAME and DB_SSLMODE variables.
+// Either way the pool is sized by DB_MAX_OPEN_CONNS, DB_MAX_IDLE_CONNS and
+// DB_MAX_LIFETIME.
+func connect() (*gorm.DB, error) {
+	config := db.NewDefaultConfig()
+
+	if dbURL := os.Getenv("DATABASE_URL"); dbURL != "" {
+		return db.Open(dbURL, config)
+	}
+
+	if config.Host == "" {
+		return nil, fmt.Errorf("DATABASE_URL or DB_HOST environment variable is required")
+	}
+	return db.Connect(config)
+}
+
+// pruneDeleted periodically removes builds whose deletion grace period
+// has expired
+func pruneDeleted(database *db.Database, grace time.Duration) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		pruned, err := database.PruneDeleted(time.Now().Add(-grace))
+		if err != nil {
+			log.Printf("Warning: failed to prune deleted builds: %v", err)
+		} else if pruned > 0 {
+			log.Printf("Pruned %d deleted builds", pruned)
+		}
+		<-ticker.C
+	}
+}
+
+// migrate brings the schema up to date through the same migration the
+// tests run, so the server never misses a table
+func migrate(gormDB *gorm.DB) (*db.Database, error) {
+	database := db.New(gormDB)
+	if err := database.Migrate(); err != nil {
+		return nil, err
+	}
+	return database, nil
 }
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/jackc/pgx/v5"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"builds/internal/server/db/dbtest"
+	dbmodels "builds/internal/server/db/models"
+)
+
+// dbVariables lists the component variables connect falls back to
+var dbVariables = []string{"DB_HOST", "DB_PORT", "DB_USER", "DB_PASSWORD", "DB_NAME", "DB_SSLMODE"}
+
+func TestConnectRequiresConfiguration(t *testing.T) {
+	t.Setenv("DATABASE_URL", "")
+	for _, key := range dbVariables {
+		t.Setenv(key, "")
+	}
+	if _, err := connect(); err == nil {
+		t.Error("connected without DATABASE_URL or DB_HOST")
+	}
+}
+
+func TestConnect(t *testing.T) {
+	url := os.Getenv(dbtest.EnvURL)
+	if url == "" {
+		t.Skipf("%s is not set", dbtest.EnvURL)
+	}
+	config, err := pgx.ParseConfig(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	components := map[string]string{
+		"DB_HOST":     config.Host,
+		"DB_PORT":     strconv.Itoa(int(config.Port)),
+		"DB_USER":     config.User,
+		"DB_PASSWORD": config.Password,
+		"DB_NAME":     config.Database,
+		"DB_SSLMODE":  "prefer",
+	}
+
+	tests := []struct {
+		name       string
+		url        string
+		components bool
+	}{
+		{"url", url, false},
+		{"components only", "", true},
+		// The URL wins over the components
+		{"url and components", url, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("DATABASE_URL", tt.url)
+			for _, key := range dbVariables {
+				value := ""
+				if tt.components {
+					value = components[key]
+				}
+				t.Setenv(key, value)
+			}
+			if tt.url != "" {
+				// Components that would not connect must be ignored
+				t.Setenv("DB_PORT", "1")
+			}
+			t.Setenv("DB_MAX_OPEN_CONNS", "7")
+
+			gormDB, err := connect()
+			if err != nil {
+				t.Fatal(err)
+			}
+			sqlDB, err := gormDB.DB()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer sqlDB.Close()
+			if err := sqlDB.Ping(); err != nil {
+				t.Errorf("ping: %v", err)
+			}
+			if got := sqlDB.Stats().MaxOpenConnections; got != 7 {
+				t.Errorf("MaxOpenConnections = %d, want 7", got)
+			}
+		})
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	gormDB, err := gorm.Open(sqlite.Open("file:buildsd_migrate?mode=memory&cache=shared"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := gormDB.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sqlDB.Close()
+
+	database, err := migrate(gormDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Reading and purging a build touch the tables added alongside remarks
+	build := dbmodels.Build{ID: "b1", Remarks: []dbmodels.CompilerRemark{{PassName: "inline", Status: "passed"}}}
+	if err := gormDB.Create(&build).Error; err != nil {
+		t.Fatal(err)
+	}
+	if _, err := database.PurgeRemarks("b1"); err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	got, err := database.GetBuildByID("b1")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if len(got.RemarkCounts) != 1 {
+		t.Errorf("got remark counts %+v, want one", got.RemarkCounts)
+	}
+}
@@ -2,9 +2,11 @@
 package performance
 
 import (
+	"fmt"
 	"strings"
 
 	"builds/internal/models"
+	"builds/internal/utils/units"
 )
 
 type Analyzer struct {
@@ -17,6 +19,12 @@ func NewAnalyzer(build *models.Build) *Analyzer {
 	}
 }
 
+// Analyze runs every analysis over a build. It is the entry point for
+// programs using the analyzer as a library, without a server.
+func Analyze(build *models.Build) (*AnalysisResult, error) {
+	return NewAnalyzer(build).Analyze()
+}
+
 type AnalysisResult struct {
 	ResourceEfficiency  float64                     `json:"resourceEfficiency"`
 	MemoryUsageProfile  map[string]int64            `json:"memoryUsageProfile"`
@@ -24,13 +32,29 @@ type AnalysisResult struct {
 	OptimizationMetrics map[string]int              `json:"optimizationMetrics"`
 	Bottlenecks         []PerformanceBottleneck     `json:"bottlenecks"`
 	Recommendations     []PerformanceRecommendation `json:"recommendations"`
+	RemarkHeatmap       []RemarkHotspot             `json:"remarkHeatmap"`
+	Files               []FileSummary               `json:"files"` // Most missed optimizations first
+	RegisterSpills      []FunctionSpills            `json:"registerSpills"`
+	MissedReasons       []MissedReason              `json:"missedReasons"`
 }
 
+// Thresholds above which a bottleneck is reported
+const (
+	MemoryUtilizationThreshold  = 0.9
+	CompileTimeThreshold        = 60.0 // seconds
+	MissedOptimizationThreshold = 10
+)
+
 type PerformanceBottleneck struct {
 	Type        string  `json:"type"`
 	Severity    string  `json:"severity"`
 	Description string  `json:"description"`
 	Impact      float64 `json:"impact"`
+
+	// The computed inputs behind the bottleneck, for explaining it
+	Inputs      map[string]float64 `json:"inputs,omitempty"`
+	Threshold   float64            `json:"threshold"`
+	Explanation string             `json:"explanation,omitempty"`
 }
 
 type PerformanceRecommendation struct {
@@ -53,6 +77,13 @@ func (a *Analyzer) Analyze() (*AnalysisResult, error) {
 	result.OptimizationMetrics = a.analyzeOptimizationMetrics()
 	result.Bottlenecks = a.identifyBottlenecks()
 	result.Recommendations = a.generateRecommendations(result.Bottlenecks)
+	result.Recommendations = append(result.Recommendations, a.analyzeIneffectiveFlags()...)
+	result.Recommendations = append(result.Recommendations, a.analyzeMemoryCoalescing()...)
+	result.Recommendations = append(result.Recommendations, a.analyzePGO()...)
+	result.RemarkHeatmap = a.analyzeRemarkHeatmap()
+	result.Files = a.analyzeFiles()
+	result.RegisterSpills = a.analyzeRegisterSpills()
+	result.MissedReasons = a.analyzeMissedReasons()
 
 	return result, nil
 }
@@ -62,8 +93,14 @@ func (a *Analyzer) calculateResourceEfficiency() float64 {
 		return 0
 	}
 
-	// Calculate efficiency based on CPU utilization and memory usage
-	cpuEfficiency := float64(a.build.ResourceUsage.Threads) / float64(a.build.Hardware.CPU.Cores)
+	// Calculate efficiency based on CPU utilization and memory usage.
+	// Threads run on logical CPUs, so those are what they are measured
+	// against; older builds only recorded a core count.
+	cpus := a.build.Hardware.CPU.Threads
+	if cpus == 0 {
+		cpus = a.build.Hardware.CPU.Cores
+	}
+	cpuEfficiency := float64(a.build.ResourceUsage.Threads) / float64(cpus)
 	memoryEfficiency := float64(a.build.ResourceUsage.MaxMemory) / float64(a.build.Hardware.Memory.Total)
 
 	return (cpuEfficiency + memoryEfficiency) / 2.0
@@ -108,58 +145,97 @@ func (a *Analyzer) analyzeCompilationOverhead() map[string]float64 {
 func (a *Analyzer) analyzeOptimizationMetrics() map[string]int {
 	metrics := make(map[string]int)
 
-	// Count optimization remarks by type
-	for _, remark := range a.build.Remarks {
-		switch remark.Type {
-		case "Passed":
-			metrics["successful_optimizations"]++
-		case "Missed":
-			metrics["missed_optimizations"]++
-		case "Analysis":
-			metrics["analysis_remarks"]++
+	// Count optimization remarks by outcome
+	counts, _ := a.remarkCounts()
+	for status, count := range counts {
+		switch status {
+		case models.RemarkStatusPassed:
+			metrics["successful_optimizations"] += count
+		case models.RemarkStatusMissed:
+			metrics["missed_optimizations"] += count
+		case models.RemarkStatusAnalysis:
+			metrics["analysis_remarks"] += count
 		}
 	}
 
 	return metrics
 }
 
+// remarkCounts counts the build's remarks by status, and in total. A build
+// whose remarks were purged is counted from the totals the server kept.
+func (a *Analyzer) remarkCounts() (map[models.RemarkStatus]int, int) {
+	counts := make(map[models.RemarkStatus]int)
+	if a.build.RemarksPurged && len(a.build.Remarks) == 0 {
+		total := 0
+		for _, c := range a.build.RemarkCounts {
+			counts[models.RemarkStatus(strings.ToLower(c.Status))] += int(c.Count)
+			total += int(c.Count)
+		}
+		return counts, total
+	}
+
+	for _, remark := range a.build.Remarks {
+		counts[models.RemarkStatus(strings.ToLower(remark.Status))]++
+	}
+	return counts, len(a.build.Remarks)
+}
+
 func (a *Analyzer) identifyBottlenecks() []PerformanceBottleneck {
 	var bottlenecks []PerformanceBottleneck
 
 	// Check memory usage
-	memoryUtilization := float64(a.build.ResourceUsage.MaxMemory) / float64(a.build.Hardware.Memory.Total)
-	if memoryUtilization > 0.9 {
+	peak := a.build.ResourceUsage.MaxMemory
+	total := a.build.Hardware.Memory.Total
+	memoryUtilization := float64(peak) / float64(total)
+	if memoryUtilization > MemoryUtilizationThreshold {
 		bottlenecks = append(bottlenecks, PerformanceBottleneck{
 			Type:        "memory",
 			Severity:    "high",
 			Description: "High memory utilization",
 			Impact:      memoryUtilization,
+			Inputs: map[string]float64{
+				"peak_memory":  float64(peak),
+				"total_memory": float64(total),
+			},
+			Threshold: MemoryUtilizationThreshold,
+			Explanation: fmt.Sprintf("memory utilization %.2f = %s peak / %s total (threshold %.2f)",
+				memoryUtilization, units.FormatBytes(peak), units.FormatBytes(total), MemoryUtilizationThreshold),
 		})
 	}
 
 	// Check compilation time
-	if a.build.Performance.CompileTime > 60.0 {
+	compileTime := a.build.Performance.CompileTime
+	if compileTime > CompileTimeThreshold {
 		bottlenecks = append(bottlenecks, PerformanceBottleneck{
 			Type:        "compilation",
 			Severity:    "medium",
 			Description: "Long compilation time",
-			Impact:      a.build.Performance.CompileTime,
+			Impact:      compileTime,
+			Inputs: map[string]float64{
+				"compile_time": compileTime,
+			},
+			Threshold: CompileTimeThreshold,
+			Explanation: fmt.Sprintf("compile time %.1fs (threshold %.1fs)",
+				compileTime, CompileTimeThreshold),
 		})
 	}
 
 	// Check optimization effectiveness
-	missedOpts := 0
-	for _, remark := range a.build.Remarks {
-		if remark.Type == "Missed" {
-			missedOpts++
-		}
-	}
-	if missedOpts > 10 {
+	counts, totalRemarks := a.remarkCounts()
+	missedOpts := counts[models.RemarkStatusMissed]
+	if missedOpts > MissedOptimizationThreshold {
 		bottlenecks = append(bottlenecks, PerformanceBottleneck{
 			Type:        "optimization",
 			Severity:    "low",
 			Description: "High number of missed optimizations",
 			Impact:      float64(missedOpts),
+			Inputs: map[string]float64{
+				"missed_optimizations": float64(missedOpts),
+				"total_remarks":        float64(totalRemarks),
+			},
+			Threshold: MissedOptimizationThreshold,
+			Explanation: fmt.Sprintf("%d missed optimizations out of %d remarks (threshold %d)",
+				missedOpts, totalRemarks, MissedOptimizationThreshold),
 		})
 	}
 
@@ -0,0 +1,150 @@
+package performance
+
+import (
+	"reflect"
+	"testing"
+
+	"builds/internal/models"
+)
+
+func TestBottlenecksCarryInputs(t *testing.T) {
+	const gib = 1 << 30
+
+	var missed []models.CompilerRemark
+	for range 12 {
+		missed = append(missed, models.CompilerRemark{Status: "missed"})
+	}
+
+	tests := []struct {
+		name        string
+		build       models.Build
+		wantType    string
+		wantInputs  map[string]float64
+		wantExplain string
+	}{
+		{
+			name: "memory",
+			build: models.Build{
+				Hardware:      models.Hardware{Memory: models.Memory{Total: 16 * gib}},
+				ResourceUsage: models.ResourceUsage{MaxMemory: 15 * gib},
+			},
+			wantType:    "memory",
+			wantInputs:  map[string]float64{"peak_memory": 15 * gib, "total_memory": 16 * gib},
+			wantExplain: "memory utilization 0.94 = 15.0GiB peak / 16.0GiB total (threshold 0.90)",
+		},
+		{
+			name: "compilation",
+			build: models.Build{
+				Hardware:    models.Hardware{Memory: models.Memory{Total: 16 * gib}},
+				Performance: models.Performance{CompileTime: 90},
+			},
+			wantType:    "compilation",
+			wantInputs:  map[string]float64{"compile_time": 90},
+			wantExplain: "compile time 90.0s (threshold 60.0s)",
+		},
+		{
+			name: "optimization",
+			build: models.Build{
+				Hardware: models.Hardware{Memory: models.Memory{Total: 16 * gib}},
+				Remarks:  append(missed, models.CompilerRemark{Status: "passed"}),
+			},
+			wantType:    "optimization",
+			wantInputs:  map[string]float64{"missed_optimizations": 12, "total_remarks": 13},
+			wantExplain: "12 missed optimizations out of 13 remarks (threshold 10)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bottlenecks := NewAnalyzer(&tt.build).identifyBottlenecks()
+			if len(bottlenecks) != 1 {
+				t.Fatalf("got %d bottlenecks, want 1: %+v", len(bottlenecks), bottlenecks)
+			}
+			b := bottlenecks[0]
+			if b.Type != tt.wantType {
+				t.Errorf("type %q, want %q", b.Type, tt.wantType)
+			}
+			if !reflect.DeepEqual(b.Inputs, tt.wantInputs) {
+				t.Errorf("inputs %v, want %v", b.Inputs, tt.wantInputs)
+			}
+			if b.Explanation != tt.wantExplain {
+				t.Errorf("explanation %q, want %q", b.Explanation, tt.wantExplain)
+			}
+		})
+	}
+}
+
+func TestMissedOptimizations(t *testing.T) {
+	// remarks returns n missed remarks, typed and cased as the parser
+	// stores them, plus a passed and an analysis remark
+	remarks := func(n int) []models.CompilerRemark {
+		remarks := []models.CompilerRemark{
+			{Type: "passed", Status: "passed"},
+			{Type: "analysis", Status: "analysis"},
+		}
+		for i := range n {
+			status := "missed"
+			if i%2 == 1 {
+				status = "Missed"
+			}
+			remarks = append(remarks, models.CompilerRemark{Type: "missed", Status: status})
+		}
+		return remarks
+	}
+
+	// counts returns the totals the server keeps for remarks(n) once they
+	// are purged
+	counts := func(n int) []models.RemarkCount {
+		return []models.RemarkCount{
+			{Pass: "inline", Status: "passed", Count: 1},
+			{Pass: "loop-vectorize", Status: "analysis", Count: 1},
+			{Pass: "loop-vectorize", Status: "missed", Count: int64(n - 1)},
+			{Pass: "inline", Status: "missed", Count: 1},
+		}
+	}
+
+	tests := []struct {
+		name           string
+		missed         int
+		purged         bool
+		wantBottleneck bool
+	}{
+		{"above the threshold", 15, false, true},
+		{"at the threshold", MissedOptimizationThreshold, false, false},
+		{"purged above the threshold", 15, true, true},
+		{"purged at the threshold", MissedOptimizationThreshold, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			build := &models.Build{
+				Hardware: models.Hardware{Memory: models.Memory{Total: 1 << 30}},
+				Remarks:  remarks(tt.missed),
+			}
+			if tt.purged {
+				build.Remarks = nil
+				build.RemarksPurged = true
+				build.RemarkCounts = counts(tt.missed)
+			}
+			result, err := Analyze(build)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			wantMetrics := map[string]int{
+				"successful_optimizations": 1,
+				"missed_optimizations":     tt.missed,
+				"analysis_remarks":         1,
+			}
+			if !reflect.DeepEqual(result.OptimizationMetrics, wantMetrics) {
+				t.Errorf("metrics %v, want %v", result.OptimizationMetrics, wantMetrics)
+			}
+
+			fired := false
+			for _, b := range result.Bottlenecks {
+				fired = fired || b.Type == "optimization"
+			}
+			if fired != tt.wantBottleneck {
+				t.Errorf("optimization bottleneck = %v, want %v: %+v", fired, tt.wantBottleneck, result.Bottlenecks)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+// internal/analysis/performance/coalescing.go
+package performance
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"builds/internal/models"
+)
+
+// uncoalescedPatterns are access patterns under which neighbouring threads
+// touch non-contiguous addresses, splitting each warp access into several
+// memory transactions
+var uncoalescedPatterns = []string{"strided", "random", "indirect", "gather", "scatter", "uncoalesced"}
+
+// analyzeMemoryCoalescing recommends restructuring GPU kernels whose global
+// memory accesses are likely uncoalesced. Flat accesses are included, as they
+// usually resolve to global memory.
+func (a *Analyzer) analyzeMemoryCoalescing() []PerformanceRecommendation {
+	byKernel := make(map[string][]models.MemoryAccess)
+
+	for _, remark := range a.build.Remarks {
+		if remark.KernelInfo == nil {
+			continue
+		}
+		name := remark.Function
+		if name == "" {
+			name = remark.Location.Function
+		}
+		for _, access := range remark.KernelInfo.MemoryAccesses {
+			if isGlobalAccess(access) && isUncoalesced(access.AccessPattern) {
+				byKernel[name] = append(byKernel[name], access)
+			}
+		}
+	}
+
+	kernels := make([]string, 0, len(byKernel))
+	for name := range byKernel {
+		kernels = append(kernels, name)
+	}
+	sort.Strings(kernels)
+
+	var recommendations []PerformanceRecommendation
+	for _, name := range kernels {
+		accesses := byKernel[name]
+		recommendations = append(recommendations, PerformanceRecommendation{
+			Category: "GPU Memory",
+			Action:   fmt.Sprintf("Coalesce global memory accesses in %s", name),
+			Impact:   "High",
+			Details: fmt.Sprintf("%d global memory accesses use a non-contiguous pattern (%s). "+
+				"Arrange data so consecutive threads access consecutive addresses, for example with a structure of arrays, or stage the data through shared memory.",
+				len(accesses), describeAccesses(accesses)),
+		})
+	}
+	return recommendations
+}
+
+func isGlobalAccess(access models.MemoryAccess) bool {
+	space := strings.ToLower(access.AddressSpace)
+	return space == "global" || space == "flat"
+}
+
+func isUncoalesced(pattern string) bool {
+	pattern = strings.ToLower(pattern)
+	for _, p := range uncoalescedPatterns {
+		if strings.Contains(pattern, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// describeAccesses summarises accesses as "strided load of a, random store"
+func describeAccesses(accesses []models.MemoryAccess) string {
+	seen := make(map[string]bool)
+	var parts []string
+	for _, access := range accesses {
+		part := strings.TrimSpace(access.AccessPattern + " " + access.Type)
+		if access.Variable != "" {
+			part += " of " + access.Variable
+		}
+		if !seen[part] {
+			seen[part] = true
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
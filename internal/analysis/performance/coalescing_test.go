@@ -0,0 +1,87 @@
+package performance
+
+import (
+	"strings"
+	"testing"
+
+	"builds/internal/models"
+)
+
+// kernel returns a kernel remark for function with the given accesses
+func kernel(function string, accesses ...models.MemoryAccess) models.CompilerRemark {
+	return models.CompilerRemark{
+		Function:   function,
+		KernelInfo: &models.KernelInfo{MemoryAccesses: accesses},
+	}
+}
+
+func TestAnalyzeMemoryCoalescing(t *testing.T) {
+	tests := []struct {
+		name        string
+		remarks     []models.CompilerRemark
+		wantActions []string
+		wantDetails string
+	}{
+		{
+			name: "coalesced",
+			remarks: []models.CompilerRemark{kernel("saxpy",
+				models.MemoryAccess{Type: "load", AddressSpace: "global", Variable: "x", AccessPattern: "coalesced"},
+				models.MemoryAccess{Type: "store", AddressSpace: "global", Variable: "y", AccessPattern: "contiguous"},
+			)},
+		},
+		{
+			name: "strided",
+			remarks: []models.CompilerRemark{kernel("transpose",
+				models.MemoryAccess{Type: "load", AddressSpace: "global", Variable: "in", AccessPattern: "contiguous"},
+				models.MemoryAccess{Type: "store", AddressSpace: "global", Variable: "out", AccessPattern: "strided"},
+			)},
+			wantActions: []string{"Coalesce global memory accesses in transpose"},
+			wantDetails: "1 global memory accesses use a non-contiguous pattern (strided store of out)",
+		},
+		{
+			name: "flat random",
+			remarks: []models.CompilerRemark{kernel("histogram",
+				models.MemoryAccess{Type: "atomic", AddressSpace: "flat", AccessPattern: "Random"},
+			)},
+			wantActions: []string{"Coalesce global memory accesses in histogram"},
+			wantDetails: "(Random atomic)",
+		},
+		{
+			name: "strided shared memory",
+			remarks: []models.CompilerRemark{kernel("reduce",
+				models.MemoryAccess{Type: "load", AddressSpace: "shared", Variable: "tile", AccessPattern: "strided"},
+			)},
+		},
+		{
+			name: "one recommendation per kernel",
+			remarks: []models.CompilerRemark{
+				kernel("spmv", models.MemoryAccess{Type: "load", AddressSpace: "global", Variable: "x", AccessPattern: "indirect"}),
+				kernel("gemm", models.MemoryAccess{Type: "load", AddressSpace: "global", Variable: "b", AccessPattern: "strided"}),
+				kernel("spmv", models.MemoryAccess{Type: "load", AddressSpace: "global", Variable: "x", AccessPattern: "indirect"}),
+				{Function: "host", Status: "missed"},
+			},
+			wantActions: []string{"Coalesce global memory accesses in gemm", "Coalesce global memory accesses in spmv"},
+			wantDetails: "2 global memory accesses use a non-contiguous pattern (indirect load of x)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			build := &models.Build{Remarks: tt.remarks}
+			recommendations := NewAnalyzer(build).analyzeMemoryCoalescing()
+
+			var actions []string
+			for _, r := range recommendations {
+				actions = append(actions, r.Action)
+				if r.Category != "GPU Memory" || r.Impact != "High" {
+					t.Errorf("category %q and impact %q", r.Category, r.Impact)
+				}
+			}
+			if strings.Join(actions, "\n") != strings.Join(tt.wantActions, "\n") {
+				t.Errorf("got %q, want %q", actions, tt.wantActions)
+			}
+			if n := len(recommendations); n > 0 && !strings.Contains(recommendations[n-1].Details, tt.wantDetails) {
+				t.Errorf("details %q, want %q", recommendations[n-1].Details, tt.wantDetails)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+// internal/analysis/performance/files.go
+package performance
+
+import (
+	"sort"
+	"strings"
+
+	"builds/internal/models"
+)
+
+// FileSummary totals the remarks of one source file. When a build compiles
+// several translation units it shows which file holds most of the missed
+// optimizations.
+type FileSummary struct {
+	File      string  `json:"file"`
+	Remarks   int     `json:"remarks"`
+	Passed    int     `json:"passed"`
+	Missed    int     `json:"missed"`
+	Functions int     `json:"functions"` // Distinct functions with remarks
+	Share     float64 `json:"share"`     // Fraction of all missed optimizations
+}
+
+// analyzeFiles groups remarks by source file, the file with the most
+// missed optimizations first. Remarks without a location are left out.
+func (a *Analyzer) analyzeFiles() []FileSummary {
+	byFile := make(map[string]*FileSummary)
+	functions := make(map[string]map[string]bool)
+	totalMissed := 0
+
+	for _, remark := range a.build.Remarks {
+		file := remark.Location.File
+		if file == "" {
+			continue
+		}
+
+		summary, ok := byFile[file]
+		if !ok {
+			summary = &FileSummary{File: file}
+			byFile[file] = summary
+			functions[file] = make(map[string]bool)
+		}
+
+		summary.Remarks++
+		switch {
+		case strings.EqualFold(remark.Status, string(models.RemarkStatusPassed)):
+			summary.Passed++
+		case strings.EqualFold(remark.Status, string(models.RemarkStatusMissed)):
+			summary.Missed++
+			totalMissed++
+		}
+		if remark.Function != "" {
+			functions[file][remark.Function] = true
+		}
+	}
+
+	files := make([]FileSummary, 0, len(byFile))
+	for file, summary := range byFile {
+		summary.Functions = len(functions[file])
+		if totalMissed > 0 {
+			summary.Share = float64(summary.Missed) / float64(totalMissed)
+		}
+		files = append(files, *summary)
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		if files[i].Missed != files[j].Missed {
+			return files[i].Missed > files[j].Missed
+		}
+		if files[i].Remarks != files[j].Remarks {
+			return files[i].Remarks > files[j].Remarks
+		}
+		return files[i].File < files[j].File
+	})
+
+	return files
+}
@@ -0,0 +1,48 @@
+package performance
+
+import (
+	"reflect"
+	"testing"
+
+	"builds/internal/models"
+)
+
+func TestAnalyzeFiles(t *testing.T) {
+	remark := func(file, function, status string) models.CompilerRemark {
+		return models.CompilerRemark{Status: status, Function: function, Location: models.Location{File: file, Line: 1}}
+	}
+
+	build := &models.Build{Remarks: []models.CompilerRemark{
+		remark("a.c", "foo", "passed"),
+		remark("a.c", "foo", "missed"),
+		remark("b.c", "bar", "missed"),
+		remark("b.c", "baz", "Missed"),
+		remark("b.c", "baz", "analysis"),
+		remark("c.c", "qux", "passed"),
+		remark("d.c", "", "missed"),
+		remark("e.c", "one", "passed"),
+		remark("e.c", "two", "passed"),
+		// No location to attribute
+		remark("", "foo", "missed"),
+	}}
+
+	got := NewAnalyzer(build).analyzeFiles()
+	want := []FileSummary{
+		{File: "b.c", Remarks: 3, Missed: 2, Functions: 2, Share: 0.5},
+		{File: "a.c", Remarks: 2, Passed: 1, Missed: 1, Functions: 1, Share: 0.25},
+		{File: "d.c", Remarks: 1, Missed: 1, Share: 0.25},
+		{File: "e.c", Remarks: 2, Passed: 2, Functions: 2},
+		{File: "c.c", Remarks: 1, Passed: 1, Functions: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	// Without misses no file takes a share
+	build.Remarks = []models.CompilerRemark{remark("a.c", "foo", "passed"), remark("b.c", "bar", "passed")}
+	for _, file := range NewAnalyzer(build).analyzeFiles() {
+		if file.Share != 0 {
+			t.Errorf("%s has share %v without misses", file.File, file.Share)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+// internal/analysis/performance/flags.go
+package performance
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"builds/internal/models"
+)
+
+// flagEffect describes the remarks that show an optimization flag had an
+// effect
+type flagEffect struct {
+	flags  []string // Option prefixes enabling the optimization
+	passes []string // Passes that report it
+	effect func(models.CompilerRemark) bool
+	names  bool // effect needs the pass names of the remarks
+	link   bool // Only link steps can show the effect
+}
+
+// flagEffects are matched against the options as given, so the -O level
+// the remarks collector substitutes does not hide -Ofast. Profile-guided
+// flags have no rule: their remarks only carry hotness when the compiler
+// is asked for it, which the collector does not do.
+var flagEffects = []flagEffect{
+	{flags: []string{"-ffast-math", "-Ofast"}, passes: []string{"loop-vectorize", "slp-vectorizer", "instcombine"}},
+	{flags: []string{"-funroll-loops"}, passes: []string{"loop-unroll"}},
+	{flags: []string{"-fvectorize", "-ftree-vectorize", "-ftree-loop-vectorize"}, passes: []string{"loop-vectorize"}},
+	{flags: []string{"-fslp-vectorize", "-ftree-slp-vectorize"}, passes: []string{"slp-vectorizer"}},
+	{flags: []string{"-finline-functions"}, passes: []string{"inline"}},
+	{flags: []string{"-fopenmp"}, passes: []string{"openmp-opt"}},
+	{flags: []string{"-flto"}, effect: isLTORemark, names: true, link: true},
+}
+
+// passCategories are the coarse pass groups servers predating pass names
+// return in place of them
+var passCategories = map[string]bool{
+	"vectorization":    true,
+	"inlining":         true,
+	"kernel_info":      true,
+	"size_info":        true,
+	"pass_analysis":    true,
+	"pass_unspecified": true,
+}
+
+// analyzeIneffectiveFlags recommends reviewing optimization flags that were
+// passed but left no trace in the remarks. Builds without remarks are
+// skipped, as there is nothing to correlate against, and so are rules
+// needing pass names when the remarks only carry pass categories.
+func (a *Analyzer) analyzeIneffectiveFlags() []PerformanceRecommendation {
+	if len(a.build.Remarks) == 0 {
+		return nil
+	}
+	named := a.hasPassNames()
+	compileOnly := a.compileOnly()
+
+	var recommendations []PerformanceRecommendation
+	for _, rule := range flagEffects {
+		if (len(rule.passes) > 0 || rule.names) && !named {
+			continue
+		}
+		// Link-time optimization runs when linking, after a -c compile
+		// has long finished
+		if rule.link && compileOnly {
+			continue
+		}
+		flag := a.passedFlag(rule.flags)
+		if flag == "" || a.hasEffect(rule) {
+			continue
+		}
+
+		observed := "remarks"
+		if len(rule.passes) > 0 {
+			observed = "remarks from " + strings.Join(rule.passes, ", ")
+		}
+		recommendations = append(recommendations, PerformanceRecommendation{
+			Category: "Compiler Flags",
+			Action:   fmt.Sprintf("Review %s, which had no observable effect", flag),
+			Impact:   "Low",
+			Details:  fmt.Sprintf("%s was passed but no matching %s were recorded. The flag may be redundant or overridden by later options.", flag, observed),
+		})
+	}
+
+	sort.Slice(recommendations, func(i, j int) bool {
+		return recommendations[i].Action < recommendations[j].Action
+	})
+	return recommendations
+}
+
+// hasPassNames reports whether any remark names the pass that emitted it
+func (a *Analyzer) hasPassNames() bool {
+	for _, remark := range a.build.Remarks {
+		if remark.Pass != "" && !passCategories[strings.ToLower(remark.Pass)] {
+			return true
+		}
+	}
+	return false
+}
+
+// compileOnly reports whether the build stopped before linking
+func (a *Analyzer) compileOnly() bool {
+	for _, option := range a.build.Compiler.Options {
+		switch option {
+		case "-c", "-S", "-E":
+			return true
+		}
+	}
+	return false
+}
+
+// passedFlag returns the first compiler option matching one of prefixes
+func (a *Analyzer) passedFlag(prefixes []string) string {
+	for _, option := range a.build.Compiler.Options {
+		for _, prefix := range prefixes {
+			if option == prefix || strings.HasPrefix(option, prefix+"=") {
+				return option
+			}
+		}
+	}
+	return ""
+}
+
+// hasEffect reports whether any remark shows the rule's optimization fired
+func (a *Analyzer) hasEffect(rule flagEffect) bool {
+	for _, remark := range a.build.Remarks {
+		if rule.effect != nil {
+			if rule.effect(remark) {
+				return true
+			}
+			continue
+		}
+		if !strings.EqualFold(remark.Status, string(models.RemarkStatusPassed)) {
+			continue
+		}
+		for _, pass := range rule.passes {
+			if strings.EqualFold(remark.Pass, pass) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isLTORemark reports whether a remark was emitted during link-time
+// optimization
+func isLTORemark(remark models.CompilerRemark) bool {
+	pass := strings.ToLower(remark.Pass)
+	return strings.Contains(strings.ToLower(remark.Location.Artifact), ".lto.") ||
+		strings.HasPrefix(pass, "lto") ||
+		strings.HasPrefix(pass, "thinlto") ||
+		pass == "function-import" ||
+		pass == "wholeprogramdevirt"
+}
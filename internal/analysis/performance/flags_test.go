@@ -0,0 +1,97 @@
+package performance
+
+import (
+	"strings"
+	"testing"
+
+	"builds/internal/models"
+)
+
+func TestAnalyzeIneffectiveFlags(t *testing.T) {
+	passed := func(pass string) models.CompilerRemark {
+		return models.CompilerRemark{Pass: pass, Name: "Done", Status: "passed"}
+	}
+
+	tests := []struct {
+		name    string
+		options []string
+		remarks []models.CompilerRemark
+		want    []string // Flags reported as ineffective
+	}{
+		{
+			name:    "unrolling happened",
+			options: []string{"-O2", "-funroll-loops"},
+			remarks: []models.CompilerRemark{passed("loop-unroll")},
+		},
+		{
+			name:    "unrolling never happened",
+			options: []string{"-O2", "-funroll-loops"},
+			remarks: []models.CompilerRemark{passed("inline")},
+			want:    []string{"-funroll-loops"},
+		},
+		{
+			name:    "missed remarks show no effect",
+			options: []string{"-fvectorize"},
+			remarks: []models.CompilerRemark{{Pass: "loop-vectorize", Status: "missed"}},
+			want:    []string{"-fvectorize"},
+		},
+		{
+			name:    "lto artifact",
+			options: []string{"-flto=thin"},
+			remarks: []models.CompilerRemark{{
+				Pass:     "inline",
+				Status:   "passed",
+				Location: models.Location{Artifact: "app.lto.opt.yaml"},
+			}},
+		},
+		{
+			name:    "lto without link-time remarks",
+			options: []string{"-flto"},
+			remarks: []models.CompilerRemark{passed("inline")},
+			want:    []string{"-flto"},
+		},
+		{
+			name:    "lto on a compile step",
+			options: []string{"-flto", "-c"},
+			remarks: []models.CompilerRemark{passed("inline")},
+		},
+		{
+			name:    "fast math level never vectorized",
+			options: []string{"-Ofast"},
+			remarks: []models.CompilerRemark{passed("inline")},
+			want:    []string{"-Ofast"},
+		},
+		{
+			name:    "profile without hotness",
+			options: []string{"-fprofile-use=app.profdata"},
+			remarks: []models.CompilerRemark{passed("inline")},
+		},
+		{
+			name:    "pass categories only",
+			options: []string{"-ffast-math", "-funroll-loops", "-flto"},
+			remarks: []models.CompilerRemark{passed("vectorization"), passed("pass_analysis")},
+		},
+		{
+			name:    "no remarks",
+			options: []string{"-funroll-loops"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			build := &models.Build{
+				Compiler: models.Compiler{Options: tt.options},
+				Remarks:  tt.remarks,
+			}
+			recommendations := NewAnalyzer(build).analyzeIneffectiveFlags()
+
+			var got []string
+			for _, r := range recommendations {
+				got = append(got, strings.TrimSuffix(strings.Fields(r.Action)[1], ","))
+			}
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("ineffective flags %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
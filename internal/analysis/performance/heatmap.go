@@ -0,0 +1,80 @@
+// internal/analysis/performance/heatmap.go
+package performance
+
+import (
+	"sort"
+	"strings"
+
+	"builds/internal/models"
+)
+
+// heatmapBucketSize is the number of source lines grouped into one bucket
+const heatmapBucketSize = 50
+
+// RemarkHotspot represents the remarks attributed to a line range of a file
+type RemarkHotspot struct {
+	File      string  `json:"file"`
+	StartLine int32   `json:"startLine"`
+	EndLine   int32   `json:"endLine"`
+	Remarks   int     `json:"remarks"`
+	Missed    int     `json:"missed"`
+	Share     float64 `json:"share"` // Fraction of all missed optimizations
+}
+
+type hotspotKey struct {
+	file   string
+	bucket int32
+}
+
+// analyzeRemarkHeatmap buckets remarks by file and line range
+func (a *Analyzer) analyzeRemarkHeatmap() []RemarkHotspot {
+	buckets := make(map[hotspotKey]*RemarkHotspot)
+	totalMissed := 0
+
+	for _, remark := range a.build.Remarks {
+		if remark.Location.File == "" || remark.Location.Line <= 0 {
+			continue
+		}
+
+		bucket := (remark.Location.Line - 1) / heatmapBucketSize
+		key := hotspotKey{file: remark.Location.File, bucket: bucket}
+		spot, ok := buckets[key]
+		if !ok {
+			spot = &RemarkHotspot{
+				File:      remark.Location.File,
+				StartLine: bucket*heatmapBucketSize + 1,
+				EndLine:   (bucket + 1) * heatmapBucketSize,
+			}
+			buckets[key] = spot
+		}
+
+		spot.Remarks++
+		if strings.EqualFold(remark.Status, string(models.RemarkStatusMissed)) {
+			spot.Missed++
+			totalMissed++
+		}
+	}
+
+	hotspots := make([]RemarkHotspot, 0, len(buckets))
+	for _, spot := range buckets {
+		if totalMissed > 0 {
+			spot.Share = float64(spot.Missed) / float64(totalMissed)
+		}
+		hotspots = append(hotspots, *spot)
+	}
+
+	sort.Slice(hotspots, func(i, j int) bool {
+		if hotspots[i].Missed != hotspots[j].Missed {
+			return hotspots[i].Missed > hotspots[j].Missed
+		}
+		if hotspots[i].Remarks != hotspots[j].Remarks {
+			return hotspots[i].Remarks > hotspots[j].Remarks
+		}
+		if hotspots[i].File != hotspots[j].File {
+			return hotspots[i].File < hotspots[j].File
+		}
+		return hotspots[i].StartLine < hotspots[j].StartLine
+	})
+
+	return hotspots
+}
@@ -0,0 +1,39 @@
+package performance
+
+import (
+	"reflect"
+	"testing"
+
+	"builds/internal/models"
+)
+
+func TestAnalyzeRemarkHeatmap(t *testing.T) {
+	remark := func(file string, line int32, status string) models.CompilerRemark {
+		return models.CompilerRemark{Status: status, Location: models.Location{File: file, Line: line}}
+	}
+
+	build := &models.Build{Remarks: []models.CompilerRemark{
+		// A cluster of misses around a loop in a.c
+		remark("a.c", 120, "missed"),
+		remark("a.c", 121, "missed"),
+		remark("a.c", 130, "Missed"),
+		remark("a.c", 140, "passed"),
+		// Bucket edges: line 50 closes the first bucket, 51 opens the next
+		remark("a.c", 1, "passed"),
+		remark("a.c", 50, "missed"),
+		remark("b.c", 51, "passed"),
+		// No line to attribute
+		remark("a.c", 0, "missed"),
+		remark("", 10, "missed"),
+	}}
+
+	got := NewAnalyzer(build).analyzeRemarkHeatmap()
+	want := []RemarkHotspot{
+		{File: "a.c", StartLine: 101, EndLine: 150, Remarks: 4, Missed: 3, Share: 0.75},
+		{File: "a.c", StartLine: 1, EndLine: 50, Remarks: 2, Missed: 1, Share: 0.25},
+		{File: "b.c", StartLine: 51, EndLine: 100, Remarks: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
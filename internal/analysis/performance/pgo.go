@@ -0,0 +1,92 @@
+// internal/analysis/performance/pgo.go
+package performance
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"builds/internal/models"
+)
+
+const (
+	// hotFunctions is the number of functions with the most remarks taken
+	// as the hot code. Remarks only carry hotness when built from a
+	// profile, so the remark count stands in for it.
+	hotFunctions = 10
+	// minHotMisses is the number of missed hot remarks below which PGO is
+	// not worth recommending
+	minHotMisses = 10
+	// hotMissRatio is the fraction of hot remarks that must be misses
+	hotMissRatio = 0.5
+)
+
+// instrumentedProfileFlags feed an instrumented profile to the compiler.
+// Sample profiles are less precise, so builds using one still qualify.
+var instrumentedProfileFlags = []string{"-fprofile-use", "-fprofile-instr-use"}
+
+// analyzePGO recommends profile-guided optimization when most remarks in the
+// functions the compiler reports on most are missed optimizations and the
+// compiler supports it. Builds that already use an instrumented profile are
+// skipped.
+func (a *Analyzer) analyzePGO() []PerformanceRecommendation {
+	if !a.build.Compiler.Features.SupportsPGO || a.passedFlag(instrumentedProfileFlags) != "" {
+		return nil
+	}
+
+	type function struct {
+		name          string
+		total, missed int
+	}
+	byName := make(map[string]*function)
+	for _, remark := range a.build.Remarks {
+		name := remark.Function
+		if name == "" {
+			name = remark.Location.Function
+		}
+		if name == "" {
+			continue
+		}
+		fn, ok := byName[name]
+		if !ok {
+			fn = &function{name: name}
+			byName[name] = fn
+		}
+		fn.total++
+		if strings.EqualFold(remark.Status, string(models.RemarkStatusMissed)) {
+			fn.missed++
+		}
+	}
+
+	functions := make([]*function, 0, len(byName))
+	for _, fn := range byName {
+		functions = append(functions, fn)
+	}
+	sort.Slice(functions, func(i, j int) bool {
+		if functions[i].total != functions[j].total {
+			return functions[i].total > functions[j].total
+		}
+		return functions[i].name < functions[j].name
+	})
+	if len(functions) > hotFunctions {
+		functions = functions[:hotFunctions]
+	}
+
+	hot, missed := 0, 0
+	for _, fn := range functions {
+		hot += fn.total
+		missed += fn.missed
+	}
+	if missed < minHotMisses || float64(missed) < hotMissRatio*float64(hot) {
+		return nil
+	}
+
+	return []PerformanceRecommendation{{
+		Category: "Optimization",
+		Action:   "Enable profile-guided optimization",
+		Impact:   "High",
+		Details: fmt.Sprintf("%d of %d remarks in the %d functions with the most remarks are missed optimizations. "+
+			"Build with -fprofile-generate, run a representative workload and rebuild with -fprofile-use "+
+			"so the compiler can prioritize the code that actually runs hot.", missed, hot, len(functions)),
+	}}
+}
@@ -0,0 +1,86 @@
+package performance
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"builds/internal/models"
+)
+
+// hotBuild returns a build whose remarks fall in three functions, followed
+// by cold functions with a single missed remark each
+func hotBuild(missed, passed int, supportsPGO bool, options ...string) *models.Build {
+	build := &models.Build{}
+	build.Compiler.Features.SupportsPGO = supportsPGO
+	build.Compiler.Options = options
+	for i := 0; i < missed; i++ {
+		build.Remarks = append(build.Remarks, models.CompilerRemark{Status: "missed", Function: fmt.Sprintf("hot%d", i%3)})
+	}
+	for i := 0; i < passed; i++ {
+		build.Remarks = append(build.Remarks, models.CompilerRemark{Status: "passed", Function: fmt.Sprintf("hot%d", i%3)})
+	}
+	return build
+}
+
+// coldMisses adds n functions with a single missed remark each
+func coldMisses(build *models.Build, n int) *models.Build {
+	for i := 0; i < n; i++ {
+		build.Remarks = append(build.Remarks, models.CompilerRemark{Status: "missed", Function: fmt.Sprintf("cold%02d", i)})
+	}
+	return build
+}
+
+// withoutFunctions clears the functions of a build's remarks
+func withoutFunctions(build *models.Build) *models.Build {
+	for i := range build.Remarks {
+		build.Remarks[i].Function = ""
+	}
+	return build
+}
+
+func TestAnalyzePGO(t *testing.T) {
+	tests := []struct {
+		name  string
+		build *models.Build
+		want  bool
+	}{
+		{"hot misses with PGO support", hotBuild(12, 4, true), true},
+		{"no PGO support", hotBuild(12, 4, false), false},
+		{"too few hot misses", hotBuild(minHotMisses-1, 0, true), false},
+		{"hot code mostly optimized", hotBuild(12, 13, true), false},
+		{"misses in cold code", coldMisses(hotBuild(0, 30, true), 40), false},
+		{"misses without a function", withoutFunctions(hotBuild(12, 4, true)), false},
+		{"already uses a profile", hotBuild(12, 4, true, "-O2", "-fprofile-use=app.profdata"), false},
+		{"sample profile", hotBuild(12, 4, true, "-fprofile-sample-use=app.prof"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewAnalyzer(tt.build).analyzePGO()
+			if fired := len(got) > 0; fired != tt.want {
+				t.Fatalf("recommended PGO = %v, want %v: %+v", fired, tt.want, got)
+			}
+			if tt.want && !strings.Contains(got[0].Details, "12 of 16 remarks in the 3 functions") {
+				t.Errorf("details do not cite the hot misses: %s", got[0].Details)
+			}
+		})
+	}
+}
+
+func TestAnalyzeRecommendsPGO(t *testing.T) {
+	for _, supportsPGO := range []bool{true, false} {
+		result, err := Analyze(hotBuild(12, 4, supportsPGO))
+		if err != nil {
+			t.Fatal(err)
+		}
+		found := false
+		for _, rec := range result.Recommendations {
+			if rec.Action == "Enable profile-guided optimization" {
+				found = true
+			}
+		}
+		if found != supportsPGO {
+			t.Errorf("with PGO support %v: recommended = %v", supportsPGO, found)
+		}
+	}
+}
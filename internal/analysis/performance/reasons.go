@@ -0,0 +1,125 @@
+// internal/analysis/performance/reasons.go
+package performance
+
+import (
+	"sort"
+	"strings"
+
+	"builds/internal/models"
+)
+
+// Categories of missed optimizations
+const (
+	ReasonDataDependency = "data dependency"
+	ReasonAliasing       = "possible aliasing"
+	ReasonTripCount      = "unknown trip count"
+	ReasonFunctionCall   = "function call present"
+	ReasonControlFlow    = "complex control flow"
+	ReasonMemoryAccess   = "unsupported memory access"
+	ReasonNotBeneficial  = "not beneficial"
+	ReasonInliningLimit  = "inlining limit"
+	ReasonOther          = "other"
+)
+
+// reasonRules maps lowercase substrings of a missed remark to a category.
+// Rules are checked in order, so more specific phrases come first.
+var reasonRules = []struct {
+	substring string
+	category  string
+}{
+	{"unsafe dependent memory", ReasonDataDependency},
+	{"dependence", ReasonDataDependency},
+	{"dependency", ReasonDataDependency},
+	{"alias", ReasonAliasing},
+	{"could not determine number of loop iterations", ReasonTripCount},
+	{"number of iterations", ReasonTripCount},
+	{"trip count", ReasonTripCount},
+	{"loop count", ReasonTripCount},
+	{"call instruction", ReasonFunctionCall},
+	{"contains a call", ReasonFunctionCall},
+	{"function call", ReasonFunctionCall},
+	{"control flow", ReasonControlFlow},
+	{"multiple exits", ReasonControlFlow},
+	{"early exit", ReasonControlFlow},
+	{"non-contiguous", ReasonMemoryAccess},
+	{"unsupported memory", ReasonMemoryAccess},
+	{"cannot identify array bounds", ReasonMemoryAccess},
+	// LLVM reports noinline functions with cost=never
+	{"too large", ReasonInliningLimit},
+	{"noinline", ReasonInliningLimit},
+	{"never inline", ReasonInliningLimit},
+	{"never be inlined", ReasonInliningLimit},
+	{"not beneficial", ReasonNotBeneficial},
+	{"cost", ReasonNotBeneficial},
+}
+
+// msvcReasons maps the reason codes MSVC reports, which the parser stores
+// without the surrounding text, to a category
+var msvcReasons = map[string]string{
+	"1200": ReasonDataDependency,
+	"1203": ReasonMemoryAccess,
+}
+
+// MissedReason counts the missed optimizations of one category
+type MissedReason struct {
+	Category string  `json:"category"`
+	Count    int     `json:"count"`
+	Share    float64 `json:"share"`   // Fraction of all missed optimizations
+	Example  string  `json:"example"` // A representative reason text
+}
+
+// CategorizeReason maps the free-text reason of a missed optimization, or
+// the code MSVC gives in its place, to a category
+func CategorizeReason(reason string) string {
+	if category, ok := msvcReasons[reason]; ok {
+		return category
+	}
+	reason = strings.ToLower(reason)
+	for _, rule := range reasonRules {
+		if strings.Contains(reason, rule.substring) {
+			return rule.category
+		}
+	}
+	return ReasonOther
+}
+
+// analyzeMissedReasons counts missed optimizations by reason category
+func (a *Analyzer) analyzeMissedReasons() []MissedReason {
+	byCategory := make(map[string]*MissedReason)
+	total := 0
+
+	for _, remark := range a.build.Remarks {
+		if !strings.EqualFold(remark.Status, string(models.RemarkStatusMissed)) {
+			continue
+		}
+
+		text := remark.Args.Reason
+		if text == "" {
+			text = remark.Message
+		}
+		category := CategorizeReason(text)
+
+		reason, ok := byCategory[category]
+		if !ok {
+			reason = &MissedReason{Category: category, Example: remark.Message}
+			byCategory[category] = reason
+		}
+		reason.Count++
+		total++
+	}
+
+	reasons := make([]MissedReason, 0, len(byCategory))
+	for _, reason := range byCategory {
+		reason.Share = float64(reason.Count) / float64(total)
+		reasons = append(reasons, *reason)
+	}
+
+	sort.Slice(reasons, func(i, j int) bool {
+		if reasons[i].Count != reasons[j].Count {
+			return reasons[i].Count > reasons[j].Count
+		}
+		return reasons[i].Category < reasons[j].Category
+	})
+
+	return reasons
+}
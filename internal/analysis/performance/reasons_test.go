@@ -0,0 +1,98 @@
+package performance
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"builds/internal/models"
+	"builds/internal/parsers/remarks"
+)
+
+func TestCategorizeReason(t *testing.T) {
+	tests := []struct {
+		reason string
+		want   string
+	}{
+		// LLVM
+		{"loop not vectorized: unsafe dependent memory operations in loop. Use #pragma clang loop distribute(enable) to allow loop distribution to attempt to isolate the offending operations into a separate loop", ReasonDataDependency},
+		{"loop not vectorized: could not determine number of loop iterations", ReasonTripCount},
+		{"loop not vectorized: call instruction cannot be vectorized", ReasonFunctionCall},
+		{"loop not vectorized: loop control flow is not understood by vectorizer", ReasonControlFlow},
+		{"loop not vectorized: cannot identify array bounds", ReasonMemoryAccess},
+		{"the cost-model indicates that vectorization is not beneficial", ReasonNotBeneficial},
+		{"'foo' not inlined into 'bar' because too costly to inline (cost=300, threshold=225)", ReasonNotBeneficial},
+		{"'foo' not inlined into 'bar' because it should never be inlined (cost=never): noinline function attribute", ReasonInliningLimit},
+		{"failed to hoist load with loop-invariant address because load is clobbered by store", ReasonOther},
+		// GCC
+		{"not vectorized: possible dependence between data-refs a[i_12] and a[_3]", ReasonDataDependency},
+		{"not vectorized: number of iterations cannot be computed.", ReasonTripCount},
+		{"not vectorized: control flow in loop.", ReasonControlFlow},
+		{"not vectorized: loop contains function calls or data references that cannot be analyzed", ReasonFunctionCall},
+		{"can't determine dependence between *p_5 and *q_7: possible alias", ReasonDataDependency},
+		{"versioning for alias required", ReasonAliasing},
+		// MSVC
+		{"1200", ReasonDataDependency},
+		{"1203", ReasonMemoryAccess},
+		{"1008", ReasonOther},
+		// Case does not matter
+		{"LOOP NOT VECTORIZED: EARLY EXIT", ReasonControlFlow},
+		{"", ReasonOther},
+	}
+	for _, tt := range tests {
+		if got := CategorizeReason(tt.reason); got != tt.want {
+			t.Errorf("CategorizeReason(%q) = %q, want %q", tt.reason, got, tt.want)
+		}
+	}
+}
+
+func TestAnalyzeMissedReasons(t *testing.T) {
+	missed := func(message, reason string) models.CompilerRemark {
+		return models.CompilerRemark{Status: "missed", Message: message, Args: models.RemarkArgs{Reason: reason}}
+	}
+	build := &models.Build{Remarks: []models.CompilerRemark{
+		missed("loop not vectorized", "unsafe dependent memory operations in loop"),
+		missed("loop not vectorized: possible dependence between data-refs", ""),
+		missed("loop not vectorized", "could not determine number of loop iterations"),
+		missed("something else entirely", ""),
+		// Only missed optimizations count
+		{Status: "passed", Message: "vectorized loop", Args: models.RemarkArgs{Reason: "trip count 8"}},
+		{Status: "Missed", Message: "loop not vectorized: call instruction cannot be vectorized"},
+	}}
+
+	got := NewAnalyzer(build).analyzeMissedReasons()
+	want := []MissedReason{
+		{Category: ReasonDataDependency, Count: 2, Share: 0.4, Example: "loop not vectorized"},
+		{Category: ReasonFunctionCall, Count: 1, Share: 0.2, Example: "loop not vectorized: call instruction cannot be vectorized"},
+		{Category: ReasonOther, Count: 1, Share: 0.2, Example: "something else entirely"},
+		{Category: ReasonTripCount, Count: 1, Share: 0.2, Example: "loop not vectorized"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	if got := NewAnalyzer(&models.Build{}).analyzeMissedReasons(); len(got) != 0 {
+		t.Errorf("reasons for a build without remarks: %+v", got)
+	}
+}
+
+func TestAnalyzeMSVCReasons(t *testing.T) {
+	report := `--- Analyzing function: void __cdecl prefix_sum(int * __ptr64,int)
+C:\src\scan.cpp(15) : info C5002: loop not vectorized due to reason '1200'
+C:\src\scan.cpp(21) : info C5002: loop not vectorized due to reason '1203'
+C:\src\scan.cpp(27) : info C5002: loop not vectorized due to reason '1200'
+`
+	parsed, err := remarks.ParseMSVCReport(strings.NewReader(report), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := NewAnalyzer(&models.Build{Remarks: parsed}).analyzeMissedReasons()
+	want := []MissedReason{
+		{Category: ReasonDataDependency, Count: 2, Share: 2.0 / 3, Example: "C5002: loop not vectorized due to reason '1200'"},
+		{Category: ReasonMemoryAccess, Count: 1, Share: 1.0 / 3, Example: "C5002: loop not vectorized due to reason '1203'"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
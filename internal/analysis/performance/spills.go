@@ -0,0 +1,77 @@
+// internal/analysis/performance/spills.go
+package performance
+
+import (
+	"sort"
+
+	"builds/internal/models"
+)
+
+// FunctionSpills represents the register spills generated for a function
+type FunctionSpills struct {
+	Function string `json:"function"`
+	Stores   int64  `json:"stores"`
+	Loads    int64  `json:"loads"`
+}
+
+// Total returns the number of spill stores and loads
+func (f FunctionSpills) Total() int64 {
+	return f.Stores + f.Loads
+}
+
+// loopSpillRemark names the register allocator remarks counting the spills
+// of a single loop, which its function-level remark already includes
+const loopSpillRemark = "LoopSpillReloadCopies"
+
+// analyzeRegisterSpills ranks functions by the spills reported for them.
+// Loop counts are only used for functions without a function-level total.
+func (a *Analyzer) analyzeRegisterSpills() []FunctionSpills {
+	byFunction := make(map[string]*FunctionSpills)
+	byLoops := make(map[string]*FunctionSpills)
+
+	for _, remark := range a.build.Remarks {
+		if remark.KernelInfo == nil {
+			continue
+		}
+		stores := remark.KernelInfo.Metrics[models.MetricSpillStores]
+		loads := remark.KernelInfo.Metrics[models.MetricSpillLoads]
+		if stores == 0 && loads == 0 {
+			continue
+		}
+
+		name := remark.Function
+		if name == "" {
+			name = remark.Location.Function
+		}
+		totals := byFunction
+		if remark.Name == loopSpillRemark {
+			totals = byLoops
+		}
+		fn, ok := totals[name]
+		if !ok {
+			fn = &FunctionSpills{Function: name}
+			totals[name] = fn
+		}
+		fn.Stores += stores
+		fn.Loads += loads
+	}
+	for name, fn := range byLoops {
+		if _, ok := byFunction[name]; !ok {
+			byFunction[name] = fn
+		}
+	}
+
+	spills := make([]FunctionSpills, 0, len(byFunction))
+	for _, fn := range byFunction {
+		spills = append(spills, *fn)
+	}
+
+	sort.Slice(spills, func(i, j int) bool {
+		if spills[i].Total() != spills[j].Total() {
+			return spills[i].Total() > spills[j].Total()
+		}
+		return spills[i].Function < spills[j].Function
+	})
+
+	return spills
+}
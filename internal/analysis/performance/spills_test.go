@@ -0,0 +1,50 @@
+package performance
+
+import (
+	"reflect"
+	"testing"
+
+	"builds/internal/models"
+)
+
+func TestAnalyzeRegisterSpills(t *testing.T) {
+	spill := func(function string, stores, loads int64) models.CompilerRemark {
+		return models.CompilerRemark{
+			Function: function,
+			KernelInfo: &models.KernelInfo{Metrics: map[string]int64{
+				models.MetricSpillStores: stores,
+				models.MetricSpillLoads:  loads,
+			}},
+		}
+	}
+
+	build := &models.Build{Remarks: []models.CompilerRemark{
+		spill("small", 1, 0),
+		spill("large", 4, 2),
+		{Function: "none"},
+		spill("medium", 2, 1),
+		spill("small", 0, 2),
+		spill("tie", 2, 1),
+	}}
+	// The function-level remark already counts the spills of its loops
+	loop := spill("looped", 3, 3)
+	loop.Name = "LoopSpillReloadCopies"
+	whole := spill("looped", 3, 4)
+	whole.Name = "SpillReloadCopies"
+	loopOnly := spill("loops only", 1, 1)
+	loopOnly.Name = "LoopSpillReloadCopies"
+	build.Remarks = append(build.Remarks, loop, whole, loop, loopOnly, loopOnly)
+
+	got := NewAnalyzer(build).analyzeRegisterSpills()
+	want := []FunctionSpills{
+		{Function: "looped", Stores: 3, Loads: 4},
+		{Function: "large", Stores: 4, Loads: 2},
+		{Function: "loops only", Stores: 2, Loads: 2},
+		{Function: "medium", Stores: 2, Loads: 1},
+		{Function: "small", Stores: 1, Loads: 2},
+		{Function: "tie", Stores: 2, Loads: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
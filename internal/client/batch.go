@@ -0,0 +1,125 @@
+// internal/client/batch.go
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	buildv1 "builds/api/build"
+)
+
+// BatchResult is the outcome of storing one build of a batch
+type BatchResult struct {
+	ID  string
+	Err error
+}
+
+// CreateBatch stores builds over a single stream with at most parallel of
+// them in flight, which is much faster than a call per build for build farms.
+// It returns one result per build, in the order given. The error is only set
+// when the stream itself fails; builds without an answer by then carry it.
+func (c *Client) CreateBatch(ctx context.Context, builds []*buildv1.Build, parallel int) ([]BatchResult, error) {
+	if parallel <= 0 {
+		parallel = 1
+	}
+
+	results := make([]BatchResult, len(builds))
+	pending := make(map[string][]int, len(builds))
+	for i, build := range builds {
+		results[i].ID = build.GetId()
+		pending[build.GetId()] = append(pending[build.GetId()], i)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := c.service.CreateBuilds(c.withAuth(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep at most parallel builds unanswered
+	window := make(chan struct{}, parallel)
+	sendErr := make(chan error, 1)
+	go func() {
+		defer close(sendErr)
+		for _, build := range builds {
+			select {
+			case window <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			if err := stream.Send(&buildv1.CreateBuildRequest{Build: build}); err != nil {
+				// The receive side reports the cause
+				sendErr <- err
+				return
+			}
+		}
+		sendErr <- stream.CloseSend()
+	}()
+
+	var streamErr error
+	for answered := 0; answered < len(builds); answered++ {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			streamErr = fmt.Errorf("stream closed with %d builds unanswered", len(builds)-answered)
+			break
+		}
+		if err != nil {
+			streamErr = err
+			break
+		}
+		<-window
+
+		indexes := pending[resp.BuildId]
+		if len(indexes) == 0 {
+			continue
+		}
+		pending[resp.BuildId] = indexes[1:]
+		if resp.Code != int32(codes.OK) {
+			results[indexes[0]].Err = status.Error(codes.Code(resp.Code), resp.Error)
+		}
+	}
+	cancel()
+	if err := <-sendErr; err != nil && streamErr == nil {
+		streamErr = err
+	}
+
+	if streamErr != nil {
+		for _, indexes := range pending {
+			for _, i := range indexes {
+				results[i].Err = streamErr
+			}
+		}
+		return results, streamErr
+	}
+	return results, nil
+}
+
+// CreateBuilds stores up to 500 builds in a single call. It returns one
+// result per build, in the order given; the error is only set when the call
+// itself fails.
+func (c *Client) CreateBuilds(ctx context.Context, builds []*buildv1.Build) ([]BatchResult, error) {
+	var resp *buildv1.BatchCreateBuildsResponse
+	err := c.retry(ctx, func(ctx context.Context) (err error) {
+		resp, err = c.service.BatchCreateBuilds(ctx, &buildv1.BatchCreateBuildsRequest{Builds: builds})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]BatchResult, len(resp.Results))
+	for i, result := range resp.Results {
+		results[i].ID = result.BuildId
+		if result.Code != int32(codes.OK) {
+			results[i].Err = status.Error(codes.Code(result.Code), result.Error)
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,98 @@
+package client
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	buildv1 "builds/api/build"
+)
+
+// CreateBuilds rejects builds whose ID starts with "bad", and ends the
+// stream without answering once it reaches one starting with "stop"
+func (s *fakeServer) CreateBuilds(stream grpc.BidiStreamingServer[buildv1.CreateBuildRequest, buildv1.CreateBuildsResponse]) error {
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		id := req.Build.GetId()
+		resp := &buildv1.CreateBuildsResponse{BuildId: id}
+		switch {
+		case strings.HasPrefix(id, "stop"):
+			return status.Error(codes.Internal, "server went away")
+		case strings.HasPrefix(id, "bad"):
+			resp.Code = int32(codes.InvalidArgument)
+			resp.Error = "malformed build"
+		default:
+			s.mu.Lock()
+			s.builds = append(s.builds, req.Build)
+			s.mu.Unlock()
+		}
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}
+
+func TestCreateBatchPartialFailure(t *testing.T) {
+	fake := &fakeServer{}
+	c := newTestClient(t, fake)
+
+	ids := []string{"b1", "bad1", "b2", "b2", "bad2", "b3"}
+	var builds []*buildv1.Build
+	for _, id := range ids {
+		builds = append(builds, &buildv1.Build{Id: id})
+	}
+
+	results, err := c.CreateBatch(context.Background(), builds, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != len(ids) {
+		t.Fatalf("got %d results, want %d", len(results), len(ids))
+	}
+	for i, result := range results {
+		if result.ID != ids[i] {
+			t.Errorf("result %d is for %q, want %q", i, result.ID, ids[i])
+		}
+		wantCode := codes.OK
+		if strings.HasPrefix(ids[i], "bad") {
+			wantCode = codes.InvalidArgument
+		}
+		if code := status.Code(result.Err); code != wantCode {
+			t.Errorf("%s: %v, want %s", result.ID, result.Err, wantCode)
+		}
+	}
+	if len(fake.builds) != 4 {
+		t.Errorf("server stored %d builds, want 4", len(fake.builds))
+	}
+}
+
+func TestCreateBatchStreamFailure(t *testing.T) {
+	c := newTestClient(t, &fakeServer{})
+
+	builds := []*buildv1.Build{{Id: "b1"}, {Id: "stop"}, {Id: "b2"}}
+	// One build at a time, so b1 is answered before the stream fails
+	results, err := c.CreateBatch(context.Background(), builds, 1)
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("CreateBatch: %v, want the stream's Internal error", err)
+	}
+	if results[0].Err != nil {
+		t.Errorf("b1 failed with %v after it was stored", results[0].Err)
+	}
+	for _, result := range results[1:] {
+		if status.Code(result.Err) != codes.Internal {
+			t.Errorf("unanswered %s: %v, want the stream error", result.ID, result.Err)
+		}
+	}
+}
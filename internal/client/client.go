@@ -0,0 +1,415 @@
+// internal/client/client.go
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	buildv1 "builds/api/build"
+	grpcutil "builds/internal/utils/grpcutil"
+)
+
+// Options configures a Client
+type Options struct {
+	Address     string // Server address
+	TLS         bool   // Use TLS when connecting
+	Token       string // Bearer token sent with every request, if set
+	MaxAttempts int    // Attempts per unary call for transient failures
+}
+
+// Client wraps the BuildService gRPC API
+type Client struct {
+	conn        *grpc.ClientConn
+	service     buildv1.BuildServiceClient
+	token       string
+	maxAttempts int
+}
+
+// New connects to the build server described by opts
+func New(opts Options) (*Client, error) {
+	conn, err := grpcutil.CreateGRPCConnection(opts.Address, opts.TLS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", opts.Address, err)
+	}
+
+	maxAttempts := opts.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 3
+	}
+
+	return &Client{
+		conn:        conn,
+		service:     buildv1.NewBuildServiceClient(conn),
+		token:       opts.Token,
+		maxAttempts: maxAttempts,
+	}, nil
+}
+
+// NewFromService wraps an existing service client, such as one bound to an
+// in-process server
+func NewFromService(service buildv1.BuildServiceClient) *Client {
+	return &Client{
+		service:     service,
+		maxAttempts: 1,
+	}
+}
+
+// Close releases the underlying connection
+func (c *Client) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+	return c.conn.Close()
+}
+
+// Service exposes the raw gRPC client for calls not covered by Client
+func (c *Client) Service() buildv1.BuildServiceClient {
+	return c.service
+}
+
+// Create stores a build on the server
+func (c *Client) Create(ctx context.Context, build *buildv1.Build) (*buildv1.Build, error) {
+	return c.retryWrite(ctx, build.Id, codes.AlreadyExists, func(ctx context.Context) (*buildv1.Build, error) {
+		return c.service.CreateBuild(ctx, &buildv1.CreateBuildRequest{Build: build})
+	})
+}
+
+// Begin registers a running build for incremental remark ingestion
+func (c *Client) Begin(ctx context.Context, build *buildv1.Build) (*buildv1.Build, error) {
+	var resp *buildv1.Build
+	err := c.retry(ctx, func(ctx context.Context) (err error) {
+		resp, err = c.service.BeginBuild(ctx, &buildv1.CreateBuildRequest{Build: build})
+		return err
+	})
+	return resp, err
+}
+
+// Finalize completes a build started with Begin
+func (c *Client) Finalize(ctx context.Context, build *buildv1.Build) (*buildv1.Build, error) {
+	return c.retryWrite(ctx, build.Id, codes.FailedPrecondition, func(ctx context.Context) (*buildv1.Build, error) {
+		return c.service.FinalizeBuild(ctx, &buildv1.CreateBuildRequest{Build: build})
+	})
+}
+
+// RemarkBatchSize is the most remarks Store sends in one message, which
+// keeps each well under gRPC's default 4 MiB limit
+const RemarkBatchSize = 5000
+
+// Store saves a finished build. Builds with more than RemarkBatchSize
+// remarks are begun, sent their remarks in batches and then finalized, as
+// a single CreateBuild message would grow past what servers accept.
+func (c *Client) Store(ctx context.Context, build *buildv1.Build) (*buildv1.Build, error) {
+	remarks := build.Remarks
+	if len(remarks) <= RemarkBatchSize {
+		return c.Create(ctx, build)
+	}
+
+	if _, err := c.Begin(ctx, &buildv1.Build{Id: build.Id, StartTime: build.StartTime}); err != nil {
+		return nil, err
+	}
+	stream, err := c.AppendRemarks(ctx, build.Id)
+	if err != nil {
+		return nil, err
+	}
+	for start := 0; start < len(remarks); start += RemarkBatchSize {
+		if err := stream.Send(remarks[start:min(start+RemarkBatchSize, len(remarks))]); err != nil {
+			break // Close reports why the stream failed
+		}
+	}
+	if _, err := stream.Close(); err != nil {
+		return nil, err
+	}
+
+	build.Remarks = nil
+	defer func() { build.Remarks = remarks }()
+	return c.Finalize(ctx, build)
+}
+
+// RemarkStream sends remarks of an in-progress build to the server
+type RemarkStream struct {
+	buildID string
+	stream  buildv1.BuildService_AppendRemarksClient
+}
+
+// AppendRemarks opens a stream of remarks for a build started with Begin
+func (c *Client) AppendRemarks(ctx context.Context, buildID string) (*RemarkStream, error) {
+	stream, err := c.service.AppendRemarks(c.withAuth(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return &RemarkStream{buildID: buildID, stream: stream}, nil
+}
+
+// Send uploads a batch of remarks
+func (s *RemarkStream) Send(remarks []*buildv1.CompilerRemark) error {
+	return s.stream.Send(&buildv1.AppendRemarksRequest{
+		BuildId: s.buildID,
+		Remarks: remarks,
+	})
+}
+
+// Close ends the stream and returns how many remarks the server stored
+func (s *RemarkStream) Close() (int64, error) {
+	resp, err := s.stream.CloseAndRecv()
+	if err != nil {
+		return 0, err
+	}
+	return resp.Stored, nil
+}
+
+// rawChunkSize bounds each chunk of an uploaded raw record
+const rawChunkSize = 1 << 20
+
+// UploadRawRemarks attaches a gzip-compressed optimization record to a
+// stored build, sending it in chunks. It reports whether the server kept it.
+func (c *Client) UploadRawRemarks(ctx context.Context, buildID string, data []byte) (bool, error) {
+	stream, err := c.service.UploadRawRemarks(c.withAuth(ctx))
+	if err != nil {
+		return false, err
+	}
+	for len(data) > 0 {
+		n := min(len(data), rawChunkSize)
+		if err := stream.Send(&buildv1.UploadRawRemarksRequest{BuildId: buildID, Data: data[:n]}); err != nil {
+			// The server's reason is returned by CloseAndRecv
+			break
+		}
+		data = data[n:]
+	}
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		return false, err
+	}
+	return resp.Stored, nil
+}
+
+// Get fetches a single build
+func (c *Client) Get(ctx context.Context, id string) (*buildv1.Build, error) {
+	var resp *buildv1.Build
+	err := c.retry(ctx, func(ctx context.Context) (err error) {
+		resp, err = c.service.GetBuild(ctx, &buildv1.GetBuildRequest{Id: id})
+		return err
+	})
+	return resp, err
+}
+
+// List fetches one page of builds
+func (c *Client) List(ctx context.Context, pageSize int32, pageToken string) (*buildv1.ListBuildsResponse, error) {
+	var resp *buildv1.ListBuildsResponse
+	err := c.retry(ctx, func(ctx context.Context) (err error) {
+		resp, err = c.service.ListBuilds(ctx, &buildv1.ListBuildsRequest{
+			PageSize:  pageSize,
+			PageToken: pageToken,
+		})
+		return err
+	})
+	return resp, err
+}
+
+// Delete removes a build
+func (c *Client) Delete(ctx context.Context, id string) error {
+	return c.retry(ctx, func(ctx context.Context) error {
+		_, err := c.service.DeleteBuild(ctx, &buildv1.DeleteBuildRequest{Id: id})
+		return err
+	})
+}
+
+// Undelete restores a deleted build that has not been pruned yet
+func (c *Client) Undelete(ctx context.Context, id string) (*buildv1.Build, error) {
+	var resp *buildv1.Build
+	err := c.retry(ctx, func(ctx context.Context) (err error) {
+		resp, err = c.service.UndeleteBuild(ctx, &buildv1.UndeleteBuildRequest{Id: id})
+		return err
+	})
+	return resp, err
+}
+
+// Remark fetches a single remark of a build by its stable ID
+func (c *Client) Remark(ctx context.Context, buildID, remarkID string) (*buildv1.CompilerRemark, error) {
+	var resp *buildv1.CompilerRemark
+	err := c.retry(ctx, func(ctx context.Context) (err error) {
+		resp, err = c.service.GetRemark(ctx, &buildv1.GetRemarkRequest{BuildId: buildID, RemarkId: remarkID})
+		return err
+	})
+	return resp, err
+}
+
+// RemarkHistory reports the first and last builds in which a remark appeared
+func (c *Client) RemarkHistory(ctx context.Context, req *buildv1.RemarkHistoryRequest) (*buildv1.RemarkHistoryResponse, error) {
+	var resp *buildv1.RemarkHistoryResponse
+	err := c.retry(ctx, func(ctx context.Context) (err error) {
+		resp, err = c.service.RemarkHistory(ctx, req)
+		return err
+	})
+	return resp, err
+}
+
+// PurgeRemarks deletes a build's remarks, returning how many were removed
+func (c *Client) PurgeRemarks(ctx context.Context, buildID string) (int64, error) {
+	var resp *buildv1.PurgeRemarksResponse
+	err := c.retry(ctx, func(ctx context.Context) (err error) {
+		resp, err = c.service.PurgeRemarks(ctx, &buildv1.PurgeRemarksRequest{BuildId: buildID})
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return resp.Purged, nil
+}
+
+// Search lists the builds matching the request's filters, passing each
+// page to handler. With all set it follows the page tokens to the end,
+// otherwise it stops after the first page. It returns the token of the
+// page that was not fetched, empty when there are no more builds.
+func (c *Client) Search(ctx context.Context, req *buildv1.ListBuildsRequest, all bool, handler func([]*buildv1.Build) error) (string, error) {
+	req = proto.Clone(req).(*buildv1.ListBuildsRequest)
+	for {
+		var resp *buildv1.ListBuildsResponse
+		err := c.retry(ctx, func(ctx context.Context) (err error) {
+			resp, err = c.service.ListBuilds(ctx, req)
+			return err
+		})
+		if err != nil {
+			return req.PageToken, err
+		}
+		if err := handler(resp.Builds); err != nil {
+			return resp.NextPageToken, err
+		}
+		if resp.NextPageToken == "" || !all {
+			return resp.NextPageToken, nil
+		}
+		req.PageToken = resp.NextPageToken
+	}
+}
+
+// Summary fetches the fleet overview
+func (c *Client) Summary(ctx context.Context, topCompilers int32) (*buildv1.BuildSummary, error) {
+	var resp *buildv1.BuildSummary
+	err := c.retry(ctx, func(ctx context.Context) (err error) {
+		resp, err = c.service.GetSummary(ctx, &buildv1.GetSummaryRequest{TopCompilers: topCompilers})
+		return err
+	})
+	return resp, err
+}
+
+// Count returns the number of builds matching the request's filter
+func (c *Client) Count(ctx context.Context, req *buildv1.CountBuildsRequest) (int64, error) {
+	var resp *buildv1.CountBuildsResponse
+	err := c.retry(ctx, func(ctx context.Context) (err error) {
+		resp, err = c.service.CountBuilds(ctx, req)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return resp.Count, nil
+}
+
+// RawRemarks copies the raw optimization record of a build to w
+func (c *Client) RawRemarks(ctx context.Context, id string, w io.Writer) error {
+	stream, err := c.service.GetRawRemarks(c.withAuth(ctx), &buildv1.GetRawRemarksRequest{Id: id})
+	if err != nil {
+		return err
+	}
+
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(chunk.Data); err != nil {
+			return err
+		}
+	}
+}
+
+// TailRemarks passes a build's remarks to handler as they are stored,
+// returning once the build is finalized and all its remarks were received
+func (c *Client) TailRemarks(ctx context.Context, buildID string, handler func(*buildv1.CompilerRemark) error) error {
+	stream, err := c.service.StreamRemarks(c.withAuth(ctx), &buildv1.StreamRemarksRequest{BuildId: buildID})
+	if err != nil {
+		return err
+	}
+
+	for {
+		remark, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := handler(remark); err != nil {
+			return err
+		}
+	}
+}
+
+// Watch streams new builds to handler, reconnecting as needed
+func (c *Client) Watch(ctx context.Context, filter string, handler BuildHandler) error {
+	return WatchBuilds(c.withAuth(ctx), c.service, filter, handler)
+}
+
+// withAuth attaches the bearer token to outgoing requests
+func (c *Client) withAuth(ctx context.Context) context.Context {
+	if c.token == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+c.token)
+}
+
+// retry runs call until it succeeds, fails permanently, or runs out of attempts
+func (c *Client) retry(ctx context.Context, call func(context.Context) error) error {
+	ctx = c.withAuth(ctx)
+	backoff := initialBackoff
+
+	var err error
+	for attempt := 1; attempt <= c.maxAttempts; attempt++ {
+		if err = call(ctx); err == nil {
+			return nil
+		}
+
+		if status.Code(err) != codes.Unavailable || attempt == c.maxAttempts {
+			break
+		}
+
+		log.Printf("Request failed: %v (retrying in %s)", err, backoff)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+	return err
+}
+
+// retryWrite retries a write to the build id. An attempt whose reply was
+// lost may still have been applied, so when a retry fails with applied,
+// the code the server gives for a write already made, the stored build is
+// fetched and returned instead.
+func (c *Client) retryWrite(ctx context.Context, id string, applied codes.Code, call func(context.Context) (*buildv1.Build, error)) (*buildv1.Build, error) {
+	var resp *buildv1.Build
+	retried := false
+	err := c.retry(ctx, func(ctx context.Context) (err error) {
+		resp, err = call(ctx)
+		if retried && status.Code(err) == applied {
+			resp, err = c.service.GetBuild(ctx, &buildv1.GetBuildRequest{Id: id})
+		}
+		retried = true
+		return err
+	})
+	return resp, err
+}
@@ -0,0 +1,323 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	buildv1 "builds/api/build"
+	grpcutil "builds/internal/utils/grpcutil"
+)
+
+// fakeServer keeps builds in memory, in the order they were created
+type fakeServer struct {
+	buildv1.UnimplementedBuildServiceServer
+
+	mu          sync.Mutex
+	builds      []*buildv1.Build
+	calls       int
+	unavailable int      // calls to fail with Unavailable before answering
+	lost        int      // writes to apply but answer with Unavailable, as if the reply were dropped
+	auth        []string // authorization metadata of each call
+}
+
+func (s *fakeServer) call(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	md, _ := metadata.FromIncomingContext(ctx)
+	s.auth = append(s.auth, md.Get("authorization")...)
+	if s.unavailable > 0 {
+		s.unavailable--
+		return status.Error(codes.Unavailable, "try again")
+	}
+	return nil
+}
+
+func (s *fakeServer) find(id string) int {
+	for i, build := range s.builds {
+		if build.Id == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *fakeServer) CreateBuild(ctx context.Context, req *buildv1.CreateBuildRequest) (*buildv1.Build, error) {
+	if err := s.call(ctx); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.find(req.Build.Id) >= 0 {
+		return nil, status.Error(codes.AlreadyExists, "build already exists")
+	}
+	s.builds = append(s.builds, req.Build)
+	return req.Build, s.lose()
+}
+
+// lose fails an applied write while writes remain to be lost. The caller
+// holds s.mu.
+func (s *fakeServer) lose() error {
+	if s.lost > 0 {
+		s.lost--
+		return status.Error(codes.Unavailable, "reply lost")
+	}
+	return nil
+}
+
+func (s *fakeServer) GetBuild(ctx context.Context, req *buildv1.GetBuildRequest) (*buildv1.Build, error) {
+	if err := s.call(ctx); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if i := s.find(req.Id); i >= 0 {
+		return s.builds[i], nil
+	}
+	return nil, status.Error(codes.NotFound, "build not found")
+}
+
+func (s *fakeServer) ListBuilds(ctx context.Context, req *buildv1.ListBuildsRequest) (*buildv1.ListBuildsResponse, error) {
+	if err := s.call(ctx); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	start, _ := strconv.Atoi(req.PageToken)
+	end := min(start+int(req.PageSize), len(s.builds))
+	resp := &buildv1.ListBuildsResponse{Builds: s.builds[start:end]}
+	if end < len(s.builds) {
+		resp.NextPageToken = strconv.Itoa(end)
+	}
+	return resp, nil
+}
+
+func (s *fakeServer) DeleteBuild(ctx context.Context, req *buildv1.DeleteBuildRequest) (*emptypb.Empty, error) {
+	if err := s.call(ctx); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.find(req.Id)
+	if i < 0 {
+		return nil, status.Error(codes.NotFound, "build not found")
+	}
+	s.builds = append(s.builds[:i], s.builds[i+1:]...)
+	return &emptypb.Empty{}, nil
+}
+
+func (s *fakeServer) StreamBuilds(req *buildv1.StreamBuildsRequest, stream grpc.ServerStreamingServer[buildv1.Build]) error {
+	s.mu.Lock()
+	builds := append([]*buildv1.Build(nil), s.builds...)
+	s.mu.Unlock()
+	for _, build := range builds {
+		if err := stream.Send(build); err != nil {
+			return err
+		}
+	}
+	<-stream.Context().Done()
+	return nil
+}
+
+// newTestClient serves fake over an in-process connection
+func newTestClient(t *testing.T, fake *fakeServer) *Client {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	buildv1.RegisterBuildServiceServer(server, fake)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(grpcutil.MaxMessageSize)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewFromService(buildv1.NewBuildServiceClient(conn))
+	c.conn = conn
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func TestClientCreateGetDelete(t *testing.T) {
+	fake := &fakeServer{}
+	c := newTestClient(t, fake)
+	c.token = "secret"
+	ctx := context.Background()
+
+	if _, err := c.Create(ctx, &buildv1.Build{Id: "b1"}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := c.Get(ctx, "b1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Id != "b1" {
+		t.Errorf("Get returned %q, want b1", got.Id)
+	}
+
+	if err := c.Delete(ctx, "b1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Get(ctx, "b1"); status.Code(err) != codes.NotFound {
+		t.Errorf("Get after Delete: %v, want NotFound", err)
+	}
+
+	for _, auth := range fake.auth {
+		if auth != "Bearer secret" {
+			t.Errorf("authorization %q, want Bearer secret", auth)
+		}
+	}
+	if len(fake.auth) != 4 {
+		t.Errorf("server saw %d tokens, want 4", len(fake.auth))
+	}
+}
+
+func TestClientRetry(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxAttempts int
+		unavailable int
+		wantCode    codes.Code
+		wantCalls   int
+	}{
+		{"succeeds after a transient failure", 2, 1, codes.OK, 2},
+		{"gives up after max attempts", 1, 1, codes.Unavailable, 1},
+		{"does not retry permanent failures", 3, 0, codes.NotFound, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeServer{
+				builds:      []*buildv1.Build{{Id: "b1"}},
+				unavailable: tt.unavailable,
+			}
+			c := newTestClient(t, fake)
+			c.maxAttempts = tt.maxAttempts
+
+			id := "b1"
+			if tt.wantCode == codes.NotFound {
+				id = "missing"
+			}
+			_, err := c.Get(context.Background(), id)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Errorf("Get: %v, want %s", err, tt.wantCode)
+			}
+			if fake.calls != tt.wantCalls {
+				t.Errorf("server saw %d calls, want %d", fake.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestClientRetriedWrites(t *testing.T) {
+	ctx := context.Background()
+
+	// The first reply is lost after the build was stored, so the retry finds
+	// it already there
+	fake := &fakeServer{lost: 1}
+	c := newTestClient(t, fake)
+	c.maxAttempts = 2
+	got, err := c.Create(ctx, &buildv1.Build{Id: "b1", Success: true})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if got.GetId() != "b1" || !got.Success {
+		t.Errorf("Create returned %v, want the stored build", got)
+	}
+
+	// Likewise for a finalize that was applied
+	if _, err := c.Begin(ctx, &buildv1.Build{Id: "b2"}); err != nil {
+		t.Fatal(err)
+	}
+	fake.lost = 1
+	got, err = c.Finalize(ctx, &buildv1.Build{Id: "b2", Success: true})
+	if err != nil {
+		t.Fatalf("Finalize: %v", err)
+	}
+	if got.GetId() != "b2" || !got.Success || got.InProgress {
+		t.Errorf("Finalize returned %v, want the finalized build", got)
+	}
+
+	// Without a retry the conflicts are the caller's own
+	if _, err := c.Create(ctx, &buildv1.Build{Id: "b1"}); status.Code(err) != codes.AlreadyExists {
+		t.Errorf("Create of a stored build: %v, want AlreadyExists", err)
+	}
+	if _, err := c.Finalize(ctx, &buildv1.Build{Id: "b2"}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Finalize of a finalized build: %v, want FailedPrecondition", err)
+	}
+}
+
+func TestClientSearch(t *testing.T) {
+	fake := &fakeServer{}
+	for i := range 5 {
+		fake.builds = append(fake.builds, &buildv1.Build{Id: strconv.Itoa(i)})
+	}
+	c := newTestClient(t, fake)
+
+	tests := []struct {
+		name      string
+		all       bool
+		wantIDs   int
+		wantToken string
+	}{
+		{"first page", false, 2, "2"},
+		{"all pages", true, 5, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ids []string
+			token, err := c.Search(context.Background(), &buildv1.ListBuildsRequest{PageSize: 2}, tt.all, func(builds []*buildv1.Build) error {
+				for _, build := range builds {
+					ids = append(ids, build.Id)
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(ids) != tt.wantIDs || token != tt.wantToken {
+				t.Errorf("got %v and token %q, want %d builds and token %q", ids, token, tt.wantIDs, tt.wantToken)
+			}
+		})
+	}
+}
+
+func TestClientWatch(t *testing.T) {
+	fake := &fakeServer{builds: []*buildv1.Build{{Id: "b1"}, {Id: "b2"}}}
+	c := newTestClient(t, fake)
+
+	stop := errors.New("stop")
+	var ids []string
+	err := c.Watch(context.Background(), "", func(build *buildv1.Build) error {
+		ids = append(ids, build.Id)
+		if len(ids) == 2 {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) {
+		t.Fatalf("Watch returned %v, want the handler's error", err)
+	}
+	if ids[0] != "b1" || ids[1] != "b2" {
+		t.Errorf("watched %v, want [b1 b2]", ids)
+	}
+}
@@ -0,0 +1,744 @@
+// internal/client/convert.go
+
+package client
+
+import (
+	"log"
+	"strings"
+
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	buildv1 "builds/api/build"
+	"builds/internal/models"
+)
+
+// EnvironmentToProto converts collected environment data
+func EnvironmentToProto(env models.Environment) *buildv1.Environment {
+	variables := make(map[string]string, len(env.Variables))
+	for k, v := range env.Variables {
+		variables[k] = v
+	}
+
+	return &buildv1.Environment{
+		Os:         env.OS,
+		Arch:       env.Arch,
+		WorkingDir: env.WorkingDir,
+		Variables:  variables,
+		Locale:     env.Locale,
+		Timezone:   env.Timezone,
+		Umask:      env.Umask,
+	}
+}
+
+// VersionControlToProto converts collected git repository data
+func VersionControlToProto(vc models.VersionControl) *buildv1.VersionControl {
+	return &buildv1.VersionControl{
+		Commit:    vc.Commit,
+		Branch:    vc.Branch,
+		Dirty:     vc.Dirty,
+		RemoteUrl: vc.RemoteURL,
+	}
+}
+
+// HardwareToProto converts collected hardware data
+func HardwareToProto(hw models.Hardware) *buildv1.Hardware {
+	gpus := make([]*buildv1.GPU, len(hw.GPUs))
+	for i, gpu := range hw.GPUs {
+		gpus[i] = &buildv1.GPU{
+			Model:       gpu.Model,
+			Memory:      gpu.Memory,
+			Driver:      gpu.Driver,
+			ComputeCaps: gpu.ComputeCaps,
+		}
+	}
+
+	return &buildv1.Hardware{
+		Cpu: &buildv1.CPU{
+			Model:     hw.CPU.Model,
+			Vendor:    hw.CPU.Vendor,
+			Cores:     hw.CPU.Cores,
+			Threads:   hw.CPU.Threads,
+			Frequency: hw.CPU.Frequency,
+			CacheSize: hw.CPU.CacheSize,
+			NumaNodes: hw.CPU.NUMANodes,
+		},
+		Memory: &buildv1.Memory{
+			Total:     hw.Memory.Total,
+			Available: hw.Memory.Available,
+			Used:      hw.Memory.Used,
+			SwapTotal: hw.Memory.SwapTotal,
+			SwapFree:  hw.Memory.SwapFree,
+		},
+		Gpus: gpus,
+	}
+}
+
+// CompilerToProto converts collected compiler data
+func CompilerToProto(comp models.Compiler) *buildv1.Compiler {
+	return &buildv1.Compiler{
+		Name:          comp.Name,
+		Version:       comp.Version,
+		VersionString: comp.VersionString,
+		Wrapper:       comp.Wrapper,
+		Target:        comp.Target,
+		Triple: &buildv1.TargetTriple{
+			Arch:   comp.Triple.Arch,
+			Vendor: comp.Triple.Vendor,
+			Os:     comp.Triple.OS,
+			Abi:    comp.Triple.ABI,
+		},
+		Language: &buildv1.Language{
+			Name:          comp.Language.Name,
+			Version:       comp.Language.Version,
+			Specification: comp.Language.Specification,
+		},
+		Features: &buildv1.CompilerFeatures{
+			SupportsOpenmp: comp.Features.SupportsOpenMP,
+			SupportsGpu:    comp.Features.SupportsGPU,
+			SupportsLto:    comp.Features.SupportsLTO,
+			SupportsPgo:    comp.Features.SupportsPGO,
+			Extensions:     comp.Features.Extensions,
+		},
+		Options:       comp.Options,
+		Optimizations: comp.Optimizations,
+		Flags:         comp.Flags,
+	}
+}
+
+// ResourceUsageToProto converts collected resource usage
+func ResourceUsageToProto(res models.ResourceUsage) *buildv1.ResourceUsage {
+	return &buildv1.ResourceUsage{
+		MaxMemory: res.MaxMemory,
+		CpuTime:   res.CPUTime,
+		Threads:   res.Threads,
+		Io: &buildv1.IOStats{
+			ReadBytes:  res.IO.ReadBytes,
+			WriteBytes: res.IO.WriteBytes,
+			ReadCount:  res.IO.ReadCount,
+			WriteCount: res.IO.WriteCount,
+		},
+		PhaseMemory: res.PhaseMemory,
+	}
+}
+
+// MetricsToProto converts measured build metrics
+func MetricsToProto(m models.BuildMetrics) *buildv1.BuildMetrics {
+	return &buildv1.BuildMetrics{
+		TotalFiles:     m.TotalFiles,
+		ProcessedFiles: m.ProcessedFiles,
+		Warnings:       m.Warnings,
+		Errors:         m.Errors,
+		InputSize:      m.InputSize,
+		OutputSize:     m.OutputSize,
+		Metrics:        m.Metrics,
+		TextSize:       m.TextSize,
+		DataSize:       m.DataSize,
+		BssSize:        m.BSSSize,
+		SectionSizes:   m.SectionSizes,
+	}
+}
+
+// LinkMapToProto converts a parsed linker map
+func LinkMapToProto(m models.LinkMap) *buildv1.LinkMap {
+	pb := &buildv1.LinkMap{
+		Sections:          make([]*buildv1.LinkSection, len(m.Sections)),
+		Symbols:           make([]*buildv1.LinkSymbol, len(m.Symbols)),
+		SymbolsSeen:       m.SymbolsSeen,
+		DiscardedSections: int32(m.DiscardedSections),
+		DiscardedSize:     m.DiscardedSize,
+	}
+	for i, section := range m.Sections {
+		pb.Sections[i] = &buildv1.LinkSection{Name: section.Name, Size: section.Size}
+	}
+	for i, sym := range m.Symbols {
+		pb.Symbols[i] = &buildv1.LinkSymbol{
+			Name:    sym.Name,
+			Section: sym.Section,
+			Object:  sym.Object,
+			Size:    sym.Size,
+		}
+	}
+	return pb
+}
+
+// CommandToProto converts the compiler command and its sub-invocations
+func CommandToProto(cmd models.Command) *buildv1.Command {
+	pbCmd := &buildv1.Command{
+		Executable: cmd.Executable,
+		Arguments:  cmd.Arguments,
+		WorkingDir: cmd.WorkingDir,
+		Env:        cmd.Env,
+	}
+	for _, inv := range cmd.Invocations {
+		pbCmd.Invocations = append(pbCmd.Invocations, &buildv1.Invocation{
+			Tool:      inv.Tool,
+			Arguments: inv.Arguments,
+		})
+	}
+	return pbCmd
+}
+
+// DiagnosticsToProto converts the diagnostics parsed from compiler output
+func DiagnosticsToProto(diagnostics []models.Diagnostic) []*buildv1.Diagnostic {
+	pbDiagnostics := make([]*buildv1.Diagnostic, len(diagnostics))
+	for i, d := range diagnostics {
+		pbDiagnostics[i] = &buildv1.Diagnostic{
+			Severity: d.Severity,
+			File:     d.File,
+			Line:     d.Line,
+			Col:      d.Col,
+			Message:  d.Message,
+		}
+	}
+	return pbDiagnostics
+}
+
+// RemarksToProto converts parsed compiler remarks
+func RemarksToProto(remarks []models.CompilerRemark) []*buildv1.CompilerRemark {
+	pbRemarks := make([]*buildv1.CompilerRemark, len(remarks))
+
+	for i, remark := range remarks {
+		pbRemark := &buildv1.CompilerRemark{
+			Id:        remark.ID,
+			Name:      remark.Name,
+			PassName:  remark.Pass,
+			Message:   remark.Message,
+			Function:  remark.Function,
+			Timestamp: timestamppb.New(remark.Timestamp),
+			Location:  locationToProto(remark.Location),
+			Args:      remarkArgsToProto(remark.Args),
+			Hotness:   remark.Hotness,
+		}
+
+		// Convert type
+		switch strings.ToLower(string(remark.Type)) {
+		case "optimization":
+			pbRemark.Type = buildv1.CompilerRemark_OPTIMIZATION
+		case "kernel":
+			pbRemark.Type = buildv1.CompilerRemark_KERNEL
+		case "analysis":
+			pbRemark.Type = buildv1.CompilerRemark_ANALYSIS
+		case "metric":
+			pbRemark.Type = buildv1.CompilerRemark_METRIC
+		default:
+			pbRemark.Type = buildv1.CompilerRemark_INFO
+		}
+
+		// Convert pass
+		switch strings.ToLower(string(remark.Pass)) {
+		case "vectorization":
+			pbRemark.Pass = buildv1.CompilerRemark_VECTORIZATION
+		case "inlining":
+			pbRemark.Pass = buildv1.CompilerRemark_INLINING
+		case "kernel-info":
+			pbRemark.Pass = buildv1.CompilerRemark_KERNEL_INFO
+		case "size-info":
+			pbRemark.Pass = buildv1.CompilerRemark_SIZE_INFO
+		default:
+			pbRemark.Pass = buildv1.CompilerRemark_PASS_ANALYSIS
+		}
+
+		// Convert status
+		switch strings.ToLower(string(remark.Status)) {
+		case "passed":
+			pbRemark.Status = buildv1.CompilerRemark_PASSED
+		case "missed":
+			pbRemark.Status = buildv1.CompilerRemark_MISSED
+		case "analysis":
+			pbRemark.Status = buildv1.CompilerRemark_STATUS_ANALYSIS
+		default:
+			pbRemark.Status = buildv1.CompilerRemark_PASSED
+		}
+
+		// Convert kernel info if present
+		if remark.KernelInfo != nil {
+			memAccesses := make([]*buildv1.MemoryAccess, len(remark.KernelInfo.MemoryAccesses))
+			for j, acc := range remark.KernelInfo.MemoryAccesses {
+				memAccesses[j] = &buildv1.MemoryAccess{
+					Type:          acc.Type,
+					AddressSpace:  acc.AddressSpace,
+					Instruction:   acc.Instruction,
+					Variable:      acc.Variable,
+					AccessPattern: acc.AccessPattern,
+				}
+			}
+
+			pbRemark.KernelInfo = &buildv1.KernelInfo{
+				ThreadLimit:              remark.KernelInfo.ThreadLimit,
+				MaxThreadsX:              remark.KernelInfo.MaxThreadsX,
+				MaxThreadsY:              remark.KernelInfo.MaxThreadsY,
+				MaxThreadsZ:              remark.KernelInfo.MaxThreadsZ,
+				SharedMemory:             remark.KernelInfo.SharedMemory,
+				Target:                   remark.KernelInfo.Target,
+				DirectCalls:              remark.KernelInfo.DirectCalls,
+				IndirectCalls:            remark.KernelInfo.IndirectCalls,
+				Callees:                  remark.KernelInfo.Callees,
+				AllocasCount:             remark.KernelInfo.AllocasCount,
+				AllocasStaticSize:        remark.KernelInfo.AllocasStaticSize,
+				AllocasDynamicCount:      remark.KernelInfo.AllocasDynamicCount,
+				FlatAddressSpaceAccesses: remark.KernelInfo.FlatAddressSpaceAccesses,
+				InlineAssemblyCalls:      remark.KernelInfo.InlineAssemblyCalls,
+				MemoryAccesses:           memAccesses,
+				Metrics:                  remark.KernelInfo.Metrics,
+				Attributes:               remark.KernelInfo.Attributes,
+			}
+		}
+
+		// Convert metadata
+		if len(remark.Metadata) > 0 {
+			metadata, err := structpb.NewStruct(map[string]interface{}(remark.Metadata))
+			if err == nil {
+				pbRemark.Metadata = metadata
+			} else {
+				log.Printf("Warning: Failed to convert metadata for remark: %v", err)
+			}
+		}
+
+		pbRemarks[i] = pbRemark
+	}
+
+	return pbRemarks
+}
+
+// BuildToModel converts a build received from the server into the
+// internal model used by the analyzers and reporters
+func BuildToModel(pb *buildv1.Build) *models.Build {
+	if pb == nil {
+		return nil
+	}
+
+	build := &models.Build{
+		ID:      pb.Id,
+		Success: pb.Success,
+		Error:   pb.Error,
+		Labels:  pb.Labels,
+
+		RemarksTruncated: pb.RemarksTruncated,
+		RemarksSeen:      pb.RemarksSeen,
+		RemarksPurged:    pb.RemarksPurged,
+		ReportedDuration: pb.ReportedDuration,
+		CacheStatus:      pb.CacheStatus,
+		RawTruncated:     pb.RawRemarksTruncated,
+	}
+
+	// Handle timestamps safely
+	if pb.StartTime != nil {
+		build.StartTime = pb.StartTime.AsTime()
+	}
+	if pb.EndTime != nil {
+		build.EndTime = pb.EndTime.AsTime()
+	}
+	build.Duration = pb.Duration
+	if pb.ClientStartTime != nil {
+		t := pb.ClientStartTime.AsTime()
+		build.ClientStartTime = &t
+	}
+	if pb.ClientEndTime != nil {
+		t := pb.ClientEndTime.AsTime()
+		build.ClientEndTime = &t
+	}
+
+	// Convert Environment
+	if pb.Environment != nil {
+		build.Environment = models.Environment{
+			OS:         pb.Environment.Os,
+			Arch:       pb.Environment.Arch,
+			WorkingDir: pb.Environment.WorkingDir,
+			Variables:  pb.Environment.Variables,
+			Locale:     pb.Environment.Locale,
+			Timezone:   pb.Environment.Timezone,
+			Umask:      pb.Environment.Umask,
+		}
+	}
+
+	if vc := pb.VersionControl; vc != nil {
+		build.VersionControl = &models.VersionControl{
+			Commit:    vc.Commit,
+			Branch:    vc.Branch,
+			Dirty:     vc.Dirty,
+			RemoteURL: vc.RemoteUrl,
+		}
+	}
+
+	// Convert Hardware
+	if pb.Hardware != nil && pb.Hardware.Cpu != nil && pb.Hardware.Memory != nil {
+		build.Hardware = models.Hardware{
+			CPU: models.CPU{
+				Model:     pb.Hardware.Cpu.Model,
+				Frequency: pb.Hardware.Cpu.Frequency,
+				Cores:     pb.Hardware.Cpu.Cores,
+				Threads:   pb.Hardware.Cpu.Threads,
+				Vendor:    pb.Hardware.Cpu.Vendor,
+				CacheSize: pb.Hardware.Cpu.CacheSize,
+				NUMANodes: pb.Hardware.Cpu.NumaNodes,
+			},
+			Memory: models.Memory{
+				Total:     pb.Hardware.Memory.Total,
+				Available: pb.Hardware.Memory.Available,
+				Used:      pb.Hardware.Memory.Used,
+				SwapTotal: pb.Hardware.Memory.SwapTotal,
+				SwapFree:  pb.Hardware.Memory.SwapFree,
+			},
+		}
+
+		// Handle GPUs safely
+		if pb.Hardware.Gpus != nil {
+			build.Hardware.GPUs = make([]models.GPU, len(pb.Hardware.Gpus))
+			for i, gpu := range pb.Hardware.Gpus {
+				if gpu != nil {
+					build.Hardware.GPUs[i] = models.GPU{
+						Model:       gpu.Model,
+						Memory:      gpu.Memory,
+						Driver:      gpu.Driver,
+						ComputeCaps: gpu.ComputeCaps,
+					}
+				}
+			}
+		}
+	}
+
+	// Convert compiler
+	if comp := pb.Compiler; comp != nil {
+		build.Compiler = models.Compiler{
+			Name:          comp.Name,
+			Version:       comp.Version,
+			VersionString: comp.VersionString,
+			Wrapper:       comp.Wrapper,
+			Target:        comp.Target,
+			Triple: models.TargetTriple{
+				Arch:   comp.GetTriple().GetArch(),
+				Vendor: comp.GetTriple().GetVendor(),
+				OS:     comp.GetTriple().GetOs(),
+				ABI:    comp.GetTriple().GetAbi(),
+			},
+			Options:       comp.Options,
+			Optimizations: comp.Optimizations,
+			Flags:         comp.Flags,
+		}
+		if comp.Language != nil {
+			build.Compiler.Language = models.Language{
+				Name:          comp.Language.Name,
+				Version:       comp.Language.Version,
+				Specification: comp.Language.Specification,
+			}
+		}
+		if comp.Features != nil {
+			build.Compiler.Features = models.CompilerFeatures{
+				SupportsOpenMP: comp.Features.SupportsOpenmp,
+				SupportsGPU:    comp.Features.SupportsGpu,
+				SupportsLTO:    comp.Features.SupportsLto,
+				SupportsPGO:    comp.Features.SupportsPgo,
+				Extensions:     comp.Features.Extensions,
+			}
+		}
+	}
+
+	// Convert resource usage
+	if usage := pb.ResourceUsage; usage != nil {
+		build.ResourceUsage = models.ResourceUsage{
+			MaxMemory:   usage.MaxMemory,
+			CPUTime:     usage.CpuTime,
+			Threads:     usage.Threads,
+			PhaseMemory: usage.PhaseMemory,
+		}
+		if usage.Io != nil {
+			build.ResourceUsage.IO = models.IOStats{
+				ReadBytes:  usage.Io.ReadBytes,
+				WriteBytes: usage.Io.WriteBytes,
+				ReadCount:  usage.Io.ReadCount,
+				WriteCount: usage.Io.WriteCount,
+			}
+		}
+	}
+
+	// Convert metrics
+	if m := pb.Metrics; m != nil {
+		build.Metrics = models.BuildMetrics{
+			TotalFiles:     m.TotalFiles,
+			ProcessedFiles: m.ProcessedFiles,
+			Warnings:       m.Warnings,
+			Errors:         m.Errors,
+			InputSize:      m.InputSize,
+			OutputSize:     m.OutputSize,
+			Metrics:        m.Metrics,
+			TextSize:       m.TextSize,
+			DataSize:       m.DataSize,
+			BSSSize:        m.BssSize,
+			SectionSizes:   m.SectionSizes,
+		}
+	}
+
+	// Convert linker map
+	if m := pb.LinkMap; m != nil {
+		build.LinkMap = &models.LinkMap{
+			Sections:          make([]models.LinkSection, len(m.Sections)),
+			Symbols:           make([]models.LinkSymbol, len(m.Symbols)),
+			SymbolsSeen:       m.SymbolsSeen,
+			DiscardedSections: int(m.DiscardedSections),
+			DiscardedSize:     m.DiscardedSize,
+		}
+		for i, section := range m.Sections {
+			build.LinkMap.Sections[i] = models.LinkSection{Name: section.Name, Size: section.Size}
+		}
+		for i, sym := range m.Symbols {
+			build.LinkMap.Symbols[i] = models.LinkSymbol{
+				Name:    sym.Name,
+				Section: sym.Section,
+				Object:  sym.Object,
+				Size:    sym.Size,
+			}
+		}
+	}
+
+	// Convert performance
+	if perf := pb.Performance; perf != nil {
+		build.Performance = models.Performance{
+			CompileTime:  perf.CompileTime,
+			LinkTime:     perf.LinkTime,
+			OptimizeTime: perf.OptimizeTime,
+			Phases:       perf.Phases,
+		}
+	}
+
+	// Convert command
+	if cmd := pb.Command; cmd != nil {
+		build.Command = models.Command{
+			Executable: cmd.Executable,
+			Arguments:  cmd.Arguments,
+			WorkingDir: cmd.WorkingDir,
+			Env:        cmd.Env,
+			Hash:       cmd.Hash,
+		}
+		for _, inv := range cmd.Invocations {
+			build.Command.Invocations = append(build.Command.Invocations, models.Invocation{
+				Tool:      inv.Tool,
+				Arguments: inv.Arguments,
+			})
+		}
+	}
+
+	// Convert output
+	if output := pb.Output; output != nil {
+		build.Output = models.Output{
+			Stdout:    output.Stdout,
+			Stderr:    output.Stderr,
+			ExitCode:  output.ExitCode,
+			Truncated: output.Truncated,
+		}
+		for _, artifact := range output.Artifacts {
+			build.Output.Artifacts = append(build.Output.Artifacts, models.Artifact{
+				Path: artifact.Path,
+				Type: artifact.Type,
+				Size: artifact.Size,
+				Hash: artifact.Hash,
+			})
+		}
+		for _, d := range output.Diagnostics {
+			build.Output.Diagnostics = append(build.Output.Diagnostics, models.Diagnostic{
+				Severity: d.Severity,
+				File:     d.File,
+				Line:     d.Line,
+				Col:      d.Col,
+				Message:  d.Message,
+			})
+		}
+	}
+
+	// Convert Remarks
+	if pb.Remarks != nil {
+		build.Remarks = make([]models.CompilerRemark, 0, len(pb.Remarks))
+		for _, remark := range pb.Remarks {
+			if remark == nil {
+				continue
+			}
+
+			modelRemark := models.CompilerRemark{
+				ID:       remark.Id,
+				Type:     strings.ToLower(remark.Type.String()),
+				Pass:     remark.PassName,
+				Status:   strings.ToLower(remark.Status.String()),
+				Name:     remark.Name,
+				Message:  remark.Message,
+				Function: remark.Function,
+				Hotness:  remark.Hotness,
+				Args:     remarkArgsFromProto(remark.Args),
+			}
+			if modelRemark.Pass == "" {
+				// Stored by a server predating pass names; only the category is known
+				modelRemark.Pass = strings.ToLower(remark.Pass.String())
+			}
+
+			if remark.Timestamp != nil {
+				modelRemark.Timestamp = remark.Timestamp.AsTime()
+			}
+
+			modelRemark.Location = locationFromProto(remark.Location)
+
+			// Handle KernelInfo
+			if remark.KernelInfo != nil {
+				modelRemark.KernelInfo = &models.KernelInfo{
+					ThreadLimit:              remark.KernelInfo.ThreadLimit,
+					MaxThreadsX:              remark.KernelInfo.MaxThreadsX,
+					MaxThreadsY:              remark.KernelInfo.MaxThreadsY,
+					MaxThreadsZ:              remark.KernelInfo.MaxThreadsZ,
+					SharedMemory:             remark.KernelInfo.SharedMemory,
+					Target:                   remark.KernelInfo.Target,
+					DirectCalls:              remark.KernelInfo.DirectCalls,
+					IndirectCalls:            remark.KernelInfo.IndirectCalls,
+					Callees:                  remark.KernelInfo.Callees,
+					AllocasCount:             remark.KernelInfo.AllocasCount,
+					AllocasStaticSize:        remark.KernelInfo.AllocasStaticSize,
+					AllocasDynamicCount:      remark.KernelInfo.AllocasDynamicCount,
+					FlatAddressSpaceAccesses: remark.KernelInfo.FlatAddressSpaceAccesses,
+					InlineAssemblyCalls:      remark.KernelInfo.InlineAssemblyCalls,
+					Metrics:                  make(map[string]int64),
+					Attributes:               make(map[string]string),
+				}
+
+				// Copy metrics
+				if remark.KernelInfo.Metrics != nil {
+					for k, v := range remark.KernelInfo.Metrics {
+						modelRemark.KernelInfo.Metrics[k] = v
+					}
+				}
+
+				// Copy attributes
+				if remark.KernelInfo.Attributes != nil {
+					for k, v := range remark.KernelInfo.Attributes {
+						modelRemark.KernelInfo.Attributes[k] = v
+					}
+				}
+
+				// Handle memory accesses
+				if remark.KernelInfo.MemoryAccesses != nil {
+					modelRemark.KernelInfo.MemoryAccesses = make([]models.MemoryAccess, len(remark.KernelInfo.MemoryAccesses))
+					for i, acc := range remark.KernelInfo.MemoryAccesses {
+						if acc != nil {
+							modelRemark.KernelInfo.MemoryAccesses[i] = models.MemoryAccess{
+								Type:          acc.Type,
+								AddressSpace:  acc.AddressSpace,
+								Instruction:   acc.Instruction,
+								Variable:      acc.Variable,
+								AccessPattern: acc.AccessPattern,
+							}
+						}
+					}
+				}
+			}
+
+			// Handle metadata
+			if remark.Metadata != nil {
+				modelRemark.Metadata = remark.Metadata.AsMap()
+			}
+
+			build.Remarks = append(build.Remarks, modelRemark)
+		}
+	}
+
+	for _, count := range pb.RemarkCounts {
+		build.RemarkCounts = append(build.RemarkCounts, models.RemarkCount{
+			Pass:   count.Pass,
+			Status: strings.ToLower(count.Status.String()),
+			Count:  count.Count,
+		})
+	}
+
+	return build
+}
+
+func locationToProto(loc models.Location) *buildv1.Location {
+	return &buildv1.Location{
+		File:     loc.File,
+		Line:     loc.Line,
+		Column:   loc.Column,
+		Function: loc.Function,
+		Region:   loc.Region,
+		Artifact: loc.Artifact,
+	}
+}
+
+func locationFromProto(loc *buildv1.Location) models.Location {
+	if loc == nil {
+		return models.Location{}
+	}
+	return models.Location{
+		File:     loc.File,
+		Line:     loc.Line,
+		Column:   loc.Column,
+		Function: loc.Function,
+		Region:   loc.Region,
+		Artifact: loc.Artifact,
+	}
+}
+
+// remarkArgsToProto converts the structured arguments of a remark
+func remarkArgsToProto(args models.RemarkArgs) *buildv1.RemarkArgs {
+	pb := &buildv1.RemarkArgs{
+		Strings: args.Strings,
+		Callee:  args.Callee,
+		Caller:  args.Caller,
+		Type:    args.Type,
+		Line:    args.Line,
+		Column:  args.Column,
+		Cost:    args.Cost,
+		Reason:  args.Reason,
+		Values:  args.Values,
+	}
+	if args.DebugLoc != nil {
+		pb.DebugLoc = locationToProto(*args.DebugLoc)
+	}
+	if args.OtherAccess != nil {
+		pb.OtherAccess = remarkAccessToProto(args.OtherAccess)
+	}
+	if args.ClobberedBy != nil {
+		pb.ClobberedBy = remarkAccessToProto(args.ClobberedBy)
+	}
+	return pb
+}
+
+func remarkAccessToProto(access *models.RemarkAccess) *buildv1.RemarkAccess {
+	pb := &buildv1.RemarkAccess{Type: access.Type}
+	if access.DebugLoc != nil {
+		pb.DebugLoc = locationToProto(*access.DebugLoc)
+	}
+	return pb
+}
+
+// remarkArgsFromProto converts received remark arguments
+func remarkArgsFromProto(pb *buildv1.RemarkArgs) models.RemarkArgs {
+	if pb == nil {
+		return models.RemarkArgs{}
+	}
+	args := models.RemarkArgs{
+		Strings: pb.Strings,
+		Callee:  pb.Callee,
+		Caller:  pb.Caller,
+		Type:    pb.Type,
+		Line:    pb.Line,
+		Column:  pb.Column,
+		Cost:    pb.Cost,
+		Reason:  pb.Reason,
+		Values:  pb.Values,
+	}
+	if pb.DebugLoc != nil {
+		loc := locationFromProto(pb.DebugLoc)
+		args.DebugLoc = &loc
+	}
+	if pb.OtherAccess != nil {
+		args.OtherAccess = remarkAccessFromProto(pb.OtherAccess)
+	}
+	if pb.ClobberedBy != nil {
+		args.ClobberedBy = remarkAccessFromProto(pb.ClobberedBy)
+	}
+	return args
+}
+
+func remarkAccessFromProto(pb *buildv1.RemarkAccess) *models.RemarkAccess {
+	access := &models.RemarkAccess{Type: pb.Type}
+	if pb.DebugLoc != nil {
+		loc := locationFromProto(pb.DebugLoc)
+		access.DebugLoc = &loc
+	}
+	return access
+}
@@ -0,0 +1,62 @@
+package client
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	buildv1 "builds/api/build"
+	"builds/internal/models"
+)
+
+func TestRemarkRoundTrip(t *testing.T) {
+	remark := models.CompilerRemark{
+		ID:        "0123456789abcdef",
+		Type:      "optimization",
+		Pass:      "loop-vectorize",
+		Status:    "missed",
+		Name:      "MissedDetails",
+		Message:   "loop not vectorized",
+		Function:  "foo",
+		Timestamp: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Location: models.Location{
+			File:     "foo.c",
+			Line:     12,
+			Column:   3,
+			Function: "foo",
+			Artifact: "foo.lto.opt.yaml",
+		},
+		Args: models.RemarkArgs{
+			Strings:  []string{"loop not vectorized"},
+			Callee:   "bar",
+			Reason:   "unsafe dependent memory operations",
+			DebugLoc: &models.Location{File: "foo.c", Line: 14},
+			OtherAccess: &models.RemarkAccess{
+				Type:     "load",
+				DebugLoc: &models.Location{File: "foo.c", Line: 15},
+			},
+			ClobberedBy: &models.RemarkAccess{Type: "store"},
+			Values:      map[string]string{"VectorizationFactor": "4"},
+		},
+		Hotness: 120,
+	}
+
+	build := BuildToModel(&buildv1.Build{Remarks: RemarksToProto([]models.CompilerRemark{remark})})
+	if len(build.Remarks) != 1 {
+		t.Fatalf("got %d remarks, want 1", len(build.Remarks))
+	}
+	got := build.Remarks[0]
+	if !reflect.DeepEqual(got, remark) {
+		t.Errorf("round trip changed the remark:\ngot  %+v\nwant %+v", got, remark)
+	}
+}
+
+func TestRemarkWithoutPassName(t *testing.T) {
+	build := BuildToModel(&buildv1.Build{Remarks: []*buildv1.CompilerRemark{{
+		Pass:   buildv1.CompilerRemark_VECTORIZATION,
+		Status: buildv1.CompilerRemark_PASSED,
+	}}})
+	if pass := build.Remarks[0].Pass; pass != "vectorization" {
+		t.Errorf("Pass = %q, want the category vectorization", pass)
+	}
+}
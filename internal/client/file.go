@@ -0,0 +1,42 @@
+// internal/client/file.go
+
+package client
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	buildv1 "builds/api/build"
+)
+
+// ExportBuild writes a build as protobuf JSON, which ReadBuildFile reads
+// back without loss
+func ExportBuild(w io.Writer, build *buildv1.Build) error {
+	data, err := protojson.MarshalOptions{Multiline: true, Indent: "  "}.Marshal(build)
+	if err != nil {
+		return fmt.Errorf("failed to encode build: %w", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+// ReadBuildFile reads a build exported with ExportBuild, so it can be
+// inspected without a server
+func ReadBuildFile(path string) (*buildv1.Build, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read build file: %w", err)
+	}
+
+	var build buildv1.Build
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, &build); err != nil {
+		return nil, fmt.Errorf("failed to decode build file %s: %w", path, err)
+	}
+	return &build, nil
+}
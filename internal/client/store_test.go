@@ -0,0 +1,145 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	buildv1 "builds/api/build"
+	"builds/internal/collectors/remarks"
+	"builds/internal/models"
+	grpcutil "builds/internal/utils/grpcutil"
+)
+
+func (s *fakeServer) BeginBuild(ctx context.Context, req *buildv1.CreateBuildRequest) (*buildv1.Build, error) {
+	if err := s.call(ctx); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	build := proto.Clone(req.Build).(*buildv1.Build)
+	build.InProgress = true
+	s.builds = append(s.builds, build)
+	return build, nil
+}
+
+func (s *fakeServer) AppendRemarks(stream grpc.ClientStreamingServer[buildv1.AppendRemarksRequest, buildv1.AppendRemarksResponse]) error {
+	var stored int64
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return stream.SendAndClose(&buildv1.AppendRemarksResponse{Stored: stored})
+		}
+		if err != nil {
+			return err
+		}
+		s.mu.Lock()
+		i := s.find(req.BuildId)
+		if i >= 0 {
+			s.builds[i].Remarks = append(s.builds[i].Remarks, req.Remarks...)
+		}
+		s.mu.Unlock()
+		if i < 0 {
+			return status.Error(codes.NotFound, "build not found")
+		}
+		stored += int64(len(req.Remarks))
+	}
+}
+
+func (s *fakeServer) FinalizeBuild(ctx context.Context, req *buildv1.CreateBuildRequest) (*buildv1.Build, error) {
+	if err := s.call(ctx); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.find(req.Build.Id)
+	if i < 0 {
+		return nil, status.Error(codes.NotFound, "build not found")
+	}
+	if !s.builds[i].InProgress {
+		return nil, status.Error(codes.FailedPrecondition, "build is already finalized")
+	}
+	build := proto.Clone(req.Build).(*buildv1.Build)
+	build.Remarks = append(s.builds[i].Remarks, req.Build.Remarks...)
+	s.builds[i] = build
+	return build, s.lose()
+}
+
+// typicalRemarks returns n remarks shaped like the missed vectorizations
+// that dominate real records: C++ paths, mangled names and reasons
+func typicalRemarks(n int) []models.CompilerRemark {
+	remarks := make([]models.CompilerRemark, n)
+	for i := range remarks {
+		file := fmt.Sprintf("src/engine/physics/collision/broadphase_%d.cpp", i%50)
+		function := fmt.Sprintf("_ZN6engine7physics9collision10Broadphase11updatePairsERKSt6vectorINS_4AABBESaIS3_EEm%d", i%400)
+		remarks[i] = models.CompilerRemark{
+			ID:        fmt.Sprintf("%016x", i),
+			Type:      "optimization",
+			Pass:      "loop-vectorize",
+			Status:    "missed",
+			Name:      "CantVectorizeInstructionReturnType",
+			Message:   "loop not vectorized: instruction return type cannot be vectorized",
+			Function:  function,
+			Timestamp: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			Location:  models.Location{File: file, Line: int32(i%2000 + 1), Column: 5, Function: function},
+			Args: models.RemarkArgs{
+				Strings:  []string{"loop not vectorized: ", "instruction return type cannot be vectorized"},
+				DebugLoc: &models.Location{File: file, Line: int32(i%2000 + 3), Column: 9},
+			},
+		}
+	}
+	return remarks
+}
+
+func TestStoreBatchesRemarksAtTheDefaultCap(t *testing.T) {
+	build := &buildv1.Build{Id: "b1", Success: true, Remarks: RemarksToProto(typicalRemarks(remarks.DefaultMaxRemarks))}
+
+	// Servers echo the stored build back, so at the cap both the request and
+	// the response must fit the raised message limit
+	if size := proto.Size(&buildv1.CreateBuildRequest{Build: build}); size > grpcutil.MaxMessageSize/2 {
+		t.Errorf("a build at the default cap serializes to %d bytes, want at most %d", size, grpcutil.MaxMessageSize/2)
+	}
+	// Each batch must also fit gRPC's default 4 MiB, for servers that do not
+	// raise it
+	batch := &buildv1.AppendRemarksRequest{BuildId: build.Id, Remarks: build.Remarks[:RemarkBatchSize]}
+	if size := proto.Size(batch); size > 4<<20 {
+		t.Errorf("a batch of %d remarks serializes to %d bytes, want at most 4 MiB", RemarkBatchSize, size)
+	}
+
+	// The fake server accepts gRPC's default 4 MiB, so each message counts
+	fake := &fakeServer{}
+	c := newTestClient(t, fake)
+	stored, err := c.Store(context.Background(), build)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(stored.Remarks); n != remarks.DefaultMaxRemarks {
+		t.Errorf("stored %d remarks, want %d", n, remarks.DefaultMaxRemarks)
+	}
+	if !stored.Success {
+		t.Error("finalizing lost the rest of the build")
+	}
+	if n := len(build.Remarks); n != remarks.DefaultMaxRemarks {
+		t.Errorf("Store left the build with %d remarks, want them restored", n)
+	}
+}
+
+func TestStoreSmallBuild(t *testing.T) {
+	fake := &fakeServer{}
+	c := newTestClient(t, fake)
+	build := &buildv1.Build{Id: "b1", Remarks: RemarksToProto(typicalRemarks(10))}
+	if _, err := c.Store(context.Background(), build); err != nil {
+		t.Fatal(err)
+	}
+	// Created in one call, without beginning and finalizing
+	if fake.calls != 1 {
+		t.Errorf("made %d calls, want 1", fake.calls)
+	}
+}
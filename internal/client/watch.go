@@ -0,0 +1,112 @@
+// internal/client/watch.go
+
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	buildv1 "builds/api/build"
+)
+
+const (
+	initialBackoff = 500 * time.Millisecond
+	maxBackoff     = 30 * time.Second
+)
+
+// BuildHandler is invoked for every build received from the stream
+type BuildHandler func(*buildv1.Build) error
+
+// WatchBuilds streams new builds to handler until ctx is cancelled or the
+// handler returns an error. Dropped streams are re-opened with exponential
+// backoff, resuming after the last build that was delivered.
+func WatchBuilds(ctx context.Context, client buildv1.BuildServiceClient, filter string, handler BuildHandler) error {
+	var (
+		since   *timestamppb.Timestamp
+		seen    = make(map[string]bool) // IDs delivered at the `since` timestamp
+		backoff = initialBackoff
+	)
+
+	for {
+		stream, err := client.StreamBuilds(ctx, &buildv1.StreamBuildsRequest{
+			Filter: filter,
+			Since:  since,
+		})
+		if err == nil {
+			for {
+				var build *buildv1.Build
+				build, err = stream.Recv()
+				if err != nil {
+					break
+				}
+
+				// Any successful receive means the connection is healthy again
+				backoff = initialBackoff
+
+				if seen[build.Id] {
+					continue
+				}
+
+				if err := handler(build); err != nil {
+					return err
+				}
+
+				// Resume from the server's clock; older servers only send
+				// when the build was created, or the client start time
+				stored := build.FinishedAt
+				if stored == nil {
+					stored = build.CreatedAt
+				}
+				if stored == nil {
+					stored = build.StartTime
+				}
+				if stored != nil {
+					if since == nil || stored.AsTime().After(since.AsTime()) {
+						since = stored
+						seen = make(map[string]bool)
+					}
+				}
+				seen[build.Id] = true
+			}
+		}
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !isRetryable(err) {
+			return err
+		}
+
+		log.Printf("Watch stream interrupted: %v (reconnecting in %s)", err, backoff)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// isRetryable reports whether a stream error warrants reconnecting
+func isRetryable(err error) bool {
+	if err == nil || errors.Is(err, io.EOF) {
+		return true
+	}
+
+	switch status.Code(err) {
+	case codes.Unavailable, codes.Internal, codes.Unknown, codes.Aborted, codes.ResourceExhausted, codes.DeadlineExceeded:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	buildv1 "builds/api/build"
+)
+
+// droppingService serves each StreamBuilds call from the next script,
+// ending the stream with the script's error
+type droppingService struct {
+	buildv1.BuildServiceClient
+
+	scripts []streamScript
+	since   []*timestamppb.Timestamp // Since of each call
+}
+
+type streamScript struct {
+	builds []*buildv1.Build
+	err    error
+}
+
+func (s *droppingService) StreamBuilds(ctx context.Context, req *buildv1.StreamBuildsRequest, _ ...grpc.CallOption) (grpc.ServerStreamingClient[buildv1.Build], error) {
+	s.since = append(s.since, req.Since)
+	if len(s.scripts) == 0 {
+		return nil, status.Error(codes.FailedPrecondition, "no more streams")
+	}
+	script := s.scripts[0]
+	s.scripts = s.scripts[1:]
+	return &scriptedStream{script: script}, nil
+}
+
+type scriptedStream struct {
+	grpc.ClientStream
+	script streamScript
+}
+
+func (s *scriptedStream) Recv() (*buildv1.Build, error) {
+	if len(s.script.builds) == 0 {
+		return nil, s.script.err
+	}
+	build := s.script.builds[0]
+	s.script.builds = s.script.builds[1:]
+	return build, nil
+}
+
+func TestWatchBuildsResumes(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	build := func(id string, second int) *buildv1.Build {
+		return &buildv1.Build{Id: id, CreatedAt: timestamppb.New(start.Add(time.Duration(second) * time.Second))}
+	}
+
+	// The stream drops after b2; b2 and b3 share a timestamp, so the
+	// resumed stream replays b2 along with b3
+	service := &droppingService{scripts: []streamScript{
+		{builds: []*buildv1.Build{build("b1", 1), build("b2", 2)}, err: status.Error(codes.Unavailable, "connection reset")},
+		{builds: []*buildv1.Build{build("b2", 2), build("b3", 2), build("b4", 3)}},
+	}}
+
+	stop := errors.New("stop")
+	var ids []string
+	err := WatchBuilds(context.Background(), service, "", func(b *buildv1.Build) error {
+		ids = append(ids, b.Id)
+		if b.Id == "b4" {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) {
+		t.Fatalf("WatchBuilds returned %v, want the handler's error", err)
+	}
+	if want := []string{"b1", "b2", "b3", "b4"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("delivered %v, want %v", ids, want)
+	}
+
+	if len(service.since) != 2 {
+		t.Fatalf("opened %d streams, want 2", len(service.since))
+	}
+	if service.since[0] != nil {
+		t.Errorf("first stream starts at %v, want the beginning", service.since[0].AsTime())
+	}
+	if got := service.since[1].AsTime(); !got.Equal(start.Add(2 * time.Second)) {
+		t.Errorf("resumed at %v, want the last delivered build", got)
+	}
+}
+
+func TestWatchBuildsStopsOnPermanentErrors(t *testing.T) {
+	service := &droppingService{scripts: []streamScript{
+		{err: status.Error(codes.PermissionDenied, "no access")},
+	}}
+
+	err := WatchBuilds(context.Background(), service, "", func(*buildv1.Build) error { return nil })
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("WatchBuilds returned %v, want PermissionDenied", err)
+	}
+	if len(service.since) != 1 {
+		t.Errorf("opened %d streams, want 1", len(service.since))
+	}
+}
+
+func TestWatchBuildsResumesByServerClock(t *testing.T) {
+	stored := time.Date(2024, 1, 1, 0, 0, 1, 0, time.UTC)
+	// A client whose clock runs a day ahead
+	skewed := &buildv1.Build{
+		Id:        "b1",
+		StartTime: timestamppb.New(stored.Add(24 * time.Hour)),
+		CreatedAt: timestamppb.New(stored),
+	}
+	service := &droppingService{scripts: []streamScript{
+		{builds: []*buildv1.Build{skewed}, err: status.Error(codes.Unavailable, "connection reset")},
+		{builds: []*buildv1.Build{skewed, {Id: "b2", CreatedAt: timestamppb.New(stored.Add(time.Second))}}},
+	}}
+
+	stop := errors.New("stop")
+	err := WatchBuilds(context.Background(), service, "", func(b *buildv1.Build) error {
+		if b.Id == "b2" {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) {
+		t.Fatalf("WatchBuilds returned %v, want the handler's error", err)
+	}
+	if len(service.since) != 2 {
+		t.Fatalf("opened %d streams, want 2", len(service.since))
+	}
+	if got := service.since[1].AsTime(); !got.Equal(stored) {
+		t.Errorf("resumed at %v, want the server's %v", got, stored)
+	}
+}
@@ -0,0 +1,150 @@
+// internal/collectors/binary/collector.go
+
+package binary
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"builds/internal/models"
+	"builds/internal/parsers/binsize"
+	"builds/internal/parsers/remarks"
+)
+
+// Collector measures the files the compiler wrote: their size on disk and
+// the text, data and bss sizes of the binaries among them. It runs once
+// the compile finished; outputs that were not written by it, such as those
+// left over from an earlier build, are skipped.
+type Collector struct {
+	models.BaseCollector
+	buildContext *models.BuildContext
+	startTime    time.Time
+	info         *models.BuildMetrics
+}
+
+// NewCollector creates a new binary size collector
+func NewCollector(ctx *models.BuildContext) *Collector {
+	return &Collector{
+		buildContext: ctx,
+		startTime:    time.Now().Truncate(time.Second), // Some file systems keep whole seconds
+	}
+}
+
+// Initialize prepares the binary size collector
+func (c *Collector) Initialize(ctx context.Context) error {
+	return nil
+}
+
+// Collect measures the outputs of the command
+func (c *Collector) Collect(ctx context.Context) error {
+	c.info = nil
+	msvc := remarks.IsMSVC(c.buildContext.Compiler)
+
+	metrics := &models.BuildMetrics{SectionSizes: make(map[string]int64)}
+	found := false
+	for _, path := range outputs(c.buildContext.Args, msvc) {
+		stat, err := os.Stat(path)
+		if err != nil || !stat.Mode().IsRegular() || stat.ModTime().Before(c.startTime) {
+			continue
+		}
+		found = true
+		metrics.OutputSize += stat.Size()
+
+		sizes, err := binsize.Read(path)
+		if err != nil {
+			if !errors.Is(err, binsize.ErrUnknownFormat) {
+				log.Printf("Warning: failed to read sections of %s: %v", path, err)
+			}
+			continue
+		}
+		metrics.TextSize += sizes.Text
+		metrics.DataSize += sizes.Data
+		metrics.BSSSize += sizes.BSS
+		for name, size := range sizes.Sections {
+			metrics.SectionSizes[name] += size
+		}
+	}
+
+	if found {
+		c.info = metrics
+	}
+	return nil
+}
+
+// GetData returns the measured sizes, or nil when no output was found
+func (c *Collector) GetData() interface{} {
+	if c.info == nil {
+		return nil
+	}
+	return *c.info
+}
+
+// Cleanup performs any necessary cleanup
+func (c *Collector) Cleanup(ctx context.Context) error {
+	return nil
+}
+
+// sourceExtensions mark the inputs that compile to an object file of
+// their own
+var sourceExtensions = map[string]bool{
+	".c": true, ".cc": true, ".cp": true, ".cpp": true, ".cxx": true, ".c++": true, ".C": true,
+	".cu": true, ".hip": true, ".m": true, ".mm": true, ".s": true, ".S": true, ".asm": true,
+	".f": true, ".for": true, ".f90": true, ".f95": true, ".f03": true, ".f08": true, ".F": true, ".F90": true,
+}
+
+// outputs returns the files the command writes. Without -o (/Fe or /Fo for
+// cl.exe) they follow the driver defaults: an object file per source when
+// compiling only, a.out or the first source's .exe when linking.
+func outputs(args []string, msvc bool) []string {
+	var (
+		explicit    string
+		sources     []string
+		compileOnly bool
+	)
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "-o" && i+1 < len(args):
+			i++
+			explicit = args[i]
+		case msvc && (strings.HasPrefix(arg, "/Fe") || strings.HasPrefix(arg, "-Fe") ||
+			strings.HasPrefix(arg, "/Fo") || strings.HasPrefix(arg, "-Fo")) && len(arg) > 3:
+			explicit = strings.TrimPrefix(arg[3:], ":")
+		case arg == "-c" || arg == "/c":
+			compileOnly = true
+		case arg == "-S" || arg == "-E" || arg == "/E" || arg == "/P" || arg == "-fsyntax-only":
+			return nil
+		case !strings.HasPrefix(arg, "-") && !(msvc && strings.HasPrefix(arg, "/")):
+			if sourceExtensions[filepath.Ext(arg)] {
+				sources = append(sources, arg)
+			}
+		}
+	}
+
+	switch {
+	case explicit != "":
+		return []string{explicit}
+	case compileOnly:
+		objExt := ".o"
+		if msvc {
+			objExt = ".obj"
+		}
+		objects := make([]string, len(sources))
+		for i, source := range sources {
+			base := filepath.Base(source)
+			objects[i] = strings.TrimSuffix(base, filepath.Ext(base)) + objExt
+		}
+		return objects
+	case msvc && len(sources) > 0:
+		base := filepath.Base(sources[0])
+		return []string{strings.TrimSuffix(base, filepath.Ext(base)) + ".exe"}
+	case len(sources) > 0:
+		return []string{"a.out"}
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+// internal/collectors/cache/cache.go
+
+package cache
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Cache statuses recorded on builds. Timings of cached builds measure the
+// cache lookup rather than the compiler, so they are labeled as such.
+const (
+	StatusHit      = "hit"
+	StatusMiss     = "miss"
+	StatusDisabled = "disabled"
+	StatusUnknown  = "unknown"
+)
+
+// Wrappers that run the real compiler on the user's behalf
+const (
+	WrapperCcache  = "ccache"
+	WrapperSccache = "sccache"
+	WrapperDistcc  = "distcc"
+	WrapperIcecc   = "icecc"
+)
+
+// wrapperFromName classifies a command by its file name, returning "" for
+// anything that is not a known compiler wrapper
+func wrapperFromName(path string) string {
+	base := strings.ToLower(filepath.Base(path))
+	base = strings.TrimSuffix(base, ".exe")
+	switch base {
+	case WrapperCcache, WrapperSccache, WrapperDistcc, WrapperIcecc:
+		return base
+	default:
+		return ""
+	}
+}
+
+// Unwrap splits a wrapper such as "ccache gcc -c x.c" into the wrapper and
+// the compiler command it runs. Compilers reached through a masquerade
+// symlink, such as /usr/lib/ccache/gcc, keep their name and report the
+// wrapper the link points to. ok is false for plain compiler commands.
+func Unwrap(command string, args []string) (wrapper, compiler string, compilerArgs []string, ok bool) {
+	if wrapper = wrapperFromName(command); wrapper != "" {
+		if len(args) == 0 {
+			return "", command, args, false
+		}
+		return wrapper, args[0], args[1:], true
+	}
+
+	if path, err := exec.LookPath(command); err == nil {
+		if resolved, err := filepath.EvalSymlinks(path); err == nil {
+			if wrapper = wrapperFromName(resolved); wrapper != "" {
+				return wrapper, command, args, true
+			}
+		}
+	}
+	return "", command, args, false
+}
+
+// StatsLogVar names the variable pointing ccache at a file it appends the
+// counters each compile updates to, available since ccache 4.4
+const StatsLogVar = "CCACHE_STATSLOG"
+
+// ParseStatsLog classifies a compile from the ccache statistics log it
+// wrote. Each compile logs a "# source" line followed by the names of the
+// counters it updated.
+func ParseStatsLog(r io.Reader) (string, error) {
+	status := StatusUnknown
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		switch strings.TrimSpace(scanner.Text()) {
+		case "direct_cache_hit", "preprocessed_cache_hit", "remote_storage_hit":
+			status = StatusHit
+		case "cache_miss":
+			status = StatusMiss
+		}
+	}
+	return status, scanner.Err()
+}
+
+// disabled reports whether ccache was switched off through the environment.
+// ccache reads 0, false, disable and no as leaving it enabled.
+func disabled() bool {
+	value, ok := os.LookupEnv("CCACHE_DISABLE")
+	if !ok {
+		return false
+	}
+	switch strings.ToLower(value) {
+	case "0", "false", "disable", "no":
+		return false
+	default:
+		return true
+	}
+}
+
+// Probe tells whether a wrapped compile was served from the cache. ccache
+// writes the counters of this compile alone to a statistics log, so other
+// compiles sharing the cache meanwhile do not affect the result.
+type Probe struct {
+	wrapper string
+	log     string // Statistics log of the compile, "" when not probing
+}
+
+// Start begins watching a compile run through wrapper. The compile must run
+// with the variables of Env.
+func Start(wrapper string) *Probe {
+	p := &Probe{wrapper: wrapper}
+	if wrapper == WrapperCcache && !disabled() {
+		if f, err := os.CreateTemp("", "builds-ccache-*.log"); err == nil {
+			f.Close()
+			p.log = f.Name()
+		}
+	}
+	return p
+}
+
+// Env returns the variables the compile runs with for Finish to classify it
+func (p *Probe) Env() []string {
+	if p.log == "" {
+		return nil
+	}
+	return []string{StatsLogVar + "=" + p.log}
+}
+
+// Finish classifies the compile. Only ccache logs per-compile counters;
+// other wrappers, and ccache releases without a statistics log, report
+// StatusUnknown. Compiles that were not wrapped report "".
+func (p *Probe) Finish() string {
+	if p.log != "" {
+		defer os.Remove(p.log)
+	}
+	switch {
+	case p.wrapper == "":
+		return ""
+	case p.wrapper == WrapperCcache && disabled():
+		return StatusDisabled
+	case p.log == "":
+		return StatusUnknown
+	}
+
+	data, err := os.ReadFile(p.log)
+	if err != nil {
+		return StatusUnknown
+	}
+	// The compile's entries belong in a log the user asked for as well
+	if own := os.Getenv(StatsLogVar); own != "" && len(data) > 0 {
+		if f, err := os.OpenFile(own, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644); err == nil {
+			f.Write(data)
+			f.Close()
+		}
+	}
+	// Uncacheable compiles, such as linking, count as neither
+	status, err := ParseStatsLog(bytes.NewReader(data))
+	if err != nil {
+		return StatusUnknown
+	}
+	return status
+}
@@ -0,0 +1,157 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// logStats appends what ccache logs for a compile updating counters to the
+// statistics log named in env
+func logStats(t *testing.T, env []string, counters ...string) {
+	t.Helper()
+	if len(env) != 1 {
+		t.Fatalf("compile environment %q, want the statistics log", env)
+	}
+	_, path, _ := strings.Cut(env[0], "=")
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString("# foo.c\n" + strings.Join(counters, "\n") + "\n"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUnwrap(t *testing.T) {
+	// A masquerade directory, where gcc is a link to ccache
+	dir := t.TempDir()
+	ccache := filepath.Join(dir, "ccache")
+	if err := os.WriteFile(ccache, []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	masquerade := filepath.Join(dir, "gcc")
+	if err := os.Symlink(ccache, masquerade); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		command      string
+		args         []string
+		wantWrapper  string
+		wantCompiler string
+		wantArgs     []string
+		wantOK       bool
+	}{
+		{"ccache", "ccache", []string{"gcc", "-c", "foo.c"}, WrapperCcache, "gcc", []string{"-c", "foo.c"}, true},
+		{"ccache by path", "/usr/bin/ccache", []string{"clang", "-O2"}, WrapperCcache, "clang", []string{"-O2"}, true},
+		{"distcc", "distcc", []string{"g++", "-c", "foo.cc"}, WrapperDistcc, "g++", []string{"-c", "foo.cc"}, true},
+		{"sccache", "sccache.exe", []string{"cl", "/c"}, WrapperSccache, "cl", []string{"/c"}, true},
+		{"wrapper without compiler", "ccache", nil, "", "ccache", nil, false},
+		{"masquerade", masquerade, []string{"-c", "foo.c"}, WrapperCcache, masquerade, []string{"-c", "foo.c"}, true},
+		{"plain compiler", "/nonexistent/gcc", []string{"-c", "foo.c"}, "", "/nonexistent/gcc", []string{"-c", "foo.c"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wrapper, compiler, args, ok := Unwrap(tt.command, tt.args)
+			if wrapper != tt.wantWrapper || compiler != tt.wantCompiler || ok != tt.wantOK || !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got %q, %q, %q, %v, want %q, %q, %q, %v",
+					wrapper, compiler, args, ok, tt.wantWrapper, tt.wantCompiler, tt.wantArgs, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestParseStatsLog(t *testing.T) {
+	tests := []struct {
+		name string
+		log  string
+		want string
+	}{
+		{"direct hit", "# foo.c\ndirect_cache_hit\n", StatusHit},
+		{"preprocessed hit", "# foo.c\npreprocessed_cache_hit\n", StatusHit},
+		{"miss", "# foo.c\ncache_miss\n", StatusMiss},
+		{"link", "# foo.o\ncalled_for_link\n", StatusUnknown},
+		{"empty", "", StatusUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseStatsLog(strings.NewReader(tt.log))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProbe(t *testing.T) {
+	tests := []struct {
+		name     string
+		wrapper  string
+		disable  string   // CCACHE_DISABLE, unset when empty
+		counters []string // Logged by the compile
+		want     string
+	}{
+		{"hit", WrapperCcache, "", []string{"direct_cache_hit"}, StatusHit},
+		{"miss", WrapperCcache, "", []string{"cache_miss"}, StatusMiss},
+		{"uncacheable", WrapperCcache, "", []string{"called_for_link"}, StatusUnknown},
+		{"no statistics log", WrapperCcache, "", nil, StatusUnknown},
+		{"disabled", WrapperCcache, "1", nil, StatusDisabled},
+		{"disable set to false", WrapperCcache, "false", []string{"direct_cache_hit"}, StatusHit},
+		{"distcc", WrapperDistcc, "", nil, StatusUnknown},
+		{"not wrapped", "", "", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("CCACHE_DISABLE", tt.disable)
+			if tt.disable == "" {
+				os.Unsetenv("CCACHE_DISABLE")
+			}
+
+			probe := Start(tt.wrapper)
+			if tt.counters != nil {
+				logStats(t, probe.Env(), tt.counters...)
+			}
+			if got := probe.Finish(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProbeIgnoresOtherCompiles(t *testing.T) {
+	// Two compiles sharing the cache at once, one hitting and one missing
+	hit, miss := Start(WrapperCcache), Start(WrapperCcache)
+	logStats(t, hit.Env(), "direct_cache_hit")
+	logStats(t, miss.Env(), "cache_miss")
+	if got := hit.Finish(); got != StatusHit {
+		t.Errorf("hitting compile got %q, want %q", got, StatusHit)
+	}
+	if got := miss.Finish(); got != StatusMiss {
+		t.Errorf("missing compile got %q, want %q", got, StatusMiss)
+	}
+}
+
+func TestProbeKeepsUserStatsLog(t *testing.T) {
+	own := filepath.Join(t.TempDir(), "stats.log")
+	t.Setenv(StatsLogVar, own)
+
+	probe := Start(WrapperCcache)
+	logStats(t, probe.Env(), "cache_miss")
+	probe.Finish()
+
+	data, err := os.ReadFile(own)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "# foo.c\ncache_miss\n" {
+		t.Errorf("user's statistics log holds %q", data)
+	}
+}
@@ -5,27 +5,44 @@ import (
 	"context"
 	"fmt"
 	"os/exec"
+	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 
 	"builds/internal/models"
+	"builds/internal/parsers/triple"
 )
 
 var (
 	clangVersionPattern = regexp.MustCompile(`clang version (\d+\.\d+\.\d+)`)
 	gccVersionPattern   = regexp.MustCompile(`gcc version (\d+\.\d+\.\d+)`)
 	targetPattern       = regexp.MustCompile(`Target: (.+)`)
+	msvcBannerPattern   = regexp.MustCompile(`Version (\d+\.\d+\.\d+)(?:\.\d+)? for (\S+)`)
+	nvccVersionPattern  = regexp.MustCompile(`Cuda compilation tools, release [\d.]+, V(\d+\.\d+\.\d+)`)
+	rustcVersionPattern = regexp.MustCompile(`rustc (\d+\.\d+\.\d+(?:-[\w.]+)?)`)
+	rustcHostPattern    = regexp.MustCompile(`host: (\S+)`)
+
+	// Any version number, for compilers without a pattern of their own
+	versionNumberPattern = regexp.MustCompile(`\d+\.\d+(?:\.\d+)*`)
+	parenthesizedPattern = regexp.MustCompile(`\([^)]*\)`)
 )
 
+// nvptxTarget is the target nvcc compiles device code for. Host code is
+// compiled by the host compiler nvcc drives.
+const nvptxTarget = "nvptx64-nvidia-cuda"
+
 type Collector struct {
 	models.BaseCollector
 	info         models.Compiler
 	buildContext *models.BuildContext
+	args         []string // As given, before other collectors add flags
 }
 
 func NewCollector(ctx *models.BuildContext) *Collector {
 	return &Collector{
 		buildContext: ctx,
+		args:         slices.Clone(ctx.Args),
 		info: models.Compiler{
 			Language:      models.Language{},
 			Features:      models.CompilerFeatures{},
@@ -43,29 +60,37 @@ func (c *Collector) Initialize(ctx context.Context) error {
 
 func (c *Collector) Collect(ctx context.Context) error {
 	// Get compiler version
-	version, err := c.collectVersion()
+	version, banner, err := c.collectVersion(ctx)
 	if err != nil {
 		return fmt.Errorf("version collection failed: %w", err)
 	}
 	c.info.Version = version
+	c.info.VersionString = banner
 
 	// Get target information
-	target, err := c.collectTarget()
+	target, err := c.collectTarget(ctx)
 	if err != nil {
 		return fmt.Errorf("target collection failed: %w", err)
 	}
 	c.info.Target = target
+	c.info.Triple = triple.Parse(target)
+	if c.info.Name == driverMSVC && c.info.Triple.Arch != "" {
+		// cl.exe only names the architecture; the rest is implied
+		c.info.Triple.Vendor, c.info.Triple.OS, c.info.Triple.ABI = "pc", "windows", "msvc"
+	}
 
-	// Parse current options (without modifying them)
-	c.info.Options = c.parseCompilerOptions(c.buildContext.Args)
+	// Record the options as given; the remarks collector adds its own
+	c.info.Options = c.parseCompilerOptions(c.args)
 
 	// Set language information
 	c.setLanguageInfo()
 
 	// Collect compiler features
-	c.collectFeatures()
+	c.collectFeatures(ctx)
 
-	return nil
+	// Feature probes report no support when cancelled, so a timeout is only
+	// visible here
+	return ctx.Err()
 }
 
 func (c *Collector) GetData() interface{} {
@@ -77,27 +102,30 @@ func (c *Collector) Cleanup(ctx context.Context) error {
 }
 
 func (c *Collector) inferCompilerType(compiler string) string {
-	base := strings.ToLower(compiler)
-	switch {
-	case strings.Contains(base, "clang"):
-		return "clang"
-	case strings.Contains(base, "gcc"):
-		return "gcc"
-	default:
-		return "unknown"
-	}
+	return detectDriver(compiler)
 }
 
-func (c *Collector) collectVersion() (string, error) {
-	cmd := exec.Command(c.buildContext.Compiler, "--version")
+// collectVersion returns the compiler's version number and the full banner
+// it was read from
+func (c *Collector) collectVersion(ctx context.Context) (string, string, error) {
+	if c.info.Name == driverMSVC {
+		output, _ := exec.CommandContext(ctx, c.buildContext.Compiler).CombinedOutput()
+		version, _ := parseMSVCBanner(output)
+		return version, versionBanner(output), nil
+	}
+
+	cmd := exec.CommandContext(ctx, c.buildContext.Compiler, "--version")
 	output, err := cmd.Output()
 	if err != nil {
-		return "", err
+		if c.info.Name == driverUnknown {
+			return "", "", nil // Not every compiler knows --version
+		}
+		return "", "", err
 	}
 
 	var version string
 	switch c.info.Name {
-	case "clang":
+	case "clang", "clang-cl":
 		if matches := clangVersionPattern.FindStringSubmatch(string(output)); len(matches) > 1 {
 			version = matches[1]
 		}
@@ -105,21 +133,60 @@ func (c *Collector) collectVersion() (string, error) {
 		if matches := gccVersionPattern.FindStringSubmatch(string(output)); len(matches) > 1 {
 			version = matches[1]
 		}
+	case driverNVCC:
+		if matches := nvccVersionPattern.FindStringSubmatch(string(output)); len(matches) > 1 {
+			version = matches[1]
+		}
+	case driverRustc:
+		if matches := rustcVersionPattern.FindStringSubmatch(string(output)); len(matches) > 1 {
+			version = matches[1]
+		}
+	}
+	if version == "" {
+		version = bannerVersion(string(output))
 	}
 
-	return version, nil
+	return version, versionBanner(output), nil
 }
 
-func (c *Collector) collectTarget() (string, error) {
+// bannerVersion takes the first version number from the first line of a
+// --version banner. Parenthesized text is skipped, as it holds the
+// packager's version in banners such as "gcc (Debian 12.2.0-14) 12.2.0".
+func bannerVersion(banner string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(banner), "\n")
+	line = parenthesizedPattern.ReplaceAllString(line, "")
+	return versionNumberPattern.FindString(line)
+}
+
+// versionBanner normalises the compiler's version output: line endings are
+// unified and trailing blank lines, such as the blank line GCC ends its
+// copyright notice with, are dropped
+func versionBanner(output []byte) string {
+	banner := strings.ReplaceAll(string(output), "\r\n", "\n")
+	return strings.TrimRight(banner, " \t\n")
+}
+
+func (c *Collector) collectTarget(ctx context.Context) (string, error) {
+	if c.info.Name == driverMSVC {
+		_, target := c.msvcBanner(ctx)
+		return target, nil
+	}
+
 	var args []string
 	switch c.info.Name {
-	case "clang":
+	case "clang", "clang-cl":
 		args = []string{"--version", "-v"}
 	case "gcc":
 		args = []string{"-v"}
+	case driverNVCC:
+		return nvptxTarget, nil
+	case driverRustc:
+		return c.rustcTarget(ctx)
+	default:
+		return c.dumpMachine(ctx), nil
 	}
 
-	cmd := exec.Command(c.buildContext.Compiler, args...)
+	cmd := exec.CommandContext(ctx, c.buildContext.Compiler, args...)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return "", err
@@ -132,44 +199,106 @@ func (c *Collector) collectTarget() (string, error) {
 	return "", nil
 }
 
+// rustcTarget returns the target given with --target, or else the host
+// rustc compiles for
+func (c *Collector) rustcTarget(ctx context.Context) (string, error) {
+	args := c.args
+	for i, arg := range args {
+		target, ok := strings.CutPrefix(arg, "--target=")
+		if !ok && arg == "--target" && i+1 < len(args) {
+			target, ok = args[i+1], true
+		}
+		if ok {
+			// Custom targets are given as the path of their specification
+			return strings.TrimSuffix(filepath.Base(target), ".json"), nil
+		}
+	}
+
+	output, err := exec.CommandContext(ctx, c.buildContext.Compiler, "-vV").Output()
+	if err != nil {
+		return "", err
+	}
+	if matches := rustcHostPattern.FindStringSubmatch(string(output)); len(matches) > 1 {
+		return matches[1], nil
+	}
+	return "", nil
+}
+
+// dumpMachine asks an unrecognized compiler for its target with
+// -dumpmachine, which compilers modelled on GCC accept. Anything but a
+// single triple is ignored.
+func (c *Collector) dumpMachine(ctx context.Context) string {
+	output, err := exec.CommandContext(ctx, c.buildContext.Compiler, "-dumpmachine").Output()
+	if err != nil {
+		return ""
+	}
+	target := strings.TrimSpace(string(output))
+	if !strings.Contains(target, "-") || strings.ContainsAny(target, " \t\n") {
+		return ""
+	}
+	return target
+}
+
 func (c *Collector) parseCompilerOptions(args []string) []string {
 	var options []string
 	for _, arg := range args {
-		if strings.HasPrefix(arg, "-") {
+		if strings.HasPrefix(arg, "-") || (c.usesCLOptions() && isCLOption(arg)) {
 			options = append(options, arg)
 		}
 	}
 	return options
 }
 
+// msvcBanner returns the version and target architecture that cl.exe
+// prints when run without arguments
+func (c *Collector) msvcBanner(ctx context.Context) (version, target string) {
+	// cl.exe exits with an error without input files, but still prints
+	// its banner
+	output, _ := exec.CommandContext(ctx, c.buildContext.Compiler).CombinedOutput()
+	return parseMSVCBanner(output)
+}
+
+// parseMSVCBanner reads the version and target from cl.exe's banner
+func parseMSVCBanner(output []byte) (version, target string) {
+	if matches := msvcBannerPattern.FindStringSubmatch(string(output)); len(matches) > 2 {
+		return matches[1], matches[2]
+	}
+	return "", ""
+}
+
+// usesCLOptions reports whether the driver accepts /-style options
+func (c *Collector) usesCLOptions() bool {
+	return c.info.Name == driverClangCL || c.info.Name == driverMSVC
+}
+
+// setLanguageInfo reports the language and standard the command selects,
+// falling back to C/C++ for GCC and Clang when it names neither
 func (c *Collector) setLanguageInfo() {
+	if lang, ok := detectLanguage(c.args, c.usesCLOptions()); ok {
+		c.info.Language = lang
+		return
+	}
 	switch c.info.Name {
-	case "clang":
-		c.info.Language = models.Language{
-			Name:          "C/C++",
-			Version:       "C++17",
-			Specification: "ISO/IEC 14882:2017",
-		}
-	case "gcc":
-		c.info.Language = models.Language{
-			Name:          "C/C++",
-			Version:       "C++17",
-			Specification: "ISO/IEC 14882:2017",
-		}
+	case driverClang, driverClangCL, driverGCC:
+		c.info.Language = defaultLanguage
+	case driverNVCC:
+		c.info.Language = models.Language{Name: langCUDA}
+	case driverRustc:
+		c.info.Language = models.Language{Name: langRust}
 	}
 }
 
-func (c *Collector) collectFeatures() {
+func (c *Collector) collectFeatures(ctx context.Context) {
 	c.info.Features = models.CompilerFeatures{
-		SupportsOpenMP: c.hasOpenMPSupport(),
-		SupportsGPU:    c.hasGPUSupport(),
-		SupportsLTO:    c.hasLTOSupport(),
-		SupportsPGO:    c.hasPGOSupport(),
+		SupportsOpenMP: c.hasOpenMPSupport(ctx),
+		SupportsGPU:    c.hasGPUSupport(ctx),
+		SupportsLTO:    c.hasLTOSupport(ctx),
+		SupportsPGO:    c.hasPGOSupport(ctx),
 		Extensions:     c.getCompilerExtensions(),
 	}
 }
 
-func (c *Collector) hasOpenMPSupport() bool {
+func (c *Collector) hasOpenMPSupport(ctx context.Context) bool {
 	var testProgram string
 	switch c.info.Name {
 	case "clang", "gcc":
@@ -178,43 +307,61 @@ func (c *Collector) hasOpenMPSupport() bool {
 		return false
 	}
 
-	cmd := exec.Command(c.buildContext.Compiler, "-fopenmp", "-x", "c", "-")
+	cmd := exec.CommandContext(ctx, c.buildContext.Compiler, "-fopenmp", "-x", "c", "-")
 	cmd.Stdin = strings.NewReader(testProgram)
 	return cmd.Run() == nil
 }
 
-func (c *Collector) hasGPUSupport() bool {
+func (c *Collector) hasGPUSupport(ctx context.Context) bool {
 	switch c.info.Name {
-	case "clang":
-		return c.hasClangGPUSupport()
+	case "clang", "clang-cl":
+		return c.hasClangGPUSupport(ctx)
 	case "gcc":
-		return c.hasGCCGPUSupport()
+		return c.hasGCCGPUSupport(ctx)
+	case driverNVCC:
+		return true
 	}
 	return false
 }
 
-func (c *Collector) hasLTOSupport() bool {
-	cmd := exec.Command(c.buildContext.Compiler, "-flto=thin", "--help")
+func (c *Collector) hasLTOSupport(ctx context.Context) bool {
+	switch c.info.Name {
+	case driverRustc:
+		return true // -C lto
+	case driverNVCC:
+		// Device code LTO, from CUDA 11.2
+		output, err := exec.CommandContext(ctx, c.buildContext.Compiler, "--help").Output()
+		return err == nil && strings.Contains(string(output), "-dlto")
+	}
+	cmd := exec.CommandContext(ctx, c.buildContext.Compiler, "-flto=thin", "--help")
 	return cmd.Run() == nil
 }
 
-func (c *Collector) hasPGOSupport() bool {
-	cmd := exec.Command(c.buildContext.Compiler, "-fprofile-generate", "--help")
+func (c *Collector) hasPGOSupport(ctx context.Context) bool {
+	switch c.info.Name {
+	case driverRustc:
+		return true // -C profile-generate
+	case driverNVCC:
+		return false
+	}
+	cmd := exec.CommandContext(ctx, c.buildContext.Compiler, "-fprofile-generate", "--help")
 	return cmd.Run() == nil
 }
 
 func (c *Collector) getCompilerExtensions() []string {
 	switch c.info.Name {
-	case "clang":
+	case "clang", "clang-cl":
 		return []string{"OpenMP", "OpenCL", "CUDA", "HIP"}
 	case "gcc":
 		return []string{"OpenMP", "OpenACC", "NVPTX"}
+	case driverNVCC:
+		return []string{"CUDA"}
 	}
 	return nil
 }
 
-func (c *Collector) hasClangGPUSupport() bool {
-	cmd := exec.Command(c.buildContext.Compiler, "--help")
+func (c *Collector) hasClangGPUSupport(ctx context.Context) bool {
+	cmd := exec.CommandContext(ctx, c.buildContext.Compiler, "--help")
 	output, err := cmd.Output()
 	if err != nil {
 		return false
@@ -224,8 +371,8 @@ func (c *Collector) hasClangGPUSupport() bool {
 		strings.Contains(string(output), "hip")
 }
 
-func (c *Collector) hasGCCGPUSupport() bool {
-	cmd := exec.Command(c.buildContext.Compiler, "--help")
+func (c *Collector) hasGCCGPUSupport(ctx context.Context) bool {
+	cmd := exec.CommandContext(ctx, c.buildContext.Compiler, "--help")
 	output, err := cmd.Output()
 	if err != nil {
 		return false
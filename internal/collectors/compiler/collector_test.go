@@ -0,0 +1,81 @@
+package compiler
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"builds/internal/models"
+)
+
+const clangBanner = `Ubuntu clang version 18.1.3 (1ubuntu1)
+Target: x86_64-pc-linux-gnu
+Thread model: posix
+InstalledDir: /usr/bin`
+
+const gccBanner = `gcc-13 (Ubuntu 13.2.0-23ubuntu4) 13.2.0
+Copyright (C) 2023 Free Software Foundation, Inc.
+This is free software; see the source for copying conditions.  There is NO
+warranty; not even for MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.`
+
+func TestCollectVersion(t *testing.T) {
+	dir := t.TempDir()
+
+	// Windows builds of clang end their lines with CRLF
+	crlf := filepath.Join(dir, "clang-crlf")
+	script := "#!/bin/sh\nprintf 'clang version 17.0.6\\r\\nTarget: x86_64-pc-windows-msvc\\r\\n\\r\\n'\n"
+	if err := os.WriteFile(crlf, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		compiler    string
+		wantVersion string
+		wantBanner  string
+	}{
+		{"clang", writeCompiler(t, dir, "clang-18", clangBanner), "18.1.3", clangBanner},
+		// GCC ends its banner with a blank line, which is dropped
+		{"gcc", writeCompiler(t, dir, "gcc-13", gccBanner+"\n"), "13.2.0", gccBanner},
+		{"crlf", crlf, "17.0.6", "clang version 17.0.6\nTarget: x86_64-pc-windows-msvc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCollector(&models.BuildContext{Compiler: tt.compiler})
+			ctx := context.Background()
+			if err := c.Initialize(ctx); err != nil {
+				t.Fatal(err)
+			}
+			version, banner, err := c.collectVersion(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if version != tt.wantVersion {
+				t.Errorf("version %q, want %q", version, tt.wantVersion)
+			}
+			if banner != tt.wantBanner {
+				t.Errorf("banner %q, want %q", banner, tt.wantBanner)
+			}
+		})
+	}
+}
+
+func TestOptionsAsGiven(t *testing.T) {
+	compiler := writeCompiler(t, t.TempDir(), "clang-18", clangBanner)
+	buildCtx := &models.BuildContext{Compiler: compiler, Args: []string{"-Ofast", "-c", "foo.c"}}
+	c := NewCollector(buildCtx)
+	ctx := context.Background()
+	if err := c.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// The remarks collector substitutes its own -O level
+	buildCtx.Args = []string{"-fsave-optimization-record", "-O2", "-c", "foo.c"}
+	if err := c.Collect(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if options := c.info.Options; !slices.Contains(options, "-Ofast") || slices.Contains(options, "-O2") {
+		t.Errorf("options %q, want those given", options)
+	}
+}
@@ -0,0 +1,96 @@
+// internal/collectors/compiler/driver.go
+package compiler
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// Compiler drivers recognized by the collector
+const (
+	driverClang   = "clang"
+	driverClangCL = "clang-cl"
+	driverGCC     = "gcc"
+	driverMSVC    = "msvc"
+	driverNVCC    = "nvcc"
+	driverRustc   = "rustc"
+	driverUnknown = "unknown"
+)
+
+// detectDriver classifies a compiler command. Generic names such as cc or
+// c++ are usually symlinks, so the link target is checked and, failing
+// that, the --version banner.
+func detectDriver(compiler string) string {
+	if driver := driverFromName(compiler); driver != driverUnknown {
+		return driver
+	}
+
+	if path, err := exec.LookPath(compiler); err == nil {
+		if resolved, err := filepath.EvalSymlinks(path); err == nil {
+			if driver := driverFromName(resolved); driver != driverUnknown {
+				return driver
+			}
+		}
+	}
+
+	output, err := exec.Command(compiler, "--version").Output()
+	if err != nil {
+		return driverUnknown
+	}
+	return driverFromBanner(string(output))
+}
+
+// driverFromName classifies a compiler by its file name
+func driverFromName(path string) string {
+	base := strings.ToLower(filepath.Base(path))
+	base = strings.TrimSuffix(base, ".exe")
+	switch {
+	case base == "cl":
+		return driverMSVC
+	case base == "nvcc":
+		return driverNVCC
+	case base == "rustc", strings.HasPrefix(base, "rustc-"):
+		return driverRustc
+	case strings.HasPrefix(base, "clang-cl"):
+		return driverClangCL
+	case strings.Contains(base, "clang"):
+		return driverClang
+	case strings.Contains(base, "gcc"), strings.Contains(base, "g++"):
+		return driverGCC
+	default:
+		return driverUnknown
+	}
+}
+
+// driverFromBanner classifies a compiler by its --version output
+func driverFromBanner(banner string) string {
+	switch {
+	case strings.Contains(banner, "Microsoft (R) C/C++"):
+		return driverMSVC
+	case strings.Contains(banner, "NVIDIA (R) Cuda compiler driver"):
+		return driverNVCC
+	case strings.HasPrefix(banner, "rustc "):
+		return driverRustc
+	case strings.Contains(banner, "clang version"):
+		return driverClang
+	case strings.Contains(banner, "Free Software Foundation"),
+		strings.Contains(strings.ToLower(banner), "gcc"):
+		return driverGCC
+	default:
+		return driverUnknown
+	}
+}
+
+// isCLOption reports whether an argument is an MSVC-style option, such as
+// /O2 or /Fo:out.obj, as accepted by cl.exe and clang-cl. Absolute paths to existing
+// files are inputs rather than options.
+func isCLOption(arg string) bool {
+	if len(arg) < 2 || arg[0] != '/' || !unicode.IsLetter(rune(arg[1])) {
+		return false
+	}
+	_, err := os.Stat(arg)
+	return err != nil
+}
@@ -0,0 +1,128 @@
+package compiler
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"builds/internal/models"
+)
+
+// writeCompiler writes a fake compiler printing banner for --version
+func writeCompiler(t *testing.T, dir, name, banner string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	script := "#!/bin/sh\ncat <<'EOF'\n" + banner + "\nEOF\n"
+	if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDriverFromName(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/usr/bin/clang", driverClang},
+		{"clang++-18", driverClang},
+		{"clang-cl", driverClangCL},
+		{"clang-cl.exe", driverClangCL},
+		{"x86_64-linux-gnu-gcc-13", driverGCC},
+		{"g++", driverGCC},
+		{"cl.exe", driverMSVC},
+		{"cc", driverUnknown},
+		{"c++", driverUnknown},
+	}
+	for _, tt := range tests {
+		if got := driverFromName(tt.path); got != tt.want {
+			t.Errorf("driverFromName(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestDetectDriverResolvesCC(t *testing.T) {
+	dir := t.TempDir()
+	gcc := writeCompiler(t, dir, "gcc-13", "")
+	clang := writeCompiler(t, dir, "clang-18", "")
+
+	// Generic names carry no hint of the driver, so it comes from the
+	// link target or, for plain files, the banner
+	tests := []struct {
+		name    string
+		command string
+		target  string
+		banner  string
+		want    string
+	}{
+		{"cc linked to gcc", "cc", gcc, "", driverGCC},
+		{"c++ linked to clang", "c++", clang, "", driverClang},
+		{"cc printing a gcc banner", "cc", "", "cc (Ubuntu 13.2.0-4ubuntu3) 13.2.0\nCopyright (C) 2023 Free Software Foundation, Inc.", driverGCC},
+		{"c++ printing a clang banner", "c++", "", "Apple clang version 15.0.0 (clang-1500.3.9.4)\nTarget: arm64-apple-darwin23.4.0", driverClang},
+		{"unrecognized banner", "cc", "", "tcc version 0.9.27", driverUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.command)
+			os.Remove(path)
+			if tt.target != "" {
+				if err := os.Symlink(tt.target, path); err != nil {
+					t.Fatal(err)
+				}
+			} else {
+				writeCompiler(t, dir, tt.command, tt.banner)
+			}
+
+			if got := detectDriver(path); got != tt.want {
+				t.Errorf("detectDriver = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseClangCLOptions(t *testing.T) {
+	input := filepath.Join(t.TempDir(), "foo.c")
+	if err := os.WriteFile(input, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	args := []string{"/O2", "/Fo:out.obj", "-Xclang", "-fsave-optimization-record", "/c", input, "bar.c"}
+
+	tests := []struct {
+		driver string
+		want   []string
+	}{
+		{driverClangCL, []string{"/O2", "/Fo:out.obj", "-Xclang", "-fsave-optimization-record", "/c"}},
+		{driverClang, []string{"-Xclang", "-fsave-optimization-record"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.driver, func(t *testing.T) {
+			c := NewCollector(&models.BuildContext{})
+			c.info.Name = tt.driver
+			if got := c.parseCompilerOptions(args); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("options %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMSVCBanner(t *testing.T) {
+	tests := []struct {
+		banner      string
+		wantVersion string
+		wantTarget  string
+	}{
+		{"Microsoft (R) C/C++ Optimizing Compiler Version 19.38.33135 for x64\nCopyright (C) Microsoft Corporation.  All rights reserved.", "19.38.33135", "x64"},
+		{"Microsoft (R) C/C++ Optimizing Compiler Version 19.29.30154.0 for ARM64", "19.29.30154", "ARM64"},
+		{"usage: cl [ option... ] filename...", "", ""},
+	}
+	for _, tt := range tests {
+		version, target := parseMSVCBanner([]byte(tt.banner))
+		if version != tt.wantVersion || target != tt.wantTarget {
+			t.Errorf("parseMSVCBanner(%q) = %q, %q, want %q, %q", tt.banner, version, target, tt.wantVersion, tt.wantTarget)
+		}
+	}
+	if got := driverFromBanner(tests[0].banner); got != driverMSVC {
+		t.Errorf("driverFromBanner = %q, want %q", got, driverMSVC)
+	}
+}
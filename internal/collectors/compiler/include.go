@@ -0,0 +1,135 @@
+// internal/collectors/compiler/include.go
+package compiler
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// SystemIncludeDirs returns the directories the compiler searches for
+// #include <...>, as listed by -v. The compile's arguments that move the
+// search list, such as --sysroot and -isystem, are passed along. For cl.exe
+// the directories come from the INCLUDE environment variable.
+//
+// The list is cached per compiler binary and search arguments, so only the
+// first compile of a build pays for running the compiler twice.
+func SystemIncludeDirs(ctx context.Context, compiler string, args []string) ([]string, error) {
+	if detectDriver(compiler) == driverMSVC {
+		return filepath.SplitList(os.Getenv("INCLUDE")), nil
+	}
+
+	probeArgs := append(searchPathArgs(args), "-E", "-x", "c++", "-v", "-")
+	cachePath := includeCachePath(compiler, probeArgs)
+	if cachePath != "" {
+		if data, err := os.ReadFile(cachePath); err == nil {
+			return strings.Split(strings.TrimSpace(string(data)), "\n"), nil
+		}
+	}
+
+	cmd := exec.CommandContext(ctx, compiler, probeArgs...)
+	cmd.Stdin = strings.NewReader("")
+	output, err := cmd.CombinedOutput()
+	dirs := parseSearchDirs(string(output))
+	if len(dirs) == 0 {
+		if err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+
+	// A cache that cannot be written only costs the next compile a probe
+	if cachePath != "" && os.MkdirAll(filepath.Dir(cachePath), 0o755) == nil {
+		os.WriteFile(cachePath, []byte(strings.Join(dirs, "\n")+"\n"), 0o644)
+	}
+	return dirs, nil
+}
+
+// Options that change the system include search list: switches, options
+// taking the next argument and options joined with their value
+var (
+	searchPathSwitches = []string{"-nostdinc", "-nostdinc++", "-nostdlibinc", "-m32", "-m64"}
+	searchPathOptions  = []string{"--sysroot", "-isysroot", "-isystem", "-idirafter", "-target"}
+	searchPathPrefixes = []string{"--sysroot=", "--target=", "--gcc-toolchain=", "-stdlib=", "-isysroot", "-isystem", "-idirafter"}
+)
+
+// searchPathArgs picks the arguments of a compile that change where the
+// compiler looks for system headers
+func searchPathArgs(args []string) []string {
+	var picked []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case slices.Contains(searchPathSwitches, arg):
+			picked = append(picked, arg)
+		case slices.Contains(searchPathOptions, arg):
+			if i+1 < len(args) {
+				picked = append(picked, arg, args[i+1])
+				i++
+			}
+		case slices.ContainsFunc(searchPathPrefixes, func(prefix string) bool { return strings.HasPrefix(arg, prefix) }):
+			picked = append(picked, arg)
+		}
+	}
+	return picked
+}
+
+// includeCachePath is where the search list of a compiler run with args is
+// cached. The key covers the binary's size and modification time, so
+// upgrading the compiler starts a new entry, and the working directory
+// relative paths in args resolve against. It is empty when the compiler or
+// the user cache directory cannot be found.
+func includeCachePath(compiler string, args []string) string {
+	path, err := exec.LookPath(compiler)
+	if err != nil {
+		return ""
+	}
+	if path, err = filepath.Abs(path); err != nil {
+		return ""
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return ""
+	}
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+
+	workDir, _ := os.Getwd()
+
+	key := fmt.Sprintf("%s\x00%d\x00%d\x00%s\x00%s", path, info.Size(), info.ModTime().UnixNano(), workDir, strings.Join(args, "\x00"))
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(cacheDir, "builds", "include-dirs", hex.EncodeToString(sum[:]))
+}
+
+// parseSearchDirs reads the "#include <...> search starts here:" list that
+// GCC and Clang print with -v. Clang marks macOS framework directories
+// with a suffix, which is dropped.
+func parseSearchDirs(output string) []string {
+	var dirs []string
+	inList := false
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "#include <...> search starts here:"):
+			inList = true
+		case strings.HasPrefix(line, "End of search list."):
+			return dirs
+		case inList:
+			dir := strings.TrimSuffix(strings.TrimSpace(line), " (framework directory)")
+			if dir != "" {
+				dirs = append(dirs, dir)
+			}
+		}
+	}
+	return dirs
+}
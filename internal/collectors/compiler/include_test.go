@@ -0,0 +1,91 @@
+package compiler
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestParseSearchDirs(t *testing.T) {
+	output := `clang version 18.1.0
+Target: x86_64-apple-darwin23.1.0
+#include "..." search starts here:
+ /home/me/project/include
+#include <...> search starts here:
+ /usr/local/include
+ /usr/lib/clang/18/include
+ /System/Library/Frameworks (framework directory)
+End of search list.
+ /not/a/search/dir
+`
+	want := []string{"/usr/local/include", "/usr/lib/clang/18/include", "/System/Library/Frameworks"}
+	if got := parseSearchDirs(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if got := parseSearchDirs("cc: error: no input files\n"); got != nil {
+		t.Errorf("got %q from output without a search list", got)
+	}
+}
+
+func TestSearchPathArgs(t *testing.T) {
+	args := []string{
+		"-O2", "-c", "foo.c", "-o", "foo.o", "-Iinclude",
+		"--sysroot", "/opt/sysroot", "-isystem", "third_party", "-isystemvendor",
+		"--target=aarch64-linux-gnu", "-stdlib=libc++", "-nostdinc++", "-m32",
+		"-include", "config.h",
+	}
+	want := []string{
+		"--sysroot", "/opt/sysroot", "-isystem", "third_party", "-isystemvendor",
+		"--target=aarch64-linux-gnu", "-stdlib=libc++", "-nostdinc++", "-m32",
+	}
+	if got := searchPathArgs(args); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestSystemIncludeDirsCached(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("runs a shell script as the compiler")
+	}
+	dir := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(dir, "cache"))
+	t.Setenv("HOME", dir)
+
+	// The fake compiler logs its arguments and lists one search directory
+	log := filepath.Join(dir, "calls")
+	compiler := filepath.Join(dir, "gcc")
+	script := `#!/bin/sh
+echo "$*" >> ` + log + `
+echo '#include <...> search starts here:' >&2
+echo ' /usr/include' >&2
+echo 'End of search list.' >&2
+`
+	if err := os.WriteFile(compiler, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	for range 2 {
+		dirs, err := SystemIncludeDirs(ctx, compiler, []string{"-O2", "-c", "foo.c"})
+		if err != nil || !reflect.DeepEqual(dirs, []string{"/usr/include"}) {
+			t.Fatalf("got %q, %v", dirs, err)
+		}
+	}
+	if _, err := SystemIncludeDirs(ctx, compiler, []string{"--sysroot=/opt/sysroot", "-c", "foo.c"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The second compile is served from the cache; a sysroot is probed
+	data, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "-E -x c++ -v -\n--sysroot=/opt/sysroot -E -x c++ -v -\n"
+	if string(data) != want {
+		t.Errorf("compiler ran with\n%swant\n%s", data, want)
+	}
+}
@@ -0,0 +1,34 @@
+// internal/collectors/compiler/invocations.go
+package compiler
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+
+	"builds/internal/models"
+	"builds/internal/parsers/invocations"
+)
+
+// Invocations lists the programs the compiler driver runs for args, such as
+// cc1, the assembler and the linker, along with the default flags it passes
+// them. It uses -###, which prints the commands without running them, so
+// it is cheap but does not work with cl.exe.
+func Invocations(ctx context.Context, compiler string, args []string) ([]models.Invocation, error) {
+	if detectDriver(compiler) == driverMSVC {
+		return nil, fmt.Errorf("%s cannot list its sub-invocations", compiler)
+	}
+
+	cmd := exec.CommandContext(ctx, compiler, append([]string{"-###"}, args...)...)
+	output, runErr := cmd.CombinedOutput()
+
+	parsed, err := invocations.Parse(bytes.NewReader(output))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse -### output: %w", err)
+	}
+	if len(parsed) == 0 && runErr != nil {
+		return nil, fmt.Errorf("%s -### failed: %w", compiler, runErr)
+	}
+	return parsed, nil
+}
@@ -0,0 +1,204 @@
+// internal/collectors/compiler/language.go
+package compiler
+
+import (
+	"path/filepath"
+	"strings"
+
+	"builds/internal/models"
+)
+
+// Source languages recognized in compiler commands
+const (
+	langC         = "C"
+	langCXX       = "C++"
+	langCUDA      = "CUDA"
+	langHIP       = "HIP"
+	langObjC      = "Objective-C"
+	langObjCXX    = "Objective-C++"
+	langOpenCL    = "OpenCL"
+	langFortran   = "Fortran"
+	langRust      = "Rust"
+	langAssembler = "Assembly"
+)
+
+// extensionLanguages maps source file extensions to their language. .C is
+// C++ by the GCC convention, so the lookup is case-sensitive.
+var extensionLanguages = map[string]string{
+	".c": langC, ".i": langC,
+	".cc": langCXX, ".cp": langCXX, ".cpp": langCXX, ".cxx": langCXX, ".c++": langCXX, ".C": langCXX, ".CPP": langCXX, ".ii": langCXX,
+	".cu":  langCUDA,
+	".hip": langHIP,
+	".m":   langObjC,
+	".mm":  langObjCXX, ".M": langObjCXX,
+	".cl": langOpenCL,
+	".f":  langFortran, ".for": langFortran, ".ftn": langFortran, ".f77": langFortran,
+	".f90": langFortran, ".f95": langFortran, ".f03": langFortran, ".f08": langFortran,
+	".F": langFortran, ".FOR": langFortran, ".F90": langFortran, ".F95": langFortran, ".F03": langFortran, ".F08": langFortran,
+	".rs": langRust,
+	".s":  langAssembler, ".S": langAssembler, ".sx": langAssembler, ".asm": langAssembler,
+}
+
+// typeLanguages maps the values of -x to their language
+var typeLanguages = map[string]string{
+	"c": langC, "c-header": langC, "cpp-output": langC,
+	"c++": langCXX, "c++-header": langCXX, "c++-cpp-output": langCXX,
+	"cuda":        langCUDA,
+	"hip":         langHIP,
+	"objective-c": langObjC, "objective-c-header": langObjC,
+	"objective-c++": langObjCXX, "objective-c++-header": langObjCXX,
+	"cl":  langOpenCL,
+	"f77": langFortran, "f77-cpp-input": langFortran, "f95": langFortran, "f95-cpp-input": langFortran,
+	"assembler": langAssembler, "assembler-with-cpp": langAssembler,
+}
+
+// standard is a language revision selected with -std or /std
+type standard struct {
+	language      string
+	version       string
+	specification string
+}
+
+// standards maps -std and /std values to the revision they select
+var standards = map[string]standard{
+	"c89":            {langC, "C89", "ANSI X3.159-1989"},
+	"c90":            {langC, "C90", "ISO/IEC 9899:1990"},
+	"iso9899:1990":   {langC, "C90", "ISO/IEC 9899:1990"},
+	"iso9899:199409": {langC, "C95", "ISO/IEC 9899:1990/AMD1:1995"},
+	"c99":            {langC, "C99", "ISO/IEC 9899:1999"},
+	"c9x":            {langC, "C99", "ISO/IEC 9899:1999"},
+	"iso9899:1999":   {langC, "C99", "ISO/IEC 9899:1999"},
+	"c11":            {langC, "C11", "ISO/IEC 9899:2011"},
+	"c1x":            {langC, "C11", "ISO/IEC 9899:2011"},
+	"iso9899:2011":   {langC, "C11", "ISO/IEC 9899:2011"},
+	"c17":            {langC, "C17", "ISO/IEC 9899:2018"},
+	"c18":            {langC, "C17", "ISO/IEC 9899:2018"},
+	"iso9899:2017":   {langC, "C17", "ISO/IEC 9899:2018"},
+	"iso9899:2018":   {langC, "C17", "ISO/IEC 9899:2018"},
+	"c2x":            {langC, "C23", "ISO/IEC 9899:2024"},
+	"c23":            {langC, "C23", "ISO/IEC 9899:2024"},
+	"iso9899:2024":   {langC, "C23", "ISO/IEC 9899:2024"},
+	"c2y":            {langC, "C2y", ""},
+	"c++98":          {langCXX, "C++98", "ISO/IEC 14882:1998"},
+	"c++03":          {langCXX, "C++03", "ISO/IEC 14882:2003"},
+	"c++11":          {langCXX, "C++11", "ISO/IEC 14882:2011"},
+	"c++0x":          {langCXX, "C++11", "ISO/IEC 14882:2011"},
+	"c++14":          {langCXX, "C++14", "ISO/IEC 14882:2014"},
+	"c++1y":          {langCXX, "C++14", "ISO/IEC 14882:2014"},
+	"c++17":          {langCXX, "C++17", "ISO/IEC 14882:2017"},
+	"c++1z":          {langCXX, "C++17", "ISO/IEC 14882:2017"},
+	"c++20":          {langCXX, "C++20", "ISO/IEC 14882:2020"},
+	"c++2a":          {langCXX, "C++20", "ISO/IEC 14882:2020"},
+	"c++23":          {langCXX, "C++23", "ISO/IEC 14882:2024"},
+	"c++2b":          {langCXX, "C++23", "ISO/IEC 14882:2024"},
+	"c++26":          {langCXX, "C++26", ""},
+	"c++2c":          {langCXX, "C++26", ""},
+	"c++latest":      {langCXX, "C++latest", ""}, // MSVC's newest, partly implemented revision
+	"clatest":        {langC, "Clatest", ""},
+	"f95":            {langFortran, "Fortran 95", "ISO/IEC 1539-1:1997"},
+	"f2003":          {langFortran, "Fortran 2003", "ISO/IEC 1539-1:2004"},
+	"f2008":          {langFortran, "Fortran 2008", "ISO/IEC 1539-1:2010"},
+	"f2018":          {langFortran, "Fortran 2018", "ISO/IEC 1539-1:2018"},
+	"f2023":          {langFortran, "Fortran 2023", "ISO/IEC 1539-1:2023"},
+	"cl1.0":          {langOpenCL, "OpenCL C 1.0", ""},
+	"cl1.1":          {langOpenCL, "OpenCL C 1.1", ""},
+	"cl1.2":          {langOpenCL, "OpenCL C 1.2", ""},
+	"cl2.0":          {langOpenCL, "OpenCL C 2.0", ""},
+	"cl3.0":          {langOpenCL, "OpenCL C 3.0", ""},
+}
+
+// defaultLanguage is reported for GCC and Clang when the command names
+// neither a source file nor a standard
+var defaultLanguage = models.Language{
+	Name:          "C/C++",
+	Version:       "C++17",
+	Specification: "ISO/IEC 14882:2017",
+}
+
+// detectLanguage determines the source language and standard of a
+// compiler command from its -std, -x and /T options and the extension of
+// its first source file. Commands compiling several languages report the
+// first. The version is left empty when the command does not select a
+// standard, as the default depends on the compiler release. ok is false
+// when nothing was detected.
+func detectLanguage(args []string, clOptions bool) (lang models.Language, ok bool) {
+	var (
+		source   string // Language of the first source file
+		forced   string // Language set by -x or /TC and /TP for later inputs
+		selected *standard
+	)
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "-x" && i+1 < len(args):
+			i++
+			forced = typeLanguages[args[i]]
+		case strings.HasPrefix(arg, "-x") && len(arg) > 2:
+			forced = typeLanguages[arg[2:]]
+		case strings.HasPrefix(arg, "-std=") || strings.HasPrefix(arg, "--std="):
+			selected = parseStandard(arg[strings.Index(arg, "=")+1:])
+		case strings.HasPrefix(arg, "--edition="):
+			// rustc
+			selected = &standard{langRust, "Rust " + strings.TrimPrefix(arg, "--edition="), ""}
+		case arg == "-o" || arg == "-MF" || arg == "-MT" || arg == "-MQ":
+			i++ // Skip the output path
+		case clOptions && isCLOption(arg):
+			// /TC and /TP set the language of every source, /Tc and /Tp
+			// of the file they name
+			option := arg[1:]
+			switch {
+			case strings.HasPrefix(strings.ToLower(option), "std:"):
+				selected = parseStandard(option[len("std:"):])
+			case option == "TC":
+				forced = langC
+			case option == "TP":
+				forced = langCXX
+			case strings.HasPrefix(option, "Tc") && source == "":
+				source = langC
+			case strings.HasPrefix(option, "Tp") && source == "":
+				source = langCXX
+			}
+		case strings.HasPrefix(arg, "-"):
+		case source == "":
+			if forced != "" {
+				source = forced
+			} else {
+				source = extensionLanguages[filepath.Ext(arg)]
+			}
+		}
+	}
+
+	switch {
+	case selected == nil && source == "":
+		return models.Language{}, false
+	case selected == nil:
+		return models.Language{Name: source}, true
+	}
+
+	lang = models.Language{Name: selected.language, Version: selected.version, Specification: selected.specification}
+	// CUDA, HIP and Objective-C take the C or C++ standard they extend
+	if source != "" && source != selected.language {
+		lang.Name = source
+	}
+	return lang, true
+}
+
+// parseStandard looks up a -std value. GNU dialects such as gnu++17 are
+// read as the revision they extend, noting the extensions in the
+// specification.
+func parseStandard(value string) *standard {
+	value = strings.ToLower(value)
+	rest, gnu := strings.CutPrefix(value, "gnu")
+	if gnu {
+		value = "c" + rest
+	}
+	std, ok := standards[value]
+	if !ok {
+		return nil
+	}
+	if gnu {
+		std.specification = strings.TrimSpace(std.specification + " with GNU extensions")
+	}
+	return &std
+}
@@ -0,0 +1,35 @@
+package compiler
+
+import (
+	"testing"
+
+	"builds/internal/models"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		clOptions bool
+		want      models.Language
+		wantOK    bool
+	}{
+		{"C++20", []string{"-std=c++20", "-c", "foo.cpp"}, false, models.Language{Name: "C++", Version: "C++20", Specification: "ISO/IEC 14882:2020"}, true},
+		{"GNU dialect", []string{"-std=gnu17", "foo.c"}, false, models.Language{Name: "C", Version: "C17", Specification: "ISO/IEC 9899:2018 with GNU extensions"}, true},
+		{"extension only", []string{"-O2", "-c", "foo.c"}, false, models.Language{Name: "C"}, true},
+		{"CUDA extends C++", []string{"-std=c++17", "kernel.cu"}, false, models.Language{Name: "CUDA", Version: "C++17", Specification: "ISO/IEC 14882:2017"}, true},
+		{"forced by -x", []string{"-x", "c++", "foo.c"}, false, models.Language{Name: "C++"}, true},
+		{"output not taken for a source", []string{"-o", "foo.cpp", "bar.f90"}, false, models.Language{Name: "Fortran"}, true},
+		{"rust edition", []string{"--edition=2021", "main.rs"}, false, models.Language{Name: "Rust", Version: "Rust 2021"}, true},
+		{"cl.exe", []string{"/std:c++20", "/TP", "foo.c"}, true, models.Language{Name: "C++", Version: "C++20", Specification: "ISO/IEC 14882:2020"}, true},
+		{"nothing to go by", []string{"-O2", "foo.o"}, false, models.Language{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := detectLanguage(tt.args, tt.clOptions)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("got %+v, %v, want %+v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+// internal/collectors/compiler/probe.go
+package compiler
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Driver names the kind of compiler a command runs: clang, clang-cl, gcc,
+// msvc, nvcc, rustc or unknown
+func Driver(compiler string) string {
+	return detectDriver(compiler)
+}
+
+// CheckOptimizationRecord compiles an empty C file with the flags the
+// remarks collector adds, reporting why remarks could not be collected
+// from this compiler. cl.exe writes its vectorizer reports to the console
+// rather than a record file, so it is not probed.
+func CheckOptimizationRecord(ctx context.Context, compiler string) error {
+	if detectDriver(compiler) == driverMSVC {
+		return nil
+	}
+
+	dir, err := os.MkdirTemp("", "builds-probe")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	record := filepath.Join(dir, "probe.opt.yaml")
+	cmd := exec.CommandContext(ctx, compiler,
+		"-fsave-optimization-record",
+		"-foptimization-record-file="+record,
+		"-O2", "-x", "c", "-c", "-", "-o", filepath.Join(dir, "probe.o"))
+	cmd.Stdin = strings.NewReader("int probe(int x) { return x * 2; }\n")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		if msg := strings.TrimSpace(string(output)); msg != "" {
+			return fmt.Errorf("%s rejected -fsave-optimization-record: %s", compiler, firstLine(msg))
+		}
+		return fmt.Errorf("%s rejected -fsave-optimization-record: %w", compiler, err)
+	}
+	if _, err := os.Stat(record); err != nil {
+		return fmt.Errorf("%s accepted -fsave-optimization-record but wrote no record", compiler)
+	}
+	return nil
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
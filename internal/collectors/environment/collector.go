@@ -7,6 +7,7 @@ import (
 	"os"
 	"runtime"
 	"strings"
+	"time"
 
 	"builds/internal/models"
 )
@@ -14,7 +15,8 @@ import (
 // Collector implements environment information collection
 type Collector struct {
 	models.BaseCollector
-	info models.Environment
+	info     models.Environment
+	noRedact bool
 }
 
 // NewCollector creates a new environment collector
@@ -22,6 +24,12 @@ func NewCollector() *Collector {
 	return &Collector{}
 }
 
+// DisableRedaction keeps sensitive variables such as tokens and passwords.
+// Only meant for debugging on trusted machines.
+func (c *Collector) DisableRedaction() {
+	c.noRedact = true
+}
+
 // Initialize prepares the environment collector
 func (c *Collector) Initialize(ctx context.Context) error {
 	return nil
@@ -42,15 +50,22 @@ func (c *Collector) Collect(ctx context.Context) error {
 
 	// Get environment variables
 	c.info.Variables = make(map[string]string)
+	c.info.Locale = make(map[string]string)
 	for _, env := range os.Environ() {
 		if key, value, ok := splitEnv(env); ok {
+			if isLocaleEnv(key) {
+				c.info.Locale[key] = value
+			}
 			// Filter sensitive environment variables
-			if !isSensitiveEnv(key) {
+			if c.noRedact || !IsSensitiveEnv(key) {
 				c.info.Variables[key] = value
 			}
 		}
 	}
 
+	c.info.Timezone = timezone()
+	c.info.Umask = umask()
+
 	return nil
 }
 
@@ -73,8 +88,28 @@ func splitEnv(env string) (key, value string, ok bool) {
 	return parts[0], parts[1], true
 }
 
-// isSensitiveEnv checks if an environment variable is sensitive
-func isSensitiveEnv(key string) bool {
+// isLocaleEnv reports whether a variable configures the locale
+func isLocaleEnv(key string) bool {
+	return key == "LANG" || key == "LANGUAGE" || strings.HasPrefix(key, "LC_")
+}
+
+// timezone returns the IANA name of the local timezone when it can be
+// determined, falling back to the zone abbreviation
+func timezone() string {
+	if tz := os.Getenv("TZ"); tz != "" {
+		return tz
+	}
+	if target, err := os.Readlink("/etc/localtime"); err == nil {
+		if _, name, ok := strings.Cut(target, "zoneinfo/"); ok {
+			return name
+		}
+	}
+	name, _ := time.Now().Zone()
+	return name
+}
+
+// IsSensitiveEnv checks if an environment variable is sensitive
+func IsSensitiveEnv(key string) bool {
 	sensitiveKeys := map[string]bool{
 		"PATH":           false,
 		"HOME":           false,
@@ -0,0 +1,73 @@
+package environment
+
+import (
+	"context"
+	"regexp"
+	"runtime"
+	"testing"
+
+	"builds/internal/models"
+)
+
+func collect(t *testing.T, c *Collector) models.Environment {
+	t.Helper()
+	if err := c.Collect(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return c.GetData().(models.Environment)
+}
+
+func TestCollectReproducibilityFields(t *testing.T) {
+	t.Setenv("LANG", "de_DE.UTF-8")
+	t.Setenv("LC_ALL", "C")
+	// Redacted from the variables, but still part of the locale
+	t.Setenv("LC_SECRET_TOKEN", "C.UTF-8")
+	t.Setenv("TZ", "Europe/Berlin")
+
+	env := collect(t, NewCollector())
+
+	for key, want := range map[string]string{"LANG": "de_DE.UTF-8", "LC_ALL": "C", "LC_SECRET_TOKEN": "C.UTF-8"} {
+		if got := env.Locale[key]; got != want {
+			t.Errorf("Locale[%s] = %q, want %q", key, got, want)
+		}
+	}
+	if _, ok := env.Variables["LC_SECRET_TOKEN"]; ok {
+		t.Error("sensitive variable kept in Variables")
+	}
+	if env.Timezone != "Europe/Berlin" {
+		t.Errorf("Timezone = %q, want Europe/Berlin", env.Timezone)
+	}
+	if runtime.GOOS != "windows" && !regexp.MustCompile(`^0[0-7]{3}$`).MatchString(env.Umask) {
+		t.Errorf("Umask = %q, want an octal mask", env.Umask)
+	}
+}
+
+func TestCollectRedaction(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "ghp_secret")
+	t.Setenv("CC", "clang")
+
+	tests := []struct {
+		name       string
+		noRedact   bool
+		wantSecret bool
+	}{
+		{"redacted by default", false, false},
+		{"kept with redaction disabled", true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCollector()
+			if tt.noRedact {
+				c.DisableRedaction()
+			}
+			env := collect(t, c)
+
+			if _, got := env.Variables["GITHUB_TOKEN"]; got != tt.wantSecret {
+				t.Errorf("GITHUB_TOKEN kept = %v, want %v", got, tt.wantSecret)
+			}
+			if env.Variables["CC"] != "clang" {
+				t.Errorf("CC = %q, want clang", env.Variables["CC"])
+			}
+		})
+	}
+}
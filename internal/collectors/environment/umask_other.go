@@ -0,0 +1,10 @@
+// internal/collectors/environment/umask_other.go
+
+//go:build !unix
+
+package environment
+
+// umask is not available on this platform
+func umask() string {
+	return ""
+}
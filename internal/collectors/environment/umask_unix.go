@@ -0,0 +1,41 @@
+// internal/collectors/environment/umask_unix.go
+
+//go:build unix
+
+package environment
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+)
+
+// umask returns the process file mode creation mask in octal. Linux reports
+// it in /proc/self/status; elsewhere it can only be read by setting it,
+// which races with other goroutines creating files in the meantime.
+func umask() string {
+	if data, err := os.ReadFile("/proc/self/status"); err == nil {
+		if mask, ok := statusUmask(data); ok {
+			return mask
+		}
+	}
+
+	mask := syscall.Umask(0)
+	syscall.Umask(mask)
+	return fmt.Sprintf("%04o", mask)
+}
+
+// statusUmask reads the Umask field of /proc/self/status, present since
+// Linux 4.7
+func statusUmask(status []byte) (string, bool) {
+	scanner := bufio.NewScanner(bytes.NewReader(status))
+	for scanner.Scan() {
+		if value, ok := strings.CutPrefix(scanner.Text(), "Umask:"); ok {
+			return strings.TrimSpace(value), true
+		}
+	}
+	return "", false
+}
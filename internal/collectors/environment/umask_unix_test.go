@@ -0,0 +1,16 @@
+//go:build unix
+
+package environment
+
+import "testing"
+
+func TestStatusUmask(t *testing.T) {
+	status := "Name:\tbuilds\nUmask:\t0027\nState:\tR (running)\n"
+	if mask, ok := statusUmask([]byte(status)); !ok || mask != "0027" {
+		t.Errorf("got %q, %v, want 0027", mask, ok)
+	}
+	// Kernels before 4.7 leave the field out
+	if mask, ok := statusUmask([]byte("Name:\tbuilds\n")); ok {
+		t.Errorf("got %q from a status without a mask", mask)
+	}
+}
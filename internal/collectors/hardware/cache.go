@@ -0,0 +1,80 @@
+// internal/collectors/hardware/cache.go
+
+package hardware
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// sysfsCacheDir lists the caches of the first CPU on Linux, one index*
+// directory per cache
+const sysfsCacheDir = "/sys/devices/system/cpu/cpu0/cache"
+
+// lastLevelCacheSize returns the size in bytes of the highest-level data
+// or unified cache described under dir, usually the L3 shared by the
+// cores. It returns 0 when dir lists no readable cache, as on platforms
+// without sysfs.
+func lastLevelCacheSize(dir string) int64 {
+	indexes, err := filepath.Glob(filepath.Join(dir, "index*"))
+	if err != nil {
+		return 0
+	}
+
+	var bestLevel int
+	var bestSize int64
+	for _, index := range indexes {
+		if readSysfs(index, "type") == "Instruction" {
+			continue
+		}
+		level, err := strconv.Atoi(readSysfs(index, "level"))
+		if err != nil {
+			continue
+		}
+		size, err := parseCacheSize(readSysfs(index, "size"))
+		if err != nil {
+			continue
+		}
+		if level > bestLevel || level == bestLevel && size > bestSize {
+			bestLevel, bestSize = level, size
+		}
+	}
+	return bestSize
+}
+
+// readSysfs returns the trimmed contents of a sysfs attribute, or "" when
+// it cannot be read
+func readSysfs(dir, name string) string {
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// parseCacheSize parses a sysfs cache size such as 48K, 2048K or 32M into
+// bytes. A number without suffix is taken as bytes.
+func parseCacheSize(s string) (int64, error) {
+	value := strings.TrimSpace(s)
+	multiplier := int64(1)
+	switch {
+	case strings.HasSuffix(value, "K"):
+		multiplier = 1024
+	case strings.HasSuffix(value, "M"):
+		multiplier = 1024 * 1024
+	case strings.HasSuffix(value, "G"):
+		multiplier = 1024 * 1024 * 1024
+	}
+	if multiplier > 1 {
+		value = value[:len(value)-1]
+	}
+
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid cache size %q", s)
+	}
+	return n * multiplier, nil
+}
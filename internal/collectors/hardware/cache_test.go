@@ -0,0 +1,72 @@
+package hardware
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeCache writes the sysfs attributes of one cache under dir
+func writeCache(t *testing.T, dir, index string, attrs map[string]string) {
+	t.Helper()
+	path := filepath.Join(dir, index)
+	if err := os.MkdirAll(path, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, value := range attrs {
+		if err := os.WriteFile(filepath.Join(path, name), []byte(value+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestParseCacheSize(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{"48K", 48 * 1024, false},
+		{"2048K", 2048 * 1024, false},
+		{"32M", 32 * 1024 * 1024, false},
+		{"1G", 1024 * 1024 * 1024, false},
+		{" 512\n", 512, false},
+		{"", 0, true},
+		{"K", 0, true},
+		{"-4K", 0, true},
+		{"12KB", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseCacheSize(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseCacheSize(%q) = %d, %v, want %d, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestLastLevelCacheSize(t *testing.T) {
+	dir := t.TempDir()
+	writeCache(t, dir, "index0", map[string]string{"level": "1", "type": "Data", "size": "48K"})
+	writeCache(t, dir, "index1", map[string]string{"level": "1", "type": "Instruction", "size": "32K"})
+	writeCache(t, dir, "index2", map[string]string{"level": "2", "type": "Unified", "size": "2048K"})
+	writeCache(t, dir, "index3", map[string]string{"level": "3", "type": "Unified", "size": "32M"})
+
+	if got := lastLevelCacheSize(dir); got != 32*1024*1024 {
+		t.Errorf("got %d, want the 32M L3", got)
+	}
+
+	// Without an L3, the L2 is the last level; an unreadable cache is
+	// skipped
+	dir = t.TempDir()
+	writeCache(t, dir, "index0", map[string]string{"level": "1", "type": "Data", "size": "32K"})
+	writeCache(t, dir, "index2", map[string]string{"level": "2", "type": "Unified", "size": "1024K"})
+	writeCache(t, dir, "index3", map[string]string{"level": "3", "type": "Unified", "size": "unknown"})
+	if got := lastLevelCacheSize(dir); got != 1024*1024 {
+		t.Errorf("got %d, want the 1024K L2", got)
+	}
+
+	// Platforms without sysfs
+	if got := lastLevelCacheSize(filepath.Join(t.TempDir(), "missing")); got != 0 {
+		t.Errorf("got %d without a cache directory, want 0", got)
+	}
+}
@@ -36,21 +36,21 @@ func (c *Collector) Initialize(ctx context.Context) error {
 // Collect gathers hardware information
 func (c *Collector) Collect(ctx context.Context) error {
 	// Collect CPU information
-	cpuInfo, err := c.collectCPUInfo()
+	cpuInfo, err := c.collectCPUInfo(ctx)
 	if err != nil {
 		return err
 	}
 	c.info.CPU = cpuInfo
 
 	// Collect memory information
-	memInfo, err := c.collectMemoryInfo()
+	memInfo, err := c.collectMemoryInfo(ctx)
 	if err != nil {
 		return err
 	}
 	c.info.Memory = memInfo
 
 	// Collect GPU information
-	gpus, err := c.collectGPUInfo()
+	gpus, err := c.collectGPUInfo(ctx)
 	if err != nil {
 		return err
 	}
@@ -70,10 +70,10 @@ func (c *Collector) Cleanup(ctx context.Context) error {
 }
 
 // collectCPUInfo gathers CPU information
-func (c *Collector) collectCPUInfo() (models.CPU, error) {
+func (c *Collector) collectCPUInfo(ctx context.Context) (models.CPU, error) {
 	var cpuInfo models.CPU
 
-	info, err := cpu.Info()
+	info, err := cpu.InfoWithContext(ctx)
 	if err != nil {
 		return cpuInfo, err
 	}
@@ -82,25 +82,44 @@ func (c *Collector) collectCPUInfo() (models.CPU, error) {
 		cpuInfo.Model = info[0].ModelName
 		cpuInfo.Vendor = info[0].VendorID
 		cpuInfo.Frequency = float64(info[0].Mhz)
-		cpuInfo.Cores = int32(runtime.NumCPU())
-		cpuInfo.Threads = int32(runtime.GOMAXPROCS(0))
-		// CPU cache size might require platform-specific code
-		cpuInfo.CacheSize = 0 // This would need to be implemented based on platform
+		cpuInfo.Cores, cpuInfo.Threads = cpuCounts(ctx)
+		cpuInfo.NUMANodes = numaNodeCount(sysfsNodeDir)
+		// sysfs describes every cache level; elsewhere gopsutil reports
+		// one size in KB, if any
+		cpuInfo.CacheSize = lastLevelCacheSize(sysfsCacheDir)
+		if cpuInfo.CacheSize == 0 {
+			cpuInfo.CacheSize = int64(info[0].CacheSize) * 1024
+		}
 	}
 
 	return cpuInfo, nil
 }
 
+// cpuCounts returns the physical cores and logical CPUs of the machine.
+// Where gopsutil cannot count physical cores, the logical count is
+// reported for both.
+func cpuCounts(ctx context.Context) (cores, threads int32) {
+	logical, err := cpu.CountsWithContext(ctx, true)
+	if err != nil || logical <= 0 {
+		logical = runtime.NumCPU()
+	}
+	physical, err := cpu.CountsWithContext(ctx, false)
+	if err != nil || physical <= 0 {
+		physical = logical
+	}
+	return int32(physical), int32(logical)
+}
+
 // collectMemoryInfo gathers memory information
-func (c *Collector) collectMemoryInfo() (models.Memory, error) {
+func (c *Collector) collectMemoryInfo(ctx context.Context) (models.Memory, error) {
 	var memInfo models.Memory
 
-	virtualMemory, err := mem.VirtualMemory()
+	virtualMemory, err := mem.VirtualMemoryWithContext(ctx)
 	if err != nil {
 		return memInfo, err
 	}
 
-	swapMemory, err := mem.SwapMemory()
+	swapMemory, err := mem.SwapMemoryWithContext(ctx)
 	if err != nil {
 		return memInfo, err
 	}
@@ -115,28 +134,29 @@ func (c *Collector) collectMemoryInfo() (models.Memory, error) {
 }
 
 // collectGPUInfo gathers GPU information
-func (c *Collector) collectGPUInfo() ([]models.GPU, error) {
+func (c *Collector) collectGPUInfo(ctx context.Context) ([]models.GPU, error) {
 	var gpus []models.GPU
 
 	// Try NVIDIA-SMI first
-	if nvidiaGPUs, err := c.collectNvidiaGPUInfo(); err == nil {
+	if nvidiaGPUs, err := c.collectNvidiaGPUInfo(ctx); err == nil {
 		gpus = append(gpus, nvidiaGPUs...)
 	}
 
 	// Try AMD ROCm
-	if amdGPUs, err := c.collectAMDGPUInfo(); err == nil {
+	if amdGPUs, err := c.collectAMDGPUInfo(ctx); err == nil {
 		gpus = append(gpus, amdGPUs...)
 	}
 
-	return gpus, nil
+	// Missing tools are not an error, but a tool killed by the timeout is
+	return gpus, ctx.Err()
 }
 
 // collectNvidiaGPUInfo gathers NVIDIA GPU information using nvidia-smi
-func (c *Collector) collectNvidiaGPUInfo() ([]models.GPU, error) {
+func (c *Collector) collectNvidiaGPUInfo(ctx context.Context) ([]models.GPU, error) {
 	var gpus []models.GPU
 
 	// Execute nvidia-smi and parse output
-	cmd := exec.Command("nvidia-smi", "--query-gpu=gpu_name,memory.total,driver_version,compute_cap", "--format=csv,noheader,nounits")
+	cmd := exec.CommandContext(ctx, "nvidia-smi", "--query-gpu=gpu_name,memory.total,driver_version,compute_cap", "--format=csv,noheader,nounits")
 	output, err := cmd.Output()
 	if err != nil {
 		return nil, err
@@ -160,11 +180,11 @@ func (c *Collector) collectNvidiaGPUInfo() ([]models.GPU, error) {
 }
 
 // collectAMDGPUInfo gathers AMD GPU information using rocm-smi
-func (c *Collector) collectAMDGPUInfo() ([]models.GPU, error) {
+func (c *Collector) collectAMDGPUInfo(ctx context.Context) ([]models.GPU, error) {
 	var gpus []models.GPU
 
 	// Execute rocm-smi and parse output
-	cmd := exec.Command("rocm-smi", "--showproductname", "--showmeminfo", "--showdriver")
+	cmd := exec.CommandContext(ctx, "rocm-smi", "--showproductname", "--showmeminfo", "--showdriver")
 	output, err := cmd.Output()
 	if err != nil {
 		return nil, err
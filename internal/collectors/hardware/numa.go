@@ -0,0 +1,34 @@
+// internal/collectors/hardware/numa.go
+
+package hardware
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// sysfsNodeDir lists the NUMA nodes on Linux as node0, node1 and so on
+const sysfsNodeDir = "/sys/devices/system/node"
+
+// numaNodeCount returns the number of NUMA nodes listed under dir, or 0
+// when it cannot be read, as on platforms without sysfs or kernels built
+// without NUMA support
+func numaNodeCount(dir string) int32 {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0
+	}
+
+	var nodes int32
+	for _, entry := range entries {
+		id, ok := strings.CutPrefix(entry.Name(), "node")
+		if !ok || !entry.IsDir() {
+			continue
+		}
+		if _, err := strconv.Atoi(id); err == nil {
+			nodes++
+		}
+	}
+	return nodes
+}
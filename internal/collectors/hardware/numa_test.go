@@ -0,0 +1,29 @@
+package hardware
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNumaNodeCount(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"node0", "node1", "power"} {
+		if err := os.Mkdir(filepath.Join(dir, name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Attributes beside the node directories are not nodes
+	for _, name := range []string{"online", "possible", "node2"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("0-1\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := numaNodeCount(dir); got != 2 {
+		t.Errorf("got %d nodes, want 2", got)
+	}
+	if got := numaNodeCount(filepath.Join(dir, "missing")); got != 0 {
+		t.Errorf("got %d nodes without sysfs, want 0", got)
+	}
+}
@@ -0,0 +1,88 @@
+// internal/collectors/labels/collector.go
+
+package labels
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"builds/internal/collectors/environment"
+	"builds/internal/models"
+)
+
+// DefaultVariables lists the well-known CI variables mapped into labels
+var DefaultVariables = []string{
+	// GitHub Actions
+	"GITHUB_SHA",
+	"GITHUB_REF",
+	"GITHUB_RUN_ID",
+	"GITHUB_REPOSITORY",
+	// GitLab CI
+	"CI_COMMIT_SHA",
+	"CI_JOB_ID",
+	"CI_PIPELINE_ID",
+	"CI_PROJECT_PATH",
+	// Jenkins
+	"BUILD_NUMBER",
+	"BUILD_URL",
+	"JOB_NAME",
+}
+
+// Collector maps selected environment variables into build labels
+type Collector struct {
+	models.BaseCollector
+	variables []string
+	explicit  map[string]string
+	labels    map[string]string
+}
+
+// NewCollector creates a label collector reading the given variables.
+// Explicit labels take precedence over those derived from the environment.
+func NewCollector(variables []string, explicit map[string]string) *Collector {
+	return &Collector{
+		variables: variables,
+		explicit:  explicit,
+	}
+}
+
+// Initialize prepares the label collector
+func (c *Collector) Initialize(ctx context.Context) error {
+	return nil
+}
+
+// Collect gathers labels from the environment
+func (c *Collector) Collect(ctx context.Context) error {
+	c.labels = make(map[string]string)
+
+	for _, name := range c.variables {
+		// Never leak variables that the environment collector would redact
+		if environment.IsSensitiveEnv(name) {
+			continue
+		}
+		if value, ok := os.LookupEnv(name); ok && value != "" {
+			c.labels[labelKey(name)] = value
+		}
+	}
+
+	for k, v := range c.explicit {
+		c.labels[k] = v
+	}
+
+	return nil
+}
+
+// GetData returns the collected labels
+func (c *Collector) GetData() interface{} {
+	return c.labels
+}
+
+// Cleanup performs any necessary cleanup
+func (c *Collector) Cleanup(ctx context.Context) error {
+	return nil
+}
+
+// labelKey derives a label key from an environment variable name
+func labelKey(name string) string {
+	return strings.ToLower(name)
+}
@@ -0,0 +1,34 @@
+package labels
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestCollectLabels(t *testing.T) {
+	t.Setenv("GITHUB_SHA", "abc123")
+	t.Setenv("CI_JOB_ID", "42")
+	t.Setenv("BUILD_NUMBER", "")
+	t.Setenv("GITHUB_TOKEN", "ghp_secret")
+	t.Setenv("DEPLOY_API_KEY", "secret")
+
+	c := NewCollector(
+		[]string{"GITHUB_SHA", "CI_JOB_ID", "BUILD_NUMBER", "UNSET_VARIABLE", "GITHUB_TOKEN", "DEPLOY_API_KEY"},
+		map[string]string{"ci_job_id": "manual", "team": "compilers"},
+	)
+	if err := c.Collect(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Empty and unset variables are skipped, sensitive ones never read, and
+	// explicit labels win over the environment
+	want := map[string]string{
+		"github_sha": "abc123",
+		"ci_job_id":  "manual",
+		"team":       "compilers",
+	}
+	if got := c.GetData(); !reflect.DeepEqual(got, want) {
+		t.Errorf("labels %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,161 @@
+// internal/collectors/linkmap/collector.go
+
+package linkmap
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"builds/internal/models"
+	"builds/internal/parsers/linkmap"
+	"builds/internal/parsers/remarks"
+)
+
+// DefaultMaxSymbols is the number of symbols kept per build unless
+// configured otherwise
+const DefaultMaxSymbols = 200
+
+// noLinkFlags stop the compiler driver before the link step
+var noLinkFlags = map[string]bool{
+	"-c": true, "-S": true, "-E": true, "-M": true, "-MM": true,
+	"-fsyntax-only": true, "-###": true,
+}
+
+// Collector reads the linker map of a link step. When the command does not
+// ask for a map, -Wl,-Map is added to it. Compile-only commands are left
+// alone and collect nothing.
+type Collector struct {
+	models.BaseCollector
+	buildContext *models.BuildContext
+	path         string // Map file, empty when the command does not link
+	injected     bool   // The map was requested by the collector
+	maxSymbols   int
+	info         *models.LinkMap
+}
+
+// NewCollector creates a linker map collector. It must be initialized
+// before the remarks collector runs the command.
+func NewCollector(ctx *models.BuildContext) *Collector {
+	return &Collector{
+		buildContext: ctx,
+		maxSymbols:   DefaultMaxSymbols,
+	}
+}
+
+// SetMaxSymbols caps the number of symbols kept, the largest first. Zero
+// or less keeps every symbol.
+func (c *Collector) SetMaxSymbols(limit int) {
+	c.maxSymbols = limit
+}
+
+// Initialize adds -Wl,-Map to link commands that do not write a map yet
+func (c *Collector) Initialize(ctx context.Context) error {
+	// cl.exe writes its maps in another format
+	if remarks.IsMSVC(c.buildContext.Compiler) || !isLink(c.buildContext.Args) {
+		return nil
+	}
+
+	if path := mapPath(c.buildContext.Args); path != "" {
+		c.path = path
+		return nil
+	}
+	c.path = filepath.Join(os.TempDir(), fmt.Sprintf("linkmap_%s.map", c.buildContext.BuildID))
+	c.injected = true
+	c.buildContext.Args = append(c.buildContext.Args, "-Wl,-Map="+c.path)
+	return nil
+}
+
+// Collect parses the map the link step wrote. A failed link leaves no map,
+// which is not an error.
+func (c *Collector) Collect(ctx context.Context) error {
+	if c.path == "" {
+		return nil
+	}
+
+	file, err := os.Open(c.path)
+	if os.IsNotExist(err) {
+		log.Printf("No linker map written to %s", c.path)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open linker map: %w", err)
+	}
+	defer file.Close()
+
+	info, err := linkmap.Parse(file)
+	if err != nil {
+		return fmt.Errorf("failed to parse linker map: %w", err)
+	}
+	if c.maxSymbols > 0 && len(info.Symbols) > c.maxSymbols {
+		info.Symbols = info.Symbols[:c.maxSymbols]
+	}
+	c.info = info
+	return nil
+}
+
+// GetData returns the parsed linker map, or nil when none was collected
+func (c *Collector) GetData() interface{} {
+	if c.info == nil {
+		return nil
+	}
+	return *c.info
+}
+
+// Cleanup removes the map when the collector requested it
+func (c *Collector) Cleanup(ctx context.Context) error {
+	if !c.injected {
+		return nil
+	}
+	if err := os.Remove(c.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to cleanup linker map: %w", err)
+	}
+	return nil
+}
+
+// isLink reports whether the driver links: it is given an input and no
+// flag stopping before the link step
+func isLink(args []string) bool {
+	inputs := false
+	for _, arg := range args {
+		if noLinkFlags[arg] {
+			return false
+		}
+		if !strings.HasPrefix(arg, "-") {
+			inputs = true
+		}
+	}
+	return inputs
+}
+
+// mapPath returns the map file the command already asks the linker for,
+// passed as -Wl,-Map=file, -Wl,-Map,file or -Xlinker -Map=file
+func mapPath(args []string) string {
+	for i, arg := range args {
+		if arg == "-Xlinker" && i+1 < len(args) {
+			if path, ok := strings.CutPrefix(args[i+1], "-Map="); ok {
+				return path
+			}
+			continue
+		}
+		linker, ok := strings.CutPrefix(arg, "-Wl,")
+		if !ok {
+			continue
+		}
+		parts := strings.Split(linker, ",")
+		for j, part := range parts {
+			for _, flag := range []string{"-Map", "--Map"} {
+				if path, ok := strings.CutPrefix(part, flag+"="); ok {
+					return path
+				}
+				if part == flag && j+1 < len(parts) {
+					return parts[j+1]
+				}
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,61 @@
+package linkmap
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"builds/internal/models"
+)
+
+func TestMapPath(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"main.o", "-Wl,-Map=app.map"}, "app.map"},
+		{[]string{"main.o", "-Wl,--gc-sections,-Map,app.map"}, "app.map"},
+		{[]string{"main.o", "-Wl,--Map=app.map"}, "app.map"},
+		{[]string{"main.o", "-Xlinker", "-Map=app.map"}, "app.map"},
+		{[]string{"main.o", "-Wl,--gc-sections"}, ""},
+	}
+	for _, tt := range tests {
+		if got := mapPath(tt.args); got != tt.want {
+			t.Errorf("mapPath(%q) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestInitialize(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		wantPath   string
+		wantInject bool
+	}{
+		{"link", []string{"main.o", "-o", "app"}, "", true},
+		{"existing map", []string{"main.o", "-Wl,-Map=app.map"}, "app.map", false},
+		{"compile only", []string{"-c", "main.c"}, "", false},
+		{"no inputs", []string{"--version"}, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := &models.BuildContext{BuildID: "b1", Compiler: "clang", Args: tt.args}
+			c := NewCollector(ctx)
+			if err := c.Initialize(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if c.injected != tt.wantInject {
+				t.Errorf("injected = %v, want %v", c.injected, tt.wantInject)
+			}
+			if tt.wantInject {
+				last := ctx.Args[len(ctx.Args)-1]
+				if !strings.HasPrefix(last, "-Wl,-Map=") || c.path == "" {
+					t.Errorf("args %q, path %q", ctx.Args, c.path)
+				}
+			} else if c.path != tt.wantPath || len(ctx.Args) != len(tt.args) {
+				t.Errorf("path %q and args %q", c.path, ctx.Args)
+			}
+		})
+	}
+}
@@ -3,15 +3,21 @@
 package remarks
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 
+	"builds/internal/collectors/compiler"
 	"builds/internal/models"
 	"builds/internal/parsers/remarks"
 )
@@ -21,22 +27,203 @@ type Collector struct {
 	buildContext *models.BuildContext
 	remarks      []models.CompilerRemark
 	yamlPath     string
+	sources      []string
+	root         string
+	maxRemarks   int
+	passes       *remarks.PassFilter
+	system       *remarks.SystemHeaders
+	systemSeen   int64
+	msvc         bool
+	noRecord     bool // Driver cannot write optimization records
+	timeReport   bool
+	strict       bool
+	runErr       error
+	state        *os.ProcessState // Of the compile, nil until it ran
+	stdout       cappedBuffer
+	stderr       cappedBuffer
+	seen         int64
+	keepRaw      bool
+	raw          []byte
+	rawTruncated bool
+	onRemarks    func([]models.CompilerRemark) error
 	mu           sync.Mutex
 }
 
-func NewCollector(ctx *models.BuildContext) *Collector {
+// DefaultMaxRemarks is the number of remarks kept per build unless
+// configured otherwise. At around 500 bytes a remark, a build at the cap
+// stays well within the gRPC message limit.
+const DefaultMaxRemarks = 20000
+
+// MaxStreamCapture is how much of the start, and again of the end, of each
+// of the compiler's output streams is kept. The middle of longer output is
+// dropped, though the wrapper still passes all of it through.
+const MaxStreamCapture = 256 << 10
+
+// MaxRawRecordSize bounds the compressed raw records kept for upload; larger
+// records are dropped and the build marked instead
+const MaxRawRecordSize = 32 << 20
+
+// NewCollector creates a remarks collector. Optional sources name extra
+// record files, directories or glob patterns to merge, as produced by
+// parallel or LTO builds.
+func NewCollector(ctx *models.BuildContext, sources ...string) *Collector {
 	return &Collector{
 		buildContext: ctx,
+		sources:      sources,
+		maxRemarks:   DefaultMaxRemarks,
+		stdout:       cappedBuffer{limit: MaxStreamCapture},
+		stderr:       cappedBuffer{limit: MaxStreamCapture},
+	}
+}
+
+// SetMaxRemarks caps the remarks kept for a build; remarks beyond the cap
+// are counted but dropped. Zero disables the cap.
+func (c *Collector) SetMaxRemarks(limit int) {
+	c.maxRemarks = limit
+}
+
+// SetPasses keeps only remarks from passes matching one of the glob
+// patterns, such as loop-vectorize or loop-*. Other remarks are dropped as
+// they are parsed and do not count towards the -max-remarks cap.
+func (c *Collector) SetPasses(patterns []string) error {
+	filter, err := remarks.NewPassFilter(patterns)
+	if err != nil {
+		return err
+	}
+	c.passes = filter
+	return nil
+}
+
+// SkipSystemHeaders drops remarks located in system headers: the given
+// include directories and remarks.DefaultSystemDirs. Users cannot act on
+// them and, with the STL inlined everywhere, they can swamp the rest.
+func (c *Collector) SkipSystemHeaders(dirs []string) {
+	c.system = remarks.NewSystemHeaders(dirs)
+}
+
+// keep reports whether a parsed remark is stored, counting the remarks
+// dropped for being in system headers
+func (c *Collector) keep(remark models.CompilerRemark) bool {
+	if !c.passes.Match(remark.Pass) {
+		return false
+	}
+	if c.system.Contains(remark.Location.File) {
+		c.mu.Lock()
+		c.systemSeen++
+		c.mu.Unlock()
+		return false
+	}
+	return true
+}
+
+// filter passes only the remarks that are kept on to fn
+func (c *Collector) filter(fn func(models.CompilerRemark) error) func(models.CompilerRemark) error {
+	return func(remark models.CompilerRemark) error {
+		if !c.keep(remark) {
+			return nil
+		}
+		return fn(remark)
 	}
 }
 
+// SystemHeaderRemarks returns the number of remarks dropped for being in
+// system headers
+func (c *Collector) SystemHeaderRemarks() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.systemSeen
+}
+
+// Truncated reports whether the cap dropped remarks, and how many remarks
+// the compiler emitted in total
+func (c *Collector) Truncated() (bool, int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.maxRemarks > 0 && c.seen > int64(c.maxRemarks), c.seen
+}
+
+// Strict makes Collect fail when a remark cannot be parsed, instead of
+// skipping it. Mismatched toolchain versions show up this way.
+func (c *Collector) Strict() {
+	c.strict = true
+}
+
+// CaptureTimeReport asks the compiler for -ftime-report and keeps its
+// output, which GCC uses to attribute memory to compiler phases
+func (c *Collector) CaptureTimeReport() {
+	c.timeReport = true
+}
+
+// Output returns everything the compiler printed, stdout followed by
+// stderr. It holds the diagnostics, which MSVC prints to stdout, and with
+// CaptureTimeReport the time report.
+func (c *Collector) Output() []byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append(c.stdout.Bytes(), c.stderr.Bytes()...)
+}
+
+// Streams returns what the compiler printed to stdout and stderr. The output
+// is also passed through to the wrapper's own streams as it is printed.
+// Streams longer than twice MaxStreamCapture lose their middle, see
+// OutputTruncated.
+func (c *Collector) Streams() (stdout, stderr string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return string(c.stdout.Bytes()), string(c.stderr.Bytes())
+}
+
+// OutputTruncated reports whether the middle of stdout or stderr was
+// dropped for being too long
+func (c *Collector) OutputTruncated() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stdout.Truncated() || c.stderr.Truncated()
+}
+
+// RelativeTo stores remark locations relative to the project root, so builds
+// from different checkouts can be compared
+func (c *Collector) RelativeTo(root string) {
+	c.root = root
+}
+
 func (c *Collector) Initialize(ctx context.Context) error {
 	log.Printf("Initializing remarks collector for build %s", c.buildContext.BuildID)
 	c.yamlPath = filepath.Join(os.TempDir(), fmt.Sprintf("remarks_%s.yml", c.buildContext.BuildID))
-	c.addCompilerFlags()
+	c.msvc = remarks.IsMSVC(c.buildContext.Compiler)
+	if c.msvc {
+		c.addMSVCFlags()
+		return nil
+	}
+	// Only Clang and GCC take -fsave-optimization-record; other drivers such
+	// as nvcc or icx would fail the compile on the flags
+	switch driver := compiler.Driver(c.buildContext.Compiler); driver {
+	case "clang", "gcc":
+		c.addCompilerFlags()
+	default:
+		c.noRecord = true
+		log.Printf("Not requesting optimization records from %s, unsupported driver %q", c.buildContext.Compiler, driver)
+	}
 	return nil
 }
 
+// addMSVCFlags enables the vectorizer and parallelizer reports of cl.exe
+func (c *Collector) addMSVCFlags() {
+	for _, flag := range []string{"/Qvec-report:2", "/Qpar-report:2"} {
+		prefix := flag[:len(flag)-1]
+		present := false
+		for _, arg := range c.buildContext.Args {
+			if strings.HasPrefix(arg, prefix) || strings.HasPrefix(arg, "-"+prefix[1:]) {
+				present = true
+				break
+			}
+		}
+		if !present {
+			c.buildContext.Args = append(c.buildContext.Args, flag)
+		}
+	}
+}
+
 func (c *Collector) addCompilerFlags() {
 	// Store original args for comparison
 	originalArgs := append([]string{}, c.buildContext.Args...)
@@ -56,6 +243,10 @@ func (c *Collector) addCompilerFlags() {
 		}
 	}
 
+	if c.timeReport {
+		optimFlags = append(optimFlags, "-ftime-report")
+	}
+
 	// Combine flags
 	c.buildContext.Args = append(optimFlags, cleanedArgs...)
 
@@ -78,40 +269,439 @@ func (c *Collector) Collect(ctx context.Context) error {
 		}
 	}()
 
+	if c.msvc {
+		return c.collectMSVC(ctx)
+	}
+
+	started := time.Now()
+
 	// Run compiler to generate YAML file
-	cmd := exec.CommandContext(ctx, c.buildContext.Compiler, c.buildContext.Args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	cmd := c.command(ctx)
+	cmd.Stdout, cmd.Stderr = c.teeOutput()
 
-	if err := cmd.Run(); err != nil {
-		log.Printf("Compilation completed with status: %v", err)
+	var runErr error
+	if c.onRemarks == nil {
+		runErr = cmd.Run()
+	} else {
+		runErr = c.runTailing(cmd)
+	}
+	c.recordResult(cmd.ProcessState, runErr)
+
+	// Without a record requested or named, there is nothing to parse
+	if c.noRecord && len(c.sources) == 0 {
+		return nil
+	}
+
+	// Locate the YAML files, which some compilers write next to the output
+	recordPaths, err := c.findRecordFiles(started)
+	if err != nil {
+		return err
+	}
+
+	// Parse and merge the YAML files, keeping at most maxRemarks
+	var parsedRemarks []models.CompilerRemark
+	var seen int64
+	err = remarks.EachFile(recordPaths, c.root, c.strict, c.filter(func(remark models.CompilerRemark) error {
+		seen++
+		if c.maxRemarks <= 0 || len(parsedRemarks) < c.maxRemarks {
+			parsedRemarks = append(parsedRemarks, remark)
+		}
+		return nil
+	}))
+	if err != nil {
+		return fmt.Errorf("failed to parse remarks: %w", err)
 	}
+	if c.maxRemarks > 0 && seen > int64(c.maxRemarks) {
+		log.Printf("Warning: kept %d of %d remarks, raise -max-remarks to keep more", c.maxRemarks, seen)
+	}
+
+	// Give each remark a stable ID within the build, then put the remarks
+	// from all record files in canonical order
+	models.AssignRemarkIDs(c.buildContext.BuildID, parsedRemarks)
+	remarks.SortRemarks(parsedRemarks)
+
+	var raw []byte
+	rawTruncated := false
+	if c.keepRaw {
+		if raw, err = compressRecords(recordPaths); err != nil {
+			log.Printf("Warning: failed to keep raw optimization records: %v", err)
+		}
+		if len(raw) > MaxRawRecordSize {
+			log.Printf("Warning: dropping raw optimization records, %d compressed bytes exceed the %d byte cap", len(raw), MaxRawRecordSize)
+			raw, rawTruncated = nil, true
+		}
+	}
+
+	c.mu.Lock()
+	c.remarks = parsedRemarks
+	c.seen = seen
+	c.raw = raw
+	c.rawTruncated = rawTruncated
+	c.mu.Unlock()
 
-	// Check if YAML file exists
-	if _, err := os.Stat(c.yamlPath); err != nil {
-		return fmt.Errorf("optimization record file not created: %w", err)
+	log.Printf("Collected %d remarks", len(parsedRemarks))
+	return nil
+}
+
+// command runs the compiler, through its wrapper when there is one
+func (c *Collector) command(ctx context.Context) *exec.Cmd {
+	var cmd *exec.Cmd
+	if wrapper := c.buildContext.Wrapper; wrapper != "" {
+		args := append([]string{c.buildContext.Compiler}, c.buildContext.Args...)
+		cmd = exec.CommandContext(ctx, wrapper, args...)
+	} else {
+		cmd = exec.CommandContext(ctx, c.buildContext.Compiler, c.buildContext.Args...)
+	}
+	if len(c.buildContext.Env) > 0 {
+		cmd.Env = append(os.Environ(), c.buildContext.Env...)
 	}
+	return cmd
+}
 
-	// Parse the YAML file
-	parser := remarks.NewParser(c.yamlPath)
-	parsedRemarks, err := parser.Parse()
+// collectMSVC runs cl.exe and parses the reports it prints, passing the
+// output through to the user
+func (c *Collector) collectMSVC(ctx context.Context) error {
+	var output bytes.Buffer
+	cmd := c.command(ctx)
+	stdout, stderr := c.teeOutput()
+	cmd.Stdout = io.MultiWriter(stdout, &output)
+	cmd.Stderr = io.MultiWriter(stderr, &output)
+	err := cmd.Run()
+	c.recordResult(cmd.ProcessState, err)
+
+	parsedRemarks, err := remarks.ParseMSVCReport(&output, c.root)
 	if err != nil {
 		return fmt.Errorf("failed to parse remarks: %w", err)
 	}
+	kept := parsedRemarks[:0]
+	for _, remark := range parsedRemarks {
+		if c.keep(remark) {
+			kept = append(kept, remark)
+		}
+	}
+	parsedRemarks = kept
 
-	// Update remarks with build ID
-	for i := range parsedRemarks {
-		parsedRemarks[i].ID = c.buildContext.BuildID
+	seen := int64(len(parsedRemarks))
+	if c.maxRemarks > 0 && len(parsedRemarks) > c.maxRemarks {
+		log.Printf("Warning: kept %d of %d remarks, raise -max-remarks to keep more", c.maxRemarks, seen)
+		parsedRemarks = parsedRemarks[:c.maxRemarks]
 	}
+	models.AssignRemarkIDs(c.buildContext.BuildID, parsedRemarks)
 
 	c.mu.Lock()
 	c.remarks = parsedRemarks
+	c.seen = seen
 	c.mu.Unlock()
 
 	log.Printf("Collected %d remarks", len(parsedRemarks))
 	return nil
 }
 
+// teeOutput returns writers for the compiler's stdout and stderr that pass
+// the output through to the wrapper's streams as it arrives, so users and
+// build systems see diagnostics in real time, while also capturing it
+func (c *Collector) teeOutput() (stdout, stderr io.Writer) {
+	return io.MultiWriter(os.Stdout, &lockedWriter{mu: &c.mu, w: &c.stdout}),
+		io.MultiWriter(os.Stderr, &lockedWriter{mu: &c.mu, w: &c.stderr})
+}
+
+// recordResult keeps the outcome of running the compiler
+func (c *Collector) recordResult(state *os.ProcessState, err error) {
+	if err != nil {
+		log.Printf("Compilation completed with status: %v", err)
+	}
+	c.mu.Lock()
+	c.state = state
+	c.runErr = err
+	c.mu.Unlock()
+}
+
+// ProcessState returns the state of the exited compile, whose CPU times
+// and resource usage cover the compiler and every subprocess it waited
+// for. It is nil until the compile ran, or when it could not be started.
+func (c *Collector) ProcessState() *os.ProcessState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.state
+}
+
+// ExitCode returns the compiler's exit status and the error it failed with,
+// if any. A compiler that could not be started reports 127, as a shell
+// would, and one killed by a signal reports 1.
+func (c *Collector) ExitCode() (int, error) {
+	c.mu.Lock()
+	err := c.runErr
+	c.mu.Unlock()
+
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return 0, nil
+	case errors.As(err, &exitErr) && exitErr.ExitCode() > 0:
+		return exitErr.ExitCode(), err
+	case errors.As(err, &exitErr):
+		return 1, err
+	}
+	return 127, err
+}
+
+// findRecordFiles returns the optimization records written by the compiler.
+// Configured sources and the explicit -foptimization-record-file path are
+// used when present; otherwise the default "<name>.opt.yaml" locations next
+// to the outputs and sources are searched for records newer than the
+// compilation.
+func (c *Collector) findRecordFiles(since time.Time) ([]string, error) {
+	var paths []string
+	seen := make(map[string]bool)
+	add := func(path string) {
+		if !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+
+	if _, err := os.Stat(c.yamlPath); err == nil {
+		add(c.yamlPath)
+	}
+
+	for _, source := range c.sources {
+		matches, err := remarks.ExpandRecordPaths(source)
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range matches {
+			add(match)
+		}
+	}
+
+	if len(paths) > 0 {
+		return paths, nil
+	}
+
+	for _, path := range c.defaultRecordPaths() {
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() || info.ModTime().Before(since) {
+			continue
+		}
+		add(filepath.Clean(path))
+	}
+
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("optimization record file not created: %s", c.yamlPath)
+	}
+
+	log.Printf("Using optimization records %v", paths)
+	return paths, nil
+}
+
+// defaultRecordPaths lists where compilers write records by default. Only
+// names derived from this compile's outputs and sources are listed, as
+// parallel compiles write their records into the same directories.
+func (c *Collector) defaultRecordPaths() []string {
+	var paths []string
+	dirs := []string{"."}
+
+	args := c.buildContext.Args
+	for i, arg := range args {
+		var output string
+		switch {
+		case arg == "-o" && i+1 < len(args):
+			output = args[i+1]
+		case strings.HasPrefix(arg, "-o") && len(arg) > 2:
+			output = arg[2:]
+		}
+		if output == "" {
+			continue
+		}
+		paths = append(paths,
+			output+".opt.yaml",
+			strings.TrimSuffix(output, filepath.Ext(output))+".opt.yaml",
+		)
+		dirs = append(dirs, filepath.Dir(output))
+	}
+
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") || !isSourceFile(arg) {
+			continue
+		}
+		base := strings.TrimSuffix(filepath.Base(arg), filepath.Ext(arg)) + ".opt.yaml"
+		for _, dir := range dirs {
+			paths = append(paths, filepath.Join(dir, base))
+		}
+	}
+
+	return paths
+}
+
+func isSourceFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".c", ".cc", ".cpp", ".cxx", ".c++", ".cu", ".m", ".mm", ".f", ".f90":
+		return true
+	}
+	return false
+}
+
+// tailInterval is how often the record file is polled while streaming
+const tailInterval = time.Second
+
+// StreamRemarks makes the collector hand remarks to fn while the compiler
+// is still running, by tailing the record file. The batches carry the same
+// stable IDs as the final result of GetData.
+func (c *Collector) StreamRemarks(fn func([]models.CompilerRemark) error) {
+	c.onRemarks = fn
+}
+
+// runTailing runs the compiler while forwarding remarks from the record
+// file as complete documents appear in it
+func (c *Collector) runTailing(cmd *exec.Cmd) error {
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	tail := &recordTail{
+		path:     c.yamlPath,
+		root:     c.root,
+		limit:    c.maxRemarks,
+		keep:     c.keep,
+		assigner: models.NewRemarkIDAssigner(c.buildContext.BuildID),
+		send:     c.onRemarks,
+	}
+
+	ticker := time.NewTicker(tailInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case err := <-done:
+			// Pick up whatever was written after the last poll
+			if tailErr := tail.poll(); tailErr != nil {
+				log.Printf("Warning: failed to stream remarks: %v", tailErr)
+			}
+			return err
+		case <-ticker.C:
+			if err := tail.poll(); err != nil {
+				log.Printf("Warning: failed to stream remarks: %v", err)
+			}
+		}
+	}
+}
+
+// documentEnd terminates every document in an optimization record
+var documentEnd = []byte("\n...\n")
+
+// recordTail incrementally parses a record file that is being written
+type recordTail struct {
+	path     string
+	root     string
+	limit    int
+	keep     func(models.CompilerRemark) bool
+	sent     int
+	offset   int64
+	assigner *models.RemarkIDAssigner
+	send     func([]models.CompilerRemark) error
+}
+
+// poll parses the complete documents appended since the previous poll
+func (t *recordTail) poll() error {
+	file, err := os.Open(t.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer file.Close()
+
+	if _, err := file.Seek(t.offset, io.SeekStart); err != nil {
+		return err
+	}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return err
+	}
+
+	// Leave a partially written document for the next poll
+	end := bytes.LastIndex(data, documentEnd)
+	if end < 0 {
+		return nil
+	}
+	chunk := data[:end+len(documentEnd)]
+	t.offset += int64(len(chunk))
+
+	var batch []models.CompilerRemark
+	err = remarks.ParseReader(bytes.NewReader(chunk), t.root, func(remark models.CompilerRemark) error {
+		if t.keep(remark) {
+			batch = append(batch, remark)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(batch) == 0 {
+		return nil
+	}
+
+	// IDs depend on every remark seen, so assign them before capping
+	t.assigner.Assign(batch)
+	if t.limit > 0 {
+		batch = batch[:min(len(batch), max(t.limit-t.sent, 0))]
+		if len(batch) == 0 {
+			return nil
+		}
+	}
+	t.sent += len(batch)
+	return t.send(batch)
+}
+
+// KeepRawRecords makes the collector retain the record files it parsed
+func (c *Collector) KeepRawRecords() {
+	c.keepRaw = true
+}
+
+// RawRecords returns the gzip-compressed record YAML, if it was retained,
+// and whether it was dropped for exceeding MaxRawRecordSize
+func (c *Collector) RawRecords() ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.raw, c.rawTruncated
+}
+
+// compressRecords concatenates the record files into one gzip stream
+func compressRecords(paths []string) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	for _, path := range paths {
+		if err := appendFile(zw, path); err != nil {
+			return nil, err
+		}
+		// Keep YAML documents from separate files apart
+		if _, err := zw.Write([]byte("\n")); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func appendFile(w io.Writer, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = io.Copy(w, file)
+	return err
+}
+
 func (c *Collector) GetData() interface{} {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -126,3 +716,62 @@ func (c *Collector) Cleanup(ctx context.Context) error {
 	}
 	return nil
 }
+
+// lockedWriter serializes writes to a buffer shared with readers
+type lockedWriter struct {
+	mu *sync.Mutex
+	w  io.Writer
+}
+
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
+
+// cappedBuffer keeps the first and the last limit bytes written to it, so
+// a compiler printing without end cannot exhaust the wrapper's memory
+type cappedBuffer struct {
+	limit int
+	head  []byte
+	tail  []byte // Latest bytes after head filled, compacted to limit
+	total int64
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	n := len(p)
+	b.total += int64(n)
+	if room := b.limit - len(b.head); room > 0 {
+		room = min(room, len(p))
+		b.head = append(b.head, p[:room]...)
+		p = p[room:]
+	}
+	b.tail = append(b.tail, p...)
+	// Let the tail grow to twice the limit before moving it down, so the
+	// copying is amortized over the writes
+	if len(b.tail) >= 2*b.limit {
+		b.tail = append(b.tail[:0], b.tail[len(b.tail)-b.limit:]...)
+	}
+	return n, nil
+}
+
+// Truncated reports whether bytes were dropped between head and tail
+func (b *cappedBuffer) Truncated() bool {
+	return b.total > int64(2*b.limit)
+}
+
+// Bytes returns a copy of the kept output, with a marker line in place of
+// the dropped middle
+func (b *cappedBuffer) Bytes() []byte {
+	tail := b.tail
+	if len(tail) > b.limit {
+		tail = tail[len(tail)-b.limit:]
+	}
+	output := make([]byte, 0, len(b.head)+len(tail)+64)
+	output = append(output, b.head...)
+	if b.Truncated() {
+		dropped := b.total - int64(len(b.head)+len(tail))
+		output = fmt.Appendf(output, "\n[... %d bytes of output dropped ...]\n", dropped)
+	}
+	return append(output, tail...)
+}
@@ -0,0 +1,343 @@
+package remarks
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"builds/internal/models"
+)
+
+func writeRecord(t *testing.T, path string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("--- !Passed\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFindRecordFiles(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "src", "foo.c")
+	output := filepath.Join(dir, "build", "foo.o")
+
+	tests := []struct {
+		name    string
+		records []string // Written by the "compiler"
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "explicit path",
+			records: []string{"explicit.yml", "build/foo.opt.yaml"},
+			want:    []string{"explicit.yml"},
+		},
+		{
+			name:    "default name next to the output",
+			records: []string{"build/foo.opt.yaml"},
+			want:    []string{"build/foo.opt.yaml"},
+		},
+		{
+			name:    "default name keeping the output extension",
+			records: []string{"build/foo.o.opt.yaml"},
+			want:    []string{"build/foo.o.opt.yaml"},
+		},
+		{
+			name:    "record of another compile",
+			records: []string{"build/bar.opt.yaml"},
+			wantErr: true,
+		},
+		{
+			name:    "no record",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.RemoveAll(filepath.Join(dir, "build"))
+			os.Remove(filepath.Join(dir, "explicit.yml"))
+
+			c := NewCollector(&models.BuildContext{
+				BuildID: "b1",
+				Args:    []string{"-c", source, "-o", output},
+			})
+			c.yamlPath = filepath.Join(dir, "explicit.yml")
+
+			started := time.Now().Add(-time.Second)
+			for _, record := range tt.records {
+				writeRecord(t, filepath.Join(dir, record))
+			}
+
+			got, err := c.findRecordFiles(started)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("found %v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var want []string
+			for _, record := range tt.want {
+				want = append(want, filepath.Join(dir, record))
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("found %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestFindRecordFilesSkipsStaleRecords(t *testing.T) {
+	dir := t.TempDir()
+	record := filepath.Join(dir, "foo.opt.yaml")
+	writeRecord(t, record)
+
+	c := NewCollector(&models.BuildContext{
+		BuildID: "b1",
+		Args:    []string{"-c", filepath.Join(dir, "foo.c"), "-o", filepath.Join(dir, "foo.o")},
+	})
+	c.yamlPath = filepath.Join(dir, "missing.yml")
+
+	// A record left by an earlier compile is not this build's
+	if got, err := c.findRecordFiles(time.Now().Add(time.Hour)); err == nil {
+		t.Errorf("found stale records %v", got)
+	}
+}
+
+// missedRecord returns a record of n remarks, each a complete document
+func missedRecord(n int) string {
+	var record strings.Builder
+	for i := range n {
+		fmt.Fprintf(&record, "--- !Missed\nPass: loop-vectorize\nName: MissedDetails\nFunction: f%d\n...\n", i)
+	}
+	return record.String()
+}
+
+func TestCollectCapsRemarks(t *testing.T) {
+	tests := []struct {
+		name          string
+		limit         int
+		wantKept      int
+		wantTruncated bool
+	}{
+		{"over the cap", 5, 5, true},
+		{"at the cap", 12, 12, false},
+		{"no cap", 0, 12, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCollector(&models.BuildContext{BuildID: "b1", Compiler: "true"})
+			c.yamlPath = filepath.Join(t.TempDir(), "foo.opt.yaml")
+			if err := os.WriteFile(c.yamlPath, []byte(missedRecord(12)), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			c.SetMaxRemarks(tt.limit)
+
+			if err := c.Collect(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if kept := len(c.GetData().([]models.CompilerRemark)); kept != tt.wantKept {
+				t.Errorf("kept %d remarks, want %d", kept, tt.wantKept)
+			}
+			truncated, seen := c.Truncated()
+			if truncated != tt.wantTruncated || seen != 12 {
+				t.Errorf("Truncated() = %v, %d, want %v, 12", truncated, seen, tt.wantTruncated)
+			}
+		})
+	}
+}
+
+func TestRecordTailCapsRemarks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "foo.opt.yaml")
+	var sent int
+	tail := &recordTail{
+		path:     path,
+		limit:    5,
+		keep:     func(models.CompilerRemark) bool { return true },
+		assigner: models.NewRemarkIDAssigner("b1"),
+		send: func(batch []models.CompilerRemark) error {
+			sent += len(batch)
+			return nil
+		},
+	}
+
+	// The cap holds across polls as the record grows
+	for _, n := range []int{3, 6, 12} {
+		if err := os.WriteFile(path, []byte(missedRecord(n)), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := tail.poll(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if sent != 5 {
+		t.Errorf("streamed %d remarks, want 5", sent)
+	}
+}
+
+func TestCollectFiltersPasses(t *testing.T) {
+	// Three inlining remarks come before the vectorization ones
+	var record strings.Builder
+	for i := range 3 {
+		fmt.Fprintf(&record, "--- !Passed\nPass: inline\nName: Inlined\nFunction: g%d\n...\n", i)
+	}
+	record.WriteString(missedRecord(4))
+
+	c := NewCollector(&models.BuildContext{BuildID: "b1", Compiler: "true"})
+	c.yamlPath = filepath.Join(t.TempDir(), "foo.opt.yaml")
+	if err := os.WriteFile(c.yamlPath, []byte(record.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SetPasses([]string{"loop-*"}); err != nil {
+		t.Fatal(err)
+	}
+	c.SetMaxRemarks(4)
+
+	if err := c.Collect(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	kept := c.GetData().([]models.CompilerRemark)
+	if len(kept) != 4 {
+		t.Fatalf("kept %d remarks, want 4", len(kept))
+	}
+	for _, remark := range kept {
+		if remark.Pass != "loop-vectorize" {
+			t.Errorf("kept a remark from %s", remark.Pass)
+		}
+	}
+	// Dropped remarks do not count towards the cap
+	if truncated, seen := c.Truncated(); truncated || seen != 4 {
+		t.Errorf("Truncated() = %v, %d, want false, 4", truncated, seen)
+	}
+}
+
+func TestCollectSystemHeaders(t *testing.T) {
+	record := `--- !Missed
+Pass: loop-vectorize
+Name: MissedDetails
+DebugLoc: { File: /usr/include/c++/13/bits/stl_algo.h, Line: 10, Column: 3 }
+Function: sort
+...
+--- !Missed
+Pass: loop-vectorize
+Name: MissedDetails
+DebugLoc: { File: /opt/sdk/include/sdk.h, Line: 4, Column: 1 }
+Function: sdk
+...
+--- !Missed
+Pass: loop-vectorize
+Name: MissedDetails
+DebugLoc: { File: src/foo.c, Line: 3, Column: 5 }
+Function: foo
+...
+`
+	tests := []struct {
+		name       string
+		skip       bool
+		wantFiles  []string
+		wantSystem int64
+	}{
+		{"dropped by default", true, []string{"src/foo.c"}, 2},
+		{"kept with -include-system-headers", false, []string{
+			"/usr/include/c++/13/bits/stl_algo.h", "/opt/sdk/include/sdk.h", "src/foo.c",
+		}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCollector(&models.BuildContext{BuildID: "b1", Compiler: "true"})
+			c.yamlPath = filepath.Join(t.TempDir(), "foo.opt.yaml")
+			if err := os.WriteFile(c.yamlPath, []byte(record), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if tt.skip {
+				// As listed by the compiler's -v
+				c.SkipSystemHeaders([]string{"/opt/sdk/include"})
+			}
+
+			if err := c.Collect(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			var files []string
+			for _, remark := range c.GetData().([]models.CompilerRemark) {
+				files = append(files, remark.Location.File)
+			}
+			sort.Strings(files)
+			want := append([]string(nil), tt.wantFiles...)
+			sort.Strings(want)
+			if !reflect.DeepEqual(files, want) {
+				t.Errorf("kept remarks in %q, want %q", files, want)
+			}
+			if got := c.SystemHeaderRemarks(); got != tt.wantSystem {
+				t.Errorf("SystemHeaderRemarks() = %d, want %d", got, tt.wantSystem)
+			}
+		})
+	}
+}
+
+func TestStreamsCapsOutput(t *testing.T) {
+	dir := t.TempDir()
+	compiler := filepath.Join(dir, "cc")
+	// Prints a marked start and end around far more than the capture keeps
+	script := fmt.Sprintf("#!/bin/sh\necho first >&2\nhead -c %d /dev/zero | tr '\\\\0' x >&2\necho last >&2\necho ok\n", 4*MaxStreamCapture)
+	if err := os.WriteFile(compiler, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	c := NewCollector(&models.BuildContext{BuildID: "b1", Compiler: compiler})
+	c.yamlPath = filepath.Join(dir, "foo.opt.yaml")
+	writeRecord(t, c.yamlPath)
+
+	// The compiler's output is passed through; keep it out of the test log
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer devNull.Close()
+	stdout, stderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = devNull, devNull
+	err = c.Collect(context.Background())
+	os.Stdout, os.Stderr = stdout, stderr
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, captured := c.Streams()
+	if out != "ok\n" {
+		t.Errorf("stdout %q, want ok", out)
+	}
+	if !c.OutputTruncated() {
+		t.Error("OutputTruncated() = false, want true")
+	}
+	if len(captured) > 2*MaxStreamCapture+100 {
+		t.Errorf("kept %d bytes of stderr, want at most about %d", len(captured), 2*MaxStreamCapture)
+	}
+	if !strings.HasPrefix(captured, "first\n") || !strings.HasSuffix(captured, "last\n") {
+		t.Errorf("stderr lost its start or end")
+	}
+	if !strings.Contains(captured, "bytes of output dropped") {
+		t.Error("stderr has no marker where output was dropped")
+	}
+}
+
+func TestCappedBuffer(t *testing.T) {
+	b := cappedBuffer{limit: 8}
+	for _, chunk := range []string{"hello ", "world", "!!"} {
+		b.Write([]byte(chunk))
+	}
+	if got := string(b.Bytes()); got != "hello world!!" || b.Truncated() {
+		t.Errorf("Bytes() = %q, Truncated() = %v", got, b.Truncated())
+	}
+	b.Write([]byte("abcde"))
+	if got := string(b.Bytes()); got != "hello wo\n[... 2 bytes of output dropped ...]\nd!!abcde" {
+		t.Errorf("Bytes() = %q", got)
+	}
+}
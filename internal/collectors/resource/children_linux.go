@@ -0,0 +1,44 @@
+// internal/collectors/resource/children_linux.go
+
+//go:build linux
+
+package resource
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// newChildLister lists the direct children of a process from the children
+// files of its threads, which is cheap enough to read on every sample.
+// Kernels built without those files fall back to a single scan of the
+// process table, shared by every lookup.
+func newChildLister(ctx context.Context) childLister {
+	var scanned childLister
+	return func(pid int32) []int32 {
+		files, _ := filepath.Glob(filepath.Join("/proc", strconv.Itoa(int(pid)), "task", "*", "children"))
+		if len(files) == 0 {
+			if scanned == nil {
+				scanned = scanChildren(ctx)
+			}
+			return scanned(pid)
+		}
+
+		var children []int32
+		for _, file := range files {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				continue // The thread exited
+			}
+			for _, field := range strings.Fields(string(data)) {
+				if child, err := strconv.ParseInt(field, 10, 32); err == nil {
+					children = append(children, int32(child))
+				}
+			}
+		}
+		return children
+	}
+}
@@ -0,0 +1,13 @@
+// internal/collectors/resource/children_other.go
+
+//go:build !linux
+
+package resource
+
+import "context"
+
+// newChildLister lists children from one scan of the process table, taken
+// when it is created; a sample creates one for the whole tree
+func newChildLister(ctx context.Context) childLister {
+	return scanChildren(ctx)
+}
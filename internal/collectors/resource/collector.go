@@ -4,8 +4,10 @@ package resource
 
 import (
 	"context"
+	"errors"
 	"os"
 	"runtime"
+	"sync"
 	"time"
 
 	"builds/internal/models"
@@ -13,13 +15,29 @@ import (
 	"github.com/shirou/gopsutil/v3/process"
 )
 
-// Collector implements resource usage collection
+// DefaultSampleInterval is how often memory is sampled while tracking,
+// unless configured otherwise
+const DefaultSampleInterval = 100 * time.Millisecond
+
+// Collector implements resource usage collection. Between StartTracking
+// and StopTracking it samples the memory of this process and every process
+// below it, the compiler and the cc1, as and ld it runs, and reports the
+// highest total as MaxMemory. Given the state of the exited compile, see
+// SetCompileState, it adds the compile's CPU time and peak memory, which
+// sampling cannot see once the processes are gone.
 type Collector struct {
 	models.BaseCollector
 	info         models.ResourceUsage
 	startTime    time.Time
 	proc         *process.Process
 	buildContext *models.BuildContext
+	compile      func() *os.ProcessState // See SetCompileState
+
+	interval time.Duration
+	mu       sync.Mutex
+	peak     int64         // Highest sampled RSS of the process tree
+	stop     chan struct{} // Closed to stop sampling, nil when not tracking
+	done     chan struct{} // Closed once the sampler returned
 }
 
 // NewCollector creates a new resource usage collector
@@ -27,9 +45,23 @@ func NewCollector(ctx *models.BuildContext) *Collector {
 	return &Collector{
 		buildContext: ctx,
 		startTime:    time.Now(),
+		interval:     DefaultSampleInterval,
 	}
 }
 
+// SetSampleInterval sets how often memory is sampled while tracking. Zero
+// or less samples only when tracking starts and stops.
+func (c *Collector) SetSampleInterval(interval time.Duration) {
+	c.interval = interval
+}
+
+// SetCompileState tells the collector where to find the state of the
+// exited compile, such as the remarks collector's ProcessState. It is
+// called when collecting, after the compile ran; a nil state is skipped.
+func (c *Collector) SetCompileState(state func() *os.ProcessState) {
+	c.compile = state
+}
+
 // Initialize prepares the resource collector
 func (c *Collector) Initialize(ctx context.Context) error {
 	proc, err := process.NewProcess(int32(os.Getpid()))
@@ -43,14 +75,20 @@ func (c *Collector) Initialize(ctx context.Context) error {
 	return nil
 }
 
-// Collect gathers resource usage information
+// Collect gathers resource usage information, ending tracking if it is
+// still running
 func (c *Collector) Collect(ctx context.Context) error {
+	c.stopSampling()
+
 	// Get memory info
 	memInfo, err := c.proc.MemoryInfo()
 	if err != nil {
 		return err
 	}
-	c.info.MaxMemory = int64(memInfo.RSS)
+	c.sample(ctx)
+	c.mu.Lock()
+	c.info.MaxMemory = max(c.peak, int64(memInfo.RSS))
+	c.mu.Unlock()
 
 	// Get CPU times
 	cpuTimes, err := c.proc.Times()
@@ -59,7 +97,16 @@ func (c *Collector) Collect(ctx context.Context) error {
 	}
 	c.info.CPUTime = cpuTimes.User + cpuTimes.System
 
-	// Get IO statistics
+	// The compile's usage covers the subprocesses it waited for
+	if c.compile != nil {
+		if state := c.compile(); state != nil {
+			c.info.CPUTime += (state.UserTime() + state.SystemTime()).Seconds()
+			c.info.MaxMemory = max(c.info.MaxMemory, maxRSS(state))
+		}
+	}
+
+	// Get IO statistics. On Linux the counters of this process include
+	// those of the children it reaped, the compile among them.
 	ioStats, err := c.proc.IOCounters()
 	if err == nil {
 		c.info.IO = models.IOStats{
@@ -86,6 +133,11 @@ func (c *Collector) GetData() interface{} {
 
 // Cleanup performs any necessary cleanup
 func (c *Collector) Cleanup(ctx context.Context) error {
+	// Nothing to sample when Initialize failed
+	if c.proc == nil {
+		return nil
+	}
+
 	// Perform one final collection before cleanup
 	if err := c.Collect(ctx); err != nil {
 		return err
@@ -93,9 +145,23 @@ func (c *Collector) Cleanup(ctx context.Context) error {
 	return nil
 }
 
-// StartTracking begins resource tracking
+// StartTracking begins resource tracking, sampling memory in the
+// background until tracking stops
 func (c *Collector) StartTracking() error {
+	if c.proc == nil {
+		return errors.New("resource collector is not initialized")
+	}
+	if c.stop != nil {
+		return nil // Already tracking
+	}
 	c.startTime = time.Now()
+	c.sample(context.Background())
+	if c.interval <= 0 {
+		return nil
+	}
+
+	c.stop, c.done = make(chan struct{}), make(chan struct{})
+	go c.sampleLoop(c.stop, c.done)
 	return nil
 }
 
@@ -104,6 +170,78 @@ func (c *Collector) StopTracking() error {
 	return c.Collect(context.Background())
 }
 
+func (c *Collector) sampleLoop(stop, done chan struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			c.sample(ctx)
+		}
+	}
+}
+
+// stopSampling stops the sampler, if running, and waits for it to return
+func (c *Collector) stopSampling() {
+	if c.stop == nil {
+		return
+	}
+	close(c.stop)
+	<-c.done
+	c.stop, c.done = nil, nil
+}
+
+// sample adds up the resident memory of this process and its descendants
+// and keeps the highest total seen. Pages shared between the processes
+// are counted once per process.
+func (c *Collector) sample(ctx context.Context) {
+	total := treeRSS(ctx, c.proc.Pid, newChildLister(ctx))
+	c.mu.Lock()
+	c.peak = max(c.peak, total)
+	c.mu.Unlock()
+}
+
+// childLister lists the direct children of a process
+type childLister func(pid int32) []int32
+
+// treeRSS returns the resident memory of pid and every process below it.
+// Processes that exit while being sampled are skipped.
+func treeRSS(ctx context.Context, pid int32, children childLister) int64 {
+	var total int64
+	if proc, err := process.NewProcessWithContext(ctx, pid); err == nil {
+		if mem, err := proc.MemoryInfoWithContext(ctx); err == nil {
+			total += int64(mem.RSS)
+		}
+	}
+	for _, child := range children(pid) {
+		total += treeRSS(ctx, child, children)
+	}
+	return total
+}
+
+// scanChildren reads the parent of every process once and lists children
+// from that snapshot, so walking a tree costs one pass over the process
+// table however many processes it has
+func scanChildren(ctx context.Context) childLister {
+	byParent := make(map[int32][]int32)
+	if procs, err := process.ProcessesWithContext(ctx); err == nil {
+		for _, proc := range procs {
+			if ppid, err := proc.PpidWithContext(ctx); err == nil {
+				byParent[ppid] = append(byParent[ppid], proc.Pid)
+			}
+		}
+	}
+	return func(pid int32) []int32 {
+		return byParent[pid]
+	}
+}
+
 // GetResourceSnapshot takes a snapshot of current resource usage
 func (c *Collector) GetResourceSnapshot() (*models.ResourceUsage, error) {
 	err := c.Collect(context.Background())
@@ -0,0 +1,148 @@
+package resource
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"runtime"
+	"slices"
+	"testing"
+	"time"
+
+	"builds/internal/models"
+)
+
+func TestCollectIOAndThreads(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("IO counters are only read on Linux")
+	}
+	ctx := context.Background()
+	c := NewCollector(&models.BuildContext{})
+	if err := c.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.ReadFile(os.Args[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Collect(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	usage, ok := c.GetData().(models.ResourceUsage)
+	if !ok {
+		t.Fatalf("GetData returned %T, want models.ResourceUsage", c.GetData())
+	}
+	if usage.Threads <= 0 {
+		t.Errorf("Threads = %d", usage.Threads)
+	}
+	if usage.IO.ReadCount <= 0 {
+		t.Errorf("ReadCount = %d after reading a file", usage.IO.ReadCount)
+	}
+}
+
+func TestCollectIncludesCompile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("runs a shell")
+	}
+	ctx := context.Background()
+
+	// A compile that burns CPU in a subprocess of its own
+	cmd := exec.Command("sh", "-c", `sh -c 'i=0; while [ $i -lt 100000 ]; do i=$((i+1)); done'`)
+	if err := cmd.Run(); err != nil {
+		t.Fatal(err)
+	}
+	state := cmd.ProcessState
+	compileTime := (state.UserTime() + state.SystemTime()).Seconds()
+	if compileTime == 0 {
+		t.Skip("the kernel reported no CPU time for the compile")
+	}
+
+	collect := func(compile func() *os.ProcessState) models.ResourceUsage {
+		c := NewCollector(&models.BuildContext{})
+		if compile != nil {
+			c.SetCompileState(compile)
+		}
+		if err := c.Initialize(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.Collect(ctx); err != nil {
+			t.Fatal(err)
+		}
+		return c.GetData().(models.ResourceUsage)
+	}
+
+	with := collect(func() *os.ProcessState { return state })
+	if with.CPUTime < compileTime {
+		t.Errorf("CPUTime = %v, want at least the compile's %v", with.CPUTime, compileTime)
+	}
+	if rss := maxRSS(state); rss > 0 && with.MaxMemory < rss {
+		t.Errorf("MaxMemory = %d, want at least the compile's peak %d", with.MaxMemory, rss)
+	}
+
+	// A compile that could not start leaves the figures alone
+	if without := collect(func() *os.ProcessState { return nil }); without.CPUTime >= with.CPUTime {
+		t.Errorf("CPUTime = %v without the compile, %v with it", without.CPUTime, with.CPUTime)
+	}
+}
+
+func TestTreeRSSIncludesChildren(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("runs sleep")
+	}
+	ctx := context.Background()
+
+	cmd := exec.Command("sleep", "10")
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+	child := int32(cmd.Process.Pid)
+	self := int32(os.Getpid())
+
+	for name, list := range map[string]childLister{
+		"newChildLister": newChildLister(ctx),
+		"scanChildren":   scanChildren(ctx),
+	} {
+		if !slices.Contains(list(self), child) {
+			t.Errorf("%s left out the child %d", name, child)
+		}
+	}
+
+	children := newChildLister(ctx)
+	if tree, alone := treeRSS(ctx, self, children), treeRSS(ctx, child, children); alone <= 0 || tree <= alone {
+		t.Errorf("tree RSS %d, child RSS %d", tree, alone)
+	}
+}
+
+func TestTrackingSamplesUntilStopped(t *testing.T) {
+	c := NewCollector(&models.BuildContext{})
+	c.SetSampleInterval(time.Millisecond)
+	if err := c.Initialize(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.StartTracking(); err != nil {
+		t.Fatal(err)
+	}
+	done := c.done
+	if err := c.StartTracking(); err != nil || c.done != done {
+		t.Errorf("tracking twice started another sampler: %v", err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if err := c.StopTracking(); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-done:
+	default:
+		t.Error("the sampler kept running after StopTracking")
+	}
+	usage := c.GetData().(models.ResourceUsage)
+	if usage.MaxMemory <= 0 || usage.MaxMemory < c.peak {
+		t.Errorf("MaxMemory = %d, sampled peak %d", usage.MaxMemory, c.peak)
+	}
+}
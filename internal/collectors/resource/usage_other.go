@@ -0,0 +1,12 @@
+// internal/collectors/resource/usage_other.go
+
+//go:build !unix
+
+package resource
+
+import "os"
+
+// maxRSS is not reported for exited processes on this platform
+func maxRSS(state *os.ProcessState) int64 {
+	return 0
+}
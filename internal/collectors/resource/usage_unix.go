@@ -0,0 +1,25 @@
+// internal/collectors/resource/usage_unix.go
+
+//go:build unix
+
+package resource
+
+import (
+	"os"
+	"runtime"
+	"syscall"
+)
+
+// maxRSS returns the peak resident memory of an exited process, which
+// includes the subprocesses it waited for
+func maxRSS(state *os.ProcessState) int64 {
+	usage, ok := state.SysUsage().(*syscall.Rusage)
+	if !ok {
+		return 0
+	}
+	// ru_maxrss is in bytes on Apple platforms and in kilobytes elsewhere
+	if runtime.GOOS == "darwin" || runtime.GOOS == "ios" {
+		return int64(usage.Maxrss)
+	}
+	return int64(usage.Maxrss) * 1024
+}
@@ -0,0 +1,9 @@
+// internal/integration/doc.go
+
+// Package integration holds end-to-end tests: builds are stored through the
+// gRPC server, fetched back with the client and rendered by the reporters.
+// They are behind the integration build tag and run against an in-memory
+// SQLite database, so no database server is needed:
+//
+//	go test -tags integration ./internal/integration
+package integration
@@ -0,0 +1,289 @@
+//go:build integration
+
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	buildv1 "builds/api/build"
+	"builds/internal/client"
+	"builds/internal/models"
+	"builds/internal/reporters"
+	"builds/internal/server/api"
+	"builds/internal/server/db/dbtest"
+)
+
+// newClient connects to a server over a fresh test database
+func newClient(t *testing.T) *client.Client {
+	t.Helper()
+	database := dbtest.OpenSQLite(t)
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	buildv1.RegisterBuildServiceServer(server, api.NewServer(database, api.Options{}))
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return client.NewFromService(buildv1.NewBuildServiceClient(conn))
+}
+
+// fetch gets a stored build back in the form the reporters take
+func fetch(t *testing.T, c *client.Client, id string) *models.Build {
+	t.Helper()
+	build, err := c.Get(context.Background(), id)
+	if err != nil {
+		t.Fatalf("failed to get build %s: %v", id, err)
+	}
+	return client.BuildToModel(build)
+}
+
+// render reports build in format, failing the test on errors
+func render(t *testing.T, build *models.Build, format string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := reporters.Render(build, nil, format, &buf); err != nil {
+		t.Fatalf("failed to render %s report: %v", format, err)
+	}
+	return buf.String()
+}
+
+// assertContains checks that a report shows each of want
+func assertContains(t *testing.T, report string, want ...string) {
+	t.Helper()
+	for _, s := range want {
+		if !strings.Contains(report, s) {
+			t.Errorf("report lacks %q:\n%s", s, report)
+		}
+	}
+}
+
+// kernelBuild has every section the wrapper collects filled in
+func kernelBuild(id string) *buildv1.Build {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	return &buildv1.Build{
+		Id:        id,
+		StartTime: timestamppb.New(start),
+		EndTime:   timestamppb.New(start.Add(2 * time.Second)),
+		Duration:  2,
+		Success:   true,
+		Labels:    map[string]string{"project": "app"},
+		Environment: &buildv1.Environment{
+			Os:         "linux",
+			Arch:       "amd64",
+			WorkingDir: "/src",
+		},
+		Hardware: &buildv1.Hardware{
+			Cpu:    &buildv1.CPU{Model: "EPYC 7763", Cores: 64, Threads: 128},
+			Memory: &buildv1.Memory{Total: 256 << 30},
+		},
+		Compiler: &buildv1.Compiler{
+			Name:     "clang",
+			Version:  "18.1.0",
+			Target:   "nvptx64-nvidia-cuda",
+			Options:  []string{"-O3", "-fopenmp"},
+			Language: &buildv1.Language{Name: "c", Specification: "c17"},
+			Features: &buildv1.CompilerFeatures{SupportsOpenmp: true, SupportsGpu: true},
+		},
+		Command: &buildv1.Command{
+			Executable: "clang",
+			Arguments:  []string{"-O3", "-fopenmp", "-c", "kernel.c"},
+			WorkingDir: "/src",
+		},
+		Output: &buildv1.Output{
+			Stdout:    "compiled kernel.c\n",
+			ExitCode:  0,
+			Artifacts: []*buildv1.Artifact{{Path: "kernel.o", Type: "object", Size: 2048, Hash: "abc123"}},
+			Diagnostics: []*buildv1.Diagnostic{
+				{Severity: "warning", File: "kernel.c", Line: 7, Col: 3, Message: "unused variable 'tmp'"},
+			},
+		},
+		ResourceUsage: &buildv1.ResourceUsage{MaxMemory: 64 << 20, CpuTime: 1.5, Threads: 4},
+		Performance: &buildv1.Performance{
+			CompileTime: 1.25,
+			Phases:      map[string]float64{"frontend": 0.5, "backend": 0.75},
+		},
+		Remarks: []*buildv1.CompilerRemark{
+			{
+				Id:       "k1",
+				Name:     "OpenMPKernel",
+				PassName: "openmp-opt",
+				Pass:     buildv1.CompilerRemark_KERNEL_INFO,
+				Status:   buildv1.CompilerRemark_PASSED,
+				Function: "__omp_offloading_main_l7",
+				Message:  "OpenMP GPU kernel",
+				Location: &buildv1.Location{File: "kernel.c", Line: 7},
+				KernelInfo: &buildv1.KernelInfo{
+					ThreadLimit: 256,
+					DirectCalls: 2,
+					Callees:     []string{"helper", "reduce"},
+					MemoryAccesses: []*buildv1.MemoryAccess{
+						{Type: "load", AddressSpace: "global", Instruction: "ld.global.f32", Location: &buildv1.Location{File: "kernel.c", Line: 9}},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestFullBuild(t *testing.T) {
+	c := newClient(t)
+	if _, err := c.Create(context.Background(), kernelBuild("full")); err != nil {
+		t.Fatal(err)
+	}
+
+	build := fetch(t, c, "full")
+	report := render(t, build, "text")
+	assertContains(t, report,
+		"Build ID:", "full",
+		"EPYC 7763",
+		// Command
+		"Executable:", "clang", "-fopenmp -c kernel.c",
+		// Output
+		"Exit Code:", "kernel.c:7:3: warning: unused variable 'tmp'", "kernel.o",
+		// Performance
+		"Compile Time:", "Phase Timings:", "frontend:", "backend:",
+		// Kernel info
+		"Kernel Info:", "Thread Limit:", "256", "Callees:", "helper, reduce", "ld.global.f32",
+	)
+	if strings.Contains(report, "No data collected") {
+		t.Errorf("a section was left empty:\n%s", report)
+	}
+
+	// The JSON report carries the same build
+	var doc struct {
+		Build models.Build `json:"build"`
+	}
+	if err := json.Unmarshal([]byte(render(t, build, "json")), &doc); err != nil {
+		t.Fatalf("invalid JSON report: %v", err)
+	}
+	got := doc.Build
+	if got.Command.Executable != "clang" || len(got.Command.Arguments) != 4 {
+		t.Errorf("command %+v", got.Command)
+	}
+	if len(got.Output.Diagnostics) != 1 || len(got.Output.Artifacts) != 1 {
+		t.Errorf("output %+v", got.Output)
+	}
+	if got.Performance.CompileTime != 1.25 || len(got.Performance.Phases) != 2 {
+		t.Errorf("performance %+v", got.Performance)
+	}
+	if len(got.Remarks) != 1 || got.Remarks[0].KernelInfo == nil || len(got.Remarks[0].KernelInfo.MemoryAccesses) != 1 {
+		t.Errorf("remarks %+v", got.Remarks)
+	}
+}
+
+func TestStreamedBuild(t *testing.T) {
+	c := newClient(t)
+	ctx := context.Background()
+
+	// The wrapper begins the build, streams remarks while compiling and
+	// sends the rest of the build when the compiler exits
+	if _, err := c.Begin(ctx, &buildv1.Build{Id: "streamed"}); err != nil {
+		t.Fatal(err)
+	}
+	stream, err := c.AppendRemarks(ctx, "streamed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	missed := func(id, function string) *buildv1.CompilerRemark {
+		return &buildv1.CompilerRemark{
+			Id:       id,
+			Name:     "MissedDetails",
+			PassName: "loop-vectorize",
+			Pass:     buildv1.CompilerRemark_VECTORIZATION,
+			Status:   buildv1.CompilerRemark_MISSED,
+			Function: function,
+			Location: &buildv1.Location{File: "loop.c", Line: 12},
+			Args:     &buildv1.RemarkArgs{Reason: "loop not vectorized"},
+		}
+	}
+	for _, batch := range [][]*buildv1.CompilerRemark{
+		{missed("r1", "sum")},
+		{missed("r2", "scale")},
+	} {
+		if err := stream.Send(batch); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if stored, err := stream.Close(); err != nil || stored != 2 {
+		t.Fatalf("streamed %d remarks: %v", stored, err)
+	}
+
+	build := kernelBuild("streamed")
+	build.Remarks = []*buildv1.CompilerRemark{missed("r1", "sum"), missed("r2", "scale")}
+	if _, err := c.Finalize(ctx, build); err != nil {
+		t.Fatal(err)
+	}
+
+	fetched := fetch(t, c, "streamed")
+	if len(fetched.Remarks) != 2 {
+		t.Fatalf("stored %d remarks, want 2", len(fetched.Remarks))
+	}
+	assertContains(t, render(t, fetched, "yaml"),
+		"id: streamed",
+		"function: sum",
+		"function: scale",
+	)
+	assertContains(t, render(t, fetched, "text"), "EPYC 7763", "loop not vectorized")
+}
+
+func TestFailedBuild(t *testing.T) {
+	c := newClient(t)
+
+	// A compile that failed before producing remarks or artifacts
+	build := &buildv1.Build{
+		Id:      "failed",
+		Success: false,
+		Error:   "exit status 1",
+		Command: &buildv1.Command{Executable: "cc", Arguments: []string{"-c", "broken.c"}},
+		Output: &buildv1.Output{
+			Stderr:   "broken.c:3:1: error: expected ';'\n",
+			ExitCode: 1,
+			Diagnostics: []*buildv1.Diagnostic{
+				{Severity: "error", File: "broken.c", Line: 3, Col: 1, Message: "expected ';'"},
+			},
+		},
+	}
+	if _, err := c.Create(context.Background(), build); err != nil {
+		t.Fatal(err)
+	}
+
+	fetched := fetch(t, c, "failed")
+	assertContains(t, render(t, fetched, "text"),
+		"Error:", "exit status 1",
+		"Exit Code:", "1 errors, 0 warnings",
+		"broken.c:3:1: error: expected ';'",
+		"Arguments:", "-c broken.c",
+	)
+
+	var doc struct {
+		Build models.Build `json:"build"`
+	}
+	if err := json.Unmarshal([]byte(render(t, fetched, "json")), &doc); err != nil {
+		t.Fatalf("invalid JSON report: %v", err)
+	}
+	if doc.Build.Success || doc.Build.Output.ExitCode != 1 || len(doc.Build.Remarks) != 0 {
+		t.Errorf("JSON report has success %v, exit code %d and %d remarks",
+			doc.Build.Success, doc.Build.Output.ExitCode, len(doc.Build.Remarks))
+	}
+}
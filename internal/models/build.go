@@ -2,7 +2,11 @@
 
 package models
 
-import "time"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 // Build represents a complete build process and its information
 type Build struct {
@@ -13,10 +17,15 @@ type Build struct {
 	Success   bool      `json:"success"`
 	Error     string    `json:"error,omitempty"`
 
+	// User and CI supplied key/value labels
+	Labels map[string]string `json:"labels,omitempty"`
+
 	// Build environment and configuration
 	Environment Environment `json:"environment"`
 	Hardware    Hardware    `json:"hardware"`
 	Compiler    Compiler    `json:"compiler"`
+	// The git checkout the build ran in, nil outside a repository
+	VersionControl *VersionControl `json:"versionControl,omitempty"`
 
 	// Build execution and output
 	Command Command      `json:"command"`
@@ -24,9 +33,20 @@ type Build struct {
 	Metrics BuildMetrics `json:"metrics"`
 
 	// Analysis data
-	Remarks       []CompilerRemark `json:"remarks"` // Generic compiler remarks
-	ResourceUsage ResourceUsage    `json:"resourceUsage"`
-	Performance   Performance      `json:"performance"`
+	Remarks []CompilerRemark `json:"remarks"` // Generic compiler remarks
+	// Set when the remark cap was reached; RemarksSeen counts all remarks
+	RemarksTruncated bool          `json:"remarksTruncated,omitempty"`
+	RemarksSeen      int64         `json:"remarksSeen,omitempty"`
+	RemarksPurged    bool          `json:"remarksPurged,omitempty"`    // Remarks were deleted to save space
+	RemarkCounts     []RemarkCount `json:"remarkCounts,omitempty"`     // Kept in place of purged remarks
+	RawTruncated     bool          `json:"rawTruncated,omitempty"`     // Raw record dropped for exceeding the upload cap
+	ReportedDuration float64       `json:"reportedDuration,omitempty"` // Client duration the server replaced
+	CacheStatus      string        `json:"cacheStatus,omitempty"`      // Compiler cache result, see collectors/cache
+	ClientStartTime  *time.Time    `json:"clientStartTime,omitempty"`  // Client timestamps the server replaced
+	ClientEndTime    *time.Time    `json:"clientEndTime,omitempty"`
+	ResourceUsage    ResourceUsage `json:"resourceUsage"`
+	Performance      Performance   `json:"performance"`
+	LinkMap          *LinkMap      `json:"linkMap,omitempty"` // Set when a linker map was collected
 }
 
 // Environment represents the build environment
@@ -35,6 +55,60 @@ type Environment struct {
 	Arch       string            `json:"arch"`
 	Variables  map[string]string `json:"variables"`
 	WorkingDir string            `json:"workingDir"`
+
+	// Captured regardless of variable filtering, as they affect reproducibility
+	Locale   map[string]string `json:"locale,omitempty"` // LANG and LC_* settings
+	Timezone string            `json:"timezone,omitempty"`
+	Umask    string            `json:"umask,omitempty"` // Octal, e.g. "0022"
+}
+
+// VersionControl identifies the git commit a build was made from
+type VersionControl struct {
+	Commit    string `json:"commit"`
+	Branch    string `json:"branch,omitempty"` // Empty on a detached HEAD
+	Dirty     bool   `json:"dirty"`            // The working tree differs from the commit
+	RemoteURL string `json:"remoteUrl,omitempty"`
+}
+
+// Describe formats the commit with its branch and whether the working tree
+// was dirty, such as "<sha> (main, dirty)"
+func (vc VersionControl) Describe() string {
+	var notes []string
+	if vc.Branch != "" {
+		notes = append(notes, vc.Branch)
+	}
+	if vc.Dirty {
+		notes = append(notes, "dirty")
+	}
+	if len(notes) == 0 {
+		return vc.Commit
+	}
+	return fmt.Sprintf("%s (%s)", vc.Commit, strings.Join(notes, ", "))
+}
+
+// LinkMap summarizes the linker map of a build's link step
+type LinkMap struct {
+	Sections []LinkSection `json:"sections"` // Output sections, largest first
+	Symbols  []LinkSymbol  `json:"symbols"`  // Largest first, possibly capped
+	// Symbols found in the map, SymbolsSeen > len(Symbols) when capped
+	SymbolsSeen int64 `json:"symbolsSeen"`
+	// Input sections removed by --gc-sections and their total size
+	DiscardedSections int   `json:"discardedSections,omitempty"`
+	DiscardedSize     int64 `json:"discardedSize,omitempty"`
+}
+
+// LinkSection is an output section of the linked binary
+type LinkSection struct {
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+}
+
+// LinkSymbol is a symbol placed in the linked binary
+type LinkSymbol struct {
+	Name    string `json:"name"`
+	Section string `json:"section"` // Output section
+	Object  string `json:"object"`  // Object file or archive member defining it
+	Size    int64  `json:"size"`
 }
 
 // Hardware represents system hardware information
@@ -51,6 +125,7 @@ type CPU struct {
 	Threads   int32   `json:"threads"`
 	Vendor    string  `json:"vendor"`
 	CacheSize int64   `json:"cacheSize"`
+	NUMANodes int32   `json:"numaNodes,omitempty"` // 0 when unknown
 }
 
 type Memory struct {
@@ -72,7 +147,10 @@ type GPU struct {
 type Compiler struct {
 	Name          string            `json:"name"`
 	Version       string            `json:"version"`
+	VersionString string            `json:"versionString,omitempty"` // Full --version banner
+	Wrapper       string            `json:"wrapper,omitempty"`       // ccache, distcc and the like
 	Target        string            `json:"target"`
+	Triple        TargetTriple      `json:"triple"` // Target split into its components
 	Options       []string          `json:"options"`
 	Optimizations map[string]bool   `json:"optimizations"`
 	Flags         map[string]string `json:"flags"`
@@ -81,6 +159,34 @@ type Compiler struct {
 	Features      CompilerFeatures  `json:"features"`
 }
 
+// TargetTriple is a target triple split into its components, with the
+// architecture normalised so builds can be grouped by it
+type TargetTriple struct {
+	Arch   string `json:"arch,omitempty"`
+	Vendor string `json:"vendor,omitempty"`
+	OS     string `json:"os,omitempty"`
+	ABI    string `json:"abi,omitempty"`
+}
+
+// String returns the normalised triple, or "" when the target is unknown
+func (t TargetTriple) String() string {
+	if t.Arch == "" {
+		return ""
+	}
+	parts := []string{t.Arch, orUnknown(t.Vendor), orUnknown(t.OS)}
+	if t.ABI != "" {
+		parts = append(parts, t.ABI)
+	}
+	return strings.Join(parts, "-")
+}
+
+func orUnknown(component string) string {
+	if component == "" {
+		return "unknown"
+	}
+	return component
+}
+
 type Language struct {
 	Name          string `json:"name"`
 	Version       string `json:"version"`
@@ -98,19 +204,91 @@ type CompilerFeatures struct {
 // Command represents the build command execution
 type Command struct {
 	Executable string            `json:"executable"`
-	Arguments  []string          `json:"arguments"`
+	Arguments  []string          `json:"arguments"` // As given, without flags collectors add
 	WorkingDir string            `json:"workingDir"`
 	Env        map[string]string `json:"env"`
+	Hash       string            `json:"hash,omitempty"` // Set by the server, see invocations.Hash
+
+	// Programs the compiler driver ran, in order, when captured with -###
+	Invocations []Invocation `json:"invocations,omitempty"`
+}
+
+// Invocation is a subprocess run by the compiler driver, such as cc1,
+// the assembler or the linker
+type Invocation struct {
+	Tool      string   `json:"tool"`
+	Arguments []string `json:"arguments"` // Including the program itself
 }
 
 // Output represents build output information
 type Output struct {
-	Stdout    string     `json:"stdout"`
-	Stderr    string     `json:"stderr"`
-	Artifacts []Artifact `json:"artifacts"`
-	ExitCode  int32      `json:"exitCode"`
-	Warnings  []string   `json:"warnings"`
-	Errors    []string   `json:"errors"`
+	Stdout      string       `json:"stdout"`
+	Stderr      string       `json:"stderr"`
+	Artifacts   []Artifact   `json:"artifacts"`
+	ExitCode    int32        `json:"exitCode"`
+	Diagnostics []Diagnostic `json:"diagnostics,omitempty"`
+	Truncated   bool         `json:"truncated,omitempty"` // Middle of long stdout or stderr dropped
+}
+
+// Diagnostic severities, as printed by the compiler
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityNote    = "note"
+	SeverityRemark  = "remark"
+)
+
+// Diagnostic is a single message the compiler printed while building
+type Diagnostic struct {
+	Severity string `json:"severity"`
+	File     string `json:"file,omitempty"`
+	Line     int32  `json:"line,omitempty"`
+	Col      int32  `json:"col,omitempty"`
+	Message  string `json:"message"`
+}
+
+// String formats the diagnostic the way compilers print it
+func (d Diagnostic) String() string {
+	switch {
+	case d.File == "":
+		return fmt.Sprintf("%s: %s", d.Severity, d.Message)
+	case d.Line == 0:
+		return fmt.Sprintf("%s: %s: %s", d.File, d.Severity, d.Message)
+	case d.Col == 0:
+		return fmt.Sprintf("%s:%d: %s: %s", d.File, d.Line, d.Severity, d.Message)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s: %s", d.File, d.Line, d.Col, d.Severity, d.Message)
+}
+
+// Warnings returns the warnings the compiler printed, formatted as text
+func (o Output) Warnings() []string {
+	return o.messages(SeverityWarning)
+}
+
+// Errors returns the errors the compiler printed, formatted as text
+func (o Output) Errors() []string {
+	return o.messages(SeverityError)
+}
+
+// Count returns the number of diagnostics with the given severity
+func (o Output) Count(severity string) int {
+	count := 0
+	for _, d := range o.Diagnostics {
+		if d.Severity == severity {
+			count++
+		}
+	}
+	return count
+}
+
+func (o Output) messages(severity string) []string {
+	var messages []string
+	for _, d := range o.Diagnostics {
+		if d.Severity == severity {
+			messages = append(messages, d.String())
+		}
+	}
+	return messages
 }
 
 type Artifact struct {
@@ -151,6 +329,14 @@ const (
 	RemarkStatusAnalysis RemarkStatus = "analysis"
 )
 
+// RemarkCount is the number of remarks a pass emitted with one status, which
+// the server keeps once a build's remarks are purged
+type RemarkCount struct {
+	Pass   string `json:"pass"`
+	Status string `json:"status"`
+	Count  int64  `json:"count"`
+}
+
 // CompilerRemark represents a generic compiler remark/diagnostic
 // CompilerRemark represents a generic compiler remark/diagnostic
 type CompilerRemark struct {
@@ -171,6 +357,30 @@ type CompilerRemark struct {
 	Metadata   JSON        `json:"metadata,omitempty"`
 }
 
+// Kind classifies the remark by its status. Passed and missed remarks are
+// optimizations, or kernel remarks when they carry kernel information;
+// analysis remarks explain decisions and anything else is informational.
+func (r CompilerRemark) Kind() RemarkType {
+	switch {
+	case strings.EqualFold(r.Status, string(RemarkStatusPassed)), strings.EqualFold(r.Status, string(RemarkStatusMissed)):
+		if r.KernelInfo != nil {
+			return RemarkTypeKernel
+		}
+		return RemarkTypeOptimization
+	case strings.EqualFold(r.Status, string(RemarkStatusAnalysis)):
+		return RemarkTypeAnalysis
+	default:
+		return RemarkTypeInfo
+	}
+}
+
+// Actionable reports whether the remark records an optimization that was
+// applied or missed, as opposed to analysis, metric or informational output
+func (r CompilerRemark) Actionable() bool {
+	kind := r.Kind()
+	return kind == RemarkTypeOptimization || kind == RemarkTypeKernel
+}
+
 // RemarkArgs represents structured arguments from YAML
 type RemarkArgs struct {
 	Strings     []string          `json:"strings,omitempty"`
@@ -233,6 +443,12 @@ type KernelInfo struct {
 	Attributes     map[string]string `json:"attributes,omitempty"`
 }
 
+// Kernel metrics derived from register allocation remarks
+const (
+	MetricSpillStores = "spill_stores"
+	MetricSpillLoads  = "spill_loads"
+)
+
 type BasicBlock struct {
 	Name         string   `json:"name"`
 	Instructions int32    `json:"instructions"`
@@ -254,6 +470,10 @@ type ResourceUsage struct {
 	CPUTime   float64 `json:"cpuTime"`
 	Threads   int32   `json:"threads"`
 	IO        IOStats `json:"io"`
+
+	// Bytes the compiler's garbage collector allocated in each phase, when
+	// the compiler reports it
+	PhaseMemory map[string]int64 `json:"phaseMemory,omitempty"`
 }
 
 type IOStats struct {
@@ -278,8 +498,15 @@ type BuildMetrics struct {
 	Warnings       int32              `json:"warnings"`
 	Errors         int32              `json:"errors"`
 	InputSize      int64              `json:"inputSize"`
-	OutputSize     int64              `json:"outputSize"`
+	OutputSize     int64              `json:"outputSize"` // Bytes on disk of the files the compiler wrote
 	Metrics        map[string]float64 `json:"metrics"`
+
+	// Loaded size of the output binaries as size(1) reports it, see
+	// binsize.Sizes
+	TextSize     int64            `json:"textSize,omitempty"`
+	DataSize     int64            `json:"dataSize,omitempty"`
+	BSSSize      int64            `json:"bssSize,omitempty"`
+	SectionSizes map[string]int64 `json:"sectionSizes,omitempty"`
 }
 
 type JSON map[string]interface{}
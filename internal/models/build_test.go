@@ -0,0 +1,33 @@
+package models_test
+
+import (
+	"testing"
+
+	"builds/internal/models"
+)
+
+func TestRemarkKind(t *testing.T) {
+	tests := []struct {
+		name       string
+		remark     models.CompilerRemark
+		want       models.RemarkType
+		actionable bool
+	}{
+		{"passed", models.CompilerRemark{Status: "passed"}, models.RemarkTypeOptimization, true},
+		{"missed", models.CompilerRemark{Status: "Missed"}, models.RemarkTypeOptimization, true},
+		{"kernel", models.CompilerRemark{Status: "missed", KernelInfo: &models.KernelInfo{}}, models.RemarkTypeKernel, true},
+		{"analysis", models.CompilerRemark{Status: "Analysis"}, models.RemarkTypeAnalysis, false},
+		{"no status", models.CompilerRemark{}, models.RemarkTypeInfo, false},
+		{"other status", models.CompilerRemark{Status: "failure"}, models.RemarkTypeInfo, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.remark.Kind(); got != tt.want {
+				t.Errorf("Kind() = %s, want %s", got, tt.want)
+			}
+			if got := tt.remark.Actionable(); got != tt.actionable {
+				t.Errorf("Actionable() = %v, want %v", got, tt.actionable)
+			}
+		})
+	}
+}
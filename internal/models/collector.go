@@ -30,9 +30,11 @@ type BaseCollector struct {
 // CollectorConfig holds configuration for collectors
 type CollectorConfig struct {
 	Enabled     bool
-	Timeout     int
+	Timeout     int // Seconds each collector but the compile may run, 0 for no limit
 	MaxAttempts int
-	Options     map[string]interface{}
+	// Seconds the compile may run, 0 for no limit
+	CompileTimeout int
+	Options        map[string]interface{}
 }
 
 // BuildContext holds context for a build operation
@@ -44,7 +46,13 @@ type BuildContext struct {
 	OutputDir  string
 	Compiler   string
 	Args       []string
-	Config     *CollectorConfig
+	Wrapper    string // Command the compiler runs under, such as ccache
+
+	// Env holds KEY=value variables added to the environment of the compile
+	// alone, not of other commands the collectors run
+	Env []string
+
+	Config *CollectorConfig
 }
 
 // CollectorFactory manages collectors
@@ -0,0 +1,52 @@
+// internal/models/remark.go
+
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// remarkIDLength is the number of hex characters kept from the digest
+const remarkIDLength = 16
+
+// RemarkID derives a deterministic identifier for a remark. The ordinal
+// disambiguates remarks that share the same pass, name and location.
+func RemarkID(buildID, pass, name string, loc Location, ordinal int) string {
+	key := fmt.Sprintf("%s\x00%s\x00%s\x00%s:%d:%d\x00%d",
+		buildID, pass, name, loc.File, loc.Line, loc.Column, ordinal)
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])[:remarkIDLength]
+}
+
+// AssignRemarkIDs sets a stable ID on every remark of a build. Parsing the
+// same remarks again in the same order yields the same IDs.
+func AssignRemarkIDs(buildID string, remarks []CompilerRemark) {
+	NewRemarkIDAssigner(buildID).Assign(remarks)
+}
+
+// RemarkIDAssigner assigns stable IDs to remarks that arrive in batches,
+// producing the same IDs as AssignRemarkIDs over the whole sequence
+type RemarkIDAssigner struct {
+	buildID  string
+	ordinals map[string]int
+}
+
+// NewRemarkIDAssigner creates an assigner for a build
+func NewRemarkIDAssigner(buildID string) *RemarkIDAssigner {
+	return &RemarkIDAssigner{
+		buildID:  buildID,
+		ordinals: make(map[string]int),
+	}
+}
+
+// Assign sets the ID of each remark in the next batch
+func (a *RemarkIDAssigner) Assign(remarks []CompilerRemark) {
+	for i := range remarks {
+		r := &remarks[i]
+		key := RemarkID(a.buildID, r.Pass, r.Name, r.Location, 0)
+		r.ID = RemarkID(a.buildID, r.Pass, r.Name, r.Location, a.ordinals[key])
+		a.ordinals[key]++
+	}
+}
@@ -0,0 +1,93 @@
+package models_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"builds/internal/models"
+	"builds/internal/parsers/remarks"
+)
+
+// record has two remarks that differ only by their position in the file
+const record = `--- !Missed
+Pass:            loop-vectorize
+Name:            MissedDetails
+DebugLoc:        { File: a.c, Line: 10, Column: 3 }
+Function:        foo
+Args:
+  - String:          loop not vectorized
+--- !Missed
+Pass:            loop-vectorize
+Name:            MissedDetails
+DebugLoc:        { File: a.c, Line: 10, Column: 3 }
+Function:        foo
+Args:
+  - String:          loop not vectorized
+--- !Passed
+Pass:            inline
+Name:            Inlined
+DebugLoc:        { File: a.c, Line: 4, Column: 1 }
+Function:        foo
+Args:
+  - String:          inlined
+`
+
+func parse(t *testing.T) []models.CompilerRemark {
+	t.Helper()
+	var parsed []models.CompilerRemark
+	err := remarks.ParseReader(strings.NewReader(record), "", func(remark models.CompilerRemark) error {
+		parsed = append(parsed, remark)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed
+}
+
+func ids(remarks []models.CompilerRemark) []string {
+	var ids []string
+	for _, remark := range remarks {
+		ids = append(ids, remark.ID)
+	}
+	return ids
+}
+
+func TestAssignRemarkIDs(t *testing.T) {
+	first := parse(t)
+	models.AssignRemarkIDs("b1", first)
+
+	seen := make(map[string]bool)
+	for _, id := range ids(first) {
+		if id == "" || seen[id] {
+			t.Errorf("ID %q is empty or repeated in %v", id, ids(first))
+		}
+		seen[id] = true
+	}
+
+	// Parsing the record again gives the same IDs
+	again := parse(t)
+	models.AssignRemarkIDs("b1", again)
+	if !reflect.DeepEqual(ids(again), ids(first)) {
+		t.Errorf("re-parse gave %v, want %v", ids(again), ids(first))
+	}
+
+	// Another build gets its own IDs
+	other := parse(t)
+	models.AssignRemarkIDs("b2", other)
+	for _, id := range ids(other) {
+		if seen[id] {
+			t.Errorf("ID %q shared across builds", id)
+		}
+	}
+
+	// Batches get the IDs the whole sequence would
+	batched := parse(t)
+	assigner := models.NewRemarkIDAssigner("b1")
+	assigner.Assign(batched[:1])
+	assigner.Assign(batched[1:])
+	if !reflect.DeepEqual(ids(batched), ids(first)) {
+		t.Errorf("batches gave %v, want %v", ids(batched), ids(first))
+	}
+}
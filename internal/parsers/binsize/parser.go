@@ -0,0 +1,153 @@
+// internal/parsers/binsize/parser.go
+
+package binsize
+
+import (
+	"debug/elf"
+	"debug/macho"
+	"debug/pe"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnknownFormat is returned for files that are not ELF, Mach-O or PE
+var ErrUnknownFormat = errors.New("not an ELF, Mach-O or PE file")
+
+// Sizes splits a binary into the sections loaded at run time, grouped the
+// way size(1) does: code and read-only data count as text, writable data
+// as data, and zero-initialized data as bss, which takes no space on disk.
+type Sizes struct {
+	Text     int64
+	Data     int64
+	BSS      int64
+	Sections map[string]int64 // Loaded sections by name, per-symbol sections folded in
+}
+
+// Read measures the ELF, Mach-O or PE file at path, executables and
+// object files alike
+func Read(path string) (*Sizes, error) {
+	if f, err := elf.Open(path); err == nil {
+		defer f.Close()
+		return readELF(f), nil
+	}
+	if f, err := macho.Open(path); err == nil {
+		defer f.Close()
+		return readMachO(f), nil
+	}
+	if f, err := pe.Open(path); err == nil {
+		defer f.Close()
+		return readPE(f), nil
+	}
+	return nil, fmt.Errorf("%s: %w", path, ErrUnknownFormat)
+}
+
+func (s *Sizes) add(name string, size int64, group *int64) {
+	if size <= 0 {
+		return
+	}
+	*group += size
+	s.Sections[name] += size
+}
+
+func readELF(f *elf.File) *Sizes {
+	sizes := &Sizes{Sections: make(map[string]int64)}
+	for _, section := range f.Sections {
+		if section.Flags&elf.SHF_ALLOC == 0 {
+			continue
+		}
+		name, size := elfSectionName(section.Name), int64(section.Size)
+		switch {
+		case section.Type == elf.SHT_NOBITS:
+			sizes.add(name, size, &sizes.BSS)
+		case section.Flags&elf.SHF_WRITE != 0:
+			sizes.add(name, size, &sizes.Data)
+		default:
+			sizes.add(name, size, &sizes.Text)
+		}
+	}
+	return sizes
+}
+
+// elfOutputSections are the sections linkers gather per-symbol sections
+// into, longest first so .data.rel.ro wins over .data
+var elfOutputSections = []string{
+	".gcc_except_table", ".data.rel.ro", ".rodata", ".tdata", ".text", ".data", ".tbss", ".bss",
+}
+
+// elfSectionName folds the per-function and per-variable sections of
+// objects built with -ffunction-sections or -fdata-sections, such as
+// .text.foo, into the section the linker places them in. Keeping them
+// apart would store a section for every symbol.
+func elfSectionName(name string) string {
+	for _, output := range elfOutputSections {
+		if strings.HasPrefix(name, output+".") {
+			return output
+		}
+	}
+	return name
+}
+
+// Mach-O section types holding zero-filled data
+const (
+	machoZerofill            = 0x1
+	machoGBZerofill          = 0xc
+	machoThreadLocalZerofill = 0x12
+	machoSectionType         = 0xff
+)
+
+func readMachO(f *macho.File) *Sizes {
+	sizes := &Sizes{Sections: make(map[string]int64)}
+	for _, section := range f.Sections {
+		name := section.Seg + "," + section.Name
+		size := int64(section.Size)
+		switch section.Flags & machoSectionType {
+		case machoZerofill, machoGBZerofill, machoThreadLocalZerofill:
+			sizes.add(name, size, &sizes.BSS)
+			continue
+		}
+		switch section.Seg {
+		case "__TEXT":
+			sizes.add(name, size, &sizes.Text)
+		case "__PAGEZERO", "__LINKEDIT":
+		default:
+			sizes.add(name, size, &sizes.Data)
+		}
+	}
+	return sizes
+}
+
+// PE section characteristics
+const (
+	peCode              = 0x00000020
+	peUninitializedData = 0x00000080
+	peDiscardable       = 0x02000000
+	peWrite             = 0x80000000
+)
+
+func readPE(f *pe.File) *Sizes {
+	sizes := &Sizes{Sections: make(map[string]int64)}
+	for _, section := range f.Sections {
+		flags := section.Characteristics
+		if flags&peDiscardable != 0 {
+			continue // Relocations and debug data are not loaded
+		}
+		// VirtualSize is the size in memory, Size the padded size on disk
+		size := int64(section.VirtualSize)
+		if size == 0 {
+			size = int64(section.Size)
+		}
+		// Objects group sections as .text$mn, which the linker merges
+		// into .text
+		name, _, _ := strings.Cut(section.Name, "$")
+		switch {
+		case flags&peUninitializedData != 0:
+			sizes.add(name, size, &sizes.BSS)
+		case flags&peCode == 0 && flags&peWrite != 0:
+			sizes.add(name, size, &sizes.Data)
+		default:
+			sizes.add(name, size, &sizes.Text)
+		}
+	}
+	return sizes
+}
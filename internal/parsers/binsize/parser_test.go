@@ -0,0 +1,128 @@
+package binsize
+
+import (
+	"bytes"
+	"debug/elf"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// elfSection is a section of the ELF file writeELF builds
+type elfSection struct {
+	name  string
+	typ   elf.SectionType
+	flags elf.SectionFlag
+	size  uint64
+}
+
+// writeELF writes a minimal 64-bit ELF object holding only section headers
+// and their names; the sections have sizes but no contents
+func writeELF(t *testing.T, sections []elfSection) string {
+	t.Helper()
+	sections = append(sections, elfSection{name: ".shstrtab", typ: elf.SHT_STRTAB})
+
+	names := []byte{0}
+	offsets := make([]uint32, len(sections))
+	for i, section := range sections {
+		offsets[i] = uint32(len(names))
+		names = append(names, section.name...)
+		names = append(names, 0)
+	}
+
+	const headerSize = 64
+	namesOffset := uint64(headerSize)
+	headersOffset := namesOffset + uint64(len(names))
+
+	var buf bytes.Buffer
+	header := elf.Header64{
+		Type:      uint16(elf.ET_REL),
+		Machine:   uint16(elf.EM_X86_64),
+		Version:   uint32(elf.EV_CURRENT),
+		Shoff:     headersOffset,
+		Ehsize:    headerSize,
+		Shentsize: 64,
+		Shnum:     uint16(len(sections) + 1),
+		Shstrndx:  uint16(len(sections)),
+	}
+	copy(header.Ident[:], elf.ELFMAG)
+	header.Ident[elf.EI_CLASS] = byte(elf.ELFCLASS64)
+	header.Ident[elf.EI_DATA] = byte(elf.ELFDATA2LSB)
+	header.Ident[elf.EI_VERSION] = byte(elf.EV_CURRENT)
+	binary.Write(&buf, binary.LittleEndian, header)
+	buf.Write(names)
+
+	// The null section comes first
+	binary.Write(&buf, binary.LittleEndian, elf.Section64{})
+	for i, section := range sections {
+		header := elf.Section64{
+			Name:  offsets[i],
+			Type:  uint32(section.typ),
+			Flags: uint64(section.flags),
+			Size:  section.size,
+		}
+		if section.name == ".shstrtab" {
+			header.Off, header.Size = namesOffset, uint64(len(names))
+		}
+		binary.Write(&buf, binary.LittleEndian, header)
+	}
+
+	path := filepath.Join(t.TempDir(), "foo.o")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReadELF(t *testing.T) {
+	path := writeELF(t, []elfSection{
+		{".text", elf.SHT_PROGBITS, elf.SHF_ALLOC | elf.SHF_EXECINSTR, 0x120},
+		{".rodata", elf.SHT_PROGBITS, elf.SHF_ALLOC, 0x30},
+		{".data", elf.SHT_PROGBITS, elf.SHF_ALLOC | elf.SHF_WRITE, 0x18},
+		{".bss", elf.SHT_NOBITS, elf.SHF_ALLOC | elf.SHF_WRITE, 0x400},
+		{".tdata", elf.SHT_PROGBITS, elf.SHF_ALLOC | elf.SHF_WRITE | elf.SHF_TLS, 0},
+		{".comment", elf.SHT_PROGBITS, 0, 0x2a},
+		{".debug_info", elf.SHT_PROGBITS, 0, 0x1000},
+		// As -ffunction-sections and -fdata-sections emit them
+		{".text.foo", elf.SHT_PROGBITS, elf.SHF_ALLOC | elf.SHF_EXECINSTR, 0x40},
+		{".text.unlikely.bar", elf.SHT_PROGBITS, elf.SHF_ALLOC | elf.SHF_EXECINSTR, 0x10},
+		{".rodata.str1.1", elf.SHT_PROGBITS, elf.SHF_ALLOC | elf.SHF_MERGE | elf.SHF_STRINGS, 0x8},
+		{".data.rel.ro.table", elf.SHT_PROGBITS, elf.SHF_ALLOC | elf.SHF_WRITE, 0x20},
+		{".bss.counter", elf.SHT_NOBITS, elf.SHF_ALLOC | elf.SHF_WRITE, 0x4},
+		{".textual", elf.SHT_PROGBITS, elf.SHF_ALLOC, 0x2},
+	})
+
+	got, err := Read(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Sizes{
+		Text: 0x1aa,
+		Data: 0x38,
+		BSS:  0x404,
+		Sections: map[string]int64{
+			".text":        0x170,
+			".rodata":      0x38,
+			".textual":     0x2,
+			".data":        0x18,
+			".data.rel.ro": 0x20,
+			".bss":         0x404,
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestReadUnknownFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "foo.txt")
+	if err := os.WriteFile(path, []byte("not a binary\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Read(path); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("Read: %v, want %v", err, ErrUnknownFormat)
+	}
+}
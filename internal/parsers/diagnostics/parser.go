@@ -0,0 +1,79 @@
+// internal/parsers/diagnostics/parser.go
+
+package diagnostics
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"builds/internal/models"
+)
+
+var (
+	// GCC and Clang, with the column and even the line being optional:
+	//
+	//	main.c:12:5: warning: unused variable 'x' [-Wunused-variable]
+	//	cc1: error: unrecognized command-line option '-fno-foo'
+	gccPattern = regexp.MustCompile(`^(.+?):(?:(\d+):(?:(\d+):)?)?\s*(fatal error|error|warning|note|remark):\s*(.*)$`)
+
+	// MSVC, which puts the location in parentheses and adds a code:
+	//
+	//	main.c(12,5): warning C4101: 'x': unreferenced local variable
+	msvcPattern = regexp.MustCompile(`^(.+?)\((\d+)(?:,(\d+))?\)\s*:\s*(fatal error|error|warning|note)\s*(?:[A-Z]+\d+)?\s*:\s*(.*)$`)
+)
+
+// Parse reads compiler diagnostics from build output. Lines that are not
+// diagnostics, such as source excerpts and carets, are skipped, so the full
+// compiler stderr can be passed in.
+func Parse(r io.Reader) ([]models.Diagnostic, error) {
+	var diagnostics []models.Diagnostic
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if d, ok := parseLine(strings.TrimRight(scanner.Text(), "\r")); ok {
+			diagnostics = append(diagnostics, d)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return diagnostics, err
+	}
+	return diagnostics, nil
+}
+
+func parseLine(line string) (models.Diagnostic, bool) {
+	matches := gccPattern.FindStringSubmatch(line)
+	if matches == nil {
+		matches = msvcPattern.FindStringSubmatch(line)
+	}
+	if matches == nil {
+		return models.Diagnostic{}, false
+	}
+
+	return models.Diagnostic{
+		Severity: severity(matches[4]),
+		File:     matches[1],
+		Line:     atoi(matches[2]),
+		Col:      atoi(matches[3]),
+		Message:  matches[5],
+	}, true
+}
+
+// severity folds fatal errors into errors
+func severity(s string) string {
+	if s == "fatal error" {
+		return models.SeverityError
+	}
+	return s
+}
+
+func atoi(s string) int32 {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0
+	}
+	return int32(n)
+}
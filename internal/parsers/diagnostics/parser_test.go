@@ -0,0 +1,69 @@
+package diagnostics
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"builds/internal/models"
+)
+
+// Stderr of a clang build with every severity, the source excerpts and
+// carets it prints between them, and an MSVC warning
+const stderr = `In file included from main.c:1:
+util.h:4:6: note: previous declaration is here
+    4 | void foo(int);
+      |      ^
+main.c:12:5: warning: unused variable 'x' [-Wunused-variable]
+   12 |     int x;
+      |     ^
+main.c:20:1: error: expected ';' after expression
+main.c:30: remark: loop not vectorized
+cc1: fatal error: foo.h: No such file or directory
+clang: warning: argument unused during compilation: '-fno-foo'
+lib.c(7,3): warning C4101: 'y': unreferenced local variable
+2 errors generated.
+`
+
+func TestParse(t *testing.T) {
+	got, err := Parse(strings.NewReader(stderr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []models.Diagnostic{
+		{Severity: models.SeverityNote, File: "util.h", Line: 4, Col: 6, Message: "previous declaration is here"},
+		{Severity: models.SeverityWarning, File: "main.c", Line: 12, Col: 5, Message: "unused variable 'x' [-Wunused-variable]"},
+		{Severity: models.SeverityError, File: "main.c", Line: 20, Col: 1, Message: "expected ';' after expression"},
+		{Severity: models.SeverityRemark, File: "main.c", Line: 30, Message: "loop not vectorized"},
+		{Severity: models.SeverityError, File: "cc1", Message: "foo.h: No such file or directory"},
+		{Severity: models.SeverityWarning, File: "clang", Message: "argument unused during compilation: '-fno-foo'"},
+		{Severity: models.SeverityWarning, File: "lib.c", Line: 7, Col: 3, Message: "'y': unreferenced local variable"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	output := models.Output{Diagnostics: got}
+	counts := map[string]int{
+		models.SeverityError:   2,
+		models.SeverityWarning: 3,
+		models.SeverityNote:    1,
+		models.SeverityRemark:  1,
+	}
+	for severity, want := range counts {
+		if got := output.Count(severity); got != want {
+			t.Errorf("Count(%s) = %d, want %d", severity, got, want)
+		}
+	}
+
+	errors := []string{
+		"main.c:20:1: error: expected ';' after expression",
+		"cc1: error: foo.h: No such file or directory",
+	}
+	if got := output.Errors(); !reflect.DeepEqual(got, errors) {
+		t.Errorf("Errors() = %q, want %q", got, errors)
+	}
+	if got := output.Warnings(); len(got) != 3 || got[2] != "lib.c:7:3: warning: 'y': unreferenced local variable" {
+		t.Errorf("Warnings() = %q", got)
+	}
+}
@@ -0,0 +1,191 @@
+// internal/parsers/invocations/hash.go
+
+package invocations
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// outputFlags name an output file in the next argument or, when joined, in
+// the rest of the argument. They are left out of the hash.
+var outputFlags = []string{"-o", "-MF", "-MT", "-MQ", "-MJ"}
+
+// outputPrefixes are joined flags naming output files, including the MSVC
+// /F family, and the flags the remark collector adds to every compile
+var outputPrefixes = []string{
+	"-Wp,-MD,", "-Wp,-MMD,",
+	"/Fo", "/Fe", "/Fd", "/Fa", "/Fp", "/Fm", "/FR", "/Fr", "/Fi",
+	"-Fo", "-Fe", "-Fd", "-Fa", "-Fp", "-Fm", "-FR", "-Fr", "-Fi",
+	"-fsave-optimization-record", "-foptimization-record-file",
+	"/Qvec-report", "/Qpar-report", "-ftime-report", "-Wl,-Map",
+}
+
+// pathFlags take a path, either in the next argument or joined
+var pathFlags = []string{"-I", "-L", "-isystem", "-iquote", "-idirafter", "-include", "-imacros", "-isysroot", "--sysroot"}
+
+// valueFlags take a value in the next argument
+var valueFlags = map[string]bool{
+	"-D": true, "-U": true, "-l": true, "-x": true, "-target": true, "-arch": true,
+	"-Xclang": true, "-Xlinker": true, "-Xassembler": true, "-Xpreprocessor": true, "-mllvm": true,
+}
+
+// joinedFlags are written without a space once normalised, so -I dir and
+// -Idir are the same argument
+var joinedFlags = map[string]bool{"-I": true, "-L": true, "-D": true, "-U": true, "-l": true}
+
+// Hash identifies a compiler invocation across runs: the same compile of
+// the same translation unit hashes the same wherever it was checked out.
+// It is the hex SHA-256 of the normalised command, see Normalize.
+func Hash(executable string, args []string, workDir string) string {
+	sum := sha256.Sum256([]byte(strings.Join(Normalize(executable, args, workDir), "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// Normalize reduces a command to the parts that decide what is compiled:
+// the executable's base name followed by the sorted arguments. Flags and
+// their values become single arguments, paths inside workDir are made
+// relative to it, and output files, temporary files and the flags the
+// remark collector adds are dropped.
+func Normalize(executable string, args []string, workDir string) []string {
+	var normalized []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		if isOutputFlag(arg) {
+			i++ // Skip the output path
+			continue
+		}
+		if isOutput(arg) || strings.HasPrefix(arg, "@") && isTemp(arg[1:]) {
+			continue
+		}
+
+		flag, value, separate := splitFlag(arg)
+		if separate {
+			if i+1 >= len(args) {
+				normalized = append(normalized, arg)
+				break
+			}
+			i++
+			value = args[i]
+		}
+
+		switch {
+		case flag == "" && strings.HasPrefix(arg, "-"):
+			normalized = append(normalized, arg)
+		case flag == "":
+			// An input file
+			if isTemp(arg) {
+				continue
+			}
+			normalized = append(normalized, canonicalPath(arg, workDir))
+		default:
+			if isPathFlag(flag) {
+				if isTemp(value) {
+					continue
+				}
+				value = canonicalPath(value, workDir)
+			}
+			normalized = append(normalized, joinFlag(flag, value))
+		}
+	}
+
+	sort.Strings(normalized)
+	tool := strings.TrimSuffix(filepath.Base(executable), ".exe")
+	return append([]string{tool}, normalized...)
+}
+
+// splitFlag splits arg into a flag taking a value and that value. separate
+// is set when the value is the next argument. Arguments that take no value
+// return an empty flag.
+func splitFlag(arg string) (flag, value string, separate bool) {
+	if valueFlags[arg] || isPathFlag(arg) {
+		return arg, "", true
+	}
+	if name, value, ok := strings.Cut(arg, "="); ok && isPathFlag(name) {
+		return name, value, false
+	}
+	for name := range joinedFlags {
+		if strings.HasPrefix(arg, name) && len(arg) > len(name) {
+			return name, arg[len(name):], false
+		}
+	}
+	for _, name := range pathFlags {
+		if strings.HasPrefix(arg, name) && len(arg) > len(name) && !joinedFlags[name] && name != "--sysroot" {
+			return name, arg[len(name):], false
+		}
+	}
+	return "", "", false
+}
+
+func joinFlag(flag, value string) string {
+	if joinedFlags[flag] {
+		return flag + value
+	}
+	return flag + " " + value
+}
+
+func isPathFlag(flag string) bool {
+	for _, name := range pathFlags {
+		if flag == name {
+			return true
+		}
+	}
+	return false
+}
+
+func isOutputFlag(arg string) bool {
+	for _, flag := range outputFlags {
+		if arg == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// isOutput reports whether arg is an output flag with its path joined
+func isOutput(arg string) bool {
+	for _, prefix := range outputPrefixes {
+		if strings.HasPrefix(arg, prefix) {
+			return true
+		}
+	}
+	for _, flag := range outputFlags {
+		// -objc flags are not -o with a path
+		if strings.HasPrefix(arg, flag) && !strings.HasPrefix(arg, "-objc") {
+			return true
+		}
+	}
+	return false
+}
+
+// isTemp reports whether path lies in a temporary directory, as the files
+// that build tools generate for a single compile do
+func isTemp(path string) bool {
+	if !filepath.IsAbs(path) {
+		return false
+	}
+	path = filepath.Clean(path)
+	for _, dir := range []string{os.TempDir(), "/tmp", "/var/tmp"} {
+		if path == dir || strings.HasPrefix(path, dir+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// canonicalPath cleans path, making it relative to workDir when it lies
+// inside it
+func canonicalPath(path, workDir string) string {
+	path = filepath.Clean(path)
+	if workDir != "" && filepath.IsAbs(path) {
+		if rel, err := filepath.Rel(workDir, path); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			path = rel
+		}
+	}
+	return filepath.ToSlash(path)
+}
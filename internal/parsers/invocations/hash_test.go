@@ -0,0 +1,69 @@
+package invocations
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	args := []string{
+		"-O2", "-I", "/home/me/proj/include", "-DNDEBUG", "-c", "src/foo.c",
+		"-o", "build/foo.o", "-MF", "build/foo.d", "-fsave-optimization-record",
+		"-foptimization-record-file=build/foo.opt.yaml", "-isystem", "/usr/include/x",
+	}
+	want := []string{
+		"clang",
+		"-DNDEBUG", "-Iinclude", "-O2", "-c", "-isystem /usr/include/x", "src/foo.c",
+	}
+	if got := Normalize("/usr/bin/clang", args, "/home/me/proj"); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestHash(t *testing.T) {
+	type invocation struct {
+		executable string
+		args       []string
+		workDir    string
+	}
+	base := invocation{"clang", []string{"-O2", "-Iinclude", "-DFOO=1", "-c", "foo.c", "-o", "foo.o"}, "/home/me/proj"}
+
+	same := []struct {
+		name string
+		inv  invocation
+	}{
+		{"full path to the compiler", invocation{"/usr/local/bin/clang", base.args, base.workDir}},
+		{"flags reordered", invocation{"clang", []string{"-c", "-DFOO=1", "foo.c", "-Iinclude", "-O2", "-o", "foo.o"}, base.workDir}},
+		{"separate flag values", invocation{"clang", []string{"-O2", "-I", "include", "-D", "FOO=1", "-c", "foo.c", "-o", "foo.o"}, base.workDir}},
+		{"absolute paths in another checkout", invocation{"clang", []string{"-O2", "-I/ci/work/include", "-DFOO=1", "-c", "/ci/work/foo.c", "-o", "/ci/work/foo.o"}, "/ci/work"}},
+		{"other output", invocation{"clang", []string{"-O2", "-Iinclude", "-DFOO=1", "-c", "foo.c", "-o", "out/foo.o", "-MFout/foo.d"}, base.workDir}},
+		{"collector flags", invocation{"clang", append(append([]string{}, base.args...), "-fsave-optimization-record", "-foptimization-record-file=/tmp/x.opt.yaml"), base.workDir}},
+		{"temporary files", invocation{"clang", append(append([]string{}, base.args...), "@/tmp/args.rsp", "-include", "/tmp/pch.h"), base.workDir}},
+		{"unclean paths", invocation{"clang", []string{"-O2", "-I./include/", "-DFOO=1", "-c", "./foo.c", "-o", "foo.o"}, base.workDir}},
+	}
+	different := []struct {
+		name string
+		inv  invocation
+	}{
+		{"other compiler", invocation{"gcc", base.args, base.workDir}},
+		{"other optimization level", invocation{"clang", []string{"-O3", "-Iinclude", "-DFOO=1", "-c", "foo.c", "-o", "foo.o"}, base.workDir}},
+		{"other define", invocation{"clang", []string{"-O2", "-Iinclude", "-DFOO=2", "-c", "foo.c", "-o", "foo.o"}, base.workDir}},
+		{"other source", invocation{"clang", []string{"-O2", "-Iinclude", "-DFOO=1", "-c", "bar.c", "-o", "foo.o"}, base.workDir}},
+		{"path outside the checkout", invocation{"clang", []string{"-O2", "-I/opt/include", "-DFOO=1", "-c", "foo.c", "-o", "foo.o"}, base.workDir}},
+	}
+
+	want := Hash(base.executable, base.args, base.workDir)
+	if len(want) != 64 {
+		t.Errorf("hash %q is not hex SHA-256", want)
+	}
+	for _, tt := range same {
+		if got := Hash(tt.inv.executable, tt.inv.args, tt.inv.workDir); got != want {
+			t.Errorf("%s: hash differs, normalized to %q", tt.name, Normalize(tt.inv.executable, tt.inv.args, tt.inv.workDir))
+		}
+	}
+	for _, tt := range different {
+		if got := Hash(tt.inv.executable, tt.inv.args, tt.inv.workDir); got == want {
+			t.Errorf("%s: hash is the same", tt.name)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+// internal/parsers/invocations/parser.go
+
+package invocations
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"builds/internal/models"
+)
+
+// Parse reads the sub-invocations a compiler driver prints for -###. Both
+// GCC and Clang print each one on its own line indented by a space, GCC
+// quoting only arguments with special characters and Clang quoting all of
+// them. Other lines, such as the version banner, GCC's COLLECT_GCC
+// variables and the "(in-process)" note Clang prints before a frontend it
+// runs without forking, are skipped.
+func Parse(r io.Reader) ([]models.Invocation, error) {
+	var invocations []models.Invocation
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, " ") || strings.TrimSpace(line) == "" {
+			continue
+		}
+		if strings.TrimSpace(line) == "(in-process)" {
+			continue
+		}
+		args, err := splitCommand(strings.TrimSpace(line))
+		if err != nil {
+			return invocations, err
+		}
+		invocations = append(invocations, models.Invocation{
+			Tool:      toolName(args),
+			Arguments: args,
+		})
+	}
+	return invocations, scanner.Err()
+}
+
+// toolName names the program an invocation runs. Clang re-invokes itself
+// for the frontend and integrated assembler, so those are told apart by
+// their first argument.
+func toolName(args []string) string {
+	tool := strings.TrimSuffix(filepath.Base(args[0]), ".exe")
+	if len(args) > 1 && strings.HasPrefix(args[1], "-cc1") {
+		return tool + " " + args[1]
+	}
+	return tool
+}
+
+// splitCommand splits a command line printed by -###. Double quoted words
+// may contain spaces and backslash escaped quotes and backslashes.
+func splitCommand(line string) ([]string, error) {
+	var (
+		args    []string
+		word    strings.Builder
+		inWord  bool
+		quoted  bool
+		escaped bool
+	)
+	for _, r := range line {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case quoted && r == '\\':
+			escaped = true
+		case r == '"':
+			quoted = !quoted
+			inWord = true
+		case !quoted && (r == ' ' || r == '\t'):
+			if inWord {
+				args = append(args, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quoted || escaped {
+		return nil, fmt.Errorf("unterminated quote in %q", line)
+	}
+	if inWord {
+		args = append(args, word.String())
+	}
+	return args, nil
+}
@@ -0,0 +1,82 @@
+package invocations
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"builds/internal/models"
+)
+
+// Output of clang -### -O2 foo.c -o foo, shortened
+const clangOutput = `Ubuntu clang version 18.1.3 (1ubuntu1)
+Target: x86_64-pc-linux-gnu
+Thread model: posix
+InstalledDir: /usr/bin
+ (in-process)
+ "/usr/lib/llvm-18/bin/clang" "-cc1" "-triple" "x86_64-pc-linux-gnu" "-O2" "-main-file-name" "foo.c" "-o" "/tmp/foo-1a2b3c.o" "-x" "c" "foo.c"
+ "/usr/bin/ld" "-pie" "-o" "foo" "/tmp/foo-1a2b3c.o" "-lc"
+`
+
+// Output of gcc -### -O2 -DNAME="a b" foo.c -o foo, shortened
+const gccOutput = `Using built-in specs.
+COLLECT_GCC=gcc
+Target: x86_64-linux-gnu
+Configured with: ../src/configure -v --with-pkgversion='Ubuntu 13.2.0-23ubuntu4'
+gcc version 13.2.0 (Ubuntu 13.2.0-23ubuntu4)
+COLLECT_GCC_OPTIONS='-O2' '-D' 'NAME=a b' '-o' 'foo' '-mtune=generic' '-march=x86-64'
+ /usr/libexec/gcc/x86_64-linux-gnu/13/cc1 -quiet -D "NAME=a b" foo.c -quiet -dumpbase foo.c "-mtune=generic" "-march=x86-64" -O2 -o /tmp/ccQz1.s
+ as --64 -o /tmp/ccQz2.o /tmp/ccQz1.s
+ /usr/libexec/gcc/x86_64-linux-gnu/13/collect2 -plugin /usr/libexec/gcc/x86_64-linux-gnu/13/liblto_plugin.so -o foo /tmp/ccQz2.o
+COMPILER_PATH=/usr/libexec/gcc/x86_64-linux-gnu/13/
+`
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []models.Invocation
+	}{
+		{
+			name:   "clang",
+			output: clangOutput,
+			want: []models.Invocation{
+				{Tool: "clang -cc1", Arguments: []string{"/usr/lib/llvm-18/bin/clang", "-cc1", "-triple", "x86_64-pc-linux-gnu", "-O2", "-main-file-name", "foo.c", "-o", "/tmp/foo-1a2b3c.o", "-x", "c", "foo.c"}},
+				{Tool: "ld", Arguments: []string{"/usr/bin/ld", "-pie", "-o", "foo", "/tmp/foo-1a2b3c.o", "-lc"}},
+			},
+		},
+		{
+			name:   "gcc",
+			output: gccOutput,
+			want: []models.Invocation{
+				{Tool: "cc1", Arguments: []string{"/usr/libexec/gcc/x86_64-linux-gnu/13/cc1", "-quiet", "-D", "NAME=a b", "foo.c", "-quiet", "-dumpbase", "foo.c", "-mtune=generic", "-march=x86-64", "-O2", "-o", "/tmp/ccQz1.s"}},
+				{Tool: "as", Arguments: []string{"as", "--64", "-o", "/tmp/ccQz2.o", "/tmp/ccQz1.s"}},
+				{Tool: "collect2", Arguments: []string{"/usr/libexec/gcc/x86_64-linux-gnu/13/collect2", "-plugin", "/usr/libexec/gcc/x86_64-linux-gnu/13/liblto_plugin.so", "-o", "foo", "/tmp/ccQz2.o"}},
+			},
+		},
+		{
+			name:   "escaped quotes",
+			output: ` "clang" "-cc1" "-DMSG=\"hi\"" "C:\\src\\foo.c"` + "\n",
+			want: []models.Invocation{
+				{Tool: "clang -cc1", Arguments: []string{"clang", "-cc1", `-DMSG="hi"`, `C:\src\foo.c`}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(strings.NewReader(tt.output))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseUnterminatedQuote(t *testing.T) {
+	if _, err := Parse(strings.NewReader(` "clang" "-cc1` + "\n")); err == nil {
+		t.Error("parsed an unterminated quote")
+	}
+}
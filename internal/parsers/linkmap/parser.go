@@ -0,0 +1,352 @@
+// internal/parsers/linkmap/parser.go
+
+package linkmap
+
+import (
+	"bufio"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"builds/internal/models"
+)
+
+// Parse reads a linker map file as written by GNU ld (-Map) or lld. It
+// returns the output sections and symbols with a size, largest first, and
+// totals the input sections that --gc-sections discarded.
+//
+// Neither linker reports symbol sizes reliably, so a symbol is sized by the
+// distance to the next symbol of its input section, the last one extending
+// to the end of the section. Local symbols are not listed at all; with
+// -ffunction-sections and -fdata-sections their input sections stand in.
+func Parse(r io.Reader) (*models.LinkMap, error) {
+	p := &parser{}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		p.line(scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return p.result(), nil
+}
+
+// Sections of a GNU ld map file
+const (
+	phaseNone = iota
+	phaseDiscarded
+	phaseMap
+	phaseLLD
+)
+
+var headings = map[string]int{
+	"Archive member included to satisfy reference by file (symbol)": phaseNone,
+	"Allocating common symbols":                                     phaseNone,
+	"Discarded input sections":                                      phaseDiscarded,
+	"Memory Configuration":                                          phaseNone,
+	"Linker script and memory map":                                  phaseMap,
+	"Cross Reference Table":                                         phaseNone,
+	"Linker Plugin Specific Output":                                 phaseNone,
+}
+
+// input is an input section and the symbols defined in it
+type input struct {
+	name    string
+	object  string
+	output  string
+	address uint64
+	size    int64
+	symbols []symbol
+}
+
+type symbol struct {
+	name    string
+	address uint64
+	size    int64 // As reported, zero when unknown
+}
+
+type parser struct {
+	phase    int
+	sections []models.LinkSection
+	output   string // Output section being read
+	inputs   []*input
+	current  *input
+	pending  string // Name of a GNU ld section whose numbers wrapped to the next line
+	discards models.LinkMap
+
+	// lld column offsets, taken from the header
+	outColumn, inColumn, symbolColumn int
+}
+
+func (p *parser) line(text string) {
+	trimmed := strings.TrimSpace(text)
+	if phase, ok := headings[trimmed]; ok {
+		p.phase = phase
+		p.current, p.pending = nil, ""
+		return
+	}
+	if p.phase == phaseNone && p.lldHeader(text) {
+		p.phase = phaseLLD
+		return
+	}
+	if trimmed == "" {
+		return
+	}
+
+	switch p.phase {
+	case phaseDiscarded:
+		p.discardedLine(text)
+	case phaseMap:
+		p.mapLine(text)
+	case phaseLLD:
+		p.lldLine(text)
+	}
+}
+
+// discardedLine counts an input section removed by the linker
+func (p *parser) discardedLine(text string) {
+	fields := strings.Fields(text)
+	if len(fields) == 1 && strings.HasPrefix(text, " ") {
+		p.pending = fields[0]
+		return
+	}
+	if p.pending != "" {
+		fields = append([]string{p.pending}, fields...)
+		p.pending = ""
+	}
+	if len(fields) < 3 {
+		return
+	}
+	size, ok := hexNumber(fields[2])
+	if !ok {
+		return
+	}
+	p.discards.DiscardedSections++
+	p.discards.DiscardedSize += int64(size)
+}
+
+// mapLine handles a line of the GNU ld memory map. Output sections start in
+// the first column, input sections are indented by one space and symbols
+// by many, with their address first.
+func (p *parser) mapLine(text string) {
+	fields := strings.Fields(text)
+
+	if p.pending != "" {
+		// The numbers of a section whose name filled the line
+		name, indented := p.pending, strings.HasPrefix(p.pending, " ")
+		p.pending = ""
+		p.section(strings.TrimSpace(name), indented, fields)
+		return
+	}
+
+	switch {
+	case !strings.HasPrefix(text, " "):
+		if len(fields) == 1 && strings.HasPrefix(fields[0], ".") {
+			p.pending = fields[0]
+			return
+		}
+		p.section(fields[0], false, fields[1:])
+	case !strings.HasPrefix(text, "  "):
+		name := fields[0]
+		if strings.HasPrefix(name, "*") && name != "*fill*" && name != "**" {
+			return // An input section pattern of the linker script
+		}
+		if len(fields) == 1 {
+			p.pending = " " + name
+			return
+		}
+		p.section(name, true, fields[1:])
+	default:
+		p.symbolLine(fields)
+	}
+}
+
+// section records an output or input section from the fields after its name
+func (p *parser) section(name string, isInput bool, fields []string) {
+	if len(fields) < 2 {
+		return
+	}
+	address, ok := hexNumber(fields[0])
+	if !ok {
+		return
+	}
+	size, ok := hexNumber(fields[1])
+	if !ok {
+		return
+	}
+
+	if !isInput {
+		p.outputSection(name, size)
+		return
+	}
+	object := ""
+	if len(fields) > 2 {
+		object = strings.Join(fields[2:], " ")
+	}
+	p.inputSection(name, object, address, size)
+}
+
+func (p *parser) outputSection(name string, size uint64) {
+	p.output, p.current = name, nil
+	if size > 0 {
+		p.sections = append(p.sections, models.LinkSection{Name: name, Size: int64(size)})
+	}
+}
+
+func (p *parser) inputSection(name, object string, address, size uint64) {
+	p.current = &input{name: name, object: object, output: p.output, address: address, size: int64(size)}
+	p.inputs = append(p.inputs, p.current)
+}
+
+// symbolLine records a symbol of the current input section. Linker script
+// assignments share the layout and are skipped.
+func (p *parser) symbolLine(fields []string) {
+	if p.current == nil || len(fields) < 2 {
+		return
+	}
+	address, ok := hexNumber(fields[0])
+	if !ok {
+		return
+	}
+	name := strings.Join(fields[1:], " ")
+	if isAssignment(name) {
+		return
+	}
+	p.current.symbols = append(p.current.symbols, symbol{name: name, address: address})
+}
+
+// lldHeader recognizes the column header of an lld map file and notes
+// where its columns start
+func (p *parser) lldHeader(text string) bool {
+	fields := strings.Fields(text)
+	if len(fields) < 7 || fields[0] != "VMA" {
+		return false
+	}
+	p.outColumn = strings.Index(text, " Out ") + 1
+	p.inColumn = strings.Index(text, " In ") + 1
+	p.symbolColumn = strings.Index(text, " Symbol") + 1
+	return p.outColumn > 0 && p.inColumn > 0 && p.symbolColumn > 0
+}
+
+// lldLine handles a row of an lld map file. The column the name starts in
+// tells output sections, input sections and symbols apart.
+func (p *parser) lldLine(text string) {
+	if len(text) <= p.outColumn {
+		return
+	}
+	fields := strings.Fields(text[:p.outColumn])
+	if len(fields) < 3 {
+		return
+	}
+	address, ok := hexNumber(fields[0])
+	if !ok {
+		return
+	}
+	size, ok := hexNumber(fields[2])
+	if !ok {
+		return
+	}
+
+	rest := text[p.outColumn:]
+	column := p.outColumn + len(rest) - len(strings.TrimLeft(rest, " "))
+	name := strings.TrimSpace(rest)
+	switch {
+	case name == "" || isAssignment(name):
+		return
+	case column < p.inColumn:
+		p.outputSection(name, size)
+	case column < p.symbolColumn:
+		// file.o:(.text.main), or lib.a(member.o):(.text)
+		object, section := name, name
+		if i := strings.LastIndex(name, ":("); i >= 0 && strings.HasSuffix(name, ")") {
+			object, section = name[:i], name[i+2:len(name)-1]
+		}
+		p.inputSection(section, object, address, size)
+	default:
+		if p.current == nil {
+			return
+		}
+		p.current.symbols = append(p.current.symbols, symbol{name: name, address: address, size: int64(size)})
+	}
+}
+
+// result sizes the symbols of every input section and orders the sections
+// and symbols by size
+func (p *parser) result() *models.LinkMap {
+	result := p.discards
+	result.Sections = p.sections
+
+	for _, in := range p.inputs {
+		syms := in.symbols
+		if len(syms) == 0 && in.size > 0 && isSymbolSection(in.name) {
+			// Static data and functions are not listed, but with
+			// -ffunction-sections the input section names them
+			syms = []symbol{{name: in.name, address: in.address, size: in.size}}
+		}
+		sort.SliceStable(syms, func(i, j int) bool { return syms[i].address < syms[j].address })
+		end := in.address + uint64(in.size)
+		for i, sym := range syms {
+			size := sym.size
+			if size == 0 {
+				next := end
+				if i+1 < len(syms) {
+					next = syms[i+1].address
+				}
+				if next > sym.address {
+					size = int64(next - sym.address)
+				}
+			}
+			if size <= 0 {
+				continue
+			}
+			result.Symbols = append(result.Symbols, models.LinkSymbol{
+				Name:    sym.name,
+				Section: in.output,
+				Object:  in.object,
+				Size:    size,
+			})
+		}
+	}
+	result.SymbolsSeen = int64(len(result.Symbols))
+
+	sort.SliceStable(result.Sections, func(i, j int) bool {
+		return result.Sections[i].Size > result.Sections[j].Size
+	})
+	sort.SliceStable(result.Symbols, func(i, j int) bool {
+		if result.Symbols[i].Size != result.Symbols[j].Size {
+			return result.Symbols[i].Size > result.Symbols[j].Size
+		}
+		return result.Symbols[i].Name < result.Symbols[j].Name
+	})
+	return &result
+}
+
+// symbolSectionPrefixes start the input sections that -ffunction-sections
+// and -fdata-sections create for each symbol
+var symbolSectionPrefixes = []string{".text.", ".data.", ".rodata.", ".bss.", ".tdata.", ".tbss."}
+
+func isSymbolSection(name string) bool {
+	for _, prefix := range symbolSectionPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isAssignment reports whether a symbol column holds a linker script
+// statement such as ". = ALIGN (0x8)" or "PROVIDE (end = .)", or a note
+// such as "(size before relaxing)"
+func isAssignment(name string) bool {
+	return strings.Contains(name, "=") || strings.HasPrefix(name, "PROVIDE") || strings.HasPrefix(name, "(") ||
+		strings.HasPrefix(name, "[") || strings.HasPrefix(name, "ASSERT")
+}
+
+// hexNumber parses a hexadecimal number with or without its 0x prefix
+func hexNumber(s string) (uint64, bool) {
+	n, err := strconv.ParseUint(strings.TrimPrefix(s, "0x"), 16, 64)
+	return n, err == nil
+}
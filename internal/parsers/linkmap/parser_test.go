@@ -0,0 +1,122 @@
+package linkmap
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"builds/internal/models"
+)
+
+// gnuMap is an excerpt of a GNU ld -Map file for a program built with
+// -ffunction-sections and --gc-sections
+const gnuMap = `Archive member included to satisfy reference by file (symbol)
+
+/usr/lib/libc.a(printf.o)     main.o (printf)
+
+Discarded input sections
+
+ .text          0x0000000000000000        0x0 main.o
+ .text.unused_function
+                0x0000000000000000       0x12 main.o
+
+Memory Configuration
+
+Name             Origin             Length             Attributes
+*default*        0x0000000000000000 0xffffffffffffffff
+
+Linker script and memory map
+
+LOAD crt1.o
+LOAD main.o
+                0x0000000000400000                PROVIDE (__executable_start = SEGMENT_START ("text-segment", 0x400000))
+.note.gnu.build-id
+                0x0000000000400200       0x24
+ *(.note.gnu.build-id)
+
+.text           0x0000000000401000      0x120
+ *(.text.unlikely .text.*_unlikely .text.unlikely.*)
+ .text          0x0000000000401000       0x40 crt1.o
+                0x0000000000401000                _start
+ .text.main     0x0000000000401040       0x60 main.o
+                0x0000000000401040                main
+ .text.helper   0x00000000004010a0       0x80 main.o
+                0x00000000004010a0                helper
+                0x00000000004010e0                helper2
+
+.data           0x0000000000402000       0x10
+ .data          0x0000000000402000       0x10 main.o
+                0x0000000000402000                table
+                0x0000000000402008                . = ALIGN (0x8)
+
+.bss            0x0000000000402010        0x0
+`
+
+// lldMap is an excerpt of an lld -Map file
+const lldMap = `             VMA              LMA     Size Align Out     In      Symbol
+          2002a8           2002a8       1c     1 .rodata
+          2002a8           2002a8       1c     1         main.o:(.rodata.str1.1)
+          201000           201000       30    16 .text
+          201000           201000       20    16         main.o:(.text.main)
+          201000           201000        0     1                 main
+          201020           201020       10    16         lib.a(util.o):(.text)
+          201020           201020        0     1                 util
+          202000           202000        0     1 __bss_start = .
+`
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want *models.LinkMap
+	}{
+		{
+			name: "GNU ld",
+			data: gnuMap,
+			want: &models.LinkMap{
+				Sections: []models.LinkSection{
+					{Name: ".text", Size: 0x120},
+					{Name: ".note.gnu.build-id", Size: 0x24},
+					{Name: ".data", Size: 0x10},
+				},
+				Symbols: []models.LinkSymbol{
+					{Name: "main", Section: ".text", Object: "main.o", Size: 0x60},
+					{Name: "_start", Section: ".text", Object: "crt1.o", Size: 0x40},
+					{Name: "helper", Section: ".text", Object: "main.o", Size: 0x40},
+					{Name: "helper2", Section: ".text", Object: "main.o", Size: 0x40},
+					{Name: "table", Section: ".data", Object: "main.o", Size: 0x10},
+				},
+				SymbolsSeen:       5,
+				DiscardedSections: 2,
+				DiscardedSize:     0x12,
+			},
+		},
+		{
+			name: "lld",
+			data: lldMap,
+			want: &models.LinkMap{
+				Sections: []models.LinkSection{
+					{Name: ".text", Size: 0x30},
+					{Name: ".rodata", Size: 0x1c},
+				},
+				Symbols: []models.LinkSymbol{
+					{Name: "main", Section: ".text", Object: "main.o", Size: 0x20},
+					{Name: ".rodata.str1.1", Section: ".rodata", Object: "main.o", Size: 0x1c},
+					{Name: "util", Section: ".text", Object: "lib.a(util.o)", Size: 0x10},
+				},
+				SymbolsSeen: 3,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(strings.NewReader(tt.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+// internal/parsers/remarks/files.go
+
+package remarks
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"builds/internal/models"
+)
+
+// recordSuffixes are the extensions used for optimization record files.
+// Plain .yaml files are left out, as build trees hold other YAML.
+var recordSuffixes = []string{".opt.yaml", ".opt.yml"}
+
+// ExpandRecordPaths resolves a record source into a sorted list of files.
+// The source may be a single file, a directory (searched recursively for
+// record files) or a glob pattern.
+func ExpandRecordPaths(source string) ([]string, error) {
+	info, err := os.Stat(source)
+	switch {
+	case err == nil && !info.IsDir():
+		return []string{source}, nil
+	case err == nil && info.IsDir():
+		var paths []string
+		err := filepath.WalkDir(source, func(path string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && isRecordFile(path) {
+				paths = append(paths, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to walk %s: %w", source, err)
+		}
+		sort.Strings(paths)
+		return paths, nil
+	}
+
+	paths, err := filepath.Glob(source)
+	if err != nil {
+		return nil, fmt.Errorf("invalid record pattern %q: %w", source, err)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// ParseFiles parses several record files and merges their remarks in
+// canonical order. Each remark's location records the file it came from, and
+// source locations under root are stored relative to it.
+func ParseFiles(paths []string, root string) ([]models.CompilerRemark, error) {
+	var all []models.CompilerRemark
+	err := EachFile(paths, root, false, func(remark models.CompilerRemark) error {
+		all = append(all, remark)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	SortRemarks(all)
+	return all, nil
+}
+
+// EachFile streams the remarks of several record files to fn in the order
+// given, like ParseFiles without holding them all in memory. In strict mode
+// a malformed remark is an error instead of being skipped.
+func EachFile(paths []string, root string, strict bool, fn func(models.CompilerRemark) error) error {
+	for _, path := range paths {
+		parser := NewParser(path)
+		if root != "" {
+			parser.SetRoot(root)
+		}
+		if strict {
+			parser.SetStrict()
+		}
+		err := parser.Each(func(remark models.CompilerRemark) error {
+			if remark.Location.Artifact == "" {
+				remark.Location.Artifact = path
+			}
+			return fn(remark)
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+func isRecordFile(path string) bool {
+	for _, suffix := range recordSuffixes {
+		if strings.HasSuffix(path, suffix) {
+			return true
+		}
+	}
+	return false
+}
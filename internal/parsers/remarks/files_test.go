@@ -0,0 +1,101 @@
+package remarks
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// recordA and recordB are small records with remarks out of source order
+const recordA = `--- !Missed
+Pass:            loop-vectorize
+Name:            MissedDetails
+DebugLoc:        { File: b.c, Line: 10, Column: 3 }
+Function:        bar
+Args:
+  - String:          loop not vectorized
+--- !Passed
+Pass:            inline
+Name:            Inlined
+DebugLoc:        { File: a.c, Line: 5, Column: 1 }
+Function:        foo
+Args:
+  - String:          inlined
+`
+
+const recordB = `--- !Analysis
+Pass:            licm
+Name:            Hoisted
+DebugLoc:        { File: a.c, Line: 5, Column: 1 }
+Function:        foo
+Args:
+  - String:          hoisted
+--- !Passed
+Pass:            inline
+Name:            Inlined
+DebugLoc:        { File: a.c, Line: 2, Column: 7 }
+Function:        foo
+Args:
+  - String:          inlined
+`
+
+func TestParseRecordDirectory(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.opt.yaml":            recordA,
+		"lto/b.opt.yml":         recordB,
+		"notes.txt":             "not a record",
+		"lto/part.o":            "object",
+		"lto/deeper/c.opt.yaml": recordA,
+		".clang-tidy.yaml":      "Checks: '*'",
+		"ci/pipeline.yml":       "steps: []",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	paths, err := ExpandRecordPaths(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		filepath.Join(dir, "a.opt.yaml"),
+		filepath.Join(dir, "lto/b.opt.yml"),
+		filepath.Join(dir, "lto/deeper/c.opt.yaml"),
+	}
+	if !reflect.DeepEqual(paths, want) {
+		t.Fatalf("expanded to %v, want %v", paths, want)
+	}
+
+	remarks, err := ParseFiles(paths, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(remarks) != 6 {
+		t.Fatalf("parsed %d remarks, want 6", len(remarks))
+	}
+	perFile := make(map[string]int)
+	for _, remark := range remarks {
+		perFile[remark.Location.Artifact]++
+	}
+	wantPerFile := map[string]int{want[0]: 2, want[1]: 2, want[2]: 2}
+	if !reflect.DeepEqual(perFile, wantPerFile) {
+		t.Errorf("remarks per record file %v, want %v", perFile, wantPerFile)
+	}
+
+	// A glob is not recursive
+	globbed, err := ExpandRecordPaths(filepath.Join(dir, "*.opt.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(globbed, want[:1]) {
+		t.Errorf("glob expanded to %v, want %v", globbed, want[:1])
+	}
+}
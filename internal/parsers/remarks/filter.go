@@ -0,0 +1,46 @@
+// internal/parsers/remarks/filter.go
+
+package remarks
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// PassFilter keeps only remarks from passes matching one of its glob
+// patterns, such as loop-vectorize or loop-*. Matching ignores case. A nil
+// or empty filter keeps every remark.
+type PassFilter struct {
+	patterns []string
+}
+
+// NewPassFilter checks the patterns and returns a filter over them
+func NewPassFilter(patterns []string) (*PassFilter, error) {
+	filter := &PassFilter{}
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		if pattern == "" {
+			continue
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid pass pattern %q: %w", pattern, err)
+		}
+		filter.patterns = append(filter.patterns, pattern)
+	}
+	return filter, nil
+}
+
+// Match reports whether remarks from pass are kept
+func (f *PassFilter) Match(pass string) bool {
+	if f == nil || len(f.patterns) == 0 {
+		return true
+	}
+	pass = strings.ToLower(pass)
+	for _, pattern := range f.patterns {
+		if ok, _ := path.Match(pattern, pass); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,42 @@
+package remarks
+
+import "testing"
+
+func TestPassFilter(t *testing.T) {
+	filter, err := NewPassFilter([]string{"vectorization", " Loop-* ", ""})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		pass string
+		want bool
+	}{
+		{"vectorization", true},
+		{"loop-vectorize", true},
+		{"LOOP-UNROLL", true},
+		{"loop", false},
+		{"inline", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := filter.Match(tt.pass); got != tt.want {
+			t.Errorf("Match(%q) = %v, want %v", tt.pass, got, tt.want)
+		}
+	}
+
+	// Without patterns every remark is kept
+	var none *PassFilter
+	empty, _ := NewPassFilter(nil)
+	for _, filter := range []*PassFilter{none, empty} {
+		if !filter.Match("inline") {
+			t.Errorf("%v dropped a remark", filter)
+		}
+	}
+}
+
+func TestNewPassFilterInvalid(t *testing.T) {
+	if _, err := NewPassFilter([]string{"loop-[vectorize"}); err == nil {
+		t.Error("accepted a malformed pattern")
+	}
+}
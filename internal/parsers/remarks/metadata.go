@@ -0,0 +1,93 @@
+// internal/parsers/remarks/metadata.go
+
+package remarks
+
+import (
+	"gopkg.in/yaml.v3"
+
+	"builds/internal/models"
+)
+
+// Keys decoded into the typed remark fields. Anything else a compiler
+// writes is kept in the remark's metadata.
+var (
+	remarkKeys = map[string]bool{
+		"Pass": true, "Name": true, "Function": true,
+		"DebugLoc": true, "Args": true, "Hotness": true,
+	}
+	argKeys = map[string]bool{
+		"String": true, "Callee": true, "Caller": true, "Type": true,
+		"Line": true, "Column": true, "DebugLoc": true,
+		"OtherAccess": true, "ClobberedBy": true,
+	}
+)
+
+// remarkMetadata collects what the typed fields do not hold: the raw tag
+// and name, the hotness when profile data was used, keys the parser does
+// not know and argument values such as Cost, Threshold and
+// VectorizationFactor. Arguments repeated within a remark become lists.
+func remarkMetadata(node *yaml.Node, tag string, remark YamlRemark) models.JSON {
+	metadata := models.JSON{
+		"tag":  tag,
+		"name": remark.Name,
+	}
+	if remark.Hotness != 0 {
+		metadata["hotness"] = remark.Hotness
+	}
+
+	args := make(map[string]interface{})
+	eachPair(node, func(key string, value *yaml.Node) {
+		switch {
+		case key == "Args" && value.Kind == yaml.SequenceNode:
+			for _, arg := range value.Content {
+				eachPair(arg, func(key string, value *yaml.Node) {
+					if !argKeys[key] {
+						addValue(args, key, decodeValue(value))
+					}
+				})
+			}
+		case !remarkKeys[key]:
+			metadata[key] = decodeValue(value)
+		}
+	})
+	if len(args) > 0 {
+		metadata["args"] = args
+	}
+	return metadata
+}
+
+// eachPair calls fn for every key of a mapping node
+func eachPair(node *yaml.Node, fn func(key string, value *yaml.Node)) {
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		fn(node.Content[i].Value, node.Content[i+1])
+	}
+}
+
+// decodeValue converts a node to plain values, keeping scalars as the
+// strings the compiler wrote
+func decodeValue(node *yaml.Node) interface{} {
+	if node.Kind == yaml.ScalarNode {
+		return node.Value
+	}
+	var value interface{}
+	if err := node.Decode(&value); err != nil {
+		return node.Value
+	}
+	return value
+}
+
+func addValue(values map[string]interface{}, key string, value interface{}) {
+	existing, ok := values[key]
+	if !ok {
+		values[key] = value
+		return
+	}
+	if list, ok := existing.([]interface{}); ok {
+		values[key] = append(list, value)
+		return
+	}
+	values[key] = []interface{}{existing, value}
+}
@@ -0,0 +1,69 @@
+package remarks
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"builds/internal/models"
+)
+
+const metadataRecord = `--- !Missed
+Pass:            loop-vectorize
+Name:            MissedDetails
+DebugLoc:        { File: foo.c, Line: 3, Column: 5 }
+Function:        foo
+Hotness:         42
+Discriminator:   2
+Args:
+  - String:          'the cost-model indicates that vectorization is not beneficial'
+  - Cost:            '12'
+  - Threshold:       '8'
+  - Cost:            '15'
+--- !Passed
+Pass:            inline
+Name:            Inlined
+Function:        bar
+`
+
+func TestRemarkMetadata(t *testing.T) {
+	var remarks []models.CompilerRemark
+	err := ParseReader(strings.NewReader(metadataRecord), "", func(remark models.CompilerRemark) error {
+		remarks = append(remarks, remark)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(remarks) != 2 {
+		t.Fatalf("parsed %d remarks, want 2", len(remarks))
+	}
+
+	metadata := remarks[0].Metadata
+	if len(metadata) == 0 {
+		t.Fatal("no metadata")
+	}
+	if metadata["tag"] != "Missed" || metadata["name"] != "MissedDetails" {
+		t.Errorf("tag %v, name %v", metadata["tag"], metadata["name"])
+	}
+	if metadata["hotness"] != int32(42) {
+		t.Errorf("hotness %v, want 42", metadata["hotness"])
+	}
+	// Unknown keys are kept as written
+	if metadata["Discriminator"] != "2" {
+		t.Errorf("Discriminator = %v, want 2", metadata["Discriminator"])
+	}
+	want := map[string]interface{}{
+		"Cost":      []interface{}{"12", "15"},
+		"Threshold": "8",
+	}
+	if args := metadata["args"]; !reflect.DeepEqual(args, want) {
+		t.Errorf("args = %v, want %v", args, want)
+	}
+
+	// A remark with nothing extra still records its tag and name
+	want = map[string]interface{}{"tag": "Passed", "name": "Inlined"}
+	if got := map[string]interface{}(remarks[1].Metadata); !reflect.DeepEqual(got, want) {
+		t.Errorf("metadata = %v, want %v", got, want)
+	}
+}
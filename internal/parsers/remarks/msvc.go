@@ -0,0 +1,103 @@
+// internal/parsers/remarks/msvc.go
+
+package remarks
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"builds/internal/models"
+)
+
+var (
+	// C:\src\foo.cpp(12) : info C5002: loop not vectorized due to reason '1200'
+	msvcRemarkPattern   = regexp.MustCompile(`^(.+?)\((\d+)\)\s*:\s*info (C\d+):\s*(.*)$`)
+	msvcFunctionPattern = regexp.MustCompile(`^--- Analyzing function:\s*(.+)$`)
+	msvcReasonPattern   = regexp.MustCompile(`reason '(\d+)'`)
+)
+
+// msvcPasses maps MSVC report codes to the pass that produced them
+var msvcPasses = map[string]struct {
+	pass, name, status string
+}{
+	"C5001": {"loop-vectorize", "Vectorized", "passed"},
+	"C5002": {"loop-vectorize", "NotVectorized", "missed"},
+	"C5011": {"loop-parallelize", "Parallelized", "passed"},
+	"C5012": {"loop-parallelize", "NotParallelized", "missed"},
+}
+
+// ParseMSVCReport reads the vectorizer and parallelizer reports printed by
+// cl.exe with /Qvec-report:2 or /Qpar-report:2. Other compiler output is
+// ignored. Locations under root are stored relative to it.
+func ParseMSVCReport(r io.Reader, root string) ([]models.CompilerRemark, error) {
+	p := &Parser{}
+	if root != "" {
+		p.SetRoot(root)
+	}
+
+	var remarks []models.CompilerRemark
+	var function string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if matches := msvcFunctionPattern.FindStringSubmatch(line); matches != nil {
+			function = matches[1]
+			continue
+		}
+
+		matches := msvcRemarkPattern.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		kind, ok := msvcPasses[matches[3]]
+		if !ok {
+			continue
+		}
+		lineNo, _ := strconv.ParseInt(matches[2], 10, 32)
+
+		remark := models.CompilerRemark{
+			Type:      kind.status,
+			Pass:      kind.pass,
+			Status:    kind.status,
+			Name:      kind.name,
+			Message:   fmt.Sprintf("%s: %s", matches[3], matches[4]),
+			Function:  function,
+			Timestamp: time.Now(),
+			Location: models.Location{
+				File:     matches[1],
+				Line:     int32(lineNo),
+				Function: function,
+			},
+			Args: models.RemarkArgs{
+				Strings: []string{matches[4]},
+			},
+		}
+		if reason := msvcReasonPattern.FindStringSubmatch(matches[4]); reason != nil {
+			remark.Args.Reason = reason[1]
+		}
+
+		p.relativize(&remark)
+		remarks = append(remarks, remark)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read compiler output: %w", err)
+	}
+	SortRemarks(remarks)
+	return remarks, nil
+}
+
+// IsMSVC reports whether a compiler command is the MSVC driver cl.exe,
+// which prints reports instead of writing optimization records
+func IsMSVC(compiler string) bool {
+	base := strings.ToLower(compiler)
+	if i := strings.LastIndexAny(base, `/\`); i >= 0 {
+		base = base[i+1:]
+	}
+	return strings.TrimSuffix(base, ".exe") == "cl"
+}
@@ -0,0 +1,77 @@
+package remarks
+
+import (
+	"strings"
+	"testing"
+)
+
+// Output of cl /O2 /Qvec-report:2 /Qpar-report:2 /Qpar, as captured
+const msvcReport = `Microsoft (R) C/C++ Optimizing Compiler Version 19.38.33135 for x64
+Copyright (C) Microsoft Corporation.  All rights reserved.
+
+saxpy.cpp
+--- Analyzing function: void __cdecl saxpy(float,float const * __ptr64,float * __ptr64,int)
+C:\src\saxpy.cpp(8) : info C5001: loop vectorized
+C:\src\saxpy.cpp(8) : info C5012: loop not parallelized due to reason '1008'
+
+--- Analyzing function: void __cdecl prefix_sum(int * __ptr64,int)
+C:\src\saxpy.cpp(15) : info C5002: loop not vectorized due to reason '1200'
+C:\src\saxpy.cpp(16) : warning C4244: 'argument': conversion from 'double' to 'float', possible loss of data
+C:\src\saxpy.cpp(22) : info C5999: some future report
+`
+
+func TestParseMSVCReport(t *testing.T) {
+	// Remarks come out in canonical order, by location and then pass
+	remarks, err := ParseMSVCReport(strings.NewReader(msvcReport), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		line               int32
+		pass, name, status string
+		function, reason   string
+	}{
+		{8, "loop-parallelize", "NotParallelized", "missed", "void __cdecl saxpy(float,float const * __ptr64,float * __ptr64,int)", "1008"},
+		{8, "loop-vectorize", "Vectorized", "passed", "void __cdecl saxpy(float,float const * __ptr64,float * __ptr64,int)", ""},
+		{15, "loop-vectorize", "NotVectorized", "missed", "void __cdecl prefix_sum(int * __ptr64,int)", "1200"},
+	}
+	if len(remarks) != len(want) {
+		t.Fatalf("parsed %d remarks, want %d: %+v", len(remarks), len(want), remarks)
+	}
+	for i, w := range want {
+		r := remarks[i]
+		if r.Location.File != `C:\src\saxpy.cpp` || r.Location.Line != w.line {
+			t.Errorf("remark %d at %s:%d, want line %d", i, r.Location.File, r.Location.Line, w.line)
+		}
+		if r.Pass != w.pass || r.Name != w.name || r.Status != w.status {
+			t.Errorf("remark %d is %s/%s %s, want %s/%s %s", i, r.Pass, r.Name, r.Status, w.pass, w.name, w.status)
+		}
+		if r.Function != w.function || r.Args.Reason != w.reason {
+			t.Errorf("remark %d in %q with reason %q, want %q and %q", i, r.Function, r.Args.Reason, w.function, w.reason)
+		}
+	}
+	if !strings.HasPrefix(remarks[2].Message, "C5002: loop not vectorized") {
+		t.Errorf("message %q", remarks[2].Message)
+	}
+}
+
+func TestIsMSVC(t *testing.T) {
+	tests := []struct {
+		compiler string
+		want     bool
+	}{
+		{"cl", true},
+		{"CL.EXE", true},
+		{`C:\Program Files\MSVC\bin\Hostx64\x64\cl.exe`, true},
+		{"/opt/msvc/bin/cl", true},
+		{"clang-cl", false},
+		{"clang", false},
+		{"cl-wrapper", false},
+	}
+	for _, tt := range tests {
+		if got := IsMSVC(tt.compiler); got != tt.want {
+			t.Errorf("IsMSVC(%q) = %v, want %v", tt.compiler, got, tt.want)
+		}
+	}
+}
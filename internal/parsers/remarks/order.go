@@ -0,0 +1,31 @@
+// internal/parsers/remarks/order.go
+
+package remarks
+
+import (
+	"sort"
+
+	"builds/internal/models"
+)
+
+// SortRemarks puts remarks in canonical order: by source file, line,
+// column, pass and name. The sort is stable, so repeated remarks keep their
+// relative order and the stable IDs derived from it.
+func SortRemarks(remarks []models.CompilerRemark) {
+	sort.SliceStable(remarks, func(i, j int) bool {
+		a, b := remarks[i], remarks[j]
+		if a.Location.File != b.Location.File {
+			return a.Location.File < b.Location.File
+		}
+		if a.Location.Line != b.Location.Line {
+			return a.Location.Line < b.Location.Line
+		}
+		if a.Location.Column != b.Location.Column {
+			return a.Location.Column < b.Location.Column
+		}
+		if a.Pass != b.Pass {
+			return a.Pass < b.Pass
+		}
+		return a.Name < b.Name
+	})
+}
@@ -0,0 +1,49 @@
+package remarks
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseFilesOrder(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.opt.yaml")
+	b := filepath.Join(dir, "b.opt.yaml")
+	if err := os.WriteFile(a, []byte(recordA), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(b, []byte(recordB), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := ParseFiles([]string{a, b}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, remark := range first {
+		got = append(got, remark.Location.File+":"+remark.Pass)
+	}
+	want := []string{"a.c:inline", "a.c:inline", "a.c:licm", "b.c:loop-vectorize"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("order %v, want %v", got, want)
+	}
+
+	// Merging in the other order or parsing again changes nothing
+	for _, paths := range [][]string{{a, b}, {b, a}} {
+		again, err := ParseFiles(paths, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range again {
+			again[i].Timestamp = first[i].Timestamp
+			again[i].Location.Artifact = first[i].Location.Artifact
+		}
+		if !reflect.DeepEqual(again, first) {
+			t.Errorf("parsing %v gave a different order", paths)
+		}
+	}
+}
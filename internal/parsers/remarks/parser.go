@@ -3,7 +3,7 @@
 package remarks
 
 import (
-	"bytes"
+	"bufio"
 	"fmt"
 	"io"
 	"os"
@@ -17,6 +17,8 @@ import (
 
 type Parser struct {
 	filepath string
+	root     string
+	strict   bool
 }
 
 type YamlRemark struct {
@@ -37,13 +39,20 @@ type YamlLocation struct {
 }
 
 type YamlArg struct {
-	String      string        `yaml:"String,omitempty"`
-	Callee      string        `yaml:"Callee,omitempty"`
-	Caller      string        `yaml:"Caller,omitempty"`
-	Type        string        `yaml:"Type,omitempty"`
-	Line        string        `yaml:"Line,omitempty"`
-	Column      string        `yaml:"Column,omitempty"`
-	DebugLoc    *YamlLocation `yaml:"DebugLoc,omitempty"`
+	String   string        `yaml:"String,omitempty"`
+	Callee   string        `yaml:"Callee,omitempty"`
+	Caller   string        `yaml:"Caller,omitempty"`
+	Type     string        `yaml:"Type,omitempty"`
+	Line     string        `yaml:"Line,omitempty"`
+	Column   string        `yaml:"Column,omitempty"`
+	DebugLoc *YamlLocation `yaml:"DebugLoc,omitempty"`
+
+	// Register allocator counts, as in the regalloc SpillReloadCopies remarks
+	NumSpills        string `yaml:"NumSpills,omitempty"`
+	NumReloads       string `yaml:"NumReloads,omitempty"`
+	NumFoldedSpills  string `yaml:"NumFoldedSpills,omitempty"`
+	NumFoldedReloads string `yaml:"NumFoldedReloads,omitempty"`
+
 	OtherAccess *struct {
 		Type     string        `yaml:"type,omitempty"`
 		DebugLoc *YamlLocation `yaml:"DebugLoc,omitempty"`
@@ -58,14 +67,55 @@ func NewParser(filepath string) *Parser {
 	return &Parser{filepath: filepath}
 }
 
+// SetStrict makes the parser fail on remark documents it cannot read, rather
+// than skipping them
+func (p *Parser) SetStrict() {
+	p.strict = true
+}
+
+// readBufferSize is the read-ahead used when streaming record files
+const readBufferSize = 64 * 1024
+
+// Parse reads all remarks from the record file, in canonical order
 func (p *Parser) Parse() ([]models.CompilerRemark, error) {
-	data, err := os.ReadFile(p.filepath)
+	var remarks []models.CompilerRemark
+	err := p.Each(func(remark models.CompilerRemark) error {
+		remarks = append(remarks, remark)
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to read file: %w", err)
+		return nil, err
 	}
+	SortRemarks(remarks)
+	return remarks, nil
+}
 
-	decoder := yaml.NewDecoder(bytes.NewReader(data))
-	var remarks []models.CompilerRemark
+// Each streams remarks from the record file to fn one document at a time,
+// so memory use is bounded by the largest remark rather than the file size.
+// Iteration stops at the first error returned by fn.
+func (p *Parser) Each(fn func(models.CompilerRemark) error) error {
+	file, err := os.Open(p.filepath)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+	defer file.Close()
+
+	return p.decode(bufio.NewReaderSize(file, readBufferSize), fn)
+}
+
+// ParseReader streams remarks from r, which must contain whole YAML
+// documents, such as a chunk of a record file that is still being written.
+// Locations under root are stored relative to it.
+func ParseReader(r io.Reader, root string, fn func(models.CompilerRemark) error) error {
+	p := &Parser{}
+	if root != "" {
+		p.SetRoot(root)
+	}
+	return p.decode(r, fn)
+}
+
+func (p *Parser) decode(r io.Reader, fn func(models.CompilerRemark) error) error {
+	decoder := yaml.NewDecoder(r)
 
 	for {
 		var node yaml.Node
@@ -74,7 +124,8 @@ func (p *Parser) Parse() ([]models.CompilerRemark, error) {
 			break
 		}
 		if err != nil {
-			continue
+			// The decoder cannot resynchronise after a syntax error
+			return fmt.Errorf("failed to decode remark: %w", err)
 		}
 
 		if node.Kind != yaml.DocumentNode || len(node.Content) == 0 {
@@ -84,12 +135,18 @@ func (p *Parser) Parse() ([]models.CompilerRemark, error) {
 		root := node.Content[0]
 		var yamlRemark YamlRemark
 		if err := root.Decode(&yamlRemark); err != nil {
+			if p.strict {
+				return fmt.Errorf("malformed remark at line %d: %w", root.Line, err)
+			}
 			continue
 		}
 
 		// Extract the type from the tag (e.g., "!Passed" -> "Passed")
 		remarkType := strings.TrimPrefix(root.Tag, "!")
 		if remarkType == "" {
+			if p.strict {
+				return fmt.Errorf("remark at line %d has no type tag", root.Line)
+			}
 			// Skip if no type tag found
 			continue
 		}
@@ -97,10 +154,12 @@ func (p *Parser) Parse() ([]models.CompilerRemark, error) {
 		remark := models.CompilerRemark{
 			Type:      strings.ToLower(remarkType), // Convert to lowercase for consistency
 			Pass:      yamlRemark.Pass,
+			Name:      yamlRemark.Name,
 			Message:   p.buildMessage(yamlRemark),
 			Function:  yamlRemark.Function,
 			Timestamp: time.Now(),
 			Hotness:   yamlRemark.Hotness,
+			Metadata:  remarkMetadata(root, remarkType, yamlRemark),
 		}
 
 		// Set status based on type
@@ -189,10 +248,19 @@ func (p *Parser) Parse() ([]models.CompilerRemark, error) {
 			}
 		}
 
-		remarks = append(remarks, remark)
+		p.relativize(&remark)
+		if stores, loads, ok := spillArgs(yamlRemark.Args); ok {
+			addSpills(&remark, stores, loads)
+		} else {
+			extractSpills(&remark)
+		}
+
+		if err := fn(remark); err != nil {
+			return err
+		}
 	}
 
-	return remarks, nil
+	return nil
 }
 
 func (p *Parser) buildMessage(remark YamlRemark) string {
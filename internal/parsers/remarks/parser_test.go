@@ -0,0 +1,96 @@
+package remarks
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"builds/internal/models"
+)
+
+func TestEachBoundedMemory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("parses a large record")
+	}
+
+	const n = 20000
+	path := filepath.Join(t.TempDir(), "large.opt.yaml")
+	if err := os.WriteFile(path, []byte(syntheticRecord(n)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Sample the live heap while parsing; holding the file in memory would
+	// grow it by the file size
+	liveHeap := func() uint64 {
+		runtime.GC()
+		var stats runtime.MemStats
+		runtime.ReadMemStats(&stats)
+		return stats.HeapAlloc
+	}
+	base := liveHeap()
+	var peak uint64
+	count := 0
+	err = NewParser(path).Each(func(models.CompilerRemark) error {
+		count++
+		if count%2000 == 0 {
+			peak = max(peak, liveHeap())
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != n {
+		t.Fatalf("parsed %d remarks, want %d", count, n)
+	}
+
+	growth := int64(peak) - int64(base)
+	if limit := info.Size() / 4; growth > limit {
+		t.Errorf("live heap grew by %d bytes parsing a %d byte record, want at most %d", growth, info.Size(), limit)
+	}
+}
+
+func TestStrict(t *testing.T) {
+	// The second remark has a line that is not a number, as written by a
+	// compiler whose record format we do not know
+	record := recordA + `--- !Missed
+Pass:            gvn
+Name:            LoadClobbered
+DebugLoc:        { File: c.c, Line: ten, Column: 1 }
+Function:        baz
+`
+	path := filepath.Join(t.TempDir(), "foo.opt.yaml")
+	if err := os.WriteFile(path, []byte(record), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		strict    bool
+		wantErr   bool
+		wantCount int
+	}{
+		{"lenient skips the malformed remark", false, false, 2},
+		{"strict fails on it", true, true, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count := 0
+			err := EachFile([]string{path}, "", tt.strict, func(models.CompilerRemark) error {
+				count++
+				return nil
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error %v, want error %v", err, tt.wantErr)
+			}
+			if count != tt.wantCount {
+				t.Errorf("parsed %d remarks, want %d", count, tt.wantCount)
+			}
+		})
+	}
+}
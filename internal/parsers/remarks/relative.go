@@ -0,0 +1,53 @@
+// internal/parsers/remarks/relative.go
+
+package remarks
+
+import (
+	"path/filepath"
+	"strings"
+
+	"builds/internal/models"
+)
+
+// RelativePath rewrites an absolute path under root as a slash-separated
+// path relative to root. Paths outside root, relative paths and an empty
+// root leave the path unchanged.
+func RelativePath(root, path string) string {
+	if root == "" || path == "" || !filepath.IsAbs(path) {
+		return path
+	}
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
+
+// SetRoot makes the parser store locations relative to root
+func (p *Parser) SetRoot(root string) {
+	p.root = filepath.Clean(root)
+}
+
+// relativize rewrites every location of a remark relative to the root
+func (p *Parser) relativize(remark *models.CompilerRemark) {
+	if p.root == "" {
+		return
+	}
+	remark.Location.File = RelativePath(p.root, remark.Location.File)
+	for _, loc := range []*models.Location{
+		remark.Args.DebugLoc,
+		accessLocation(remark.Args.OtherAccess),
+		accessLocation(remark.Args.ClobberedBy),
+	} {
+		if loc != nil {
+			loc.File = RelativePath(p.root, loc.File)
+		}
+	}
+}
+
+func accessLocation(access *models.RemarkAccess) *models.Location {
+	if access == nil {
+		return nil
+	}
+	return access.DebugLoc
+}
@@ -0,0 +1,83 @@
+package remarks
+
+import (
+	"strings"
+	"testing"
+
+	"builds/internal/models"
+)
+
+func TestRelativePath(t *testing.T) {
+	tests := []struct {
+		name string
+		root string
+		path string
+		want string
+	}{
+		{"under the root", "/home/ci/work", "/home/ci/work/src/foo.cpp", "src/foo.cpp"},
+		{"root with a trailing slash", "/home/ci/work/", "/home/ci/work/foo.cpp", "foo.cpp"},
+		{"outside the root", "/home/ci/work", "/usr/include/stdio.h", "/usr/include/stdio.h"},
+		{"sibling sharing a prefix", "/home/ci/work", "/home/ci/workspace/foo.cpp", "/home/ci/workspace/foo.cpp"},
+		{"already relative", "/home/ci/work", "src/foo.cpp", "src/foo.cpp"},
+		{"no root", "", "/home/ci/work/src/foo.cpp", "/home/ci/work/src/foo.cpp"},
+		{"no path", "/home/ci/work", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RelativePath(tt.root, tt.path); got != tt.want {
+				t.Errorf("RelativePath(%q, %q) = %q, want %q", tt.root, tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseReaderRelativizes(t *testing.T) {
+	const input = `--- !Missed
+Pass:            loop-vectorize
+Name:            MissedDetails
+DebugLoc:        { File: '/home/ci/work/src/foo.cpp', Line: 3, Column: 5 }
+Function:        foo
+Args:
+  - String:          'loop not vectorized'
+  - DebugLoc:        { File: '/home/ci/work/src/foo.h', Line: 7, Column: 1 }
+--- !Missed
+Pass:            inline
+Name:            NoDefinition
+DebugLoc:        { File: '/usr/include/stdio.h', Line: 10, Column: 1 }
+Function:        bar
+`
+
+	tests := []struct {
+		name     string
+		root     string
+		want     []string
+		wantArgs string
+	}{
+		{"matching root", "/home/ci/work", []string{"src/foo.cpp", "/usr/include/stdio.h"}, "src/foo.h"},
+		{"other root", "/home/dev/checkout", []string{"/home/ci/work/src/foo.cpp", "/usr/include/stdio.h"}, "/home/ci/work/src/foo.h"},
+		{"no root", "", []string{"/home/ci/work/src/foo.cpp", "/usr/include/stdio.h"}, "/home/ci/work/src/foo.h"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var remarks []models.CompilerRemark
+			err := ParseReader(strings.NewReader(input), tt.root, func(remark models.CompilerRemark) error {
+				remarks = append(remarks, remark)
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(remarks) != len(tt.want) {
+				t.Fatalf("parsed %d remarks, want %d", len(remarks), len(tt.want))
+			}
+			for i, remark := range remarks {
+				if remark.Location.File != tt.want[i] {
+					t.Errorf("remark %d in %q, want %q", i, remark.Location.File, tt.want[i])
+				}
+			}
+			if got := remarks[0].Args.DebugLoc; got == nil || got.File != tt.wantArgs {
+				t.Errorf("argument location %+v, want %q", got, tt.wantArgs)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+// internal/parsers/remarks/spills.go
+
+package remarks
+
+import (
+	"regexp"
+	"strconv"
+
+	"builds/internal/models"
+)
+
+var (
+	spillStorePattern = regexp.MustCompile(`(\d+) (?:spill stores|spills)\b`)
+	spillLoadPattern  = regexp.MustCompile(`(\d+) (?:spill loads|reloads)\b`)
+)
+
+// spillArgs sums the register allocator's spill and reload counts in a
+// remark's arguments. Folded spills and reloads are counted with the others,
+// as they still access the stack.
+func spillArgs(args []YamlArg) (stores, loads int64, ok bool) {
+	for _, arg := range args {
+		for _, count := range []struct {
+			value string
+			total *int64
+		}{
+			{arg.NumSpills, &stores},
+			{arg.NumFoldedSpills, &stores},
+			{arg.NumReloads, &loads},
+			{arg.NumFoldedReloads, &loads},
+		} {
+			if count.value == "" {
+				continue
+			}
+			n, err := strconv.ParseInt(count.value, 10, 64)
+			if err != nil {
+				continue
+			}
+			*count.total += n
+			ok = true
+		}
+	}
+	return stores, loads, ok
+}
+
+// extractSpills records register spill counts mentioned in the text of a
+// remark without spill arguments, such as "3 spill stores generated" or
+// "2 spill loads", as kernel metrics
+func extractSpills(remark *models.CompilerRemark) {
+	stores := matchCount(spillStorePattern, remark.Message)
+	loads := matchCount(spillLoadPattern, remark.Message)
+	addSpills(remark, stores, loads)
+}
+
+// addSpills adds spill counts to a remark's kernel metrics
+func addSpills(remark *models.CompilerRemark, stores, loads int64) {
+	if stores == 0 && loads == 0 {
+		return
+	}
+
+	if remark.KernelInfo == nil {
+		remark.KernelInfo = &models.KernelInfo{}
+	}
+	if remark.KernelInfo.Metrics == nil {
+		remark.KernelInfo.Metrics = make(map[string]int64)
+	}
+	remark.KernelInfo.Metrics[models.MetricSpillStores] += stores
+	remark.KernelInfo.Metrics[models.MetricSpillLoads] += loads
+}
+
+// matchCount sums the counts captured by pattern in s
+func matchCount(pattern *regexp.Regexp, s string) int64 {
+	var total int64
+	for _, match := range pattern.FindAllStringSubmatch(s, -1) {
+		n, err := strconv.ParseInt(match[1], 10, 64)
+		if err == nil {
+			total += n
+		}
+	}
+	return total
+}
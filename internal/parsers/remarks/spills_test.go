@@ -0,0 +1,106 @@
+package remarks
+
+import (
+	"strings"
+	"testing"
+
+	"builds/internal/models"
+)
+
+func TestParseSpills(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		wantStores int64
+		wantLoads  int64
+	}{
+		{
+			name: "regalloc arguments",
+			input: `--- !Missed
+Pass:            regalloc
+Name:            SpillReloadCopies
+Function:        kernel
+Args:
+  - NumSpills:       '3'
+  - String:          ' spills '
+  - TotalSpillsCost: '1.500000e+00'
+  - String:          ' total spills cost '
+  - NumReloads:      '2'
+  - String:          ' reloads '
+  - NumFoldedReloads: '1'
+  - String:          ' folded reloads '
+  - String:          generated in function
+`,
+			wantStores: 3,
+			wantLoads:  3,
+		},
+		{
+			name: "text only",
+			input: `--- !Analysis
+Pass:            asm-printer
+Name:            SpillCount
+Function:        kernel
+Args:
+  - String:          '4 spill stores generated, 5 spill loads'
+`,
+			wantStores: 4,
+			wantLoads:  5,
+		},
+		{
+			name: "arguments take precedence over text",
+			input: `--- !Missed
+Pass:            regalloc
+Name:            LoopSpillReloadCopies
+Function:        kernel
+Args:
+  - NumSpills:       '1'
+  - String:          ' spills 9 reloads '
+`,
+			wantStores: 1,
+			wantLoads:  0,
+		},
+		{
+			name: "no spills",
+			input: `--- !Passed
+Pass:            inline
+Name:            Inlined
+Function:        kernel
+Args:
+  - String:          inlined
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var remarks []models.CompilerRemark
+			err := ParseReader(strings.NewReader(tt.input), "", func(remark models.CompilerRemark) error {
+				remarks = append(remarks, remark)
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(remarks) != 1 {
+				t.Fatalf("parsed %d remarks, want 1", len(remarks))
+			}
+
+			info := remarks[0].KernelInfo
+			if tt.wantStores == 0 && tt.wantLoads == 0 {
+				if info != nil {
+					t.Errorf("KernelInfo = %+v, want none", info)
+				}
+				return
+			}
+			if info == nil {
+				t.Fatal("no KernelInfo")
+			}
+			if got := info.Metrics[models.MetricSpillStores]; got != tt.wantStores {
+				t.Errorf("spill stores = %d, want %d", got, tt.wantStores)
+			}
+			if got := info.Metrics[models.MetricSpillLoads]; got != tt.wantLoads {
+				t.Errorf("spill loads = %d, want %d", got, tt.wantLoads)
+			}
+		})
+	}
+}
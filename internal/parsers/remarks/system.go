@@ -0,0 +1,63 @@
+// internal/parsers/remarks/system.go
+
+package remarks
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// DefaultSystemDirs are include directories treated as system headers even
+// when the compiler does not list them, such as the C library headers and
+// the toolchains of common installs
+var DefaultSystemDirs = []string{
+	"/usr/include",
+	"/usr/lib/gcc",
+	"/usr/lib/llvm",
+	"/usr/lib/clang",
+	"/opt/rocm/include",
+	"/usr/local/cuda/include",
+	"/Library/Developer/CommandLineTools",
+	"/Applications/Xcode.app",
+}
+
+// SystemHeaders recognises locations in system include directories, which
+// users cannot act on
+type SystemHeaders struct {
+	dirs []string
+}
+
+// NewSystemHeaders matches locations under the given directories and
+// DefaultSystemDirs. Directories that are not absolute are ignored.
+func NewSystemHeaders(dirs []string) *SystemHeaders {
+	s := &SystemHeaders{}
+	seen := make(map[string]bool)
+	for _, dir := range append(append([]string{}, DefaultSystemDirs...), dirs...) {
+		if !filepath.IsAbs(dir) {
+			continue
+		}
+		dir = filepath.Clean(dir)
+		if !seen[dir] {
+			seen[dir] = true
+			s.dirs = append(s.dirs, dir)
+		}
+	}
+	return s
+}
+
+// Contains reports whether path is in a system include directory. Paths
+// are cleaned first, as compilers record headers such as
+// /usr/bin/../lib/gcc/x86_64-linux-gnu/13/../../../../include/c++/13/vector.
+// Relative paths belong to the project and never match.
+func (s *SystemHeaders) Contains(path string) bool {
+	if s == nil || !filepath.IsAbs(path) {
+		return false
+	}
+	path = filepath.Clean(path)
+	for _, dir := range s.dirs {
+		if path == dir || strings.HasPrefix(path, dir+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
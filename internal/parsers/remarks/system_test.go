@@ -0,0 +1,32 @@
+package remarks
+
+import "testing"
+
+func TestSystemHeaders(t *testing.T) {
+	system := NewSystemHeaders([]string{"/opt/toolchain/include", "relative/include"})
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/usr/include/stdio.h", true},
+		{"/usr/bin/../lib/gcc/x86_64-linux-gnu/13/../../../../include/c++/13/vector", true},
+		{"/opt/toolchain/include/foo.h", true},
+		{"/opt/toolchain/include", true},
+		{"/opt/toolchain/includes/foo.h", false},
+		{"/home/me/project/foo.c", false},
+		{"usr/include/stdio.h", false},
+		{"relative/include/foo.h", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := system.Contains(tt.path); got != tt.want {
+			t.Errorf("Contains(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+
+	var none *SystemHeaders
+	if none.Contains("/usr/include/stdio.h") {
+		t.Error("a nil filter matched a system header")
+	}
+}
@@ -0,0 +1,53 @@
+// internal/parsers/timereport/parser.go
+
+package timereport
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// GCC's -ftime-report ends each row with the memory allocated during it:
+//
+//	phase opt and generate             :   0.53 ( 85%)   0.05 ( 50%)   0.58 ( 81%)  9754k ( 67%)
+var phasePattern = regexp.MustCompile(`^\s*phase (.+?)\s*:.*?(\d+(?:\.\d+)?)([kMG])\s*\(\s*\d+%\)\s*$`)
+
+var memoryUnits = map[string]float64{
+	"k": 1 << 10,
+	"M": 1 << 20,
+	"G": 1 << 30,
+}
+
+// ParsePhaseMemory reads the memory GCC's garbage collector allocated in
+// each compiler phase from -ftime-report output. This is allocation, not
+// the resident high-water mark. Lines that are not phase rows are ignored, so the
+// full compiler stderr can be passed in. A phase reported more than once,
+// as happens for several translation units, keeps its largest value.
+func ParsePhaseMemory(r io.Reader) (map[string]int64, error) {
+	phases := make(map[string]int64)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		matches := phasePattern.FindStringSubmatch(scanner.Text())
+		if matches == nil {
+			continue
+		}
+		value, err := strconv.ParseFloat(matches[2], 64)
+		if err != nil {
+			continue
+		}
+
+		name := strings.Join(strings.Fields(matches[1]), " ")
+		bytes := int64(value * memoryUnits[matches[3]])
+		if bytes > phases[name] {
+			phases[name] = bytes
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return phases, nil
+}
@@ -0,0 +1,56 @@
+package timereport
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Output of gcc -O2 -ftime-report for two translation units, with a
+// warning in between as it appears on stderr
+const timeReport = `
+Time variable                                   usr           sys          wall           GGC
+ phase setup                        :   0.00 (  0%)   0.00 (  0%)   0.01 (  2%)  1585k ( 10%)
+ phase parsing                      :   0.04 ( 14%)   0.03 ( 60%)   0.07 ( 23%)  5021k ( 31%)
+ phase lang. deferred               :   0.01 (  4%)   0.00 (  0%)   0.01 (  3%)   616k (  4%)
+ phase opt and generate             :   0.22 ( 79%)   0.02 ( 40%)   0.24 ( 72%)  9754k ( 60%)
+ phase finalize                     :   0.00 (  0%)   0.00 (  0%)   0.00 (  0%)     0  (  0%)
+ |name lookup                       :   0.01 (  4%)   0.00 (  0%)   0.01 (  3%)   233k (  1%)
+ callgraph construction             :   0.00 (  0%)   0.00 (  0%)   0.01 (  3%)   512k (  3%)
+ TOTAL                              :   0.28          0.05          0.33           16M
+bar.c: In function 'bar':
+bar.c:3:10: warning: unused variable 'x' [-Wunused-variable]
+
+Time variable                                   usr           sys          wall           GGC
+ phase setup                        :   0.00 (  0%)   0.00 (  0%)   0.00 (  0%)  1585k (  1%)
+ phase parsing                      :   0.02 (  3%)   0.01 ( 20%)   0.03 (  4%)  2048k (  2%)
+ phase opt and generate             :   0.61 ( 95%)   0.04 ( 80%)   0.65 ( 94%)   1.5G ( 96%)
+ TOTAL                              :   0.64          0.05          0.69         1.6G
+`
+
+func TestParsePhaseMemory(t *testing.T) {
+	got, err := ParsePhaseMemory(strings.NewReader(timeReport))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]int64{
+		"setup":          1585 << 10,
+		"parsing":        5021 << 10,
+		"lang. deferred": 616 << 10,
+		// The largest of the two translation units
+		"opt and generate": 3 << 29,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestParsePhaseMemoryWithoutReport(t *testing.T) {
+	got, err := ParsePhaseMemory(strings.NewReader("foo.c:1:1: error: expected ';'\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("got %v from output without a time report", got)
+	}
+}
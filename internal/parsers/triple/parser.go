@@ -0,0 +1,103 @@
+// internal/parsers/triple/parser.go
+
+package triple
+
+import (
+	"strings"
+
+	"builds/internal/models"
+)
+
+// archAliases maps the spellings toolchains use for an architecture onto
+// the name LLVM gives it, so the same machine groups together whatever
+// compiler reported it
+var archAliases = map[string]string{
+	"amd64":   "x86_64",
+	"x64":     "x86_64",
+	"arm64":   "aarch64",
+	"x86":     "i386",
+	"i486":    "i386",
+	"i586":    "i386",
+	"i686":    "i386",
+	"ppc":     "powerpc",
+	"ppc64":   "powerpc64",
+	"ppc64le": "powerpc64le",
+}
+
+// vendors are the vendor components in common use. none is left out: in
+// arm-none-eabi it is the OS.
+var vendors = map[string]bool{
+	"unknown": true,
+	"pc":      true,
+	"apple":   true,
+	"nvidia":  true,
+	"amd":     true,
+	"ibm":     true,
+	"redhat":  true,
+	"suse":    true,
+	"w64":     true,
+	"scei":    true,
+}
+
+// osPrefixes are the OS components in common use. Some carry a version,
+// such as darwin23.1.0, so they are matched by prefix.
+var osPrefixes = []string{
+	"linux", "darwin", "macos", "ios", "tvos", "watchos", "windows", "win32",
+	"mingw", "cygwin", "freebsd", "netbsd", "openbsd", "dragonfly", "solaris",
+	"aix", "haiku", "fuchsia", "none", "cuda", "amdhsa", "amdpal", "mesa3d",
+	"wasi", "emscripten", "uefi", "elf",
+}
+
+// Parse splits a target triple such as x86_64-pc-linux-gnu into its
+// architecture, vendor, OS and ABI. Triples may leave out the vendor
+// (x86_64-linux-gnu, wasm32-wasi) or the ABI (nvptx64-nvidia-cuda); a
+// missing vendor is reported as unknown and a missing ABI as empty. A
+// lone component, as cl.exe reports, is taken to be the architecture.
+// Components are lowercased and the architecture is normalised, so amd64
+// and x86_64 compare equal.
+func Parse(target string) models.TargetTriple {
+	target = strings.ToLower(strings.TrimSpace(target))
+	if target == "" {
+		return models.TargetTriple{}
+	}
+
+	parts := strings.Split(target, "-")
+	triple := models.TargetTriple{Arch: normalizeArch(parts[0])}
+	rest := parts[1:]
+	if len(rest) == 0 {
+		return triple
+	}
+
+	// A second component that names an OS means the vendor was left out
+	if isVendor(rest[0]) || !isOS(rest[0]) && len(rest) > 1 {
+		triple.Vendor, rest = rest[0], rest[1:]
+	} else {
+		triple.Vendor = "unknown"
+	}
+	if len(rest) > 0 {
+		triple.OS, rest = rest[0], rest[1:]
+	}
+	// Anything past the fourth component belongs to the ABI
+	triple.ABI = strings.Join(rest, "-")
+	return triple
+}
+
+func normalizeArch(arch string) string {
+	if alias, ok := archAliases[arch]; ok {
+		return alias
+	}
+	return arch
+}
+
+func isVendor(component string) bool {
+	return vendors[component]
+}
+
+func isOS(component string) bool {
+	for _, prefix := range osPrefixes {
+		if strings.HasPrefix(component, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,39 @@
+package triple
+
+import (
+	"testing"
+
+	"builds/internal/models"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		target string
+		want   models.TargetTriple
+	}{
+		{"x86_64-pc-linux-gnu", models.TargetTriple{Arch: "x86_64", Vendor: "pc", OS: "linux", ABI: "gnu"}},
+		{"aarch64-unknown-linux-gnu", models.TargetTriple{Arch: "aarch64", Vendor: "unknown", OS: "linux", ABI: "gnu"}},
+		{"arm64-apple-darwin23.1.0", models.TargetTriple{Arch: "aarch64", Vendor: "apple", OS: "darwin23.1.0"}},
+		{"x86_64-linux-gnu", models.TargetTriple{Arch: "x86_64", Vendor: "unknown", OS: "linux", ABI: "gnu"}},
+		{"x86_64-w64-mingw32", models.TargetTriple{Arch: "x86_64", Vendor: "w64", OS: "mingw32"}},
+		{"arm-none-eabi", models.TargetTriple{Arch: "arm", Vendor: "unknown", OS: "none", ABI: "eabi"}},
+		{"armv7-unknown-linux-gnueabihf", models.TargetTriple{Arch: "armv7", Vendor: "unknown", OS: "linux", ABI: "gnueabihf"}},
+		{"wasm32-wasi", models.TargetTriple{Arch: "wasm32", Vendor: "unknown", OS: "wasi"}},
+		{"wasm32-unknown-emscripten", models.TargetTriple{Arch: "wasm32", Vendor: "unknown", OS: "emscripten"}},
+		{"nvptx64-nvidia-cuda", models.TargetTriple{Arch: "nvptx64", Vendor: "nvidia", OS: "cuda"}},
+		{"amdgcn-amd-amdhsa", models.TargetTriple{Arch: "amdgcn", Vendor: "amd", OS: "amdhsa"}},
+		{"i686-pc-windows-msvc", models.TargetTriple{Arch: "i386", Vendor: "pc", OS: "windows", ABI: "msvc"}},
+		{"ppc64le-redhat-linux", models.TargetTriple{Arch: "powerpc64le", Vendor: "redhat", OS: "linux"}},
+		{"riscv64-unknown-linux-gnu-extra", models.TargetTriple{Arch: "riscv64", Vendor: "unknown", OS: "linux", ABI: "gnu-extra"}},
+		{" X86_64-PC-Linux-GNU\n", models.TargetTriple{Arch: "x86_64", Vendor: "pc", OS: "linux", ABI: "gnu"}},
+		{"x64", models.TargetTriple{Arch: "x86_64"}},
+		{"", models.TargetTriple{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			if got := Parse(tt.target); got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+// internal/reporters/csv/reporter.go
+package csv
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"builds/internal/analysis/performance"
+	"builds/internal/models"
+	"builds/internal/reporters/filename"
+)
+
+type Reporter struct {
+	filename.Prefix
+	build    *models.Build
+	analysis *performance.AnalysisResult
+	outDir   string
+}
+
+func NewReporter(build *models.Build, analysis *performance.AnalysisResult, outDir string) *Reporter {
+	return &Reporter{
+		build:    build,
+		analysis: analysis,
+		outDir:   outDir,
+	}
+}
+
+func (r *Reporter) Generate() error {
+	if err := os.MkdirAll(r.outDir, 0755); err != nil {
+		return fmt.Errorf("creating output directory: %w", err)
+	}
+
+	heatmapPath := filepath.Join(r.outDir, r.FilePrefix(r.build.ID)+"-heatmap.csv")
+	if err := r.writeHeatmap(heatmapPath); err != nil {
+		return fmt.Errorf("writing heatmap: %w", err)
+	}
+
+	return nil
+}
+
+// GenerateTo writes the heatmap to out instead of a file
+func (r *Reporter) GenerateTo(out io.Writer) error {
+	return r.writeHeatmapTo(out)
+}
+
+func (r *Reporter) writeHeatmap(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return r.writeHeatmapTo(file)
+}
+
+func (r *Reporter) writeHeatmapTo(out io.Writer) error {
+	w := csv.NewWriter(out)
+	if err := w.Write([]string{"file", "start_line", "end_line", "remarks", "missed", "missed_share"}); err != nil {
+		return err
+	}
+
+	for _, spot := range r.analysis.RemarkHeatmap {
+		record := []string{
+			spot.File,
+			strconv.Itoa(int(spot.StartLine)),
+			strconv.Itoa(int(spot.EndLine)),
+			strconv.Itoa(spot.Remarks),
+			strconv.Itoa(spot.Missed),
+			strconv.FormatFloat(spot.Share, 'f', 4, 64),
+		}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
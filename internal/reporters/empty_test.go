@@ -0,0 +1,71 @@
+package reporters
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"builds/internal/models"
+)
+
+func TestMinimalBuild(t *testing.T) {
+	tests := []struct {
+		format  string
+		marked  int      // Sections marked as having no data
+		missing []string // Headers or rows of empty sections that must not appear
+	}{
+		// Environment, hardware, compiler, command, output, resource usage,
+		// performance and analysis
+		{"text", 8, []string{"Operating System:", "CPU:", "Version:", "Executable:", "Exit Code:", "Memory Usage Profile:"}},
+		{"display", 8, []string{"Operating System:", "CPU:", "Version:", "Executable:", "Exit Code:", "Memory Usage Profile:"}},
+		// Hardware; the summary table leaves out the rows it has no data for
+		{"html", 1, []string{"<th>Compiler</th>", "<th>Command</th>", "<th>Peak Memory</th>"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Render(&models.Build{ID: "min"}, nil, tt.format, &buf); err != nil {
+				t.Fatal(err)
+			}
+			report := buf.String()
+
+			if got := strings.Count(report, "No data collected"); got != tt.marked {
+				t.Errorf("%d sections marked as empty, want %d:\n%s", got, tt.marked, report)
+			}
+			for _, s := range tt.missing {
+				if strings.Contains(report, s) {
+					t.Errorf("report shows %q for a build without it:\n%s", s, report)
+				}
+			}
+		})
+	}
+}
+
+func TestCollectedSectionsNotMarked(t *testing.T) {
+	build := testBuild()
+	build.Environment = models.Environment{OS: "linux", Arch: "amd64"}
+	build.Hardware.CPU = models.CPU{Model: "EPYC", Cores: 8}
+	build.Command = models.Command{Executable: "clang", Arguments: []string{"-c", "foo.c"}}
+	build.ResourceUsage = models.ResourceUsage{MaxMemory: 1 << 20, CPUTime: 1}
+
+	var buf bytes.Buffer
+	if err := Render(build, nil, "text", &buf); err != nil {
+		t.Fatal(err)
+	}
+	report := buf.String()
+	for _, header := range []string{"Environment Information", "Hardware Information", "Compiler Information", "Command Information", "Resource Usage"} {
+		_, section, _ := strings.Cut(report, header)
+		section, _, _ = strings.Cut(section, "\n\n")
+		if strings.Contains(section, "No data collected") {
+			t.Errorf("%s marked as empty:\n%s", header, report)
+		}
+	}
+	// Nothing was collected for these
+	for _, header := range []string{"Output Information", "Performance Information"} {
+		_, section, _ := strings.Cut(report, header)
+		section, _, _ = strings.Cut(section, "\n\n")
+		if !strings.Contains(section, "No data collected") {
+			t.Errorf("%s not marked as empty:\n%s", header, report)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+// internal/reporters/filename/filename.go
+
+package filename
+
+// Prefix holds the name the files of a report start with. Reporters writing
+// files embed it to take SetFilePrefix.
+type Prefix struct {
+	prefix string
+}
+
+// SetFilePrefix sets the name report files start with
+func (p *Prefix) SetFilePrefix(prefix string) {
+	p.prefix = prefix
+}
+
+// FilePrefix returns the prefix set, or build-<id> when none was
+func (p *Prefix) FilePrefix(buildID string) string {
+	if p.prefix == "" {
+		return "build-" + buildID
+	}
+	return p.prefix
+}
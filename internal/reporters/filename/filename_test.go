@@ -0,0 +1,14 @@
+package filename
+
+import "testing"
+
+func TestFilePrefix(t *testing.T) {
+	var p Prefix
+	if got := p.FilePrefix("b1"); got != "build-b1" {
+		t.Errorf("default prefix %q, want build-b1", got)
+	}
+	p.SetFilePrefix("clang-20240102")
+	if got := p.FilePrefix("b1"); got != "clang-20240102" {
+		t.Errorf("prefix %q, want clang-20240102", got)
+	}
+}
@@ -0,0 +1,465 @@
+// internal/reporters/html/reporter.go
+package html
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"builds/internal/analysis/performance"
+	"builds/internal/models"
+	"builds/internal/reporters/filename"
+	"builds/internal/utils/units"
+)
+
+// maxRemarksPerPass bounds the rows listed for each pass, so builds with
+// tens of thousands of remarks still produce a file browsers can open
+const maxRemarksPerPass = 200
+
+type Reporter struct {
+	filename.Prefix
+	build    *models.Build
+	analysis *performance.AnalysisResult
+	outDir   string
+}
+
+func NewReporter(build *models.Build, analysis *performance.AnalysisResult, outDir string) *Reporter {
+	return &Reporter{
+		build:    build,
+		analysis: analysis,
+		outDir:   outDir,
+	}
+}
+
+func (r *Reporter) Generate() error {
+	if err := os.MkdirAll(r.outDir, 0755); err != nil {
+		return fmt.Errorf("creating output directory: %w", err)
+	}
+
+	// Render first so a failure does not leave a partial file
+	var buf bytes.Buffer
+	if err := r.GenerateTo(&buf); err != nil {
+		return err
+	}
+
+	reportPath := filepath.Join(r.outDir, r.FilePrefix(r.build.ID)+".html")
+	if err := os.WriteFile(reportPath, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("writing report: %w", err)
+	}
+	return nil
+}
+
+// GenerateTo writes the report to w instead of a file
+func (r *Reporter) GenerateTo(w io.Writer) error {
+	analysis := r.analysis
+	if analysis == nil {
+		analysis = &performance.AnalysisResult{}
+	}
+
+	passes := groupByPass(r.build.Remarks)
+	if err := reportTemplate.Execute(w, reportData{
+		Build:       r.build,
+		Analysis:    analysis,
+		Passes:      passes,
+		Capped:      slices.ContainsFunc(passes, func(g passGroup) bool { return g.Hidden() > 0 }),
+		MaxListed:   maxRemarksPerPass,
+		HasAnalysis: slices.ContainsFunc(passes, listsAnalysis),
+		Generated:   time.Now().UTC(),
+	}); err != nil {
+		return fmt.Errorf("rendering report: %w", err)
+	}
+	return nil
+}
+
+type reportData struct {
+	Build    *models.Build
+	Analysis *performance.AnalysisResult
+	Passes   []passGroup
+	// Set when a pass has more remarks than are listed, which the filter
+	// cannot see
+	Capped      bool
+	MaxListed   int
+	HasAnalysis bool // Whether any listed remark is an analysis remark
+	Generated   time.Time
+}
+
+// passGroup is the remarks of one compiler pass
+type passGroup struct {
+	Pass      string
+	Passed    int
+	Missed    int
+	Total     int
+	Remarks   []models.CompilerRemark // At most maxRemarksPerPass, missed first
+	Functions []functionGroup         // The listed remarks by function
+}
+
+// functionGroup is the listed remarks of a pass in one function
+type functionGroup struct {
+	Function string
+	Passed   int
+	Missed   int
+	Remarks  []models.CompilerRemark
+}
+
+// Hidden is the number of remarks left out of the listing
+func (g passGroup) Hidden() int {
+	return g.Total - len(g.Remarks)
+}
+
+// groupByPass groups remarks by pass, largest groups first
+func groupByPass(remarks []models.CompilerRemark) []passGroup {
+	byPass := make(map[string]*passGroup)
+	var order []string
+	for _, remark := range remarks {
+		pass := remark.Pass
+		if pass == "" {
+			pass = "unknown"
+		}
+		group, ok := byPass[pass]
+		if !ok {
+			group = &passGroup{Pass: pass}
+			byPass[pass] = group
+			order = append(order, pass)
+		}
+		group.Total++
+		switch {
+		case strings.EqualFold(remark.Status, string(models.RemarkStatusPassed)):
+			group.Passed++
+		case strings.EqualFold(remark.Status, string(models.RemarkStatusMissed)):
+			group.Missed++
+		}
+		group.Remarks = append(group.Remarks, remark)
+	}
+
+	groups := make([]passGroup, 0, len(order))
+	for _, pass := range order {
+		group := *byPass[pass]
+		// Missed optimizations are what readers act on, so list them first
+		sort.SliceStable(group.Remarks, func(i, j int) bool {
+			return isMissed(group.Remarks[i]) && !isMissed(group.Remarks[j])
+		})
+		if len(group.Remarks) > maxRemarksPerPass {
+			group.Remarks = group.Remarks[:maxRemarksPerPass]
+		}
+		group.Functions = groupByFunction(group.Remarks)
+		groups = append(groups, group)
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].Total != groups[j].Total {
+			return groups[i].Total > groups[j].Total
+		}
+		return groups[i].Pass < groups[j].Pass
+	})
+	return groups
+}
+
+// groupByFunction groups remarks by function in the order the functions
+// first appear, so functions with missed remarks stay on top
+func groupByFunction(remarks []models.CompilerRemark) []functionGroup {
+	index := make(map[string]int)
+	var groups []functionGroup
+	for _, remark := range remarks {
+		i, ok := index[remark.Function]
+		if !ok {
+			i = len(groups)
+			index[remark.Function] = i
+			groups = append(groups, functionGroup{Function: remark.Function})
+		}
+		group := &groups[i]
+		switch {
+		case strings.EqualFold(remark.Status, string(models.RemarkStatusPassed)):
+			group.Passed++
+		case isMissed(remark):
+			group.Missed++
+		}
+		group.Remarks = append(group.Remarks, remark)
+	}
+	return groups
+}
+
+// searchText is the lowercased text the filter box matches a remark
+// against
+func searchText(remark models.CompilerRemark) string {
+	text := strings.Join([]string{remark.Pass, remark.Name, remark.Function, remark.Location.File, remark.Message}, " ")
+	return strings.ToLower(text)
+}
+
+// listsAnalysis reports whether the listing of a pass has an analysis
+// remark, which the status filter can then select
+func listsAnalysis(g passGroup) bool {
+	return slices.ContainsFunc(g.Remarks, func(remark models.CompilerRemark) bool {
+		return strings.EqualFold(remark.Status, string(models.RemarkStatusAnalysis))
+	})
+}
+
+func isMissed(remark models.CompilerRemark) bool {
+	return strings.EqualFold(remark.Status, string(models.RemarkStatusMissed))
+}
+
+var funcs = template.FuncMap{
+	"formatBytes": units.FormatBytes,
+	"seconds": func(v float64) string {
+		return fmt.Sprintf("%.2f", v)
+	},
+	"percent": func(ratio float64) string {
+		return fmt.Sprintf("%.1f%%", ratio*100)
+	},
+	"timestamp": func(t time.Time) string {
+		if t.IsZero() {
+			return "-"
+		}
+		return t.Format(time.RFC3339)
+	},
+	"lower":      strings.ToLower,
+	"base":       filepath.Base,
+	"searchText": searchText,
+}
+
+// All values are inserted through html/template, which escapes them for
+// the context they appear in
+var reportTemplate = template.Must(template.New("report").Funcs(funcs).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Build {{.Build.ID}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em auto; max-width: 72em; padding: 0 1em; color: #222; }
+h1 { font-size: 1.6em; margin-bottom: 0.2em; }
+h2 { font-size: 1.25em; border-bottom: 1px solid #ddd; padding-bottom: 0.2em; margin-top: 1.8em; }
+h3 { font-size: 1.05em; margin-bottom: 0.4em; }
+table { border-collapse: collapse; width: 100%; margin: 0.5em 0 1em; font-size: 0.92em; }
+th, td { text-align: left; padding: 0.3em 0.6em; border-bottom: 1px solid #eee; vertical-align: top; }
+th { background: #f6f8fa; }
+table.facts th { width: 14em; background: none; font-weight: 600; }
+code, .loc { font-family: SFMono-Regular, Consolas, monospace; font-size: 0.9em; }
+.status { font-weight: 600; }
+.success, .passed { color: #1a7f37; }
+.failure, .missed { color: #cf222e; }
+.high { color: #cf222e; } .medium { color: #9a6700; } .low { color: #57606a; }
+.muted { color: #57606a; }
+.badge { display: inline-block; padding: 0 0.5em; border-radius: 1em; font-size: 0.8em; font-weight: 600; line-height: 1.6; }
+.badge.passed { background: #dafbe1; }
+.badge.missed { background: #ffebe9; }
+.badge.analysis, .badge.total { background: #eaeef2; color: #57606a; }
+details.pass { margin: 0.6em 0; }
+details.pass > summary { font-size: 1.05em; font-weight: 600; cursor: pointer; padding: 0.2em 0; }
+details.function { margin: 0.3em 0 0.3em 1.2em; }
+details.function > summary { cursor: pointer; }
+details.function table { margin-top: 0.3em; }
+.filters { display: flex; gap: 0.6em; align-items: center; margin: 0.8em 0; }
+.filters input { flex: 1; max-width: 30em; padding: 0.3em 0.5em; }
+</style>
+</head>
+<body>
+<h1>Build {{.Build.ID}}</h1>
+<p class="muted">Generated {{timestamp .Generated}}</p>
+
+<h2>Summary</h2>
+<table class="facts">
+<tr><th>Status</th><td class="status {{if .Build.Success}}success">SUCCESS{{else}}failure">FAILED{{end}}</td></tr>
+{{- if .Build.Error}}<tr><th>Error</th><td>{{.Build.Error}}</td></tr>{{end}}
+<tr><th>Start</th><td>{{timestamp .Build.StartTime}}</td></tr>
+<tr><th>End</th><td>{{timestamp .Build.EndTime}}</td></tr>
+<tr><th>Duration</th><td>{{seconds .Build.Duration}} s</td></tr>
+{{- with .Build.VersionControl}}
+<tr><th>Commit</th><td><code>{{.Commit}}</code>{{if .Branch}} <span class="muted">({{.Branch}})</span>{{end}}{{if .Dirty}} <span class="medium">dirty</span>{{end}}</td></tr>
+{{- if .RemoteURL}}<tr><th>Repository</th><td>{{.RemoteURL}}</td></tr>{{end}}
+{{- end}}
+{{- with .Build.Compiler}}{{if .Name}}
+<tr><th>Compiler</th><td>{{.Name}} {{.Version}}{{if .Target}} <span class="muted">({{.Target}})</span>{{end}}</td></tr>
+{{- end}}{{end}}
+{{- if .Build.Command.Executable}}
+<tr><th>Command</th><td><code>{{.Build.Command.Executable}}{{range .Build.Command.Arguments}} {{.}}{{end}}</code></td></tr>
+{{- end}}
+{{- if .Build.ResourceUsage.MaxMemory}}
+<tr><th>Peak Memory</th><td>{{formatBytes .Build.ResourceUsage.MaxMemory}}</td></tr>
+<tr><th>CPU Time</th><td>{{seconds .Build.ResourceUsage.CPUTime}} s</td></tr>
+{{- end}}
+{{- range $key, $value := .Build.Labels}}
+<tr><th>Label {{$key}}</th><td>{{$value}}</td></tr>
+{{- end}}
+</table>
+
+<h2>Hardware</h2>
+{{- with .Build.Hardware}}
+{{- if or .CPU.Model .Memory.Total}}
+<table class="facts">
+<tr><th>CPU</th><td>{{.CPU.Model}}{{if .CPU.Cores}} <span class="muted">({{.CPU.Cores}} cores, {{.CPU.Threads}} threads{{if gt .CPU.NUMANodes 1}}, {{.CPU.NUMANodes}} NUMA nodes{{end}})</span>{{end}}</td></tr>
+<tr><th>Memory</th><td>{{formatBytes .Memory.Total}}</td></tr>
+{{- range .GPUs}}
+<tr><th>GPU</th><td>{{.Model}} <span class="muted">{{formatBytes .Memory}}, driver {{.Driver}}</span></td></tr>
+{{- end}}
+</table>
+{{- else}}
+<p class="muted">No data collected</p>
+{{- end}}
+{{- end}}
+
+<h2>Bottlenecks</h2>
+{{- if .Analysis.Bottlenecks}}
+<table>
+<tr><th>Type</th><th>Severity</th><th>Description</th><th>Impact</th><th>Explanation</th></tr>
+{{- range .Analysis.Bottlenecks}}
+<tr><td>{{.Type}}</td><td class="{{lower .Severity}}">{{.Severity}}</td><td>{{.Description}}</td><td>{{printf "%.2f" .Impact}}</td><td>{{.Explanation}}</td></tr>
+{{- end}}
+</table>
+{{- else}}
+<p class="muted">No bottlenecks found</p>
+{{- end}}
+
+<h2>Recommendations</h2>
+{{- if .Analysis.Recommendations}}
+<table>
+<tr><th>Category</th><th>Action</th><th>Impact</th><th>Details</th></tr>
+{{- range .Analysis.Recommendations}}
+<tr><td>{{.Category}}</td><td>{{.Action}}</td><td>{{.Impact}}</td><td>{{.Details}}</td></tr>
+{{- end}}
+</table>
+{{- else}}
+<p class="muted">No recommendations</p>
+{{- end}}
+
+{{- with .Build.LinkMap}}
+<h2>Binary Size</h2>
+<table class="facts">
+{{- range .Sections}}
+<tr><th>{{.Name}}</th><td>{{formatBytes .Size}}</td></tr>
+{{- end}}
+{{- if .DiscardedSections}}
+<tr><th>Discarded</th><td>{{.DiscardedSections}} unused sections <span class="muted">({{formatBytes .DiscardedSize}})</span></td></tr>
+{{- end}}
+</table>
+{{- if .Symbols}}
+<table>
+<tr><th>Symbol</th><th>Size</th><th>Section</th><th>Object</th></tr>
+{{- range .Symbols}}
+<tr><td><code>{{.Name}}</code></td><td>{{formatBytes .Size}}</td><td>{{.Section}}</td><td class="loc">{{base .Object}}</td></tr>
+{{- end}}
+</table>
+{{- if gt .SymbolsSeen (len .Symbols)}}
+<p class="muted">Largest {{len .Symbols}} of {{.SymbolsSeen}} symbols shown</p>
+{{- end}}
+{{- end}}
+{{- end}}
+
+{{- if gt (len .Analysis.Files) 1}}
+<h2>Remarks by File</h2>
+{{- with index .Analysis.Files 0}}{{if .Missed}}
+<p>Optimization hotspot: <code>{{.File}}</code>, with {{.Missed}} missed optimizations</p>
+{{- end}}{{end}}
+<table>
+<tr><th>File</th><th>Remarks</th><th>Passed</th><th>Missed</th><th>Share of Missed</th><th>Functions</th></tr>
+{{- range .Analysis.Files}}
+<tr><td class="loc">{{.File}}</td><td>{{.Remarks}}</td><td>{{.Passed}}</td><td>{{.Missed}}</td><td>{{percent .Share}}</td><td>{{.Functions}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+
+<h2>Compiler Remarks</h2>
+{{- if .Passes}}
+<table>
+<tr><th>Pass</th><th>Remarks</th><th>Passed</th><th>Missed</th></tr>
+{{- range .Passes}}
+<tr><td><a href="#pass-{{.Pass}}">{{.Pass}}</a></td><td>{{.Total}}</td><td>{{.Passed}}</td><td>{{.Missed}}</td></tr>
+{{- end}}
+</table>
+<div class="filters">
+<input id="remark-filter" type="search" placeholder="Filter by pass, function, file or message">
+<select id="remark-status">
+<option value="">All statuses</option>
+<option value="missed">Missed</option>
+<option value="passed">Passed</option>
+{{- if .HasAnalysis}}
+<option value="analysis">Analysis</option>
+{{- end}}
+</select>
+<span id="remark-count" class="muted"></span>
+</div>
+{{- if .Capped}}
+<p class="muted">At most {{.MaxListed}} remarks are listed per pass, missed first. The filter searches only the listed remarks; the pass table above counts them all.</p>
+{{- end}}
+{{- range .Passes}}
+<details class="pass" id="pass-{{.Pass}}" data-pass="{{.Pass}}">
+<summary>{{.Pass}} <span class="badge total">{{.Total}}</span>{{if .Missed}} <span class="badge missed">{{.Missed}} missed</span>{{end}}{{if .Passed}} <span class="badge passed">{{.Passed}} passed</span>{{end}}</summary>
+{{- range .Functions}}
+<details class="function" data-function="{{.Function}}">
+<summary>{{if .Function}}<code>{{.Function}}</code>{{else}}<span class="muted">No function</span>{{end}} <span class="badge total">{{len .Remarks}}</span>{{if .Missed}} <span class="badge missed">{{.Missed}} missed</span>{{end}}{{if .Passed}} <span class="badge passed">{{.Passed}} passed</span>{{end}}</summary>
+<table>
+<tr><th>Status</th><th>Location</th><th>Message</th></tr>
+{{- range .Remarks}}
+<tr class="remark" data-status="{{lower .Status}}" data-text="{{searchText .}}"><td><span class="badge {{lower .Status}}">{{.Status}}</span></td><td class="loc">{{if .Location.File}}{{.Location.File}}:{{.Location.Line}}{{if .Location.Column}}:{{.Location.Column}}{{end}}{{end}}</td><td>{{.Message}}</td></tr>
+{{- end}}
+</table>
+</details>
+{{- end}}
+{{- if .Hidden}}<p class="muted">{{.Hidden}} more remarks not shown</p>{{end}}
+</details>
+{{- end}}
+<script>
+// Filters the remark rows by status and text, hiding functions and
+// passes left without rows and opening those with matches
+(function () {
+  var input = document.getElementById("remark-filter");
+  var status = document.getElementById("remark-status");
+  var count = document.getElementById("remark-count");
+  var rows = document.querySelectorAll("tr.remark");
+
+  function apply() {
+    var query = input.value.trim().toLowerCase();
+    var wanted = status.value;
+    var filtering = query !== "" || wanted !== "";
+    var total = 0;
+    document.querySelectorAll("details.pass").forEach(function (pass) {
+      var passShown = 0;
+      pass.querySelectorAll("details.function").forEach(function (fn) {
+        var shown = 0;
+        fn.querySelectorAll("tr.remark").forEach(function (row) {
+          var match = (wanted === "" || row.dataset.status === wanted) &&
+            (query === "" || row.dataset.text.indexOf(query) !== -1);
+          row.hidden = !match;
+          if (match) {
+            shown++;
+          }
+        });
+        fn.hidden = shown === 0;
+        if (filtering) {
+          fn.open = shown > 0;
+        }
+        passShown += shown;
+      });
+      pass.hidden = passShown === 0;
+      if (filtering) {
+        pass.open = passShown > 0;
+      }
+      total += passShown;
+    });
+    count.textContent = filtering ? total + " of " + rows.length + " listed remarks shown" : "";
+  }
+
+  // Links from the pass table open the pass they point to
+  function openTarget() {
+    var target = document.getElementById(decodeURIComponent(location.hash.slice(1)));
+    if (target && target.tagName === "DETAILS") {
+      target.open = true;
+    }
+  }
+
+  input.addEventListener("input", apply);
+  status.addEventListener("change", apply);
+  window.addEventListener("hashchange", openTarget);
+  openTarget();
+})();
+</script>
+{{- else}}
+<p class="muted">No remarks collected</p>
+{{- end}}
+</body>
+</html>
+`))
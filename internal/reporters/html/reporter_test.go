@@ -0,0 +1,188 @@
+package html
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"builds/internal/analysis/performance"
+	"builds/internal/models"
+)
+
+func TestRemarkDataAttributes(t *testing.T) {
+	build := &models.Build{
+		ID: "b1",
+		Remarks: []models.CompilerRemark{
+			{Pass: "loop-vectorize", Name: "Vectorized", Status: "Passed", Function: "foo", Location: models.Location{File: "a.c", Line: 3}},
+			{Pass: "loop-vectorize", Name: "MissedDetails", Status: "missed", Function: "foo", Message: "loop not vectorized"},
+			{Pass: "inline", Name: "NoDefinition", Status: "missed", Function: `bar<"T">`, Message: "<script>alert(1)</script>"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := NewReporter(build, nil, "").GenerateTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		// The elements and attributes the filter script reads
+		`<input id="remark-filter"`,
+		`<select id="remark-status">`,
+		`<details class="pass" id="pass-loop-vectorize" data-pass="loop-vectorize">`,
+		`<details class="function" data-function="foo">`,
+		`<tr class="remark" data-status="passed" data-text="loop-vectorize vectorized foo a.c ">`,
+		`<tr class="remark" data-status="missed" data-text="loop-vectorize misseddetails foo  loop not vectorized">`,
+		`<span class="badge missed">missed</span>`,
+		`<span class="badge passed">Passed</span>`,
+		`row.dataset.status`,
+		`row.dataset.text`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report lacks %s", want)
+		}
+	}
+
+	// Remark text is escaped, in attributes as in content
+	if strings.Contains(out, "<script>alert(1)") || strings.Contains(out, `data-function="bar<"T">"`) {
+		t.Error("remark text is not escaped")
+	}
+	if !strings.Contains(out, "&lt;script&gt;alert(1)&lt;/script&gt;") {
+		t.Error("escaped message missing")
+	}
+}
+
+func TestGroupByPass(t *testing.T) {
+	remarks := []models.CompilerRemark{
+		{Pass: "inline", Status: "passed", Function: "a"},
+		{Pass: "licm", Status: "passed", Function: "b"},
+		{Pass: "licm", Status: "missed", Function: "c"},
+		{Status: "missed"},
+	}
+	groups := groupByPass(remarks)
+
+	var passes []string
+	for _, group := range groups {
+		passes = append(passes, group.Pass)
+	}
+	if got := strings.Join(passes, " "); got != "licm inline unknown" {
+		t.Fatalf("passes %s, want the largest first", got)
+	}
+	licm := groups[0]
+	if licm.Passed != 1 || licm.Missed != 1 || licm.Total != 2 {
+		t.Errorf("licm counts %d passed, %d missed of %d", licm.Passed, licm.Missed, licm.Total)
+	}
+	// Missed remarks, and so their functions, come first
+	if len(licm.Functions) != 2 || licm.Functions[0].Function != "c" || licm.Functions[0].Missed != 1 {
+		t.Errorf("licm functions %+v", licm.Functions)
+	}
+}
+
+func TestReportEscapesBuildText(t *testing.T) {
+	const payload = `<b onmouseover="x()">'&`
+	build := &models.Build{
+		ID:       payload,
+		Error:    payload,
+		Labels:   map[string]string{payload: payload},
+		Compiler: models.Compiler{Name: payload, Version: payload},
+		Command:  models.Command{Executable: "cc", Arguments: []string{payload}},
+		Hardware: models.Hardware{CPU: models.CPU{Model: payload}},
+		Remarks: []models.CompilerRemark{
+			{Pass: payload, Name: payload, Status: "missed", Function: payload, Message: payload, Location: models.Location{File: payload, Line: 1}},
+		},
+	}
+	analysis := &performance.AnalysisResult{
+		Bottlenecks:     []performance.PerformanceBottleneck{{Type: payload, Severity: payload, Description: payload, Explanation: payload}},
+		Recommendations: []performance.PerformanceRecommendation{{Category: payload, Action: payload, Impact: payload, Details: payload}},
+	}
+
+	var buf bytes.Buffer
+	if err := NewReporter(build, analysis, "").GenerateTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	if strings.Contains(out, "<b onmouseover") || strings.Contains(out, `"x()"`) {
+		t.Errorf("user text reached the page unescaped:\n%s", out)
+	}
+	// Every field is still shown, escaped
+	if n := strings.Count(out, "&lt;b onmouseover=&#34;x()&#34;&gt;&#39;&amp;"); n < 15 {
+		t.Errorf("escaped text appears %d times, want every field", n)
+	}
+}
+
+func TestReportGroupsByPass(t *testing.T) {
+	var remarks []models.CompilerRemark
+	for i := range maxRemarksPerPass + 5 {
+		status := "passed"
+		if i == maxRemarksPerPass+4 {
+			status = "missed"
+		}
+		remarks = append(remarks, models.CompilerRemark{Pass: "inline", Status: status, Function: "f", Message: "inlined"})
+	}
+	remarks = append(remarks,
+		models.CompilerRemark{Pass: "licm", Status: "missed", Function: "g", Message: "not hoisted"},
+		models.CompilerRemark{Pass: "gvn", Status: "passed", Function: "g"},
+		models.CompilerRemark{Pass: "gvn", Status: "missed", Function: "h"})
+
+	var buf bytes.Buffer
+	if err := NewReporter(&models.Build{ID: "b1", Remarks: remarks}, nil, "").GenerateTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	// One section per pass, largest first
+	var passes []string
+	for _, part := range strings.Split(out, `<details class="pass" id="pass-`)[1:] {
+		passes = append(passes, part[:strings.Index(part, `"`)])
+	}
+	if got := strings.Join(passes, " "); got != "inline gvn licm" {
+		t.Errorf("pass sections %s, want inline gvn licm", got)
+	}
+	if !strings.Contains(out, `<tr><td><a href="#pass-inline">inline</a></td><td>205</td><td>204</td><td>1</td></tr>`) {
+		t.Error("pass table lacks the inline counts")
+	}
+
+	// The listing is capped per pass, keeping the missed remark
+	if rows := strings.Count(out, `<tr class="remark"`); rows != maxRemarksPerPass+3 {
+		t.Errorf("%d remark rows, want %d", rows, maxRemarksPerPass+3)
+	}
+	if !strings.Contains(out, "5 more remarks not shown") {
+		t.Error("hidden remarks not noted")
+	}
+	if !strings.Contains(out, "At most 200 remarks are listed per pass") {
+		t.Error("the filter does not say it sees only the listed remarks")
+	}
+	inline := out[strings.Index(out, `id="pass-inline"`):strings.Index(out, `id="pass-gvn"`)]
+	if !strings.Contains(inline, `data-status="missed"`) {
+		t.Error("the missed inline remark was cut from the listing")
+	}
+}
+
+func TestStatusFilterOptions(t *testing.T) {
+	tests := []struct {
+		name         string
+		remarks      []models.CompilerRemark
+		wantAnalysis bool
+	}{
+		{"passed and missed", []models.CompilerRemark{{Pass: "inline", Status: "passed"}, {Pass: "licm", Status: "missed"}}, false},
+		{"analysis", []models.CompilerRemark{{Pass: "inline", Status: "passed"}, {Pass: "loop-vectorize", Status: "Analysis"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := NewReporter(&models.Build{ID: "b1", Remarks: tt.remarks}, nil, "").GenerateTo(&buf); err != nil {
+				t.Fatal(err)
+			}
+			out := buf.String()
+
+			if got := strings.Contains(out, `<option value="analysis">`); got != tt.wantAnalysis {
+				t.Errorf("analysis option shown = %v, want %v", got, tt.wantAnalysis)
+			}
+			// Every remark is listed, so there is no cap to explain
+			if strings.Contains(out, "remarks are listed per pass") {
+				t.Error("uncapped listing noted as capped")
+			}
+		})
+	}
+}
@@ -4,15 +4,18 @@ package json
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"time"
 
 	"builds/internal/analysis/performance"
 	"builds/internal/models"
+	"builds/internal/reporters/filename"
 )
 
 type Reporter struct {
+	filename.Prefix
 	build    *models.Build
 	analysis *performance.AnalysisResult
 	outDir   string
@@ -32,26 +35,15 @@ func (r *Reporter) Generate() error {
 		return fmt.Errorf("creating output directory: %w", err)
 	}
 
-	// Generate full report
-	report := struct {
-		Build     *models.Build               `json:"build"`
-		Analysis  *performance.AnalysisResult `json:"analysis"`
-		Generated time.Time                   `json:"generated"`
-	}{
-		Build:     r.build,
-		Analysis:  r.analysis,
-		Generated: time.Now(),
-	}
-
 	// Write full report
-	fullReportPath := filepath.Join(r.outDir, fmt.Sprintf("build-%s-full.json", r.build.ID))
-	if err := r.writeJSON(fullReportPath, report); err != nil {
+	fullReportPath := filepath.Join(r.outDir, r.FilePrefix(r.build.ID)+"-full.json")
+	if err := r.writeJSON(fullReportPath, r.FullReport()); err != nil {
 		return fmt.Errorf("writing full report: %w", err)
 	}
 
 	// Write summary report
-	summary := r.generateSummary()
-	summaryPath := filepath.Join(r.outDir, fmt.Sprintf("build-%s-summary.json", r.build.ID))
+	summary := r.Summary()
+	summaryPath := filepath.Join(r.outDir, r.FilePrefix(r.build.ID)+"-summary.json")
 	if err := r.writeJSON(summaryPath, summary); err != nil {
 		return fmt.Errorf("writing summary: %w", err)
 	}
@@ -59,6 +51,11 @@ func (r *Reporter) Generate() error {
 	return nil
 }
 
+// GenerateTo writes the full report to w instead of a file
+func (r *Reporter) GenerateTo(w io.Writer) error {
+	return encode(w, r.FullReport())
+}
+
 func (r *Reporter) writeJSON(path string, data interface{}) error {
 	file, err := os.Create(path)
 	if err != nil {
@@ -66,12 +63,30 @@ func (r *Reporter) writeJSON(path string, data interface{}) error {
 	}
 	defer file.Close()
 
-	encoder := json.NewEncoder(file)
+	return encode(file, data)
+}
+
+func encode(w io.Writer, data interface{}) error {
+	encoder := json.NewEncoder(w)
 	encoder.SetIndent("", "  ")
 	return encoder.Encode(data)
 }
 
-func (r *Reporter) generateSummary() interface{} {
+// FullReport returns the complete report document
+func (r *Reporter) FullReport() interface{} {
+	return struct {
+		Build     *models.Build               `json:"build"`
+		Analysis  *performance.AnalysisResult `json:"analysis"`
+		Generated time.Time                   `json:"generated"`
+	}{
+		Build:     r.build,
+		Analysis:  r.analysis,
+		Generated: time.Now(),
+	}
+}
+
+// Summary returns the condensed summary document
+func (r *Reporter) Summary() interface{} {
 	return struct {
 		ID          string    `json:"id"`
 		Status      string    `json:"status"`
@@ -0,0 +1,294 @@
+// internal/reporters/markdown/reporter.go
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"builds/internal/analysis/performance"
+	"builds/internal/models"
+	"builds/internal/reporters/filename"
+	"builds/internal/reporters/stats"
+	"builds/internal/utils/units"
+)
+
+// maxDetailedRemarks bounds the remarks listed in the details block, as
+// pull request comments are limited to 65536 characters
+const maxDetailedRemarks = 200
+
+// maxListedSections and maxListedSymbols bound the binary size section
+const (
+	maxListedSections = 10
+	maxListedSymbols  = 20
+)
+
+type Reporter struct {
+	filename.Prefix
+	build    *models.Build
+	analysis *performance.AnalysisResult
+	outDir   string
+}
+
+func NewReporter(build *models.Build, analysis *performance.AnalysisResult, outDir string) *Reporter {
+	return &Reporter{
+		build:    build,
+		analysis: analysis,
+		outDir:   outDir,
+	}
+}
+
+func (r *Reporter) Generate() error {
+	if err := os.MkdirAll(r.outDir, 0755); err != nil {
+		return fmt.Errorf("creating output directory: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := r.GenerateTo(&buf); err != nil {
+		return err
+	}
+
+	reportPath := filepath.Join(r.outDir, r.FilePrefix(r.build.ID)+".md")
+	if err := os.WriteFile(reportPath, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("writing report: %w", err)
+	}
+	return nil
+}
+
+// GenerateTo writes the report to w instead of a file
+func (r *Reporter) GenerateTo(w io.Writer) error {
+	if r.analysis == nil {
+		r.analysis = &performance.AnalysisResult{}
+	}
+
+	var buf bytes.Buffer
+	r.writeSummary(&buf)
+	r.writeHardware(&buf)
+	r.writeRemarks(&buf)
+	r.writeLinkMap(&buf)
+	r.writeAnalysis(&buf)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func (r *Reporter) writeSummary(w io.Writer) {
+	build := r.build
+	fmt.Fprintf(w, "# Build %s\n\n", escape(build.ID))
+
+	status := "SUCCESS"
+	if !build.Success {
+		status = "FAILED"
+	}
+
+	fmt.Fprintf(w, "## Summary\n\n")
+	fmt.Fprintf(w, "| | |\n|---|---|\n")
+	row(w, "Status", "**"+status+"**")
+	if build.Error != "" {
+		row(w, "Error", escape(build.Error))
+	}
+	row(w, "Start", timestamp(build.StartTime))
+	row(w, "Duration", fmt.Sprintf("%.2f s", build.Duration))
+	if vc := build.VersionControl; vc != nil {
+		row(w, "Commit", escape(vc.Describe()))
+		if vc.RemoteURL != "" {
+			row(w, "Repository", escape(vc.RemoteURL))
+		}
+	}
+	if comp := build.Compiler; comp.Name != "" {
+		row(w, "Compiler", escape(strings.TrimSpace(comp.Name+" "+comp.Version)))
+		if comp.Target != "" {
+			row(w, "Target", code(comp.Target))
+		}
+	}
+	if usage := build.ResourceUsage; usage.MaxMemory > 0 {
+		row(w, "Peak Memory", units.FormatBytes(usage.MaxMemory))
+		row(w, "CPU Time", fmt.Sprintf("%.2f s", usage.CPUTime))
+	}
+	if perf := build.Performance; perf.CompileTime > 0 || perf.LinkTime > 0 {
+		row(w, "Compile Time", fmt.Sprintf("%.2f s", perf.CompileTime))
+		row(w, "Link Time", fmt.Sprintf("%.2f s", perf.LinkTime))
+	}
+	fmt.Fprintln(w)
+}
+
+func (r *Reporter) writeHardware(w io.Writer) {
+	hw := r.build.Hardware
+	fmt.Fprintf(w, "## Hardware\n\n")
+	if hw.CPU.Model == "" && hw.Memory.Total == 0 {
+		fmt.Fprintf(w, "_No data collected_\n\n")
+		return
+	}
+	numa := ""
+	if hw.CPU.NUMANodes > 1 {
+		numa = fmt.Sprintf(", %d NUMA nodes", hw.CPU.NUMANodes)
+	}
+	fmt.Fprintf(w, "- CPU: %s (%d cores, %d threads%s)\n", escape(hw.CPU.Model), hw.CPU.Cores, hw.CPU.Threads, numa)
+	fmt.Fprintf(w, "- Memory: %s\n", units.FormatBytes(hw.Memory.Total))
+	for _, gpu := range hw.GPUs {
+		fmt.Fprintf(w, "- GPU: %s (%s, driver %s)\n", escape(gpu.Model), units.FormatBytes(gpu.Memory), escape(gpu.Driver))
+	}
+	fmt.Fprintln(w)
+}
+
+func (r *Reporter) writeRemarks(w io.Writer) {
+	fmt.Fprintf(w, "## Compiler Remarks\n\n")
+	if len(r.build.Remarks) == 0 {
+		fmt.Fprintf(w, "_No data collected_\n\n")
+		return
+	}
+
+	remarkStats := stats.CalculateRemarks(r.build.Remarks)
+	fmt.Fprintf(w, "%d remarks", remarkStats.TotalRemarks)
+	if total := remarkStats.Optimizations.Total; total > 0 {
+		fmt.Fprintf(w, ", %.1f%% of optimizations applied (%d/%d)",
+			float64(remarkStats.Optimizations.Passed)/float64(total)*100, remarkStats.Optimizations.Passed, total)
+	}
+	fmt.Fprintf(w, ".\n\n")
+
+	fmt.Fprintf(w, "| Pass | Remarks | Passed | Missed |\n")
+	fmt.Fprintf(w, "|---|---:|---:|---:|\n")
+	for _, pass := range stats.Sorted(remarkStats.ByPass) {
+		outcomes := remarkStats.PassOutcomes[pass.Key]
+		fmt.Fprintf(w, "| %s | %d | %d | %d |\n", cell(pass.Key), pass.Value, outcomes.Passed, outcomes.Missed)
+	}
+	fmt.Fprintln(w)
+
+	// The HTML block needs blank lines around its Markdown content
+	listed := min(len(r.build.Remarks), maxDetailedRemarks)
+	fmt.Fprintf(w, "<details>\n<summary>Detailed remarks (%d)</summary>\n\n", listed)
+	fmt.Fprintf(w, "| Status | Pass | Remark | Location | Function | Message |\n")
+	fmt.Fprintf(w, "|---|---|---|---|---|---|\n")
+	for _, remark := range r.build.Remarks[:listed] {
+		location := ""
+		if remark.Location.File != "" {
+			location = code(fmt.Sprintf("%s:%d", remark.Location.File, remark.Location.Line))
+		}
+		function := ""
+		if remark.Function != "" {
+			function = code(remark.Function)
+		}
+		fmt.Fprintf(w, "| %s | %s | %s | %s | %s | %s |\n",
+			cell(remark.Status), cell(remark.Pass), cell(remark.Name), location, function, cell(remark.Message))
+	}
+	if hidden := len(r.build.Remarks) - listed; hidden > 0 {
+		fmt.Fprintf(w, "\n%d more remarks not shown.\n", hidden)
+	}
+	fmt.Fprintf(w, "\n</details>\n\n")
+}
+
+// writeLinkMap ranks the largest symbols of the linked binary. Builds that
+// collected no linker map get no section.
+func (r *Reporter) writeLinkMap(w io.Writer) {
+	m := r.build.LinkMap
+	if m == nil {
+		return
+	}
+
+	fmt.Fprintf(w, "## Binary Size\n\n")
+	for i, section := range m.Sections {
+		if i >= maxListedSections {
+			fmt.Fprintf(w, " and %d more", len(m.Sections)-maxListedSections)
+			break
+		}
+		if i > 0 {
+			fmt.Fprintf(w, ", ")
+		}
+		fmt.Fprintf(w, "%s %s", code(section.Name), units.FormatBytes(section.Size))
+	}
+	if len(m.Sections) > 0 {
+		fmt.Fprintf(w, "\n\n")
+	}
+	if m.DiscardedSections > 0 {
+		fmt.Fprintf(w, "%d unused sections (%s) were discarded.\n\n", m.DiscardedSections, units.FormatBytes(m.DiscardedSize))
+	}
+	if len(m.Symbols) == 0 {
+		return
+	}
+
+	listed := min(len(m.Symbols), maxListedSymbols)
+	fmt.Fprintf(w, "| Symbol | Size | Section | Object |\n")
+	fmt.Fprintf(w, "|---|---:|---|---|\n")
+	for _, sym := range m.Symbols[:listed] {
+		fmt.Fprintf(w, "| %s | %s | %s | %s |\n",
+			code(sym.Name), units.FormatBytes(sym.Size), cell(sym.Section), cell(filepath.Base(sym.Object)))
+	}
+	if hidden := m.SymbolsSeen - int64(listed); hidden > 0 {
+		fmt.Fprintf(w, "\n%d more symbols not shown.\n", hidden)
+	}
+	fmt.Fprintln(w)
+}
+
+func (r *Reporter) writeAnalysis(w io.Writer) {
+	fmt.Fprintf(w, "## Bottlenecks\n\n")
+	if len(r.analysis.Bottlenecks) == 0 {
+		fmt.Fprintf(w, "_None found_\n\n")
+	}
+	for _, b := range r.analysis.Bottlenecks {
+		fmt.Fprintf(w, "- **%s** %s: %s (impact %.2f)\n", escape(b.Severity), escape(b.Type), escape(b.Description), b.Impact)
+	}
+	if len(r.analysis.Bottlenecks) > 0 {
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintf(w, "## Recommendations\n\n")
+	if len(r.analysis.Recommendations) == 0 {
+		fmt.Fprintf(w, "_None_\n")
+		return
+	}
+	for _, rec := range r.analysis.Recommendations {
+		fmt.Fprintf(w, "- **%s**: %s", escape(rec.Category), escape(rec.Action))
+		if rec.Details != "" {
+			fmt.Fprintf(w, " - %s", escape(rec.Details))
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+func row(w io.Writer, name, value string) {
+	fmt.Fprintf(w, "| %s | %s |\n", name, value)
+}
+
+func timestamp(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format(time.RFC3339)
+}
+
+// markdownEscaper backslash-escapes the punctuation that could start
+// emphasis, links, code, HTML or headings, so build data renders as text
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", `*`, `\*`, `_`, `\_`, `[`, `\[`, `]`, `\]`,
+	`<`, `\<`, `>`, `\>`, `#`, `\#`, `|`, `\|`, `~`, `\~`, `!`, `\!`,
+	"\r\n", " ", "\n", " ",
+)
+
+func escape(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+// cell escapes text for a table cell, which must stay on one line
+func cell(s string) string {
+	return escape(s)
+}
+
+// code renders s as inline code, with a fence longer than any run of
+// backticks inside it. Pipes are escaped, as GitHub splits table cells on
+// them even inside code spans.
+func code(s string) string {
+	s = strings.ReplaceAll(strings.ReplaceAll(s, "\r\n", " "), "\n", " ")
+	fence := "`"
+	for strings.Contains(s, fence) {
+		fence += "`"
+	}
+	s = strings.ReplaceAll(s, "|", `\|`)
+	if strings.HasPrefix(s, "`") || strings.HasSuffix(s, "`") {
+		s = " " + s + " "
+	}
+	return fence + s + fence
+}
@@ -0,0 +1,35 @@
+package markdown
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"builds/internal/models"
+)
+
+func TestRemarksByPass(t *testing.T) {
+	build := &models.Build{
+		ID: "b1",
+		Remarks: []models.CompilerRemark{
+			{Pass: "loop-vectorize", Name: "Vectorized", Status: "passed", Location: models.Location{File: "a.c", Line: 3}},
+			{Pass: "loop-vectorize", Name: "MissedDetails", Status: "missed"},
+			{Pass: "inline", Name: "Inlined", Status: "passed"},
+		},
+	}
+
+	var buf bytes.Buffer
+	NewReporter(build, nil, "").writeRemarks(&buf)
+	out := buf.String()
+
+	for _, row := range []string{
+		"| loop-vectorize | 2 | 1 | 1 |",
+		"| inline | 1 | 1 | 0 |",
+		"| passed | loop-vectorize | Vectorized | `a.c:3` |",
+		"| missed | loop-vectorize | MissedDetails |",
+	} {
+		if !strings.Contains(out, row) {
+			t.Errorf("report lacks %q:\n%s", row, out)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+// internal/reporters/prefix.go
+package reporters
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"builds/internal/models"
+)
+
+// DefaultFilePrefix names report files build-<id>.txt, build-<id>-full.json
+// and so on
+const DefaultFilePrefix = "build-{{.ID}}"
+
+// prefixData is the data available to file prefix templates
+type prefixData struct {
+	ID        string
+	Compiler  string
+	Version   string
+	Timestamp string // Build start as 20060102-150405
+	Date      string // Build start as 2006-01-02
+}
+
+// ExpandFilePrefix renders a report file prefix template such as
+// "{{.Compiler}}-{{.Timestamp}}". The result must be a plain file name, so
+// templates that could escape the output directory are rejected.
+func ExpandFilePrefix(tmpl string, build *models.Build) (string, error) {
+	if tmpl == "" {
+		tmpl = DefaultFilePrefix
+	}
+
+	t, err := template.New("prefix").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("invalid output prefix %q: %w", tmpl, err)
+	}
+
+	var sb strings.Builder
+	err = t.Execute(&sb, prefixData{
+		ID:        build.ID,
+		Compiler:  build.Compiler.Name,
+		Version:   build.Compiler.Version,
+		Timestamp: build.StartTime.UTC().Format("20060102-150405"),
+		Date:      build.StartTime.UTC().Format("2006-01-02"),
+	})
+	if err != nil {
+		return "", fmt.Errorf("invalid output prefix %q: %w", tmpl, err)
+	}
+
+	prefix := sb.String()
+	if err := validateFilePrefix(prefix); err != nil {
+		return "", fmt.Errorf("invalid output prefix %q: %w", tmpl, err)
+	}
+	return prefix, nil
+}
+
+// validateFilePrefix rejects prefixes that are not a single safe file name
+func validateFilePrefix(prefix string) error {
+	switch {
+	case prefix == "":
+		return fmt.Errorf("expands to an empty name")
+	case strings.ContainsAny(prefix, `/\:`):
+		return fmt.Errorf("%q contains a path separator", prefix)
+	case strings.Contains(prefix, ".."):
+		return fmt.Errorf("%q contains \"..\"", prefix)
+	case strings.HasPrefix(prefix, "."):
+		return fmt.Errorf("%q starts with a dot", prefix)
+	}
+	for _, r := range prefix {
+		if r < 0x20 || r == 0x7f {
+			return fmt.Errorf("%q contains a control character", prefix)
+		}
+	}
+	return nil
+}
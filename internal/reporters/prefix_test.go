@@ -0,0 +1,102 @@
+package reporters
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestExpandFilePrefix(t *testing.T) {
+	tests := []struct {
+		tmpl string
+		want string
+	}{
+		{"", "build-b1"},
+		{"{{.Compiler}}-{{.Timestamp}}", "clang-20240101-120000"},
+		{"nightly-{{.Date}}-{{.ID}}", "nightly-2024-01-01-b1"},
+		{"{{.Compiler}}{{.Version}}", "clang18.1.0"},
+		{"report", "report"},
+	}
+	for _, tt := range tests {
+		got, err := ExpandFilePrefix(tt.tmpl, testBuild())
+		if err != nil {
+			t.Errorf("ExpandFilePrefix(%q): %v", tt.tmpl, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ExpandFilePrefix(%q) = %q, want %q", tt.tmpl, got, tt.want)
+		}
+	}
+}
+
+func TestExpandFilePrefixRejectsUnsafe(t *testing.T) {
+	tests := []struct {
+		name     string
+		tmpl     string
+		compiler string
+	}{
+		{"parent directory", "../{{.ID}}", ""},
+		{"absolute path", "/tmp/{{.ID}}", ""},
+		{"subdirectory", "reports/{{.ID}}", ""},
+		{"windows separator", `..\{{.ID}}`, ""},
+		{"drive letter", "C:{{.ID}}", ""},
+		{"hidden file", ".{{.ID}}", ""},
+		{"dots in the middle", "a..b", ""},
+		{"control character", "build\n{{.ID}}", ""},
+		{"empty expansion", "{{.Version}}", ""},
+		{"unsafe build data", "{{.Compiler}}", "../../etc/passwd"},
+		{"unknown field", "{{.Missing}}", ""},
+		{"malformed template", "{{.ID", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			build := testBuild()
+			build.Compiler.Name = tt.compiler
+			build.Compiler.Version = ""
+			got, err := ExpandFilePrefix(tt.tmpl, build)
+			if err == nil {
+				t.Fatalf("ExpandFilePrefix(%q) = %q, want an error", tt.tmpl, got)
+			}
+			if !strings.Contains(err.Error(), "invalid output prefix") {
+				t.Errorf("error %q does not name the prefix", err)
+			}
+		})
+	}
+}
+
+func TestNewReporterFilePrefix(t *testing.T) {
+	dir := t.TempDir()
+	build, analysis := analyzed(t)
+	opts := Options{
+		OutputDir:  dir,
+		Format:     "json",
+		Build:      build,
+		Analysis:   analysis,
+		FilePrefix: "{{.Compiler}}-{{.Date}}",
+	}
+
+	reporter, err := NewReporter(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := reporter.Generate(); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) == 0 {
+		t.Fatal("no report written")
+	}
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), "clang-2024-01-01") {
+			t.Errorf("report file %s does not start with clang-2024-01-01", entry.Name())
+		}
+	}
+
+	opts.FilePrefix = "../{{.ID}}"
+	if _, err := NewReporter(opts); err == nil {
+		t.Error("NewReporter accepted a prefix outside the output directory")
+	}
+}
@@ -0,0 +1,115 @@
+package reporters
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"builds/internal/analysis/performance"
+	"builds/internal/models"
+)
+
+// A build as another program would hold it, decoded from JSON
+const buildJSON = `{
+	"id": "b1",
+	"startTime": "2024-01-01T12:00:00Z",
+	"success": true,
+	"compiler": {"name": "clang", "version": "18.1.0"},
+	"remarks": [{
+		"pass": "loop-vectorize",
+		"name": "MissedDetails",
+		"status": "missed",
+		"function": "foo",
+		"location": {"file": "foo.c", "line": 3}
+	}]
+}`
+
+func TestRender(t *testing.T) {
+	var build models.Build
+	if err := json.Unmarshal([]byte(buildJSON), &build); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, format := range append([]string{"display"}, fileFormats...) {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Render(&build, nil, format, &buf); err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(buf.String(), "foo.c") {
+				t.Errorf("%s report lacks the remark:\n%s", format, buf.String())
+			}
+		})
+	}
+}
+
+func TestRenderAnalyzes(t *testing.T) {
+	build := testBuild()
+	var buf bytes.Buffer
+	if err := Render(build, nil, "json", &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var report struct {
+		Build    models.Build                `json:"build"`
+		Analysis *performance.AnalysisResult `json:"analysis"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.Build.ID != "b1" {
+		t.Errorf("reported build %q, want b1", report.Build.ID)
+	}
+	if report.Analysis == nil {
+		t.Fatal("report has no analysis")
+	}
+
+	want, err := performance.Analyze(build)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Analysis.Recommendations) != len(want.Recommendations) {
+		t.Errorf("reported %d recommendations, want %d", len(report.Analysis.Recommendations), len(want.Recommendations))
+	}
+}
+
+func TestRenderUnknownFormat(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Render(testBuild(), nil, "pdf", &buf); err == nil {
+		t.Error("rendered an unknown format")
+	}
+	if buf.Len() > 0 {
+		t.Errorf("wrote %q for an unknown format", buf.String())
+	}
+}
+
+func TestRenderFileSummary(t *testing.T) {
+	build := testBuild()
+	build.Remarks = append(build.Remarks,
+		models.CompilerRemark{Pass: "licm", Status: "missed", Function: "bar", Location: models.Location{File: "bar.c", Line: 7}},
+		models.CompilerRemark{Pass: "licm", Status: "missed", Function: "bar", Location: models.Location{File: "bar.c", Line: 9}},
+	)
+
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{"text", []string{"Remarks by File", "Optimization hotspot: bar.c"}},
+		{"html", []string{"<h2>Remarks by File</h2>", "Optimization hotspot: <code>bar.c</code>, with 2 missed optimizations"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Render(build, nil, tt.format, &buf); err != nil {
+				t.Fatal(err)
+			}
+			out := strings.Join(strings.Fields(buf.String()), " ")
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("report lacks %q:\n%s", want, buf.String())
+				}
+			}
+		})
+	}
+}
@@ -4,9 +4,16 @@ package reporters
 import (
 	"builds/internal/analysis/performance"
 	"builds/internal/models"
+	"builds/internal/reporters/csv"
+	"builds/internal/reporters/html"
 	"builds/internal/reporters/json"
+	"builds/internal/reporters/markdown"
+	"builds/internal/reporters/sarif"
 	"builds/internal/reporters/stdout"
+	"builds/internal/reporters/template"
 	"builds/internal/reporters/text"
+	"builds/internal/reporters/yaml"
+	"fmt"
 	"io"
 )
 
@@ -22,18 +29,185 @@ type Options struct {
 	Build     *models.Build
 	Analysis  *performance.AnalysisResult
 	Writer    io.Writer
+	Explain   bool // Show the figures and thresholds behind bottlenecks
+	Precision int  // Decimals shown for durations in text reports (0 for the default)
+
+	// AllRemarks keeps analysis, metric and informational remarks, which
+	// are left out of the display, text, html and markdown reports by
+	// default. Machine-readable formats always hold every remark.
+	AllRemarks bool
+
+	// FilePrefix is a template for report file names, see ExpandFilePrefix
+	FilePrefix string
+
+	// TemplateFile is the text/template the template format executes
+	TemplateFile string
+}
+
+// streamer is implemented by file reporters that can also write their
+// primary document to an arbitrary writer
+type streamer interface {
+	GenerateTo(w io.Writer) error
 }
 
-// NewReporter creates a new reporter based on the specified format
+// writerReporter adapts a streamer to write to a fixed writer
+type writerReporter struct {
+	streamer streamer
+	w        io.Writer
+}
+
+func (r writerReporter) Generate() error {
+	return r.streamer.GenerateTo(r.w)
+}
+
+// NewReporter creates a new reporter based on the specified format. File
+// based formats write to OutputDir when it is set and to Writer otherwise.
+// Reports meant for reading hold only actionable remarks unless AllRemarks
+// is set.
 func NewReporter(opts Options) (Reporter, error) {
+	if !opts.AllRemarks && forReading(opts.Format) {
+		opts.Build = actionableRemarks(opts.Build)
+	}
+
+	newText := func(outDir string) *text.Reporter {
+		r := text.NewReporter(opts.Build, opts.Analysis, outDir)
+		r.SetExplain(opts.Explain)
+		r.SetPrecision(opts.Precision)
+		return r
+	}
+	newStdout := func() *stdout.Reporter {
+		r := stdout.NewReporter(opts.Build, opts.Analysis, opts.Writer)
+		r.SetExplain(opts.Explain)
+		r.SetPrecision(opts.Precision)
+		return r
+	}
+
+	newTemplate := func(outDir string) (*template.Reporter, error) {
+		if opts.TemplateFile == "" {
+			return nil, fmt.Errorf("the template format needs a template file")
+		}
+		tmpl, err := template.Parse(opts.TemplateFile)
+		if err != nil {
+			return nil, err
+		}
+		return template.NewReporter(opts.Build, opts.Analysis, outDir, tmpl), nil
+	}
+
+	if opts.OutputDir == "" && opts.Writer != nil {
+		switch opts.Format {
+		case "csv":
+			return writerReporter{csv.NewReporter(opts.Build, opts.Analysis, ""), opts.Writer}, nil
+		case "json":
+			return writerReporter{json.NewReporter(opts.Build, opts.Analysis, ""), opts.Writer}, nil
+		case "yaml":
+			return writerReporter{yaml.NewReporter(opts.Build, opts.Analysis, ""), opts.Writer}, nil
+		case "text":
+			return writerReporter{newText(""), opts.Writer}, nil
+		case "html":
+			return writerReporter{html.NewReporter(opts.Build, opts.Analysis, ""), opts.Writer}, nil
+		case "markdown":
+			return writerReporter{markdown.NewReporter(opts.Build, opts.Analysis, ""), opts.Writer}, nil
+		case "sarif":
+			return writerReporter{sarif.NewReporter(opts.Build, opts.Analysis, ""), opts.Writer}, nil
+		case "template":
+			r, err := newTemplate("")
+			if err != nil {
+				return nil, err
+			}
+			return writerReporter{r, opts.Writer}, nil
+		}
+	}
+
+	prefix, err := ExpandFilePrefix(opts.FilePrefix, opts.Build)
+	if err != nil {
+		return nil, err
+	}
+
+	var r interface {
+		Reporter
+		SetFilePrefix(prefix string)
+	}
 	switch opts.Format {
+	case "csv":
+		r = csv.NewReporter(opts.Build, opts.Analysis, opts.OutputDir)
 	case "json":
-		return json.NewReporter(opts.Build, opts.Analysis, opts.OutputDir), nil
+		r = json.NewReporter(opts.Build, opts.Analysis, opts.OutputDir)
+	case "yaml":
+		r = yaml.NewReporter(opts.Build, opts.Analysis, opts.OutputDir)
 	case "text":
-		return text.NewReporter(opts.Build, opts.Analysis, opts.OutputDir), nil
-	case "display", "stdout":
-		return stdout.NewReporter(opts.Build, opts.Analysis, opts.Writer), nil
+		r = newText(opts.OutputDir)
+	case "html":
+		r = html.NewReporter(opts.Build, opts.Analysis, opts.OutputDir)
+	case "markdown":
+		r = markdown.NewReporter(opts.Build, opts.Analysis, opts.OutputDir)
+	case "sarif":
+		r = sarif.NewReporter(opts.Build, opts.Analysis, opts.OutputDir)
+	case "template":
+		if r, err = newTemplate(opts.OutputDir); err != nil {
+			return nil, err
+		}
+	default:
+		return newStdout(), nil
+	}
+	r.SetFilePrefix(prefix)
+	return r, nil
+}
+
+// forReading reports whether format is read by people rather than parsed,
+// so that remarks they cannot act on are clutter. Unknown formats fall back
+// to the display.
+func forReading(format string) bool {
+	switch format {
+	case "csv", "json", "yaml", "sarif", "template":
+		return false
 	default:
-		return stdout.NewReporter(opts.Build, opts.Analysis, opts.Writer), nil
+		return true
+	}
+}
+
+// actionableRemarks returns a copy of build holding only the remarks that
+// record applied or missed optimizations
+func actionableRemarks(build *models.Build) *models.Build {
+	if build == nil {
+		return nil
+	}
+
+	filtered := *build
+	filtered.Remarks = make([]models.CompilerRemark, 0, len(build.Remarks))
+	for _, remark := range build.Remarks {
+		if remark.Actionable() {
+			filtered.Remarks = append(filtered.Remarks, remark)
+		}
+	}
+	return &filtered
+}
+
+// Render writes a report for build in format to w, without a server or
+// output directory. When analysis is nil the build is analyzed first.
+// Reports meant for reading include only actionable remarks.
+// Formats are display, text, json, yaml, csv, html, markdown and sarif.
+func Render(build *models.Build, analysis *performance.AnalysisResult, format string, w io.Writer) error {
+	switch format {
+	case "display", "stdout", "text", "json", "yaml", "csv", "html", "markdown", "sarif":
+	default:
+		return fmt.Errorf("unknown report format %q", format)
+	}
+
+	if analysis == nil {
+		var err error
+		if analysis, err = performance.Analyze(build); err != nil {
+			return fmt.Errorf("failed to analyze build: %w", err)
+		}
+	}
+
+	reporter, err := NewReporter(Options{
+		Format:   format,
+		Build:    build,
+		Analysis: analysis,
+		Writer:   w,
+	})
+	if err != nil {
+		return err
 	}
+	return reporter.Generate()
 }
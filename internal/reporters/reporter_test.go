@@ -0,0 +1,271 @@
+package reporters
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"builds/internal/analysis/performance"
+	"builds/internal/models"
+)
+
+func testBuild() *models.Build {
+	return &models.Build{
+		ID:        "b1",
+		StartTime: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		Success:   true,
+		Compiler:  models.Compiler{Name: "clang", Version: "18.1.0"},
+		Remarks: []models.CompilerRemark{
+			{
+				Pass:     "loop-vectorize",
+				Name:     "MissedDetails",
+				Status:   "missed",
+				Function: "foo",
+				Location: models.Location{File: "foo.c", Line: 3},
+			},
+		},
+	}
+}
+
+// analyzed returns a build with its analysis, as buildsctl passes them
+func analyzed(t *testing.T) (*models.Build, *performance.AnalysisResult) {
+	t.Helper()
+	build := testBuild()
+	analysis, err := performance.Analyze(build)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return build, analysis
+}
+
+var fileFormats = []string{"text", "json", "yaml", "csv", "html", "markdown", "sarif"}
+
+func TestNewReporterWritesToStdout(t *testing.T) {
+	for _, format := range fileFormats {
+		t.Run(format, func(t *testing.T) {
+			build, analysis := analyzed(t)
+			var buf bytes.Buffer
+			reporter, err := NewReporter(Options{
+				Format:   format,
+				Build:    build,
+				Analysis: analysis,
+				Writer:   &buf,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := reporter.Generate(); err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(buf.String(), "foo.c") {
+				t.Errorf("%s report on the writer lacks the remark:\n%s", format, buf.String())
+			}
+		})
+	}
+}
+
+func TestNewReporterWritesFiles(t *testing.T) {
+	for _, format := range fileFormats {
+		t.Run(format, func(t *testing.T) {
+			dir := t.TempDir()
+			build, analysis := analyzed(t)
+			var buf bytes.Buffer
+			reporter, err := NewReporter(Options{
+				OutputDir: dir,
+				Format:    format,
+				Build:     build,
+				Analysis:  analysis,
+				Writer:    &buf,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := reporter.Generate(); err != nil {
+				t.Fatal(err)
+			}
+			if buf.Len() > 0 {
+				t.Errorf("%s report also written to the writer:\n%s", format, buf.String())
+			}
+
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) == 0 {
+				t.Fatalf("no %s report written to %s", format, dir)
+			}
+			for _, entry := range entries {
+				if !strings.HasPrefix(entry.Name(), "build-b1") {
+					t.Errorf("report file %s does not start with build-b1", entry.Name())
+				}
+			}
+		})
+	}
+}
+
+func TestExplain(t *testing.T) {
+	build := testBuild()
+	build.Performance.CompileTime = 90
+	analysis, err := performance.Analyze(build)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, explain := range []bool{false, true} {
+		var buf bytes.Buffer
+		reporter, err := NewReporter(Options{
+			Format:   "text",
+			Build:    build,
+			Analysis: analysis,
+			Writer:   &buf,
+			Explain:  explain,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := reporter.Generate(); err != nil {
+			t.Fatal(err)
+		}
+		detail := "Why: compile time 90.0s (threshold 60.0s)"
+		if got := strings.Contains(buf.String(), detail); got != explain {
+			t.Errorf("with explain %v, report has the detail: %v\n%s", explain, got, buf.String())
+		}
+	}
+}
+
+func TestPhaseMemory(t *testing.T) {
+	tests := []struct {
+		name   string
+		phases map[string]int64
+		want   []string // In order
+	}{
+		{"per phase", map[string]int64{"parsing": 5 << 20, "opt and generate": 3 << 29, "setup": 1 << 20}, []string{"opt and generate:", "parsing:", "setup:"}},
+		{"overall peak only", nil, []string{"not reported, 2.0GiB peak overall"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			build := testBuild()
+			build.ResourceUsage = models.ResourceUsage{MaxMemory: 2 << 30, CPUTime: 1, PhaseMemory: tt.phases}
+			analysis, err := performance.Analyze(build)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var buf bytes.Buffer
+			reporter, err := NewReporter(Options{Format: "text", Build: build, Analysis: analysis, Writer: &buf})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := reporter.Generate(); err != nil {
+				t.Fatal(err)
+			}
+
+			report := buf.String()
+			_, section, ok := strings.Cut(report, "GC Allocation by Phase:")
+			if !ok {
+				t.Fatalf("no phase memory section:\n%s", report)
+			}
+			for _, want := range tt.want {
+				i := strings.Index(section, want)
+				if i < 0 {
+					t.Fatalf("phase memory lacks %q:\n%s", want, report)
+				}
+				section = section[i:]
+			}
+		})
+	}
+}
+
+func TestPrecision(t *testing.T) {
+	tests := []struct {
+		name      string
+		precision int
+		want      []string
+	}{
+		{"default", 0, []string{"Duration: 0.00 seconds", "Compile Time: 1.23 seconds", "Optimization Success Rate: 50.0%"}},
+		{"higher", 4, []string{"Duration: 0.0042 seconds", "Compile Time: 1.2346 seconds", "Optimization Success Rate: 50.000%"}},
+		{"lower", 1, []string{"Duration: 0.0 seconds", "Compile Time: 1.2 seconds", "Optimization Success Rate: 50%"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			build := testBuild()
+			build.Duration = 0.0042
+			build.Performance.CompileTime = 1.23456
+			build.Remarks = append(build.Remarks, models.CompilerRemark{Pass: "inline", Name: "Inlined", Status: "passed"})
+			analysis, err := performance.Analyze(build)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var buf bytes.Buffer
+			reporter, err := NewReporter(Options{Format: "text", Build: build, Analysis: analysis, Writer: &buf, Precision: tt.precision})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := reporter.Generate(); err != nil {
+				t.Fatal(err)
+			}
+
+			// Collapse the tabwriter's padding
+			report := strings.Join(strings.Fields(buf.String()), " ")
+			for _, want := range tt.want {
+				if !strings.Contains(report, want) {
+					t.Errorf("report lacks %q:\n%s", want, buf.String())
+				}
+			}
+		})
+	}
+}
+
+func TestNewReporterHidesAnalysisRemarks(t *testing.T) {
+	build := testBuild()
+	build.Remarks = append(build.Remarks,
+		models.CompilerRemark{Pass: "loop-vectorize", Status: "analysis", Location: models.Location{File: "analysis.c"}},
+		models.CompilerRemark{Pass: "size-info", Location: models.Location{File: "metric.c"}},
+	)
+
+	tests := []struct {
+		name   string
+		format string
+		all    bool
+		shown  []string
+		not    []string
+	}{
+		{"default", "markdown", false, []string{"foo.c"}, []string{"analysis.c", "metric.c"}},
+		{"all remarks", "markdown", true, []string{"foo.c", "analysis.c", "metric.c"}, nil},
+		{"machine-readable", "json", false, []string{"foo.c", "analysis.c", "metric.c"}, nil},
+		{"sarif", "sarif", false, []string{"foo.c", "analysis.c", "metric.c"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			reporter, err := NewReporter(Options{
+				Format:     tt.format,
+				Build:      build,
+				Writer:     &buf,
+				AllRemarks: tt.all,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := reporter.Generate(); err != nil {
+				t.Fatal(err)
+			}
+			for _, file := range tt.shown {
+				if !strings.Contains(buf.String(), file) {
+					t.Errorf("remark in %s hidden", file)
+				}
+			}
+			for _, file := range tt.not {
+				if strings.Contains(buf.String(), file) {
+					t.Errorf("remark in %s shown", file)
+				}
+			}
+		})
+	}
+	// The caller's build is left alone
+	if len(build.Remarks) != 3 {
+		t.Errorf("build has %d remarks after reporting, want 3", len(build.Remarks))
+	}
+}
@@ -0,0 +1,247 @@
+// internal/reporters/sarif/reporter.go
+package sarif
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"builds/internal/analysis/performance"
+	"builds/internal/models"
+	"builds/internal/reporters/filename"
+)
+
+const (
+	version = "2.1.0"
+	schema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+// Reporter writes compiler remarks as a SARIF 2.1.0 log, the format
+// consumed by code scanning tools such as GitHub's
+type Reporter struct {
+	filename.Prefix
+	build    *models.Build
+	analysis *performance.AnalysisResult
+	outDir   string
+}
+
+func NewReporter(build *models.Build, analysis *performance.AnalysisResult, outDir string) *Reporter {
+	return &Reporter{
+		build:    build,
+		analysis: analysis,
+		outDir:   outDir,
+	}
+}
+
+func (r *Reporter) Generate() error {
+	if err := os.MkdirAll(r.outDir, 0755); err != nil {
+		return fmt.Errorf("creating output directory: %w", err)
+	}
+
+	file, err := os.Create(filepath.Join(r.outDir, r.FilePrefix(r.build.ID)+".sarif"))
+	if err != nil {
+		return fmt.Errorf("creating report: %w", err)
+	}
+	defer file.Close()
+
+	if err := r.GenerateTo(file); err != nil {
+		return fmt.Errorf("writing report: %w", err)
+	}
+	return nil
+}
+
+// GenerateTo writes the SARIF log to w instead of a file
+func (r *Reporter) GenerateTo(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r.Log())
+}
+
+type Log struct {
+	Schema  string `json:"$schema"`
+	Version string `json:"version"`
+	Runs    []Run  `json:"runs"`
+}
+
+type Run struct {
+	Tool    Tool     `json:"tool"`
+	Results []Result `json:"results"`
+	// Resolves relative artifact URIs against the build's working directory
+	OriginalURIBaseIDs map[string]ArtifactLocation `json:"originalUriBaseIds,omitempty"`
+}
+
+type Tool struct {
+	Driver Driver `json:"driver"`
+}
+
+type Driver struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	Rules   []Rule `json:"rules"`
+}
+
+type Rule struct {
+	ID               string  `json:"id"`
+	ShortDescription Message `json:"shortDescription"`
+}
+
+type Result struct {
+	RuleID              string            `json:"ruleId"`
+	RuleIndex           int               `json:"ruleIndex"`
+	Level               string            `json:"level"`
+	Message             Message           `json:"message"`
+	Locations           []Location        `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints,omitempty"`
+	Properties          map[string]string `json:"properties,omitempty"`
+}
+
+type Message struct {
+	Text string `json:"text"`
+}
+
+type Location struct {
+	PhysicalLocation PhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []LogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type PhysicalLocation struct {
+	ArtifactLocation ArtifactLocation `json:"artifactLocation"`
+	Region           *Region          `json:"region,omitempty"`
+}
+
+type ArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type Region struct {
+	StartLine   int32 `json:"startLine"`
+	StartColumn int32 `json:"startColumn,omitempty"`
+}
+
+type LogicalLocation struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+}
+
+// srcRoot is the base ID of paths relative to the build's working directory
+const srcRoot = "SRCROOT"
+
+// Log converts the build's remarks to a SARIF log with one run. Each pass
+// becomes a rule, and remarks become results carrying the remark name as a
+// property. Remarks without a source file are left out, since code scanning
+// rejects results without a location.
+func (r *Reporter) Log() *Log {
+	compiler := r.build.Compiler
+	driver := Driver{Name: compiler.Name, Version: compiler.Version, Rules: []Rule{}}
+	if driver.Name == "" {
+		driver.Name = "builds"
+	}
+
+	run := Run{Results: []Result{}}
+	workDir := r.build.Environment.WorkingDir
+	if workDir != "" {
+		run.OriginalURIBaseIDs = map[string]ArtifactLocation{
+			srcRoot: {URI: fileURI(workDir + "/")},
+		}
+	}
+
+	rules := make(map[string]int)
+	for _, remark := range r.build.Remarks {
+		if remark.Location.File == "" {
+			continue
+		}
+
+		ruleID, description := rule(remark)
+		index, ok := rules[ruleID]
+		if !ok {
+			index = len(driver.Rules)
+			rules[ruleID] = index
+			driver.Rules = append(driver.Rules, Rule{
+				ID:               ruleID,
+				ShortDescription: Message{Text: description},
+			})
+		}
+
+		result := Result{
+			RuleID:    ruleID,
+			RuleIndex: index,
+			Level:     level(remark.Status),
+			Message:   Message{Text: remark.Message},
+			Locations: []Location{location(remark, workDir)},
+		}
+		if result.Message.Text == "" {
+			result.Message.Text = remark.Name
+		}
+		if remark.ID != "" {
+			result.PartialFingerprints = map[string]string{"remarkId/v1": remark.ID}
+		}
+		if remark.Name != "" {
+			result.Properties = map[string]string{"remarkName": remark.Name}
+		}
+		run.Results = append(run.Results, result)
+	}
+
+	run.Tool = Tool{Driver: driver}
+	return &Log{Schema: schema, Version: version, Runs: []Run{run}}
+}
+
+// rule identifies the kind of a remark by its pass, such as loop-vectorize,
+// and describes it. Names vary with the compiler version, so they would
+// split one pass into rules that come and go between builds.
+func rule(remark models.CompilerRemark) (id, description string) {
+	pass := remark.Pass
+	if pass == "" {
+		pass = "unknown"
+	}
+	return pass, fmt.Sprintf("Optimization remarks of the %s pass", pass)
+}
+
+// level maps a remark status to a SARIF result level. Missed
+// optimizations are worth acting on; everything else is informational.
+func level(status string) string {
+	if strings.EqualFold(status, "missed") {
+		return "warning"
+	}
+	return "note"
+}
+
+func location(remark models.CompilerRemark, workDir string) Location {
+	loc := Location{PhysicalLocation: PhysicalLocation{ArtifactLocation: artifact(remark.Location.File, workDir)}}
+	if remark.Location.Line > 0 {
+		loc.PhysicalLocation.Region = &Region{StartLine: remark.Location.Line}
+		if remark.Location.Column > 0 {
+			loc.PhysicalLocation.Region.StartColumn = remark.Location.Column
+		}
+	}
+	if remark.Function != "" {
+		loc.LogicalLocations = []LogicalLocation{{Name: remark.Function, Kind: "function"}}
+	}
+	return loc
+}
+
+// artifact locates file relative to the working directory when it lies
+// inside it, so code scanning can match it to the repository
+func artifact(file, workDir string) ArtifactLocation {
+	if !filepath.IsAbs(file) {
+		if workDir == "" {
+			return ArtifactLocation{URI: filepath.ToSlash(file)}
+		}
+		return ArtifactLocation{URI: filepath.ToSlash(filepath.Clean(file)), URIBaseID: srcRoot}
+	}
+	if workDir != "" {
+		if rel, err := filepath.Rel(workDir, file); err == nil && !strings.HasPrefix(rel, "..") {
+			return ArtifactLocation{URI: filepath.ToSlash(rel), URIBaseID: srcRoot}
+		}
+	}
+	return ArtifactLocation{URI: fileURI(file)}
+}
+
+func fileURI(path string) string {
+	u := url.URL{Scheme: "file", Path: filepath.ToSlash(path)}
+	return u.String()
+}
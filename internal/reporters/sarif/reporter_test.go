@@ -0,0 +1,57 @@
+package sarif
+
+import (
+	"testing"
+
+	"builds/internal/models"
+)
+
+func TestLogRules(t *testing.T) {
+	build := &models.Build{
+		ID: "b1",
+		Remarks: []models.CompilerRemark{
+			{Pass: "loop-vectorize", Name: "MissedDetails", Status: "missed", Location: models.Location{File: "a.c", Line: 3}},
+			{Pass: "loop-vectorize", Name: "Vectorized", Status: "passed", Location: models.Location{File: "a.c", Line: 9}},
+			{Pass: "loop-vectorize", Name: "MissedDetails", Status: "missed", Location: models.Location{File: "b.c", Line: 1}},
+			{Pass: "inline", Status: "passed", Location: models.Location{File: "b.c", Line: 2}},
+			{Pass: "inline", Name: "Inlined", Status: "passed"}, // No location
+		},
+	}
+
+	run := NewReporter(build, nil, "").Log().Runs[0]
+
+	var rules []string
+	for _, rule := range run.Tool.Driver.Rules {
+		rules = append(rules, rule.ID)
+	}
+	want := []string{"loop-vectorize", "inline"}
+	if len(rules) != len(want) {
+		t.Fatalf("rules %v, want %v", rules, want)
+	}
+	for i := range want {
+		if rules[i] != want[i] {
+			t.Errorf("rule %d is %q, want %q", i, rules[i], want[i])
+		}
+	}
+
+	if len(run.Results) != 4 {
+		t.Fatalf("got %d results, want 4", len(run.Results))
+	}
+	wantIndex := []int{0, 0, 0, 1}
+	for i, result := range run.Results {
+		if result.RuleIndex != wantIndex[i] || result.RuleID != want[wantIndex[i]] {
+			t.Errorf("result %d has rule %q at %d", i, result.RuleID, result.RuleIndex)
+		}
+	}
+	if run.Results[0].Level != "warning" || run.Results[1].Level != "note" {
+		t.Errorf("levels %q and %q, want warning and note", run.Results[0].Level, run.Results[1].Level)
+	}
+
+	// The remark name moves to the result
+	wantNames := []string{"MissedDetails", "Vectorized", "MissedDetails", ""}
+	for i, result := range run.Results {
+		if name := result.Properties["remarkName"]; name != wantNames[i] {
+			t.Errorf("result %d has remark name %q, want %q", i, name, wantNames[i])
+		}
+	}
+}
@@ -0,0 +1,125 @@
+// internal/reporters/stats/stats.go
+
+package stats
+
+import (
+	"sort"
+	"strings"
+
+	"builds/internal/models"
+)
+
+// Remarks summarises a build's remarks for reports
+type Remarks struct {
+	TotalRemarks  int
+	ByType        map[string]int
+	ByPass        map[string]int
+	ByFunction    map[string]int
+	PassOutcomes  map[string]Outcomes // Passed and missed remarks of each pass
+	Optimizations struct {
+		Passed int
+		Missed int
+		Total  int
+	}
+	InliningStats struct {
+		Successful int
+		Failed     int
+		Total      int
+	}
+	KernelStats struct {
+		TotalAccesses    int
+		TotalThreadLimit int
+		TotalDirectCalls int
+		TotalAllocas     int
+	}
+}
+
+// Outcomes counts the remarks of a pass by whether the optimization applied
+type Outcomes struct {
+	Passed int
+	Missed int
+}
+
+// Count is a key of a distribution with its count
+type Count struct {
+	Key   string
+	Value int
+}
+
+// CalculateRemarks computes the statistics of a set of remarks
+func CalculateRemarks(remarks []models.CompilerRemark) Remarks {
+	stats := Remarks{
+		ByType:       make(map[string]int),
+		ByPass:       make(map[string]int),
+		ByFunction:   make(map[string]int),
+		PassOutcomes: make(map[string]Outcomes),
+	}
+
+	for _, remark := range remarks {
+		stats.TotalRemarks++
+		stats.ByType[remark.Type]++
+		stats.ByPass[remark.Pass]++
+		if remark.Function != "" {
+			stats.ByFunction[remark.Function]++
+		}
+
+		// Track optimization statistics. Whether an optimization applied is
+		// in the status; the type only says what kind of remark it is.
+		passed := strings.EqualFold(remark.Status, string(models.RemarkStatusPassed))
+		missed := strings.EqualFold(remark.Status, string(models.RemarkStatusMissed))
+		outcomes := stats.PassOutcomes[remark.Pass]
+		switch {
+		case passed:
+			stats.Optimizations.Passed++
+			stats.Optimizations.Total++
+			outcomes.Passed++
+		case missed:
+			stats.Optimizations.Missed++
+			stats.Optimizations.Total++
+			outcomes.Missed++
+		}
+		stats.PassOutcomes[remark.Pass] = outcomes
+
+		// Track inlining statistics. The parser names the pass "inline" and
+		// builds read back from the server name it "inlining".
+		if IsInliningPass(remark.Pass) && (passed || missed) {
+			stats.InliningStats.Total++
+			if passed {
+				stats.InliningStats.Successful++
+			} else {
+				stats.InliningStats.Failed++
+			}
+		}
+
+		// Track kernel statistics
+		if remark.KernelInfo != nil {
+			stats.KernelStats.TotalAccesses += len(remark.KernelInfo.MemoryAccesses)
+			stats.KernelStats.TotalThreadLimit += int(remark.KernelInfo.ThreadLimit)
+			stats.KernelStats.TotalDirectCalls += int(remark.KernelInfo.DirectCalls)
+			stats.KernelStats.TotalAllocas += int(remark.KernelInfo.AllocasCount)
+		}
+	}
+
+	return stats
+}
+
+// IsInliningPass reports whether a remark came from the inliner
+func IsInliningPass(pass string) bool {
+	return strings.EqualFold(pass, "inline") || strings.EqualFold(pass, "inlining")
+}
+
+// Sorted returns a distribution with the largest counts first, ties in key
+// order
+func Sorted(m map[string]int) []Count {
+	counts := make([]Count, 0, len(m))
+	for key, value := range m {
+		counts = append(counts, Count{Key: key, Value: value})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Value != counts[j].Value {
+			return counts[i].Value > counts[j].Value
+		}
+		return counts[i].Key < counts[j].Key
+	})
+	return counts
+}
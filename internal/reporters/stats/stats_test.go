@@ -0,0 +1,57 @@
+package stats
+
+import (
+	"reflect"
+	"testing"
+
+	"builds/internal/models"
+)
+
+func TestCalculateRemarks(t *testing.T) {
+	remarks := []models.CompilerRemark{
+		// The parser lowercases statuses; other sources may not
+		{Type: "passed", Status: "passed", Pass: "inline", Function: "main"},
+		{Type: "missed", Status: "missed", Pass: "inline", Function: "main"},
+		{Type: "passed", Status: "Passed", Pass: "inlining", Function: "foo"},
+		{Type: "passed", Status: "passed", Pass: "loop-vectorize", Function: "foo"},
+		{Type: "missed", Status: "MISSED", Pass: "loop-vectorize", Function: "foo"},
+		{Type: "missed", Status: "missed", Pass: "loop-vectorize"},
+		// Analysis remarks are neither passed nor missed
+		{Type: "analysis", Status: "analysis", Pass: "loop-vectorize", Function: "foo"},
+		{Type: "analysis", Status: "analysis", Pass: "inline", Function: "main"},
+	}
+
+	stats := CalculateRemarks(remarks)
+	if stats.TotalRemarks != 8 {
+		t.Errorf("TotalRemarks = %d, want 8", stats.TotalRemarks)
+	}
+	if o := stats.Optimizations; o.Passed != 3 || o.Missed != 3 || o.Total != 6 {
+		t.Errorf("optimizations %+v, want 3 passed and 3 missed of 6", o)
+	}
+	if rate := float64(stats.Optimizations.Passed) / float64(stats.Optimizations.Total); rate != 0.5 {
+		t.Errorf("success rate %v, want 0.5", rate)
+	}
+	if i := stats.InliningStats; i.Successful != 2 || i.Failed != 1 || i.Total != 3 {
+		t.Errorf("inlining %+v, want 2 successful and 1 failed of 3", i)
+	}
+
+	wantOutcomes := map[string]Outcomes{
+		"inline":         {Passed: 1, Missed: 1},
+		"inlining":       {Passed: 1},
+		"loop-vectorize": {Passed: 1, Missed: 2},
+	}
+	if !reflect.DeepEqual(stats.PassOutcomes, wantOutcomes) {
+		t.Errorf("PassOutcomes = %v, want %v", stats.PassOutcomes, wantOutcomes)
+	}
+	if stats.ByFunction["foo"] != 4 || stats.ByFunction["main"] != 3 || len(stats.ByFunction) != 2 {
+		t.Errorf("ByFunction = %v", stats.ByFunction)
+	}
+}
+
+func TestSorted(t *testing.T) {
+	got := Sorted(map[string]int{"b": 2, "a": 2, "c": 5, "d": 1})
+	want := []Count{{"c", 5}, {"a", 2}, {"b", 2}, {"d", 1}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -13,9 +13,11 @@ import (
 )
 
 type Reporter struct {
-	build    *models.Build
-	analysis *performance.AnalysisResult
-	writer io.Writer
+	build     *models.Build
+	analysis  *performance.AnalysisResult
+	writer    io.Writer
+	explain   bool
+	precision int
 }
 
 func NewReporter(build *models.Build, analysis *performance.AnalysisResult, writer io.Writer) *Reporter {
@@ -25,15 +27,27 @@ func NewReporter(build *models.Build, analysis *performance.AnalysisResult, writ
 	return &Reporter{
 		build:    build,
 		analysis: analysis,
-		writer: writer,
+		writer:   writer,
 	}
 }
 
+// SetExplain makes the report show the figures behind each bottleneck
+func (r *Reporter) SetExplain(explain bool) {
+	r.explain = explain
+}
+
+// SetPrecision sets the decimals shown for durations, see text.Reporter
+func (r *Reporter) SetPrecision(decimals int) {
+	r.precision = decimals
+}
+
 func (r *Reporter) Generate() error {
 	w := tabwriter.NewWriter(r.writer, 0, 0, 2, ' ', 0)
 	defer w.Flush()
 
 	// Reuse the text reporter
 	reporter := text.NewReporter(r.build, r.analysis, "")
+	reporter.SetExplain(r.explain)
+	reporter.SetPrecision(r.precision)
 	return reporter.GenerateToWriter(w)
 }
@@ -0,0 +1,114 @@
+// internal/reporters/template/reporter.go
+package template
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"builds/internal/analysis/performance"
+	"builds/internal/models"
+	"builds/internal/reporters/filename"
+	"builds/internal/utils/units"
+)
+
+// Data is what user templates are executed against
+type Data struct {
+	Build    *models.Build
+	Analysis *performance.AnalysisResult
+}
+
+// Funcs are the helpers available to user templates in addition to the
+// text/template builtins
+var Funcs = texttemplate.FuncMap{
+	"formatBytes": units.FormatBytes,
+	"seconds": func(v float64) string {
+		return fmt.Sprintf("%.2f", v)
+	},
+	"percent": func(ratio float64) string {
+		return fmt.Sprintf("%.1f%%", ratio*100)
+	},
+	"duration": func(seconds float64) time.Duration {
+		return time.Duration(seconds * float64(time.Second)).Round(time.Millisecond)
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"join":  strings.Join,
+}
+
+// Parse loads and validates the template in path. Templates fail on
+// undefined map keys so typos are reported instead of rendering "<no value>".
+func Parse(path string) (*texttemplate.Template, error) {
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading template: %w", err)
+	}
+
+	t, err := texttemplate.New(filepath.Base(path)).
+		Funcs(Funcs).
+		Option("missingkey=error").
+		Parse(string(source))
+	if err != nil {
+		return nil, fmt.Errorf("invalid template %s: %w", path, err)
+	}
+	return t, nil
+}
+
+type Reporter struct {
+	filename.Prefix
+	build    *models.Build
+	analysis *performance.AnalysisResult
+	outDir   string
+	tmpl     *texttemplate.Template
+}
+
+func NewReporter(build *models.Build, analysis *performance.AnalysisResult, outDir string, tmpl *texttemplate.Template) *Reporter {
+	return &Reporter{
+		build:    build,
+		analysis: analysis,
+		outDir:   outDir,
+		tmpl:     tmpl,
+	}
+}
+
+// Generate writes the report to <prefix>-<template name>, dropping a
+// trailing .tmpl so dashboard.html.tmpl produces build-<id>-dashboard.html
+func (r *Reporter) Generate() error {
+	if err := os.MkdirAll(r.outDir, 0755); err != nil {
+		return fmt.Errorf("creating output directory: %w", err)
+	}
+
+	// Render first so a failing template does not leave a partial file
+	var buf bytes.Buffer
+	if err := r.GenerateTo(&buf); err != nil {
+		return err
+	}
+
+	name := strings.TrimSuffix(r.tmpl.Name(), ".tmpl")
+	reportPath := filepath.Join(r.outDir, r.FilePrefix(r.build.ID)+"-"+name)
+	if err := os.WriteFile(reportPath, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("writing report: %w", err)
+	}
+	return nil
+}
+
+// GenerateTo writes the report to w instead of a file. Nothing is written
+// when the template fails part way through.
+func (r *Reporter) GenerateTo(w io.Writer) error {
+	analysis := r.analysis
+	if analysis == nil {
+		analysis = &performance.AnalysisResult{}
+	}
+
+	var buf bytes.Buffer
+	if err := r.tmpl.Execute(&buf, Data{Build: r.build, Analysis: analysis}); err != nil {
+		return fmt.Errorf("executing template: %w", err)
+	}
+	_, err := buf.WriteTo(w)
+	return err
+}
@@ -0,0 +1,127 @@
+package reporters
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeTemplate writes a template file named name and returns its path
+func writeTemplate(t *testing.T, name, source string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(source), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+const summaryTemplate = `{{.Build.ID}} {{upper .Build.Compiler.Name}} {{.Build.Compiler.Version}}
+{{range .Build.Remarks}}{{.Location.File}}:{{.Location.Line}} {{.Status}}
+{{end}}peak {{formatBytes .Build.ResourceUsage.MaxMemory}} in {{seconds .Build.Duration}}s`
+
+func TestTemplateFormat(t *testing.T) {
+	build, analysis := analyzed(t)
+	build.Duration = 1.5
+	build.ResourceUsage.MaxMemory = 3 << 20
+	want := "b1 CLANG 18.1.0\nfoo.c:3 missed\npeak 3.0MiB in 1.50s"
+
+	t.Run("writer", func(t *testing.T) {
+		var buf bytes.Buffer
+		reporter, err := NewReporter(Options{
+			Format:       "template",
+			TemplateFile: writeTemplate(t, "summary.txt.tmpl", summaryTemplate),
+			Build:        build,
+			Analysis:     analysis,
+			Writer:       &buf,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := reporter.Generate(); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != want {
+			t.Errorf("got %q, want %q", buf.String(), want)
+		}
+	})
+
+	t.Run("file", func(t *testing.T) {
+		dir := t.TempDir()
+		reporter, err := NewReporter(Options{
+			OutputDir:    dir,
+			Format:       "template",
+			TemplateFile: writeTemplate(t, "summary.txt.tmpl", summaryTemplate),
+			Build:        build,
+			Analysis:     analysis,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := reporter.Generate(); err != nil {
+			t.Fatal(err)
+		}
+		got, err := os.ReadFile(filepath.Join(dir, "build-b1-summary.txt"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	})
+}
+
+func TestTemplateErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string // Empty for no template file
+	}{
+		{"no template file", ""},
+		{"syntax error", "{{.Build.ID"},
+		{"unknown function", "{{bogus .Build.ID}}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			build, analysis := analyzed(t)
+			opts := Options{Format: "template", Build: build, Analysis: analysis, Writer: &bytes.Buffer{}}
+			if tt.source != "" {
+				opts.TemplateFile = writeTemplate(t, "bad.tmpl", tt.source)
+			}
+			if _, err := NewReporter(opts); err == nil {
+				t.Error("accepted the template")
+			}
+		})
+	}
+}
+
+func TestTemplateFailsWithoutPartialOutput(t *testing.T) {
+	build, analysis := analyzed(t)
+	dir := t.TempDir()
+	var buf bytes.Buffer
+
+	// The ID renders before the missing label fails the template
+	source := `{{.Build.ID}} {{.Build.Labels.missing}}`
+	for _, outputDir := range []string{"", dir} {
+		reporter, err := NewReporter(Options{
+			OutputDir:    outputDir,
+			Format:       "template",
+			TemplateFile: writeTemplate(t, "labels.tmpl", source),
+			Build:        build,
+			Analysis:     analysis,
+			Writer:       &buf,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := reporter.Generate(); err == nil {
+			t.Errorf("template with a missing key succeeded in %q", outputDir)
+		}
+	}
+	if buf.Len() > 0 {
+		t.Errorf("wrote %q before failing", buf.String())
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) > 0 {
+		t.Errorf("left %s behind", entries[0].Name())
+	}
+}
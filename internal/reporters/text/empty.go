@@ -0,0 +1,77 @@
+// internal/reporters/text/empty.go
+
+package text
+
+import (
+	"fmt"
+	"text/tabwriter"
+)
+
+// noData marks a section the build carries nothing for, so it is not
+// mistaken for a build that measured zeros
+func noData(w *tabwriter.Writer) error {
+	fmt.Fprintf(w, "No data collected\n")
+	return nil
+}
+
+func (r *Reporter) hasEnvironment() bool {
+	env := r.build.Environment
+	return env.OS != "" || env.Arch != "" || env.WorkingDir != "" ||
+		len(env.Variables) > 0 || len(env.Locale) > 0
+}
+
+func (r *Reporter) hasHardware() bool {
+	hw := r.build.Hardware
+	return hw.CPU.Model != "" || hw.CPU.Cores > 0 || hw.Memory.Total > 0 || len(hw.GPUs) > 0
+}
+
+func (r *Reporter) hasCompiler() bool {
+	comp := r.build.Compiler
+	return comp.Name != "" || comp.Version != "" || comp.VersionString != ""
+}
+
+func (r *Reporter) hasCommand() bool {
+	cmd := r.build.Command
+	return cmd.Executable != "" || len(cmd.Arguments) > 0 || len(cmd.Invocations) > 0
+}
+
+func (r *Reporter) hasOutput() bool {
+	out := r.build.Output
+	return out.ExitCode != 0 || out.Stdout != "" || out.Stderr != "" ||
+		len(out.Diagnostics) > 0 || len(out.Artifacts) > 0
+}
+
+func (r *Reporter) hasResourceUsage() bool {
+	usage := r.build.ResourceUsage
+	return usage.MaxMemory > 0 || usage.CPUTime > 0 || usage.Threads > 0
+}
+
+func (r *Reporter) hasPerformance() bool {
+	perf := r.build.Performance
+	return perf.CompileTime > 0 || perf.LinkTime > 0 || perf.OptimizeTime > 0 || len(perf.Phases) > 0
+}
+
+// hasAnalysis reports whether the analysis found anything. Analyzing a
+// build without measurements still fills the profiles, with zeros.
+func (r *Reporter) hasAnalysis() bool {
+	a := r.analysis
+	if a.ResourceEfficiency != 0 {
+		return true
+	}
+	for _, v := range a.MemoryUsageProfile {
+		if v != 0 {
+			return true
+		}
+	}
+	for _, v := range a.CompilationOverhead {
+		if v != 0 {
+			return true
+		}
+	}
+	for _, v := range a.OptimizationMetrics {
+		if v != 0 {
+			return true
+		}
+	}
+	return false
+}
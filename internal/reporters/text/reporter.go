@@ -3,71 +3,97 @@ package text
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"text/tabwriter"
 	"time"
 
 	"builds/internal/analysis/performance"
 	"builds/internal/models"
+	"builds/internal/reporters/filename"
+	"builds/internal/reporters/stats"
+	"builds/internal/utils/units"
 )
 
 type Reporter struct {
+	filename.Prefix
 	build    *models.Build
 	analysis *performance.AnalysisResult
 	outDir   string
-}
+	explain  bool
 
-type remarkStats struct {
-	TotalRemarks  int
-	ByType        map[string]int
-	ByPass        map[string]int
-	ByFunction    map[string]int
-	Optimizations struct {
-		Passed int
-		Missed int
-		Total  int
-	}
-	InliningStats struct {
-		Successful int
-		Failed     int
-		Total      int
-	}
-	KernelStats struct {
-		TotalAccesses    int
-		TotalThreadLimit int
-		TotalDirectCalls int
-		TotalAllocas     int
-	}
+	// Decimals shown for durations; percentages use one fewer
+	precision int
 }
 
+// DefaultPrecision is the number of decimals shown for durations
+const DefaultPrecision = 2
+
 func NewReporter(build *models.Build, analysis *performance.AnalysisResult, outDir string) *Reporter {
 	return &Reporter{
-		build:    build,
-		analysis: analysis,
-		outDir:   outDir,
+		build:     build,
+		analysis:  analysis,
+		outDir:    outDir,
+		precision: DefaultPrecision,
+	}
+}
+
+// SetPrecision sets the decimals shown for durations, for scripts parsing
+// the report or to keep sub-second compiles from rounding to zero.
+// Percentages are shown with one decimal fewer.
+func (r *Reporter) SetPrecision(decimals int) {
+	if decimals > 0 {
+		r.precision = decimals
 	}
 }
 
+// seconds formats a duration in seconds with the configured precision
+func (r *Reporter) seconds(v float64) string {
+	return strconv.FormatFloat(v, 'f', r.precision, 64)
+}
+
+// percent formats a percentage with one decimal fewer than durations
+func (r *Reporter) percent(v float64) string {
+	return strconv.FormatFloat(v, 'f', r.precision-1, 64)
+}
+
+// SetExplain makes the report show the figures and thresholds behind
+// each bottleneck
+func (r *Reporter) SetExplain(explain bool) {
+	r.explain = explain
+}
+
 func (r *Reporter) Generate() error {
 	if err := os.MkdirAll(r.outDir, 0755); err != nil {
 		return fmt.Errorf("creating output directory: %w", err)
 	}
 
-	reportPath := filepath.Join(r.outDir, fmt.Sprintf("build-%s.txt", r.build.ID))
+	reportPath := filepath.Join(r.outDir, r.FilePrefix(r.build.ID)+".txt")
 	file, err := os.Create(reportPath)
 	if err != nil {
 		return fmt.Errorf("creating report file: %w", err)
 	}
 	defer file.Close()
 
-	w := tabwriter.NewWriter(file, 0, 0, 2, ' ', 0)
+	return r.GenerateTo(file)
+}
+
+// GenerateTo writes the report to out instead of a file
+func (r *Reporter) GenerateTo(out io.Writer) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
 	return r.GenerateToWriter(w)
 }
 
 func (r *Reporter) GenerateToWriter(w *tabwriter.Writer) error {
+	// Builds that could not be analyzed still get the collected sections
+	if r.analysis == nil {
+		r.analysis = &performance.AnalysisResult{}
+	}
+
 	// Generate each section
 	sections := []func(*tabwriter.Writer) error{
 		r.generateBuildSummary,
@@ -78,8 +104,13 @@ func (r *Reporter) GenerateToWriter(w *tabwriter.Writer) error {
 		r.generateOutputInfo,
 		r.generateResourceUsage,
 		r.generatePerformanceInfo,
+		r.generateLinkMap,
 		r.generateAnalysisResults,
 		r.generateOptimizationRemarks,
+		r.generateFileSummary,
+		r.generateRemarkHeatmap,
+		r.generateRegisterSpills,
+		r.generateMissedReasons,
 		r.generateBottlenecks,
 	}
 
@@ -102,7 +133,7 @@ func (r *Reporter) generateOptimizationRemarks(w *tabwriter.Writer) error {
 	fmt.Fprintf(w, "===========================\n\n")
 
 	// Calculate statistics
-	stats := r.calculateRemarkStats()
+	stats := stats.CalculateRemarks(r.build.Remarks)
 
 	// Print Summary Statistics
 	fmt.Fprintf(w, "Summary Statistics\n")
@@ -111,14 +142,14 @@ func (r *Reporter) generateOptimizationRemarks(w *tabwriter.Writer) error {
 
 	if stats.Optimizations.Total > 0 {
 		successRate := float64(stats.Optimizations.Passed) / float64(stats.Optimizations.Total) * 100
-		fmt.Fprintf(w, "Optimization Success Rate:\t%.1f%% (%d/%d)\n",
-			successRate, stats.Optimizations.Passed, stats.Optimizations.Total)
+		fmt.Fprintf(w, "Optimization Success Rate:\t%s%% (%d/%d)\n",
+			r.percent(successRate), stats.Optimizations.Passed, stats.Optimizations.Total)
 	}
 
 	if stats.InliningStats.Total > 0 {
 		inlineRate := float64(stats.InliningStats.Successful) / float64(stats.InliningStats.Total) * 100
-		fmt.Fprintf(w, "Inlining Success Rate:\t%.1f%% (%d/%d)\n",
-			inlineRate, stats.InliningStats.Successful, stats.InliningStats.Total)
+		fmt.Fprintf(w, "Inlining Success Rate:\t%s%% (%d/%d)\n",
+			r.percent(inlineRate), stats.InliningStats.Successful, stats.InliningStats.Total)
 	}
 
 	// Print Distribution by Type
@@ -163,6 +194,123 @@ func (r *Reporter) generateOptimizationRemarks(w *tabwriter.Writer) error {
 	return nil
 }
 
+func (r *Reporter) generateFileSummary(w *tabwriter.Writer) error {
+	// A single file adds nothing to the overall remark counts
+	if len(r.analysis.Files) < 2 {
+		return nil
+	}
+
+	fmt.Fprintf(w, "Remarks by File\n")
+	fmt.Fprintf(w, "===============\n")
+
+	const limit = 10
+	for i, file := range r.analysis.Files {
+		if i >= limit {
+			fmt.Fprintf(w, "  ... and %d more files\n", len(r.analysis.Files)-limit)
+			break
+		}
+		fmt.Fprintf(w, "  %s:\t%d remarks\t%d passed\t%d missed (%s%%)\t%d functions\n",
+			file.File, file.Remarks, file.Passed, file.Missed, r.percent(file.Share*100), file.Functions)
+	}
+	if worst := r.analysis.Files[0]; worst.Missed > 0 {
+		fmt.Fprintf(w, "Optimization hotspot:\t%s\n", worst.File)
+	}
+	return nil
+}
+
+func (r *Reporter) generateRemarkHeatmap(w *tabwriter.Writer) error {
+	if len(r.analysis.RemarkHeatmap) == 0 {
+		return nil
+	}
+
+	fmt.Fprintf(w, "Remark Heatmap\n")
+	fmt.Fprintf(w, "==============\n")
+
+	const limit = 10
+	for i, spot := range r.analysis.RemarkHeatmap {
+		if i >= limit {
+			break
+		}
+		fmt.Fprintf(w, "  %s:%d-%d:\t%d remarks\t%d missed (%s%%)\n",
+			spot.File, spot.StartLine, spot.EndLine,
+			spot.Remarks, spot.Missed, r.percent(spot.Share*100))
+	}
+	return nil
+}
+
+func (r *Reporter) generateRegisterSpills(w *tabwriter.Writer) error {
+	if len(r.analysis.RegisterSpills) == 0 {
+		return nil
+	}
+
+	fmt.Fprintf(w, "Register Spills\n")
+	fmt.Fprintf(w, "===============\n")
+
+	const limit = 10
+	for i, fn := range r.analysis.RegisterSpills {
+		if i >= limit {
+			break
+		}
+		fmt.Fprintf(w, "  %s:\t%d spills\t%d stores\t%d loads\n",
+			fn.Function, fn.Total(), fn.Stores, fn.Loads)
+	}
+	return nil
+}
+
+// generateLinkMap ranks the sections and symbols taking the most space in
+// the linked binary, for builds that collected a linker map
+func (r *Reporter) generateLinkMap(w *tabwriter.Writer) error {
+	m := r.build.LinkMap
+	if m == nil {
+		return nil
+	}
+
+	fmt.Fprintf(w, "Binary Size\n")
+	fmt.Fprintf(w, "===========\n")
+
+	const limit = 10
+	fmt.Fprintf(w, "Largest Sections:\n")
+	for i, section := range m.Sections {
+		if i >= limit {
+			fmt.Fprintf(w, "  ... and %d more sections\n", len(m.Sections)-limit)
+			break
+		}
+		fmt.Fprintf(w, "  %s:\t%s\n", section.Name, units.FormatBytes(section.Size))
+	}
+
+	fmt.Fprintf(w, "\nLargest Symbols:\n")
+	for i, sym := range m.Symbols {
+		if i >= limit {
+			break
+		}
+		fmt.Fprintf(w, "  %s:\t%s\t%s\t%s\n", sym.Name, units.FormatBytes(sym.Size), sym.Section, filepath.Base(sym.Object))
+	}
+	if m.SymbolsSeen > int64(min(len(m.Symbols), limit)) {
+		fmt.Fprintf(w, "  ... %d symbols in total\n", m.SymbolsSeen)
+	}
+	if m.DiscardedSections > 0 {
+		fmt.Fprintf(w, "\nDiscarded:\t%d unused sections (%s)\n", m.DiscardedSections, units.FormatBytes(m.DiscardedSize))
+	}
+	return nil
+}
+
+func (r *Reporter) generateMissedReasons(w *tabwriter.Writer) error {
+	if len(r.analysis.MissedReasons) == 0 {
+		return nil
+	}
+
+	fmt.Fprintf(w, "Top Reasons Optimizations Were Missed\n")
+	fmt.Fprintf(w, "=====================================\n")
+
+	for _, reason := range r.analysis.MissedReasons {
+		fmt.Fprintf(w, "  %s:\t%d\t(%s%%)\n", reason.Category, reason.Count, r.percent(reason.Share*100))
+		if r.explain {
+			fmt.Fprintf(w, "    e.g.\t%s\n", reason.Example)
+		}
+	}
+	return nil
+}
+
 func (r *Reporter) generateBuildSummary(w *tabwriter.Writer) error {
 	fmt.Fprintf(w, "Build Report\n")
 	fmt.Fprintf(w, "============\n\n")
@@ -170,19 +318,86 @@ func (r *Reporter) generateBuildSummary(w *tabwriter.Writer) error {
 	fmt.Fprintf(w, "Status:\t%s\n", r.getStatus())
 	fmt.Fprintf(w, "Start Time:\t%s\n", r.build.StartTime.Format(time.RFC3339))
 	fmt.Fprintf(w, "End Time:\t%s\n", r.build.EndTime.Format(time.RFC3339))
-	fmt.Fprintf(w, "Duration:\t%.2f seconds\n", r.build.Duration)
+	fmt.Fprintf(w, "Duration:\t%s seconds\n", r.seconds(r.build.Duration))
+	if start, end := r.build.ClientStartTime, r.build.ClientEndTime; start != nil && end != nil {
+		fmt.Fprintf(w, "Client Times:\t%s - %s (replaced by the server clock)\n",
+			start.Format(time.RFC3339), end.Format(time.RFC3339))
+	}
+	if vc := r.build.VersionControl; vc != nil {
+		fmt.Fprintf(w, "Commit:\t%s\n", vc.Describe())
+		if vc.RemoteURL != "" {
+			fmt.Fprintf(w, "Repository:\t%s\n", vc.RemoteURL)
+		}
+	}
+	if r.build.ReportedDuration != 0 {
+		fmt.Fprintf(w, "Reported Duration:\t%s seconds (disagreed with the timestamps)\n", r.seconds(r.build.ReportedDuration))
+	}
+	if wrapper := r.build.Compiler.Wrapper; wrapper != "" {
+		status := r.build.CacheStatus
+		if status == "" {
+			status = "unknown"
+		}
+		fmt.Fprintf(w, "Compiler Cache:\t%s (%s)\n", wrapper, status)
+		if status == "hit" {
+			fmt.Fprintf(w, "\tServed from the cache; timings do not reflect a full compile\n")
+		}
+	}
 	if !r.build.Success {
 		fmt.Fprintf(w, "Error:\t%s\n", r.build.Error)
 	}
+	if r.build.RemarksPurged {
+		// The kept counts cover exactly the stored remarks that were deleted
+		var removed int64
+		for _, count := range r.build.RemarkCounts {
+			removed += count.Count
+		}
+		fmt.Fprintf(w, "Remarks:\tpurged (%d removed)\n", removed)
+	} else if r.build.RemarksTruncated {
+		fmt.Fprintf(w, "Remarks:\ttruncated to %d of %d\n", len(r.build.Remarks), r.build.RemarksSeen)
+	}
+	if r.build.RawTruncated {
+		fmt.Fprintf(w, "Raw Remarks:\tdropped, over the upload cap\n")
+	}
+	if len(r.build.Labels) > 0 {
+		fmt.Fprintf(w, "\nLabels:\n")
+		keys := make([]string, 0, len(r.build.Labels))
+		for k := range r.build.Labels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(w, "  %s:\t%s\n", k, r.build.Labels[k])
+		}
+	}
 	return nil
 }
 
 func (r *Reporter) generateEnvironmentInfo(w *tabwriter.Writer) error {
 	fmt.Fprintf(w, "Environment Information\n")
 	fmt.Fprintf(w, "=====================\n")
+	if !r.hasEnvironment() {
+		return noData(w)
+	}
 	fmt.Fprintf(w, "Operating System:\t%s\n", r.build.Environment.OS)
 	fmt.Fprintf(w, "Architecture:\t%s\n", r.build.Environment.Arch)
 	fmt.Fprintf(w, "Working Directory:\t%s\n", r.build.Environment.WorkingDir)
+	if r.build.Environment.Timezone != "" {
+		fmt.Fprintf(w, "Timezone:\t%s\n", r.build.Environment.Timezone)
+	}
+	if r.build.Environment.Umask != "" {
+		fmt.Fprintf(w, "Umask:\t%s\n", r.build.Environment.Umask)
+	}
+	if len(r.build.Environment.Locale) > 0 {
+		fmt.Fprintf(w, "\nLocale:\n")
+		keys := make([]string, 0, len(r.build.Environment.Locale))
+		for k := range r.build.Environment.Locale {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(w, "  %s:\t%s\n", k, r.build.Environment.Locale[k])
+		}
+	}
 	if len(r.build.Environment.Variables) > 0 {
 		fmt.Fprintf(w, "\nEnvironment Variables:\n")
 		vars := make([]string, 0, len(r.build.Environment.Variables))
@@ -200,6 +415,9 @@ func (r *Reporter) generateEnvironmentInfo(w *tabwriter.Writer) error {
 func (r *Reporter) generateHardwareInfo(w *tabwriter.Writer) error {
 	fmt.Fprintf(w, "Hardware Information\n")
 	fmt.Fprintf(w, "===================\n")
+	if !r.hasHardware() {
+		return noData(w)
+	}
 
 	fmt.Fprintf(w, "\nCPU:\n")
 	fmt.Fprintf(w, "  Model:\t%s\n", r.build.Hardware.CPU.Model)
@@ -207,21 +425,24 @@ func (r *Reporter) generateHardwareInfo(w *tabwriter.Writer) error {
 	fmt.Fprintf(w, "  Frequency:\t%.2f MHz\n", r.build.Hardware.CPU.Frequency)
 	fmt.Fprintf(w, "  Cores:\t%d\n", r.build.Hardware.CPU.Cores)
 	fmt.Fprintf(w, "  Threads:\t%d\n", r.build.Hardware.CPU.Threads)
-	fmt.Fprintf(w, "  Cache Size:\t%s\n", formatBytes(r.build.Hardware.CPU.CacheSize))
+	fmt.Fprintf(w, "  Cache Size:\t%s\n", units.FormatBytes(r.build.Hardware.CPU.CacheSize))
+	if nodes := r.build.Hardware.CPU.NUMANodes; nodes > 0 {
+		fmt.Fprintf(w, "  NUMA Nodes:\t%d\n", nodes)
+	}
 
 	fmt.Fprintf(w, "\nMemory:\n")
-	fmt.Fprintf(w, "  Total:\t%s\n", formatBytes(r.build.Hardware.Memory.Total))
-	fmt.Fprintf(w, "  Available:\t%s\n", formatBytes(r.build.Hardware.Memory.Available))
-	fmt.Fprintf(w, "  Used:\t%s\n", formatBytes(r.build.Hardware.Memory.Used))
-	fmt.Fprintf(w, "  Swap Total:\t%s\n", formatBytes(r.build.Hardware.Memory.SwapTotal))
-	fmt.Fprintf(w, "  Swap Free:\t%s\n", formatBytes(r.build.Hardware.Memory.SwapFree))
+	fmt.Fprintf(w, "  Total:\t%s\n", units.FormatBytes(r.build.Hardware.Memory.Total))
+	fmt.Fprintf(w, "  Available:\t%s\n", units.FormatBytes(r.build.Hardware.Memory.Available))
+	fmt.Fprintf(w, "  Used:\t%s\n", units.FormatBytes(r.build.Hardware.Memory.Used))
+	fmt.Fprintf(w, "  Swap Total:\t%s\n", units.FormatBytes(r.build.Hardware.Memory.SwapTotal))
+	fmt.Fprintf(w, "  Swap Free:\t%s\n", units.FormatBytes(r.build.Hardware.Memory.SwapFree))
 
 	if len(r.build.Hardware.GPUs) > 0 {
 		fmt.Fprintf(w, "\nGPUs:\n")
 		for i, gpu := range r.build.Hardware.GPUs {
 			fmt.Fprintf(w, "  GPU %d:\n", i+1)
 			fmt.Fprintf(w, "    Model:\t%s\n", gpu.Model)
-			fmt.Fprintf(w, "    Memory:\t%s\n", formatBytes(gpu.Memory))
+			fmt.Fprintf(w, "    Memory:\t%s\n", units.FormatBytes(gpu.Memory))
 			fmt.Fprintf(w, "    Driver:\t%s\n", gpu.Driver)
 			fmt.Fprintf(w, "    Compute Capabilities:\t%s\n", gpu.ComputeCaps)
 		}
@@ -232,9 +453,21 @@ func (r *Reporter) generateHardwareInfo(w *tabwriter.Writer) error {
 func (r *Reporter) generateCompilerInfo(w *tabwriter.Writer) error {
 	fmt.Fprintf(w, "Compiler Information\n")
 	fmt.Fprintf(w, "===================\n")
+	if !r.hasCompiler() {
+		return noData(w)
+	}
 	fmt.Fprintf(w, "Name:\t%s\n", r.build.Compiler.Name)
 	fmt.Fprintf(w, "Version:\t%s\n", r.build.Compiler.Version)
 	fmt.Fprintf(w, "Target:\t%s\n", r.build.Compiler.Target)
+	if arch := r.build.Compiler.Triple.Arch; arch != "" {
+		fmt.Fprintf(w, "Architecture:\t%s\n", arch)
+	}
+	if banner := r.build.Compiler.VersionString; banner != "" {
+		fmt.Fprintf(w, "\nVersion Banner:\n")
+		for _, line := range strings.Split(banner, "\n") {
+			fmt.Fprintf(w, "  %s\n", line)
+		}
+	}
 
 	fmt.Fprintf(w, "\nLanguage:\n")
 	fmt.Fprintf(w, "  Name:\t%s\n", r.build.Compiler.Language.Name)
@@ -266,6 +499,9 @@ func (r *Reporter) generateCompilerInfo(w *tabwriter.Writer) error {
 func (r *Reporter) generateCommandInfo(w *tabwriter.Writer) error {
 	fmt.Fprintf(w, "Command Information\n")
 	fmt.Fprintf(w, "==================\n")
+	if !r.hasCommand() {
+		return noData(w)
+	}
 	fmt.Fprintf(w, "Executable:\t%s\n", r.build.Command.Executable)
 	fmt.Fprintf(w, "Working Directory:\t%s\n", r.build.Command.WorkingDir)
 
@@ -273,6 +509,17 @@ func (r *Reporter) generateCommandInfo(w *tabwriter.Writer) error {
 		fmt.Fprintf(w, "Arguments:\t%s\n", strings.Join(r.build.Command.Arguments, " "))
 	}
 
+	if len(r.build.Command.Invocations) > 0 {
+		fmt.Fprintf(w, "\nDriver Invocations:\n")
+		for i, inv := range r.build.Command.Invocations {
+			args := inv.Arguments
+			if len(args) > 0 {
+				args = args[1:]
+			}
+			fmt.Fprintf(w, "  %d. %s:\t%s\n", i+1, inv.Tool, strings.Join(args, " "))
+		}
+	}
+
 	if len(r.build.Command.Env) > 0 {
 		fmt.Fprintf(w, "\nEnvironment Overrides:\n")
 		vars := make([]string, 0, len(r.build.Command.Env))
@@ -290,19 +537,24 @@ func (r *Reporter) generateCommandInfo(w *tabwriter.Writer) error {
 func (r *Reporter) generateOutputInfo(w *tabwriter.Writer) error {
 	fmt.Fprintf(w, "Output Information\n")
 	fmt.Fprintf(w, "=================\n")
+	if !r.hasOutput() {
+		return noData(w)
+	}
 	fmt.Fprintf(w, "Exit Code:\t%d\n", r.build.Output.ExitCode)
-
-	if len(r.build.Output.Warnings) > 0 {
-		fmt.Fprintf(w, "\nWarnings:\n")
-		for _, warning := range r.build.Output.Warnings {
-			fmt.Fprintf(w, "  - %s\n", warning)
-		}
+	if r.build.Output.Truncated {
+		fmt.Fprintf(w, "Captured Output:\ttruncated, the middle was dropped\n")
 	}
 
-	if len(r.build.Output.Errors) > 0 {
-		fmt.Fprintf(w, "\nErrors:\n")
-		for _, err := range r.build.Output.Errors {
-			fmt.Fprintf(w, "  - %s\n", err)
+	if len(r.build.Output.Diagnostics) > 0 {
+		fmt.Fprintf(w, "Diagnostics:\t%d errors, %d warnings, %d notes, %d remarks\n",
+			r.build.Output.Count(models.SeverityError),
+			r.build.Output.Count(models.SeverityWarning),
+			r.build.Output.Count(models.SeverityNote),
+			r.build.Output.Count(models.SeverityRemark))
+
+		fmt.Fprintf(w, "\nDiagnostics:\n")
+		for _, d := range r.build.Output.Diagnostics {
+			fmt.Fprintf(w, "  - %s\n", d)
 		}
 	}
 
@@ -311,7 +563,7 @@ func (r *Reporter) generateOutputInfo(w *tabwriter.Writer) error {
 		for _, artifact := range r.build.Output.Artifacts {
 			fmt.Fprintf(w, "  - %s\n", artifact.Path)
 			fmt.Fprintf(w, "    Type: %s\n", artifact.Type)
-			fmt.Fprintf(w, "    Size: %s\n", formatBytes(artifact.Size))
+			fmt.Fprintf(w, "    Size: %s\n", units.FormatBytes(artifact.Size))
 			fmt.Fprintf(w, "    Hash: %s\n", artifact.Hash)
 		}
 	}
@@ -321,16 +573,41 @@ func (r *Reporter) generateOutputInfo(w *tabwriter.Writer) error {
 func (r *Reporter) generateResourceUsage(w *tabwriter.Writer) error {
 	fmt.Fprintf(w, "Resource Usage\n")
 	fmt.Fprintf(w, "==============\n")
-	fmt.Fprintf(w, "Max Memory:\t%s\n", formatBytes(r.build.ResourceUsage.MaxMemory))
-	fmt.Fprintf(w, "CPU Time:\t%.2f seconds\n", r.build.ResourceUsage.CPUTime)
+	if !r.hasResourceUsage() {
+		return noData(w)
+	}
+	fmt.Fprintf(w, "Max Memory:\t%s\n", units.FormatBytes(r.build.ResourceUsage.MaxMemory))
+	fmt.Fprintf(w, "CPU Time:\t%s seconds\n", r.seconds(r.build.ResourceUsage.CPUTime))
 	fmt.Fprintf(w, "Threads:\t%d\n", r.build.ResourceUsage.Threads)
 
+	// -ftime-report counts what GCC's garbage collector allocated in each
+	// phase, which is not the process's resident memory
+	if len(r.build.ResourceUsage.PhaseMemory) == 0 {
+		fmt.Fprintf(w, "\nGC Allocation by Phase:\tnot reported, %s peak overall\n", units.FormatBytes(r.build.ResourceUsage.MaxMemory))
+	} else {
+		fmt.Fprintf(w, "\nGC Allocation by Phase:\n")
+		phases := make([]string, 0, len(r.build.ResourceUsage.PhaseMemory))
+		for phase := range r.build.ResourceUsage.PhaseMemory {
+			phases = append(phases, phase)
+		}
+		sort.Slice(phases, func(i, j int) bool {
+			a, b := r.build.ResourceUsage.PhaseMemory[phases[i]], r.build.ResourceUsage.PhaseMemory[phases[j]]
+			if a != b {
+				return a > b
+			}
+			return phases[i] < phases[j]
+		})
+		for _, phase := range phases {
+			fmt.Fprintf(w, "  %s:\t%s\n", phase, units.FormatBytes(r.build.ResourceUsage.PhaseMemory[phase]))
+		}
+	}
+
 	fmt.Fprintf(w, "\nIO Statistics:\n")
 	fmt.Fprintf(w, "  Read:\t%s (%d operations)\n",
-		formatBytes(r.build.ResourceUsage.IO.ReadBytes),
+		units.FormatBytes(r.build.ResourceUsage.IO.ReadBytes),
 		r.build.ResourceUsage.IO.ReadCount)
 	fmt.Fprintf(w, "  Write:\t%s (%d operations)\n",
-		formatBytes(r.build.ResourceUsage.IO.WriteBytes),
+		units.FormatBytes(r.build.ResourceUsage.IO.WriteBytes),
 		r.build.ResourceUsage.IO.WriteCount)
 	return nil
 }
@@ -338,9 +615,12 @@ func (r *Reporter) generateResourceUsage(w *tabwriter.Writer) error {
 func (r *Reporter) generatePerformanceInfo(w *tabwriter.Writer) error {
 	fmt.Fprintf(w, "Performance Information\n")
 	fmt.Fprintf(w, "=====================\n")
-	fmt.Fprintf(w, "Compile Time:\t%.2f seconds\n", r.build.Performance.CompileTime)
-	fmt.Fprintf(w, "Link Time:\t%.2f seconds\n", r.build.Performance.LinkTime)
-	fmt.Fprintf(w, "Optimize Time:\t%.2f seconds\n", r.build.Performance.OptimizeTime)
+	if !r.hasPerformance() {
+		return noData(w)
+	}
+	fmt.Fprintf(w, "Compile Time:\t%s seconds\n", r.seconds(r.build.Performance.CompileTime))
+	fmt.Fprintf(w, "Link Time:\t%s seconds\n", r.seconds(r.build.Performance.LinkTime))
+	fmt.Fprintf(w, "Optimize Time:\t%s seconds\n", r.seconds(r.build.Performance.OptimizeTime))
 
 	if len(r.build.Performance.Phases) > 0 {
 		fmt.Fprintf(w, "\nPhase Timings:\n")
@@ -351,7 +631,7 @@ func (r *Reporter) generatePerformanceInfo(w *tabwriter.Writer) error {
 		sort.Strings(phases)
 
 		for _, phase := range phases {
-			fmt.Fprintf(w, "  %s:\t%.2f seconds\n", phase, r.build.Performance.Phases[phase])
+			fmt.Fprintf(w, "  %s:\t%s seconds\n", phase, r.seconds(r.build.Performance.Phases[phase]))
 		}
 	}
 	return nil
@@ -360,7 +640,10 @@ func (r *Reporter) generatePerformanceInfo(w *tabwriter.Writer) error {
 func (r *Reporter) generateAnalysisResults(w *tabwriter.Writer) error {
 	fmt.Fprintf(w, "Performance Analysis Results\n")
 	fmt.Fprintf(w, "=========================\n")
-	fmt.Fprintf(w, "Resource Efficiency:\t%.2f%%\n", r.analysis.ResourceEfficiency*100)
+	if !r.hasAnalysis() {
+		return noData(w)
+	}
+	fmt.Fprintf(w, "Resource Efficiency:\t%s%%\n", r.seconds(r.analysis.ResourceEfficiency*100))
 
 	if len(r.analysis.MemoryUsageProfile) > 0 {
 		fmt.Fprintf(w, "\nMemory Usage Profile:\n")
@@ -370,7 +653,7 @@ func (r *Reporter) generateAnalysisResults(w *tabwriter.Writer) error {
 		}
 		sort.Strings(metrics)
 		for _, metric := range metrics {
-			fmt.Fprintf(w, "  %s:\t%s\n", metric, formatBytes(r.analysis.MemoryUsageProfile[metric]))
+			fmt.Fprintf(w, "  %s:\t%s\n", metric, units.FormatBytes(r.analysis.MemoryUsageProfile[metric]))
 		}
 	}
 
@@ -382,7 +665,7 @@ func (r *Reporter) generateAnalysisResults(w *tabwriter.Writer) error {
 		}
 		sort.Strings(phases)
 		for _, phase := range phases {
-			fmt.Fprintf(w, "  %s:\t%.2f seconds\n", phase, r.analysis.CompilationOverhead[phase])
+			fmt.Fprintf(w, "  %s:\t%s seconds\n", phase, r.seconds(r.analysis.CompilationOverhead[phase]))
 		}
 	}
 
@@ -407,6 +690,9 @@ func (r *Reporter) generateBottlenecks(w *tabwriter.Writer) error {
 		for _, b := range r.analysis.Bottlenecks {
 			fmt.Fprintf(w, "- %s (Severity: %s)\n", b.Description, b.Severity)
 			fmt.Fprintf(w, "  Impact: %.2f\n", b.Impact)
+			if r.explain && b.Explanation != "" {
+				fmt.Fprintf(w, "  Why: %s\n", b.Explanation)
+			}
 		}
 	}
 
@@ -456,19 +742,6 @@ func (r *Reporter) groupRemarksByPass() map[string][]models.CompilerRemark {
 	return result
 }
 
-func formatBytes(bytes int64) string {
-	const unit = 1024
-	if bytes < unit {
-		return fmt.Sprintf("%d B", bytes)
-	}
-	div, exp := int64(unit), 0
-	for n := bytes / unit; n >= unit; n /= unit {
-		div *= unit
-		exp++
-	}
-	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
-}
-
 func (r *Reporter) getStatus() string {
 	if r.build.Success {
 		return "SUCCESS"
@@ -551,70 +824,9 @@ func (r *Reporter) printRemark(w *tabwriter.Writer, remark models.CompilerRemark
 	fmt.Fprintf(w, "\n")
 }
 
-func (r *Reporter) calculateRemarkStats() remarkStats {
-	stats := remarkStats{
-		ByType:     make(map[string]int),
-		ByPass:     make(map[string]int),
-		ByFunction: make(map[string]int),
-	}
-
-	for _, remark := range r.build.Remarks {
-		stats.TotalRemarks++
-		stats.ByType[remark.Type]++
-		stats.ByPass[remark.Pass]++
-		if remark.Function != "" {
-			stats.ByFunction[remark.Function]++
-		}
-
-		// Track optimization statistics
-		switch remark.Type {
-		case "Passed":
-			stats.Optimizations.Passed++
-			stats.Optimizations.Total++
-		case "Missed":
-			stats.Optimizations.Missed++
-			stats.Optimizations.Total++
-		}
-
-		// Track inlining statistics
-		if remark.Pass == "inline" {
-			stats.InliningStats.Total++
-			if remark.Type == "Passed" {
-				stats.InliningStats.Successful++
-			} else {
-				stats.InliningStats.Failed++
-			}
-		}
-
-		// Track kernel statistics
-		if remark.KernelInfo != nil {
-			stats.KernelStats.TotalAccesses += len(remark.KernelInfo.MemoryAccesses)
-			stats.KernelStats.TotalThreadLimit += int(remark.KernelInfo.ThreadLimit)
-			stats.KernelStats.TotalDirectCalls += int(remark.KernelInfo.DirectCalls)
-			stats.KernelStats.TotalAllocas += int(remark.KernelInfo.AllocasCount)
-		}
-	}
-
-	return stats
-}
-
 func (r *Reporter) printSortedMap(w *tabwriter.Writer, m map[string]int, total int) {
-	type kv struct {
-		Key   string
-		Value int
-	}
-
-	var items []kv
-	for k, v := range m {
-		items = append(items, kv{k, v})
-	}
-
-	sort.Slice(items, func(i, j int) bool {
-		return items[i].Value > items[j].Value
-	})
-
-	for _, item := range items {
+	for _, item := range stats.Sorted(m) {
 		percentage := float64(item.Value) / float64(total) * 100
-		fmt.Fprintf(w, "  %s:\t%d\t(%.1f%%)\n", item.Key, item.Value, percentage)
+		fmt.Fprintf(w, "  %s:\t%d\t(%s%%)\n", item.Key, item.Value, r.percent(percentage))
 	}
 }
@@ -0,0 +1,106 @@
+// internal/reporters/yaml/reporter.go
+package yaml
+
+import (
+	encjson "encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+
+	"builds/internal/analysis/performance"
+	"builds/internal/models"
+	"builds/internal/reporters/filename"
+	"builds/internal/reporters/json"
+)
+
+type Reporter struct {
+	filename.Prefix
+	build    *models.Build
+	analysis *performance.AnalysisResult
+	outDir   string
+}
+
+func NewReporter(build *models.Build, analysis *performance.AnalysisResult, outDir string) *Reporter {
+	return &Reporter{
+		build:    build,
+		analysis: analysis,
+		outDir:   outDir,
+	}
+}
+
+func (r *Reporter) Generate() error {
+	if err := os.MkdirAll(r.outDir, 0755); err != nil {
+		return fmt.Errorf("creating output directory: %w", err)
+	}
+
+	// The YAML documents mirror the JSON reporter's output
+	source := json.NewReporter(r.build, r.analysis, r.outDir)
+
+	fullReportPath := filepath.Join(r.outDir, r.FilePrefix(r.build.ID)+"-full.yaml")
+	if err := r.writeYAML(fullReportPath, source.FullReport()); err != nil {
+		return fmt.Errorf("writing full report: %w", err)
+	}
+
+	summaryPath := filepath.Join(r.outDir, r.FilePrefix(r.build.ID)+"-summary.yaml")
+	if err := r.writeYAML(summaryPath, source.Summary()); err != nil {
+		return fmt.Errorf("writing summary: %w", err)
+	}
+
+	return nil
+}
+
+// GenerateTo writes the full report to w instead of a file
+func (r *Reporter) GenerateTo(w io.Writer) error {
+	return encode(w, json.NewReporter(r.build, r.analysis, r.outDir).FullReport())
+}
+
+func (r *Reporter) writeYAML(path string, data interface{}) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return encode(file, data)
+}
+
+func encode(w io.Writer, data interface{}) error {
+	node, err := toNode(data)
+	if err != nil {
+		return err
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(node); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// toNode converts data into a YAML node through its JSON encoding, so that
+// field names and ordering match the JSON reports exactly.
+func toNode(data interface{}) (*yaml.Node, error) {
+	raw, err := encjson.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(raw, &node); err != nil {
+		return nil, err
+	}
+	resetStyle(&node)
+	return &node, nil
+}
+
+// resetStyle drops the JSON flow and quoting styles in favour of plain YAML
+func resetStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetStyle(child)
+	}
+}
@@ -0,0 +1,126 @@
+package yaml
+
+import (
+	"bytes"
+	encjson "encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"builds/internal/analysis/performance"
+	"builds/internal/models"
+	"builds/internal/reporters/json"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files")
+
+func testBuild() (*models.Build, *performance.AnalysisResult) {
+	build := &models.Build{
+		ID:        "b1",
+		StartTime: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		Duration:  2.5,
+		Success:   true,
+		Environment: models.Environment{
+			OS:         "linux",
+			Arch:       "amd64",
+			WorkingDir: "/src",
+		},
+		Compiler: models.Compiler{
+			Name:    "clang",
+			Version: "18.1.0",
+			Target:  "x86_64-unknown-linux-gnu",
+		},
+		Performance:   models.Performance{CompileTime: 2, LinkTime: 0.5},
+		ResourceUsage: models.ResourceUsage{MaxMemory: 1 << 20, CPUTime: 2.25},
+	}
+	analysis := &performance.AnalysisResult{
+		ResourceEfficiency: 0.5,
+		Bottlenecks:        []performance.PerformanceBottleneck{{Description: "Too many missed optimizations: 12"}},
+	}
+	return build, analysis
+}
+
+func TestSummaryGolden(t *testing.T) {
+	build, analysis := testBuild()
+	dir := t.TempDir()
+	if err := NewReporter(build, analysis, dir).Generate(); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(dir, "build-b1-summary.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden := filepath.Join("testdata", "summary.golden.yaml")
+	if *update {
+		if err := os.WriteFile(golden, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("summary differs from %s:\n%s", golden, got)
+	}
+
+	// The YAML parses back to the document the JSON reporter writes
+	var parsed interface{}
+	if err := yaml.Unmarshal(got, &parsed); err != nil {
+		t.Fatalf("summary does not parse: %v", err)
+	}
+	raw, err := encjson.Marshal(json.NewReporter(build, analysis, "").Summary())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fromJSON interface{}
+	if err := encjson.Unmarshal(raw, &fromJSON); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(normalize(parsed), normalize(fromJSON)) {
+		t.Errorf("parsed summary %v, want %v", parsed, fromJSON)
+	}
+}
+
+func TestFullReportParses(t *testing.T) {
+	build, analysis := testBuild()
+	var buf bytes.Buffer
+	if err := NewReporter(build, analysis, "").GenerateTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var report struct {
+		Build    models.Build               `yaml:"build"`
+		Analysis performance.AnalysisResult `yaml:"analysis"`
+	}
+	if err := yaml.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("full report does not parse: %v\n%s", err, buf.String())
+	}
+	if report.Build.ID != "b1" || report.Build.Compiler.Name != "clang" {
+		t.Errorf("parsed build %q compiled by %q", report.Build.ID, report.Build.Compiler.Name)
+	}
+}
+
+// normalize makes numbers comparable across decoders, which pick int or
+// float64 differently
+func normalize(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			v[k] = normalize(child)
+		}
+	case []interface{}:
+		for i, child := range v {
+			v[i] = normalize(child)
+		}
+	case int:
+		return float64(v)
+	}
+	return v
+}
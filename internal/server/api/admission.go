@@ -0,0 +1,74 @@
+// internal/server/api/admission.go
+
+package api
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// writeLimiter bounds the number of concurrent write transactions so that a
+// burst of builds queues instead of exhausting the connection pool
+type writeLimiter struct {
+	slots   chan struct{}
+	waiting atomic.Int64
+	queue   int64
+	timeout time.Duration
+}
+
+func newWriteLimiter(concurrency, queue int, timeout time.Duration) *writeLimiter {
+	if concurrency <= 0 {
+		return nil
+	}
+	return &writeLimiter{
+		slots:   make(chan struct{}, concurrency),
+		queue:   int64(queue),
+		timeout: timeout,
+	}
+}
+
+// acquire waits for a write slot. It fails with ResourceExhausted when the
+// queue is full and with Unavailable when the wait times out, which clients
+// retry. A nil limiter admits everything.
+func (l *writeLimiter) acquire(ctx context.Context) (release func(), err error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	// Fast path when a slot is free
+	select {
+	case l.slots <- struct{}{}:
+		return l.release, nil
+	default:
+	}
+
+	if l.waiting.Add(1) > l.queue {
+		l.waiting.Add(-1)
+		return nil, status.Error(codes.ResourceExhausted, "too many builds queued, try again later")
+	}
+	defer l.waiting.Add(-1)
+
+	var timeout <-chan time.Time
+	if l.timeout > 0 {
+		timer := time.NewTimer(l.timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+		return l.release, nil
+	case <-timeout:
+		return nil, status.Error(codes.Unavailable, "timed out waiting for a write slot")
+	case <-ctx.Done():
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+}
+
+func (l *writeLimiter) release() {
+	<-l.slots
+}
@@ -0,0 +1,171 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	buildv1 "builds/api/build"
+	"builds/internal/server/db/dbtest"
+)
+
+// waitQueued waits until n writes are queued on l
+func waitQueued(t *testing.T, l *writeLimiter, n int64) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for l.waiting.Load() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d writes queued, want %d", l.waiting.Load(), n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestWriteLimiterQueue(t *testing.T) {
+	l := newWriteLimiter(2, 3, time.Minute)
+	ctx := context.Background()
+
+	// Fill both slots, then the queue
+	var held []func()
+	for range 2 {
+		release, err := l.acquire(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		held = append(held, release)
+	}
+	var admitted atomic.Int64
+	var wg sync.WaitGroup
+	for range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, err := l.acquire(ctx)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			admitted.Add(1)
+			release()
+		}()
+	}
+	waitQueued(t, l, 3)
+
+	// Only a full queue rejects writes
+	if _, err := l.acquire(ctx); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("acquire with a full queue: %v, want ResourceExhausted", err)
+	}
+
+	// Queued writes go ahead as slots free up
+	for _, release := range held {
+		release()
+	}
+	wg.Wait()
+	if admitted.Load() != 3 {
+		t.Errorf("admitted %d queued writes, want 3", admitted.Load())
+	}
+}
+
+func TestWriteLimiterWait(t *testing.T) {
+	l := newWriteLimiter(1, 1, 10*time.Millisecond)
+	release, err := l.acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	if _, err := l.acquire(context.Background()); status.Code(err) != codes.Unavailable {
+		t.Errorf("acquire after the queue timeout: %v, want Unavailable", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	l.timeout = time.Minute
+	if _, err := l.acquire(ctx); status.Code(err) != codes.Canceled {
+		t.Errorf("acquire with a canceled context: %v, want Canceled", err)
+	}
+	if l.waiting.Load() != 0 {
+		t.Errorf("%d writes still queued", l.waiting.Load())
+	}
+}
+
+func TestWriteLimiterDisabled(t *testing.T) {
+	l := newWriteLimiter(0, 0, 0)
+	for range 100 {
+		if _, err := l.acquire(context.Background()); err != nil {
+			t.Fatalf("disabled limiter: %v", err)
+		}
+	}
+}
+
+func TestWriteLimiterConcurrency(t *testing.T) {
+	const limit, writers = 3, 50
+	l := newWriteLimiter(limit, writers, time.Minute)
+
+	var mu sync.Mutex
+	var inFlight, peak int
+	var wg sync.WaitGroup
+	for range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, err := l.acquire(context.Background())
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer release()
+			mu.Lock()
+			inFlight++
+			peak = max(peak, inFlight)
+			mu.Unlock()
+
+			time.Sleep(time.Millisecond)
+
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	if peak > limit {
+		t.Errorf("%d writes ran at once, want at most %d", peak, limit)
+	}
+}
+
+func TestCreateBuildBeyondWriteLimit(t *testing.T) {
+	const builds = 20
+	s := NewServer(dbtest.Open(t), Options{
+		MaxConcurrentWrites: 2,
+		WriteQueueSize:      builds,
+		WriteQueueTimeout:   time.Minute,
+	})
+
+	// Every build waits its turn rather than failing
+	var wg sync.WaitGroup
+	for i := range builds {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			build := &buildv1.Build{Id: fmt.Sprintf("build-%d", i)}
+			if _, err := s.CreateBuild(context.Background(), &buildv1.CreateBuildRequest{Build: build}); err != nil {
+				t.Errorf("CreateBuild(%s): %v", build.Id, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	count, err := s.CountBuilds(context.Background(), &buildv1.CountBuildsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count.Count != builds {
+		t.Errorf("stored %d builds, want %d", count.Count, builds)
+	}
+}
@@ -0,0 +1,103 @@
+// internal/server/api/batch.go
+
+package api
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	buildv1 "builds/api/build"
+)
+
+// maxBatchInFlight bounds the builds of one CreateBuilds stream stored at
+// once. Further requests are not read until a slot frees up, which pushes
+// back on the client.
+const maxBatchInFlight = 16
+
+// maxBatchSize is the most builds a BatchCreateBuilds request may carry
+const maxBatchSize = 500
+
+// BatchCreateBuilds stores the builds of one request, up to
+// maxBatchInFlight at a time, each in its own transaction. Results follow
+// the request order, so one bad build does not fail the batch.
+func (s *Server) BatchCreateBuilds(ctx context.Context, req *buildv1.BatchCreateBuildsRequest) (*buildv1.BatchCreateBuildsResponse, error) {
+	if len(req.Builds) > maxBatchSize {
+		return nil, status.Errorf(codes.InvalidArgument, "batch of %d builds exceeds the limit of %d", len(req.Builds), maxBatchSize)
+	}
+
+	results := make([]*buildv1.CreateBuildsResponse, len(req.Builds))
+	var g errgroup.Group
+	g.SetLimit(maxBatchInFlight)
+	for i, build := range req.Builds {
+		g.Go(func() error {
+			results[i] = s.createResult(ctx, build)
+			return nil
+		})
+	}
+	g.Wait()
+
+	return &buildv1.BatchCreateBuildsResponse{Results: results}, nil
+}
+
+// createResult stores one build of a batch and reports its outcome
+func (s *Server) createResult(ctx context.Context, build *buildv1.Build) *buildv1.CreateBuildsResponse {
+	resp := &buildv1.CreateBuildsResponse{BuildId: build.GetId()}
+	if _, err := s.storeBuild(ctx, build); err != nil {
+		st := status.Convert(err)
+		resp.Code = int32(st.Code())
+		resp.Error = st.Message()
+	}
+	return resp
+}
+
+// CreateBuilds stores the builds sent on the stream concurrently and answers
+// each with its own outcome, so one bad build does not fail the batch.
+// Results are sent in the order builds finish, not the order they arrived.
+func (s *Server) CreateBuilds(stream buildv1.BuildService_CreateBuildsServer) error {
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		sendErr error
+	)
+	send := func(resp *buildv1.CreateBuildsResponse) {
+		mu.Lock()
+		defer mu.Unlock()
+		if sendErr == nil {
+			sendErr = stream.Send(resp)
+		}
+	}
+
+	slots := make(chan struct{}, maxBatchInFlight)
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			wg.Wait()
+			return err
+		}
+
+		slots <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			send(s.createResult(stream.Context(), req.GetBuild()))
+		}()
+	}
+
+	wg.Wait()
+	mu.Lock()
+	defer mu.Unlock()
+	if sendErr != nil {
+		return status.Error(codes.Unavailable, "failed to send batch results")
+	}
+	return nil
+}
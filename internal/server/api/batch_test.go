@@ -0,0 +1,101 @@
+package api
+
+import (
+	"context"
+	"io"
+	"slices"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	buildv1 "builds/api/build"
+)
+
+func TestCreateBuildsPartialFailure(t *testing.T) {
+	client := newTestClient(t, Options{})
+	ctx := context.Background()
+
+	stream, err := client.CreateBuilds(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The second b1 collides with the first
+	for _, id := range []string{"b1", "b2", "b1", "b3"} {
+		if err := stream.Send(&buildv1.CreateBuildRequest{Build: &buildv1.Build{Id: id}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := stream.CloseSend(); err != nil {
+		t.Fatal(err)
+	}
+
+	codesByID := make(map[string][]codes.Code)
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		codesByID[resp.BuildId] = append(codesByID[resp.BuildId], codes.Code(resp.Code))
+	}
+
+	// Results arrive in the order builds finish, so either b1 may win
+	got := codesByID["b1"]
+	slices.Sort(got)
+	if !slices.Equal(got, []codes.Code{codes.OK, codes.AlreadyExists}) {
+		t.Errorf("b1 answered with %v, want one OK and one AlreadyExists", got)
+	}
+	for _, id := range []string{"b2", "b3"} {
+		if got := codesByID[id]; len(got) != 1 || got[0] != codes.OK {
+			t.Errorf("%s answered with %v, want OK", id, got)
+		}
+	}
+
+	count, err := client.CountBuilds(ctx, &buildv1.CountBuildsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count.Count != 3 {
+		t.Errorf("stored %d builds, want 3", count.Count)
+	}
+}
+
+func TestBatchCreateBuildsPartialFailure(t *testing.T) {
+	client := newTestClient(t, Options{})
+	ctx := context.Background()
+	if _, err := client.CreateBuild(ctx, &buildv1.CreateBuildRequest{Build: &buildv1.Build{Id: "b0"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := client.BatchCreateBuilds(ctx, &buildv1.BatchCreateBuildsRequest{
+		Builds: []*buildv1.Build{{Id: "b1"}, {Id: "b0"}, {Id: "b2"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []codes.Code{codes.OK, codes.AlreadyExists, codes.OK}
+	if len(resp.Results) != len(want) {
+		t.Fatalf("got %d results, want %d", len(resp.Results), len(want))
+	}
+	for i, result := range resp.Results {
+		if codes.Code(result.Code) != want[i] {
+			t.Errorf("result %d (%q): %s %q, want %s", i, result.BuildId, codes.Code(result.Code), result.Error, want[i])
+		}
+	}
+}
+
+func TestBatchCreateBuildsLimit(t *testing.T) {
+	client := newTestClient(t, Options{})
+
+	builds := make([]*buildv1.Build, maxBatchSize+1)
+	for i := range builds {
+		builds[i] = &buildv1.Build{}
+	}
+	_, err := client.BatchCreateBuilds(context.Background(), &buildv1.BatchCreateBuildsRequest{Builds: builds})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("BatchCreateBuilds of %d builds: %v, want InvalidArgument", len(builds), err)
+	}
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	buildv1 "builds/api/build"
+	"builds/internal/server/db/dbtest"
+)
+
+var durationStart = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// timedBuild returns a build that ran for span by its timestamps and
+// reported duration seconds
+func timedBuild(id string, span time.Duration, duration float64) *buildv1.Build {
+	return &buildv1.Build{
+		Id:        id,
+		StartTime: timestamppb.New(durationStart),
+		EndTime:   timestamppb.New(durationStart.Add(span)),
+		Duration:  duration,
+	}
+}
+
+func TestCheckDuration(t *testing.T) {
+	tests := []struct {
+		name         string
+		build        *buildv1.Build
+		wantDuration float64
+		wantReported float64
+	}{
+		{"consistent", timedBuild("b", 30*time.Second, 30), 30, 0},
+		{"within tolerance", timedBuild("b", 30*time.Second, 30.8), 30, 0},
+		{"too long", timedBuild("b", 30*time.Second, 90), 30, 90},
+		{"too short", timedBuild("b", 30*time.Second, 5), 30, 5},
+		{"no end time", &buildv1.Build{StartTime: timestamppb.New(durationStart), Duration: 12}, 12, 0},
+		{"no timestamps", &buildv1.Build{Duration: 12}, 12, 0},
+		{"unset start time", &buildv1.Build{StartTime: timestamppb.New(time.Unix(0, 0)), EndTime: timestamppb.New(durationStart), Duration: 12}, 12, 0},
+		{"end before start", timedBuild("b", -time.Minute, 12), 12, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			duration, reported := checkDuration(tt.build)
+			if duration != tt.wantDuration || reported != tt.wantReported {
+				t.Errorf("got %v reported as %v, want %v reported as %v", duration, reported, tt.wantDuration, tt.wantReported)
+			}
+		})
+	}
+}
+
+func TestCreateBuildDuration(t *testing.T) {
+	s := NewServer(dbtest.Open(t), Options{})
+	ctx := context.Background()
+
+	tests := []struct {
+		build        *buildv1.Build
+		wantDuration float64
+		wantReported float64
+	}{
+		{timedBuild("consistent", 30*time.Second, 30.2), 30, 0},
+		{timedBuild("inconsistent", 30*time.Second, 3600), 30, 3600},
+	}
+	for _, tt := range tests {
+		t.Run(tt.build.Id, func(t *testing.T) {
+			if _, err := s.CreateBuild(ctx, &buildv1.CreateBuildRequest{Build: tt.build}); err != nil {
+				t.Fatal(err)
+			}
+			got, err := s.GetBuild(ctx, &buildv1.GetBuildRequest{Id: tt.build.Id})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Duration != tt.wantDuration || got.ReportedDuration != tt.wantReported {
+				t.Errorf("stored %v reported as %v, want %v reported as %v",
+					got.Duration, got.ReportedDuration, tt.wantDuration, tt.wantReported)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+// internal/server/api/errors.go
+
+package api
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"log"
+	"net"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+// Error categories handlers can wrap to pick the status code returned to
+// clients
+var (
+	ErrNotFound    = errors.New("not found")
+	ErrConflict    = errors.New("conflict")
+	ErrInvalid     = errors.New("invalid argument")
+	ErrUnavailable = errors.New("unavailable")
+)
+
+// statusError maps err to a gRPC status. Expected failures get a code and a
+// message naming the resource; anything else is logged and reported as an
+// internal error without exposing database details to the client.
+func statusError(err error, resource string) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	var netErr net.Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound), errors.Is(err, ErrNotFound):
+		return status.Errorf(codes.NotFound, "%s not found", resource)
+	case errors.Is(err, gorm.ErrDuplicatedKey), errors.Is(err, ErrConflict):
+		return status.Errorf(codes.AlreadyExists, "%s already exists", resource)
+	case errors.Is(err, gorm.ErrInvalidData), errors.Is(err, gorm.ErrInvalidValue),
+		errors.Is(err, gorm.ErrPrimaryKeyRequired), errors.Is(err, ErrInvalid):
+		return status.Errorf(codes.InvalidArgument, "invalid %s", resource)
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, "request canceled")
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, "request timed out")
+	case errors.Is(err, driver.ErrBadConn), errors.As(err, &netErr), errors.Is(err, ErrUnavailable):
+		log.Printf("Database unavailable handling %s: %v", resource, err)
+		return status.Error(codes.Unavailable, "database unavailable, try again later")
+	default:
+		log.Printf("Internal error handling %s: %v", resource, err)
+		return status.Error(codes.Internal, "internal error")
+	}
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+func TestStatusError(t *testing.T) {
+	// Database errors as they reach handlers, with the query attached
+	query := func(err error) error {
+		return fmt.Errorf(`failed to get build: SELECT * FROM "builds" WHERE id = 'b1' AND "builds"."deleted_at" IS NULL: %w`, err)
+	}
+
+	tests := []struct {
+		name     string
+		err      error
+		wantCode codes.Code
+		wantMsg  string
+	}{
+		{"record not found", query(gorm.ErrRecordNotFound), codes.NotFound, "build not found"},
+		{"not found sentinel", fmt.Errorf("no build b1: %w", ErrNotFound), codes.NotFound, "build not found"},
+		{"duplicate key", query(gorm.ErrDuplicatedKey), codes.AlreadyExists, "build already exists"},
+		{"conflict sentinel", fmt.Errorf("build b1 is finalized: %w", ErrConflict), codes.AlreadyExists, "build already exists"},
+		{"invalid data", query(gorm.ErrInvalidData), codes.InvalidArgument, "invalid build"},
+		{"missing primary key", query(gorm.ErrPrimaryKeyRequired), codes.InvalidArgument, "invalid build"},
+		{"invalid sentinel", fmt.Errorf("negative duration: %w", ErrInvalid), codes.InvalidArgument, "invalid build"},
+		{"canceled", query(context.Canceled), codes.Canceled, "request canceled"},
+		{"deadline", query(context.DeadlineExceeded), codes.DeadlineExceeded, "request timed out"},
+		{"bad connection", query(driver.ErrBadConn), codes.Unavailable, "database unavailable, try again later"},
+		{"network", query(&net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}), codes.Unavailable, "database unavailable, try again later"},
+		{"unavailable sentinel", fmt.Errorf("pool closed: %w", ErrUnavailable), codes.Unavailable, "database unavailable, try again later"},
+		{"anything else", query(errors.New(`ERROR: column "foo" does not exist (SQLSTATE 42703)`)), codes.Internal, "internal error"},
+		{"status passes through", status.Error(codes.PermissionDenied, "not yours"), codes.PermissionDenied, "not yours"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := statusError(tt.err, "build")
+			st, ok := status.FromError(err)
+			if !ok {
+				t.Fatalf("%v is not a status", err)
+			}
+			if st.Code() != tt.wantCode || st.Message() != tt.wantMsg {
+				t.Errorf("got %s %q, want %s %q", st.Code(), st.Message(), tt.wantCode, tt.wantMsg)
+			}
+			for _, leak := range []string{"SELECT", "builds", "SQLSTATE", "connection refused"} {
+				if strings.Contains(st.Message(), leak) {
+					t.Errorf("message %q leaks %q", st.Message(), leak)
+				}
+			}
+		})
+	}
+
+	if err := statusError(nil, "build"); err != nil {
+		t.Errorf("statusError(nil) = %v", err)
+	}
+}
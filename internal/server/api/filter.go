@@ -0,0 +1,52 @@
+// internal/server/api/filter.go
+
+package api
+
+import (
+	"fmt"
+	"strings"
+
+	"builds/internal/parsers/triple"
+	"builds/internal/server/db"
+)
+
+// minCommandHashPrefix is the shortest command hash prefix accepted, so a
+// filter cannot match unrelated compiles by accident
+const minCommandHashPrefix = 8
+
+// parseListFilter reads a ListBuilds filter: whitespace separated terms that
+// must all match. label=key:value selects builds carrying that label and
+// arch=name builds compiled for that target architecture; the architecture
+// is normalised as targets are, so arch=arm64 finds aarch64 builds.
+// command=hash selects runs of the same compile by a prefix of their
+// command hash.
+func parseListFilter(filter string) (db.ListFilter, error) {
+	parsed := db.ListFilter{Labels: make(map[string]string)}
+	for _, term := range strings.Fields(filter) {
+		field, arg, ok := strings.Cut(term, "=")
+		if !ok {
+			return parsed, fmt.Errorf("filter term %q must be field=value", term)
+		}
+		switch field {
+		case "label":
+			key, value, ok := strings.Cut(arg, ":")
+			if !ok || key == "" {
+				return parsed, fmt.Errorf("label filter %q must be label=key:value", term)
+			}
+			parsed.Labels[key] = value
+		case "arch":
+			if arg == "" || strings.Contains(arg, "-") {
+				return parsed, fmt.Errorf("arch filter %q must name an architecture, such as arch=aarch64", term)
+			}
+			parsed.Arch = triple.Parse(arg).Arch
+		case "command":
+			if len(arg) < minCommandHashPrefix || strings.Trim(strings.ToLower(arg), "0123456789abcdef") != "" {
+				return parsed, fmt.Errorf("command filter %q must give at least %d hex digits of a command hash", term, minCommandHashPrefix)
+			}
+			parsed.CommandHash = strings.ToLower(arg)
+		default:
+			return parsed, fmt.Errorf("unknown filter field %q", field)
+		}
+	}
+	return parsed, nil
+}
@@ -0,0 +1,33 @@
+package api
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseListFilterLabels(t *testing.T) {
+	tests := []struct {
+		filter  string
+		want    map[string]string
+		wantErr bool
+	}{
+		{filter: "", want: map[string]string{}},
+		{filter: "label=project:app", want: map[string]string{"project": "app"}},
+		{filter: "label=project:app label=ci:", want: map[string]string{"project": "app", "ci": ""}},
+		{filter: "label=url:http://host", want: map[string]string{"url": "http://host"}},
+		{filter: "label=project", wantErr: true},
+		{filter: "label=:app", wantErr: true},
+		{filter: "project:app", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			got, err := parseListFilter(tt.filter)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got.Labels, tt.want) {
+				t.Errorf("labels %v, want %v", got.Labels, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	buildv1 "builds/api/build"
+	"builds/internal/server/db/dbtest"
+)
+
+// newTestClient serves a server over a test database in-process. Tests
+// calling it are skipped without a database.
+func newTestClient(t *testing.T, opts Options) buildv1.BuildServiceClient {
+	t.Helper()
+	database := dbtest.Open(t)
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	buildv1.RegisterBuildServiceServer(server, NewServer(database, opts))
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return buildv1.NewBuildServiceClient(conn)
+}
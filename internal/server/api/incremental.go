@@ -0,0 +1,207 @@
+// internal/server/api/incremental.go
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+
+	buildv1 "builds/api/build"
+	"builds/internal/server/auth"
+	models "builds/internal/server/db/models"
+)
+
+// BeginBuild registers a running build so remarks can be streamed into it
+// before it finishes
+func (s *Server) BeginBuild(ctx context.Context, req *buildv1.CreateBuildRequest) (*buildv1.Build, error) {
+	if req.Build == nil || req.Build.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "build with id is required")
+	}
+
+	build := &models.Build{
+		ID:         req.Build.Id,
+		StartTime:  req.Build.StartTime.AsTime(),
+		Tenant:     auth.TenantFromContext(ctx),
+		InProgress: true,
+	}
+	if s.opts.ServerTimestamps {
+		build.ClientStartTime = clientTime(req.Build.StartTime)
+		build.StartTime = time.Now()
+	}
+
+	release, err := s.writes.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	if err := s.db.DB.Create(build).Error; err != nil {
+		return nil, statusError(err, "build")
+	}
+
+	return s.fetchBuild(build.ID)
+}
+
+// AppendRemarks stores remarks for an in-progress build as they arrive
+func (s *Server) AppendRemarks(stream buildv1.BuildService_AppendRemarksServer) error {
+	store := s.store(stream.Context())
+	// Stable IDs stored for each build, loaded once per stream
+	seen := make(map[string]map[string]bool)
+	var stored int64
+
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return stream.SendAndClose(&buildv1.AppendRemarksResponse{Stored: stored})
+		}
+		if err != nil {
+			return err
+		}
+
+		if seen[req.BuildId] == nil {
+			inProgress, err := store.BuildInProgress(req.BuildId)
+			if err != nil {
+				return statusError(err, "build")
+			}
+			if !inProgress {
+				return status.Error(codes.FailedPrecondition, "build is already finalized")
+			}
+			existing, err := storedRemarkIDs(s.db.DB, req.BuildId)
+			if err != nil {
+				return statusError(err, "remarks")
+			}
+			seen[req.BuildId] = existing
+		}
+
+		build := models.Build{ID: req.BuildId}
+		remarks := make([]*models.CompilerRemark, 0, len(req.Remarks))
+		for _, remark := range req.Remarks {
+			remarks = append(remarks, createCompilerRemark(build, remark))
+		}
+
+		release, err := s.writes.acquire(stream.Context())
+		if err != nil {
+			return err
+		}
+		fresh := newRemarks(seen[build.ID], remarks)
+		err = s.db.DB.Transaction(func(tx *gorm.DB) error {
+			return s.createRemarks(tx, fresh)
+		})
+		release()
+		if err != nil {
+			return statusError(err, "remarks")
+		}
+		stored += int64(len(fresh))
+	}
+}
+
+// FinalizeBuild completes an in-progress build with the full record.
+// Remarks that were already streamed are not stored twice.
+func (s *Server) FinalizeBuild(ctx context.Context, req *buildv1.CreateBuildRequest) (*buildv1.Build, error) {
+	if req.Build == nil {
+		return nil, status.Error(codes.InvalidArgument, "build is required")
+	}
+	received := time.Now()
+
+	inProgress, err := s.store(ctx).BuildInProgress(req.Build.Id)
+	if err != nil {
+		return nil, statusError(err, "build")
+	}
+	if !inProgress {
+		return nil, status.Error(codes.FailedPrecondition, "build is already finalized")
+	}
+
+	build := models.Build{ID: req.Build.Id}
+	remarks := make([]*models.CompilerRemark, 0, len(req.Build.Remarks))
+	for _, remark := range req.Build.Remarks {
+		remarks = append(remarks, createCompilerRemark(build, remark))
+	}
+	assignStableRemarkIDs(build.ID, remarks)
+
+	release, err := s.writes.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	duration, reported := checkDuration(req.Build)
+	updates := map[string]interface{}{
+		"end_time":          req.Build.EndTime.AsTime(),
+		"duration":          duration,
+		"reported_duration": reported,
+		"cache_status":      req.Build.CacheStatus,
+		"success":           req.Build.Success,
+		"error":             req.Build.Error,
+		"in_progress":       false,
+		"finished_at":       time.Now(),
+
+		"remarks_truncated": req.Build.RemarksTruncated,
+		"remarks_seen":      req.Build.RemarksSeen,
+		"raw_truncated":     req.Build.RawRemarksTruncated,
+	}
+	if s.opts.ServerTimestamps {
+		// The client start time was kept when the build began
+		start, end := serverTimes(received, duration)
+		updates["start_time"] = start
+		updates["end_time"] = end
+		updates["client_end_time"] = clientTime(req.Build.EndTime)
+	}
+
+	err = s.db.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.Build{}).Where("id = ?", build.ID).Updates(updates).Error
+		if err != nil {
+			return fmt.Errorf("failed to finalize build: %w", err)
+		}
+
+		if err := s.createDetails(tx, build.ID, req.Build); err != nil {
+			return err
+		}
+
+		existing, err := storedRemarkIDs(tx, build.ID)
+		if err != nil {
+			return err
+		}
+		return s.createRemarks(tx, newRemarks(existing, remarks))
+	})
+	if err != nil {
+		return nil, statusError(err, "build")
+	}
+
+	return s.fetchBuild(build.ID)
+}
+
+// storedRemarkIDs returns the stable IDs of the remarks stored for a build
+func storedRemarkIDs(tx *gorm.DB, buildID string) (map[string]bool, error) {
+	var existing []string
+	if err := tx.Model(&models.CompilerRemark{}).
+		Where("build_id = ? AND stable_id <> ''", buildID).
+		Pluck("stable_id", &existing).Error; err != nil {
+		return nil, fmt.Errorf("failed to load existing remarks: %w", err)
+	}
+
+	seen := make(map[string]bool, len(existing))
+	for _, id := range existing {
+		seen[id] = true
+	}
+	return seen, nil
+}
+
+// newRemarks drops remarks whose stable ID is in seen, adding the IDs of
+// the others to it
+func newRemarks(seen map[string]bool, remarks []*models.CompilerRemark) []*models.CompilerRemark {
+	fresh := remarks[:0]
+	for _, remark := range remarks {
+		if remark.StableID != "" && seen[remark.StableID] {
+			continue
+		}
+		seen[remark.StableID] = true
+		fresh = append(fresh, remark)
+	}
+	return fresh
+}
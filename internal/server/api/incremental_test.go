@@ -0,0 +1,214 @@
+package api
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	buildv1 "builds/api/build"
+	models "builds/internal/server/db/models"
+)
+
+func TestNewRemarks(t *testing.T) {
+	seen := map[string]bool{"a": true}
+	remarks := []*models.CompilerRemark{
+		{StableID: "a"}, {StableID: "b"}, {StableID: "b"}, {}, {}, {StableID: "c"},
+	}
+
+	var got []string
+	for _, remark := range newRemarks(seen, remarks) {
+		got = append(got, remark.StableID)
+	}
+	// Remarks without an ID cannot be told apart and are all kept
+	if want := []string{"b", "", "", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("fresh remarks %q, want %q", got, want)
+	}
+	if !seen["b"] || !seen["c"] {
+		t.Errorf("seen %v is missing the new IDs", seen)
+	}
+}
+
+func TestAppendRemarksBeforeFinalize(t *testing.T) {
+	client := newTestClient(t, Options{})
+	ctx := context.Background()
+
+	remark := func(id string) *buildv1.CompilerRemark {
+		return &buildv1.CompilerRemark{Id: id, Name: "NotVectorized", PassName: "loop-vectorize"}
+	}
+	remarkIDs := func() ([]string, bool) {
+		t.Helper()
+		build, err := client.GetBuild(ctx, &buildv1.GetBuildRequest{Id: "b1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []string
+		for _, r := range build.Remarks {
+			ids = append(ids, r.Id)
+		}
+		return ids, build.InProgress
+	}
+	appendRemarks := func(batches ...[]*buildv1.CompilerRemark) (int64, error) {
+		t.Helper()
+		stream, err := client.AppendRemarks(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, batch := range batches {
+			if err := stream.Send(&buildv1.AppendRemarksRequest{BuildId: "b1", Remarks: batch}); err != nil {
+				break
+			}
+		}
+		resp, err := stream.CloseAndRecv()
+		return resp.GetStored(), err
+	}
+
+	if _, err := client.BeginBuild(ctx, &buildv1.CreateBuildRequest{Build: &buildv1.Build{Id: "b1"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	stored, err := appendRemarks([]*buildv1.CompilerRemark{remark("r1"), remark("r2")})
+	if err != nil || stored != 2 {
+		t.Fatalf("stored %d remarks: %v", stored, err)
+	}
+	ids, inProgress := remarkIDs()
+	if !inProgress || !reflect.DeepEqual(ids, []string{"r1", "r2"}) {
+		t.Errorf("before finalizing: remarks %v, in progress %v", ids, inProgress)
+	}
+
+	// Resent remarks are dropped, whether stored by this stream or another
+	stored, err = appendRemarks(
+		[]*buildv1.CompilerRemark{remark("r2"), remark("r3")},
+		[]*buildv1.CompilerRemark{remark("r3")},
+	)
+	if err != nil || stored != 1 {
+		t.Fatalf("stored %d remarks, want 1: %v", stored, err)
+	}
+
+	_, err = client.FinalizeBuild(ctx, &buildv1.CreateBuildRequest{Build: &buildv1.Build{
+		Id:      "b1",
+		Success: true,
+		Remarks: []*buildv1.CompilerRemark{remark("r1"), remark("r2"), remark("r3"), remark("r4")},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids, inProgress = remarkIDs()
+	if inProgress || !reflect.DeepEqual(ids, []string{"r1", "r2", "r3", "r4"}) {
+		t.Errorf("after finalizing: remarks %v, in progress %v", ids, inProgress)
+	}
+
+	if _, err := appendRemarks([]*buildv1.CompilerRemark{remark("r5")}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("appending to a finalized build: %v, want FailedPrecondition", err)
+	}
+}
+
+func TestStreamRemarks(t *testing.T) {
+	client := newTestClient(t, Options{})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	remark := func(id string) *buildv1.CompilerRemark {
+		return &buildv1.CompilerRemark{Id: id, Name: "NotVectorized", PassName: "loop-vectorize"}
+	}
+	appendRemarks := func(remarks ...*buildv1.CompilerRemark) {
+		t.Helper()
+		stream, err := client.AppendRemarks(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := stream.Send(&buildv1.AppendRemarksRequest{BuildId: "b1", Remarks: remarks}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := stream.CloseAndRecv(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := client.BeginBuild(ctx, &buildv1.CreateBuildRequest{Build: &buildv1.Build{Id: "b1"}}); err != nil {
+		t.Fatal(err)
+	}
+	appendRemarks(remark("r1"), remark("r2"))
+
+	tail, err := client.StreamRemarks(ctx, &buildv1.StreamRemarksRequest{BuildId: "b1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	receive := func(want string) {
+		t.Helper()
+		got, err := tail.Recv()
+		if err != nil {
+			t.Fatalf("waiting for %s: %v", want, err)
+		}
+		if got.Id != want {
+			t.Errorf("received %s, want %s", got.Id, want)
+		}
+	}
+
+	// Remarks stored before the stream opened come first
+	receive("r1")
+	receive("r2")
+
+	// Then those stored while it is open
+	appendRemarks(remark("r3"))
+	receive("r3")
+
+	// Finalizing delivers the remaining remarks and ends the stream
+	_, err = client.FinalizeBuild(ctx, &buildv1.CreateBuildRequest{Build: &buildv1.Build{
+		Id:      "b1",
+		Remarks: []*buildv1.CompilerRemark{remark("r1"), remark("r2"), remark("r3"), remark("r4")},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	receive("r4")
+	if _, err := tail.Recv(); err != io.EOF {
+		t.Errorf("stream after finalizing: %v, want io.EOF", err)
+	}
+}
+
+func TestStreamRemarksFinishedBuild(t *testing.T) {
+	client := newTestClient(t, Options{})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := client.CreateBuild(ctx, &buildv1.CreateBuildRequest{Build: &buildv1.Build{
+		Id:      "b1",
+		Remarks: []*buildv1.CompilerRemark{{Id: "r1"}, {Id: "r2"}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tail, err := client.StreamRemarks(ctx, &buildv1.StreamRemarksRequest{BuildId: "b1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for {
+		remark, err := tail.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, remark.Id)
+	}
+	if !reflect.DeepEqual(ids, []string{"r1", "r2"}) {
+		t.Errorf("streamed %v, want [r1 r2]", ids)
+	}
+
+	// Stream errors arrive with the first receive
+	tail, err = client.StreamRemarks(ctx, &buildv1.StreamRemarksRequest{BuildId: "missing"})
+	if err == nil {
+		_, err = tail.Recv()
+	}
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("streaming a missing build: %v, want NotFound", err)
+	}
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	buildv1 "builds/api/build"
+	"builds/internal/server/db/dbtest"
+	models "builds/internal/server/db/models"
+)
+
+func TestFlatDiagnostic(t *testing.T) {
+	tests := []struct {
+		name     string
+		severity string
+		text     string
+		want     models.Diagnostic
+	}{
+		{
+			"compiler format", "warning", "foo.c:3:5: warning: unused variable 'x'",
+			models.Diagnostic{BuildID: "b1", Severity: "warning", File: "foo.c", Line: 3, Col: 5, Message: "unused variable 'x'"},
+		},
+		{
+			"free text", "error", "linker failed",
+			models.Diagnostic{BuildID: "b1", Severity: "error", Message: "linker failed"},
+		},
+		{
+			// Listed as an error, so its text is kept whole
+			"other severity", "error", "foo.c:3:5: warning: unused variable 'x'",
+			models.Diagnostic{BuildID: "b1", Severity: "error", Message: "foo.c:3:5: warning: unused variable 'x'"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := flatDiagnostic("b1", tt.severity, tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetBuildKeepsFlatOutput(t *testing.T) {
+	s := NewServer(dbtest.Open(t), Options{})
+	ctx := context.Background()
+	output := &buildv1.Output{
+		Warnings: []string{"foo.c:3:5: warning: unused variable 'x'"},
+		Errors:   []string{"linker failed"},
+	}
+	if _, err := s.CreateBuild(ctx, &buildv1.CreateBuildRequest{Build: &buildv1.Build{Id: "b1", Output: output}}); err != nil {
+		t.Fatal(err)
+	}
+
+	build, err := s.GetBuild(ctx, &buildv1.GetBuildRequest{Id: "b1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(build.Output.GetWarnings(), output.Warnings) {
+		t.Errorf("warnings %q, want %q", build.Output.GetWarnings(), output.Warnings)
+	}
+	if !reflect.DeepEqual(build.Output.GetErrors(), []string{"error: linker failed"}) {
+		t.Errorf("errors %q", build.Output.GetErrors())
+	}
+	if len(build.Output.GetDiagnostics()) != 2 {
+		t.Errorf("got %d diagnostics, want 2", len(build.Output.GetDiagnostics()))
+	}
+}
+
+func TestGetBuildKeepsLargeOutput(t *testing.T) {
+	s := NewServer(dbtest.Open(t), Options{})
+	ctx := context.Background()
+	output := &buildv1.Output{
+		Stdout: "compiled foo.c\n",
+		Stderr: strings.Repeat("foo.h:12:3: error: no matching function for call to 'bar'\n", 2000),
+	}
+	if _, err := s.CreateBuild(ctx, &buildv1.CreateBuildRequest{Build: &buildv1.Build{Id: "b1", Output: output}}); err != nil {
+		t.Fatal(err)
+	}
+
+	build, err := s.GetBuild(ctx, &buildv1.GetBuildRequest{Id: "b1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if build.Output.GetStdout() != output.Stdout {
+		t.Errorf("stdout %q, want %q", build.Output.GetStdout(), output.Stdout)
+	}
+	if build.Output.GetStderr() != output.Stderr {
+		t.Errorf("stderr of %d bytes, want %d", len(build.Output.GetStderr()), len(output.Stderr))
+	}
+}
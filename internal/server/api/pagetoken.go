@@ -0,0 +1,59 @@
+// internal/server/api/pagetoken.go
+
+package api
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"builds/internal/server/db"
+)
+
+// Page sizes for ListBuilds when the request asks for none or too many
+const (
+	defaultPageSize = 50
+	maxPageSize     = 1000
+)
+
+var errInvalidPageToken = errors.New("invalid page token")
+
+func pageSize(requested int32) int {
+	switch {
+	case requested <= 0:
+		return defaultPageSize
+	case requested > maxPageSize:
+		return maxPageSize
+	}
+	return int(requested)
+}
+
+// encodePageToken makes an opaque, URL-safe token from the last build of a
+// page: its creation time in nanoseconds and its ID
+func encodePageToken(cursor db.Cursor) string {
+	raw := strconv.FormatInt(cursor.CreatedAt.UnixNano(), 10) + ":" + cursor.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodePageToken reads a token from encodePageToken. An empty token
+// starts from the newest build.
+func decodePageToken(token string) (*db.Cursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, errInvalidPageToken
+	}
+	nanos, id, ok := strings.Cut(string(raw), ":")
+	if !ok || id == "" {
+		return nil, errInvalidPageToken
+	}
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return nil, errInvalidPageToken
+	}
+	return &db.Cursor{CreatedAt: time.Unix(0, n).UTC(), ID: id}, nil
+}
@@ -0,0 +1,170 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	buildv1 "builds/api/build"
+	"builds/internal/server/db"
+	"builds/internal/server/db/dbtest"
+	models "builds/internal/server/db/models"
+)
+
+func TestPageToken(t *testing.T) {
+	cursor := db.Cursor{
+		CreatedAt: time.Date(2024, 3, 1, 12, 0, 0, 123456789, time.UTC),
+		ID:        "build:with/odd+chars",
+	}
+	token := encodePageToken(cursor)
+	if url.QueryEscape(token) != token {
+		t.Errorf("token %q is not URL-safe", token)
+	}
+
+	got, err := decodePageToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.CreatedAt.Equal(cursor.CreatedAt) || got.ID != cursor.ID {
+		t.Errorf("got %+v, want %+v", got, cursor)
+	}
+
+	if got, err := decodePageToken(""); got != nil || err != nil {
+		t.Errorf("empty token gave %+v, %v", got, err)
+	}
+	for _, token := range []string{"not base64!", "MTIz", "OjEyMw", "YWJjOmI"} {
+		if _, err := decodePageToken(token); err != errInvalidPageToken {
+			t.Errorf("decodePageToken(%q): %v, want %v", token, err, errInvalidPageToken)
+		}
+	}
+}
+
+func TestListBuildsPagination(t *testing.T) {
+	database := dbtest.Open(t)
+	s := NewServer(database, Options{})
+	ctx := context.Background()
+
+	// Builds are stored three to an instant, so pages end inside a tie
+	const n = 120
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range n {
+		build := models.Build{
+			ID:        fmt.Sprintf("build-%03d", i),
+			CreatedAt: start.Add(time.Duration(i/3) * time.Second),
+		}
+		if err := database.DB.Create(&build).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	seen := make(map[string]bool)
+	var order []string
+	token := ""
+	pages := 0
+	for {
+		resp, err := s.ListBuilds(ctx, &buildv1.ListBuildsRequest{PageSize: 50, PageToken: token})
+		if err != nil {
+			t.Fatal(err)
+		}
+		pages++
+		for _, build := range resp.Builds {
+			if seen[build.Id] {
+				t.Errorf("build %s listed twice", build.Id)
+			}
+			seen[build.Id] = true
+			order = append(order, build.Id)
+		}
+		if resp.NextPageToken == "" {
+			break
+		}
+		token = resp.NextPageToken
+	}
+
+	if len(seen) != n || pages != 3 {
+		t.Errorf("listed %d builds over %d pages, want %d over 3", len(seen), pages, n)
+	}
+	// Newest first, the ID breaking ties
+	for i, id := range order {
+		if want := fmt.Sprintf("build-%03d", n-1-i); id != want {
+			t.Fatalf("build %d is %s, want %s", i, id, want)
+		}
+	}
+
+	_, err := s.ListBuilds(ctx, &buildv1.ListBuildsRequest{PageToken: "garbage"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("invalid token: %v, want InvalidArgument", err)
+	}
+}
+
+func TestListBuildsFilteredPagination(t *testing.T) {
+	database := dbtest.Open(t)
+	s := NewServer(database, Options{})
+	ctx := context.Background()
+
+	// Compilers alternate, every third build failed and the first ten were
+	// stored before the time bound; builds share instants in pairs
+	const n = 90
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var want []string
+	for i := range n {
+		compiler := "gcc"
+		if i%2 == 1 {
+			compiler = "clang"
+		}
+		build := models.Build{
+			ID:        fmt.Sprintf("build-%03d", i),
+			Success:   i%3 != 0,
+			CreatedAt: start.Add(time.Duration(i/2) * time.Second),
+			Compiler:  models.Compiler{Name: compiler},
+		}
+		if err := database.DB.Create(&build).Error; err != nil {
+			t.Fatal(err)
+		}
+		if compiler == "gcc" && build.Success && i >= 10 {
+			want = append([]string{build.ID}, want...)
+		}
+	}
+
+	var order []string
+	token := ""
+	pages := 0
+	for {
+		resp, err := s.ListBuilds(ctx, &buildv1.ListBuildsRequest{
+			PageSize:  4,
+			PageToken: token,
+			Compiler:  "gcc",
+			Outcome:   buildv1.ListBuildsRequest_OUTCOME_SUCCEEDED,
+			Since:     timestamppb.New(start.Add(5 * time.Second)),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		pages++
+		if len(resp.Builds) > 4 {
+			t.Errorf("page %d has %d builds", pages, len(resp.Builds))
+		}
+		for _, build := range resp.Builds {
+			order = append(order, build.Id)
+		}
+		if resp.NextPageToken == "" {
+			break
+		}
+		token = resp.NextPageToken
+	}
+
+	// Every page holds only matching builds, and the pages together hold
+	// each of them once, newest first
+	if !reflect.DeepEqual(order, want) {
+		t.Errorf("listed %v, want %v", order, want)
+	}
+	if wantPages := (len(want) + 3) / 4; pages != wantPages {
+		t.Errorf("listed over %d pages, want %d", pages, wantPages)
+	}
+}
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	buildv1 "builds/api/build"
+	"builds/internal/server/db/dbtest"
+)
+
+func TestGetBuildKeepsPerformance(t *testing.T) {
+	s := NewServer(dbtest.Open(t), Options{})
+	ctx := context.Background()
+	performance := &buildv1.Performance{
+		CompileTime:  12.5,
+		LinkTime:     1.25,
+		OptimizeTime: 4,
+		Phases:       map[string]float64{"parse": 2.5, "codegen": 6},
+	}
+	_, err := s.CreateBuild(ctx, &buildv1.CreateBuildRequest{Build: &buildv1.Build{Id: "b1", Performance: performance}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	build, err := s.GetBuild(ctx, &buildv1.GetBuildRequest{Id: "b1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := build.Performance
+	if got.GetCompileTime() != 12.5 || got.GetLinkTime() != 1.25 || got.GetOptimizeTime() != 4 {
+		t.Errorf("compile %v, link %v, optimize %v", got.GetCompileTime(), got.GetLinkTime(), got.GetOptimizeTime())
+	}
+	if !reflect.DeepEqual(got.GetPhases(), performance.Phases) {
+		t.Errorf("phases %v, want %v", got.GetPhases(), performance.Phases)
+	}
+}
@@ -0,0 +1,103 @@
+// internal/server/api/raw.go
+
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"log"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	buildv1 "builds/api/build"
+)
+
+// rawChunkSize bounds the payload of each streamed chunk
+const rawChunkSize = 64 * 1024
+
+// maxRawRemarksSize bounds the compressed record accepted for one build
+const maxRawRemarksSize = 64 << 20
+
+// GetRawRemarks streams the decompressed optimization record of a build
+func (s *Server) GetRawRemarks(req *buildv1.GetRawRemarksRequest, stream buildv1.BuildService_GetRawRemarksServer) error {
+	raw, err := s.store(stream.Context()).GetRawRemarks(req.Id)
+	if err != nil {
+		return statusError(err, "raw remarks")
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(raw.Data))
+	if err != nil {
+		log.Printf("Corrupt raw remarks for build %s: %v", req.Id, err)
+		return status.Error(codes.DataLoss, "corrupt raw remarks")
+	}
+	defer zr.Close()
+
+	buf := make([]byte, rawChunkSize)
+	for {
+		n, err := io.ReadFull(zr, buf)
+		if n > 0 {
+			if sendErr := stream.Send(&buildv1.RawRemarksChunk{Data: buf[:n]}); sendErr != nil {
+				return sendErr
+			}
+		}
+		switch {
+		case err == io.EOF || errors.Is(err, io.ErrUnexpectedEOF):
+			return nil
+		case err != nil:
+			log.Printf("Corrupt raw remarks for build %s: %v", req.Id, err)
+			return status.Error(codes.DataLoss, "corrupt raw remarks")
+		}
+	}
+}
+
+// UploadRawRemarks attaches a raw optimization record, sent in chunks, to a
+// stored build. With storage disabled the record is read and discarded.
+func (s *Server) UploadRawRemarks(stream buildv1.BuildService_UploadRawRemarksServer) error {
+	var (
+		buildID string
+		data    []byte
+	)
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		switch {
+		case req.BuildId == "":
+			return status.Error(codes.InvalidArgument, "build_id is required")
+		case buildID != "" && req.BuildId != buildID:
+			return status.Error(codes.InvalidArgument, "all chunks must name the same build")
+		case len(data)+len(req.Data) > maxRawRemarksSize:
+			return status.Errorf(codes.ResourceExhausted, "raw remarks exceed %d bytes", maxRawRemarksSize)
+		}
+		buildID = req.BuildId
+		data = append(data, req.Data...)
+	}
+	if buildID == "" {
+		return status.Error(codes.InvalidArgument, "build_id is required")
+	}
+
+	resp := &buildv1.UploadRawRemarksResponse{Size: int64(len(data))}
+	if !s.opts.StoreRawRemarks {
+		log.Printf("Discarding raw remarks for build %s: storage disabled", buildID)
+		return stream.SendAndClose(resp)
+	}
+
+	release, err := s.writes.acquire(stream.Context())
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if err := s.store(stream.Context()).StoreRawRemarks(buildID, data); err != nil {
+		return statusError(err, "build")
+	}
+	resp.Stored = true
+	return stream.SendAndClose(resp)
+}
@@ -0,0 +1,148 @@
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	buildv1 "builds/api/build"
+	"builds/internal/client"
+)
+
+// rawRecord is an optimization record spanning several streamed chunks
+func rawRecord() []byte {
+	var buf bytes.Buffer
+	for i := 0; buf.Len() < 3*rawChunkSize; i++ {
+		fmt.Fprintf(&buf, "--- !Missed\nPass: loop-vectorize\nName: MissedDetails\nDebugLoc: { File: foo.c, Line: %d, Column: 3 }\nFunction: foo\n", i)
+	}
+	return buf.Bytes()
+}
+
+func gzipped(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// getRawRemarks reads the whole raw record of a build
+func getRawRemarks(t *testing.T, client buildv1.BuildServiceClient, id string) ([]byte, error) {
+	t.Helper()
+	stream, err := client.GetRawRemarks(context.Background(), &buildv1.GetRawRemarksRequest{Id: id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var data []byte
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			return data, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, chunk.Data...)
+	}
+}
+
+func TestRawRemarks(t *testing.T) {
+	record := rawRecord()
+	tests := []struct {
+		name     string
+		store    bool
+		wantCode codes.Code
+	}{
+		{"stored", true, codes.OK},
+		{"storage disabled", false, codes.NotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, Options{StoreRawRemarks: tt.store})
+			_, err := client.CreateBuild(context.Background(), &buildv1.CreateBuildRequest{Build: &buildv1.Build{
+				Id:         "b1",
+				RawRemarks: gzipped(t, record),
+			}})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := getRawRemarks(t, client, "b1")
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("GetRawRemarks: %v, want %s", err, tt.wantCode)
+			}
+			if tt.wantCode == codes.OK && !bytes.Equal(got, record) {
+				t.Errorf("got %d bytes back, want the %d byte record", len(got), len(record))
+			}
+		})
+	}
+}
+
+func TestRawRemarksMissingBuild(t *testing.T) {
+	client := newTestClient(t, Options{StoreRawRemarks: true})
+	if _, err := getRawRemarks(t, client, "missing"); status.Code(err) != codes.NotFound {
+		t.Errorf("GetRawRemarks of a missing build: %v, want NotFound", err)
+	}
+}
+
+func TestUploadRawRemarks(t *testing.T) {
+	// Larger than gRPC's default 4 MiB message limit, which the harness keeps
+	record := make([]byte, 6<<20)
+	for i := range record {
+		record[i] = byte(i % 251)
+	}
+
+	tests := []struct {
+		name       string
+		store      bool
+		wantStored bool
+		wantCode   codes.Code
+	}{
+		{"stored", true, true, codes.OK},
+		{"storage disabled", false, false, codes.NotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTestClient(t, Options{StoreRawRemarks: tt.store})
+			ctx := context.Background()
+			if _, err := service.CreateBuild(ctx, &buildv1.CreateBuildRequest{Build: &buildv1.Build{Id: "b1"}}); err != nil {
+				t.Fatal(err)
+			}
+
+			stored, err := client.NewFromService(service).UploadRawRemarks(ctx, "b1", gzipped(t, record))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stored != tt.wantStored {
+				t.Errorf("stored = %v, want %v", stored, tt.wantStored)
+			}
+
+			got, err := getRawRemarks(t, service, "b1")
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("GetRawRemarks: %v, want %s", err, tt.wantCode)
+			}
+			if tt.wantCode == codes.OK && !bytes.Equal(got, record) {
+				t.Errorf("got %d bytes back, want the %d byte record", len(got), len(record))
+			}
+		})
+	}
+}
+
+func TestUploadRawRemarksMissingBuild(t *testing.T) {
+	service := newTestClient(t, Options{StoreRawRemarks: true})
+	_, err := client.NewFromService(service).UploadRawRemarks(context.Background(), "missing", []byte("data"))
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("UploadRawRemarks to a missing build: %v, want NotFound", err)
+	}
+}
@@ -0,0 +1,407 @@
+// internal/server/api/remarks.go
+
+package api
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	buildv1 "builds/api/build"
+	coremodels "builds/internal/models"
+	"builds/internal/server/db"
+	models "builds/internal/server/db/models"
+)
+
+// GetRemark returns a single remark of a build, including its arguments and
+// kernel info, so tools can link to a specific diagnostic
+func (s *Server) GetRemark(ctx context.Context, req *buildv1.GetRemarkRequest) (*buildv1.CompilerRemark, error) {
+	if req.BuildId == "" || req.RemarkId == "" {
+		return nil, status.Error(codes.InvalidArgument, "build_id and remark_id are required")
+	}
+
+	remark, err := s.store(ctx).GetRemark(req.BuildId, req.RemarkId)
+	if err != nil {
+		return nil, statusError(err, "remark")
+	}
+
+	return remarkToProto(remark), nil
+}
+
+// Remarks of builds being ingested are polled for at this interval and sent
+// in batches of at most remarkBatchSize
+const (
+	remarkPollInterval = time.Second
+	remarkBatchSize    = 500
+)
+
+// StreamRemarks sends the remarks stored for a build and, while the build is
+// still being ingested, those appended afterwards. The stream ends once a
+// finalized build's remarks have all been sent.
+func (s *Server) StreamRemarks(req *buildv1.StreamRemarksRequest, stream buildv1.BuildService_StreamRemarksServer) error {
+	if req.BuildId == "" {
+		return status.Error(codes.InvalidArgument, "build_id is required")
+	}
+
+	ctx := stream.Context()
+	store := s.store(ctx)
+	ticker := time.NewTicker(remarkPollInterval)
+	defer ticker.Stop()
+
+	var lastID uint
+	for {
+		// Finalizing stores the last remarks in the same transaction that
+		// clears in_progress, so a finished build is fully drained below
+		inProgress, err := store.BuildInProgress(req.BuildId)
+		if err != nil {
+			return statusError(err, "build")
+		}
+
+		for {
+			remarks, err := store.RemarksAfter(req.BuildId, lastID, remarkBatchSize)
+			if err != nil {
+				return statusError(err, "remarks")
+			}
+			for i := range remarks {
+				if err := stream.Send(remarkToProto(&remarks[i])); err != nil {
+					return err
+				}
+				lastID = remarks[i].ID
+			}
+			if len(remarks) < remarkBatchSize {
+				break
+			}
+		}
+
+		if !inProgress {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// PurgeRemarks deletes the remarks of a build to save space, keeping the
+// build, its other details and its remark counts. Builds still streaming
+// remarks are refused.
+func (s *Server) PurgeRemarks(ctx context.Context, req *buildv1.PurgeRemarksRequest) (*buildv1.PurgeRemarksResponse, error) {
+	if req.BuildId == "" {
+		return nil, status.Error(codes.InvalidArgument, "build_id is required")
+	}
+
+	release, err := s.writes.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	store := s.store(ctx)
+	inProgress, err := store.BuildInProgress(req.BuildId)
+	if err != nil {
+		return nil, statusError(err, "build")
+	}
+	if inProgress {
+		return nil, status.Error(codes.FailedPrecondition, "build is still in progress")
+	}
+
+	purged, err := store.PurgeRemarks(req.BuildId)
+	if err != nil {
+		return nil, statusError(err, "build")
+	}
+	return &buildv1.PurgeRemarksResponse{Purged: purged}, nil
+}
+
+// RemarkHistory reports when a remark first and last appeared among the
+// builds sharing a label
+func (s *Server) RemarkHistory(ctx context.Context, req *buildv1.RemarkHistoryRequest) (*buildv1.RemarkHistoryResponse, error) {
+	if req.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "remark name is required")
+	}
+
+	history, err := s.store(ctx).RemarkHistory(req.LabelKey, req.LabelValue, db.RemarkKey{
+		Name:     req.Name,
+		Function: req.Function,
+		Pass:     req.Pass,
+	})
+	if err != nil {
+		return nil, statusError(err, "remark history")
+	}
+
+	return &buildv1.RemarkHistoryResponse{
+		Builds:   history.Builds,
+		First:    buildRefToProto(history.First),
+		Last:     buildRefToProto(history.Last),
+		Latest:   buildRefToProto(history.Latest),
+		InLatest: history.InLatest(),
+	}, nil
+}
+
+func buildRefToProto(ref *db.BuildRef) *buildv1.BuildRef {
+	if ref == nil {
+		return nil
+	}
+	return &buildv1.BuildRef{Id: ref.ID, CreatedAt: timestamppb.New(ref.CreatedAt)}
+}
+
+// createCompilerRemark converts a protobuf remark into its database model
+func createCompilerRemark(build models.Build, remark *buildv1.CompilerRemark) *models.CompilerRemark {
+	dbRemark := &models.CompilerRemark{
+		StableID: remark.Id,
+		BuildID:  build.ID,
+		Type:     strings.ToLower(remark.Type.String()),
+		Pass:     strings.ToLower(remark.Pass.String()),
+		PassName: remark.PassName,
+		Name:     remark.Name,
+		Status:   strings.ToLower(remark.Status.String()),
+		Message:  remark.Message,
+		Function: remark.Function,
+		Hotness:  remark.Hotness,
+		Location: locationFromProto(remark.Location),
+	}
+
+	if remark.Timestamp != nil {
+		dbRemark.Timestamp = remark.Timestamp.AsTime()
+	}
+
+	if remark.Metadata != nil {
+		dbRemark.Metadata = remark.Metadata.AsMap()
+	}
+
+	if args := remark.Args; args != nil {
+		dbRemark.Args = models.RemarkArgs{
+			Strings: args.Strings,
+			Callee:  args.Callee,
+			Caller:  args.Caller,
+			Type:    args.Type,
+			Line:    args.Line,
+			Column:  args.Column,
+			Cost:    args.Cost,
+			Reason:  args.Reason,
+			Values:  args.Values,
+		}
+		if args.DebugLoc != nil {
+			loc := locationFromProto(args.DebugLoc)
+			dbRemark.Args.DebugLoc = &loc
+		}
+		if args.OtherAccess != nil {
+			dbRemark.Args.OtherAccess = remarkAccessFromProto(args.OtherAccess)
+		}
+		if args.ClobberedBy != nil {
+			dbRemark.Args.ClobberedBy = remarkAccessFromProto(args.ClobberedBy)
+		}
+	}
+
+	if ki := remark.KernelInfo; ki != nil {
+		dbRemark.KernelInfo = &models.KernelInfo{
+			ThreadLimit:              ki.ThreadLimit,
+			MaxThreadsX:              ki.MaxThreadsX,
+			MaxThreadsY:              ki.MaxThreadsY,
+			MaxThreadsZ:              ki.MaxThreadsZ,
+			SharedMemory:             ki.SharedMemory,
+			Target:                   ki.Target,
+			DirectCalls:              ki.DirectCalls,
+			IndirectCalls:            ki.IndirectCalls,
+			Callees:                  ki.Callees,
+			AllocasCount:             ki.AllocasCount,
+			AllocasStaticSize:        ki.AllocasStaticSize,
+			AllocasDynamicCount:      ki.AllocasDynamicCount,
+			FlatAddressSpaceAccesses: ki.FlatAddressSpaceAccesses,
+			InlineAssemblyCalls:      ki.InlineAssemblyCalls,
+		}
+
+		if len(ki.Metrics) > 0 {
+			dbRemark.KernelInfo.Metrics = make(models.JSON, len(ki.Metrics))
+			for k, v := range ki.Metrics {
+				dbRemark.KernelInfo.Metrics[k] = v
+			}
+		}
+		if len(ki.Attributes) > 0 {
+			dbRemark.KernelInfo.Attributes = make(models.JSON, len(ki.Attributes))
+			for k, v := range ki.Attributes {
+				dbRemark.KernelInfo.Attributes[k] = v
+			}
+		}
+
+		dbRemark.KernelInfo.MemoryAccesses = make([]models.MemoryAccess, 0, len(ki.MemoryAccesses))
+		for _, acc := range ki.MemoryAccesses {
+			if acc == nil {
+				continue
+			}
+			dbRemark.KernelInfo.MemoryAccesses = append(dbRemark.KernelInfo.MemoryAccesses, models.MemoryAccess{
+				Type:          acc.Type,
+				AddressSpace:  acc.AddressSpace,
+				Instruction:   acc.Instruction,
+				Variable:      acc.Variable,
+				AccessPattern: acc.AccessPattern,
+				Location:      locationFromProto(acc.Location),
+			})
+		}
+	}
+
+	return dbRemark
+}
+
+// assignStableRemarkIDs fills in IDs for remarks sent by clients that
+// predate stable remark IDs
+func assignStableRemarkIDs(buildID string, remarks []*models.CompilerRemark) {
+	ordinals := make(map[string]int)
+	for _, remark := range remarks {
+		if remark.StableID != "" {
+			continue
+		}
+		loc := coremodels.Location{
+			File:   remark.Location.File,
+			Line:   remark.Location.Line,
+			Column: remark.Location.Column,
+		}
+		key := coremodels.RemarkID(buildID, remark.Pass, remark.Name, loc, 0)
+		remark.StableID = coremodels.RemarkID(buildID, remark.Pass, remark.Name, loc, ordinals[key])
+		ordinals[key]++
+	}
+}
+
+// remarkToProto converts a stored remark into its protobuf representation
+func remarkToProto(remark *models.CompilerRemark) *buildv1.CompilerRemark {
+	pb := &buildv1.CompilerRemark{
+		Id:        remark.StableID,
+		Type:      buildv1.CompilerRemark_Type(buildv1.CompilerRemark_Type_value[strings.ToUpper(remark.Type)]),
+		Pass:      buildv1.CompilerRemark_Pass(buildv1.CompilerRemark_Pass_value[strings.ToUpper(remark.Pass)]),
+		Status:    buildv1.CompilerRemark_Status(buildv1.CompilerRemark_Status_value[strings.ToUpper(remark.Status)]),
+		Name:      remark.Name,
+		PassName:  remark.PassName,
+		Message:   remark.Message,
+		Function:  remark.Function,
+		Timestamp: timestamppb.New(remark.Timestamp),
+		Location:  locationToProto(remark.Location),
+		Hotness:   remark.Hotness,
+		Args: &buildv1.RemarkArgs{
+			Strings: remark.Args.Strings,
+			Callee:  remark.Args.Callee,
+			Caller:  remark.Args.Caller,
+			Type:    remark.Args.Type,
+			Line:    remark.Args.Line,
+			Column:  remark.Args.Column,
+			Cost:    remark.Args.Cost,
+			Reason:  remark.Args.Reason,
+			Values:  remark.Args.Values,
+		},
+	}
+
+	if remark.Args.DebugLoc != nil {
+		pb.Args.DebugLoc = locationToProto(*remark.Args.DebugLoc)
+	}
+	if remark.Args.OtherAccess != nil {
+		pb.Args.OtherAccess = remarkAccessToProto(remark.Args.OtherAccess)
+	}
+	if remark.Args.ClobberedBy != nil {
+		pb.Args.ClobberedBy = remarkAccessToProto(remark.Args.ClobberedBy)
+	}
+
+	if len(remark.Metadata) > 0 {
+		if metadata, err := structpb.NewStruct(remark.Metadata); err == nil {
+			pb.Metadata = metadata
+		}
+	}
+
+	if ki := remark.KernelInfo; ki != nil {
+		pb.KernelInfo = &buildv1.KernelInfo{
+			ThreadLimit:              ki.ThreadLimit,
+			MaxThreadsX:              ki.MaxThreadsX,
+			MaxThreadsY:              ki.MaxThreadsY,
+			MaxThreadsZ:              ki.MaxThreadsZ,
+			SharedMemory:             ki.SharedMemory,
+			Target:                   ki.Target,
+			DirectCalls:              ki.DirectCalls,
+			IndirectCalls:            ki.IndirectCalls,
+			Callees:                  ki.Callees,
+			AllocasCount:             ki.AllocasCount,
+			AllocasStaticSize:        ki.AllocasStaticSize,
+			AllocasDynamicCount:      ki.AllocasDynamicCount,
+			FlatAddressSpaceAccesses: ki.FlatAddressSpaceAccesses,
+			InlineAssemblyCalls:      ki.InlineAssemblyCalls,
+			Metrics:                  make(map[string]int64, len(ki.Metrics)),
+			Attributes:               make(map[string]string, len(ki.Attributes)),
+		}
+
+		// JSON columns decode numbers as float64
+		for k, v := range ki.Metrics {
+			switch n := v.(type) {
+			case float64:
+				pb.KernelInfo.Metrics[k] = int64(n)
+			case int64:
+				pb.KernelInfo.Metrics[k] = n
+			}
+		}
+		for k, v := range ki.Attributes {
+			if s, ok := v.(string); ok {
+				pb.KernelInfo.Attributes[k] = s
+			}
+		}
+
+		pb.KernelInfo.MemoryAccesses = make([]*buildv1.MemoryAccess, 0, len(ki.MemoryAccesses))
+		for _, acc := range ki.MemoryAccesses {
+			pb.KernelInfo.MemoryAccesses = append(pb.KernelInfo.MemoryAccesses, &buildv1.MemoryAccess{
+				Type:          acc.Type,
+				AddressSpace:  acc.AddressSpace,
+				Instruction:   acc.Instruction,
+				Variable:      acc.Variable,
+				AccessPattern: acc.AccessPattern,
+				Location:      locationToProto(acc.Location),
+			})
+		}
+	}
+
+	return pb
+}
+
+func locationFromProto(loc *buildv1.Location) models.Location {
+	if loc == nil {
+		return models.Location{}
+	}
+	return models.Location{
+		File:     loc.File,
+		Line:     loc.Line,
+		Column:   loc.Column,
+		Function: loc.Function,
+		Region:   loc.Region,
+		Artifact: loc.Artifact,
+	}
+}
+
+func locationToProto(loc models.Location) *buildv1.Location {
+	return &buildv1.Location{
+		File:     loc.File,
+		Line:     loc.Line,
+		Column:   loc.Column,
+		Function: loc.Function,
+		Region:   loc.Region,
+		Artifact: loc.Artifact,
+	}
+}
+
+func remarkAccessFromProto(access *buildv1.RemarkAccess) *models.RemarkAccess {
+	dbAccess := &models.RemarkAccess{Type: access.Type}
+	if access.DebugLoc != nil {
+		loc := locationFromProto(access.DebugLoc)
+		dbAccess.DebugLoc = &loc
+	}
+	return dbAccess
+}
+
+func remarkAccessToProto(access *models.RemarkAccess) *buildv1.RemarkAccess {
+	pb := &buildv1.RemarkAccess{Type: access.Type}
+	if access.DebugLoc != nil {
+		pb.DebugLoc = locationToProto(*access.DebugLoc)
+	}
+	return pb
+}
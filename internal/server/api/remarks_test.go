@@ -0,0 +1,161 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	buildv1 "builds/api/build"
+	"builds/internal/server/db/dbtest"
+	models "builds/internal/server/db/models"
+)
+
+func TestCompilerRemarkKeepsNames(t *testing.T) {
+	pb := &buildv1.CompilerRemark{
+		Id:       "r1",
+		Name:     "NotVectorized",
+		Pass:     buildv1.CompilerRemark_PASS_ANALYSIS,
+		PassName: "loop-vectorize",
+		Status:   buildv1.CompilerRemark_MISSED,
+	}
+
+	stored := createCompilerRemark(models.Build{ID: "b1"}, pb)
+	if stored.Name != "NotVectorized" || stored.PassName != "loop-vectorize" {
+		t.Errorf("stored name %q and pass name %q", stored.Name, stored.PassName)
+	}
+	if stored.Pass != "pass_analysis" {
+		t.Errorf("stored pass category %q, want pass_analysis", stored.Pass)
+	}
+
+	got := remarkToProto(stored)
+	if got.Name != pb.Name || got.PassName != pb.PassName || got.Pass != pb.Pass {
+		t.Errorf("round trip gave name %q, pass name %q, pass %v", got.Name, got.PassName, got.Pass)
+	}
+}
+
+func TestCompilerRemarkKeepsArgs(t *testing.T) {
+	pb := &buildv1.CompilerRemark{
+		Location: &buildv1.Location{File: "foo.c", Line: 3, Artifact: "foo.opt.yaml"},
+		Args: &buildv1.RemarkArgs{
+			Reason:      "unsafe dependent memory operations",
+			DebugLoc:    &buildv1.Location{File: "foo.c", Line: 4},
+			OtherAccess: &buildv1.RemarkAccess{Type: "load", DebugLoc: &buildv1.Location{File: "foo.c", Line: 5}},
+			ClobberedBy: &buildv1.RemarkAccess{Type: "store"},
+			Values:      map[string]string{"Cost": "12"},
+		},
+	}
+
+	got := remarkToProto(createCompilerRemark(models.Build{ID: "b1"}, pb))
+	if got.Location.Artifact != "foo.opt.yaml" {
+		t.Errorf("artifact %q, want foo.opt.yaml", got.Location.Artifact)
+	}
+	args := got.Args
+	if args.Reason != pb.Args.Reason || args.Values["Cost"] != "12" || args.DebugLoc.GetLine() != 4 {
+		t.Errorf("args changed: %v", args)
+	}
+	if args.OtherAccess.GetType() != "load" || args.OtherAccess.GetDebugLoc().GetLine() != 5 {
+		t.Errorf("other access %v", args.OtherAccess)
+	}
+	if args.ClobberedBy.GetType() != "store" {
+		t.Errorf("clobbered by %v", args.ClobberedBy)
+	}
+}
+
+// kernelRemark returns a remark with every nested field set
+func kernelRemark() *buildv1.CompilerRemark {
+	return &buildv1.CompilerRemark{
+		Id:       "r1",
+		Name:     "KernelInfo",
+		PassName: "kernel-resource-usage",
+		Type:     buildv1.CompilerRemark_ANALYSIS,
+		Function: "kernel",
+		Message:  "kernel uses 3 spill stores",
+		Hotness:  7,
+		Location: &buildv1.Location{File: "kernel.cu", Line: 12, Column: 3},
+		Args: &buildv1.RemarkArgs{
+			Reason:      "register pressure",
+			DebugLoc:    &buildv1.Location{File: "kernel.cu", Line: 14},
+			OtherAccess: &buildv1.RemarkAccess{Type: "load", DebugLoc: &buildv1.Location{File: "kernel.cu", Line: 15}},
+			ClobberedBy: &buildv1.RemarkAccess{Type: "store"},
+			Values:      map[string]string{"NumSpills": "3"},
+		},
+		KernelInfo: &buildv1.KernelInfo{
+			ThreadLimit:  256,
+			SharedMemory: 4096,
+			Target:       "nvptx64-nvidia-cuda",
+			DirectCalls:  2,
+			Callees:      []string{"helper"},
+			AllocasCount: 1,
+			MemoryAccesses: []*buildv1.MemoryAccess{{
+				Type:         "load",
+				AddressSpace: "global",
+				Variable:     "in",
+				Location:     &buildv1.Location{File: "kernel.cu", Line: 13},
+			}},
+			Metrics:    map[string]int64{"spill_stores": 3},
+			Attributes: map[string]string{"launch_bounds": "256"},
+		},
+	}
+}
+
+func TestGetRemark(t *testing.T) {
+	s := NewServer(dbtest.Open(t), Options{})
+	ctx := context.Background()
+	_, err := s.CreateBuild(ctx, &buildv1.CreateBuildRequest{Build: &buildv1.Build{
+		Id:      "b1",
+		Remarks: []*buildv1.CompilerRemark{{Id: "r0", Name: "Other"}, kernelRemark()},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := s.GetRemark(ctx, &buildv1.GetRemarkRequest{BuildId: "b1", RemarkId: "r1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkKernelRemark(t, got)
+
+	for _, req := range []*buildv1.GetRemarkRequest{
+		{BuildId: "b1", RemarkId: "missing"},
+		{BuildId: "missing", RemarkId: "r1"},
+	} {
+		if _, err := s.GetRemark(ctx, req); status.Code(err) != codes.NotFound {
+			t.Errorf("GetRemark(%s, %s): %v, want NotFound", req.BuildId, req.RemarkId, err)
+		}
+	}
+	if _, err := s.GetRemark(ctx, &buildv1.GetRemarkRequest{BuildId: "b1"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("GetRemark without a remark ID: %v, want InvalidArgument", err)
+	}
+}
+
+// checkKernelRemark asserts got carries every nested field of kernelRemark
+func checkKernelRemark(t *testing.T, got *buildv1.CompilerRemark) {
+	t.Helper()
+	if got.Id != "r1" || got.Name != "KernelInfo" || got.Function != "kernel" || got.Hotness != 7 {
+		t.Errorf("remark %v", got)
+	}
+	if loc := got.Location; loc.GetFile() != "kernel.cu" || loc.GetLine() != 12 || loc.GetColumn() != 3 {
+		t.Errorf("location %v", loc)
+	}
+
+	args := got.Args
+	if args.GetReason() != "register pressure" || args.GetDebugLoc().GetLine() != 14 || args.GetValues()["NumSpills"] != "3" {
+		t.Errorf("args %v", args)
+	}
+	if args.GetOtherAccess().GetDebugLoc().GetLine() != 15 || args.GetClobberedBy().GetType() != "store" {
+		t.Errorf("accesses %v and %v", args.GetOtherAccess(), args.GetClobberedBy())
+	}
+
+	ki := got.KernelInfo
+	if ki.GetThreadLimit() != 256 || ki.GetSharedMemory() != 4096 || ki.GetTarget() != "nvptx64-nvidia-cuda" {
+		t.Errorf("kernel info %v", ki)
+	}
+	if len(ki.GetCallees()) != 1 || ki.GetMetrics()["spill_stores"] != 3 || ki.GetAttributes()["launch_bounds"] != "256" {
+		t.Errorf("kernel callees %v, metrics %v, attributes %v", ki.GetCallees(), ki.GetMetrics(), ki.GetAttributes())
+	}
+	if accesses := ki.GetMemoryAccesses(); len(accesses) != 1 || accesses[0].AddressSpace != "global" || accesses[0].Location.GetLine() != 13 {
+		t.Errorf("memory accesses %v", accesses)
+	}
+}
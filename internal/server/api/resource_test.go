@@ -0,0 +1,67 @@
+package api
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	buildv1 "builds/api/build"
+	"builds/internal/client"
+	"builds/internal/collectors/resource"
+	"builds/internal/models"
+	"builds/internal/server/db/dbtest"
+)
+
+func TestResourceUsageSurvivesStorage(t *testing.T) {
+	s := NewServer(dbtest.Open(t), Options{})
+	ctx := context.Background()
+
+	// Read a file so the IO counters move
+	collector := resource.NewCollector(&models.BuildContext{})
+	if err := collector.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.ReadFile(os.Args[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := collector.Collect(ctx); err != nil {
+		t.Fatal(err)
+	}
+	collected := collector.GetData().(models.ResourceUsage)
+	if collected.Threads == 0 {
+		t.Fatal("collector reported no threads")
+	}
+
+	usage := client.ResourceUsageToProto(collected)
+	builds := []*buildv1.Build{
+		{Id: "collected", ResourceUsage: usage},
+		// Platforms without IO counters leave them out
+		{Id: "no-io", ResourceUsage: &buildv1.ResourceUsage{MaxMemory: 1 << 20, Threads: 4}},
+	}
+	for _, build := range builds {
+		t.Run(build.Id, func(t *testing.T) {
+			if _, err := s.CreateBuild(ctx, &buildv1.CreateBuildRequest{Build: build}); err != nil {
+				t.Fatal(err)
+			}
+			got, err := s.GetBuild(ctx, &buildv1.GetBuildRequest{Id: build.Id})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			stored := got.ResourceUsage
+			if stored.GetThreads() != build.ResourceUsage.Threads || stored.GetMaxMemory() != build.ResourceUsage.MaxMemory {
+				t.Errorf("stored %d threads and %d bytes, want %d and %d", stored.GetThreads(), stored.GetMaxMemory(),
+					build.ResourceUsage.Threads, build.ResourceUsage.MaxMemory)
+			}
+			want := build.ResourceUsage.Io
+			if want == nil {
+				want = &buildv1.IOStats{}
+			}
+			if !proto.Equal(stored.GetIo(), want) {
+				t.Errorf("stored IO %v, want %v", stored.GetIo(), want)
+			}
+		})
+	}
+}
@@ -4,9 +4,11 @@ package api
 
 import (
 	"context"
-	"errors"
 	"fmt"
-	"strconv"
+	"log"
+	"math"
+	"sort"
+	"strings"
 	"time"
 
 	"google.golang.org/grpc/codes"
@@ -14,169 +16,397 @@ import (
 	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	buildv1 "builds/api/build"
+	coremodels "builds/internal/models"
+	"builds/internal/parsers/diagnostics"
+	"builds/internal/parsers/invocations"
+	"builds/internal/parsers/triple"
+	"builds/internal/server/auth"
 	"builds/internal/server/db"
 	models "builds/internal/server/db/models"
 )
 
+// Options configures optional server behaviour
+type Options struct {
+	// StoreRawRemarks keeps uploaded optimization records. Records can be
+	// large, so they are dropped unless enabled.
+	StoreRawRemarks bool
+
+	// MaxConcurrentWrites bounds the write transactions running at once;
+	// zero disables the limit. Up to WriteQueueSize further writes wait for
+	// at most WriteQueueTimeout before being rejected.
+	MaxConcurrentWrites int
+	WriteQueueSize      int
+	WriteQueueTimeout   time.Duration
+
+	// ServerTimestamps replaces the start and end times sent by clients
+	// with the server clock, for clients whose clocks cannot be trusted.
+	// The client's timestamps are kept alongside.
+	ServerTimestamps bool
+}
+
 type Server struct {
 	buildv1.UnimplementedBuildServiceServer
-	db *db.Database
+	db     *db.Database
+	opts   Options
+	writes *writeLimiter
+}
+
+func NewServer(db *db.Database, opts Options) *Server {
+	return &Server{
+		db:     db,
+		opts:   opts,
+		writes: newWriteLimiter(opts.MaxConcurrentWrites, opts.WriteQueueSize, opts.WriteQueueTimeout),
+	}
 }
 
-func NewServer(db *db.Database) *Server {
-	return &Server{db: db}
+// store returns the database scoped to the caller's tenant
+func (s *Server) store(ctx context.Context) *db.Database {
+	return s.db.ForTenant(auth.TenantFromContext(ctx))
 }
 
 func (s *Server) CreateBuild(ctx context.Context, req *buildv1.CreateBuildRequest) (*buildv1.Build, error) {
-	if req.Build == nil {
-		return nil, status.Error(codes.InvalidArgument, "build is required")
+	id, err := s.storeBuild(ctx, req.Build)
+	if err != nil {
+		return nil, err
+	}
+	return s.fetchBuild(id)
+}
+
+// storeBuild stores a complete build in one transaction and returns its ID.
+// Errors are gRPC status errors.
+func (s *Server) storeBuild(ctx context.Context, pb *buildv1.Build) (string, error) {
+	if pb == nil {
+		return "", status.Error(codes.InvalidArgument, "build is required")
 	}
+	received := time.Now()
 
+	duration, reported := checkDuration(pb)
 	build := &models.Build{
-		ID:        req.Build.Id,
-		StartTime: req.Build.StartTime.AsTime(),
-		EndTime:   req.Build.EndTime.AsTime(),
-		Duration:  req.Build.Duration,
-		Success:   req.Build.Success,
-		Error:     req.Build.Error,
+		ID:        pb.Id,
+		StartTime: pb.StartTime.AsTime(),
+		EndTime:   pb.EndTime.AsTime(),
+		Duration:  duration,
+		Success:   pb.Success,
+		Error:     pb.Error,
+		Tenant:    auth.TenantFromContext(ctx),
+
+		RemarksTruncated: pb.RemarksTruncated,
+		RemarksSeen:      pb.RemarksSeen,
+		ReportedDuration: reported,
+		CacheStatus:      pb.CacheStatus,
+		RawTruncated:     pb.RawRemarksTruncated,
+	}
+	if s.opts.ServerTimestamps {
+		build.ClientStartTime = clientTime(pb.StartTime)
+		build.ClientEndTime = clientTime(pb.EndTime)
+		build.StartTime, build.EndTime = serverTimes(received, duration)
 	}
 
 	// Create remarks first to have their IDs available
-	var remarks []*models.CompilerRemark
-	for _, remark := range req.Build.Remarks {
+	remarks := make([]*models.CompilerRemark, 0, len(pb.Remarks))
+	for _, remark := range pb.Remarks {
 		dbRemark := createCompilerRemark(*build, remark)
 		remarks = append(remarks, dbRemark)
 	}
+	assignStableRemarkIDs(build.ID, remarks)
+
+	release, err := s.writes.acquire(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer release()
 
-	err := s.db.DB.Transaction(func(tx *gorm.DB) error {
+	err = s.db.DB.Transaction(func(tx *gorm.DB) error {
 		// Create the build first
+		finished := time.Now()
+		build.FinishedAt = &finished
 		if err := tx.Create(build).Error; err != nil {
 			return fmt.Errorf("failed to create build: %w", err)
 		}
 
-		// Create environment
-		if req.Build.Environment != nil {
-			if err := s.createEnvironment(tx, build.ID, req.Build.Environment); err != nil {
-				return err
-			}
+		if err := s.createDetails(tx, build.ID, pb); err != nil {
+			return err
 		}
 
-		// Create hardware
-		if req.Build.Hardware != nil {
-			if err := s.createHardware(tx, build.ID, req.Build.Hardware); err != nil {
-				return err
-			}
+		return s.createRemarks(tx, remarks)
+	})
+
+	if err != nil {
+		return "", statusError(err, "build")
+	}
+
+	return build.ID, nil
+}
+
+// durationTolerance is how far, in seconds, a client's duration may drift
+// from its timestamps before the server replaces it
+const durationTolerance = 1.0
+
+// checkDuration returns the duration to store for a build. When both
+// timestamps are set it is computed from them, and the client's value is
+// returned as reported if it disagreed by more than durationTolerance.
+func checkDuration(pb *buildv1.Build) (duration, reported float64) {
+	if pb.StartTime == nil || pb.EndTime == nil {
+		return pb.Duration, 0
+	}
+	start, end := pb.StartTime.AsTime(), pb.EndTime.AsTime()
+	if start.Unix() <= 0 || end.Before(start) {
+		return pb.Duration, 0
+	}
+
+	computed := end.Sub(start).Seconds()
+	if math.Abs(computed-pb.Duration) > durationTolerance {
+		log.Printf("Build %s reported a duration of %.3fs but its timestamps span %.3fs; storing the latter",
+			pb.Id, pb.Duration, computed)
+		return computed, pb.Duration
+	}
+	return computed, 0
+}
+
+// serverTimes returns the timestamps of a build that took duration seconds
+// and was received at received, taking it to have ended on arrival
+func serverTimes(received time.Time, duration float64) (start, end time.Time) {
+	return received.Add(-time.Duration(duration * float64(time.Second))), received
+}
+
+// clientTime returns the time ts holds, or nil when the client sent none
+func clientTime(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
+
+// optionalTimestamp converts t, leaving the field unset when t is nil
+func optionalTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+// createDetails stores everything recorded about a build besides its
+// remarks
+func (s *Server) createDetails(tx *gorm.DB, buildID string, pb *buildv1.Build) error {
+	// Create labels
+	if len(pb.Labels) > 0 {
+		if err := s.createLabels(tx, buildID, pb.Labels); err != nil {
+			return err
 		}
+	}
 
-		// Create compiler
-		if req.Build.Compiler != nil {
-			if err := s.createCompiler(tx, build.ID, req.Build.Compiler); err != nil {
-				return err
-			}
+	// Create environment
+	if pb.Environment != nil {
+		if err := s.createEnvironment(tx, buildID, pb.Environment); err != nil {
+			return err
 		}
+	}
 
-		// Create command
-		if req.Build.Command != nil {
-			if err := s.createCommand(tx, build.ID, req.Build.Command); err != nil {
-				return err
-			}
+	// Create version control
+	if vc := pb.VersionControl; vc != nil && vc.Commit != "" {
+		dbVC := &models.VersionControl{
+			BuildID:   buildID,
+			Commit:    vc.Commit,
+			Branch:    vc.Branch,
+			Dirty:     vc.Dirty,
+			RemoteURL: vc.RemoteUrl,
+		}
+		if err := tx.Create(dbVC).Error; err != nil {
+			return fmt.Errorf("failed to create version control: %w", err)
 		}
+	}
 
-		// Create output
-		if req.Build.Output != nil {
-			if err := s.createOutput(tx, build.ID, req.Build.Output); err != nil {
-				return err
-			}
+	// Create hardware
+	if pb.Hardware != nil {
+		if err := s.createHardware(tx, buildID, pb.Hardware); err != nil {
+			return err
 		}
+	}
+
+	// Create compiler
+	if pb.Compiler != nil {
+		if err := s.createCompiler(tx, buildID, pb.Compiler); err != nil {
+			return err
+		}
+	}
 
-		// Create resource usage
-		if req.Build.ResourceUsage != nil {
-			if err := s.createResourceUsage(tx, build.ID, req.Build.ResourceUsage); err != nil {
-				return err
+	// Create command
+	if pb.Command != nil {
+		if err := s.createCommand(tx, buildID, pb.Command); err != nil {
+			return err
+		}
+	}
+
+	// Create output
+	if pb.Output != nil {
+		if err := s.createOutput(tx, buildID, pb.Output); err != nil {
+			return err
+		}
+	}
+
+	// Create resource usage
+	if pb.ResourceUsage != nil {
+		if err := s.createResourceUsage(tx, buildID, pb.ResourceUsage); err != nil {
+			return err
+		}
+	}
+
+	// Create performance
+	if pb.Performance != nil {
+		if err := s.createPerformance(tx, buildID, pb.Performance); err != nil {
+			return err
+		}
+	}
+
+	// Create output sizes
+	if pb.Metrics != nil {
+		if err := s.createMetrics(tx, buildID, pb.Metrics); err != nil {
+			return err
+		}
+	}
+
+	// Create linker map
+	if pb.LinkMap != nil {
+		if err := s.createLinkMap(tx, buildID, pb.LinkMap); err != nil {
+			return err
+		}
+	}
+
+	// Store the raw optimization record
+	if len(pb.RawRemarks) > 0 {
+		if s.opts.StoreRawRemarks {
+			raw := &models.RawRemarks{BuildID: buildID, Data: pb.RawRemarks}
+			if err := tx.Create(raw).Error; err != nil {
+				return fmt.Errorf("failed to store raw remarks: %w", err)
 			}
+		} else {
+			log.Printf("Discarding raw remarks for build %s: storage disabled", buildID)
+		}
+	}
+
+	return nil
+}
+
+// createRemarks stores remarks along with their kernel details. Each row is
+// inserted without its associations, which gorm would otherwise save on its
+// own and then collide with the explicit inserts below.
+func (s *Server) createRemarks(tx *gorm.DB, remarks []*models.CompilerRemark) error {
+	for _, remark := range remarks {
+		if err := tx.Omit(clause.Associations).Create(remark).Error; err != nil {
+			return fmt.Errorf("failed to create remark: %w", err)
 		}
 
-		// Store remarks
-		for _, remark := range remarks {
-			if err := tx.Create(remark).Error; err != nil {
-				return fmt.Errorf("failed to create remark: %w", err)
+		// Create kernel info if present
+		if remark.KernelInfo != nil {
+			remark.KernelInfo.RemarkID = remark.ID
+			if err := tx.Omit(clause.Associations).Create(remark.KernelInfo).Error; err != nil {
+				return fmt.Errorf("failed to create kernel info: %w", err)
 			}
 
-			// Create kernel info if present
-			if remark.KernelInfo != nil {
-				remark.KernelInfo.RemarkID = remark.ID
-				if err := tx.Create(remark.KernelInfo).Error; err != nil {
-					return fmt.Errorf("failed to create kernel info: %w", err)
+			// Create memory accesses
+			if len(remark.KernelInfo.MemoryAccesses) > 0 {
+				for i := range remark.KernelInfo.MemoryAccesses {
+					remark.KernelInfo.MemoryAccesses[i].KernelInfoID = remark.KernelInfo.ID
 				}
-
-				// Create memory accesses
-				if len(remark.KernelInfo.MemoryAccesses) > 0 {
-					for i := range remark.KernelInfo.MemoryAccesses {
-						remark.KernelInfo.MemoryAccesses[i].KernelInfoID = remark.KernelInfo.ID
-					}
-					if err := tx.Create(&remark.KernelInfo.MemoryAccesses).Error; err != nil {
-						return fmt.Errorf("failed to create memory accesses: %w", err)
-					}
+				if err := tx.Create(&remark.KernelInfo.MemoryAccesses).Error; err != nil {
+					return fmt.Errorf("failed to create memory accesses: %w", err)
 				}
 			}
 		}
-
-		return nil
-	})
-
-	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
 	}
 
-	// Fetch the complete build with all relationships
+	return nil
+}
+
+// fetchBuild loads a build with all relationships for returning to clients
+func (s *Server) fetchBuild(id string) (*buildv1.Build, error) {
 	var completeBuild models.Build
-	err = s.db.DB.
+	err := s.db.DB.
+		Preload("Labels").
 		Preload("Environment.Variables").
+		Preload("VersionControl").
 		Preload("Hardware.GPUs").
-		Preload("Compiler.Options").
+		Preload("Compiler.Options", func(db *gorm.DB) *gorm.DB {
+			return db.Order("compiler_options.position ASC")
+		}).
 		Preload("Compiler.Optimizations").
 		Preload("Compiler.Extensions").
-		Preload("Command.Arguments").
+		Preload("Command.Arguments", func(db *gorm.DB) *gorm.DB {
+			return db.Order("command_arguments.position ASC")
+		}).
+		Preload("Command.Invocations", func(db *gorm.DB) *gorm.DB {
+			return db.Order("command_invocations.position ASC")
+		}).
 		Preload("Output.Artifacts").
+		Preload("Output.Diagnostics", func(db *gorm.DB) *gorm.DB {
+			return db.Order("diagnostics.id ASC")
+		}).
 		Preload("Remarks", func(db *gorm.DB) *gorm.DB {
 			return db.Order("compiler_remarks.id ASC")
 		}).
 		Preload("Remarks.KernelInfo").
-		Preload("Remarks.KernelInfo.MemoryAccesses").
+		Preload("Remarks.KernelInfo.MemoryAccesses", func(db *gorm.DB) *gorm.DB {
+			return db.Order("memory_accesses.id ASC")
+		}).
 		Preload("ResourceUsage").
 		Preload("Performance.Phases").
-		First(&completeBuild, "id = ?", build.ID).Error
+		Preload("Metrics").
+		Preload("LinkMap.Sections", func(db *gorm.DB) *gorm.DB {
+			return db.Order("link_sections.id ASC")
+		}).
+		Preload("LinkMap.Symbols", func(db *gorm.DB) *gorm.DB {
+			return db.Order("link_symbols.id ASC")
+		}).
+		First(&completeBuild, "id = ?", id).Error
 
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, statusError(err, "build")
 	}
 
 	return s.convertBuildToProto(&completeBuild), nil
 }
 
 func (s *Server) GetBuild(ctx context.Context, req *buildv1.GetBuildRequest) (*buildv1.Build, error) {
-	build, err := s.db.GetBuildByID(req.Id)
+	build, err := s.store(ctx).GetBuildByID(req.Id)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, status.Error(codes.NotFound, "build not found")
-		}
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, statusError(err, "build")
 	}
 
 	return s.convertBuildToProto(build), nil
 }
 
 func (s *Server) ListBuilds(ctx context.Context, req *buildv1.ListBuildsRequest) (*buildv1.ListBuildsResponse, error) {
-	builds, err := s.db.ListBuilds(int(req.PageSize), req.PageToken)
+	filter, err := parseListFilter(req.Filter)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	success := outcomeSuccess(req.Outcome == buildv1.ListBuildsRequest_OUTCOME_SUCCEEDED,
+		req.Outcome == buildv1.ListBuildsRequest_OUTCOME_FAILED)
+	if filter.BuildFilter, err = buildFilter(req.Compiler, success, req.Since, req.Until); err != nil {
+		return nil, err
+	}
+
+	after, err := decodePageToken(req.PageToken)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	builds, more, err := s.store(ctx).ListBuilds(pageSize(req.PageSize), after, filter)
+	if err != nil {
+		return nil, statusError(err, "builds")
 	}
 
 	response := &buildv1.ListBuildsResponse{
 		Builds: make([]*buildv1.Build, len(builds)),
 	}
+	if more {
+		last := builds[len(builds)-1]
+		response.NextPageToken = encodePageToken(db.Cursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
 
 	for i, build := range builds {
 		response.Builds[i] = s.convertBuildToProto(&build)
@@ -186,22 +416,48 @@ func (s *Server) ListBuilds(ctx context.Context, req *buildv1.ListBuildsRequest)
 }
 
 func (s *Server) DeleteBuild(ctx context.Context, req *buildv1.DeleteBuildRequest) (*emptypb.Empty, error) {
-	if err := s.db.DeleteBuild(req.Id); err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, status.Error(codes.NotFound, "build not found")
-		}
-		return nil, status.Error(codes.Internal, err.Error())
+	if err := s.store(ctx).DeleteBuild(req.Id); err != nil {
+		return nil, statusError(err, "build")
 	}
 
 	return &emptypb.Empty{}, nil
 }
 
+func (s *Server) UndeleteBuild(ctx context.Context, req *buildv1.UndeleteBuildRequest) (*buildv1.Build, error) {
+	store := s.store(ctx)
+	if err := store.UndeleteBuild(req.Id); err != nil {
+		return nil, statusError(err, "deleted build")
+	}
+
+	build, err := store.GetBuildByID(req.Id)
+	if err != nil {
+		return nil, statusError(err, "build")
+	}
+
+	return s.convertBuildToProto(build), nil
+}
+
+// buildPollInterval is how often StreamBuilds checks for new builds
+var buildPollInterval = 5 * time.Second
+
 func (s *Server) StreamBuilds(req *buildv1.StreamBuildsRequest, stream buildv1.BuildService_StreamBuildsServer) error {
 	ctx := stream.Context()
-	ticker := time.NewTicker(5 * time.Second)
+	tenant := auth.TenantFromContext(ctx)
+	ticker := time.NewTicker(buildPollInterval)
 	defer ticker.Stop()
 
+	// Builds are tracked by the server-assigned finished_at, as client start
+	// times may be skewed and arrive out of order, and builds begun for
+	// streaming remarks are only complete once finalized
 	lastTime := time.Now()
+	comparison := "finished_at > ?"
+
+	// Resuming clients ask for everything from their last seen build onwards
+	// and discard the duplicates themselves
+	if req.Since != nil {
+		lastTime = req.Since.AsTime()
+		comparison = "finished_at >= ?"
+	}
 
 	for {
 		select {
@@ -209,18 +465,22 @@ func (s *Server) StreamBuilds(req *buildv1.StreamBuildsRequest, stream buildv1.B
 			return ctx.Err()
 		case <-ticker.C:
 			var builds []models.Build
-			err := s.db.DB.
-				Where("start_time > ?", lastTime).
-				Order("start_time ASC").
+			query := s.db.DB.Where(comparison, lastTime).Where("NOT in_progress")
+			if tenant != "" {
+				query = query.Where("tenant = ?", tenant)
+			}
+			err := query.
+				Order("finished_at ASC").
 				Find(&builds).Error
 
 			if err != nil {
-				return status.Error(codes.Internal, err.Error())
+				return statusError(err, "builds")
 			}
+			comparison = "finished_at > ?"
 
 			for _, build := range builds {
-				if build.StartTime.After(lastTime) {
-					lastTime = build.StartTime
+				if build.FinishedAt.After(lastTime) {
+					lastTime = *build.FinishedAt
 				}
 				if err := stream.Send(s.convertBuildToProto(&build)); err != nil {
 					return err
@@ -230,16 +490,110 @@ func (s *Server) StreamBuilds(req *buildv1.StreamBuildsRequest, stream buildv1.B
 	}
 }
 
+func (s *Server) GetSummary(ctx context.Context, req *buildv1.GetSummaryRequest) (*buildv1.BuildSummary, error) {
+	topCompilers := int(req.TopCompilers)
+	if topCompilers <= 0 {
+		topCompilers = 5
+	}
+
+	summary, err := s.store(ctx).Summary(time.Now(), topCompilers)
+	if err != nil {
+		return nil, statusError(err, "summary")
+	}
+
+	response := &buildv1.BuildSummary{
+		TotalBuilds:       summary.TotalBuilds,
+		BuildsLastDay:     summary.BuildsLastDay,
+		BuildsLastWeek:    summary.BuildsLastWeek,
+		RecentSuccessRate: summary.RecentSuccessRate,
+	}
+
+	for _, c := range summary.TopCompilers {
+		response.TopCompilers = append(response.TopCompilers, &buildv1.CompilerCount{
+			Name:   c.Name,
+			Builds: c.Builds,
+		})
+	}
+
+	if summary.SlowestRecent != nil {
+		response.SlowestRecentBuild = s.convertBuildToProto(summary.SlowestRecent)
+	}
+
+	return response, nil
+}
+
+// outcomeSuccess returns the success value builds with the requested outcome
+// have; nil matches every build
+func outcomeSuccess(succeeded, failed bool) *bool {
+	if !succeeded && !failed {
+		return nil
+	}
+	return &succeeded
+}
+
+// buildFilter reads the filter fields ListBuilds and CountBuilds share
+func buildFilter(compiler string, success *bool, since, until *timestamppb.Timestamp) (db.BuildFilter, error) {
+	filter := db.BuildFilter{Compiler: compiler, Success: success}
+	if since != nil {
+		filter.Since = since.AsTime()
+	}
+	if until != nil {
+		filter.Until = until.AsTime()
+	}
+	if !filter.Since.IsZero() && !filter.Until.IsZero() && !filter.Since.Before(filter.Until) {
+		return filter, status.Error(codes.InvalidArgument, "since must be before until")
+	}
+	return filter, nil
+}
+
+// CountBuilds returns how many builds match the request's filter
+func (s *Server) CountBuilds(ctx context.Context, req *buildv1.CountBuildsRequest) (*buildv1.CountBuildsResponse, error) {
+	success := outcomeSuccess(req.Outcome == buildv1.CountBuildsRequest_OUTCOME_SUCCEEDED,
+		req.Outcome == buildv1.CountBuildsRequest_OUTCOME_FAILED)
+	filter, err := buildFilter(req.Compiler, success, req.Since, req.Until)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := s.store(ctx).CountBuilds(filter)
+	if err != nil {
+		return nil, statusError(err, "builds")
+	}
+	return &buildv1.CountBuildsResponse{Count: count}, nil
+}
+
 // Helper functions for creating related entities
+func (s *Server) createLabels(tx *gorm.DB, buildID string, labels map[string]string) error {
+	dbLabels := make([]models.BuildLabel, 0, len(labels))
+	for k, v := range labels {
+		dbLabels = append(dbLabels, models.BuildLabel{
+			BuildID: buildID,
+			Key:     k,
+			Value:   v,
+		})
+	}
+
+	return tx.Create(&dbLabels).Error
+}
+
 func (s *Server) createEnvironment(tx *gorm.DB, buildID string, env *buildv1.Environment) error {
 	dbEnv := &models.Environment{
 		BuildID:    buildID,
 		OS:         env.Os,
 		Arch:       env.Arch,
 		WorkingDir: env.WorkingDir,
+		Timezone:   env.Timezone,
+		Umask:      env.Umask,
 		Variables:  make([]models.EnvironmentVariable, 0, len(env.Variables)),
 	}
 
+	if len(env.Locale) > 0 {
+		dbEnv.Locale = make(models.JSON, len(env.Locale))
+		for k, v := range env.Locale {
+			dbEnv.Locale[k] = v
+		}
+	}
+
 	for k, v := range env.Variables {
 		dbEnv.Variables = append(dbEnv.Variables, models.EnvironmentVariable{
 			BuildID: buildID,
@@ -251,6 +605,36 @@ func (s *Server) createEnvironment(tx *gorm.DB, buildID string, env *buildv1.Env
 	return tx.Create(dbEnv).Error
 }
 
+// createLinkMap stores a linker map, keeping the order of its sections and
+// symbols
+func (s *Server) createLinkMap(tx *gorm.DB, buildID string, m *buildv1.LinkMap) error {
+	dbMap := &models.LinkMap{
+		BuildID:           buildID,
+		SymbolsSeen:       m.SymbolsSeen,
+		DiscardedSections: m.DiscardedSections,
+		DiscardedSize:     m.DiscardedSize,
+		Sections:          make([]models.LinkSection, len(m.Sections)),
+		Symbols:           make([]models.LinkSymbol, len(m.Symbols)),
+	}
+	for i, section := range m.Sections {
+		dbMap.Sections[i] = models.LinkSection{BuildID: buildID, Name: section.Name, Size: section.Size}
+	}
+	for i, sym := range m.Symbols {
+		dbMap.Symbols[i] = models.LinkSymbol{
+			BuildID: buildID,
+			Name:    sym.Name,
+			Section: sym.Section,
+			Object:  sym.Object,
+			Size:    sym.Size,
+		}
+	}
+
+	if err := tx.Create(dbMap).Error; err != nil {
+		return fmt.Errorf("failed to create linker map: %w", err)
+	}
+	return nil
+}
+
 func (s *Server) createHardware(tx *gorm.DB, buildID string, hw *buildv1.Hardware) error {
 	dbHw := &models.Hardware{
 		BuildID:    buildID,
@@ -260,6 +644,7 @@ func (s *Server) createHardware(tx *gorm.DB, buildID string, hw *buildv1.Hardwar
 		CPUThreads: hw.Cpu.Threads,
 		CPUVendor:  hw.Cpu.Vendor,
 		CacheSize:  hw.Cpu.CacheSize,
+		NUMANodes:  hw.Cpu.NumaNodes,
 		MemTotal:   hw.Memory.Total,
 		MemAvail:   hw.Memory.Available,
 		MemUsed:    hw.Memory.Used,
@@ -282,11 +667,28 @@ func (s *Server) createHardware(tx *gorm.DB, buildID string, hw *buildv1.Hardwar
 }
 
 func (s *Server) createCompiler(tx *gorm.DB, buildID string, comp *buildv1.Compiler) error {
+	// Older clients send only the raw target
+	target := coremodels.TargetTriple{
+		Arch:   comp.GetTriple().GetArch(),
+		Vendor: comp.GetTriple().GetVendor(),
+		OS:     comp.GetTriple().GetOs(),
+		ABI:    comp.GetTriple().GetAbi(),
+	}
+	if target.Arch == "" {
+		target = triple.Parse(comp.Target)
+	}
+
 	dbComp := &models.Compiler{
 		BuildID:         buildID,
 		Name:            comp.Name,
 		Version:         comp.Version,
+		VersionString:   comp.VersionString,
+		Wrapper:         comp.Wrapper,
 		Target:          comp.Target,
+		TargetArch:      target.Arch,
+		TargetVendor:    target.Vendor,
+		TargetOS:        target.OS,
+		TargetABI:       target.ABI,
 		LanguageName:    comp.Language.Name,
 		LanguageVersion: comp.Language.Version,
 		LanguageSpec:    comp.Language.Specification,
@@ -295,15 +697,16 @@ func (s *Server) createCompiler(tx *gorm.DB, buildID string, comp *buildv1.Compi
 		SupportsLTO:     comp.Features.SupportsLto,
 		SupportsPGO:     comp.Features.SupportsPgo,
 		Options:         make([]models.CompilerOption, len(comp.Options)),
-		Optimizations:   make([]models.CompilerOptimization, 0),
+		Optimizations:   make([]models.CompilerOptimization, 0, len(comp.Optimizations)),
 		Extensions:      make([]models.CompilerExtension, len(comp.Features.Extensions)),
 	}
 
 	// Store options
 	for i, opt := range comp.Options {
 		dbComp.Options[i] = models.CompilerOption{
-			BuildID: buildID,
-			Option:  opt,
+			BuildID:  buildID,
+			Option:   opt,
+			Position: i,
 		}
 	}
 
@@ -332,6 +735,7 @@ func (s *Server) createCommand(tx *gorm.DB, buildID string, cmd *buildv1.Command
 		BuildID:    buildID,
 		Executable: cmd.Executable,
 		WorkingDir: cmd.WorkingDir,
+		Hash:       invocations.Hash(cmd.Executable, cmd.Arguments, cmd.WorkingDir),
 		Arguments:  make([]models.CommandArgument, len(cmd.Arguments)),
 	}
 
@@ -343,18 +747,51 @@ func (s *Server) createCommand(tx *gorm.DB, buildID string, cmd *buildv1.Command
 		}
 	}
 
+	// Store driver sub-invocations
+	for i, inv := range cmd.Invocations {
+		dbCmd.Invocations = append(dbCmd.Invocations, models.CommandInvocation{
+			BuildID:   buildID,
+			Position:  i,
+			Tool:      inv.Tool,
+			Arguments: inv.Arguments,
+		})
+	}
+
 	return tx.Create(dbCmd).Error
 }
 
 func (s *Server) createOutput(tx *gorm.DB, buildID string, output *buildv1.Output) error {
 	dbOutput := &models.Output{
 		BuildID:   buildID,
-		Stdout:    output.Stdout,
-		Stderr:    output.Stderr,
+		Stdout:    models.CompressedText(output.Stdout),
+		Stderr:    models.CompressedText(output.Stderr),
 		ExitCode:  output.ExitCode,
+		Truncated: output.Truncated,
 		Artifacts: make([]models.Artifact, len(output.Artifacts)),
 	}
 
+	for _, d := range output.Diagnostics {
+		dbOutput.Diagnostics = append(dbOutput.Diagnostics, models.Diagnostic{
+			BuildID:  buildID,
+			Severity: d.Severity,
+			File:     d.File,
+			Line:     d.Line,
+			Col:      d.Col,
+			Message:  d.Message,
+		})
+	}
+
+	// Clients that only fill the flat lists would otherwise lose them, as
+	// they are rebuilt from the diagnostics on read
+	if len(output.Diagnostics) == 0 {
+		for _, text := range output.Warnings {
+			dbOutput.Diagnostics = append(dbOutput.Diagnostics, flatDiagnostic(buildID, coremodels.SeverityWarning, text))
+		}
+		for _, text := range output.Errors {
+			dbOutput.Diagnostics = append(dbOutput.Diagnostics, flatDiagnostic(buildID, coremodels.SeverityError, text))
+		}
+	}
+
 	for i, artifact := range output.Artifacts {
 		dbOutput.Artifacts[i] = models.Artifact{
 			BuildID: buildID,
@@ -370,32 +807,111 @@ func (s *Server) createOutput(tx *gorm.DB, buildID string, output *buildv1.Outpu
 
 func (s *Server) createResourceUsage(tx *gorm.DB, buildID string, usage *buildv1.ResourceUsage) error {
 	dbUsage := &models.ResourceUsage{
-		BuildID:    buildID,
-		MaxMemory:  usage.MaxMemory,
-		CPUTime:    usage.CpuTime,
-		Threads:    usage.Threads,
-		ReadBytes:  usage.Io.ReadBytes,
-		WriteBytes: usage.Io.WriteBytes,
-		ReadCount:  usage.Io.ReadCount,
-		WriteCount: usage.Io.WriteCount,
+		BuildID:   buildID,
+		MaxMemory: usage.MaxMemory,
+		CPUTime:   usage.CpuTime,
+		Threads:   usage.Threads,
+	}
+	// IO counters are unavailable on some platforms, so the collector may omit them
+	if io := usage.Io; io != nil {
+		dbUsage.ReadBytes = io.ReadBytes
+		dbUsage.WriteBytes = io.WriteBytes
+		dbUsage.ReadCount = io.ReadCount
+		dbUsage.WriteCount = io.WriteCount
+	}
+	if len(usage.PhaseMemory) > 0 {
+		dbUsage.PhaseMemory = make(models.JSON, len(usage.PhaseMemory))
+		for phase, bytes := range usage.PhaseMemory {
+			dbUsage.PhaseMemory[phase] = bytes
+		}
 	}
 
 	return tx.Create(dbUsage).Error
 }
 
+func (s *Server) createMetrics(tx *gorm.DB, buildID string, metrics *buildv1.BuildMetrics) error {
+	dbMetrics := &models.BuildMetrics{
+		BuildID:        buildID,
+		TotalFiles:     metrics.TotalFiles,
+		ProcessedFiles: metrics.ProcessedFiles,
+		Warnings:       metrics.Warnings,
+		Errors:         metrics.Errors,
+		InputSize:      metrics.InputSize,
+		OutputSize:     metrics.OutputSize,
+		TextSize:       metrics.TextSize,
+		DataSize:       metrics.DataSize,
+		BSSSize:        metrics.BssSize,
+	}
+	if len(metrics.SectionSizes) > 0 {
+		dbMetrics.SectionSizes = make(models.JSON, len(metrics.SectionSizes))
+		for name, size := range metrics.SectionSizes {
+			dbMetrics.SectionSizes[name] = size
+		}
+	}
+	if len(metrics.Metrics) > 0 {
+		dbMetrics.Metrics = make(models.JSON, len(metrics.Metrics))
+		for name, value := range metrics.Metrics {
+			dbMetrics.Metrics[name] = value
+		}
+	}
+
+	return tx.Create(dbMetrics).Error
+}
+
+func (s *Server) createPerformance(tx *gorm.DB, buildID string, perf *buildv1.Performance) error {
+	dbPerf := &models.Performance{
+		BuildID:      buildID,
+		CompileTime:  perf.CompileTime,
+		LinkTime:     perf.LinkTime,
+		OptimizeTime: perf.OptimizeTime,
+		Phases:       make([]models.PerformancePhase, 0, len(perf.Phases)),
+	}
+
+	// Store phase timings
+	for phase, duration := range perf.Phases {
+		dbPerf.Phases = append(dbPerf.Phases, models.PerformancePhase{
+			BuildID:  buildID,
+			Phase:    phase,
+			Duration: duration,
+		})
+	}
+
+	return tx.Create(dbPerf).Error
+}
+
 func (s *Server) convertBuildToProto(build *models.Build) *buildv1.Build {
+	sortDetails(build)
+
 	pb := &buildv1.Build{
-		Id:        build.ID,
-		StartTime: timestamppb.New(build.StartTime),
-		EndTime:   timestamppb.New(build.EndTime),
-		Duration:  build.Duration,
-		Success:   build.Success,
-		Error:     build.Error,
+		Id:         build.ID,
+		StartTime:  timestamppb.New(build.StartTime),
+		EndTime:    timestamppb.New(build.EndTime),
+		Duration:   build.Duration,
+		Success:    build.Success,
+		Error:      build.Error,
+		InProgress: build.InProgress,
+		CreatedAt:  timestamppb.New(build.CreatedAt),
+
+		RemarksTruncated: build.RemarksTruncated,
+		RemarksSeen:      build.RemarksSeen,
+		RemarksPurged:    build.RemarksPurged,
+		ReportedDuration: build.ReportedDuration,
+		CacheStatus:      build.CacheStatus,
+		ClientStartTime:  optionalTimestamp(build.ClientStartTime),
+		ClientEndTime:    optionalTimestamp(build.ClientEndTime),
+		FinishedAt:       optionalTimestamp(build.FinishedAt),
+
+		RawRemarksTruncated: build.RawTruncated,
+
+		Labels: make(map[string]string, len(build.Labels)),
 		Environment: &buildv1.Environment{
 			Os:         build.Environment.OS,
 			Arch:       build.Environment.Arch,
 			WorkingDir: build.Environment.WorkingDir,
-			Variables:  make(map[string]string),
+			Variables:  make(map[string]string, len(build.Environment.Variables)),
+			Locale:     make(map[string]string, len(build.Environment.Locale)),
+			Timezone:   build.Environment.Timezone,
+			Umask:      build.Environment.Umask,
 		},
 		Hardware: &buildv1.Hardware{
 			Cpu: &buildv1.CPU{
@@ -405,6 +921,7 @@ func (s *Server) convertBuildToProto(build *models.Build) *buildv1.Build {
 				Threads:   build.Hardware.CPUThreads,
 				Frequency: build.Hardware.CPUFreq,
 				CacheSize: build.Hardware.CacheSize,
+				NumaNodes: build.Hardware.NUMANodes,
 			},
 			Memory: &buildv1.Memory{
 				Total:     build.Hardware.MemTotal,
@@ -418,9 +935,17 @@ func (s *Server) convertBuildToProto(build *models.Build) *buildv1.Build {
 		Compiler: &buildv1.Compiler{
 			Name:          build.Compiler.Name,
 			Version:       build.Compiler.Version,
+			VersionString: build.Compiler.VersionString,
+			Wrapper:       build.Compiler.Wrapper,
 			Target:        build.Compiler.Target,
-			Options:       make([]string, 0),
-			Optimizations: make(map[string]bool),
+			Triple: &buildv1.TargetTriple{
+				Arch:   build.Compiler.TargetArch,
+				Vendor: build.Compiler.TargetVendor,
+				Os:     build.Compiler.TargetOS,
+				Abi:    build.Compiler.TargetABI,
+			},
+			Options:       make([]string, 0, len(build.Compiler.Options)),
+			Optimizations: make(map[string]bool, len(build.Compiler.Optimizations)),
 			Flags:         make(map[string]string),
 			Language: &buildv1.Language{
 				Name:          build.Compiler.LanguageName,
@@ -432,22 +957,22 @@ func (s *Server) convertBuildToProto(build *models.Build) *buildv1.Build {
 				SupportsGpu:    build.Compiler.SupportsGPU,
 				SupportsLto:    build.Compiler.SupportsLTO,
 				SupportsPgo:    build.Compiler.SupportsPGO,
-				Extensions:     make([]string, 0),
+				Extensions:     make([]string, 0, len(build.Compiler.Extensions)),
 			},
 		},
 		Command: &buildv1.Command{
 			Executable: build.Command.Executable,
 			WorkingDir: build.Command.WorkingDir,
-			Arguments:  make([]string, 0),
+			Hash:       build.Command.Hash,
+			Arguments:  make([]string, 0, len(build.Command.Arguments)),
 			Env:        make(map[string]string),
 		},
 		Output: &buildv1.Output{
-			Stdout:    build.Output.Stdout,
-			Stderr:    build.Output.Stderr,
+			Stdout:    string(build.Output.Stdout),
+			Stderr:    string(build.Output.Stderr),
 			ExitCode:  build.Output.ExitCode,
-			Artifacts: make([]*buildv1.Artifact, 0),
-			Warnings:  make([]string, 0),
-			Errors:    make([]string, 0),
+			Truncated: build.Output.Truncated,
+			Artifacts: make([]*buildv1.Artifact, 0, len(build.Output.Artifacts)),
 		},
 		ResourceUsage: &buildv1.ResourceUsage{
 			MaxMemory: build.ResourceUsage.MaxMemory,
@@ -459,21 +984,93 @@ func (s *Server) convertBuildToProto(build *models.Build) *buildv1.Build {
 				ReadCount:  build.ResourceUsage.ReadCount,
 				WriteCount: build.ResourceUsage.WriteCount,
 			},
+			PhaseMemory: make(map[string]int64, len(build.ResourceUsage.PhaseMemory)),
 		},
 		Performance: &buildv1.Performance{
 			CompileTime:  build.Performance.CompileTime,
 			LinkTime:     build.Performance.LinkTime,
 			OptimizeTime: build.Performance.OptimizeTime,
-			Phases:       make(map[string]float64),
+			Phases:       make(map[string]float64, len(build.Performance.Phases)),
 		},
 		Remarks: make([]*buildv1.CompilerRemark, len(build.Remarks)),
 	}
 
 	// Convert relationships
+	for phase, bytes := range build.ResourceUsage.PhaseMemory {
+		if n, ok := bytes.(float64); ok {
+			pb.ResourceUsage.PhaseMemory[phase] = int64(n)
+		}
+	}
+	for _, label := range build.Labels {
+		pb.Labels[label.Key] = label.Value
+	}
+
+	if vc := build.VersionControl; vc != nil {
+		pb.VersionControl = &buildv1.VersionControl{
+			Commit:    vc.Commit,
+			Branch:    vc.Branch,
+			Dirty:     vc.Dirty,
+			RemoteUrl: vc.RemoteURL,
+		}
+	}
+
+	if m := build.Metrics; m != nil {
+		pb.Metrics = &buildv1.BuildMetrics{
+			TotalFiles:     m.TotalFiles,
+			ProcessedFiles: m.ProcessedFiles,
+			Warnings:       m.Warnings,
+			Errors:         m.Errors,
+			InputSize:      m.InputSize,
+			OutputSize:     m.OutputSize,
+			TextSize:       m.TextSize,
+			DataSize:       m.DataSize,
+			BssSize:        m.BSSSize,
+			SectionSizes:   make(map[string]int64, len(m.SectionSizes)),
+			Metrics:        make(map[string]float64, len(m.Metrics)),
+		}
+		for name, size := range m.SectionSizes {
+			if n, ok := size.(float64); ok {
+				pb.Metrics.SectionSizes[name] = int64(n)
+			}
+		}
+		for name, value := range m.Metrics {
+			if n, ok := value.(float64); ok {
+				pb.Metrics.Metrics[name] = n
+			}
+		}
+	}
+
+	if m := build.LinkMap; m != nil {
+		pb.LinkMap = &buildv1.LinkMap{
+			SymbolsSeen:       m.SymbolsSeen,
+			DiscardedSections: m.DiscardedSections,
+			DiscardedSize:     m.DiscardedSize,
+			Sections:          make([]*buildv1.LinkSection, len(m.Sections)),
+			Symbols:           make([]*buildv1.LinkSymbol, len(m.Symbols)),
+		}
+		for i, section := range m.Sections {
+			pb.LinkMap.Sections[i] = &buildv1.LinkSection{Name: section.Name, Size: section.Size}
+		}
+		for i, sym := range m.Symbols {
+			pb.LinkMap.Symbols[i] = &buildv1.LinkSymbol{
+				Name:    sym.Name,
+				Section: sym.Section,
+				Object:  sym.Object,
+				Size:    sym.Size,
+			}
+		}
+	}
+
 	for _, v := range build.Environment.Variables {
 		pb.Environment.Variables[v.Key] = v.Value
 	}
 
+	for k, v := range build.Environment.Locale {
+		if s, ok := v.(string); ok {
+			pb.Environment.Locale[k] = s
+		}
+	}
+
 	for _, gpu := range build.Hardware.GPUs {
 		pb.Hardware.Gpus = append(pb.Hardware.Gpus, &buildv1.GPU{
 			Model:       gpu.Model,
@@ -498,6 +1095,12 @@ func (s *Server) convertBuildToProto(build *models.Build) *buildv1.Build {
 	for _, arg := range build.Command.Arguments {
 		pb.Command.Arguments = append(pb.Command.Arguments, arg.Argument)
 	}
+	for _, inv := range build.Command.Invocations {
+		pb.Command.Invocations = append(pb.Command.Invocations, &buildv1.Invocation{
+			Tool:      inv.Tool,
+			Arguments: inv.Arguments,
+		})
+	}
 
 	for _, artifact := range build.Output.Artifacts {
 		pb.Output.Artifacts = append(pb.Output.Artifacts, &buildv1.Artifact{
@@ -508,39 +1111,95 @@ func (s *Server) convertBuildToProto(build *models.Build) *buildv1.Build {
 		})
 	}
 
+	for _, d := range build.Output.Diagnostics {
+		pb.Output.Diagnostics = append(pb.Output.Diagnostics, &buildv1.Diagnostic{
+			Severity: d.Severity,
+			File:     d.File,
+			Line:     d.Line,
+			Col:      d.Col,
+			Message:  d.Message,
+		})
+	}
+	pb.Output.Warnings, pb.Output.Errors = formatDiagnostics(pb.Output.Diagnostics)
+
 	for _, phase := range build.Performance.Phases {
 		pb.Performance.Phases[phase.Phase] = phase.Duration
 	}
 
 	// Convert remarks using converter
-	for i, remark := range build.Remarks {
-		pb.Remarks[i] = remarkToProto(&remark)
+	for i := range build.Remarks {
+		pb.Remarks[i] = remarkToProto(&build.Remarks[i])
+	}
+	for _, count := range build.RemarkCounts {
+		pb.RemarkCounts = append(pb.RemarkCounts, &buildv1.RemarkCount{
+			Pass:   count.Pass,
+			Status: buildv1.CompilerRemark_Status(buildv1.CompilerRemark_Status_value[strings.ToUpper(count.Status)]),
+			Count:  count.Count,
+		})
 	}
 
 	return pb
 }
 
-func getOffset(pageToken string) int {
-	if pageToken == "" {
-		return 0
-	}
+// sortDetails orders the build's unordered collections by name. Rows come
+// back from the database in no particular order, which would make responses
+// differ between identical requests. Options keep the order they were given
+// in, as later ones override earlier ones.
+func sortDetails(build *models.Build) {
+	optimizations := build.Compiler.Optimizations
+	sort.Slice(optimizations, func(i, j int) bool { return optimizations[i].Name < optimizations[j].Name })
 
-	// Try to parse the token as an integer offset
-	offset, err := strconv.Atoi(pageToken)
-	if err != nil {
-		return 0
-	}
+	extensions := build.Compiler.Extensions
+	sort.Slice(extensions, func(i, j int) bool { return extensions[i].Extension < extensions[j].Extension })
 
-	// Ensure offset is non-negative
-	if offset < 0 {
-		return 0
-	}
+	phases := build.Performance.Phases
+	sort.Slice(phases, func(i, j int) bool { return phases[i].Phase < phases[j].Phase })
 
-	// Optional: Add a maximum offset limit to prevent excessive queries
-	const maxOffset = 10000
-	if offset > maxOffset {
-		return maxOffset
+	counts := build.RemarkCounts
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Pass != counts[j].Pass {
+			return counts[i].Pass < counts[j].Pass
+		}
+		return counts[i].Status < counts[j].Status
+	})
+}
+
+// flatDiagnostic stores an entry of the flat warning or error lists,
+// keeping its location when it is in compiler format
+func flatDiagnostic(buildID, severity, text string) models.Diagnostic {
+	parsed, err := diagnostics.Parse(strings.NewReader(text))
+	if err == nil && len(parsed) == 1 && parsed[0].Severity == severity {
+		d := parsed[0]
+		return models.Diagnostic{
+			BuildID:  buildID,
+			Severity: d.Severity,
+			File:     d.File,
+			Line:     d.Line,
+			Col:      d.Col,
+			Message:  d.Message,
+		}
 	}
+	return models.Diagnostic{BuildID: buildID, Severity: severity, Message: text}
+}
 
-	return offset
+// formatDiagnostics fills the flat warning and error lists older clients
+// read from the structured diagnostics
+func formatDiagnostics(diagnostics []*buildv1.Diagnostic) (warnings, errors []string) {
+	warnings, errors = make([]string, 0), make([]string, 0)
+	for _, d := range diagnostics {
+		text := coremodels.Diagnostic{
+			Severity: d.Severity,
+			File:     d.File,
+			Line:     d.Line,
+			Col:      d.Col,
+			Message:  d.Message,
+		}.String()
+		switch d.Severity {
+		case coremodels.SeverityWarning:
+			warnings = append(warnings, text)
+		case coremodels.SeverityError:
+			errors = append(errors, text)
+		}
+	}
+	return warnings, errors
 }
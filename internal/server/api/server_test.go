@@ -0,0 +1,121 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	buildv1 "builds/api/build"
+	models "builds/internal/server/db/models"
+)
+
+func TestConvertBuildToProtoDeterministic(t *testing.T) {
+	// newBuild returns the same build with its unordered rows rotated, as
+	// the database may return them
+	newBuild := func(rotate int) *models.Build {
+		rot := func(n int) []int {
+			order := make([]int, n)
+			for i := range order {
+				order[i] = (i + rotate) % n
+			}
+			return order
+		}
+
+		names := []string{"inline", "vectorize", "unroll"}
+		extensions := []string{"gnu", "openmp"}
+		phases := []string{"parse", "optimize", "codegen"}
+
+		build := &models.Build{
+			ID: "build",
+			Compiler: models.Compiler{
+				BuildID: "build",
+				Options: []models.CompilerOption{
+					{Option: "-O3", Position: 0},
+					{Option: "-march=native", Position: 1},
+					{Option: "-O0", Position: 2},
+				},
+			},
+			Performance: models.Performance{BuildID: "build"},
+		}
+		for _, i := range rot(len(names)) {
+			build.Compiler.Optimizations = append(build.Compiler.Optimizations,
+				models.CompilerOptimization{Name: names[i], Enabled: i%2 == 0})
+		}
+		for _, i := range rot(len(extensions)) {
+			build.Compiler.Extensions = append(build.Compiler.Extensions,
+				models.CompilerExtension{Extension: extensions[i]})
+		}
+		for _, i := range rot(len(phases)) {
+			build.Performance.Phases = append(build.Performance.Phases,
+				models.PerformancePhase{Phase: phases[i], Duration: float64(i)})
+		}
+		return build
+	}
+
+	s := &Server{}
+	first := s.convertBuildToProto(newBuild(0))
+	want, err := protojson.Marshal(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for rotate := 1; rotate < 3; rotate++ {
+		got, err := protojson.Marshal(s.convertBuildToProto(newBuild(rotate)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("rotation %d gave\n%s\nwant\n%s", rotate, got, want)
+		}
+	}
+
+	// Options are a command line, where order matters
+	if want := []string{"-O3", "-march=native", "-O0"}; !reflect.DeepEqual(first.Compiler.Options, want) {
+		t.Errorf("options %v, want %v", first.Compiler.Options, want)
+	}
+}
+
+func TestCreateBuildKeepsKernelInfo(t *testing.T) {
+	client := newTestClient(t, Options{})
+	ctx := context.Background()
+
+	info := &buildv1.KernelInfo{
+		ThreadLimit:   256,
+		MaxThreadsX:   128,
+		MaxThreadsY:   2,
+		MaxThreadsZ:   1,
+		SharedMemory:  4096,
+		Target:        "sm_80",
+		DirectCalls:   3,
+		IndirectCalls: 1,
+		Callees:       []string{"helper", "operator new(unsigned long)", "with,comma"},
+		MemoryAccesses: []*buildv1.MemoryAccess{
+			{Type: "load", AddressSpace: "global", Variable: "a", Location: &buildv1.Location{File: "k.cu", Line: 7}},
+			{Type: "store", AddressSpace: "shared", Variable: "b", Location: &buildv1.Location{File: "k.cu", Line: 9}},
+		},
+		Metrics:    map[string]int64{"spill_stores": 2},
+		Attributes: map[string]string{"launch_bounds": "256"},
+	}
+	_, err := client.CreateBuild(ctx, &buildv1.CreateBuildRequest{Build: &buildv1.Build{
+		Id:      "b1",
+		Remarks: []*buildv1.CompilerRemark{{Id: "r1", Name: "KernelInfo", KernelInfo: info}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	build, err := client.GetBuild(ctx, &buildv1.GetBuildRequest{Id: "b1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(build.Remarks) != 1 {
+		t.Fatalf("got %d remarks, want 1", len(build.Remarks))
+	}
+	if got := build.Remarks[0].KernelInfo; !proto.Equal(got, info) {
+		t.Errorf("kernel info changed:\ngot  %v\nwant %v", got, info)
+	}
+}
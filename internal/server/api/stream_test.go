@@ -0,0 +1,94 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	buildv1 "builds/api/build"
+)
+
+func TestStreamBuildsIgnoresClientClock(t *testing.T) {
+	interval := buildPollInterval
+	buildPollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { buildPollInterval = interval })
+
+	client := newTestClient(t, Options{})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stream, err := client.StreamBuilds(ctx, &buildv1.StreamBuildsRequest{Since: timestamppb.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A client a day ahead must not hide the builds that follow it, nor
+	// must a client a day behind be skipped
+	now := time.Now()
+	for _, tt := range []struct {
+		id    string
+		start time.Time
+	}{
+		{"ahead", now.Add(24 * time.Hour)},
+		{"behind", now.Add(-24 * time.Hour)},
+		{"on time", now},
+	} {
+		build := &buildv1.Build{Id: tt.id, StartTime: timestamppb.New(tt.start)}
+		if _, err := client.CreateBuild(ctx, &buildv1.CreateBuildRequest{Build: build}); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("waiting for %s: %v", tt.id, err)
+		}
+		if got.Id != tt.id {
+			t.Errorf("streamed %s, want %s", got.Id, tt.id)
+		}
+		// The database keeps microseconds
+		if got.StartTime.AsTime().Sub(tt.start).Abs() > time.Millisecond {
+			t.Errorf("%s start time %v, want the client's %v", got.Id, got.StartTime.AsTime(), tt.start)
+		}
+		if created := got.CreatedAt.AsTime(); created.Before(now.Add(-time.Minute)) || created.After(time.Now()) {
+			t.Errorf("%s created at %v, want the server clock", got.Id, created)
+		}
+	}
+}
+
+func TestStreamBuildsWaitsForFinalize(t *testing.T) {
+	interval := buildPollInterval
+	buildPollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { buildPollInterval = interval })
+
+	client := newTestClient(t, Options{})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stream, err := client.StreamBuilds(ctx, &buildv1.StreamBuildsRequest{Since: timestamppb.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A build in progress is created before it is finalized, yet must only
+	// be streamed once it is complete
+	if _, err := client.BeginBuild(ctx, &buildv1.CreateBuildRequest{Build: &buildv1.Build{Id: "streamed"}}); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * buildPollInterval)
+	if _, err := client.FinalizeBuild(ctx, &buildv1.CreateBuildRequest{Build: &buildv1.Build{Id: "streamed", Success: true}}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Id != "streamed" || !got.Success {
+		t.Errorf("streamed %s with success %v, want the finalized build", got.Id, got.Success)
+	}
+	if got.FinishedAt == nil || got.FinishedAt.AsTime().Before(got.CreatedAt.AsTime()) {
+		t.Errorf("finished at %v, want after created at %v", got.FinishedAt, got.CreatedAt)
+	}
+}
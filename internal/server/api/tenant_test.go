@@ -0,0 +1,65 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	buildv1 "builds/api/build"
+	"builds/internal/server/auth"
+	"builds/internal/server/db/dbtest"
+)
+
+func TestTenantIsolation(t *testing.T) {
+	s := NewServer(dbtest.Open(t), Options{})
+	teamA := auth.WithTenant(context.Background(), "team-a")
+	teamB := auth.WithTenant(context.Background(), "team-b")
+
+	_, err := s.CreateBuild(teamA, &buildv1.CreateBuildRequest{Build: &buildv1.Build{
+		Id:      "a1",
+		Remarks: []*buildv1.CompilerRemark{{Id: "r1", Name: "NotVectorized"}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The owner sees the build
+	if _, err := s.GetBuild(teamA, &buildv1.GetBuildRequest{Id: "a1"}); err != nil {
+		t.Errorf("owner GetBuild: %v", err)
+	}
+
+	// Another tenant can neither see nor change it
+	if _, err := s.GetBuild(teamB, &buildv1.GetBuildRequest{Id: "a1"}); status.Code(err) != codes.NotFound {
+		t.Errorf("cross-tenant GetBuild: %v, want NotFound", err)
+	}
+	if _, err := s.GetRemark(teamB, &buildv1.GetRemarkRequest{BuildId: "a1", RemarkId: "r1"}); status.Code(err) != codes.NotFound {
+		t.Errorf("cross-tenant GetRemark: %v, want NotFound", err)
+	}
+	list, err := s.ListBuilds(teamB, &buildv1.ListBuildsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Builds) != 0 {
+		t.Errorf("cross-tenant ListBuilds returned %d builds", len(list.Builds))
+	}
+	count, err := s.CountBuilds(teamB, &buildv1.CountBuildsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count.Count != 0 {
+		t.Errorf("cross-tenant CountBuilds = %d", count.Count)
+	}
+	if _, err := s.DeleteBuild(teamB, &buildv1.DeleteBuildRequest{Id: "a1"}); status.Code(err) != codes.NotFound {
+		t.Errorf("cross-tenant DeleteBuild: %v, want NotFound", err)
+	}
+
+	// Reusing the ID from another tenant does not overwrite the build
+	if _, err := s.CreateBuild(teamB, &buildv1.CreateBuildRequest{Build: &buildv1.Build{Id: "a1"}}); err == nil {
+		t.Error("cross-tenant CreateBuild replaced the build")
+	}
+	if _, err := s.GetBuild(teamA, &buildv1.GetBuildRequest{Id: "a1"}); err != nil {
+		t.Errorf("owner GetBuild after the cross-tenant calls: %v", err)
+	}
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	buildv1 "builds/api/build"
+	"builds/internal/server/db/dbtest"
+)
+
+func TestServerTimes(t *testing.T) {
+	received := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	start, end := serverTimes(received, 1.5)
+	if !end.Equal(received) || !start.Equal(received.Add(-1500*time.Millisecond)) {
+		t.Errorf("got %v to %v, want 1.5s ending at %v", start, end, received)
+	}
+}
+
+// checkTimestamps asserts build was stored with the server's clock when
+// override is set, keeping the client's times, and with the client's
+// clock otherwise
+func checkTimestamps(t *testing.T, got *buildv1.Build, override bool, before time.Time) {
+	t.Helper()
+	start, end := got.StartTime.AsTime(), got.EndTime.AsTime()
+	if !override {
+		if !start.Equal(durationStart) || !end.Equal(durationStart.Add(30*time.Second)) {
+			t.Errorf("stored %v to %v, want the client's times", start, end)
+		}
+		if got.ClientStartTime != nil || got.ClientEndTime != nil {
+			t.Errorf("client times %v and %v kept without the override", got.ClientStartTime, got.ClientEndTime)
+		}
+		return
+	}
+
+	// The database keeps microseconds
+	if end.Before(before.Truncate(time.Millisecond)) || end.After(time.Now().Add(time.Millisecond)) {
+		t.Errorf("end time %v is not when the server received the build", end)
+	}
+	if span := end.Sub(start); span != 30*time.Second {
+		t.Errorf("stored span %v, want the measured 30s", span)
+	}
+	if !got.ClientStartTime.AsTime().Equal(durationStart) || !got.ClientEndTime.AsTime().Equal(durationStart.Add(30*time.Second)) {
+		t.Errorf("client times %v and %v, want the times sent", got.ClientStartTime, got.ClientEndTime)
+	}
+}
+
+func TestCreateBuildServerTimestamps(t *testing.T) {
+	database := dbtest.Open(t)
+	ctx := context.Background()
+
+	for _, override := range []bool{false, true} {
+		s := NewServer(database, Options{ServerTimestamps: override})
+		build := timedBuild("b1", 30*time.Second, 30)
+		if override {
+			build.Id = "b2"
+		}
+
+		before := time.Now()
+		if _, err := s.CreateBuild(ctx, &buildv1.CreateBuildRequest{Build: build}); err != nil {
+			t.Fatal(err)
+		}
+		got, err := s.GetBuild(ctx, &buildv1.GetBuildRequest{Id: build.Id})
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkTimestamps(t, got, override, before)
+	}
+}
+
+func TestFinalizeBuildServerTimestamps(t *testing.T) {
+	s := NewServer(dbtest.Open(t), Options{ServerTimestamps: true})
+	ctx := context.Background()
+
+	build := timedBuild("b1", 30*time.Second, 30)
+	before := time.Now()
+	if _, err := s.BeginBuild(ctx, &buildv1.CreateBuildRequest{Build: build}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.FinalizeBuild(ctx, &buildv1.CreateBuildRequest{Build: build}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.GetBuild(ctx, &buildv1.GetBuildRequest{Id: "b1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkTimestamps(t, got, true, before)
+}
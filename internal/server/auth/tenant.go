@@ -0,0 +1,99 @@
+// internal/server/auth/tenant.go
+
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type tenantKey struct{}
+
+// WithTenant returns a context scoped to tenant
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant of the caller, or "" when the request
+// is not scoped
+func TenantFromContext(ctx context.Context) string {
+	tenant, _ := ctx.Value(tenantKey{}).(string)
+	return tenant
+}
+
+// Tenants maps bearer tokens to the tenant they belong to
+type Tenants map[string]string
+
+// ParseTenants parses a "token=tenant,token=tenant" list
+func ParseTenants(value string) (Tenants, error) {
+	tenants := make(Tenants)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		token, tenant, ok := strings.Cut(pair, "=")
+		if !ok || token == "" || tenant == "" {
+			return nil, fmt.Errorf("invalid tenant mapping %q, expected token=tenant", pair)
+		}
+		tenants[token] = tenant
+	}
+	return tenants, nil
+}
+
+// resolve scopes ctx to the tenant owning the request's bearer token
+func (t Tenants) resolve(ctx context.Context) (context.Context, error) {
+	if len(t) == 0 {
+		return ctx, nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get("authorization") {
+		token, ok := strings.CutPrefix(value, "Bearer ")
+		if !ok {
+			continue
+		}
+		if tenant, ok := t[token]; ok {
+			return WithTenant(ctx, tenant), nil
+		}
+	}
+	return nil, status.Error(codes.Unauthenticated, "unknown or missing token")
+}
+
+// UnaryInterceptor rejects unknown tokens and scopes unary calls to a tenant
+func (t Tenants) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := t.resolve(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor rejects unknown tokens and scopes streams to a tenant
+func (t Tenants) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := t.resolve(ss.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, &scopedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// scopedStream overrides the context of a server stream
+type scopedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *scopedStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestParseTenants(t *testing.T) {
+	tenants, err := ParseTenants(" tokA=team-a, tokB=team-b ,")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Tenants{"tokA": "team-a", "tokB": "team-b"}); !reflect.DeepEqual(tenants, want) {
+		t.Errorf("parsed %v, want %v", tenants, want)
+	}
+
+	for _, value := range []string{"tokA", "=team-a", "tokA="} {
+		if _, err := ParseTenants(value); err == nil {
+			t.Errorf("ParseTenants(%q) succeeded", value)
+		}
+	}
+}
+
+func TestUnaryInterceptor(t *testing.T) {
+	tenants := Tenants{"tokA": "team-a"}
+	call := func(tenants Tenants, auth ...string) (string, error) {
+		ctx := context.Background()
+		if len(auth) > 0 {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", auth[0]))
+		}
+		var tenant string
+		_, err := tenants.UnaryInterceptor()(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			tenant = TenantFromContext(ctx)
+			return nil, nil
+		})
+		return tenant, err
+	}
+
+	tests := []struct {
+		name       string
+		tenants    Tenants
+		auth       []string
+		wantTenant string
+		wantCode   codes.Code
+	}{
+		{"known token", tenants, []string{"Bearer tokA"}, "team-a", codes.OK},
+		{"unknown token", tenants, []string{"Bearer tokB"}, "", codes.Unauthenticated},
+		{"no token", tenants, nil, "", codes.Unauthenticated},
+		{"not a bearer token", tenants, []string{"tokA"}, "", codes.Unauthenticated},
+		{"tenancy disabled", nil, nil, "", codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenant, err := call(tt.tenants, tt.auth...)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("got %v, want %s", err, tt.wantCode)
+			}
+			if tenant != tt.wantTenant {
+				t.Errorf("scoped to %q, want %q", tenant, tt.wantTenant)
+			}
+		})
+	}
+}
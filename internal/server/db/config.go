@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"gorm.io/driver/postgres"
@@ -35,39 +36,72 @@ func NewDefaultConfig() *Config {
 		SSLMode:      os.Getenv("DB_SSLMODE"),
 		MaxOpenConns: getIntEnv("DB_MAX_OPEN_CONNS", 25),
 		MaxIdleConns: getIntEnv("DB_MAX_IDLE_CONNS", 5),
-		MaxLifetime:  time.Duration(getIntEnv("DB_MAX_LIFETIME", int(time.Hour))),
+		MaxLifetime:  getDurationEnv("DB_MAX_LIFETIME", time.Hour),
 	}
 }
 
+// DSN returns the connection string for the config. Unset fields are left
+// out so the driver defaults apply, and values are quoted so passwords may
+// contain spaces.
+func (c *Config) DSN() string {
+	fields := []struct{ key, value string }{
+		{"host", c.Host},
+		{"port", strconv.Itoa(c.Port)},
+		{"user", c.User},
+		{"password", c.Password},
+		{"dbname", c.DatabaseName},
+		{"sslmode", c.SSLMode},
+	}
+
+	parts := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if f.value == "" {
+			continue
+		}
+		value := strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(f.value)
+		parts = append(parts, fmt.Sprintf("%s='%s'", f.key, value))
+	}
+	return strings.Join(parts, " ")
+}
+
+// Connect opens the database the config's connection fields describe
 func Connect(config *Config) (*gorm.DB, error) {
-	dsn := fmt.Sprintf(
-		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
-		config.Host,
-		config.Port,
-		config.User,
-		config.Password,
-		config.DatabaseName,
-		config.SSLMode,
-	)
+	return Open(config.DSN(), config)
+}
 
+// Open opens the database at dsn, a URL or key=value connection string, and
+// sizes its pool by config. Every server connection shares one gorm config,
+// logging only slow queries and errors rather than each statement.
+func Open(dsn string, config *Config) (*gorm.DB, error) {
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
+		Logger: logger.Default.LogMode(logger.Warn),
+		// Report constraint violations as gorm errors so handlers can classify them
+		TranslateError: true,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	if err := ConfigurePool(db, config); err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// ConfigurePool applies the config's connection pool limits to an open
+// database
+func ConfigurePool(db *gorm.DB, config *Config) error {
 	sqlDB, err := db.DB()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get underlying *sql.DB: %w", err)
+		return fmt.Errorf("failed to get underlying *sql.DB: %w", err)
 	}
 
-	// Set connection pool settings
 	sqlDB.SetMaxOpenConns(config.MaxOpenConns)
 	sqlDB.SetMaxIdleConns(config.MaxIdleConns)
 	sqlDB.SetConnMaxLifetime(config.MaxLifetime)
 
-	return db, nil
+	return nil
 }
 
 // Helper function to retrieve an integer environment variable with a default fallback
@@ -82,3 +116,17 @@ func getIntEnv(key string, defaultValue int) int {
 	}
 	return intValue
 }
+
+// Helper function to retrieve a duration environment variable such as "30m"
+// with a default fallback
+func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return defaultValue
+	}
+	return d
+}
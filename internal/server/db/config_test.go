@@ -0,0 +1,92 @@
+package db_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"builds/internal/server/db"
+)
+
+func TestNewDefaultConfig(t *testing.T) {
+	t.Setenv("DB_HOST", "db.internal")
+	t.Setenv("DB_PORT", "6432")
+	t.Setenv("DB_USER", "builds")
+	t.Setenv("DB_PASSWORD", `it's a secret\`)
+	t.Setenv("DB_NAME", "builds")
+	t.Setenv("DB_SSLMODE", "disable")
+
+	// The driver must read back what was configured, quotes and all
+	config, err := pgx.ParseConfig(db.NewDefaultConfig().DSN())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Host != "db.internal" || config.Port != 6432 || config.User != "builds" || config.Database != "builds" {
+		t.Errorf("connects to %s@%s:%d/%s", config.User, config.Host, config.Port, config.Database)
+	}
+	if config.Password != `it's a secret\` {
+		t.Errorf("password %q", config.Password)
+	}
+	if config.TLSConfig != nil {
+		t.Error("TLS enabled with DB_SSLMODE=disable")
+	}
+}
+
+func TestDSNLeavesOutUnsetFields(t *testing.T) {
+	config := &db.Config{Host: "localhost", Port: 5432}
+	if got, want := config.DSN(), "host='localhost' port='5432'"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestConfigurePool(t *testing.T) {
+	t.Setenv("DB_MAX_OPEN_CONNS", "7")
+	t.Setenv("DB_MAX_IDLE_CONNS", "3")
+	t.Setenv("DB_MAX_LIFETIME", "10m")
+	config := db.NewDefaultConfig()
+	if config.MaxOpenConns != 7 || config.MaxIdleConns != 3 || config.MaxLifetime != 10*time.Minute {
+		t.Fatalf("pool settings %d open, %d idle, %s lifetime", config.MaxOpenConns, config.MaxIdleConns, config.MaxLifetime)
+	}
+
+	// Opening the pool does not connect, so no server is needed
+	gormDB, err := gorm.Open(postgres.Open("host=localhost"), &gorm.Config{DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := gormDB.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sqlDB.Close()
+
+	if err := db.ConfigurePool(gormDB, config); err != nil {
+		t.Fatal(err)
+	}
+	if got := sqlDB.Stats().MaxOpenConnections; got != 7 {
+		t.Errorf("MaxOpenConnections = %d, want 7", got)
+	}
+}
+
+func TestPoolDefaults(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"unset", ""},
+		{"not a number", "lots"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("DB_MAX_OPEN_CONNS", tt.value)
+			t.Setenv("DB_MAX_IDLE_CONNS", tt.value)
+			t.Setenv("DB_MAX_LIFETIME", tt.value)
+			config := db.NewDefaultConfig()
+			if config.MaxOpenConns != 25 || config.MaxIdleConns != 5 || config.MaxLifetime != time.Hour {
+				t.Errorf("pool settings %d open, %d idle, %s lifetime", config.MaxOpenConns, config.MaxIdleConns, config.MaxLifetime)
+			}
+		})
+	}
+}
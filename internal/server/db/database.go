@@ -5,26 +5,47 @@ package db
 import (
 	models "builds/internal/server/db/models"
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type Database struct {
-	DB *gorm.DB
+	DB     *gorm.DB
+	tenant string
 }
 
 func New(db *gorm.DB) *Database {
 	return &Database{DB: db}
 }
 
+// ForTenant returns a view of the database restricted to a tenant's builds.
+// An empty tenant leaves queries unscoped.
+func (d *Database) ForTenant(tenant string) *Database {
+	return &Database{DB: d.DB, tenant: tenant}
+}
+
+// scope restricts a query on builds to the database's tenant
+func (d *Database) scope(db *gorm.DB) *gorm.DB {
+	if d.tenant == "" {
+		return db
+	}
+	return db.Where("builds.tenant = ?", d.tenant)
+}
+
 func (d *Database) Migrate() error {
 	// The order is important here due to foreign key constraints
 	modelsList := []interface{}{
 		// Core models
 		&models.Build{},
+		&models.BuildLabel{},
+		&models.RemarkCount{},
 		&models.Environment{},
 		&models.EnvironmentVariable{},
+		&models.VersionControl{},
 		&models.Hardware{},
 		&models.GPU{},
 		&models.Compiler{},
@@ -33,22 +54,32 @@ func (d *Database) Migrate() error {
 		&models.CompilerExtension{},
 		&models.Command{},
 		&models.CommandArgument{},
+		&models.CommandInvocation{},
 		&models.Output{},
 		&models.Artifact{},
+		&models.Diagnostic{},
 		&models.ResourceUsage{},
 		&models.Performance{},
 		&models.PerformancePhase{},
+		&models.BuildMetrics{},
+		&models.LinkMap{},
+		&models.LinkSection{},
+		&models.LinkSymbol{},
 
 		// Remarks and related models
 		&models.CompilerRemark{},
 		&models.KernelInfo{},
 		&models.MemoryAccess{},
+		&models.RawRemarks{},
 	}
 
 	// Create custom types first
 	if err := d.createCustomTypes(); err != nil {
 		return fmt.Errorf("failed to create custom types: %w", err)
 	}
+	if err := ConvertOutputColumns(d.DB); err != nil {
+		return err
+	}
 
 	// Migrate models
 	for _, model := range modelsList {
@@ -56,6 +87,16 @@ func (d *Database) Migrate() error {
 			return fmt.Errorf("failed to migrate %T: %w", model, err)
 		}
 	}
+	if err := KeyOptionsByPosition(d.DB); err != nil {
+		return err
+	}
+	// Builds stored before finished_at existed finished when they were
+	// created, or will when they are finalized
+	if err := d.DB.Model(&models.Build{}).
+		Where("finished_at IS NULL AND NOT in_progress").
+		Update("finished_at", gorm.Expr("created_at")).Error; err != nil {
+		return fmt.Errorf("failed to backfill finished_at: %w", err)
+	}
 
 	return nil
 }
@@ -203,17 +244,37 @@ func (d *Database) CreateBuildWithRelations(build *models.Build) error {
 func (d *Database) GetBuildByID(id string) (*models.Build, error) {
 	var build models.Build
 
-	result := d.DB.
+	result := d.scope(d.DB).
+		Preload("Labels").
+		Preload("RemarkCounts").
 		Preload("Environment.Variables").
+		Preload("VersionControl").
 		Preload("Hardware.GPUs").
-		Preload("Compiler.Options").
+		Preload("Compiler.Options", func(db *gorm.DB) *gorm.DB {
+			return db.Order("compiler_options.position ASC")
+		}).
 		Preload("Compiler.Optimizations").
 		Preload("Compiler.Extensions").
-		Preload("Command.Arguments").
+		Preload("Command.Arguments", func(db *gorm.DB) *gorm.DB {
+			return db.Order("command_arguments.position ASC")
+		}).
+		Preload("Command.Invocations", func(db *gorm.DB) *gorm.DB {
+			return db.Order("command_invocations.position ASC")
+		}).
 		Preload("Output.Artifacts").
+		Preload("Output.Diagnostics", func(db *gorm.DB) *gorm.DB {
+			return db.Order("diagnostics.id ASC")
+		}).
 		Preload("ResourceUsage").
 		Preload("Performance").
 		Preload("Performance.Phases").
+		Preload("Metrics").
+		Preload("LinkMap.Sections", func(db *gorm.DB) *gorm.DB {
+			return db.Order("link_sections.id ASC")
+		}).
+		Preload("LinkMap.Symbols", func(db *gorm.DB) *gorm.DB {
+			return db.Order("link_symbols.id ASC")
+		}).
 		First(&build, "id = ?", id)
 
 	if result.Error != nil {
@@ -225,7 +286,10 @@ func (d *Database) GetBuildByID(id string) (*models.Build, error) {
 	if err := d.DB.
 		Where("build_id = ?", build.ID).
 		Preload("KernelInfo").
-		Preload("KernelInfo.MemoryAccesses").
+		Preload("KernelInfo.MemoryAccesses", func(db *gorm.DB) *gorm.DB {
+			return db.Order("memory_accesses.id ASC")
+		}).
+		Order("id ASC").
 		Find(&remarks).Error; err != nil {
 		return nil, fmt.Errorf("failed to load remarks: %w", err)
 	}
@@ -234,69 +298,307 @@ func (d *Database) GetBuildByID(id string) (*models.Build, error) {
 	return &build, nil
 }
 
-func (d *Database) ListBuilds(pageSize int, lastID string) ([]models.Build, error) {
+// ListFilter selects builds to list. Zero fields match everything.
+type ListFilter struct {
+	BuildFilter
+	Labels map[string]string // Builds must carry every label
+	Arch   string            // Normalised target architecture
+	// Prefix of the command hash, selecting runs of the same compile
+	CommandHash string
+}
+
+// Cursor is a position in the build listing: the last build of a page
+type Cursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// ListBuilds returns a page of builds, newest first, after the cursor, and
+// whether more builds follow. Only builds matching the filter are listed.
+// Listed builds leave out their remarks, which GetBuildByID loads.
+func (d *Database) ListBuilds(pageSize int, after *Cursor, filter ListFilter) ([]models.Build, bool, error) {
 	var builds []models.Build
 
-	query := d.DB.Model(&models.Build{}).Order("created_at DESC")
+	// Order by the server-assigned created_at; the id breaks ties so builds
+	// stored in the same instant are neither skipped nor repeated
+	query := d.scope(d.DB.Model(&models.Build{})).Order("created_at DESC, id DESC")
 
-	if lastID != "" {
-		var lastBuild models.Build
-		if err := d.DB.First(&lastBuild, "id = ?", lastID).Error; err != nil {
-			return nil, err
-		}
-		query = query.Where("created_at < ?", lastBuild.CreatedAt)
+	if after != nil {
+		query = query.Where("(builds.created_at < ? OR (builds.created_at = ? AND builds.id < ?))",
+			after.CreatedAt, after.CreatedAt, after.ID)
+	}
+	query = filter.apply(query)
+
+	keys := make([]string, 0, len(filter.Labels))
+	for key := range filter.Labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		query = query.Where("EXISTS (SELECT 1 FROM build_labels l WHERE l.build_id = builds.id AND l.key = ? AND l.value = ?)",
+			key, filter.Labels[key])
+	}
+	if filter.Arch != "" {
+		query = query.Where("EXISTS (SELECT 1 FROM compilers c WHERE c.build_id = builds.id AND c.target_arch = ?)", filter.Arch)
+	}
+	if filter.CommandHash != "" {
+		query = query.Where("EXISTS (SELECT 1 FROM commands c WHERE c.build_id = builds.id AND c.hash LIKE ?)", filter.CommandHash+"%")
 	}
 
+	// Relations are preloaded with one query each for the whole page
 	err := query.
+		Preload("Labels").
 		Preload("Environment").
+		Preload("VersionControl").
 		Preload("Hardware").
 		Preload("Compiler").
+		Preload("Command"). // For the hash; arguments are left out of listings
 		Preload("ResourceUsage").
-		Limit(pageSize).
+		Preload("Metrics").
+		Limit(pageSize + 1). // One more tells whether another page follows
 		Find(&builds).Error
 
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
-	// Load remarks separately for each build
-	for i := range builds {
-		if err := d.DB.
-			Where("build_id = ?", builds[i].ID).
-			Preload("KernelInfo").
-			Find(&builds[i].Remarks).Error; err != nil {
-			return nil, fmt.Errorf("failed to load remarks for build %s: %w", builds[i].ID, err)
-		}
+	if len(builds) > pageSize {
+		return builds[:pageSize], true, nil
 	}
-
-	return builds, nil
+	return builds, false, nil
 }
 
+// DeleteBuild soft-deletes a build. It stays recoverable with UndeleteBuild
+// until PruneDeleted removes it.
 func (d *Database) DeleteBuild(id string) error {
-	return d.DB.Transaction(func(tx *gorm.DB) error {
+	result := d.scope(d.DB).Where("id = ?", id).Delete(&models.Build{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// UndeleteBuild restores a soft-deleted build
+func (d *Database) UndeleteBuild(id string) error {
+	result := d.scope(d.DB.Unscoped().Model(&models.Build{})).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// PruneDeleted permanently removes builds soft-deleted before the cutoff,
+// along with everything recorded for them
+func (d *Database) PruneDeleted(before time.Time) (int64, error) {
+	var ids []string
+	if err := d.scope(d.DB.Unscoped().Model(&models.Build{})).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+		Pluck("id", &ids).Error; err != nil {
+		return 0, fmt.Errorf("failed to find deleted builds: %w", err)
+	}
+
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	err := d.DB.Transaction(func(tx *gorm.DB) error {
 		// Delete related records first to maintain referential integrity
-		if err := tx.Where("build_id = ?", id).Delete(&models.CompilerRemark{}).Error; err != nil {
+		if _, err := deleteRemarks(tx, ids); err != nil {
 			return err
 		}
 
-		// Delete the build
-		result := tx.Where("id = ?", id).Delete(&models.Build{})
-		if result.Error != nil {
-			return result.Error
+		dependents := []interface{}{
+			&models.BuildLabel{},
+			&models.RemarkCount{},
+			&models.EnvironmentVariable{},
+			&models.Environment{},
+			&models.VersionControl{},
+			&models.GPU{},
+			&models.Hardware{},
+			&models.CompilerOption{},
+			&models.CompilerOptimization{},
+			&models.CompilerExtension{},
+			&models.Compiler{},
+			&models.CommandArgument{},
+			&models.CommandInvocation{},
+			&models.Command{},
+			&models.Artifact{},
+			&models.Diagnostic{},
+			&models.Output{},
+			&models.ResourceUsage{},
+			&models.PerformancePhase{},
+			&models.Performance{},
+			&models.BuildMetrics{},
+			&models.LinkSection{},
+			&models.LinkSymbol{},
+			&models.LinkMap{},
+		}
+		for _, model := range dependents {
+			if err := tx.Where("build_id IN ?", ids).Delete(model).Error; err != nil {
+				return fmt.Errorf("failed to delete %T: %w", model, err)
+			}
+		}
+
+		return tx.Unscoped().Where("id IN ?", ids).Delete(&models.Build{}).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(len(ids)), nil
+}
+
+// PurgeRemarks deletes the remarks of a build, with their kernel info and
+// the raw optimization record, while keeping the build itself. The build
+// records that it was purged, how many remarks it had and how many each pass
+// emitted by status, so its analysis summary survives. Builds still
+// streaming remarks are refused.
+func (d *Database) PurgeRemarks(id string) (int64, error) {
+	var build models.Build
+	if err := d.scope(d.DB).Select("id", "remarks_seen", "in_progress").First(&build, "id = ?", id).Error; err != nil {
+		return 0, err
+	}
+	if build.InProgress {
+		return 0, fmt.Errorf("build %s is still in progress", id)
+	}
+
+	var purged int64
+	err := d.DB.Transaction(func(tx *gorm.DB) error {
+		var counts []models.RemarkCount
+		err := tx.Model(&models.CompilerRemark{}).
+			Select("build_id, COALESCE(NULLIF(pass_name, ''), pass) AS pass, status, COUNT(*) AS count").
+			Where("build_id = ?", id).
+			Group("build_id, COALESCE(NULLIF(pass_name, ''), pass), status").
+			Scan(&counts).Error
+		if err != nil {
+			return fmt.Errorf("failed to count remarks: %w", err)
+		}
+		if len(counts) > 0 {
+			if err := tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(&counts).Error; err != nil {
+				return fmt.Errorf("failed to store remark counts: %w", err)
+			}
 		}
 
-		if result.RowsAffected == 0 {
-			return gorm.ErrRecordNotFound
+		if purged, err = deleteRemarks(tx, []string{id}); err != nil {
+			return err
 		}
 
-		return nil
+		updates := map[string]interface{}{"remarks_purged": true}
+		if build.RemarksSeen == 0 {
+			updates["remarks_seen"] = purged
+		}
+		return tx.Model(&models.Build{}).Where("id = ?", id).Updates(updates).Error
 	})
+	if err != nil {
+		return 0, err
+	}
+	return purged, nil
+}
+
+// deleteRemarks removes the remarks of the given builds and everything
+// hanging off them, returning the number of remarks deleted
+func deleteRemarks(tx *gorm.DB, buildIDs []string) (int64, error) {
+	remarkIDs := tx.Model(&models.CompilerRemark{}).Select("id").Where("build_id IN ?", buildIDs)
+	kernelIDs := tx.Model(&models.KernelInfo{}).Select("id").Where("remark_id IN (?)", remarkIDs)
+	if err := tx.Where("kernel_info_id IN (?)", kernelIDs).Delete(&models.MemoryAccess{}).Error; err != nil {
+		return 0, fmt.Errorf("failed to delete memory accesses: %w", err)
+	}
+	if err := tx.Where("remark_id IN (?)", remarkIDs).Delete(&models.KernelInfo{}).Error; err != nil {
+		return 0, fmt.Errorf("failed to delete kernel info: %w", err)
+	}
+
+	result := tx.Where("build_id IN ?", buildIDs).Delete(&models.CompilerRemark{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete remarks: %w", result.Error)
+	}
+	if err := tx.Where("build_id IN ?", buildIDs).Delete(&models.RawRemarks{}).Error; err != nil {
+		return 0, fmt.Errorf("failed to delete raw remarks: %w", err)
+	}
+	return result.RowsAffected, nil
+}
+
+// GetRawRemarks returns the stored optimization record of a build
+func (d *Database) GetRawRemarks(buildID string) (*models.RawRemarks, error) {
+	if err := d.scope(d.DB).Select("id").First(&models.Build{}, "id = ?", buildID).Error; err != nil {
+		return nil, fmt.Errorf("failed to get raw remarks: %w", err)
+	}
+
+	var raw models.RawRemarks
+	if err := d.DB.First(&raw, "build_id = ?", buildID).Error; err != nil {
+		return nil, fmt.Errorf("failed to get raw remarks: %w", err)
+	}
+	return &raw, nil
+}
+
+// StoreRawRemarks attaches a gzip-compressed optimization record to a build
+// visible to the tenant, replacing any record it already had
+func (d *Database) StoreRawRemarks(buildID string, data []byte) error {
+	if err := d.scope(d.DB).Select("id").First(&models.Build{}, "id = ?", buildID).Error; err != nil {
+		return fmt.Errorf("failed to store raw remarks: %w", err)
+	}
+
+	raw := &models.RawRemarks{BuildID: buildID, Data: data}
+	err := d.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "build_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"data"}),
+	}).Create(raw).Error
+	if err != nil {
+		return fmt.Errorf("failed to store raw remarks: %w", err)
+	}
+	return nil
+}
+
+// GetRemark returns a remark of a build by its stable ID
+func (d *Database) GetRemark(buildID, remarkID string) (*models.CompilerRemark, error) {
+	if err := d.scope(d.DB).Select("id").First(&models.Build{}, "id = ?", buildID).Error; err != nil {
+		return nil, fmt.Errorf("failed to get remark: %w", err)
+	}
+
+	var remark models.CompilerRemark
+	err := d.DB.
+		Preload("KernelInfo").
+		Preload("KernelInfo.MemoryAccesses").
+		First(&remark, "build_id = ? AND stable_id = ?", buildID, remarkID).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get remark: %w", err)
+	}
+	return &remark, nil
+}
+
+// RemarksAfter returns up to limit remarks of a build stored after the
+// remark with database ID afterID, in the order they were stored. Callers
+// check the build is visible to the tenant first.
+func (d *Database) RemarksAfter(buildID string, afterID uint, limit int) ([]models.CompilerRemark, error) {
+	var remarks []models.CompilerRemark
+	err := d.DB.
+		Preload("KernelInfo").
+		Preload("KernelInfo.MemoryAccesses").
+		Where("build_id = ? AND id > ?", buildID, afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&remarks).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get remarks: %w", err)
+	}
+	return remarks, nil
 }
 
 func (d *Database) GetBuildsAfter(timestamp string) ([]models.Build, error) {
 	var builds []models.Build
 
-	err := d.DB.
+	err := d.scope(d.DB).
 		Where("created_at > ?", timestamp).
 		Order("created_at ASC").
 		Preload("Environment").
@@ -314,6 +616,12 @@ func (d *Database) GetBuildsAfter(timestamp string) ([]models.Build, error) {
 }
 
 func (d *Database) createCustomTypes() error {
+	// Enums are PostgreSQL types; other databases, such as the SQLite ones
+	// tests use, take the column types as plain text
+	if d.DB.Dialector.Name() != "postgres" {
+		return nil
+	}
+
 	// Create enums if needed
 	type enumInfo struct {
 		name       string
@@ -372,6 +680,71 @@ func (d *Database) createCustomTypes() error {
 	return nil
 }
 
+// ConvertOutputColumns turns the text stdout and stderr columns of
+// databases created before output compression into bytea. AutoMigrate
+// would cast them as bytea escape syntax, which fails on the backslashes
+// of Windows paths, so the text is converted explicitly. It must run
+// before Output is migrated.
+func ConvertOutputColumns(gormDB *gorm.DB) error {
+	migrator := gormDB.Migrator()
+	if !migrator.HasTable(&models.Output{}) {
+		return nil
+	}
+	columns, err := migrator.ColumnTypes(&models.Output{})
+	if err != nil {
+		return fmt.Errorf("failed to read output columns: %w", err)
+	}
+	for _, column := range columns {
+		name := column.Name()
+		if (name != "stdout" && name != "stderr") || !strings.EqualFold(column.DatabaseTypeName(), "text") {
+			continue
+		}
+		sql := fmt.Sprintf(`ALTER TABLE outputs ALTER COLUMN %s TYPE bytea USING convert_to(%s, 'UTF8')`, name, name)
+		if err := gormDB.Exec(sql).Error; err != nil {
+			return fmt.Errorf("failed to convert outputs.%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// KeyOptionsByPosition moves the primary key of compiler options from the
+// option text to its position, as in databases created before repeated
+// flags such as -I could be stored. Options stored before positions were
+// kept all share position 0, so each build's are numbered first. It must
+// run after CompilerOption is migrated.
+func KeyOptionsByPosition(gormDB *gorm.DB) error {
+	columns, err := gormDB.Migrator().ColumnTypes(&models.CompilerOption{})
+	if err != nil {
+		return fmt.Errorf("failed to read compiler option columns: %w", err)
+	}
+	keyed := false
+	for _, column := range columns {
+		if primary, ok := column.PrimaryKey(); ok && primary && column.Name() == "option" {
+			keyed = true
+		}
+	}
+	if !keyed {
+		return nil
+	}
+
+	return gormDB.Transaction(func(tx *gorm.DB) error {
+		statements := []string{
+			`UPDATE compiler_options o SET position = n.position
+			FROM (SELECT build_id, option, row_number() OVER (PARTITION BY build_id ORDER BY position, option) - 1 AS position
+				FROM compiler_options) n
+			WHERE o.build_id = n.build_id AND o.option = n.option`,
+			`ALTER TABLE compiler_options DROP CONSTRAINT compiler_options_pkey`,
+			`ALTER TABLE compiler_options ADD PRIMARY KEY (build_id, position)`,
+		}
+		for _, sql := range statements {
+			if err := tx.Exec(sql).Error; err != nil {
+				return fmt.Errorf("failed to key compiler options by position: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
 // Ensure table consistency
 func (d *Database) EnsureTables() error {
 	// Check if KernelInfo table exists
@@ -390,3 +763,13 @@ func (d *Database) EnsureTables() error {
 
 	return nil
 }
+
+// BuildInProgress reports whether a build visible to the tenant is still
+// accepting streamed remarks
+func (d *Database) BuildInProgress(id string) (bool, error) {
+	var build models.Build
+	if err := d.scope(d.DB).Select("id", "in_progress").First(&build, "id = ?", id).Error; err != nil {
+		return false, err
+	}
+	return build.InProgress, nil
+}
@@ -0,0 +1,435 @@
+package db_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"builds/internal/server/db"
+	"builds/internal/server/db/dbtest"
+	models "builds/internal/server/db/models"
+)
+
+func TestDeleteUndelete(t *testing.T) {
+	database := dbtest.Open(t)
+	createBuild(t, database, models.Build{ID: "build", Remarks: []models.CompilerRemark{{Name: "NotVectorized"}}})
+
+	if err := database.DeleteBuild("build"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := database.GetBuildByID("build"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("GetBuildByID of a deleted build: %v, want not found", err)
+	}
+	if err := database.DeleteBuild("build"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("deleting twice: %v, want not found", err)
+	}
+
+	if err := database.UndeleteBuild("build"); err != nil {
+		t.Fatal(err)
+	}
+	build, err := database.GetBuildByID("build")
+	if err != nil {
+		t.Fatalf("GetBuildByID after undelete: %v", err)
+	}
+	if len(build.Remarks) != 1 {
+		t.Errorf("restored build has %d remarks, want 1", len(build.Remarks))
+	}
+	if err := database.UndeleteBuild("build"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("undeleting a live build: %v, want not found", err)
+	}
+}
+
+func TestDeletePruneGone(t *testing.T) {
+	database := dbtest.Open(t)
+	createBuild(t, database, models.Build{ID: "build"})
+	if err := database.DeleteBuild("build"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Builds deleted after the cutoff are kept for recovery
+	if pruned, err := database.PruneDeleted(time.Now().Add(-time.Hour)); err != nil || pruned != 0 {
+		t.Fatalf("pruned %d builds before the cutoff: %v", pruned, err)
+	}
+	if pruned, err := database.PruneDeleted(time.Now().Add(time.Hour)); err != nil || pruned != 1 {
+		t.Fatalf("pruned %d builds, want 1: %v", pruned, err)
+	}
+	if err := database.UndeleteBuild("build"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("undeleting a pruned build: %v, want not found", err)
+	}
+}
+
+// fullBuild has a row in every table that records a build
+func fullBuild(id string) models.Build {
+	remark := notVectorized("foo")
+	remark.KernelInfo = &models.KernelInfo{
+		Callees:        models.StringArray{"bar"},
+		MemoryAccesses: []models.MemoryAccess{{Type: "load"}},
+	}
+	return models.Build{
+		ID: id,
+		Environment: models.Environment{
+			BuildID:   id,
+			Variables: []models.EnvironmentVariable{{Key: "CC", Value: "cc"}},
+		},
+		VersionControl: &models.VersionControl{Commit: "abc"},
+		Hardware: models.Hardware{
+			BuildID: id,
+			GPUs:    []models.GPU{{Model: "gpu"}},
+		},
+		Compiler: models.Compiler{
+			BuildID:       id,
+			Options:       []models.CompilerOption{{Option: "-O2"}},
+			Optimizations: []models.CompilerOptimization{{Name: "vectorize", Enabled: true}},
+			Extensions:    []models.CompilerExtension{{Extension: "openmp"}},
+		},
+		Command: models.Command{
+			BuildID:     id,
+			Arguments:   []models.CommandArgument{{Argument: "foo.c"}},
+			Invocations: []models.CommandInvocation{{Tool: "cc1", Arguments: models.StringArray{"foo.c"}}},
+		},
+		Output: models.Output{
+			BuildID:     id,
+			Artifacts:   []models.Artifact{{Path: "foo.o"}},
+			Diagnostics: []models.Diagnostic{{Severity: "warning", Message: "unused"}},
+		},
+		ResourceUsage: models.ResourceUsage{BuildID: id},
+		Performance: models.Performance{
+			BuildID: id,
+			Phases:  []models.PerformancePhase{{Phase: "parse"}},
+		},
+		LinkMap: &models.LinkMap{
+			Sections: []models.LinkSection{{Name: ".text"}},
+			Symbols:  []models.LinkSymbol{{Name: "main"}},
+		},
+		Metrics: &models.BuildMetrics{},
+		Remarks: []models.CompilerRemark{remark},
+		Labels:  []models.BuildLabel{{Key: "project", Value: "app"}},
+	}
+}
+
+func TestPurgeRemarks(t *testing.T) {
+	database := dbtest.Open(t)
+	for _, id := range []string{"purged", "kept", "streaming"} {
+		build := fullBuild(id)
+		build.Metrics = &models.BuildMetrics{Warnings: 1, TextSize: 4096}
+		build.InProgress = id == "streaming"
+		if id == "purged" {
+			// Both inline remarks are kept as one count
+			build.Remarks = append(build.Remarks,
+				models.CompilerRemark{Name: "Inlined", PassName: "inline", Status: "passed"},
+				models.CompilerRemark{Name: "Inlined", PassName: "inline", Status: "passed"})
+		}
+		createBuild(t, database, build)
+		if err := database.DB.Create(&models.RawRemarks{BuildID: id, Data: []byte("--- !Missed")}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	purged, err := database.PurgeRemarks("purged")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if purged != 3 {
+		t.Errorf("purged %d remarks, want 3", purged)
+	}
+
+	// Only the other builds' remarks are left
+	for _, model := range []interface{}{&models.CompilerRemark{}, &models.KernelInfo{}, &models.MemoryAccess{}, &models.RawRemarks{}} {
+		var rows int64
+		if err := database.DB.Model(model).Count(&rows).Error; err != nil {
+			t.Fatalf("failed to count %T: %v", model, err)
+		}
+		if rows != 2 {
+			t.Errorf("%d rows of %T, want 2", rows, model)
+		}
+	}
+
+	// The build keeps everything else, and records the purge
+	build, err := database.GetBuildByID("purged")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(build.Remarks) != 0 {
+		t.Errorf("build still has %d remarks", len(build.Remarks))
+	}
+	if !build.RemarksPurged || build.RemarksSeen != 3 {
+		t.Errorf("RemarksPurged = %v, RemarksSeen = %d, want true and 3", build.RemarksPurged, build.RemarksSeen)
+	}
+
+	// The counts behind the analysis summary are kept
+	counts := make(map[string]int64)
+	for _, count := range build.RemarkCounts {
+		counts[count.Pass+" "+count.Status] = count.Count
+	}
+	wantCounts := map[string]int64{"loop-vectorize missed": 1, "inline passed": 2}
+	if !reflect.DeepEqual(counts, wantCounts) {
+		t.Errorf("remark counts %v, want %v", counts, wantCounts)
+	}
+	if build.Metrics == nil || build.Metrics.Warnings != 1 || build.Metrics.TextSize != 4096 {
+		t.Errorf("metrics summary lost: %+v", build.Metrics)
+	}
+	if len(build.Compiler.Options) != 1 || len(build.Performance.Phases) != 1 || len(build.Labels) != 1 {
+		t.Errorf("build details lost: %+v", build)
+	}
+
+	summary, err := database.Summary(time.Now(), 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.TotalBuilds != 3 {
+		t.Errorf("summary counts %d builds, want 3", summary.TotalBuilds)
+	}
+
+	// A build still streaming remarks is left alone
+	if _, err := database.PurgeRemarks("streaming"); err == nil {
+		t.Error("purged a build in progress")
+	}
+	var remaining int64
+	if err := database.DB.Model(&models.CompilerRemark{}).Where("build_id = ?", "streaming").Count(&remaining).Error; err != nil {
+		t.Fatal(err)
+	}
+	if remaining != 1 {
+		t.Errorf("in-progress build has %d remarks, want 1", remaining)
+	}
+
+	if _, err := database.PurgeRemarks("missing"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("purging a missing build: %v, want not found", err)
+	}
+}
+
+func TestGetBuildByIDOptions(t *testing.T) {
+	database := dbtest.Open(t)
+	createBuild(t, database, models.Build{
+		ID: "build",
+		Compiler: models.Compiler{
+			BuildID: "build",
+			Options: []models.CompilerOption{
+				{Option: "-O0", Position: 2},
+				{Option: "-O3", Position: 0},
+				{Option: "-march=native", Position: 1},
+			},
+		},
+	})
+
+	build, err := database.GetBuildByID("build")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var options []string
+	for _, option := range build.Compiler.Options {
+		options = append(options, option.Option)
+	}
+	if want := []string{"-O3", "-march=native", "-O0"}; !reflect.DeepEqual(options, want) {
+		t.Errorf("options %v, want %v", options, want)
+	}
+}
+
+func TestGetBuildByIDOrder(t *testing.T) {
+	database := dbtest.Open(t)
+
+	// Rows are inserted out of order, so only an ORDER BY returns them sorted
+	createBuild(t, database, models.Build{
+		ID: "build",
+		Command: models.Command{
+			BuildID:    "build",
+			Executable: "cc",
+			Arguments: []models.CommandArgument{
+				{Position: 2, Argument: "foo.c"},
+				{Position: 0, Argument: "-O2"},
+				{Position: 1, Argument: "-c"},
+			},
+		},
+		Remarks: []models.CompilerRemark{
+			{ID: 30, Name: "third"},
+			{ID: 10, Name: "first"},
+			{ID: 20, Name: "second"},
+		},
+	})
+
+	for range 3 {
+		build, err := database.GetBuildByID("build")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var args []string
+		for _, arg := range build.Command.Arguments {
+			args = append(args, arg.Argument)
+		}
+		if want := []string{"-O2", "-c", "foo.c"}; !reflect.DeepEqual(args, want) {
+			t.Errorf("arguments %v, want %v", args, want)
+		}
+
+		var names []string
+		for _, remark := range build.Remarks {
+			names = append(names, remark.Name)
+		}
+		if want := []string{"first", "second", "third"}; !reflect.DeepEqual(names, want) {
+			t.Errorf("remarks %v, want %v", names, want)
+		}
+	}
+}
+
+func TestListBuildsByLabel(t *testing.T) {
+	database := dbtest.Open(t)
+
+	for i := range 6 {
+		project := "app"
+		if i%2 == 1 {
+			project = "lib"
+		}
+		createBuild(t, database, models.Build{
+			ID: fmt.Sprintf("build-%d", i),
+			Labels: []models.BuildLabel{
+				{Key: "project", Value: project},
+				{Key: "ci", Value: "true"},
+			},
+			Remarks: []models.CompilerRemark{{Name: "NotVectorized", Function: "foo"}},
+		})
+	}
+
+	// Count the statements a listing runs, which must not grow with the page
+	var queries int
+	countQueries := func(*gorm.DB) { queries++ }
+	if err := database.DB.Callback().Query().After("gorm:query").Register("test:count", countQueries); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		labels map[string]string
+		want   []string
+	}{
+		{"shared label", map[string]string{"ci": "true"}, []string{"build-5", "build-4", "build-3", "build-2", "build-1", "build-0"}},
+		{"one label", map[string]string{"project": "app"}, []string{"build-4", "build-2", "build-0"}},
+		{"all labels must match", map[string]string{"project": "lib", "ci": "true"}, []string{"build-5", "build-3", "build-1"}},
+		{"no match", map[string]string{"project": "app", "ci": "false"}, nil},
+	}
+	perPage := -1
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queries = 0
+			builds, _, err := database.ListBuilds(10, nil, db.ListFilter{Labels: tt.labels})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var ids []string
+			for _, build := range builds {
+				ids = append(ids, build.ID)
+				if len(build.Labels) != 2 {
+					t.Errorf("%s has %d labels, want 2", build.ID, len(build.Labels))
+				}
+				if len(build.Remarks) != 0 {
+					t.Errorf("%s listed with its remarks", build.ID)
+				}
+			}
+			if !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("listed %v, want %v", ids, tt.want)
+			}
+
+			if len(builds) > 0 {
+				if perPage >= 0 && queries != perPage {
+					t.Errorf("ran %d queries, another page ran %d", queries, perPage)
+				}
+				perPage = queries
+			}
+		})
+	}
+}
+
+func TestPruneDeleted(t *testing.T) {
+	database := dbtest.Open(t)
+	for _, id := range []string{"pruned", "kept"} {
+		createBuild(t, database, fullBuild(id))
+		if err := database.DB.Create(&models.RawRemarks{BuildID: id, Data: []byte("--- !Missed")}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := database.DeleteBuild("pruned"); err != nil {
+		t.Fatal(err)
+	}
+	pruned, err := database.PruneDeleted(time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pruned != 1 {
+		t.Errorf("pruned %d builds, want 1", pruned)
+	}
+
+	// Every table keeps the other build's row and nothing of the pruned one
+	tables := []interface{}{
+		&models.Build{},
+		&models.BuildLabel{},
+		&models.Environment{},
+		&models.EnvironmentVariable{},
+		&models.VersionControl{},
+		&models.Hardware{},
+		&models.GPU{},
+		&models.Compiler{},
+		&models.CompilerOption{},
+		&models.CompilerOptimization{},
+		&models.CompilerExtension{},
+		&models.Command{},
+		&models.CommandArgument{},
+		&models.CommandInvocation{},
+		&models.Output{},
+		&models.Artifact{},
+		&models.Diagnostic{},
+		&models.ResourceUsage{},
+		&models.Performance{},
+		&models.PerformancePhase{},
+		&models.BuildMetrics{},
+		&models.LinkMap{},
+		&models.LinkSection{},
+		&models.LinkSymbol{},
+		&models.CompilerRemark{},
+		&models.KernelInfo{},
+		&models.MemoryAccess{},
+		&models.RawRemarks{},
+	}
+	for _, model := range tables {
+		var rows int64
+		if err := database.DB.Unscoped().Model(model).Count(&rows).Error; err != nil {
+			t.Fatalf("failed to count %T: %v", model, err)
+		}
+		if rows != 1 {
+			t.Errorf("%d rows of %T, want 1", rows, model)
+		}
+	}
+
+	kept, err := database.GetBuildByID("kept")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(kept.Remarks) != 1 || kept.Remarks[0].KernelInfo == nil || len(kept.Remarks[0].KernelInfo.MemoryAccesses) != 1 {
+		t.Errorf("kept build lost its remarks: %+v", kept.Remarks)
+	}
+}
+
+func TestGetBuildByIDRepeatedOptions(t *testing.T) {
+	database := dbtest.Open(t)
+	want := []string{"-I", "include", "-I", "third_party", "-Wall", "-Wall"}
+	build := models.Build{ID: "build", Compiler: models.Compiler{BuildID: "build"}}
+	for i, option := range want {
+		build.Compiler.Options = append(build.Compiler.Options, models.CompilerOption{Option: option, Position: i})
+	}
+	createBuild(t, database, build)
+
+	stored, err := database.GetBuildByID("build")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var options []string
+	for _, option := range stored.Compiler.Options {
+		options = append(options, option.Option)
+	}
+	if !reflect.DeepEqual(options, want) {
+		t.Errorf("options %v, want %v", options, want)
+	}
+}
@@ -0,0 +1,92 @@
+// internal/server/db/history.go
+
+package db
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	models "builds/internal/server/db/models"
+)
+
+// RemarkKey identifies a remark across builds by the names the compiler
+// gives it, such as NotVectorized and loop-vectorize. Empty Function or Pass
+// match any value.
+type RemarkKey struct {
+	Name     string
+	Function string
+	Pass     string
+}
+
+// BuildRef names a build and when the server stored it
+type BuildRef struct {
+	ID        string
+	CreatedAt time.Time
+}
+
+// RemarkHistory tells when a remark first and last appeared among a set of
+// builds, for tracking down when a regression was introduced or fixed
+type RemarkHistory struct {
+	Builds int64 // Builds the remark appeared in
+	First  *BuildRef
+	Last   *BuildRef
+	Latest *BuildRef // Newest build in the set, with or without the remark
+}
+
+// InLatest reports whether the remark appeared in the newest build
+func (h *RemarkHistory) InLatest() bool {
+	return h.Last != nil && h.Latest != nil && h.Last.ID == h.Latest.ID
+}
+
+// RemarkHistory finds the builds carrying the label labelKey=labelValue in
+// which the remark appeared. An empty labelKey considers every build.
+func (d *Database) RemarkHistory(labelKey, labelValue string, key RemarkKey) (*RemarkHistory, error) {
+	builds := d.scope(d.DB).Model(&models.Build{})
+	if labelKey != "" {
+		builds = builds.Where("EXISTS (SELECT 1 FROM build_labels l WHERE l.build_id = builds.id AND l.key = ? AND l.value = ?)",
+			labelKey, labelValue)
+	}
+
+	remarks := d.DB.Model(&models.CompilerRemark{}).Select("build_id").Where("name = ?", key.Name)
+	if key.Function != "" {
+		remarks = remarks.Where("function = ?", key.Function)
+	}
+	if key.Pass != "" {
+		remarks = remarks.Where("pass_name = ?", key.Pass)
+	}
+	matching := builds.Session(&gorm.Session{}).Where("builds.id IN (?)", remarks)
+
+	var history RemarkHistory
+	if err := matching.Session(&gorm.Session{}).Count(&history.Builds).Error; err != nil {
+		return nil, fmt.Errorf("failed to count builds with remark: %w", err)
+	}
+
+	var err error
+	if history.First, err = firstBuild(matching, "created_at ASC, id ASC"); err != nil {
+		return nil, err
+	}
+	if history.Last, err = firstBuild(matching, "created_at DESC, id DESC"); err != nil {
+		return nil, err
+	}
+	if history.Latest, err = firstBuild(builds, "created_at DESC, id DESC"); err != nil {
+		return nil, err
+	}
+
+	return &history, nil
+}
+
+// firstBuild returns the first build of query in the given order, or nil
+func firstBuild(query *gorm.DB, order string) (*BuildRef, error) {
+	var build models.Build
+	err := query.Session(&gorm.Session{}).Select("id", "created_at").Order(order).First(&build).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to find build: %w", err)
+	}
+	return &BuildRef{ID: build.ID, CreatedAt: build.CreatedAt}, nil
+}
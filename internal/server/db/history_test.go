@@ -0,0 +1,117 @@
+package db_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"builds/internal/server/db"
+	"builds/internal/server/db/dbtest"
+	models "builds/internal/server/db/models"
+)
+
+func notVectorized(function string) models.CompilerRemark {
+	return models.CompilerRemark{
+		Name:     "NotVectorized",
+		PassName: "loop-vectorize",
+		Pass:     "pass_analysis",
+		Status:   "missed",
+		Function: function,
+	}
+}
+
+func TestRemarkHistory(t *testing.T) {
+	database := dbtest.Open(t)
+
+	// The remark appears in the second and third builds of the project and
+	// is fixed in the fourth; another project keeps it throughout
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	present := []bool{false, true, true, false}
+	for i, has := range present {
+		build := models.Build{
+			ID:        fmt.Sprintf("build-%d", i),
+			CreatedAt: start.Add(time.Duration(i) * time.Hour),
+			Labels:    []models.BuildLabel{{Key: "project", Value: "app"}},
+		}
+		if has {
+			build.Remarks = []models.CompilerRemark{notVectorized("foo")}
+		}
+		createBuild(t, database, build)
+	}
+	createBuild(t, database, models.Build{
+		ID:        "other",
+		CreatedAt: start.Add(10 * time.Hour),
+		Labels:    []models.BuildLabel{{Key: "project", Value: "lib"}},
+		Remarks:   []models.CompilerRemark{notVectorized("foo")},
+	})
+
+	key := db.RemarkKey{Name: "NotVectorized", Function: "foo", Pass: "loop-vectorize"}
+	history, err := database.RemarkHistory("project", "app", key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if history.Builds != 2 {
+		t.Errorf("Builds = %d, want 2", history.Builds)
+	}
+	if history.First == nil || history.First.ID != "build-1" {
+		t.Errorf("First = %+v, want build-1", history.First)
+	}
+	if history.Last == nil || history.Last.ID != "build-2" {
+		t.Errorf("Last = %+v, want build-2", history.Last)
+	}
+	if history.Latest == nil || history.Latest.ID != "build-3" {
+		t.Errorf("Latest = %+v, want build-3", history.Latest)
+	}
+	if history.InLatest() {
+		t.Error("InLatest() = true after the remark disappeared")
+	}
+
+	// Reappearing in a later build makes it current again
+	createBuild(t, database, models.Build{
+		ID:        "build-4",
+		CreatedAt: start.Add(4 * time.Hour),
+		Labels:    []models.BuildLabel{{Key: "project", Value: "app"}},
+		Remarks:   []models.CompilerRemark{notVectorized("foo")},
+	})
+	history, err = database.RemarkHistory("project", "app", key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if history.Builds != 3 || history.Last == nil || history.Last.ID != "build-4" || !history.InLatest() {
+		t.Errorf("after reappearing: %d builds, last %+v, in latest %v", history.Builds, history.Last, history.InLatest())
+	}
+}
+
+func TestRemarkHistoryKey(t *testing.T) {
+	database := dbtest.Open(t)
+	createBuild(t, database, models.Build{
+		ID:      "build",
+		Remarks: []models.CompilerRemark{notVectorized("foo")},
+	})
+
+	tests := []struct {
+		name  string
+		key   db.RemarkKey
+		found bool
+	}{
+		{"name only", db.RemarkKey{Name: "NotVectorized"}, true},
+		{"pass name", db.RemarkKey{Name: "NotVectorized", Pass: "loop-vectorize"}, true},
+		{"pass category", db.RemarkKey{Name: "NotVectorized", Pass: "pass_analysis"}, false},
+		{"other function", db.RemarkKey{Name: "NotVectorized", Function: "bar"}, false},
+		{"other name", db.RemarkKey{Name: "Vectorized"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			history, err := database.RemarkHistory("", "", tt.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if found := history.Builds > 0; found != tt.found {
+				t.Errorf("found = %v, want %v", found, tt.found)
+			}
+			if history.Latest == nil || history.Latest.ID != "build" {
+				t.Errorf("Latest = %+v, want build", history.Latest)
+			}
+		})
+	}
+}
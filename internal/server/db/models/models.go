@@ -3,29 +3,60 @@
 package db
 
 import (
+	"bytes"
+	"compress/gzip"
 	"database/sql/driver"
 	"encoding/json"
 	"fmt"
+	"io"
+	"sync"
 	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"gorm.io/gorm"
 )
 
 type Build struct {
-	ID            string `gorm:"primarykey"`
-	StartTime     time.Time
-	EndTime       time.Time
-	Duration      float64
-	Success       bool
-	Error         string
-	Environment   Environment      `gorm:"foreignKey:BuildID"`
-	Hardware      Hardware         `gorm:"foreignKey:BuildID"`
-	Compiler      Compiler         `gorm:"foreignKey:BuildID"`
-	Command       Command          `gorm:"foreignKey:BuildID"`
-	Output        Output           `gorm:"foreignKey:BuildID"`
-	ResourceUsage ResourceUsage    `gorm:"foreignKey:BuildID"`
-	Performance   Performance      `gorm:"foreignKey:BuildID"`
-	Remarks       []CompilerRemark `gorm:"foreignKey:BuildID"`
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
+	ID         string `gorm:"primarykey"`
+	Tenant     string `gorm:"index"` // Owning tenant; empty when tenancy is disabled
+	StartTime  time.Time
+	EndTime    time.Time
+	Duration   float64
+	Success    bool
+	Error      string
+	InProgress bool
+	// Set when the collector dropped remarks beyond its cap
+	RemarksTruncated bool
+	RemarksSeen      int64
+	RemarksPurged    bool       // Remarks were deleted to save space
+	RawTruncated     bool       // Raw record dropped for exceeding the upload cap
+	ReportedDuration float64    // Client duration replaced by the server, if any
+	CacheStatus      string     // Compiler cache hit, miss, disabled or unknown
+	ClientStartTime  *time.Time // Client timestamps replaced by the server, if any
+	ClientEndTime    *time.Time
+	FinishedAt       *time.Time       `gorm:"index"` // Server time the finished build was stored; nil in progress
+	Environment      Environment      `gorm:"foreignKey:BuildID"`
+	VersionControl   *VersionControl  `gorm:"foreignKey:BuildID"` // Nil outside a git repository
+	Hardware         Hardware         `gorm:"foreignKey:BuildID"`
+	Compiler         Compiler         `gorm:"foreignKey:BuildID"`
+	Command          Command          `gorm:"foreignKey:BuildID"`
+	Output           Output           `gorm:"foreignKey:BuildID"`
+	ResourceUsage    ResourceUsage    `gorm:"foreignKey:BuildID"`
+	Performance      Performance      `gorm:"foreignKey:BuildID"`
+	LinkMap          *LinkMap         `gorm:"foreignKey:BuildID"` // Nil unless a linker map was collected
+	Metrics          *BuildMetrics    `gorm:"foreignKey:BuildID"` // Nil unless the outputs were measured
+	Remarks          []CompilerRemark `gorm:"foreignKey:BuildID"`
+	RemarkCounts     []RemarkCount    `gorm:"foreignKey:BuildID"` // Set once the remarks are purged
+	Labels           []BuildLabel     `gorm:"foreignKey:BuildID"`
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	DeletedAt        gorm.DeletedAt `gorm:"index"` // Set while a deleted build awaits pruning
+}
+
+type BuildLabel struct {
+	BuildID string `gorm:"primarykey"`
+	Key     string `gorm:"primarykey"`
+	Value   string `gorm:"index"`
 }
 
 type Environment struct {
@@ -33,6 +64,9 @@ type Environment struct {
 	OS         string
 	Arch       string
 	WorkingDir string
+	Locale     JSON `gorm:"type:jsonb"`
+	Timezone   string
+	Umask      string
 	Variables  []EnvironmentVariable `gorm:"foreignKey:BuildID"`
 }
 
@@ -42,6 +76,14 @@ type EnvironmentVariable struct {
 	Value   string
 }
 
+type VersionControl struct {
+	BuildID   string `gorm:"primarykey"`
+	Commit    string `gorm:"index"`
+	Branch    string
+	Dirty     bool
+	RemoteURL string
+}
+
 type Hardware struct {
 	BuildID    string `gorm:"primarykey"`
 	CPUModel   string
@@ -50,6 +92,7 @@ type Hardware struct {
 	CPUThreads int32
 	CPUVendor  string
 	CacheSize  int64
+	NUMANodes  int32
 	MemTotal   int64
 	MemAvail   int64
 	MemUsed    int64
@@ -67,11 +110,57 @@ type GPU struct {
 	ComputeCaps string
 }
 
+type BuildMetrics struct {
+	BuildID        string `gorm:"primarykey"`
+	TotalFiles     int32
+	ProcessedFiles int32
+	Warnings       int32
+	Errors         int32
+	InputSize      int64
+	OutputSize     int64
+	TextSize       int64
+	DataSize       int64
+	BSSSize        int64
+	SectionSizes   JSON `gorm:"type:jsonb"` // Section name to bytes
+	Metrics        JSON `gorm:"type:jsonb"`
+}
+
+type LinkMap struct {
+	BuildID           string `gorm:"primarykey"`
+	SymbolsSeen       int64
+	DiscardedSections int32
+	DiscardedSize     int64
+	Sections          []LinkSection `gorm:"foreignKey:BuildID"`
+	Symbols           []LinkSymbol  `gorm:"foreignKey:BuildID"`
+}
+
+type LinkSection struct {
+	ID      uint `gorm:"primarykey"`
+	BuildID string
+	Name    string
+	Size    int64
+}
+
+type LinkSymbol struct {
+	ID      uint `gorm:"primarykey"`
+	BuildID string
+	Name    string
+	Section string
+	Object  string
+	Size    int64
+}
+
 type Compiler struct {
 	BuildID         string `gorm:"primarykey"`
 	Name            string
 	Version         string
+	VersionString   string `gorm:"type:text"`
+	Wrapper         string
 	Target          string
+	TargetArch      string `gorm:"index"`
+	TargetVendor    string
+	TargetOS        string
+	TargetABI       string
 	LanguageName    string
 	LanguageVersion string
 	LanguageSpec    string
@@ -85,8 +174,9 @@ type Compiler struct {
 }
 
 type CompilerOption struct {
-	BuildID string `gorm:"primarykey"`
-	Option  string `gorm:"primarykey"`
+	BuildID  string `gorm:"primarykey"`
+	Position int    `gorm:"primarykey"` // Later options override earlier ones
+	Option   string
 }
 
 type CompilerOptimization struct {
@@ -104,7 +194,17 @@ type Command struct {
 	BuildID    string `gorm:"primarykey"`
 	Executable string
 	WorkingDir string
+	Hash       string            `gorm:"index"` // Normalised invocation, see invocations.Hash
 	Arguments  []CommandArgument `gorm:"foreignKey:BuildID"`
+	// Sub-invocations of the compiler driver, when captured
+	Invocations []CommandInvocation `gorm:"foreignKey:BuildID"`
+}
+
+type CommandInvocation struct {
+	BuildID   string `gorm:"primarykey"`
+	Position  int    `gorm:"primarykey"`
+	Tool      string
+	Arguments StringArray `gorm:"type:text[]"`
 }
 
 type CommandArgument struct {
@@ -114,11 +214,23 @@ type CommandArgument struct {
 }
 
 type Output struct {
-	BuildID   string `gorm:"primarykey"`
-	Stdout    string
-	Stderr    string
-	ExitCode  int32
-	Artifacts []Artifact `gorm:"foreignKey:BuildID"`
+	BuildID     string         `gorm:"primarykey"`
+	Stdout      CompressedText `gorm:"type:bytea"`
+	Stderr      CompressedText `gorm:"type:bytea"`
+	ExitCode    int32
+	Truncated   bool
+	Artifacts   []Artifact   `gorm:"foreignKey:BuildID"`
+	Diagnostics []Diagnostic `gorm:"foreignKey:BuildID"`
+}
+
+type Diagnostic struct {
+	ID       uint   `gorm:"primarykey"`
+	BuildID  string `gorm:"index"`
+	Severity string
+	File     string `gorm:"type:text"`
+	Line     int32
+	Col      int32
+	Message  string `gorm:"type:text"`
 }
 
 type Artifact struct {
@@ -132,10 +244,12 @@ type Artifact struct {
 
 type CompilerRemark struct {
 	ID         uint   `gorm:"primarykey"`
+	StableID   string `gorm:"type:text;index"` // Deterministic ID shared with clients
 	BuildID    string `gorm:"index"`
 	Type       string // The YAML tag type (Passed, Missed, Analysis, etc)
 	Pass       string `gorm:"type:text"`
-	Name       string `gorm:"type:text"`
+	PassName   string `gorm:"type:text"` // As the compiler names it, e.g. loop-vectorize
+	Name       string `gorm:"type:text;index"`
 	Message    string `gorm:"type:text"`
 	Function   string `gorm:"type:text"`
 	Timestamp  time.Time
@@ -148,6 +262,15 @@ type CompilerRemark struct {
 	Metadata   JSON        `gorm:"type:jsonb"`
 }
 
+// RemarkCount is the number of remarks a pass emitted with one status, kept
+// when the remarks themselves are purged
+type RemarkCount struct {
+	BuildID string `gorm:"primarykey"`
+	Pass    string `gorm:"primarykey"`
+	Status  string `gorm:"primarykey"`
+	Count   int64
+}
+
 // RemarkArgs represents the structured arguments from YAML
 type RemarkArgs struct {
 	Strings     []string          `json:"strings,omitempty"`
@@ -176,6 +299,7 @@ type Location struct {
 	Column   int32  `json:"column,omitempty"`
 	Function string `json:"function,omitempty"`
 	Region   string `json:"region,omitempty"`
+	Artifact string `json:"artifact,omitempty"` // Record file the remark was read from
 }
 
 // KernelInfo updates to better support YAML structure
@@ -223,6 +347,13 @@ type BasicBlock struct {
 	Location     Location `gorm:"embedded;embeddedPrefix:bb_loc_"`
 }
 
+// RawRemarks holds the gzip-compressed optimization record of a build
+type RawRemarks struct {
+	BuildID   string `gorm:"primarykey"`
+	Data      []byte `gorm:"type:bytea"`
+	CreatedAt time.Time
+}
+
 type ResourceUsage struct {
 	BuildID    string `gorm:"primarykey"`
 	MaxMemory  int64
@@ -232,6 +363,8 @@ type ResourceUsage struct {
 	WriteBytes int64
 	ReadCount  int64
 	WriteCount int64
+	// Compiler phase name to GC bytes allocated, when reported
+	PhaseMemory JSON `gorm:"type:jsonb"`
 }
 
 type Performance struct {
@@ -249,18 +382,54 @@ type PerformancePhase struct {
 }
 
 // Custom types for handling arrays and JSON
+
+// StringArray is stored as a Postgres text[], written and read in its text
+// format, e.g. {a,"b c"}
 type StringArray []string
 
+// textArrays encodes and decodes StringArray values. A type map is not
+// safe for concurrent use.
+var (
+	textArrays   = pgtype.NewMap()
+	textArraysMu sync.Mutex
+)
+
 func (a StringArray) Value() (driver.Value, error) {
-	return json.Marshal(a)
+	if a == nil {
+		return nil, nil
+	}
+	textArraysMu.Lock()
+	buf, err := textArrays.Encode(pgtype.TextArrayOID, pgtype.TextFormatCode, []string(a), nil)
+	textArraysMu.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode text array: %w", err)
+	}
+	return string(buf), nil
 }
 
 func (a *StringArray) Scan(value interface{}) error {
-	if value == nil {
+	var src []byte
+	switch v := value.(type) {
+	case nil:
 		*a = nil
 		return nil
+	case []byte:
+		src = v
+	case string:
+		src = []byte(v)
+	default:
+		return fmt.Errorf("unsupported type: %T", value)
+	}
+
+	var values []string
+	textArraysMu.Lock()
+	err := textArrays.Scan(pgtype.TextArrayOID, pgtype.TextFormatCode, src, &values)
+	textArraysMu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to decode text array: %w", err)
 	}
-	return json.Unmarshal(value.([]byte), a)
+	*a = values
+	return nil
 }
 
 type JSON map[string]interface{}
@@ -288,6 +457,68 @@ func (j *JSON) Scan(value interface{}) error {
 	}
 }
 
+// CompressThreshold is the size from which CompressedText values are
+// stored gzip-compressed. Zero or less stores every value as is.
+var CompressThreshold = 16 * 1024
+
+// gzipMagic starts every gzip stream. Valid UTF-8 text never starts with
+// it, so compressed and plain values can share a column.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// CompressedText is text stored gzip-compressed once it reaches
+// CompressThreshold, such as the output of a failing compiler. Values are
+// decompressed when read, and plain values written before compression was
+// enabled read as they are.
+type CompressedText string
+
+func (t CompressedText) Value() (driver.Value, error) {
+	if CompressThreshold <= 0 || len(t) < CompressThreshold {
+		return []byte(t), nil
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := io.WriteString(zw, string(t)); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (t *CompressedText) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*t = ""
+		return nil
+	case string:
+		*t = CompressedText(v)
+		return nil
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("unsupported type: %T", value)
+	}
+
+	if !bytes.HasPrefix(data, gzipMagic) {
+		*t = CompressedText(data)
+		return nil
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to decompress text: %w", err)
+	}
+	defer zr.Close()
+	text, err := io.ReadAll(zr)
+	if err != nil {
+		return fmt.Errorf("failed to decompress text: %w", err)
+	}
+	*t = CompressedText(text)
+	return nil
+}
+
 // JSON marshaling for RemarkArgs
 func (r RemarkArgs) Value() (driver.Value, error) {
 	return json.Marshal(r)
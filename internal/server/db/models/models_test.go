@@ -0,0 +1,128 @@
+package db
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStringArray(t *testing.T) {
+	tests := []struct {
+		name  string
+		array StringArray
+		text  string
+	}{
+		{"nil", nil, ""},
+		{"empty", StringArray{}, "{}"},
+		{"plain", StringArray{"foo", "bar"}, "{foo,bar}"},
+		{"quoted", StringArray{"a b", " pad ", `say "hi"`, "x,y", "NULL", ""}, `{a b," pad ","say \"hi\"","x,y","NULL",""}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := tt.array.Value()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.array == nil {
+				if value != nil {
+					t.Errorf("Value() = %v, want NULL", value)
+				}
+				return
+			}
+			if value != tt.text {
+				t.Errorf("Value() = %v, want %s", value, tt.text)
+			}
+
+			// Drivers hand back either form
+			for _, src := range []interface{}{tt.text, []byte(tt.text)} {
+				var got StringArray
+				if err := got.Scan(src); err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(got, tt.array) {
+					t.Errorf("Scan(%T) = %q, want %q", src, got, tt.array)
+				}
+			}
+		})
+	}
+
+	var got StringArray
+	if err := got.Scan(nil); err != nil || got != nil {
+		t.Errorf("Scan(nil) = %q, %v", got, err)
+	}
+	if err := got.Scan(42); err == nil {
+		t.Error("Scan(42) succeeded")
+	}
+}
+
+func TestCompressedText(t *testing.T) {
+	large := strings.Repeat("foo.h:12:3: error: no matching function for call to 'bar'\n", 1000)
+	tests := []struct {
+		name       string
+		text       CompressedText
+		compressed bool
+	}{
+		{"empty", "", false},
+		{"small", "foo.c:3:5: warning: unused variable 'x'\n", false},
+		{"just under the threshold", CompressedText(strings.Repeat("x", CompressThreshold-1)), false},
+		{"at the threshold", CompressedText(strings.Repeat("x", CompressThreshold)), true},
+		{"large", CompressedText(large), true},
+		{"large with non-ASCII", CompressedText(strings.Repeat("é\\ÿ\n", CompressThreshold)), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := tt.text.Value()
+			if err != nil {
+				t.Fatal(err)
+			}
+			data := value.([]byte)
+			if compressed := bytes.HasPrefix(data, gzipMagic); compressed != tt.compressed {
+				t.Errorf("compressed = %v, want %v", compressed, tt.compressed)
+			}
+			if tt.compressed && len(data) >= len(tt.text) {
+				t.Errorf("stored %d bytes for %d of text", len(data), len(tt.text))
+			}
+
+			var got CompressedText
+			if err := got.Scan(data); err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.text {
+				t.Errorf("round trip changed the text: %d bytes, want %d", len(got), len(tt.text))
+			}
+		})
+	}
+}
+
+func TestCompressedTextScan(t *testing.T) {
+	// Values stored before compression, as text or bytea
+	for _, src := range []interface{}{"plain", []byte("plain")} {
+		var got CompressedText
+		if err := got.Scan(src); err != nil || got != "plain" {
+			t.Errorf("Scan(%T) = %q, %v", src, got, err)
+		}
+	}
+
+	got := CompressedText("stale")
+	if err := got.Scan(nil); err != nil || got != "" {
+		t.Errorf("Scan(nil) = %q, %v", got, err)
+	}
+	if err := got.Scan([]byte{0x1f, 0x8b, 0}); err == nil {
+		t.Error("Scan accepted a truncated gzip stream")
+	}
+}
+
+func TestCompressedTextDisabled(t *testing.T) {
+	defer func(threshold int) { CompressThreshold = threshold }(CompressThreshold)
+	CompressThreshold = 0
+
+	text := CompressedText(strings.Repeat("x", 1<<20))
+	value, err := text.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data := value.([]byte); bytes.HasPrefix(data, gzipMagic) {
+		t.Error("compressed with compression off")
+	}
+}
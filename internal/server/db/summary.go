@@ -0,0 +1,118 @@
+// internal/server/db/summary.go
+
+package db
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	models "builds/internal/server/db/models"
+)
+
+// FleetSummary aggregates figures across all stored builds
+type FleetSummary struct {
+	TotalBuilds       int64
+	BuildsLastDay     int64
+	BuildsLastWeek    int64
+	RecentSuccessRate float64
+	TopCompilers      []CompilerCount
+	SlowestRecent     *models.Build
+}
+
+// CompilerCount is the number of builds using a compiler
+type CompilerCount struct {
+	Name   string
+	Builds int64
+}
+
+// Summary computes the fleet overview relative to now. Recent figures cover
+// the last seven days by when the server stored each build, as clients'
+// clocks disagree, and outcomes only count finished builds.
+func (d *Database) Summary(now time.Time, topCompilers int) (*FleetSummary, error) {
+	dayAgo := now.Add(-24 * time.Hour)
+	weekAgo := now.Add(-7 * 24 * time.Hour)
+
+	var summary FleetSummary
+	err := d.DB.Raw(`
+		SELECT
+			COUNT(*) AS total_builds,
+			COUNT(*) FILTER (WHERE created_at >= ?) AS builds_last_day,
+			COUNT(*) FILTER (WHERE created_at >= ?) AS builds_last_week,
+			COALESCE(AVG(CASE WHEN success THEN 1.0 ELSE 0.0 END) FILTER (WHERE created_at >= ? AND NOT in_progress), 0) AS recent_success_rate
+		FROM builds
+		WHERE deleted_at IS NULL AND (? = '' OR tenant = ?)`, dayAgo, weekAgo, weekAgo, d.tenant, d.tenant).
+		Row().
+		Scan(&summary.TotalBuilds, &summary.BuildsLastDay, &summary.BuildsLastWeek, &summary.RecentSuccessRate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count builds: %w", err)
+	}
+
+	err = d.DB.Raw(`
+		SELECT c.name AS name, COUNT(*) AS builds
+		FROM compilers c
+		JOIN builds b ON b.id = c.build_id
+		WHERE b.deleted_at IS NULL AND (? = '' OR b.tenant = ?)
+		GROUP BY c.name
+		ORDER BY builds DESC, c.name ASC
+		LIMIT ?`, d.tenant, d.tenant, topCompilers).
+		Scan(&summary.TopCompilers).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to rank compilers: %w", err)
+	}
+
+	var slowest models.Build
+	err = d.scope(d.DB).
+		Preload("Compiler").
+		Where("created_at >= ? AND NOT in_progress", weekAgo).
+		Order("duration DESC").
+		First(&slowest).Error
+	switch {
+	case err == nil:
+		summary.SlowestRecent = &slowest
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, fmt.Errorf("failed to find slowest build: %w", err)
+	}
+
+	return &summary, nil
+}
+
+// BuildFilter selects builds to count or list. Zero fields match
+// everything. Outcomes only match finished builds, and the time range
+// applies to when the server stored a build, as clients' clocks disagree.
+type BuildFilter struct {
+	Compiler string
+	Success  *bool
+	Since    time.Time
+	Until    time.Time
+}
+
+// apply adds the filter's conditions to a query over builds
+func (f BuildFilter) apply(query *gorm.DB) *gorm.DB {
+	if f.Compiler != "" {
+		query = query.Where("EXISTS (SELECT 1 FROM compilers c WHERE c.build_id = builds.id AND c.name = ?)", f.Compiler)
+	}
+	if f.Success != nil {
+		query = query.Where("builds.success = ? AND NOT builds.in_progress", *f.Success)
+	}
+	if !f.Since.IsZero() {
+		query = query.Where("builds.created_at >= ?", f.Since)
+	}
+	if !f.Until.IsZero() {
+		query = query.Where("builds.created_at < ?", f.Until)
+	}
+	return query
+}
+
+// CountBuilds returns the number of builds matching the filter
+func (d *Database) CountBuilds(filter BuildFilter) (int64, error) {
+	query := filter.apply(d.scope(d.DB).Model(&models.Build{}))
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count builds: %w", err)
+	}
+	return count, nil
+}
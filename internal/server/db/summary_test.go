@@ -0,0 +1,192 @@
+package db_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"builds/internal/server/db"
+	"builds/internal/server/db/dbtest"
+	models "builds/internal/server/db/models"
+)
+
+// createBuild stores a build with its relations
+func createBuild(t *testing.T, database *db.Database, build models.Build) {
+	t.Helper()
+	if err := database.DB.Create(&build).Error; err != nil {
+		t.Fatalf("failed to create build %s: %v", build.ID, err)
+	}
+}
+
+// seedFleet stores builds of varying age, compiler and outcome
+func seedFleet(t *testing.T, database *db.Database, now time.Time) {
+	t.Helper()
+	builds := []struct {
+		age      time.Duration
+		compiler string
+		duration float64
+		success  bool
+	}{
+		{time.Hour, "clang", 10, true},
+		{2 * time.Hour, "clang", 30, false},
+		{3 * 24 * time.Hour, "gcc", 50, true},
+		{6 * 24 * time.Hour, "clang", 20, true},
+		{30 * 24 * time.Hour, "gcc", 900, true}, // Too old to be recent
+	}
+	for i, b := range builds {
+		id := fmt.Sprintf("build-%d", i)
+		createBuild(t, database, models.Build{
+			ID:        id,
+			StartTime: now.Add(-b.age),
+			CreatedAt: now.Add(-b.age),
+			Duration:  b.duration,
+			Success:   b.success,
+			Compiler:  models.Compiler{BuildID: id, Name: b.compiler},
+		})
+	}
+}
+
+func TestSummary(t *testing.T) {
+	database := dbtest.Open(t)
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	seedFleet(t, database, now)
+
+	// Deleted builds are left out
+	createBuild(t, database, models.Build{ID: "deleted", StartTime: now, Duration: 5000})
+	if err := database.DeleteBuild("deleted"); err != nil {
+		t.Fatal(err)
+	}
+	// Still compiling, so counted but neither failed nor slowest
+	createBuild(t, database, models.Build{
+		ID:         "compiling",
+		StartTime:  now.Add(-time.Hour),
+		CreatedAt:  now.Add(-time.Hour),
+		Duration:   5000,
+		InProgress: true,
+		Compiler:   models.Compiler{BuildID: "compiling", Name: "clang"},
+	})
+	// Stored an hour ago by a client whose clock is a month behind
+	createBuild(t, database, models.Build{
+		ID:        "skewed",
+		StartTime: now.Add(-30 * 24 * time.Hour),
+		CreatedAt: now.Add(-time.Hour),
+		Duration:  5,
+		Success:   true,
+		Compiler:  models.Compiler{BuildID: "skewed", Name: "clang"},
+	})
+
+	summary, err := database.Summary(now, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.TotalBuilds != 7 || summary.BuildsLastDay != 4 || summary.BuildsLastWeek != 6 {
+		t.Errorf("counted %d builds, %d in a day and %d in a week, want 7, 4 and 6",
+			summary.TotalBuilds, summary.BuildsLastDay, summary.BuildsLastWeek)
+	}
+	if summary.RecentSuccessRate != 0.8 {
+		t.Errorf("RecentSuccessRate = %v, want 0.8", summary.RecentSuccessRate)
+	}
+	if len(summary.TopCompilers) != 1 || summary.TopCompilers[0] != (db.CompilerCount{Name: "clang", Builds: 5}) {
+		t.Errorf("TopCompilers = %+v, want clang with 5 builds", summary.TopCompilers)
+	}
+	if summary.SlowestRecent == nil || summary.SlowestRecent.ID != "build-2" || summary.SlowestRecent.Compiler.Name != "gcc" {
+		t.Errorf("SlowestRecent = %+v, want build-2 compiled by gcc", summary.SlowestRecent)
+	}
+}
+
+func TestSummaryEmpty(t *testing.T) {
+	database := dbtest.Open(t)
+
+	summary, err := database.Summary(time.Now(), 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.TotalBuilds != 0 || summary.RecentSuccessRate != 0 || len(summary.TopCompilers) != 0 || summary.SlowestRecent != nil {
+		t.Errorf("summary of no builds: %+v", summary)
+	}
+}
+
+func TestCountBuilds(t *testing.T) {
+	database := dbtest.Open(t)
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	seedFleet(t, database, now)
+
+	createBuild(t, database, models.Build{ID: "deleted", StartTime: now, Success: false})
+	if err := database.DeleteBuild("deleted"); err != nil {
+		t.Fatal(err)
+	}
+	// Still compiling, and begun by a client whose clock is a day behind
+	createBuild(t, database, models.Build{
+		ID:         "compiling",
+		StartTime:  now.Add(-25 * time.Hour),
+		CreatedAt:  now.Add(-time.Minute),
+		InProgress: true,
+	})
+
+	succeeded, failed := true, false
+	tests := []struct {
+		name   string
+		filter db.BuildFilter
+		want   int64
+	}{
+		{"unfiltered", db.BuildFilter{}, 6},
+		{"compiler", db.BuildFilter{Compiler: "gcc"}, 2},
+		{"failed", db.BuildFilter{Success: &failed}, 1},
+		{"succeeded", db.BuildFilter{Success: &succeeded}, 4},
+		{"since", db.BuildFilter{Since: now.Add(-24 * time.Hour)}, 3},
+		{"until", db.BuildFilter{Until: now.Add(-24 * time.Hour)}, 3},
+		{"failed since", db.BuildFilter{Success: &failed, Since: now.Add(-24 * time.Hour)}, 1},
+		{"succeeded clang in a week", db.BuildFilter{Compiler: "clang", Success: &succeeded, Since: now.Add(-7 * 24 * time.Hour)}, 2},
+		{"no match", db.BuildFilter{Compiler: "msvc"}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := database.CountBuilds(tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListBuildsFiltered(t *testing.T) {
+	database := dbtest.Open(t)
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	seedFleet(t, database, now)
+
+	// Paging one build at a time must keep to the filter on every page
+	succeeded := true
+	filter := db.ListFilter{BuildFilter: db.BuildFilter{
+		Compiler: "clang",
+		Success:  &succeeded,
+		Since:    now.Add(-7 * 24 * time.Hour),
+	}}
+	var ids []string
+	var after *db.Cursor
+	for page := 0; ; page++ {
+		if page > 3 {
+			t.Fatalf("still paging after %v", ids)
+		}
+		builds, more, err := database.ListBuilds(1, after, filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, build := range builds {
+			ids = append(ids, build.ID)
+		}
+		if !more {
+			break
+		}
+		last := builds[len(builds)-1]
+		after = &db.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+
+	// Newest stored first
+	want := []string{"build-0", "build-3"}
+	if fmt.Sprint(ids) != fmt.Sprint(want) {
+		t.Errorf("listed %v, want %v", ids, want)
+	}
+}
@@ -16,8 +16,27 @@ import (
 	"google.golang.org/grpc/credentials/insecure"
 )
 
+// MaxMessageSize is the largest gRPC message clients and the server send or
+// accept, raised from gRPC's 4 MiB default to fit builds with many remarks
+// or long compiler output
+const MaxMessageSize = 64 << 20
+
+// ServerOptions returns the options servers need to accept what clients
+// created by CreateGRPCConnection send
+func ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.MaxRecvMsgSize(MaxMessageSize),
+		grpc.MaxSendMsgSize(MaxMessageSize),
+	}
+}
+
 func CreateGRPCConnection(addr string, useTLS bool) (*grpc.ClientConn, error) {
-	var opts []grpc.DialOption
+	opts := []grpc.DialOption{
+		grpc.WithDefaultCallOptions(
+			grpc.MaxCallSendMsgSize(MaxMessageSize),
+			grpc.MaxCallRecvMsgSize(MaxMessageSize),
+		),
+	}
 
 	// Handle ngrok-specific configuration
 	if strings.Contains(addr, "ngrok-free.app") {
@@ -0,0 +1,130 @@
+package units
+
+import (
+	"flag"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{in: "30s", want: 30 * time.Second},
+		{in: "1h30m", want: 90 * time.Minute},
+		{in: "1.5s", want: 1500 * time.Millisecond},
+		{in: " 250ms ", want: 250 * time.Millisecond},
+		{in: "0", want: 0},
+		{in: "-5s", wantErr: true},
+		{in: "5", wantErr: true},
+		{in: "1d", wantErr: true},
+		{in: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseDuration(%q) = %v, want an error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseDuration(%q) = %v, %v, want %v", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseBytes(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{in: "4096B", want: 4096},
+		{in: "512MB", want: 512e6},
+		{in: "1.5GB", want: 1.5e9},
+		{in: "1.5GiB", want: 3 << 29},
+		{in: "0.5KiB", want: 512},
+		{in: ".5KB", want: 500},
+		{in: "2TiB", want: 2 << 40},
+		{in: "16 GB", want: 16e9},
+		{in: " 1KB ", want: 1000},
+		{in: "0B", want: 0},
+		// Bare numbers and ambiguous or unknown units
+		{in: "100", wantErr: true},
+		{in: "1mb", wantErr: true},
+		{in: "1K", wantErr: true},
+		{in: "1M", wantErr: true},
+		{in: "1Gb", wantErr: true},
+		{in: "1PB", wantErr: true},
+		// Malformed numbers
+		{in: "GB", wantErr: true},
+		{in: "", wantErr: true},
+		{in: "-1GB", wantErr: true},
+		{in: "1.2.3MB", wantErr: true},
+		{in: "1e3MB", wantErr: true},
+		{in: "1,000KB", wantErr: true},
+		// Out of range
+		{in: "8388608TiB", wantErr: true},
+		{in: "100000000TB", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseBytes(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseBytes(%q) = %d, want an error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseBytes(%q) = %d, %v, want %d", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		in   int64
+		want string
+	}{
+		{0, "0B"},
+		{1023, "1023B"},
+		{1024, "1.0KiB"},
+		{1536, "1.5KiB"},
+		{3 << 29, "1.5GiB"},
+		{1 << 40, "1.0TiB"},
+	}
+	for _, tt := range tests {
+		if got := FormatBytes(tt.in); got != tt.want {
+			t.Errorf("FormatBytes(%d) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFlags(t *testing.T) {
+	var maxAge Duration
+	var limit Bytes
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Var(&maxAge, "max-age", "")
+	fs.Var(&limit, "limit", "")
+
+	if err := fs.Parse([]string{"-max-age", "72h", "-limit", "1.5GiB"}); err != nil {
+		t.Fatal(err)
+	}
+	if time.Duration(maxAge) != 72*time.Hour || maxAge.String() != "72h0m0s" {
+		t.Errorf("max-age = %s", maxAge.String())
+	}
+	if int64(limit) != 3<<29 || limit.String() != "1.5GiB" {
+		t.Errorf("limit = %s", limit.String())
+	}
+
+	for _, args := range [][]string{{"-max-age", "3 days"}, {"-limit", "2G"}} {
+		if err := fs.Parse(args); err == nil {
+			t.Errorf("parsing %v succeeded", args)
+		}
+	}
+}
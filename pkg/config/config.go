@@ -4,6 +4,8 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 )
@@ -68,6 +70,35 @@ func LoadConfig(path string) (*Config, error) {
 	return &config, nil
 }
 
+// Validate reports settings that cannot work
+func (c *Config) Validate() error {
+	var errs []error
+	if c.DefaultCompiler == "" {
+		errs = append(errs, errors.New("defaultCompiler is not set"))
+	}
+	for name, path := range c.CompilerPaths {
+		if path == "" {
+			errs = append(errs, fmt.Errorf("compilerPaths.%s is empty", name))
+		}
+	}
+	if c.MaxBuilds < 0 {
+		errs = append(errs, fmt.Errorf("maxBuilds must not be negative, got %d", c.MaxBuilds))
+	}
+	if c.OutputFormat == "" {
+		errs = append(errs, errors.New("outputFormat is not set"))
+	}
+	return errors.Join(errs...)
+}
+
+// CompilerPath returns the command for the named compiler, falling back to
+// the name itself when no path is configured
+func (c *Config) CompilerPath(name string) string {
+	if path := c.CompilerPaths[name]; path != "" {
+		return path
+	}
+	return name
+}
+
 // SaveConfig saves configuration to a file
 func (c *Config) SaveConfig(path string) error {
 	data, err := json.MarshalIndent(c, "", "  ")